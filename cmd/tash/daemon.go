@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/Aj4x/tash/internal/bridge"
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/history"
+	"github.com/Aj4x/tash/internal/instance"
+	"github.com/Aj4x/tash/internal/msgbus"
+	"github.com/Aj4x/tash/internal/runlog"
+	"github.com/Aj4x/tash/internal/task"
+	"github.com/Aj4x/tash/internal/ui"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// daemonWidth and daemonHeight size the output of the daemon's model, which has no terminal
+const (
+	daemonWidth  = 120
+	daemonHeight = 40
+)
+
+// runDaemon implements the "tash daemon" subcommand: the project's watchers and scheduled tasks
+// run in the background, printing their output, until the daemon is stopped. Interfaces
+// launched for the project attach to it instead of running tasks themselves.
+func runDaemon(args []string, cfg config.Config, out, errOut io.Writer) int {
+	switch {
+	case len(args) == 1 && args[0] == "stop":
+		return stopDaemon(cfg, out, errOut)
+	case len(args) > 0:
+		fmt.Fprintln(errOut, "usage: tash daemon [stop]")
+		return exitUsage
+	}
+	stateDir, err := instanceStateDir()
+	if err != nil {
+		fmt.Fprintln(errOut, "tash error: "+err.Error())
+		return 1
+	}
+	server, err := instance.ListenDaemon(stateDir, projectDir(cfg))
+	if err != nil {
+		fmt.Fprintln(errOut, "tash error: "+err.Error())
+		return 1
+	}
+
+	cfg.WatchEnabled = true
+	bus := msgbus.NewMessageBus[task.Message]()
+	model := ui.NewModel(bus, cfg)
+	model.Headless = true
+	if dir, err := config.DataDir(); err == nil {
+		// runs of attached interfaces run here, so the daemon keeps their history and logs
+		model.UseHistory(history.NewStore(dir))
+		model.UseRunLogs(runlog.NewStore(dir))
+	}
+	model.HandleWindowResize(daemonWidth, daemonHeight)
+	p := tea.NewProgram(model, tea.WithInput(nil), tea.WithoutRenderer())
+
+	server.OnCancel = func() error {
+		return ui.RemoteCancel(p)
+	}
+	server.OnStop = p.Quit
+	var broker *bridge.Bridge
+	if cfg.BridgeURL != "" {
+		broker = &bridge.Bridge{
+			URL:    os.ExpandEnv(cfg.BridgeURL),
+			Prefix: cfg.BridgePrefix,
+			Run: func(req instance.Request) error {
+				return ui.RemoteRun(p, req)
+			},
+			Cancel: server.OnCancel,
+		}
+		if err := bridge.Validate(broker.URL); err != nil {
+			server.Close()
+			fmt.Fprintln(errOut, "tash error: "+err.Error())
+			return 1
+		}
+	}
+	server.OnEvent = func(e instance.Event) {
+		printEvent(out, e)
+		if broker != nil {
+			broker.Publish(e)
+		}
+	}
+	release := serve(server, bus, p)
+	defer release()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if broker != nil {
+		go broker.Serve(ctx)
+	}
+	go func() {
+		<-ctx.Done()
+		p.Quit()
+	}()
+
+	fmt.Fprintf(out, "tash daemon running for %s (pid %d), stop it with \"tash daemon stop\"\n", server.Info.Dir, server.Info.Pid)
+	if _, err := p.Run(); err != nil {
+		fmt.Fprintln(errOut, "tash error: "+err.Error())
+		return 1
+	}
+	return 0
+}
+
+// stopDaemon asks the daemon running for the project to stop
+func stopDaemon(cfg config.Config, out, errOut io.Writer) int {
+	info := runningInstance(cfg)
+	if info == nil || !info.Daemon {
+		fmt.Fprintln(errOut, "tash error: no tash daemon is running for this project")
+		return 1
+	}
+	if _, err := instance.Send(info.Socket, instance.Request{Command: instance.CommandStop}); err != nil {
+		fmt.Fprintln(errOut, "tash error: "+err.Error())
+		return 1
+	}
+	fmt.Fprintf(out, "Stopped the tash daemon (pid %d)\n", info.Pid)
+	return 0
+}
+
+// attachInterface shows the task runs of the daemon in p, and returns a function detaching from it
+func attachInterface(info *instance.Info, p *tea.Program) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		err := instance.Attach(ctx, info.Socket, func(e instance.Event) {
+			p.Send(ui.RemoteEventMsg{Event: e})
+		})
+		if ctx.Err() == nil {
+			p.Send(ui.RemoteDetachedMsg{Err: err})
+		}
+	}()
+	return cancel
+}
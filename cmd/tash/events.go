@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/Aj4x/tash/pkg/tash"
+)
+
+// Lifecycle events of "tash run --events jsonl"
+const (
+	eventRunStarted  = "run-started"
+	eventOutputLine  = "output-line"
+	eventRunFinished = "run-finished"
+)
+
+// runEvent is a line of "tash run --events jsonl"; the fields set depend on the event
+type runEvent struct {
+	Event string    `json:"event"`
+	Task  string    `json:"task"`
+	Time  time.Time `json:"time"`
+	// Stream ("stdout" or "stderr") and Text are set on output-line events
+	Stream string `json:"stream,omitempty"`
+	Text   string `json:"text,omitempty"`
+	// Success, ExitCode, DurationMs and Error are set on run-finished events
+	Success    *bool  `json:"success,omitempty"`
+	ExitCode   *int   `json:"exit_code,omitempty"`
+	DurationMs *int64 `json:"duration_ms,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// eventWriter writes the lifecycle events of the tasks run as one JSON object per line, so
+// wrappers can follow a run without parsing its output
+type eventWriter struct {
+	mu  sync.Mutex // Keeps the lines of events written at once from interleaving
+	enc *json.Encoder
+}
+
+func newEventWriter(w io.Writer) *eventWriter {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &eventWriter{enc: enc}
+}
+
+func (w *eventWriter) write(e runEvent) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	e.Time = time.Now()
+	// a closed stdout leaves nothing to report the failure to
+	_ = w.enc.Encode(e)
+}
+
+func (w *eventWriter) started(id string) {
+	w.write(runEvent{Event: eventRunStarted, Task: id})
+}
+
+func (w *eventWriter) line(id string, l tash.Line) {
+	stream := "stdout"
+	if l.Stderr {
+		stream = "stderr"
+	}
+	w.write(runEvent{Event: eventOutputLine, Task: id, Stream: stream, Text: l.Text})
+}
+
+// finished reports the end of the run of id, which failed with exit code code unless err is nil
+func (w *eventWriter) finished(id string, elapsed time.Duration, code int, err error) {
+	success, ms := err == nil, elapsed.Milliseconds()
+	e := runEvent{Event: eventRunFinished, Task: id, Success: &success, ExitCode: &code, DurationMs: &ms}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	w.write(e)
+}
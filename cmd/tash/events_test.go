@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/Aj4x/tash/pkg/tash"
+)
+
+func TestEventWriterConcurrentLines(t *testing.T) {
+	var out bytes.Buffer
+	w := newEventWriter(&out)
+	var wg sync.WaitGroup
+	for _, stderr := range []bool{false, true} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				w.line("build", tash.Line{Text: "a line of output", Stderr: stderr})
+			}
+		}()
+	}
+	wg.Wait()
+
+	lines := 0
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var e runEvent
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil || e.Event != eventOutputLine {
+			t.Fatalf("Expected an output-line event, got %q: %v", scanner.Text(), err)
+		}
+		lines++
+	}
+	if lines != 200 {
+		t.Errorf("Expected 200 events, got %d", lines)
+	}
+}
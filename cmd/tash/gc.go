@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/history"
+	"github.com/Aj4x/tash/internal/retention"
+	"github.com/Aj4x/tash/internal/runlog"
+	"github.com/Aj4x/tash/internal/ui"
+)
+
+// runGC implements the "tash gc" subcommand: it removes the run logs and history records the
+// retention settings don't keep, the flags overriding the settings of the config
+func runGC(args []string, cfg config.Config, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("gc", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	maxAge := fs.Duration("max-age", 0, "Remove the runs started longer ago (default the retention.max_age setting, or 720h)")
+	maxSize := fs.Int("max-size-mb", 0, "Keep at most this many megabytes of run logs (default the retention.max_size_mb setting, or 500)")
+	maxRuns := fs.Int("max-runs-per-task", 0, "Keep the latest runs of each task (default the retention.max_runs_per_task setting, or 100)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: tash gc [flags]")
+		fmt.Fprintln(stderr, "A negative limit keeps everything.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return exitUsage
+	}
+	if *maxAge != 0 {
+		cfg.Retention.MaxAge = config.Duration(*maxAge)
+	}
+	if *maxSize != 0 {
+		cfg.Retention.MaxSizeMB = *maxSize
+	}
+	if *maxRuns != 0 {
+		cfg.Retention.MaxRunsPerTask = *maxRuns
+	}
+
+	dir, err := config.DataDir()
+	if err != nil {
+		fmt.Fprintln(stderr, "tash error: "+err.Error())
+		return 1
+	}
+	res, err := retention.Clean(runlog.NewStore(dir), history.NewStore(dir), ui.RetentionPolicy(cfg), time.Now())
+	fmt.Fprintf(stdout, "Removed %d runs (%.1f MB) and %d history records\n", res.Runs, float64(res.Bytes)/(1<<20), res.Records)
+	if err != nil {
+		fmt.Fprintln(stderr, "tash error: "+err.Error())
+		return 1
+	}
+	return 0
+}
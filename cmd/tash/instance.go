@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/instance"
+	"github.com/Aj4x/tash/internal/msgbus"
+	"github.com/Aj4x/tash/internal/task"
+	"github.com/Aj4x/tash/internal/ui"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// instanceStateDir returns the directory of the lock files and sockets of running instances
+func instanceStateDir() (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return instance.StateDir(dir), nil
+}
+
+// projectDir returns the directory whose instance this tash is
+func projectDir(cfg config.Config) string {
+	if cfg.WorkDir != "" {
+		return cfg.WorkDir
+	}
+	return "."
+}
+
+// runningInstance returns the instance already running for the project, if any
+func runningInstance(cfg config.Config) *instance.Info {
+	stateDir, err := instanceStateDir()
+	if err != nil {
+		return nil
+	}
+	info, err := instance.Find(stateDir, projectDir(cfg))
+	if err != nil {
+		slog.Warn("unable to look for a running instance", "error", err)
+		return nil
+	}
+	return info
+}
+
+// serveInstance makes this tash the instance of the project, so later launches can send it tasks
+// or follow its output. It returns a function releasing the project, or nil when another
+// instance holds it.
+func serveInstance(cfg config.Config, bus msgbus.PublisherSubscriber[task.Message], p *tea.Program) func() {
+	stateDir, err := instanceStateDir()
+	if err != nil {
+		return nil
+	}
+	server, err := instance.Listen(stateDir, projectDir(cfg))
+	if err != nil {
+		slog.Warn("not serving as the project's instance", "error", err)
+		return nil
+	}
+	return serve(server, bus, p)
+}
+
+// serve answers the requests sent to server with p, and returns a function releasing the project
+func serve(server *instance.Server, bus msgbus.PublisherSubscriber[task.Message], p *tea.Program) func() {
+	server.OnRun = func(req instance.Request) error {
+		return ui.RemoteRun(p, req)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	go server.Serve()
+	go func() {
+		if err := server.Follow(ctx, bus); err != nil {
+			slog.Warn("unable to follow task output for attached clients", "error", err)
+		}
+	}()
+	return func() {
+		cancel()
+		_ = server.Close()
+	}
+}
+
+// useRunningInstance offers to attach to the instance already running for the project, or to
+// send it a task, and returns tash's exit code. It returns -1 when a new instance should start
+// anyway. A task given with --run is sent without asking.
+func useRunningInstance(info *instance.Info, runTask string, in io.Reader, out io.Writer) int {
+	if runTask != "" {
+		return sendRun(info, runTask, out)
+	}
+	fmt.Fprintf(out, "tash is already running for this project (pid %d).\n", info.Pid)
+	fmt.Fprintln(out, "  a) attach to its output")
+	fmt.Fprintln(out, "  r) run a task in it")
+	fmt.Fprintln(out, "  n) start another instance anyway")
+	fmt.Fprintln(out, "  q) quit")
+	fmt.Fprint(out, "Choice [a]: ")
+	scanner := bufio.NewScanner(in)
+	scanner.Scan()
+	switch strings.ToLower(strings.TrimSpace(scanner.Text())) {
+	case "", "a":
+		return attach(info, out)
+	case "r":
+		fmt.Fprint(out, "Task: ")
+		scanner.Scan()
+		return sendRun(info, strings.TrimSpace(scanner.Text()), out)
+	case "n":
+		return -1
+	default:
+		return 0
+	}
+}
+
+// sendRun asks the running instance to run taskId
+func sendRun(info *instance.Info, taskId string, out io.Writer) int {
+	if _, err := instance.Send(info.Socket, instance.Request{Command: instance.CommandRun, Task: taskId}); err != nil {
+		fmt.Fprintf(out, "tash error: %s didn't run: %s\n", taskId, err)
+		return 1
+	}
+	fmt.Fprintf(out, "Running %s in the tash already open for this project (pid %d)\n", taskId, info.Pid)
+	return 0
+}
+
+// attach prints the output of the running instance until interrupted or the instance exits
+func attach(info *instance.Info, out io.Writer) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	fmt.Fprintf(out, "Attached to tash (pid %d), press Ctrl+C to detach\n", info.Pid)
+	err := instance.Attach(ctx, info.Socket, func(e instance.Event) {
+		printEvent(out, e)
+	})
+	if err != nil {
+		fmt.Fprintln(out, "tash error: "+err.Error())
+		return 1
+	}
+	return 0
+}
+
+// runAttach implements the "tash attach" subcommand
+func runAttach(cfg config.Config, out io.Writer) int {
+	info := runningInstance(cfg)
+	if info == nil {
+		fmt.Fprintln(out, "tash error: tash isn't running for this project")
+		return 1
+	}
+	return attach(info, out)
+}
+
+// printEvent prints an event of a running instance
+func printEvent(out io.Writer, e instance.Event) {
+	switch e.Event {
+	case instance.EventStart:
+		fmt.Fprintln(out, "-- running "+e.Task)
+	case instance.EventOutput:
+		fmt.Fprintln(out, e.Line)
+	case instance.EventDone:
+		fmt.Fprintln(out, "-- task executed successfully")
+	case instance.EventError:
+		fmt.Fprintln(out, "-- "+e.Error)
+	}
+}
+
+// runStatus implements the "tash status" subcommand, printing the status of the running instance
+// as JSON so editors can find its socket
+func runStatus(cfg config.Config, out, errOut io.Writer) int {
+	info := runningInstance(cfg)
+	if info == nil {
+		fmt.Fprintln(errOut, "tash error: tash isn't running for this project")
+		return 1
+	}
+	resp, err := instance.Send(info.Socket, instance.Request{Command: instance.CommandStatus})
+	if err != nil {
+		fmt.Fprintln(errOut, "tash error: "+err.Error())
+		return 1
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(resp.Status); err != nil {
+		fmt.Fprintln(errOut, "tash error: "+err.Error())
+		return 1
+	}
+	return 0
+}
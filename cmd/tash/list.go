@@ -0,0 +1,125 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Aj4x/tash/internal/task"
+)
+
+// listEntry is the headless representation of a task in the merged catalog
+type listEntry struct {
+	Id        string   `json:"id"`
+	Provider  string   `json:"provider"`
+	Namespace string   `json:"namespace"`
+	Desc      string   `json:"desc,omitempty"`
+	Summary   string   `json:"summary,omitempty"`
+	Aliases   []string `json:"aliases,omitempty"`
+	UpToDate  bool     `json:"up_to_date"`
+	Taskfile  string   `json:"taskfile,omitempty"`
+}
+
+// listTasks returns the tasks of the named provider, listed in env
+func listTasks(provider string, env task.Environment) ([]task.Task, error) {
+	if provider == task.ProviderDemo {
+		return task.DemoTasks(), nil
+	}
+	return task.ListAll(env)
+}
+
+func newListEntry(t task.Task, provider string) listEntry {
+	if provider == "" {
+		provider = task.ProviderTask
+	}
+	e := listEntry{
+		Id:        t.Id,
+		Provider:  provider,
+		Namespace: t.Namespace(),
+		Desc:      t.Desc,
+		Summary:   t.Summary,
+		Aliases:   t.Aliases,
+		UpToDate:  t.UpToDate,
+	}
+	if t.Location != nil {
+		e.Taskfile = t.Location.Taskfile
+	}
+	return e
+}
+
+// runList implements the "tash list" subcommand
+func runList(args []string, provider string, env task.Environment, out io.Writer) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	jsonFlag := fs.Bool("json", false, "Print the task list as JSON (shorthand for --format json)")
+	format := fs.String("format", "", "Output format: table (default on a terminal), text (tab-separated, the default otherwise) or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	switch {
+	case *jsonFlag:
+		*format = "json"
+	case *format == "" && isTerminal(out):
+		*format = "table"
+	case *format == "":
+		*format = "text"
+	}
+
+	tasks, err := listTasks(provider, env)
+	if err != nil {
+		return err
+	}
+	entries := make([]listEntry, len(tasks))
+	for i, t := range tasks {
+		entries[i] = newListEntry(t, provider)
+	}
+
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	case "table":
+		return printTable(out, entries)
+	case "text":
+		for _, e := range entries {
+			fmt.Fprintf(out, "%s\t%s\t%s\t%s\t%s\n", e.Id, e.Namespace, e.status(), strings.Join(e.Aliases, ","), e.Desc)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown list format %q", *format)
+	}
+}
+
+// status describes whether the task is up to date
+func (e listEntry) status() string {
+	if e.UpToDate {
+		return "up-to-date"
+	}
+	return "stale"
+}
+
+// printTable prints the entries as a table with aligned columns and a header, for reading
+func printTable(out io.Writer, entries []listEntry) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TASK\tNAMESPACE\tSTATUS\tALIASES\tDESCRIPTION")
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", e.Id, cmp.Or(e.Namespace, "-"), e.status(),
+			cmp.Or(strings.Join(e.Aliases, ", "), "-"), strings.ReplaceAll(e.Desc, "\n", " "))
+	}
+	return w.Flush()
+}
+
+// isTerminal reports whether out is a terminal rather than a pipe or file
+func isTerminal(out io.Writer) bool {
+	f, ok := out.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -3,17 +3,45 @@ package main
 import (
 	"flag"
 	"fmt"
+	"github.com/Aj4x/tash/internal/catalog"
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/frecency"
+	"github.com/Aj4x/tash/internal/history"
+	"github.com/Aj4x/tash/internal/instance"
+	"github.com/Aj4x/tash/internal/logging"
 	"github.com/Aj4x/tash/internal/msgbus"
+	"github.com/Aj4x/tash/internal/namespaces"
+	"github.com/Aj4x/tash/internal/runlog"
 	"github.com/Aj4x/tash/internal/task"
 	"github.com/Aj4x/tash/internal/ui"
+	"github.com/Aj4x/tash/internal/update"
 	tea "github.com/charmbracelet/bubbletea"
+	"log/slog"
 	"os"
+	"path/filepath"
 	"runtime/debug"
+	"time"
+	_ "time/tzdata" // time zones for time_zone on systems without a zone database, such as Windows
 )
 
 func main() {
+	started := time.Now()
+
 	// Parse command-line flags
 	versionFlag := flag.Bool("version", false, "Print version information")
+	debugFlag := flag.Bool("debug", false, "Write debug logs to the tash data directory")
+	mergeOutputFlag := flag.Bool("merge-output", false, "Merge task stdout and stderr to preserve line ordering")
+	themeFlag := flag.String("theme", "", "Color theme: default, high-contrast, deuteranopia or no-color")
+	runFlag := flag.String("run", "", "Run the task with this id or alias once the task list has loaded")
+	plainFlag := flag.Bool("plain", false, "Screen reader friendly mode: linear layout without borders or colors")
+	taskfileFlag := flag.String("taskfile", "", "Use this Taskfile, a path or the URL of a remote Taskfile, instead of the one in the working directory")
+	demoFlag := flag.Bool("demo", false, "Use built-in demo tasks instead of the Taskfile, no task binary required")
+	newInstanceFlag := flag.Bool("new-instance", false, "Start even when tash is already running for this project, instead of offering to use it")
+	pprofFlag := flag.String("pprof", "", "Serve net/http/pprof profiles at this localhost address, such as localhost:6060")
+	readOnlyFlag := flag.Bool("read-only", false, "Observer mode: list tasks and browse output, but don't run tasks or edit Taskfiles")
+	inlineFlag := flag.Bool("inline", false, "Run below the shell prompt in a compact layout instead of on the alternate screen, keeping the terminal's scrollback")
+	reducedMotionFlag := flag.Bool("reduced-motion", false, "Redraw less often and keep counters still, for slow connections and screen readers")
+	noColorFlag := flag.Bool("no-color", false, "Disable colors and use bold/underline styling only (also set by NO_COLOR)")
 	flag.Parse()
 
 	if *versionFlag {
@@ -26,11 +54,194 @@ func main() {
 		os.Exit(0)
 	}
 
+	cfg, err := config.LoadDefault()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "tash warning: "+err.Error())
+	}
+	if cfg, err = config.LoadProject(cfg, "."); err != nil {
+		fmt.Fprintln(os.Stderr, "tash warning: "+err.Error())
+	}
+
+	if *mergeOutputFlag {
+		cfg.MergeOutput = true
+	}
+	if *readOnlyFlag {
+		cfg.ReadOnly = true
+	}
+	if *themeFlag != "" {
+		cfg.Theme = *themeFlag
+	}
+	if *noColorFlag || os.Getenv("NO_COLOR") != "" {
+		cfg.Theme = ui.NoColorTheme.Name
+	}
+	if *plainFlag {
+		cfg.Plain = true
+	}
+	if *reducedMotionFlag {
+		cfg.ReducedMotion = true
+	}
+	if *inlineFlag {
+		cfg.Inline = true
+	}
+	if *taskfileFlag != "" {
+		cfg.Taskfile = *taskfileFlag
+	}
+	if *demoFlag {
+		cfg.Provider = task.ProviderDemo
+	}
+	if cfg.Plain {
+		cfg.Theme = ui.PlainTheme.Name
+	}
+	theme, err := ui.LookupTheme(cfg.Theme)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "tash warning: "+err.Error())
+	}
+	ui.ApplyTheme(theme)
+	if _, err := cfg.Location(); err != nil {
+		fmt.Fprintln(os.Stderr, "tash warning: "+err.Error())
+	}
+
+	logCloser, logPath := setupLogging(cfg.Debug || *debugFlag)
+	defer logCloser()
+
+	if *pprofFlag != "" {
+		addr, err := startPprof(*pprofFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "tash warning: "+err.Error())
+		} else {
+			slog.Info("serving pprof", "address", "http://"+addr+"/debug/pprof/")
+		}
+	}
+
+	// detect the task version up front, as it decides whether tasks are listed as JSON or text;
+	// the demo provider doesn't use the task binary
+	if cfg.Provider != task.ProviderDemo {
+		if v := task.InstalledVersion(ui.ExecEnvironment(cfg)); !v.SupportsJSON() {
+			slog.Info("task does not support JSON listing, falling back to the text listing", "version", v)
+		}
+	}
+
+	switch flag.Arg(0) {
+	case "list":
+		if err := runList(flag.Args()[1:], cfg.Provider, ui.ExecEnvironment(cfg), os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "tash error: "+err.Error())
+			logCloser()
+			os.Exit(1)
+		}
+		return
+	case "run":
+		code := runTasks(flag.Args()[1:], cfg, os.Stdout, os.Stderr)
+		logCloser()
+		os.Exit(code)
+	case "attach":
+		code := runAttach(cfg, os.Stdout)
+		logCloser()
+		os.Exit(code)
+	case "status":
+		code := runStatus(cfg, os.Stdout, os.Stderr)
+		logCloser()
+		os.Exit(code)
+	case "daemon":
+		code := runDaemon(flag.Args()[1:], cfg, os.Stdout, os.Stderr)
+		logCloser()
+		os.Exit(code)
+	case "web":
+		code := runWeb(flag.Args()[1:], cfg, os.Stdout, os.Stderr)
+		logCloser()
+		os.Exit(code)
+	case "gc":
+		code := runGC(flag.Args()[1:], cfg, os.Stdout, os.Stderr)
+		logCloser()
+		os.Exit(code)
+	}
+
+	// an interface launched while the daemon runs attaches to it; the daemon runs its tasks
+	var daemon *instance.Info
+	if info := runningInstance(cfg); info != nil && !*newInstanceFlag {
+		if info.Daemon {
+			daemon = info
+			cfg.WatchEnabled = false
+			cfg.Schedules = nil
+		} else if code := useRunningInstance(info, *runFlag, os.Stdin, os.Stdout); code >= 0 {
+			logCloser()
+			os.Exit(code)
+		}
+	}
+
 	messageBus := msgbus.NewMessageBus[task.Message]()
 
-	p := tea.NewProgram(ui.NewModel(messageBus), tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+	model := ui.NewModel(messageBus, cfg)
+	model.StartupTask = *runFlag
+	if cfg.Provider != task.ProviderDemo {
+		model.UseTaskVersion(task.InstalledVersion(ui.ExecEnvironment(cfg)))
+	}
+	model.Remote = daemon
+	model.LogPath = logPath
+	model.TimeStartup(started)
+	if dir, err := config.DataDir(); err == nil {
+		model.UseCatalogCache(catalog.NewStore(dir))
+		model.UseHistory(history.NewStore(dir))
+		model.UseRunLogs(runlog.NewStore(dir))
+		model.UseNamespaceStore(namespaces.NewStore(dir))
+		model.UsePickStore(frecency.NewStore(dir))
+		model.UseTourMarker(dir)
+		model.UseUpdateChecker(update.NewChecker(dir))
+	}
+	guard := ui.NewCrashGuard(model, crashLogDir())
+	options := []tea.ProgramOption{tea.WithFPS(ui.FPS(cfg))}
+	if !cfg.Inline {
+		options = append(options, tea.WithAltScreen())
+	}
+	p := tea.NewProgram(guard, options...)
+	var release func()
+	if daemon != nil {
+		release = attachInterface(daemon, p)
+	} else {
+		release = serveInstance(cfg, messageBus, p)
+	}
+	if release == nil {
+		release = func() {}
+	}
+	_, err = p.Run()
+	release()
+	if err != nil {
 		fmt.Println("tash error: " + err.Error())
+		logCloser()
 		os.Exit(1)
 	}
+	if crash := guard.Crash(); crash != nil {
+		fmt.Fprintln(os.Stderr, crash.Error())
+		logCloser()
+		os.Exit(2)
+	}
+}
+
+// crashLogDir returns the directory crash logs are written to, falling back to the temp dir
+func crashLogDir() string {
+	dir, err := config.DataDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "tash")
+	}
+	return filepath.Join(dir, "crash")
+}
+
+// setupLogging configures the debug log and returns a function that flushes and closes it, along
+// with the path of the log file, empty when logging is disabled
+func setupLogging(enabled bool) (func(), string) {
+	dir, err := config.DataDir()
+	if err == nil {
+		dir = filepath.Join(dir, "logs")
+	}
+	if err != nil && enabled {
+		fmt.Fprintln(os.Stderr, "tash warning: debug logging disabled: "+err.Error())
+		enabled = false
+	}
+	closer, path, err := logging.Setup(dir, enabled)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "tash warning: debug logging disabled: "+err.Error())
+	}
+	if path != "" {
+		slog.Debug("tash starting", "args", os.Args, "log", path)
+	}
+	return func() { _ = closer.Close() }, path
 }
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/task"
+	"github.com/Aj4x/tash/internal/ui"
+	"github.com/Aj4x/tash/pkg/tash"
+)
+
+// exitUsage is the exit code of a "tash run" invocation with invalid arguments
+const exitUsage = 2
+
+// runTasks implements the "tash run" subcommand: it runs each task in turn without the
+// interface, writing their output to stdout and stderr, and returns the exit code for tash.
+// By default the first failure stops the run and its exit code is returned; with --keep-going
+// the remaining tasks still run and --exit-code picks the first or the highest failing code.
+// With --events jsonl, stdout carries one JSON object per lifecycle event instead of the output.
+func runTasks(args []string, cfg config.Config, stdout, stderr io.Writer) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return runTasksContext(ctx, args, cfg, stdout, stderr)
+}
+
+// runTasksContext runs the tasks of "tash run" until ctx is cancelled, which interrupts them
+func runTasksContext(ctx context.Context, args []string, cfg config.Config, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	keepGoing := fs.Bool("keep-going", cfg.ContinueOnError, "Run the remaining tasks after one fails")
+	exitCode := fs.String("exit-code", "first", "Exit code when tasks fail: first (the first failure's) or worst (the highest)")
+	timeout := fs.Duration("timeout", 0, "Cancel each task once it exceeds this duration")
+	nice := fs.Bool("nice", false, "Run every task at reduced CPU and IO priority, not just those configured to")
+	events := fs.String("events", "", "Write lifecycle events to stdout instead of the output: jsonl (one JSON object per line)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: tash run [flags] task...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+	if *exitCode != "first" && *exitCode != "worst" {
+		fmt.Fprintf(stderr, "tash error: unknown exit code mode %q\n", *exitCode)
+		return exitUsage
+	}
+	var eventsOut *eventWriter
+	switch *events {
+	case "":
+	case "jsonl":
+		eventsOut = newEventWriter(stdout)
+	default:
+		fmt.Fprintf(stderr, "tash error: unknown events format %q\n", *events)
+		return exitUsage
+	}
+
+	code := 0
+	for _, id := range fs.Args() {
+		opts := taskRunOptions(cfg, id)
+		opts.Timeout = cmp.Or(*timeout, opts.Timeout)
+		opts.LowPriority = *nice || opts.LowPriority
+		start := time.Now()
+		if eventsOut != nil {
+			eventsOut.started(id)
+		}
+		err := tash.Run(ctx, id, opts, func(l tash.Line) {
+			switch {
+			case eventsOut != nil:
+				eventsOut.line(id, l)
+			case l.Stderr:
+				fmt.Fprintln(stderr, l.Text)
+			default:
+				fmt.Fprintln(stdout, l.Text)
+			}
+		})
+		failed := 0
+		if ctx.Err() != nil {
+			failed = task.ExitInterrupted
+			err = cmp.Or(err, ctx.Err())
+		} else if err != nil {
+			failed = tash.ExitCode(err)
+		}
+		if eventsOut != nil {
+			eventsOut.finished(id, time.Since(start), failed, err)
+		}
+		if ctx.Err() != nil {
+			fmt.Fprintf(stderr, "tash: %s interrupted\n", id)
+			return task.ExitInterrupted
+		}
+		if err == nil {
+			continue
+		}
+		fmt.Fprintf(stderr, "tash: %s failed after %s: %s\n", id, time.Since(start).Round(time.Millisecond), err)
+		if code == 0 || (*exitCode == "worst" && failed > code) {
+			code = failed
+		}
+		if !*keepGoing {
+			break
+		}
+	}
+	return code
+}
+
+// taskRunOptions returns the options of the task id run without the interface, by "tash run"
+// and the dashboard, configured the way the interface runs it
+func taskRunOptions(cfg config.Config, id string) tash.RunOptions {
+	return tash.RunOptions{
+		MergeOutput:  cfg.MergeOutput,
+		Timeout:      cfg.TimeoutFor(id),
+		Retries:      cfg.RetriesFor(id),
+		RetryBackoff: time.Duration(cfg.RetryBackoff),
+		Vars:         cfg.TaskVars[id],
+		Args:         ui.ParseRunArgs(cfg.TaskArgs[id]),
+		LowPriority:  cfg.LowPriorityFor(id),
+		GracePeriod:  time.Duration(cfg.CancelGracePeriod),
+		Demo:         cfg.Provider == task.ProviderDemo,
+		Dir:          cfg.WorkDir,
+		Taskfile:     cfg.Taskfile,
+		PathPrefix:   cfg.PathPrefix,
+		EnvAllow:     cfg.EnvAllow,
+		EnvDeny:      cfg.EnvDeny,
+		// output written to stdout often ends up in CI logs
+		RedactPatterns: cfg.RedactPatterns,
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/task"
+)
+
+// useFakeTaskBinary puts the fake task binary of testdata first on the PATH
+func useFakeTaskBinary(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+	script, err := os.ReadFile(filepath.Join("testdata", "task"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "task"), script, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestRunTasksExitCode(t *testing.T) {
+	useFakeTaskBinary(t)
+	for _, c := range []struct {
+		name    string
+		args    []string
+		want    int
+		ran     []string
+		skipped []string
+	}{
+		{name: "success", args: []string{"build", "lint"}, want: 0, ran: []string{"build", "lint"}},
+		{name: "first failure stops the run", args: []string{"fail2", "build"}, want: 2, ran: []string{"fail2"}, skipped: []string{"build"}},
+		{name: "keep going", args: []string{"--keep-going", "fail2", "build"}, want: 2, ran: []string{"fail2", "build"}},
+		{name: "first failure's code", args: []string{"--keep-going", "fail2", "fail3"}, want: 2, ran: []string{"fail2", "fail3"}},
+		{name: "worst failure's code", args: []string{"--keep-going", "--exit-code", "worst", "fail3", "fail2"}, want: 3, ran: []string{"fail3", "fail2"}},
+		{name: "worst without keep going", args: []string{"--exit-code", "worst", "fail2", "fail3"}, want: 2, ran: []string{"fail2"}, skipped: []string{"fail3"}},
+		{name: "unknown exit code mode", args: []string{"--exit-code", "last", "build"}, want: exitUsage},
+		{name: "no task", args: nil, want: exitUsage},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			got := runTasksContext(context.Background(), c.args, config.Default(), &stdout, &stderr)
+			if got != c.want {
+				t.Errorf("Expected exit code %d, got %d\n%s", c.want, got, stderr.String())
+			}
+			for _, id := range c.ran {
+				if !strings.Contains(stdout.String(), id) {
+					t.Errorf("Expected %s to run, got output:\n%s", id, stdout.String())
+				}
+			}
+			for _, id := range c.skipped {
+				if strings.Contains(stdout.String(), id) {
+					t.Errorf("Expected %s not to run, got output:\n%s", id, stdout.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRunTasksInterrupted(t *testing.T) {
+	useFakeTaskBinary(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+
+	var stdout, stderr bytes.Buffer
+	if got := runTasksContext(ctx, []string{"--keep-going", "slow", "build"}, config.Default(), &stdout, &stderr); got != task.ExitInterrupted {
+		t.Errorf("Expected exit code %d, got %d\n%s", task.ExitInterrupted, got, stderr.String())
+	}
+	if strings.Contains(stdout.String(), "build") {
+		t.Errorf("Expected the interrupt to stop the run, got output:\n%s", stdout.String())
+	}
+}
+
+func TestRunTasksUsesTheTaskConfig(t *testing.T) {
+	useFakeTaskBinary(t)
+	cfg := config.Default()
+	cfg.TaskTimeouts = map[string]config.Duration{"slow": config.Duration(100 * time.Millisecond)}
+	cfg.TaskVars = map[string][]string{"build": {"ENV=staging"}}
+	cfg.TaskArgs = map[string]string{"build": `--out "my dir"`}
+	cfg.TaskRetries = map[string]int{"fail2": 1}
+
+	var stdout, stderr bytes.Buffer
+	if got := runTasksContext(context.Background(), []string{"build"}, cfg, &stdout, &stderr); got != 0 {
+		t.Fatalf("Expected build to succeed, got %d\n%s", got, stderr.String())
+	}
+	if want := "build ENV=staging -- --out my dir"; !strings.Contains(stdout.String(), want) {
+		t.Errorf("Expected the task's vars and args, got output:\n%s", stdout.String())
+	}
+
+	stdout.Reset()
+	if got := runTasksContext(context.Background(), []string{"fail2"}, cfg, &stdout, &stderr); got != 2 {
+		t.Errorf("Expected fail2 to fail, got %d", got)
+	}
+	if n := strings.Count(stdout.String(), "fail2"); n != 2 {
+		t.Errorf("Expected fail2 to be retried once, ran %d times:\n%s", n, stdout.String())
+	}
+
+	if got := runTasksContext(context.Background(), []string{"slow"}, cfg, &stdout, &stderr); got != task.ExitTimeout {
+		t.Errorf("Expected the configured timeout to stop slow, got %d\n%s", got, stderr.String())
+	}
+}
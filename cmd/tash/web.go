@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/ui"
+	"github.com/Aj4x/tash/internal/web"
+	"github.com/Aj4x/tash/pkg/tash"
+)
+
+// shutdownTimeout is how long open requests get to finish when the dashboard stops
+const shutdownTimeout = 5 * time.Second
+
+// runWeb implements the "tash web" subcommand: it serves the dashboard listing the project's
+// tasks, running them and streaming their output, until interrupted
+func runWeb(args []string, cfg config.Config, out, errOut io.Writer) int {
+	fs := flag.NewFlagSet("web", flag.ContinueOnError)
+	fs.SetOutput(errOut)
+	addr := fs.String("addr", "localhost:7070", "Address to serve the dashboard at; use :7070 to share it on the network")
+	hosts := fs.String("hosts", "", "Comma-separated names the dashboard is reached by besides localhost, this machine's name and its addresses, e.g. tash.example.com")
+	if err := fs.Parse(args); err != nil || fs.NArg() > 0 {
+		return exitUsage
+	}
+
+	env := ui.ExecEnvironment(cfg)
+	server := &web.Server{
+		List: func() ([]tash.Task, error) {
+			tasks, err := listTasks(cfg.Provider, env)
+			if err != nil {
+				return nil, err
+			}
+			list := make([]tash.Task, len(tasks))
+			for i, t := range tasks {
+				e := newListEntry(t, cfg.Provider)
+				list[i] = tash.Task{ID: e.Id, Namespace: e.Namespace, Description: e.Desc, Summary: e.Summary,
+					Aliases: e.Aliases, UpToDate: e.UpToDate, Taskfile: e.Taskfile}
+			}
+			return list, nil
+		},
+		Run: func(ctx context.Context, id string, output func(tash.Line)) error {
+			return tash.Run(ctx, id, taskRunOptions(cfg, id), output)
+		},
+		ReadOnly: cfg.ReadOnly,
+		Hosts:    dashboardHosts(*addr, *hosts),
+	}
+
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		fmt.Fprintln(errOut, "tash error: "+err.Error())
+		return 1
+	}
+	fmt.Fprintf(out, "Serving the tash dashboard at http://%s\n", listener.Addr())
+	httpServer := &http.Server{Handler: server.Handler(), ReadHeaderTimeout: 10 * time.Second}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		httpServer.Shutdown(shutdown)
+	}()
+	defer server.Stop()
+	if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintln(errOut, "tash error: "+err.Error())
+		return 1
+	}
+	return 0
+}
+
+// dashboardHosts returns the names the dashboard at addr is reached by: localhost, the machine's
+// name, the host of addr and those listed in extra
+func dashboardHosts(addr, extra string) []string {
+	hosts := []string{"localhost"}
+	if name, err := os.Hostname(); err == nil {
+		hosts = append(hosts, name)
+	}
+	if host, _, err := net.SplitHostPort(addr); err == nil && host != "" {
+		hosts = append(hosts, host)
+	}
+	for _, h := range strings.Split(extra, ",") {
+		if h = strings.TrimSpace(h); h != "" {
+			hosts = append(hosts, h)
+		}
+	}
+	return hosts
+}
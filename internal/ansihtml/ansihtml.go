@@ -0,0 +1,203 @@
+// Package ansihtml renders terminal output colored with ANSI escape sequences as HTML.
+package ansihtml
+
+import (
+	"cmp"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// escapePattern matches the escape sequences of terminal output; only SGR sequences, ending in
+// "m", change how the text looks, the others are dropped
+var escapePattern = regexp.MustCompile(`\x1b\[([0-9;]*)([A-Za-z])`)
+
+// palette is the 16 standard terminal colors: black, red, green, yellow, blue, magenta, cyan and
+// white, then their bright variants
+var palette = [16]string{
+	"#000000", "#cd3131", "#0dbc79", "#e5e510", "#2472c8", "#bc3fbc", "#11a8cd", "#e5e5e5",
+	"#666666", "#f14c4c", "#23d18b", "#f5f543", "#3b8eea", "#d670d6", "#29b8db", "#ffffff",
+}
+
+// style is the look of the text at a point of a line
+type style struct {
+	fg, bg                         string
+	bold, faint, italic, underline bool
+	reverse                        bool
+}
+
+// css returns the inline style of s, empty for plain text
+func (s style) css() string {
+	fg, bg := s.fg, s.bg
+	if s.reverse {
+		fg, bg = cmp.Or(bg, "var(--bg)"), cmp.Or(fg, "var(--fg)")
+	}
+	var decls []string
+	if fg != "" {
+		decls = append(decls, "color:"+fg)
+	}
+	if bg != "" {
+		decls = append(decls, "background:"+bg)
+	}
+	for _, d := range []struct {
+		set  bool
+		decl string
+	}{
+		{s.bold, "font-weight:bold"},
+		{s.faint, "opacity:0.7"},
+		{s.italic, "font-style:italic"},
+		{s.underline, "text-decoration:underline"},
+	} {
+		if d.set {
+			decls = append(decls, d.decl)
+		}
+	}
+	return strings.Join(decls, ";")
+}
+
+// color256 returns the color numbered n in the 256 color palette
+func color256(n int) string {
+	switch {
+	case n < 16:
+		return palette[n]
+	case n < 232:
+		levels := [6]int{0, 95, 135, 175, 215, 255}
+		n -= 16
+		return fmt.Sprintf("#%02x%02x%02x", levels[n/36], levels[n/6%6], levels[n%6])
+	default:
+		gray := 8 + (n-232)*10
+		return fmt.Sprintf("#%02x%02x%02x", gray, gray, gray)
+	}
+}
+
+// extendedColor reads the color of a 38 or 48 parameter from the parameters following it, as
+// "5;n" or "2;r;g;b", returning the color and how many parameters it used
+func extendedColor(params []int) (string, int) {
+	switch {
+	case len(params) >= 2 && params[0] == 5 && params[1] >= 0 && params[1] < 256:
+		return color256(params[1]), 2
+	case len(params) >= 4 && params[0] == 2:
+		return fmt.Sprintf("#%02x%02x%02x", params[1]&0xff, params[2]&0xff, params[3]&0xff), 4
+	default:
+		return "", len(params)
+	}
+}
+
+// apply returns s changed by the parameters of an SGR sequence
+func (s style) apply(params []int) style {
+	if len(params) == 0 {
+		return style{}
+	}
+	for i := 0; i < len(params); i++ {
+		switch p := params[i]; {
+		case p == 0:
+			s = style{}
+		case p == 1:
+			s.bold = true
+		case p == 2:
+			s.faint = true
+		case p == 3:
+			s.italic = true
+		case p == 4:
+			s.underline = true
+		case p == 7:
+			s.reverse = true
+		case p == 22:
+			s.bold, s.faint = false, false
+		case p == 23:
+			s.italic = false
+		case p == 24:
+			s.underline = false
+		case p == 27:
+			s.reverse = false
+		case p >= 30 && p <= 37:
+			s.fg = palette[p-30]
+		case p >= 90 && p <= 97:
+			s.fg = palette[p-90+8]
+		case p == 39:
+			s.fg = ""
+		case p >= 40 && p <= 47:
+			s.bg = palette[p-40]
+		case p >= 100 && p <= 107:
+			s.bg = palette[p-100+8]
+		case p == 49:
+			s.bg = ""
+		case p == 38 || p == 48:
+			color, used := extendedColor(params[i+1:])
+			if p == 38 {
+				s.fg = color
+			} else {
+				s.bg = color
+			}
+			i += used
+		}
+	}
+	return s
+}
+
+// sgrParams parses the parameters of an SGR sequence; empty parameters are 0
+func sgrParams(s string) []int {
+	if s == "" {
+		return nil
+	}
+	fields := strings.Split(s, ";")
+	params := make([]int, len(fields))
+	for i, f := range fields {
+		params[i], _ = strconv.Atoi(f)
+	}
+	return params
+}
+
+// Line renders a line of terminal output as HTML, with its colors as styled spans
+func Line(line string) string {
+	var b strings.Builder
+	var current style
+	write := func(text string) {
+		if text == "" {
+			return
+		}
+		if css := current.css(); css != "" {
+			fmt.Fprintf(&b, `<span style="%s">%s</span>`, css, html.EscapeString(text))
+		} else {
+			b.WriteString(html.EscapeString(text))
+		}
+	}
+	last := 0
+	for _, m := range escapePattern.FindAllStringSubmatchIndex(line, -1) {
+		write(line[last:m[0]])
+		last = m[1]
+		if line[m[4]:m[5]] == "m" {
+			current = current.apply(sgrParams(line[m[2]:m[3]]))
+		}
+	}
+	write(line[last:])
+	return b.String()
+}
+
+// Document renders lines of terminal output as a standalone HTML page titled title, readable
+// in any browser without the terminal that produced them
+func Document(title string, lines []string) string {
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&b, "<title>%s</title>\n", html.EscapeString(title))
+	b.WriteString(`<style>
+:root { --bg: #1e1e1e; --fg: #d4d4d4; }
+body { background: var(--bg); color: var(--fg); margin: 2em; font-family: ui-monospace, Menlo, Consolas, monospace; }
+h1 { font-size: 1.1em; }
+pre { white-space: pre-wrap; word-break: break-all; line-height: 1.35; }
+</style>
+</head>
+<body>
+`)
+	fmt.Fprintf(&b, "<h1>%s</h1>\n<pre>", html.EscapeString(title))
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(Line(line))
+	}
+	b.WriteString("</pre>\n</body>\n</html>\n")
+	return b.String()
+}
@@ -0,0 +1,32 @@
+package ansihtml
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLine(t *testing.T) {
+	for _, tt := range []struct {
+		line, want string
+	}{
+		{"plain <b> & text", "plain &lt;b&gt; &amp; text"},
+		{"\x1b[31mFAIL\x1b[0m ok", `<span style="color:#cd3131">FAIL</span> ok`},
+		{"\x1b[1;92mPASS\x1b[22m still green\x1b[m", `<span style="color:#23d18b;font-weight:bold">PASS</span><span style="color:#23d18b"> still green</span>`},
+		{"\x1b[38;5;214mwarn\x1b[39m", `<span style="color:#ffaf00">warn</span>`},
+		{"\x1b[48;2;16;32;48mbg", `<span style="background:#102030">bg</span>`},
+		{"\x1b[2Kcleared\x1b[1A", "cleared"},
+	} {
+		if got := Line(tt.line); got != tt.want {
+			t.Errorf("Line(%q) = %s, want %s", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestDocument(t *testing.T) {
+	doc := Document("build <failed>", []string{"one", "\x1b[31mtwo\x1b[0m"})
+	for _, want := range []string{"<!DOCTYPE html>", "<title>build &lt;failed&gt;</title>", "<pre>one\n<span style=\"color:#cd3131\">two</span></pre>"} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("Expected %q in the document, got:\n%s", want, doc)
+		}
+	}
+}
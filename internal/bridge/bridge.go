@@ -0,0 +1,226 @@
+// Package bridge connects the daemon to an MQTT or NATS broker: the events of its task runs are
+// published to the broker, and tasks are run and cancelled on request from it, so existing
+// home-lab and ops automation can drive tash.
+package bridge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Aj4x/tash/internal/instance"
+)
+
+// DefaultPrefix starts the topics of the bridge unless configured otherwise
+const DefaultPrefix = "tash"
+
+const (
+	// dialTimeout bounds connecting and the handshake with the broker
+	dialTimeout = 10 * time.Second
+	// writeTimeout bounds a write to the broker, so a stalled broker doesn't hold up the daemon
+	writeTimeout = 10 * time.Second
+	// maxRetryDelay caps the delay between attempts to reconnect to the broker
+	maxRetryDelay = time.Minute
+)
+
+// EventRejected is published when a run requested through the broker is refused, e.g. because
+// another task is running; the refused task and the reason are in its task and error fields
+const EventRejected = "rejected"
+
+// message is a message received from the broker
+type message struct {
+	topic   string
+	reply   string // NATS subject answering a request, or empty
+	payload []byte
+}
+
+// broker is a connection to an MQTT or NATS broker
+type broker interface {
+	// separator joins the levels of topics: "/" for MQTT, "." for NATS
+	separator() string
+	subscribe(topic string) error
+	publish(topic string, payload []byte) error
+	// read calls handle with the messages of the subscriptions until the connection fails
+	read(handle func(message)) error
+	close() error
+}
+
+// dial connects to the broker of rawURL, chosen by its scheme
+func dial(rawURL string) (broker, error) {
+	if err := Validate(rawURL); err != nil {
+		return nil, err
+	}
+	u, _ := url.Parse(rawURL)
+	if u.Scheme == "nats" {
+		return dialNATS(u)
+	}
+	return dialMQTT(u)
+}
+
+// hostPort returns the address of the broker of u, with port unless u has one
+func hostPort(u *url.URL, port string) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// Validate reports whether rawURL is the URL of a supported broker, without connecting
+func Validate(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "nats" && u.Scheme != "mqtt" && u.Scheme != "mqtts" || u.Hostname() == "" {
+		return fmt.Errorf("unsupported broker %q, expected a nats://, mqtt:// or mqtts:// URL", redactURL(rawURL))
+	}
+	return nil
+}
+
+// Bridge relays between the daemon and the broker at URL. With the default prefix, it publishes
+// the events of the runs to tash/events/<event> (MQTT) or tash.events.<event> (NATS), runs the
+// task named by the messages of tash/run, either a task id or a JSON instance.Request, and
+// cancels the running task on any message to tash/cancel.
+type Bridge struct {
+	URL    string
+	Prefix string
+	// Run runs the requested task; Cancel cancels the running task
+	Run    func(instance.Request) error
+	Cancel func() error
+
+	mu   sync.Mutex
+	conn broker // Connection to the broker, or nil while disconnected
+}
+
+// topic returns the topic of the bridge made of levels, e.g. tash/events/start
+func (b *Bridge) topic(conn broker, levels ...string) string {
+	prefix := b.Prefix
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	return strings.Join(append([]string{prefix}, levels...), conn.separator())
+}
+
+// Serve connects to the broker and relays until ctx is done, reconnecting when the connection
+// fails
+func (b *Bridge) Serve(ctx context.Context) {
+	delay := time.Second
+	for ctx.Err() == nil {
+		err := b.serveOnce(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		slog.Warn("bridge to the broker disconnected, reconnecting", "url", redactURL(b.URL), "delay", delay, "error", err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+		delay = min(delay*2, maxRetryDelay)
+	}
+}
+
+// serveOnce relays over one connection to the broker, until it fails or ctx is done
+func (b *Bridge) serveOnce(ctx context.Context) error {
+	conn, err := dial(b.URL)
+	if err != nil {
+		return err
+	}
+	defer conn.close()
+	runTopic, cancelTopic := b.topic(conn, "run"), b.topic(conn, "cancel")
+	for _, topic := range []string{runTopic, cancelTopic} {
+		if err := conn.subscribe(topic); err != nil {
+			return err
+		}
+	}
+	slog.Info("bridged to the broker", "url", redactURL(b.URL), "run", runTopic, "cancel", cancelTopic)
+
+	b.mu.Lock()
+	b.conn = conn
+	b.mu.Unlock()
+	defer func() {
+		b.mu.Lock()
+		b.conn = nil
+		b.mu.Unlock()
+	}()
+	stop := context.AfterFunc(ctx, func() { conn.close() })
+	defer stop()
+	return conn.read(func(msg message) {
+		switch msg.topic {
+		case runTopic:
+			// the run waits for the daemon, while the connection keeps being read
+			go b.handleRun(conn, msg)
+		case cancelTopic:
+			go func() { b.reply(conn, msg, "", b.Cancel()) }()
+		}
+	})
+}
+
+// handleRun runs the task requested by msg
+func (b *Bridge) handleRun(conn broker, msg message) {
+	req := instance.Request{Task: strings.TrimSpace(string(msg.payload))}
+	if strings.HasPrefix(req.Task, "{") {
+		if err := json.Unmarshal(msg.payload, &req); err != nil {
+			b.reply(conn, msg, "", fmt.Errorf("invalid run request: %w", err))
+			return
+		}
+	}
+	req.Command = instance.CommandRun
+	if req.Task == "" {
+		b.reply(conn, msg, "", fmt.Errorf("the run request names no task"))
+		return
+	}
+	b.reply(conn, msg, req.Task, b.Run(req))
+}
+
+// reply answers a request with its outcome: NATS requests get an instance.Response on their
+// reply subject, and refused runs are published as a rejected event
+func (b *Bridge) reply(conn broker, msg message, taskId string, err error) {
+	if msg.reply != "" {
+		resp := instance.Response{OK: err == nil, Pid: os.Getpid()}
+		if err != nil {
+			resp.Error = err.Error()
+		}
+		if payload, mErr := json.Marshal(resp); mErr == nil {
+			conn.publish(msg.reply, payload)
+		}
+	}
+	if err != nil {
+		slog.Info("request from the broker refused", "topic", msg.topic, "task", taskId, "error", err)
+		b.Publish(instance.Event{Event: EventRejected, Task: taskId, Error: err.Error()})
+	}
+}
+
+// Publish publishes e to the broker; events are dropped while disconnected
+func (b *Bridge) Publish(e instance.Event) {
+	b.mu.Lock()
+	conn := b.conn
+	b.mu.Unlock()
+	if conn == nil {
+		return
+	}
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	if err := conn.publish(b.topic(conn, "events", e.Event), payload); err != nil {
+		slog.Debug("unable to publish to the broker", "error", err)
+	}
+}
+
+// redactURL returns rawURL without its password or token, for logs
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.User == nil {
+		return rawURL
+	}
+	u.User = nil
+	return u.String()
+}
@@ -0,0 +1,153 @@
+package bridge
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Aj4x/tash/internal/instance"
+)
+
+// fakeBroker accepts a single connection, handing it to serve
+func fakeBroker(t *testing.T, serve func(net.Conn)) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+		serve(conn)
+	}()
+	return l.Addr().String()
+}
+
+func TestNATSBridge(t *testing.T) {
+	requests := make(chan instance.Request, 1)
+	lines := make(chan string, 16)
+	addr := fakeBroker(t, func(conn net.Conn) {
+		r := bufio.NewReader(conn)
+		conn.Write([]byte("INFO {\"server_id\":\"test\"}\r\n"))
+		subs := 0
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			switch {
+			case line == "PING":
+				conn.Write([]byte("PONG\r\n"))
+			case strings.HasPrefix(line, "SUB "):
+				if subs++; subs == 2 {
+					conn.Write([]byte("MSG tash.run 1 _INBOX.1 5\r\nbuild\r\n"))
+				}
+			case strings.HasPrefix(line, "PUB "):
+				payload, _ := r.ReadString('\n')
+				lines <- line + " " + strings.TrimRight(payload, "\r\n")
+			default:
+				lines <- line
+			}
+		}
+	})
+
+	b := &Bridge{
+		URL: "nats://s3cret@" + addr,
+		Run: func(req instance.Request) error {
+			requests <- req
+			return nil
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go b.Serve(ctx)
+
+	if connect := <-lines; !strings.HasPrefix(connect, "CONNECT ") || !strings.Contains(connect, `"auth_token":"s3cret"`) {
+		t.Errorf("Expected the token to be sent, got %q", connect)
+	}
+	if req := <-requests; req.Task != "build" || req.Command != instance.CommandRun {
+		t.Errorf("Expected build to run, got %+v", req)
+	}
+	if reply := <-lines; !strings.HasPrefix(reply, "PUB _INBOX.1 ") || !strings.Contains(reply, `"ok":true`) {
+		t.Errorf("Expected the request to be answered, got %q", reply)
+	}
+	b.Publish(instance.Event{Event: instance.EventDone, Task: "build"})
+	if event := <-lines; !strings.HasPrefix(event, "PUB tash.events.done ") || !strings.Contains(event, `"task":"build"`) {
+		t.Errorf("Expected the event to be published, got %q", event)
+	}
+}
+
+func TestMQTTBridge(t *testing.T) {
+	published := make(chan message, 4)
+	addr := fakeBroker(t, func(conn net.Conn) {
+		c := &mqttConn{conn: conn, r: bufio.NewReader(conn)}
+		if kind, body, err := c.readPacket(); err != nil || kind != mqttConnect || body[7]&0xc0 != 0xc0 {
+			t.Errorf("Expected CONNECT with a user and password, got %x %v", kind, err)
+			return
+		}
+		c.writePacket(mqttConnAck, []byte{0, 0})
+		for subs := 0; ; {
+			kind, body, err := c.readPacket()
+			if err != nil {
+				return
+			}
+			switch kind & 0xf0 {
+			case mqttSubscribe:
+				if subs++; subs == 2 {
+					c.writePacket(mqttPublish, append(appendString(nil, "home/tash/run"), `{"task":"deploy","vars":["ENV=prod"]}`...))
+				}
+			case mqttPublish:
+				n := int(binary.BigEndian.Uint16(body))
+				published <- message{topic: string(body[2 : 2+n]), payload: body[2+n:]}
+			}
+		}
+	})
+
+	b := &Bridge{
+		URL:    "mqtt://ann:pw@" + addr,
+		Prefix: "home/tash",
+		Run: func(req instance.Request) error {
+			if req.Task != "deploy" || len(req.Vars) != 1 {
+				t.Errorf("Expected the JSON request to be read, got %+v", req)
+			}
+			return errors.New("waiting for build to finish")
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go b.Serve(ctx)
+
+	msg := <-published
+	var e instance.Event
+	if json.Unmarshal(msg.payload, &e); msg.topic != "home/tash/events/rejected" || e.Task != "deploy" || e.Error != "waiting for build to finish" {
+		t.Errorf("Expected the refused run to be published, got %s %s", msg.topic, msg.payload)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, u := range []string{"nats://localhost", "mqtt://broker:1883", "mqtts://user:pw@broker"} {
+		if err := Validate(u); err != nil {
+			t.Errorf("Validate(%q) = %v", u, err)
+		}
+	}
+	for _, u := range []string{"", "http://localhost", "amqp://broker", "mqtt://"} {
+		if err := Validate(u); err == nil {
+			t.Errorf("Expected %q to be refused", u)
+		}
+	}
+	if got := redactURL("nats://user:pw@localhost:4222"); got != "nats://localhost:4222" {
+		t.Errorf("Expected the credentials to be left out, got %q", got)
+	}
+}
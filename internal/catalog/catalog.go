@@ -0,0 +1,168 @@
+// Package catalog caches the task listing of each project, so tash can show the tasks at startup
+// without waiting for task to list them. A cached listing is only used while none of the
+// Taskfiles it was listed from has changed.
+package catalog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DirName is the name of the directory inside the data directory holding the cached listings
+const DirName = "catalog"
+
+// Store keeps a cached listing per project directory. A Store with an empty directory is
+// disabled.
+type Store struct {
+	Dir string
+}
+
+// NewStore returns a store keeping its listings in the data directory dataDir
+func NewStore(dataDir string) Store {
+	return Store{Dir: filepath.Join(dataDir, DirName)}
+}
+
+// stamp identifies a version of a Taskfile
+type stamp struct {
+	ModTime time.Time `json:"mod_time"`
+	Size    int64     `json:"size"`
+}
+
+// entry is the cached listing of a project, or of a remote Taskfile
+type entry struct {
+	Dir       string           `json:"dir,omitempty"`
+	URL       string           `json:"url,omitempty"`
+	Listing   string           `json:"listing"`
+	Taskfiles map[string]stamp `json:"taskfiles,omitempty"`
+	Listed    time.Time        `json:"listed,omitempty"`
+}
+
+// path returns the file caching the listing of the project in dir
+func (s Store) path(dir string) (string, string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", "", err
+	}
+	return s.file(abs), abs, nil
+}
+
+// file returns the file caching the listing identified by key
+func (s Store) file(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.Dir, hex.EncodeToString(sum[:8])+".json")
+}
+
+// stampOf returns the stamp of the Taskfile at path
+func stampOf(path string) (stamp, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return stamp{}, err
+	}
+	return stamp{ModTime: info.ModTime().UTC(), Size: info.Size()}, nil
+}
+
+// Load returns the cached listing of the project in dir. It reports false when nothing is cached
+// or one of the Taskfiles the listing came from has changed since.
+func (s Store) Load(dir string) (string, bool) {
+	if s.Dir == "" {
+		return "", false
+	}
+	path, abs, err := s.path(dir)
+	if err != nil {
+		return "", false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil || e.Dir != abs || len(e.Taskfiles) == 0 {
+		return "", false
+	}
+	for taskfile, cached := range e.Taskfiles {
+		if current, err := stampOf(taskfile); err != nil || !current.ModTime.Equal(cached.ModTime) || current.Size != cached.Size {
+			return "", false
+		}
+	}
+	return e.Listing, true
+}
+
+// Save caches the listing of the project in dir, which was listed from taskfiles. Listings that
+// don't come from any Taskfile aren't cached, as nothing would invalidate them.
+func (s Store) Save(dir, listing string, taskfiles []string) error {
+	if s.Dir == "" || len(taskfiles) == 0 {
+		return nil
+	}
+	path, abs, err := s.path(dir)
+	if err != nil {
+		return err
+	}
+	e := entry{Dir: abs, Listing: listing, Taskfiles: map[string]stamp{}}
+	for _, taskfile := range taskfiles {
+		st, err := stampOf(taskfile)
+		if err != nil {
+			return fmt.Errorf("unable to cache the task list: %w", err)
+		}
+		e.Taskfiles[taskfile] = st
+	}
+	return s.write(path, e)
+}
+
+// LoadRemote returns the cached listing of the remote Taskfile at url and when it was listed.
+// Nothing tells whether the Taskfile changed since, so the listing is only meant to be shown
+// until task lists the Taskfile again, or when it can't be fetched.
+func (s Store) LoadRemote(url string) (string, time.Time, bool) {
+	if s.Dir == "" {
+		return "", time.Time{}, false
+	}
+	data, err := os.ReadFile(s.file(url))
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil || e.URL != url {
+		return "", time.Time{}, false
+	}
+	return e.Listing, e.Listed, true
+}
+
+// SaveRemote caches the listing of the remote Taskfile at url
+func (s Store) SaveRemote(url, listing string) error {
+	if s.Dir == "" {
+		return nil
+	}
+	return s.write(s.file(url), entry{URL: url, Listing: listing, Listed: time.Now().UTC()})
+}
+
+// write stores e in the file at path
+func (s Store) write(path string, e entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return fmt.Errorf("unable to create the catalog cache directory: %w", err)
+	}
+	// the listing is written to a temp file first, so a concurrent Load never reads half of it
+	tmp, err := os.CreateTemp(s.Dir, "listing-*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("unable to cache the task list: %w", err)
+	}
+	return nil
+}
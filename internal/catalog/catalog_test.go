@@ -0,0 +1,72 @@
+package catalog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadReturnsTheSavedListing(t *testing.T) {
+	store := NewStore(t.TempDir())
+	project := t.TempDir()
+	taskfile := filepath.Join(project, "Taskfile.yml")
+	if err := os.WriteFile(taskfile, []byte("version: '3'\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := store.Load(project); ok {
+		t.Fatal("Expected nothing cached yet")
+	}
+	if err := store.Save(project, `{"tasks": []}`, []string{taskfile}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	listing, ok := store.Load(project)
+	if !ok || listing != `{"tasks": []}` {
+		t.Fatalf("Expected the saved listing, got %q, %v", listing, ok)
+	}
+	if _, ok := store.Load(t.TempDir()); ok {
+		t.Error("Expected nothing cached for another project")
+	}
+
+	// editing the Taskfile invalidates the listing
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(taskfile, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.Load(project); ok {
+		t.Error("Expected the listing to be invalidated by the changed Taskfile")
+	}
+}
+
+func TestListingsWithoutTaskfilesAreNotCached(t *testing.T) {
+	store := NewStore(t.TempDir())
+	project := t.TempDir()
+	if err := store.Save(project, `{"tasks": []}`, nil); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, ok := store.Load(project); ok {
+		t.Error("Expected a listing without Taskfiles not to be cached")
+	}
+	if _, ok := (Store{}).Load(project); ok {
+		t.Error("Expected a disabled store to cache nothing")
+	}
+}
+
+func TestRemoteListingsAreCachedByURL(t *testing.T) {
+	store := NewStore(t.TempDir())
+	url := "https://example.com/Taskfile.yml"
+	if _, _, ok := store.LoadRemote(url); ok {
+		t.Fatal("Expected nothing cached yet")
+	}
+	if err := store.SaveRemote(url, `{"tasks": []}`); err != nil {
+		t.Fatalf("SaveRemote() error = %v", err)
+	}
+	listing, listed, ok := store.LoadRemote(url)
+	if !ok || listing != `{"tasks": []}` || time.Since(listed) > time.Minute {
+		t.Fatalf("Expected the saved listing, got %q listed %v, %v", listing, listed, ok)
+	}
+	if _, _, ok := store.LoadRemote("https://example.com/other.yml"); ok {
+		t.Error("Expected nothing cached for another Taskfile")
+	}
+}
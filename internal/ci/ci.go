@@ -0,0 +1,351 @@
+// Package ci reads the jobs of a project's GitHub Actions workflows and GitLab CI pipeline, so
+// they can be listed, and run locally, alongside the project's tasks
+package ci
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Namespace is the namespace of the task ids of CI jobs
+const Namespace = "ci"
+
+// Pipelines defining jobs
+const (
+	GitHub = "github"
+	GitLab = "gitlab"
+)
+
+// defaultImage is the image GitLab jobs without one run in, as runners are configured with
+// their own default
+const defaultImage = "alpine:latest"
+
+// Job is a job of a CI pipeline
+type Job struct {
+	// Pipeline is the CI system defining the job, GitHub or GitLab
+	Pipeline string
+	// File is the path of the workflow or pipeline file defining the job
+	File string
+	// Line is the line of the job's definition in File
+	Line int
+	// Key is the job's key in File
+	Key string
+	// Name is the job's display name, its key unless it sets one
+	Name string
+	// Workflow is the name of the GitHub workflow the job belongs to
+	Workflow string
+	// Stage is the stage of a GitLab job
+	Stage string
+	// Image is the image a GitLab job runs in
+	Image string
+	// Script are the commands of a GitLab job, its before_script followed by its script
+	Script []string
+	// Variables are the variables of a GitLab job, including the pipeline's
+	Variables map[string]string
+}
+
+// Id returns the task id of the job in the ci namespace: "ci:<workflow file>:<key>" for GitHub
+// jobs and "ci:gitlab:<key>" for GitLab jobs
+func (j Job) Id() string {
+	if j.Pipeline == GitHub {
+		name := filepath.Base(j.File)
+		return Namespace + ":" + strings.TrimSuffix(name, filepath.Ext(name)) + ":" + j.Key
+	}
+	return Namespace + ":" + GitLab + ":" + j.Key
+}
+
+// Description returns a one line description of the job for the task list
+func (j Job) Description() string {
+	if j.Pipeline == GitHub {
+		workflow := cmp.Or(j.Workflow, filepath.Base(j.File))
+		return fmt.Sprintf("%s (GitHub Actions workflow %s)", j.Name, workflow)
+	}
+	if j.Stage != "" {
+		return fmt.Sprintf("%s (GitLab CI stage %s)", j.Name, j.Stage)
+	}
+	return j.Name + " (GitLab CI)"
+}
+
+// Runner holds the commands running jobs locally
+type Runner struct {
+	// Act runs GitHub Actions jobs; empty means "act"
+	Act string
+	// Container is the container engine running GitLab jobs in their image; empty means "docker"
+	Container string
+}
+
+// Args returns the command line running the job locally in dir: GitHub jobs with act, GitLab
+// jobs in their image with the project mounted as the working directory
+func (r Runner) Args(j Job, dir string) []string {
+	if j.Pipeline == GitHub {
+		return []string{cmp.Or(r.Act, "act"), "--workflows", j.File, "--job", j.Key}
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	const workdir = "/builds/project"
+	args := []string{cmp.Or(r.Container, "docker"), "run", "--rm", "--volume", dir + ":" + workdir, "--workdir", workdir}
+	for _, name := range slices.Sorted(maps.Keys(j.Variables)) {
+		args = append(args, "--env", name+"="+j.Variables[name])
+	}
+	script := append([]string{"set -e"}, j.Script...)
+	return append(args, cmp.Or(j.Image, defaultImage), "sh", "-c", strings.Join(script, "\n"))
+}
+
+// Load returns the jobs of the GitHub Actions workflows and the GitLab CI pipeline in dir. Files
+// that can't be parsed are skipped and reported in the error, along with the jobs of the others.
+func Load(dir string) ([]Job, error) {
+	var jobs []Job
+	var errs []error
+	workflows, _ := filepath.Glob(filepath.Join(dir, ".github", "workflows", "*.y*ml"))
+	for _, path := range workflows {
+		if ext := filepath.Ext(path); ext != ".yml" && ext != ".yaml" {
+			continue
+		}
+		found, err := loadFile(path, parseGitHub)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		jobs = append(jobs, found...)
+	}
+	gitlab := filepath.Join(dir, ".gitlab-ci.yml")
+	if _, err := os.Stat(gitlab); err == nil {
+		found, err := loadFile(gitlab, parseGitLab)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		jobs = append(jobs, found...)
+	}
+	return jobs, errors.Join(errs...)
+}
+
+// loadFile parses the jobs of the file at path with parse
+func loadFile(path string, parse func(path string, root *yaml.Node) []Job) ([]Job, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	return parse(path, doc.Content[0]), nil
+}
+
+// parseGitHub returns the jobs of a GitHub Actions workflow
+func parseGitHub(path string, root *yaml.Node) []Job {
+	var workflow string
+	if n := value(root, "name"); n != nil {
+		workflow = n.Value
+	}
+	jobsNode := value(root, "jobs")
+	if jobsNode == nil || jobsNode.Kind != yaml.MappingNode {
+		return nil
+	}
+	var jobs []Job
+	for i := 0; i+1 < len(jobsNode.Content); i += 2 {
+		key, def := jobsNode.Content[i], jobsNode.Content[i+1]
+		job := Job{Pipeline: GitHub, File: path, Line: key.Line, Key: key.Value, Name: key.Value, Workflow: workflow}
+		if n := value(def, "name"); n != nil && n.Value != "" {
+			job.Name = n.Value
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs
+}
+
+// gitlabKeywords are the top-level keys of a GitLab pipeline that aren't jobs
+var gitlabKeywords = []string{
+	"after_script", "before_script", "cache", "default", "image", "include", "services", "spec",
+	"stages", "variables", "workflow",
+}
+
+// gitlabJob holds the keywords of a GitLab job that are used to run it
+type gitlabJob struct {
+	Stage        string
+	Image        string
+	BeforeScript []string
+	Script       []string
+	Variables    map[string]string
+}
+
+// parseGitLab returns the jobs of a GitLab CI pipeline that run a script. Hidden jobs, whose keys
+// start with ".", only serve as templates extended by the others.
+func parseGitLab(path string, root *yaml.Node) []Job {
+	defs := map[string]*yaml.Node{}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		defs[root.Content[i].Value] = root.Content[i+1]
+	}
+	global := gitlabJob{Variables: map[string]string{}}
+	if n := value(root, "variables"); n != nil {
+		global.Variables = variables(n)
+	}
+	if n := value(root, "image"); n != nil {
+		global.Image = image(n)
+	}
+	if n := value(root, "before_script"); n != nil {
+		global.BeforeScript = script(n)
+	}
+	if d := value(root, "default"); d != nil {
+		if n := value(d, "image"); n != nil {
+			global.Image = image(n)
+		}
+		if n := value(d, "before_script"); n != nil {
+			global.BeforeScript = script(n)
+		}
+	}
+
+	var jobs []Job
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, def := root.Content[i], root.Content[i+1]
+		if strings.HasPrefix(key.Value, ".") || slices.Contains(gitlabKeywords, key.Value) || def.Kind != yaml.MappingNode {
+			continue
+		}
+		resolved := resolveGitLab(key.Value, defs, 0)
+		if resolved.Script == nil {
+			// trigger jobs start other pipelines, which can't be run locally
+			continue
+		}
+		job := Job{
+			Pipeline:  GitLab,
+			File:      path,
+			Line:      key.Line,
+			Key:       key.Value,
+			Name:      key.Value,
+			Stage:     cmp.Or(resolved.Stage, "test"),
+			Image:     cmp.Or(resolved.Image, global.Image),
+			Variables: map[string]string{},
+		}
+		before := resolved.BeforeScript
+		if before == nil {
+			before = global.BeforeScript
+		}
+		job.Script = append(slices.Clone(before), resolved.Script...)
+		maps.Copy(job.Variables, global.Variables)
+		maps.Copy(job.Variables, resolved.Variables)
+		jobs = append(jobs, job)
+	}
+	return jobs
+}
+
+// maxExtends bounds the chain of templates a GitLab job extends, which GitLab limits too
+const maxExtends = 11
+
+// resolveGitLab returns the keywords of the named job merged over those of the templates it
+// extends, in order
+func resolveGitLab(name string, defs map[string]*yaml.Node, depth int) gitlabJob {
+	def := defs[name]
+	if def == nil || def.Kind != yaml.MappingNode || depth > maxExtends {
+		return gitlabJob{}
+	}
+	var extends []string
+	if n := value(def, "extends"); n != nil {
+		if n.Kind == yaml.ScalarNode {
+			extends = []string{n.Value}
+		} else {
+			_ = n.Decode(&extends)
+		}
+	}
+	resolved := gitlabJob{Variables: map[string]string{}}
+	for _, parent := range extends {
+		p := resolveGitLab(parent, defs, depth+1)
+		resolved.Stage = cmp.Or(p.Stage, resolved.Stage)
+		resolved.Image = cmp.Or(p.Image, resolved.Image)
+		if p.BeforeScript != nil {
+			resolved.BeforeScript = p.BeforeScript
+		}
+		if p.Script != nil {
+			resolved.Script = p.Script
+		}
+		maps.Copy(resolved.Variables, p.Variables)
+	}
+	if n := value(def, "stage"); n != nil {
+		resolved.Stage = n.Value
+	}
+	if n := value(def, "image"); n != nil {
+		resolved.Image = image(n)
+	}
+	if n := value(def, "before_script"); n != nil {
+		resolved.BeforeScript = script(n)
+	}
+	if n := value(def, "script"); n != nil {
+		resolved.Script = script(n)
+	}
+	if n := value(def, "variables"); n != nil {
+		maps.Copy(resolved.Variables, variables(n))
+	}
+	return resolved
+}
+
+// value returns the value of key in the mapping n, or nil
+func value(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// image returns the name of an image, given as a string or as a mapping with a name
+func image(n *yaml.Node) string {
+	if n.Kind == yaml.MappingNode {
+		if name := value(n, "name"); name != nil {
+			return name.Value
+		}
+		return ""
+	}
+	return n.Value
+}
+
+// script returns the commands of a script, a command or a list of commands and nested lists.
+// Commands referenced with !reference can't be followed and are left out.
+func script(n *yaml.Node) []string {
+	if n.Tag == "!reference" {
+		return nil
+	}
+	switch n.Kind {
+	case yaml.ScalarNode:
+		return []string{n.Value}
+	case yaml.SequenceNode:
+		commands := []string{}
+		for _, c := range n.Content {
+			commands = append(commands, script(c)...)
+		}
+		return commands
+	}
+	return nil
+}
+
+// variables returns the variables of a mapping, whose values are strings or mappings with a value
+func variables(n *yaml.Node) map[string]string {
+	vars := map[string]string{}
+	if n.Kind != yaml.MappingNode {
+		return vars
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		v := n.Content[i+1]
+		if v.Kind == yaml.MappingNode {
+			if value := value(v, "value"); value != nil {
+				vars[n.Content[i].Value] = value.Value
+			}
+			continue
+		}
+		vars[n.Content[i].Value] = v.Value
+	}
+	return vars
+}
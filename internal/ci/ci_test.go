@@ -0,0 +1,134 @@
+package ci
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadGitHubWorkflows(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".github", "workflows", "ci.yml"), `name: CI
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: go build ./...
+  lint:
+    name: Lint code
+    runs-on: ubuntu-latest
+`)
+	writeFile(t, filepath.Join(dir, ".github", "workflows", "README.md"), "not a workflow")
+
+	jobs, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("Expected 2 jobs, got %+v", jobs)
+	}
+	if jobs[0].Id() != "ci:ci:build" || jobs[0].Line != 4 {
+		t.Errorf("Expected build at line 4, got %s at line %d", jobs[0].Id(), jobs[0].Line)
+	}
+	if got := jobs[1].Description(); got != "Lint code (GitHub Actions workflow CI)" {
+		t.Errorf("Expected the job's name and workflow, got %q", got)
+	}
+	args := Runner{}.Args(jobs[0], dir)
+	if strings.Join(args, " ") != "act --workflows "+jobs[0].File+" --job build" {
+		t.Errorf("Expected the job to run with act, got %v", args)
+	}
+}
+
+func TestLoadGitLabPipeline(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".gitlab-ci.yml"), `image: golang:1.23
+variables:
+  GOFLAGS: -mod=mod
+stages: [build, test]
+.tests:
+  stage: test
+  before_script:
+    - go version
+  variables:
+    CGO_ENABLED: "0"
+build:
+  stage: build
+  script: go build ./...
+unit:
+  extends: .tests
+  image:
+    name: golang:1.22
+  script:
+    - go test ./...
+    - [go vet ./...]
+deploy:
+  trigger: ops/deploy
+`)
+
+	jobs, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, j := range jobs {
+		ids = append(ids, j.Id())
+	}
+	if !slices.Equal(ids, []string{"ci:gitlab:build", "ci:gitlab:unit"}) {
+		t.Fatalf("Expected the jobs running scripts, got %v", ids)
+	}
+	build, unit := jobs[0], jobs[1]
+	if build.Image != "golang:1.23" || build.Description() != "build (GitLab CI stage build)" {
+		t.Errorf("Expected build to use the pipeline's image, got %+v", build)
+	}
+	if unit.Image != "golang:1.22" || unit.Stage != "test" {
+		t.Errorf("Expected unit to extend the template with its own image, got %+v", unit)
+	}
+	if !slices.Equal(unit.Script, []string{"go version", "go test ./...", "go vet ./..."}) {
+		t.Errorf("Expected the before_script and script, got %q", unit.Script)
+	}
+	if unit.Variables["GOFLAGS"] != "-mod=mod" || unit.Variables["CGO_ENABLED"] != "0" {
+		t.Errorf("Expected the pipeline's and the template's variables, got %v", unit.Variables)
+	}
+
+	args := Runner{Container: "podman"}.Args(unit, dir)
+	want := []string{"podman", "run", "--rm", "--volume", dir + ":/builds/project", "--workdir", "/builds/project",
+		"--env", "CGO_ENABLED=0", "--env", "GOFLAGS=-mod=mod", "golang:1.22", "sh", "-c",
+		"set -e\ngo version\ngo test ./...\ngo vet ./..."}
+	if !slices.Equal(args, want) {
+		t.Errorf("Expected the job to run in its image\n got %q\nwant %q", args, want)
+	}
+}
+
+func TestLoadReportsInvalidFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".github", "workflows", "broken.yaml"), "jobs: [unclosed")
+	writeFile(t, filepath.Join(dir, ".gitlab-ci.yml"), "test:\n  script: make test\n")
+
+	jobs, err := Load(dir)
+	if err == nil || !strings.Contains(err.Error(), "broken.yaml") {
+		t.Errorf("Expected an error naming the broken workflow, got %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].Key != "test" {
+		t.Errorf("Expected the jobs of the other files, got %+v", jobs)
+	}
+}
+
+func TestLoadWithoutPipelines(t *testing.T) {
+	jobs, err := Load(t.TempDir())
+	if err != nil || len(jobs) != 0 {
+		t.Errorf("Expected no jobs and no error, got %v, %v", jobs, err)
+	}
+}
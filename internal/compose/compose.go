@@ -0,0 +1,130 @@
+// Package compose lists the services of a Docker Compose project and builds the command lines
+// managing them
+package compose
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/Aj4x/tash/internal/task"
+)
+
+// Files are the names compose looks for its file by, in order of preference
+var Files = []string{"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"}
+
+// Find returns the compose file in dir, if it has one
+func Find(dir string) (string, bool) {
+	for _, name := range Files {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// Project is a compose project and the command managing it
+type Project struct {
+	// Command runs compose; empty means "docker compose"
+	Command string
+	// File is the compose file; empty lets compose find it in the working directory
+	File string
+}
+
+// Args returns the command line running compose with args for the project
+func (p Project) Args(args ...string) []string {
+	command := strings.Fields(cmp.Or(p.Command, "docker compose"))
+	if p.File != "" {
+		command = append(command, "--file", p.File)
+	}
+	return append(command, args...)
+}
+
+// Service is a service of a project and the state of its container
+type Service struct {
+	Name string
+	// State is the state of the service's container, e.g. "running" or "exited"; empty when the
+	// service has no container
+	State string
+	// Status describes the state, e.g. "Up 5 minutes (healthy)"
+	Status string
+}
+
+// Running reports whether the service's container is running
+func (s Service) Running() bool {
+	return s.State == "running"
+}
+
+// Services returns the services of the project, in the order of the compose file, with the
+// state of their containers. The commands run in env.
+func (p Project) Services(ctx context.Context, env task.Environment) ([]Service, error) {
+	names, err := env.Command(ctx, nil, p.Args("config", "--services")...).Output()
+	if err != nil {
+		return nil, commandError(err)
+	}
+	ps, err := env.Command(ctx, nil, p.Args("ps", "--all", "--format", "json")...).Output()
+	if err != nil {
+		return nil, commandError(err)
+	}
+	containers, err := parsePS(ps)
+	if err != nil {
+		return nil, err
+	}
+	var services []Service
+	for _, name := range strings.Fields(string(names)) {
+		s := Service{Name: name}
+		for _, c := range containers {
+			// a scaled service is running while any of its containers is
+			if c.Service == name && !s.Running() {
+				s.State, s.Status = c.State, c.Status
+			}
+		}
+		services = append(services, s)
+	}
+	return services, nil
+}
+
+// container is a container listed by "compose ps"
+type container struct {
+	Service string `json:"Service"`
+	State   string `json:"State"`
+	Status  string `json:"Status"`
+}
+
+// parsePS parses the containers listed by "compose ps --format json": a JSON array from older
+// versions of compose, one JSON object per line from newer ones
+func parsePS(data []byte) ([]container, error) {
+	data = bytes.TrimSpace(data)
+	var containers []container
+	if bytes.HasPrefix(data, []byte("[")) {
+		err := json.Unmarshal(data, &containers)
+		return containers, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for dec.More() {
+		var c container
+		if err := dec.Decode(&c); err != nil {
+			return nil, err
+		}
+		containers = append(containers, c)
+	}
+	return containers, nil
+}
+
+// commandError returns the message compose printed for err, or err itself when it printed none
+func commandError(err error) error {
+	var exitError *exec.ExitError
+	if errors.As(err, &exitError) {
+		if msg := strings.TrimSpace(string(exitError.Stderr)); msg != "" {
+			return errors.New(msg)
+		}
+	}
+	return err
+}
@@ -0,0 +1,87 @@
+package compose
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/task"
+)
+
+func TestProjectArgs(t *testing.T) {
+	got := Project{}.Args("up", "--detach", "web")
+	if !slices.Equal(got, []string{"docker", "compose", "up", "--detach", "web"}) {
+		t.Errorf("Expected docker compose by default, got %q", got)
+	}
+	got = Project{Command: "podman compose", File: "dev.yaml"}.Args("logs")
+	if !slices.Equal(got, []string{"podman", "compose", "--file", "dev.yaml", "logs"}) {
+		t.Errorf("Expected the configured command and file, got %q", got)
+	}
+}
+
+func TestFind(t *testing.T) {
+	dir := t.TempDir()
+	if _, ok := Find(dir); ok {
+		t.Error("Expected no compose file")
+	}
+	os.WriteFile(filepath.Join(dir, "docker-compose.yml"), nil, 0o644)
+	os.WriteFile(filepath.Join(dir, "compose.yaml"), nil, 0o644)
+	if path, ok := Find(dir); !ok || filepath.Base(path) != "compose.yaml" {
+		t.Errorf("Expected the preferred compose file, got %q", path)
+	}
+}
+
+func TestParsePS(t *testing.T) {
+	lines := `{"Service":"db","State":"running","Status":"Up 2 minutes"}
+{"Service":"web","State":"exited","Status":"Exited (1) 5 seconds ago"}
+`
+	array := `[{"Service":"db","State":"running","Status":"Up 2 minutes"},{"Service":"web","State":"exited","Status":"Exited (1) 5 seconds ago"}]`
+	for _, data := range []string{lines, array, ""} {
+		containers, err := parsePS([]byte(data))
+		if err != nil {
+			t.Fatalf("Unable to parse %q: %v", data, err)
+		}
+		if data == "" {
+			if len(containers) != 0 {
+				t.Errorf("Expected no containers, got %+v", containers)
+			}
+			continue
+		}
+		if len(containers) != 2 || containers[1].Service != "web" || containers[1].State != "exited" {
+			t.Errorf("Expected both containers from %q, got %+v", data, containers)
+		}
+	}
+}
+
+func TestServices(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+	dir := t.TempDir()
+	script := `#!/bin/sh
+case "$*" in
+*"config --services"*) printf 'db\nweb\nworker\n' ;;
+*ps*) echo '{"Service":"db","State":"running","Status":"Up 2 minutes"}'
+      echo '{"Service":"worker","State":"exited","Status":"Exited (0)"}'
+      echo '{"Service":"worker","State":"running","Status":"Up 1 second"}' ;;
+esac
+`
+	os.WriteFile(filepath.Join(dir, "docker"), []byte(script), 0o755)
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	services, err := Project{}.Services(context.Background(), task.Environment{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Service{
+		{Name: "db", State: "running", Status: "Up 2 minutes"},
+		{Name: "web"},
+		{Name: "worker", State: "running", Status: "Up 1 second"},
+	}
+	if !slices.Equal(services, want) {
+		t.Errorf("Expected the services with their containers\n got %+v\nwant %+v", services, want)
+	}
+}
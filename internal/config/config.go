@@ -0,0 +1,508 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// appName is the directory name used under the user's config and data directories
+// ProjectFileName is the name of the optional per-project config file in the working directory
+const (
+	appName         = "tash"
+	ProjectFileName = ".tash.json"
+)
+
+// Config holds the user configuration loaded from the config file
+type Config struct {
+	// Debug enables the debug log written to the data directory
+	Debug bool `json:"debug,omitempty"`
+	// MergeOutput runs tasks with stdout and stderr merged into one stream to preserve line ordering
+	MergeOutput bool `json:"merge_output,omitempty"`
+	// WorkDir is the working directory of the tasks and commands tash runs, which also decides the
+	// Taskfile they use; relative paths are relative to where tash was started
+	WorkDir string `json:"work_dir,omitempty"`
+	// Taskfile is the Taskfile task uses instead of the one it finds in WorkDir: a path, or the
+	// URL of a remote Taskfile such as a catalog of tasks shared across an organisation
+	Taskfile string `json:"taskfile,omitempty"`
+	// PathPrefix lists directories put in front of PATH for the commands tash runs
+	PathPrefix []string `json:"path_prefix,omitempty"`
+	// EnvAllow restricts the variables commands inherit from tash's environment to those matching
+	// these glob patterns, e.g. ["HOME", "PATH", "GO*"]; empty inherits every variable
+	EnvAllow []string `json:"env_allow,omitempty"`
+	// EnvDeny drops inherited variables matching these glob patterns, e.g. ["AWS_*"]
+	EnvDeny []string `json:"env_deny,omitempty"`
+	// Timeout is the default timeout applied to every task run; zero disables it
+	Timeout Duration `json:"timeout,omitempty"`
+	// TaskTimeouts overrides Timeout for individual tasks, keyed by task id
+	TaskTimeouts map[string]Duration `json:"task_timeouts,omitempty"`
+	// CancelGracePeriod is how long a cancelled or timed out task has to exit before all of its
+	// processes are killed (default 5s)
+	CancelGracePeriod Duration `json:"cancel_grace_period,omitempty"`
+	// Retries is the default number of times a failing task is retried
+	Retries int `json:"retries,omitempty"`
+	// TaskRetries overrides Retries for individual tasks, keyed by task id
+	TaskRetries map[string]int `json:"task_retries,omitempty"`
+	// LowPriority runs tasks at reduced CPU and IO priority (nice/ionice, or the below normal
+	// priority class on Windows) so long builds don't starve interactive programs
+	LowPriority bool `json:"low_priority,omitempty"`
+	// TaskLowPriority overrides LowPriority for individual tasks, keyed by task id
+	TaskLowPriority map[string]bool `json:"task_low_priority,omitempty"`
+	// TaskVars are variables passed to individual tasks on every run, keyed by task id, e.g.
+	// {"deploy": ["ENV=staging"]}; variables given for a run override them
+	TaskVars map[string][]string `json:"task_vars,omitempty"`
+	// TaskArgs are CLI args passed to individual tasks after "--" on every run, keyed by task id
+	TaskArgs map[string]string `json:"task_args,omitempty"`
+	// RetryBackoff is the delay before the first retry, doubling for each further attempt
+	RetryBackoff Duration `json:"retry_backoff,omitempty"`
+	// RepeatMaxIterations caps the number of runs in run-until-fail mode (default 100)
+	RepeatMaxIterations int `json:"repeat_max_iterations,omitempty"`
+	// Schedules lists tasks that run periodically while tash is open
+	Schedules []ScheduleConfig `json:"schedules,omitempty"`
+	// HostGroups are named groups of hosts a task can be run on at once over ssh, keyed by name
+	HostGroups map[string]HostGroupConfig `json:"host_groups,omitempty"`
+	// Watchers bind tasks to file patterns; matching changes trigger a run
+	Watchers []WatcherConfig `json:"watchers,omitempty"`
+	// Preflight lists what the project's tasks need, checked when tash starts; best kept in the
+	// project's .tash.json
+	Preflight []PreflightCheck `json:"preflight,omitempty"`
+	// WatchEnabled starts the configured watchers when tash opens; they can also be toggled at runtime
+	WatchEnabled bool `json:"watch_enabled,omitempty"`
+	// ContinueOnError keeps executing the remaining tasks of a batch after one fails or times out
+	ContinueOnError bool `json:"continue_on_error,omitempty"`
+	// DisableFailureSummary stops the summary of a failed run opening when the run fails; F
+	// still shows it
+	DisableFailureSummary bool `json:"disable_failure_summary,omitempty"`
+	// DisableCatalogCache stops the task list being cached between starts; with the cache, the
+	// tasks are shown at once when no Taskfile has changed, and refreshed in the background
+	DisableCatalogCache bool `json:"disable_catalog_cache,omitempty"`
+	// OutputMemoryLines caps the output lines held in memory (default 50000); older lines are
+	// moved to a temp file, which the full output view still shows
+	OutputMemoryLines int `json:"output_memory_lines,omitempty"`
+	// StatusPollInterval is how often the tasks around the cursor are checked for being up to
+	// date with "task --status", updating their indicators; zero disables the checks
+	StatusPollInterval Duration `json:"status_poll_interval,omitempty"`
+	// ReadOnly disables running, cancelling and scheduling tasks, running commands and editing
+	// Taskfiles, keeping listing, details and output browsing, e.g. for shared demo machines
+	ReadOnly bool `json:"read_only,omitempty"`
+	// RedactPatterns are regular expressions whose matches in task output are masked, e.g.
+	// "ghp_[A-Za-z0-9]+"; in patterns with capture groups only the groups are masked. The values
+	// of variables named like *TOKEN*, *SECRET* or *PASSWORD* are always masked.
+	RedactPatterns []string `json:"redact_patterns,omitempty"`
+	// PasteURL is the endpoint the output of a run is shared to: the GitHub gists API
+	// ("https://api.github.com/gists"), or a paste service receiving the output as the plain text
+	// body of a POST and answering with the link
+	PasteURL string `json:"paste_url,omitempty"`
+	// PasteTokenEnv names the environment variable holding the token sent to PasteURL as a bearer
+	// token, e.g. "GITHUB_TOKEN"; the token itself is kept out of the config
+	PasteTokenEnv string `json:"paste_token_env,omitempty"`
+	// HeartbeatURL, when set, receives a JSON heartbeat from "tash daemon" with the running and
+	// scheduled tasks, so monitoring notices a daemon that stopped
+	HeartbeatURL string `json:"heartbeat_url,omitempty"`
+	// HeartbeatInterval is how often the heartbeat is sent (default 1m)
+	HeartbeatInterval Duration `json:"heartbeat_interval,omitempty"`
+	// QueueDir is a directory "tash daemon" runs the jobs dropped into as JSON files, e.g.
+	// {"task": "deploy", "vars": ["ENV=prod"]}, moving them to done/ or failed/ with the result;
+	// relative to the project directory
+	QueueDir string `json:"queue_dir,omitempty"`
+	// BridgeURL, when set, connects "tash daemon" to an MQTT or NATS broker, e.g.
+	// "mqtt://localhost:1883" or "nats://localhost:4222": the events of its runs are published to
+	// the broker, and tasks are run and cancelled on request from it. Environment variables in it
+	// are expanded, e.g. "nats://${NATS_TOKEN}@localhost", keeping credentials out of the config.
+	BridgeURL string `json:"bridge_url,omitempty"`
+	// BridgePrefix starts the topics of the bridge (default "tash")
+	BridgePrefix string `json:"bridge_prefix,omitempty"`
+	// DisablePasswordPrompts runs tasks with an empty stdin instead of asking, in a masked input,
+	// for the passwords they prompt for, e.g. with "sudo -S"
+	DisablePasswordPrompts bool `json:"disable_password_prompts,omitempty"`
+	// DangerousTasks are patterns of task ids, e.g. "db:drop" or "*:prod*", whose runs must be
+	// confirmed by typing the task id, to prevent costly misfires
+	DangerousTasks []string `json:"dangerous_tasks,omitempty"`
+	// DisableUpdateCheck stops tash looking up, at most once a day when it starts, whether a newer
+	// release is out
+	DisableUpdateCheck bool `json:"disable_update_check,omitempty"`
+	// DisableGitStatus hides the branch and uncommitted changes of the project's git repository
+	DisableGitStatus bool `json:"disable_git_status,omitempty"`
+	// ChangedFilesVar names a variable passed to every task run with the files changed in the git
+	// working tree, separated by spaces, e.g. "CHANGED_FILES" for a lint task using
+	// {{.CHANGED_FILES}}; empty passes nothing
+	ChangedFilesVar string `json:"changed_files_var,omitempty"`
+	// KeyBindings rebinds actions to other keys, e.g. {"quit": ["ctrl+q"]}
+	KeyBindings map[string][]string `json:"key_bindings,omitempty"`
+	// Leader is the key substituted for "<leader>" in key bindings, e.g. "space" or ","
+	Leader string `json:"leader,omitempty"`
+	// Theme is the name of a built-in color theme: default, high-contrast, deuteranopia or no-color
+	Theme string `json:"theme,omitempty"`
+	// Plain renders a linear layout without borders or colors and announces UI changes as text
+	Plain bool `json:"plain,omitempty"`
+	// TickInterval is how often tash checks for task output, due schedules and watched changes
+	// while no output arrives (default 50ms); longer intervals use less CPU on slow terminals
+	TickInterval Duration `json:"tick_interval,omitempty"`
+	// MaxFPS caps the frames drawn per second (default 60, at most 120); while output arrives it
+	// is read once per frame
+	MaxFPS int `json:"max_fps,omitempty"`
+	// ReducedMotion redraws the screen less often, batching output into a few updates a second,
+	// and keeps counters and countdowns still, for slow connections and screen readers; Q toggles it
+	ReducedMotion bool `json:"reduced_motion,omitempty"`
+	// Inline runs tash below the shell prompt instead of on the alternate screen, keeping the
+	// terminal's scrollback, in a compact layout InlineHeight lines tall (--inline)
+	Inline bool `json:"inline,omitempty"`
+	// InlineHeight is the height of tash in inline mode (default 20 lines)
+	InlineHeight int `json:"inline_height,omitempty"`
+	// Layout arranges the task list and output: auto (default), side-by-side, stacked or single
+	Layout string `json:"layout,omitempty"`
+	// TimeFormat is the layout of the timestamps shown, such as next scheduled runs: a Go time
+	// layout, e.g. "2006-01-02 15:04:05 MST", or one of kitchen, 24h, datetime and rfc3339; by
+	// default each timestamp has its own layout
+	TimeFormat string `json:"time_format,omitempty"`
+	// TimeZone is the IANA time zone timestamps are shown in, e.g. "UTC" or "Europe/Berlin"
+	// (default the local time zone)
+	TimeZone string `json:"time_zone,omitempty"`
+	// Verbosity of task runs at startup: normal (default), verbose or silent
+	Verbosity string `json:"verbosity,omitempty"`
+	// Highlights style task output lines matching a pattern, applied in order as lines arrive
+	Highlights []HighlightConfig `json:"highlights,omitempty"`
+	// ProblemMatchers recognise problems in task output for the Problems list; the built-in
+	// matchers for go, gcc-style and tsc output are used when none are configured
+	ProblemMatchers []ProblemMatcherConfig `json:"problem_matchers,omitempty"`
+	// EnvProfiles are named sets of environment variables offered by the execution options
+	// overlay, e.g. {"staging": {"API_URL": "https://staging.example.com"}}
+	EnvProfiles map[string]map[string]string `json:"env_profiles,omitempty"`
+	// HideTasks hides internal tasks, tasks without a description and tasks matching IgnoreTasks
+	// from the task list; they can be shown at runtime and are still found by the picker
+	HideTasks bool `json:"hide_tasks,omitempty"`
+	// HideLoopVariants stops the runs generated by for loops calling other tasks, such as the
+	// cells of a matrix, being listed below the task defining the loop
+	HideLoopVariants bool `json:"hide_loop_variants,omitempty"`
+	// IgnoreTasks are patterns of task ids hidden by HideTasks, e.g. "internal:*" or "_*"
+	IgnoreTasks []string `json:"ignore_tasks,omitempty"`
+	// GroupByInclude orders the task list by the Taskfile defining each task, the root Taskfile
+	// first and then each included file
+	GroupByInclude bool `json:"group_by_include,omitempty"`
+	// Labels group tasks the way a team thinks of them, mapping each label to patterns of the
+	// task ids it applies to, e.g. {"release": ["release:*", "changelog"], "ops": ["deploy*"]}
+	Labels map[string][]string `json:"labels,omitempty"`
+	// GroupByLabel orders the task list by label, labels in alphabetical order and unlabelled
+	// tasks last
+	GroupByLabel bool `json:"group_by_label,omitempty"`
+	// ExternalTerminal is the command opening a new terminal window to run a task in, e.g.
+	// "alacritty -e"; {cmd} marks where the task command goes, otherwise it is appended
+	ExternalTerminal string `json:"external_terminal,omitempty"`
+	// Provider lists and runs the tasks: "task" (default) uses the task binary, "demo" the
+	// built-in scripted tasks and "kubernetes" runs the tasks listed by the task binary as
+	// Kubernetes Jobs
+	Provider string `json:"provider,omitempty"`
+	// Kubernetes is where the kubernetes provider runs tasks
+	Kubernetes KubernetesConfig `json:"kubernetes,omitempty"`
+	// CI lists the jobs of the project's GitHub Actions workflows and GitLab CI pipeline
+	// alongside its tasks
+	CI CIConfig `json:"ci,omitempty"`
+	// Compose is the compose project whose services U shows
+	Compose ComposeConfig `json:"compose,omitempty"`
+	// Retention limits the run logs and history kept in the data directory, cleaned up when tash
+	// starts and by "tash gc"
+	Retention RetentionConfig `json:"retention,omitempty"`
+}
+
+// RetriesFor returns the number of retries configured for the given task
+func (c Config) RetriesFor(taskId string) int {
+	if n, ok := c.TaskRetries[taskId]; ok {
+		return n
+	}
+	return c.Retries
+}
+
+// LowPriorityFor reports whether the given task runs at low priority
+func (c Config) LowPriorityFor(taskId string) bool {
+	if low, ok := c.TaskLowPriority[taskId]; ok {
+		return low
+	}
+	return c.LowPriority
+}
+
+// LabelsFor returns the labels of the given task, sorted
+func (c Config) LabelsFor(taskId string) []string {
+	var labels []string
+	for label, patterns := range c.Labels {
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, taskId); ok {
+				labels = append(labels, label)
+				break
+			}
+		}
+	}
+	slices.Sort(labels)
+	return labels
+}
+
+// IsDangerous reports whether runs of the given task must be confirmed by typing its id
+func (c Config) IsDangerous(taskId string) bool {
+	for _, pattern := range c.DangerousTasks {
+		if ok, _ := path.Match(pattern, taskId); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// TimeoutFor returns the timeout configured for the given task
+func (c Config) TimeoutFor(taskId string) time.Duration {
+	if d, ok := c.TaskTimeouts[taskId]; ok {
+		return time.Duration(d)
+	}
+	return time.Duration(c.Timeout)
+}
+
+// timeFormats are the named layouts of TimeFormat
+var timeFormats = map[string]string{
+	"kitchen":  time.Kitchen,
+	"24h":      "15:04:05",
+	"datetime": time.DateTime,
+	"rfc3339":  time.RFC3339,
+}
+
+// TimeLayout returns the Go layout of TimeFormat, empty when each timestamp keeps its own
+func (c Config) TimeLayout() string {
+	if layout, ok := timeFormats[c.TimeFormat]; ok {
+		return layout
+	}
+	return c.TimeFormat
+}
+
+// Location returns the time zone timestamps are shown in. An unknown zone is reported along
+// with the local time zone, used instead.
+func (c Config) Location() (*time.Location, error) {
+	if c.TimeZone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(c.TimeZone)
+	if err != nil {
+		return time.Local, fmt.Errorf("unknown time zone %q in the config, using the local time zone", c.TimeZone)
+	}
+	return loc, nil
+}
+
+// HighlightConfig styles task output matching a regular expression
+type HighlightConfig struct {
+	// Pattern is a regular expression such as "WARN" or "(?i)fail(ed|ure)?"
+	Pattern string `json:"pattern"`
+	// Color is the foreground: a name such as "yellow", an ANSI number such as "214" or a hex
+	// color such as "#ff8800"
+	Color string `json:"color,omitempty"`
+	// Background is the background color, in the same forms as Color
+	Background string `json:"background,omitempty"`
+	Bold       bool   `json:"bold,omitempty"`
+	// Match styles only the matching text instead of the whole line
+	Match bool `json:"match,omitempty"`
+}
+
+// ProblemMatcherConfig recognises problems reported in task output
+type ProblemMatcherConfig struct {
+	// Name identifies the matcher in the Problems list
+	Name string `json:"name"`
+	// Pattern is a regular expression with the named groups file (required), line, column,
+	// severity and message, e.g. "^(?P<file>\\S+\\.py):(?P<line>\\d+): (?P<message>.*)$"
+	Pattern string `json:"pattern"`
+}
+
+// ScheduleConfig schedules a task to run repeatedly
+type ScheduleConfig struct {
+	// Task is the id of the task to run
+	Task string `json:"task"`
+	// Schedule is an interval such as "10m" or a five-field cron expression such as "*/10 * * * *"
+	Schedule string `json:"schedule"`
+}
+
+// KubernetesConfig is the cluster, namespace and image the kubernetes provider runs tasks in
+type KubernetesConfig struct {
+	// Context is the kubectl context of the cluster (default the current context)
+	Context string `json:"context,omitempty"`
+	// Namespace is the namespace of the jobs (default the context's namespace)
+	Namespace string `json:"namespace,omitempty"`
+	// Image is the container image with the task binary and the project's Taskfile
+	Image string `json:"image,omitempty"`
+	// Dir is the directory of the project in the image (default the image's working directory)
+	Dir string `json:"dir,omitempty"`
+}
+
+// RetentionConfig limits the run logs and history kept; a negative limit keeps everything
+type RetentionConfig struct {
+	// MaxAge removes the runs started longer ago (default 720h)
+	MaxAge Duration `json:"max_age,omitempty"`
+	// MaxSizeMB is the total size of the run logs kept in megabytes, removing the oldest runs
+	// first (default 500)
+	MaxSizeMB int `json:"max_size_mb,omitempty"`
+	// MaxRunsPerTask is the number of the latest runs of each task kept (default 100)
+	MaxRunsPerTask int `json:"max_runs_per_task,omitempty"`
+}
+
+// CIConfig lists the jobs of the project's CI pipelines, and runs them locally
+type CIConfig struct {
+	// List adds the jobs of .github/workflows and .gitlab-ci.yml to the task list, in the "ci"
+	// namespace
+	List bool `json:"list,omitempty"`
+	// Run allows running the listed jobs locally; otherwise they are only shown
+	Run bool `json:"run,omitempty"`
+	// Act is the command running GitHub Actions jobs (default "act")
+	Act string `json:"act,omitempty"`
+	// Container is the container engine running GitLab CI jobs in their image (default "docker")
+	Container string `json:"container,omitempty"`
+}
+
+// ComposeConfig is the compose project managed from tash
+type ComposeConfig struct {
+	// Command runs compose (default "docker compose"), e.g. "podman compose"
+	Command string `json:"command,omitempty"`
+	// File is the compose file (default the compose.yaml or docker-compose.yml of the project)
+	File string `json:"file,omitempty"`
+}
+
+// HostGroupConfig is a group of hosts tasks run on over ssh
+type HostGroupConfig struct {
+	// Hosts are ssh destinations such as "web1" or "deploy@10.0.0.5", using the ssh config for
+	// users, keys and jump hosts
+	Hosts []string `json:"hosts"`
+	// Dir is the directory of the project on the hosts (default the home directory)
+	Dir string `json:"dir,omitempty"`
+}
+
+// WatcherConfig runs a task when files matching its patterns change
+type WatcherConfig struct {
+	// Task is the id of the task to run
+	Task string `json:"task"`
+	// Patterns are globs relative to the project directory; "**" matches any number of directories
+	Patterns []string `json:"patterns"`
+	// Debounce is how long changes must settle before the task runs (default 300ms)
+	Debounce Duration `json:"debounce,omitempty"`
+}
+
+// PreflightCheck is a pre-flight check: a binary that must be on the PATH, optionally in a
+// minimum version, or an environment variable that must be set
+type PreflightCheck struct {
+	// Name describes the check; by default it is derived from what is checked
+	Name string `json:"name,omitempty"`
+	// Binary must be found on the PATH
+	Binary string `json:"binary,omitempty"`
+	// MinVersion is the lowest version of Binary that will do, e.g. "1.22"
+	MinVersion string `json:"min_version,omitempty"`
+	// VersionArgs make Binary print its version (default --version)
+	VersionArgs []string `json:"version_args,omitempty"`
+	// Env must be set to a value that isn't empty
+	Env string `json:"env,omitempty"`
+	// Tasks are patterns of the ids of the tasks depending on the check, e.g. "deploy:*"; empty
+	// means every task
+	Tasks []string `json:"tasks,omitempty"`
+}
+
+// Default returns the default configuration
+func Default() Config {
+	return Config{
+		RepeatMaxIterations: 100,
+	}
+}
+
+// Path returns the location of the user config file
+func Path() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, appName, "config.json"), nil
+}
+
+// DataDir returns the directory tash uses for logs and other persistent data.
+// It honours $XDG_DATA_HOME and falls back to ~/.local/share/tash.
+func DataDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, appName), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to locate data directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "share", appName), nil
+}
+
+// Load reads the config file at path. A missing file is not an error and yields the default configuration.
+func Load(path string) (Config, error) {
+	cfg := Default()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("unable to read config file: %w", err)
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("unable to parse config file %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// LoadDefault loads the config from the default config path
+func LoadDefault() (Config, error) {
+	path, err := Path()
+	if err != nil {
+		return Default(), err
+	}
+	return Load(path)
+}
+
+// LoadProject overlays the project config file in dir, if present, onto cfg. Only the settings
+// present in the project file are changed; lists are replaced and maps are merged.
+func LoadProject(cfg Config, dir string) (Config, error) {
+	path := filepath.Join(dir, ProjectFileName)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("unable to read project config file: %w", err)
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("unable to parse project config file %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// SaveKeyBindings writes the key binding overrides to the config file at path, keeping
+// every other setting in the file as it is. The file is created if it doesn't exist.
+func SaveKeyBindings(path string, bindings map[string][]string) error {
+	settings := map[string]json.RawMessage{}
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("unable to read config file: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return fmt.Errorf("unable to parse config file %s: %w", path, err)
+		}
+	}
+
+	if len(bindings) == 0 {
+		delete(settings, "key_bindings")
+	} else {
+		raw, err := json.Marshal(bindings)
+		if err != nil {
+			return fmt.Errorf("unable to encode key bindings: %w", err)
+		}
+		settings["key_bindings"] = raw
+	}
+
+	data, err = json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to encode config file: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("unable to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("unable to write config file: %w", err)
+	}
+	return nil
+}
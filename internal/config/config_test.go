@@ -0,0 +1,121 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestLoadMissingFileReturnsDefault(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "config.json"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Debug {
+		t.Error("Expected debug to be disabled by default")
+	}
+}
+
+func TestLoadTimeouts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := `{"timeout": "10m", "task_timeouts": {"test:integration": "1h"}}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := cfg.TimeoutFor("build"); got != 10*time.Minute {
+		t.Errorf("Expected global timeout of 10m, got %s", got)
+	}
+	if got := cfg.TimeoutFor("test:integration"); got != time.Hour {
+		t.Errorf("Expected task timeout of 1h, got %s", got)
+	}
+}
+
+func TestTimeFormat(t *testing.T) {
+	cfg := Config{TimeFormat: "24h", TimeZone: "UTC"}
+	if got := cfg.TimeLayout(); got != "15:04:05" {
+		t.Errorf("Expected the named layout, got %q", got)
+	}
+	if loc, err := cfg.Location(); err != nil || loc != time.UTC {
+		t.Errorf("Expected UTC, got %v, %v", loc, err)
+	}
+	cfg = Config{TimeFormat: "Jan 2 15:04", TimeZone: "Nowhere/Atlantis"}
+	if got := cfg.TimeLayout(); got != "Jan 2 15:04" {
+		t.Errorf("Expected the layout as written, got %q", got)
+	}
+	if loc, err := cfg.Location(); err == nil || loc != time.Local {
+		t.Errorf("Expected an error and the local time zone, got %v, %v", loc, err)
+	}
+}
+
+func TestLowPriorityFor(t *testing.T) {
+	cfg := Config{LowPriority: true, TaskLowPriority: map[string]bool{"serve": false, "lint": true}}
+	if !cfg.LowPriorityFor("build") || cfg.LowPriorityFor("serve") {
+		t.Error("Expected build to run at low priority and serve not to")
+	}
+	cfg.LowPriority = false
+	if cfg.LowPriorityFor("build") || !cfg.LowPriorityFor("lint") {
+		t.Error("Expected only lint to run at low priority")
+	}
+}
+
+func TestLabelsFor(t *testing.T) {
+	cfg := Config{Labels: map[string][]string{
+		"release": {"release:*", "changelog"},
+		"ops":     {"deploy*", "release:prod"},
+	}}
+	if got := cfg.LabelsFor("release:prod"); !slices.Equal(got, []string{"ops", "release"}) {
+		t.Errorf("Expected both labels, sorted, got %v", got)
+	}
+	if got := cfg.LabelsFor("build"); len(got) != 0 {
+		t.Errorf("Expected no labels, got %v", got)
+	}
+}
+
+func TestLoadProjectOverlaysUserConfig(t *testing.T) {
+	dir := t.TempDir()
+	data := `{"watch_enabled": true, "task_timeouts": {"deploy": "5m"}}`
+	if err := os.WriteFile(filepath.Join(dir, ProjectFileName), []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	user := Default()
+	user.Debug = true
+	user.TaskTimeouts = map[string]Duration{"test": Duration(time.Minute)}
+
+	cfg, err := LoadProject(user, dir)
+	if err != nil {
+		t.Fatalf("LoadProject() error = %v", err)
+	}
+	if !cfg.Debug || !cfg.WatchEnabled {
+		t.Errorf("Expected user and project settings to be combined, got %+v", cfg)
+	}
+	if cfg.TimeoutFor("test") != time.Minute || cfg.TimeoutFor("deploy") != 5*time.Minute {
+		t.Errorf("Expected task timeouts to be merged, got %v", cfg.TaskTimeouts)
+	}
+}
+
+func TestSaveKeyBindingsKeepsOtherSettings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"timeout": "10m"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveKeyBindings(path, map[string][]string{"quit": {"ctrl+q"}}); err != nil {
+		t.Fatalf("SaveKeyBindings() error = %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := cfg.TimeoutFor("build"); got != 10*time.Minute {
+		t.Errorf("Expected the timeout to be kept, got %s", got)
+	}
+	if keys := cfg.KeyBindings["quit"]; len(keys) != 1 || keys[0] != "ctrl+q" {
+		t.Errorf("Expected quit to be bound to ctrl+q, got %v", keys)
+	}
+}
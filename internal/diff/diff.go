@@ -0,0 +1,93 @@
+package diff
+
+// Kind identifies whether a line is unchanged, added or removed
+type Kind int
+
+const (
+	Equal Kind = iota
+	Added
+	Removed
+)
+
+// Line is a single line of a line-based diff
+type Line struct {
+	Kind Kind
+	Text string
+}
+
+// MaxCells bounds the size of the LCS table. Larger inputs fall back to reporting
+// every old line as removed and every new line as added.
+const MaxCells = 4_000_000
+
+// Lines computes a line-based diff turning a into b, using the longest common subsequence
+func Lines(a, b []string) []Line {
+	// trim the common prefix and suffix, which is typically most of two runs' output
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var result []Line
+	for _, l := range a[:prefix] {
+		result = append(result, Line{Kind: Equal, Text: l})
+	}
+	result = append(result, lcsDiff(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, l := range a[len(a)-suffix:] {
+		result = append(result, Line{Kind: Equal, Text: l})
+	}
+	return result
+}
+
+func lcsDiff(a, b []string) []Line {
+	var result []Line
+	if (len(a)+1)*(len(b)+1) > MaxCells {
+		for _, l := range a {
+			result = append(result, Line{Kind: Removed, Text: l})
+		}
+		for _, l := range b {
+			result = append(result, Line{Kind: Added, Text: l})
+		}
+		return result
+	}
+
+	// lcs[i][j] is the length of the LCS of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			result = append(result, Line{Kind: Equal, Text: a[i]})
+			i, j = i+1, j+1
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			result = append(result, Line{Kind: Removed, Text: a[i]})
+			i++
+		default:
+			result = append(result, Line{Kind: Added, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		result = append(result, Line{Kind: Removed, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		result = append(result, Line{Kind: Added, Text: b[j]})
+	}
+	return result
+}
@@ -0,0 +1,33 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	a := []string{"start", "PASS a", "FAIL b", "PASS c", "done"}
+	b := []string{"start", "PASS a", "PASS b", "PASS c", "PASS d", "done"}
+
+	expected := []Line{
+		{Equal, "start"},
+		{Equal, "PASS a"},
+		{Removed, "FAIL b"},
+		{Added, "PASS b"},
+		{Equal, "PASS c"},
+		{Added, "PASS d"},
+		{Equal, "done"},
+	}
+	if got := Lines(a, b); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestLinesIdentical(t *testing.T) {
+	a := []string{"one", "two"}
+	for _, l := range Lines(a, a) {
+		if l.Kind != Equal {
+			t.Errorf("Expected identical inputs to produce only equal lines, got %v", l)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Package fanout runs a task on every host of a group at once over ssh, reporting the output
+// and outcome of each host as it comes, a lightweight ansible for Taskfiles.
+package fanout
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Aj4x/tash/internal/msgbus"
+	"github.com/Aj4x/tash/internal/task"
+)
+
+// Update is a line of output of a host, or the end of its run when Finished is set
+type Update struct {
+	Host     string
+	Line     string
+	Stderr   bool
+	Finished bool
+	Err      error // Why the run of the host failed, when Finished
+}
+
+// Run is a task running on a group of hosts
+type Run struct {
+	updates chan Update
+	cancel  context.CancelFunc
+}
+
+// Start runs taskId on each of hosts with opts, whose Host is set to each host in turn
+func Start(taskId string, hosts []string, opts task.ExecOptions) *Run {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &Run{updates: make(chan Update, 256), cancel: cancel}
+	var wg sync.WaitGroup
+	for _, host := range hosts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p := &hostPublisher{host: host, updates: r.updates}
+			stop := context.AfterFunc(ctx, p.cancel)
+			defer stop()
+			hostOpts := opts
+			hostOpts.Host = host
+			task.ExecuteTaskWithOptions(taskId, hostOpts, p)
+			r.updates <- Update{Host: host, Finished: true, Err: p.err}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		cancel()
+		close(r.updates)
+	}()
+	return r
+}
+
+// Updates returns the updates of the hosts, closed once every host has finished
+func (r *Run) Updates() <-chan Update {
+	return r.updates
+}
+
+// Cancel cancels the runs of the hosts still running
+func (r *Run) Cancel() {
+	r.cancel()
+}
+
+// hostPublisher receives the messages of the run of a host
+type hostPublisher struct {
+	host       string
+	updates    chan<- Update
+	mu         sync.Mutex
+	cancelFunc context.CancelFunc
+	cancelled  bool
+	err        error
+}
+
+func (p *hostPublisher) Publish(msg msgbus.TopicMessage[task.Message]) {
+	m := msg.Message
+	switch m.Type {
+	case task.TypeTaskOutput, task.TypeTaskOutputErr:
+		p.updates <- Update{Host: p.host, Line: m.Output(), Stderr: m.Type == task.TypeTaskOutputErr}
+	case task.TypeTaskCommand:
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if cancel := m.CancelFunc(); cancel != nil && m.TaskRunning() {
+			p.cancelFunc = cancel
+			if p.cancelled {
+				cancel()
+			}
+		}
+	case task.TypeTaskError:
+		p.err = m.Error()
+	}
+}
+
+// cancel stops the run, or marks it to be stopped as soon as it has started
+func (p *hostPublisher) cancel() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cancelled = true
+	if p.cancelFunc != nil {
+		p.cancelFunc()
+	}
+}
@@ -0,0 +1,37 @@
+package fanout
+
+import (
+	"testing"
+
+	"github.com/Aj4x/tash/internal/task"
+)
+
+func TestStartRunsOnEveryHost(t *testing.T) {
+	r := Start("lint", []string{"web1", "web2"}, task.ExecOptions{Provider: task.ProviderDemo})
+	lines, finished := map[string]int{}, map[string]error{}
+	for u := range r.Updates() {
+		if u.Finished {
+			finished[u.Host] = u.Err
+		} else {
+			lines[u.Host]++
+		}
+	}
+	for _, host := range []string{"web1", "web2"} {
+		if err, ok := finished[host]; !ok || err == nil {
+			t.Errorf("Expected the failing task to finish with an error on %s, got %v", host, err)
+		}
+		if lines[host] == 0 {
+			t.Errorf("Expected the output of %s", host)
+		}
+	}
+}
+
+func TestCancelStopsEveryHost(t *testing.T) {
+	r := Start("build", []string{"web1", "web2"}, task.ExecOptions{Provider: task.ProviderDemo})
+	r.Cancel()
+	for u := range r.Updates() {
+		if u.Finished && u.Err == nil {
+			t.Errorf("Expected the run of %s to be cancelled", u.Host)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// Package frecency remembers, per project, the tasks chosen in the picker, so the ones used most
+// often and most recently can be offered first. Like the namespace choices, the picks are kept
+// in the data directory rather than the project, as they are personal.
+package frecency
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// DirName is the name of the directory inside the data directory holding the picks
+const DirName = "picks"
+
+// Pick is how often and when last a task was chosen
+type Pick struct {
+	Count int       `json:"count"`
+	Last  time.Time `json:"last"`
+}
+
+// Picks are the picks of a project by task id
+type Picks map[string]Pick
+
+// Record counts a pick of the task id at now
+func (p Picks) Record(id string, now time.Time) {
+	pick := p[id]
+	pick.Count++
+	pick.Last = now
+	p[id] = pick
+}
+
+// Score returns the frecency of the task id at now: its number of picks, weighted by how
+// recent the last one is. Tasks never picked score 0.
+func (p Picks) Score(id string, now time.Time) float64 {
+	pick, ok := p[id]
+	if !ok {
+		return 0
+	}
+	age := now.Sub(pick.Last)
+	weight := 0.25
+	switch {
+	case age < time.Hour:
+		weight = 4
+	case age < 24*time.Hour:
+		weight = 2
+	case age < 7*24*time.Hour:
+		weight = 0.5
+	}
+	return float64(pick.Count) * weight
+}
+
+// Sort orders items by the frecency of their ids at now, highest first; items of equal
+// frecency, such as those never picked, keep their order
+func Sort[T any](p Picks, items []T, id func(T) string, now time.Time) []T {
+	sorted := slices.Clone(items)
+	slices.SortStableFunc(sorted, func(a, b T) int {
+		return cmp.Compare(p.Score(id(b), now), p.Score(id(a), now))
+	})
+	return sorted
+}
+
+// Store keeps the picks of each project. A Store with an empty directory is disabled.
+type Store struct {
+	Dir string
+}
+
+// NewStore returns a store keeping its picks in the data directory dataDir
+func NewStore(dataDir string) Store {
+	return Store{Dir: filepath.Join(dataDir, DirName)}
+}
+
+// entry is the stored picks of a project
+type entry struct {
+	Dir   string `json:"dir"`
+	Picks Picks  `json:"picks"`
+}
+
+// path returns the file keeping the picks of the project in dir
+func (s Store) path(dir string) (string, string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", "", err
+	}
+	sum := sha256.Sum256([]byte(abs))
+	return filepath.Join(s.Dir, hex.EncodeToString(sum[:8])+".json"), abs, nil
+}
+
+// Load returns the picks of the project in dir. A project without picks has an empty set.
+func (s Store) Load(dir string) (Picks, error) {
+	if s.Dir == "" {
+		return Picks{}, nil
+	}
+	path, abs, err := s.path(dir)
+	if err != nil {
+		return Picks{}, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Picks{}, nil
+	}
+	if err != nil {
+		return Picks{}, fmt.Errorf("unable to read the picker history: %w", err)
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return Picks{}, fmt.Errorf("unable to parse the picker history: %w", err)
+	}
+	if e.Dir != abs || e.Picks == nil {
+		return Picks{}, nil
+	}
+	return e.Picks, nil
+}
+
+// Save stores the picks of the project in dir
+func (s Store) Save(dir string, p Picks) error {
+	if s.Dir == "" {
+		return nil
+	}
+	path, abs, err := s.path(dir)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(entry{Dir: abs, Picks: p}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return fmt.Errorf("unable to create the picks directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("unable to save the picker history: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package frecency
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSortOrdersByFrequencyAndRecency(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	p := Picks{}
+	// Picked often, but a month ago
+	for range 5 {
+		p.Record("lint", now.Add(-30*24*time.Hour))
+	}
+	// Picked three times this morning
+	p.Record("test", now.Add(-4*time.Hour))
+	p.Record("test", now.Add(-3*time.Hour))
+	p.Record("test", now.Add(-2*time.Hour))
+	// Picked once, just now
+	p.Record("build", now.Add(-time.Minute))
+
+	ids := []string{"deploy", "lint", "build", "test", "docs"}
+	got := Sort(p, ids, func(id string) string { return id }, now)
+	if want := []string{"test", "build", "lint", "deploy", "docs"}; !slices.Equal(got, want) {
+		t.Errorf("Sort() = %v, want %v", got, want)
+	}
+	if p["test"].Count != 3 || !p["test"].Last.Equal(now.Add(-2*time.Hour)) {
+		t.Errorf("Expected the count and time of the last pick, got %+v", p["test"])
+	}
+}
+
+func TestStoreKeepsThePicksOfEachProject(t *testing.T) {
+	store := NewStore(t.TempDir())
+	project := t.TempDir()
+	now := time.Now().Truncate(time.Second)
+
+	p := Picks{}
+	p.Record("build", now)
+	if err := store.Save(project, p); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	loaded, err := store.Load(project)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if pick := loaded["build"]; pick.Count != 1 || !pick.Last.Equal(now) {
+		t.Errorf("Expected the saved pick, got %+v", loaded)
+	}
+	if other, err := store.Load(t.TempDir()); err != nil || len(other) != 0 {
+		t.Errorf("Expected no picks for another project, got %+v, %v", other, err)
+	}
+}
@@ -0,0 +1,122 @@
+// Package git reads the state of the git repository tasks run in
+package git
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"path"
+	"strings"
+	"time"
+)
+
+// timeout bounds the git commands, which can be slow in very large repositories
+const timeout = 2 * time.Second
+
+// Status is the state of a working tree
+type Status struct {
+	// Branch is the checked out branch, or "HEAD" when it is detached
+	Branch string
+	// Changed lists the modified, added, renamed and untracked files, relative to the directory
+	// the status was read in. Deleted files aren't listed.
+	Changed []string
+	// Dirty is set when the working tree or index has any change, deletions included
+	Dirty bool
+}
+
+// Read returns the status of the repository containing dir. It fails when git isn't installed
+// or dir isn't inside a repository.
+func Read(ctx context.Context, dir string) (Status, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	prefix, err := run(ctx, dir, "rev-parse", "--show-prefix")
+	if err != nil {
+		return Status{}, err
+	}
+	out, err := run(ctx, dir, "status", "--porcelain=v1", "--branch", "-z")
+	if err != nil {
+		return Status{}, err
+	}
+	return parseStatus(out, strings.TrimSpace(string(prefix))), nil
+}
+
+// run runs git in dir and returns its output
+func run(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	return cmd.Output()
+}
+
+// parseStatus parses the output of "git status --porcelain=v1 --branch -z", whose paths are
+// relative to the repository root, making them relative to the directory at prefix
+func parseStatus(out []byte, prefix string) Status {
+	var s Status
+	entries := bytes.Split(out, []byte{0})
+	for i := 0; i < len(entries); i++ {
+		entry := string(entries[i])
+		if header, ok := strings.CutPrefix(entry, "## "); ok {
+			s.Branch = branchOf(header)
+			continue
+		}
+		if len(entry) < 4 {
+			continue
+		}
+		code, file := entry[:2], entry[3:]
+		if code[0] == 'R' || code[0] == 'C' {
+			// the original path of a rename or copy follows as its own entry
+			i++
+		}
+		s.Dirty = true
+		if strings.Contains(code, "D") {
+			continue
+		}
+		s.Changed = append(s.Changed, relative(prefix, file))
+	}
+	return s
+}
+
+// branchOf returns the branch named in the branch header of the porcelain status, such as
+// "main...origin/main [ahead 1]" or "No commits yet on main"
+func branchOf(header string) string {
+	header = strings.TrimPrefix(header, "No commits yet on ")
+	if strings.HasPrefix(header, "HEAD (no branch)") {
+		return "HEAD"
+	}
+	branch, _, _ := strings.Cut(header, "...")
+	branch, _, _ = strings.Cut(branch, " ")
+	return branch
+}
+
+// relative makes file, relative to the repository root, relative to the directory at prefix
+func relative(prefix, file string) string {
+	dir := strings.Split(strings.TrimSuffix(prefix, "/"), "/")
+	if prefix == "" {
+		dir = nil
+	}
+	parts := strings.Split(file, "/")
+	common := 0
+	for common < len(dir) && common < len(parts)-1 && dir[common] == parts[common] {
+		common++
+	}
+	up := strings.Repeat("../", len(dir)-common)
+	return up + path.Join(parts[common:]...)
+}
+
+// IgnoredDirs returns the directories below dir that git ignores, e.g. build output, relative to
+// dir with slashes. It fails when git isn't installed or dir isn't inside a repository.
+func IgnoredDirs(ctx context.Context, dir string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	// with --directory, an ignored directory is listed once rather than file by file
+	out, err := run(ctx, dir, "ls-files", "--others", "--ignored", "--exclude-standard", "--directory", "-z")
+	if err != nil {
+		return nil, err
+	}
+	var dirs []string
+	for _, entry := range strings.Split(string(out), "\x00") {
+		if d, ok := strings.CutSuffix(entry, "/"); ok {
+			dirs = append(dirs, d)
+		}
+	}
+	return dirs, nil
+}
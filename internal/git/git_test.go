@@ -0,0 +1,90 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestParseStatus(t *testing.T) {
+	out := "## main...origin/main [ahead 1]\x00 M cmd/main.go\x00R  new.go\x00old.go\x00 D gone.go\x00?? notes.txt\x00"
+	s := parseStatus([]byte(out), "")
+	if s.Branch != "main" || !s.Dirty {
+		t.Errorf("Expected a dirty main branch, got %+v", s)
+	}
+	want := []string{"cmd/main.go", "new.go", "notes.txt"}
+	if !slices.Equal(s.Changed, want) {
+		t.Errorf("Expected %v, got %v", want, s.Changed)
+	}
+
+	if s := parseStatus([]byte("## No commits yet on trunk\x00"), ""); s.Branch != "trunk" || s.Dirty {
+		t.Errorf("Expected a clean trunk branch, got %+v", s)
+	}
+	if s := parseStatus([]byte("## HEAD (no branch)\x00"), ""); s.Branch != "HEAD" {
+		t.Errorf("Expected a detached HEAD, got %+v", s)
+	}
+}
+
+func TestChangedFilesAreRelativeToTheDirectory(t *testing.T) {
+	tests := []struct {
+		prefix, file, want string
+	}{
+		{"", "a/b.go", "a/b.go"},
+		{"a/", "a/b.go", "b.go"},
+		{"a/b/", "a/c.go", "../c.go"},
+		{"a/", "README.md", "../README.md"},
+	}
+	for _, tt := range tests {
+		if got := relative(tt.prefix, tt.file); got != tt.want {
+			t.Errorf("relative(%q, %q): expected %q, got %q", tt.prefix, tt.file, tt.want, got)
+		}
+	}
+}
+
+func TestRead(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	if err := exec.Command("git", "init", "-q", "-b", "work", dir).Run(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Read(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if s.Branch != "work" || !s.Dirty || !slices.Equal(s.Changed, []string{"main.go"}) {
+		t.Errorf("Expected main.go to be changed on work, got %+v", s)
+	}
+
+	if _, err := Read(context.Background(), t.TempDir()); err == nil {
+		t.Error("Expected an error outside a repository")
+	}
+}
+
+func TestIgnoredDirs(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	if err := exec.Command("git", "init", "-q", dir).Run(); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("dist/\n*.log\n"), 0o644)
+	os.MkdirAll(filepath.Join(dir, "dist", "assets"), 0o755)
+	os.WriteFile(filepath.Join(dir, "dist", "assets", "app.js"), nil, 0o644)
+	os.WriteFile(filepath.Join(dir, "debug.log"), nil, 0o644)
+	dirs, err := IgnoredDirs(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("IgnoredDirs() error = %v", err)
+	}
+	if !slices.Equal(dirs, []string{"dist"}) {
+		t.Errorf("Expected only dist to be ignored, got %v", dirs)
+	}
+}
@@ -0,0 +1,75 @@
+// Package heartbeat reports that a tash daemon is alive, along with what it is running and has
+// scheduled, to a monitoring endpoint, so a dead daemon is noticed when the heartbeats stop.
+package heartbeat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Aj4x/tash/internal/history"
+)
+
+// DefaultInterval is how often a heartbeat is sent when the config doesn't say otherwise
+const DefaultInterval = time.Minute
+
+// requestTimeout bounds a heartbeat, which must not pile up behind a slow endpoint
+const requestTimeout = 10 * time.Second
+
+// Status is the body of a heartbeat
+type Status struct {
+	Time time.Time `json:"time"`
+	Pid  int       `json:"pid"`
+	Dir  string    `json:"dir"`
+	// Running is the task being run, if any
+	Running *Run `json:"running,omitempty"`
+	// Scheduled lists the scheduled tasks with their next run
+	Scheduled []Scheduled `json:"scheduled"`
+	// Watching is set while the watchers run tasks on file changes
+	Watching bool `json:"watching"`
+	// LastRun is the last finished run, if any
+	LastRun *history.Record `json:"last_run,omitempty"`
+}
+
+// Run is a task being run
+type Run struct {
+	Task    string    `json:"task"`
+	Started time.Time `json:"started"`
+}
+
+// Scheduled is a task run on a schedule
+type Scheduled struct {
+	Task     string    `json:"task"`
+	Schedule string    `json:"schedule"`
+	NextRun  time.Time `json:"next_run"`
+}
+
+// Send posts status as JSON to url; any 2xx answer accepts it
+func Send(ctx context.Context, client *http.Client, url string, status Status) error {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	body, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to send the heartbeat: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("heartbeat refused: %s", resp.Status)
+	}
+	return nil
+}
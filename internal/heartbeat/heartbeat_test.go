@@ -0,0 +1,38 @@
+package heartbeat
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSend(t *testing.T) {
+	var got Status
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil || r.Method != http.MethodPost {
+			http.Error(w, "bad heartbeat", http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	status := Status{
+		Time:      now,
+		Pid:       42,
+		Running:   &Run{Task: "backup", Started: now.Add(-time.Minute)},
+		Scheduled: []Scheduled{{Task: "backup", Schedule: "every 1h0m0s", NextRun: now.Add(time.Hour)}},
+	}
+	if err := Send(context.Background(), server.Client(), server.URL, status); err != nil {
+		t.Fatal(err)
+	}
+	if got.Pid != 42 || got.Running == nil || got.Running.Task != "backup" || len(got.Scheduled) != 1 || !got.Scheduled[0].NextRun.Equal(now.Add(time.Hour)) {
+		t.Errorf("Expected the status to be posted, got %+v", got)
+	}
+
+	if err := Send(context.Background(), server.Client(), server.URL+"/missing\x7f", status); err == nil {
+		t.Error("Expected an invalid URL to be reported")
+	}
+}
@@ -0,0 +1,152 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileName is the name of the run history file inside the data directory
+const FileName = "history.jsonl"
+
+// Record describes a single finished task run
+type Record struct {
+	Task    string    `json:"task"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Success bool      `json:"success"`
+	Error   string    `json:"error,omitempty"`
+	// Log is the output of the run, next to its metadata file, when it was logged
+	Log string `json:"log,omitempty"`
+}
+
+// Duration returns how long the run took
+func (r Record) Duration() time.Duration {
+	return r.End.Sub(r.Start)
+}
+
+// Store persists run records as JSON lines in a file. A Store with an empty path is disabled.
+type Store struct {
+	Path string
+}
+
+// NewStore returns a store writing to the history file in dir
+func NewStore(dir string) Store {
+	return Store{Path: filepath.Join(dir, FileName)}
+}
+
+// Append adds a record to the history file, creating it if needed
+func (s Store) Append(r Record) error {
+	if s.Path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o755); err != nil {
+		return fmt.Errorf("unable to create history directory: %w", err)
+	}
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	f, err := os.OpenFile(s.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("unable to open history file: %w", err)
+	}
+	defer f.Close()
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// Load reads all records from the history file, skipping malformed lines. A missing file yields no records.
+func (s Store) Load() ([]Record, error) {
+	if s.Path == "" {
+		return nil, nil
+	}
+	f, err := os.Open(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to open history file: %w", err)
+	}
+	defer f.Close()
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			continue
+		}
+		records = append(records, r)
+	}
+	return records, scanner.Err()
+}
+
+// Prune rewrites the history file with the records keep returns out of the records of the file,
+// in the same order, returning the number of records removed
+func (s Store) Prune(keep func([]Record) []Record) (int, error) {
+	if s.Path == "" {
+		return 0, nil
+	}
+	if _, err := os.Stat(s.Path); errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	// the file is loaded and replaced under the lock, so no record appended meanwhile is lost
+	unlock, err := s.lock()
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+	records, err := s.Load()
+	if err != nil || len(records) == 0 {
+		return 0, err
+	}
+	kept := keep(records)
+	if len(kept) == len(records) {
+		return 0, nil
+	}
+	var data []byte
+	for _, r := range kept {
+		line, err := json.Marshal(r)
+		if err != nil {
+			return 0, err
+		}
+		data = append(append(data, line...), '\n')
+	}
+	// the file is replaced whole, so a reader never sees it half written
+	tmp := s.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return 0, fmt.Errorf("unable to write history file: %w", err)
+	}
+	if err := os.Rename(tmp, s.Path); err != nil {
+		os.Remove(tmp)
+		return 0, fmt.Errorf("unable to write history file: %w", err)
+	}
+	return len(records) - len(kept), nil
+}
+
+// lock waits for the other writers of the history file, in this and other processes, and returns
+// the function letting them write again. The lock is taken on a file next to the history, as the
+// history file itself is replaced when pruned.
+func (s Store) lock() (func(), error) {
+	f, err := os.OpenFile(s.Path+".lock", os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("unable to lock history file: %w", err)
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("unable to lock history file: %w", err)
+	}
+	return func() {
+		unlockFile(f)
+		f.Close()
+	}, nil
+}
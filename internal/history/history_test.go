@@ -0,0 +1,78 @@
+package history
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStoreAppendAndLoad(t *testing.T) {
+	store := NewStore(t.TempDir())
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i, success := range []bool{true, false, true} {
+		r := Record{Task: "build", Start: start, End: start.Add(time.Duration(i+1) * time.Second), Success: success}
+		if err := store.Append(r); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	records, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("Expected 3 records, got %d", len(records))
+	}
+
+	stats := Compute(records, 2)
+	if len(stats) != 1 {
+		t.Fatalf("Expected stats for 1 task, got %d", len(stats))
+	}
+	s := stats[0]
+	if s.Runs != 3 || s.Last != 3*time.Second || s.Avg != 2*time.Second || s.Min != time.Second || s.Max != 3*time.Second {
+		t.Errorf("Unexpected stats: %+v", s)
+	}
+	if s.SuccessRate < 0.66 || s.SuccessRate > 0.67 {
+		t.Errorf("Expected a success rate of 2/3, got %f", s.SuccessRate)
+	}
+	if len(s.Recent) != 2 {
+		t.Errorf("Expected 2 recent durations, got %d", len(s.Recent))
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	got := Sparkline([]time.Duration{time.Second, 5 * time.Second, 9 * time.Second})
+	if got != "▁▄█" {
+		t.Errorf("Expected '▁▄█', got %q", got)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	records, err := NewStore(t.TempDir()).Load()
+	if err != nil || records != nil {
+		t.Errorf("Expected no records and no error, got %v, %v", records, err)
+	}
+}
+
+func TestPruneKeepsRecordsAppendedMeanwhile(t *testing.T) {
+	store := NewStore(t.TempDir())
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	store.Append(Record{Task: "old", Start: start})
+
+	appended := make(chan error)
+	removed, err := store.Prune(func(records []Record) []Record {
+		// another writer appends while the history is being rewritten
+		go func() { appended <- store.Append(Record{Task: "new", Start: start}) }()
+		time.Sleep(50 * time.Millisecond)
+		return records[:0]
+	})
+	if err != nil || removed != 1 {
+		t.Fatalf("Expected 1 record removed, got %d, %v", removed, err)
+	}
+	if err := <-appended; err != nil {
+		t.Fatal(err)
+	}
+	records, _ := store.Load()
+	if len(records) != 1 || records[0].Task != "new" {
+		t.Errorf("Expected the appended record to be kept, got %v", records)
+	}
+}
@@ -0,0 +1,79 @@
+package history
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Stats summarises the recorded runs of a single task
+type Stats struct {
+	Task        string
+	Runs        int
+	Last        time.Duration
+	Avg         time.Duration
+	Min         time.Duration
+	Max         time.Duration
+	SuccessRate float64
+	Recent      []time.Duration // Durations of the most recent runs, oldest first
+}
+
+// Compute aggregates records per task, keeping up to recent durations for sparklines.
+// Records are assumed to be in chronological order; the result is sorted by task id.
+func Compute(records []Record, recent int) []Stats {
+	byTask := map[string]*Stats{}
+	var total = map[string]time.Duration{}
+	var successes = map[string]int{}
+	for _, r := range records {
+		s, ok := byTask[r.Task]
+		d := r.Duration()
+		if !ok {
+			s = &Stats{Task: r.Task, Min: d, Max: d}
+			byTask[r.Task] = s
+		}
+		s.Runs++
+		s.Last = d
+		s.Min = min(s.Min, d)
+		s.Max = max(s.Max, d)
+		total[r.Task] += d
+		if r.Success {
+			successes[r.Task]++
+		}
+		s.Recent = append(s.Recent, d)
+		if len(s.Recent) > recent {
+			s.Recent = s.Recent[1:]
+		}
+	}
+
+	stats := make([]Stats, 0, len(byTask))
+	for task, s := range byTask {
+		s.Avg = total[task] / time.Duration(s.Runs)
+		s.SuccessRate = float64(successes[task]) / float64(s.Runs)
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Task < stats[j].Task })
+	return stats
+}
+
+// sparkBlocks are the characters used to draw sparklines, from lowest to highest
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders durations as a line of block characters scaled between their minimum and maximum
+func Sparkline(durations []time.Duration) string {
+	if len(durations) == 0 {
+		return ""
+	}
+	lo, hi := durations[0], durations[0]
+	for _, d := range durations {
+		lo, hi = min(lo, d), max(hi, d)
+	}
+	var sb strings.Builder
+	for _, d := range durations {
+		i := 0
+		if hi > lo {
+			i = int(float64(d-lo) / float64(hi-lo) * float64(len(sparkBlocks)-1))
+		}
+		sb.WriteRune(sparkBlocks[i])
+	}
+	return sb.String()
+}
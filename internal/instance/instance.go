@@ -0,0 +1,482 @@
+// Package instance keeps a single tash running per project. The running instance holds a lock
+// file and listens on a local socket, through which later launches can ask it to run a task or
+// follow its output instead of starting a second instance.
+//
+// The protocol is JSON lines: the client writes a Request, the server answers with a Response
+// and, for the attach command, then streams an Event per line until the client disconnects,
+// starting with the events of the run in progress. The instance is either an interactive tash or
+// a daemon started with "tash daemon". Editors talk to it the same way; docs/protocol.md
+// describes the protocol for them.
+package instance
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Aj4x/tash/internal/msgbus"
+	"github.com/Aj4x/tash/internal/task"
+)
+
+// Error represents a textual error value that implements the error interface.
+type Error string
+
+// Error returns the string representation of the Error type. It satisfies the error interface.
+func (e Error) Error() string {
+	return string(e)
+}
+
+// ErrRunning is returned by Listen when another instance is running for the project
+const ErrRunning = Error("tash is already running for this project")
+
+// Commands understood by the server
+const (
+	CommandPing   = "ping"
+	CommandStatus = "status"
+	CommandRun    = "run"
+	CommandCancel = "cancel"
+	CommandAttach = "attach"
+	CommandStop   = "stop"
+)
+
+// Events streamed to attached clients
+const (
+	EventStart  = "start"
+	EventOutput = "output"
+	EventDone   = "done"
+	EventError  = "error"
+)
+
+// dialTimeout bounds connecting to an instance, which is local and answers at once
+const dialTimeout = 2 * time.Second
+
+// followerBuffer is how many events an attached client may fall behind before events are dropped
+const followerBuffer = 256
+
+// replayLimit is how many output lines of the active run are replayed to clients attaching to it
+const replayLimit = 1000
+
+// Info describes a running instance; it is the content of the lock file
+type Info struct {
+	Pid    int    `json:"pid"`
+	Dir    string `json:"dir"`
+	Socket string `json:"socket"`
+	// Daemon is set for instances started with "tash daemon", which have no interface of their own
+	Daemon bool `json:"daemon,omitempty"`
+}
+
+// Request is sent by a client
+type Request struct {
+	Command string `json:"command"`
+	Task    string `json:"task,omitempty"`
+	// Vars answer the task's required variables as NAME=value entries
+	Vars []string `json:"vars,omitempty"`
+	// Confirmed answers the task's prompts
+	Confirmed bool `json:"confirmed,omitempty"`
+	// ConfirmedDangerous confirms the run of a task marked dangerous, whose id the user typed
+	ConfirmedDangerous bool `json:"confirmed_dangerous,omitempty"`
+}
+
+// Response answers a Request
+type Response struct {
+	OK     bool    `json:"ok"`
+	Error  string  `json:"error,omitempty"`
+	Pid    int     `json:"pid"`
+	Status *Status `json:"status,omitempty"`
+}
+
+// Status answers a status request
+type Status struct {
+	Info
+	// Running is the task being run, if any
+	Running string `json:"running,omitempty"`
+}
+
+// Event is streamed to attached clients
+type Event struct {
+	Event  string `json:"event"`
+	Task   string `json:"task,omitempty"`
+	Line   string `json:"line,omitempty"`
+	Stderr bool   `json:"stderr,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// StateDir returns the directory holding the lock files and sockets of running instances
+func StateDir(dataDir string) string {
+	return filepath.Join(dataDir, "instances")
+}
+
+// paths returns the lock file and socket of the instance for projectDir
+func paths(stateDir, projectDir string) (lock, socket string, err error) {
+	abs, err := filepath.Abs(projectDir)
+	if err != nil {
+		return "", "", err
+	}
+	sum := sha256.Sum256([]byte(abs))
+	// sockets paths are limited to around 100 bytes, so the name is kept short
+	name := hex.EncodeToString(sum[:8])
+	return filepath.Join(stateDir, name+".lock"), filepath.Join(stateDir, name+".sock"), nil
+}
+
+// Find returns the instance running for projectDir, or nil when there is none. Lock files left
+// by instances that are no longer running are removed.
+func Find(stateDir, projectDir string) (*Info, error) {
+	lock, socket, err := paths(stateDir, projectDir)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(lock)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var info Info
+	if err := json.Unmarshal(data, &info); err == nil {
+		if _, err := Send(info.Socket, Request{Command: CommandPing}); err == nil {
+			return &info, nil
+		}
+	}
+	// the instance didn't exit cleanly
+	_ = os.Remove(socket)
+	_ = os.Remove(lock)
+	return nil, nil
+}
+
+// Server is the running instance of a project, answering requests on its socket
+type Server struct {
+	Info Info
+	// OnRun is called for run requests; its error is returned to the client
+	OnRun func(req Request) error
+	// OnCancel is called for cancel requests; its error is returned to the client
+	OnCancel func() error
+	// OnStop is called for stop requests, which only daemons accept
+	OnStop func()
+	// OnEvent, when set, is called with each event published to attached clients
+	OnEvent func(Event)
+
+	lock      string
+	listener  net.Listener
+	mu        sync.Mutex
+	followers map[chan Event]struct{}
+	running   string
+	replay    []Event
+}
+
+// Listen claims projectDir for this process, returning ErrRunning when another instance holds
+// it. Serve must be called to answer requests, and Close to release the project.
+func Listen(stateDir, projectDir string) (*Server, error) {
+	return claim(stateDir, projectDir, false)
+}
+
+// ListenDaemon claims projectDir like Listen, for a daemon
+func ListenDaemon(stateDir, projectDir string) (*Server, error) {
+	return claim(stateDir, projectDir, true)
+}
+
+func claim(stateDir, projectDir string, daemon bool) (*Server, error) {
+	if info, err := Find(stateDir, projectDir); err != nil {
+		return nil, err
+	} else if info != nil {
+		return nil, fmt.Errorf("%w (pid %d)", ErrRunning, info.Pid)
+	}
+	lock, socket, err := paths(stateDir, projectDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(stateDir, 0o700); err != nil {
+		return nil, err
+	}
+	abs, _ := filepath.Abs(projectDir)
+	info := Info{Pid: os.Getpid(), Dir: abs, Socket: socket, Daemon: daemon}
+	data, err := json.Marshal(info)
+	if err != nil {
+		return nil, err
+	}
+	// creating the lock file exclusively settles two instances starting at once
+	f, err := os.OpenFile(lock, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if errors.Is(err, fs.ErrExist) {
+		return nil, ErrRunning
+	}
+	if err != nil {
+		return nil, err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(lock)
+		return nil, err
+	}
+	_ = os.Remove(socket)
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		_ = os.Remove(lock)
+		return nil, err
+	}
+	return &Server{
+		Info:      info,
+		lock:      lock,
+		listener:  listener,
+		followers: map[chan Event]struct{}{},
+	}, nil
+}
+
+// Serve answers requests until the server is closed
+func (s *Server) Serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+// Close stops answering requests and releases the project
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	_ = os.Remove(s.Info.Socket)
+	_ = os.Remove(s.lock)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for f := range s.followers {
+		close(f)
+		delete(s.followers, f)
+	}
+	return err
+}
+
+// handle answers the request of a connection
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+	var req Request
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		return
+	}
+	enc := json.NewEncoder(conn)
+	resp := Response{OK: true, Pid: s.Info.Pid}
+	refuse := func(err string) {
+		resp = Response{Error: err, Pid: s.Info.Pid}
+	}
+	switch req.Command {
+	case CommandPing:
+	case CommandStatus:
+		s.mu.Lock()
+		resp.Status = &Status{Info: s.Info, Running: s.running}
+		s.mu.Unlock()
+	case CommandRun:
+		switch {
+		case req.Task == "":
+			refuse("no task given")
+		case s.OnRun == nil:
+			refuse("this instance can't run tasks")
+		default:
+			if err := s.OnRun(req); err != nil {
+				refuse(err.Error())
+			}
+		}
+	case CommandCancel:
+		if s.OnCancel == nil {
+			refuse("this instance can't cancel tasks")
+		} else if err := s.OnCancel(); err != nil {
+			refuse(err.Error())
+		}
+	case CommandStop:
+		if s.OnStop == nil {
+			refuse("only a daemon can be stopped")
+		} else {
+			defer s.OnStop()
+		}
+	case CommandAttach:
+		events, replay := s.follow()
+		defer s.unfollow(events)
+		if err := enc.Encode(resp); err != nil {
+			return
+		}
+		for _, e := range replay {
+			if err := enc.Encode(e); err != nil {
+				return
+			}
+		}
+		// the client disconnecting is only noticed when writing to it
+		for e := range events {
+			if err := enc.Encode(e); err != nil {
+				return
+			}
+		}
+		return
+	default:
+		refuse(fmt.Sprintf("unknown command %q", req.Command))
+	}
+	_ = enc.Encode(resp)
+}
+
+// follow registers a channel receiving the events published from now on, and returns the events
+// of the active run published so far
+func (s *Server) follow() (chan Event, []Event) {
+	events := make(chan Event, followerBuffer)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.followers[events] = struct{}{}
+	return events, slices.Clone(s.replay)
+}
+
+// unfollow stops sending events to a channel registered by follow
+func (s *Server) unfollow(events chan Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.followers[events]; ok {
+		delete(s.followers, events)
+		close(events)
+	}
+}
+
+// Publish sends an event to every attached client. Clients too slow to keep up miss events
+// rather than holding up the instance.
+func (s *Server) Publish(e Event) {
+	if s.OnEvent != nil {
+		s.OnEvent(e)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for f := range s.followers {
+		select {
+		case f <- e:
+		default:
+		}
+	}
+}
+
+// Follow publishes the output and the outcome of the task runs on bus to attached clients, until
+// ctx is done
+func (s *Server) Follow(ctx context.Context, bus msgbus.PublisherSubscriber[task.Message]) error {
+	handler := make(msgbus.MessageHandler[task.Message], followerBuffer)
+	types := []task.Type{task.TypeTaskCommand, task.TypeTaskOutput, task.TypeTaskOutputErr, task.TypeTaskDone, task.TypeTaskError}
+	for _, t := range types {
+		key, err := bus.Subscribe(t.Topic(), handler)
+		if err != nil {
+			return err
+		}
+		defer bus.Unsubscribe(t.Topic(), key)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg := <-handler:
+			if e, ok := s.eventOf(msg.Message); ok {
+				s.Publish(e)
+			}
+		}
+	}
+}
+
+// eventOf converts a task message into the event sent to attached clients, tracking the task
+// that is running and the events to replay to clients attaching during its run. Command messages
+// only make an event when a run starts, rather than for each of its attempts.
+func (s *Server) eventOf(m task.Message) (Event, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch m.Type {
+	case task.TypeTaskCommand:
+		if !m.TaskRunning() || m.TaskId() == s.running {
+			return Event{}, false
+		}
+		s.running = m.TaskId()
+		e := Event{Event: EventStart, Task: s.running}
+		s.replay = []Event{e}
+		return e, true
+	case task.TypeTaskDone:
+		e := Event{Event: EventDone, Task: s.running}
+		s.running, s.replay = "", nil
+		return e, true
+	case task.TypeTaskError:
+		e := Event{Event: EventError, Task: s.running, Error: m.Error().Error()}
+		s.running, s.replay = "", nil
+		return e, true
+	default:
+		e := Event{Event: EventOutput, Task: s.running, Line: m.Output(), Stderr: m.Type == task.TypeTaskOutputErr}
+		if s.running != "" {
+			if len(s.replay) > replayLimit {
+				// the start event is kept, so clients know which task the lines belong to
+				s.replay = slices.Delete(s.replay, 1, 2)
+			}
+			s.replay = append(s.replay, e)
+		}
+		return e, true
+	}
+}
+
+// Send sends a request to the instance listening on socket and returns its response. A response
+// reporting an error is returned along with that error.
+func Send(socket string, req Request) (Response, error) {
+	conn, err := net.DialTimeout("unix", socket, dialTimeout)
+	if err != nil {
+		return Response{}, err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(dialTimeout))
+	return exchange(conn, req)
+}
+
+// exchange writes req to conn and reads the response
+func exchange(conn net.Conn, req Request) (Response, error) {
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return Response{}, err
+	}
+	var resp Response
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&resp); err != nil {
+		return Response{}, err
+	}
+	if !resp.OK {
+		return resp, Error(resp.Error)
+	}
+	return resp, nil
+}
+
+// Attach follows the output of the instance listening on socket, calling onEvent for each event,
+// until ctx is done or the instance exits
+func Attach(ctx context.Context, socket string, onEvent func(Event)) error {
+	conn, err := net.DialTimeout("unix", socket, dialTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+	if err := json.NewEncoder(conn).Encode(Request{Command: CommandAttach}); err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bufio.NewReader(conn))
+	var resp Response
+	if err := dec.Decode(&resp); err != nil {
+		return err
+	}
+	if !resp.OK {
+		return Error(resp.Error)
+	}
+	for {
+		var e Event
+		if err := dec.Decode(&e); err != nil {
+			// ctx is done or the instance exited
+			return nil
+		}
+		onEvent(e)
+	}
+}
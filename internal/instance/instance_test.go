@@ -0,0 +1,227 @@
+package instance
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Aj4x/tash/internal/msgbus"
+	"github.com/Aj4x/tash/internal/task"
+)
+
+// listen starts the instance of a new project, closed when the test ends
+func listen(t *testing.T) (*Server, string, string) {
+	t.Helper()
+	stateDir, project := t.TempDir(), t.TempDir()
+	server, err := Listen(stateDir, project)
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	go server.Serve()
+	t.Cleanup(func() { server.Close() })
+	return server, stateDir, project
+}
+
+func TestSecondInstanceFindsTheFirst(t *testing.T) {
+	server, stateDir, project := listen(t)
+
+	info, err := Find(stateDir, project)
+	if err != nil || info == nil {
+		t.Fatalf("Expected the running instance, got %v, %v", info, err)
+	}
+	if info.Pid != os.Getpid() || info.Socket != server.Info.Socket {
+		t.Errorf("Expected pid %d on %s, got %+v", os.Getpid(), server.Info.Socket, info)
+	}
+	if _, err := Listen(stateDir, project); !errors.Is(err, ErrRunning) {
+		t.Errorf("Expected ErrRunning, got %v", err)
+	}
+	if info, _ := Find(stateDir, t.TempDir()); info != nil {
+		t.Errorf("Expected no instance for another project, got %+v", info)
+	}
+
+	server.Close()
+	if info, _ := Find(stateDir, project); info != nil {
+		t.Errorf("Expected no instance once closed, got %+v", info)
+	}
+}
+
+func TestStaleLockIsRemoved(t *testing.T) {
+	stateDir, project := t.TempDir(), t.TempDir()
+	lock, socket, err := paths(stateDir, project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// an instance that was killed leaves its lock file behind
+	data := `{"pid": 1, "dir": "` + project + `", "socket": "` + socket + `"}`
+	if err := os.WriteFile(lock, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if info, err := Find(stateDir, project); info != nil || err != nil {
+		t.Fatalf("Expected the stale instance to be ignored, got %+v, %v", info, err)
+	}
+	if _, err := os.Stat(lock); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected the stale lock file to be removed, got %v", err)
+	}
+	server, err := Listen(stateDir, project)
+	if err != nil {
+		t.Fatalf("Expected to take over the project, got %v", err)
+	}
+	server.Close()
+}
+
+func TestRunRequest(t *testing.T) {
+	server, _, _ := listen(t)
+	var ran string
+	server.OnRun = func(req Request) error {
+		if req.Task == "missing" {
+			return errors.New("unknown task 'missing'")
+		}
+		ran = req.Task
+		return nil
+	}
+
+	if _, err := Send(server.Info.Socket, Request{Command: CommandRun, Task: "build"}); err != nil {
+		t.Fatalf("Expected the run to be accepted, got %v", err)
+	}
+	if ran != "build" {
+		t.Errorf("Expected build to run, got %q", ran)
+	}
+	_, err := Send(server.Info.Socket, Request{Command: CommandRun, Task: "missing"})
+	if err == nil || err.Error() != "unknown task 'missing'" {
+		t.Errorf("Expected the refusal to be returned, got %v", err)
+	}
+	if _, err := Send(server.Info.Socket, Request{Command: "reboot"}); err == nil {
+		t.Error("Expected an unknown command to be refused")
+	}
+}
+
+func TestDaemonCancelAndStop(t *testing.T) {
+	stateDir, project := t.TempDir(), t.TempDir()
+	server, err := ListenDaemon(stateDir, project)
+	if err != nil {
+		t.Fatalf("ListenDaemon() error = %v", err)
+	}
+	go server.Serve()
+	defer server.Close()
+	if info, _ := Find(stateDir, project); info == nil || !info.Daemon {
+		t.Fatalf("Expected the daemon to be found, got %+v", info)
+	}
+
+	if _, err := Send(server.Info.Socket, Request{Command: CommandCancel}); err == nil {
+		t.Error("Expected cancelling to be refused without a handler")
+	}
+	cancelled := false
+	server.OnCancel = func() error {
+		cancelled = true
+		return nil
+	}
+	if _, err := Send(server.Info.Socket, Request{Command: CommandCancel}); err != nil || !cancelled {
+		t.Errorf("Expected the run to be cancelled, got %v", err)
+	}
+
+	stopped := make(chan struct{})
+	server.OnStop = func() { close(stopped) }
+	if _, err := Send(server.Info.Socket, Request{Command: CommandStop}); err != nil {
+		t.Fatalf("Expected the stop to be accepted, got %v", err)
+	}
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Error("Expected the daemon to stop")
+	}
+}
+
+func TestAttachStreamsTaskOutput(t *testing.T) {
+	server, _, _ := listen(t)
+	bus := msgbus.NewMessageBus[task.Message]()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go server.Follow(ctx, bus)
+
+	events := make(chan Event, 10)
+	go Attach(ctx, server.Info.Socket, func(e Event) { events <- e })
+	// wait until the client is following before publishing
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		server.mu.Lock()
+		n := len(server.followers)
+		server.mu.Unlock()
+		if n == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the client to attach")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// a retried run publishes a command message for each attempt
+	bus.Publish(task.TypeTaskCommand.Message().SetTaskId("build").SetTaskRunning(true).TopicMessage())
+	bus.Publish(task.TypeTaskOutputErr.Message().SetOutput("compiling").TopicMessage())
+	bus.Publish(task.TypeTaskCommand.Message().SetTaskId("build").SetTaskRunning(true).TopicMessage())
+	bus.Publish(task.TypeTaskDone.Message().TopicMessage())
+
+	for _, want := range []Event{
+		{Event: EventStart, Task: "build"},
+		{Event: EventOutput, Task: "build", Line: "compiling", Stderr: true},
+		{Event: EventDone, Task: "build"},
+	} {
+		select {
+		case got := <-events:
+			if got != want {
+				t.Errorf("Expected %+v, got %+v", want, got)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Expected %+v to be streamed", want)
+		}
+	}
+}
+
+func TestStatusAndReplayOfTheActiveRun(t *testing.T) {
+	server, _, _ := listen(t)
+	bus := msgbus.NewMessageBus[task.Message]()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go server.Follow(ctx, bus)
+
+	resp, err := Send(server.Info.Socket, Request{Command: CommandStatus})
+	if err != nil || resp.Status == nil || resp.Status.Running != "" || resp.Status.Socket != server.Info.Socket {
+		t.Fatalf("Expected an idle status, got %+v, %v", resp.Status, err)
+	}
+
+	bus.Publish(task.TypeTaskCommand.Message().SetTaskId("serve").SetTaskRunning(true).TopicMessage())
+	bus.Publish(task.TypeTaskOutput.Message().SetOutput("listening").TopicMessage())
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		server.mu.Lock()
+		n := len(server.replay)
+		server.mu.Unlock()
+		if n == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the run to be tracked")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if resp, _ := Send(server.Info.Socket, Request{Command: CommandStatus}); resp.Status == nil || resp.Status.Running != "serve" {
+		t.Errorf("Expected serve to be running, got %+v", resp.Status)
+	}
+
+	// a client attaching during the run is sent what it missed
+	events := make(chan Event, 10)
+	go Attach(ctx, server.Info.Socket, func(e Event) { events <- e })
+	for _, want := range []Event{{Event: EventStart, Task: "serve"}, {Event: EventOutput, Task: "serve", Line: "listening"}} {
+		select {
+		case got := <-events:
+			if got != want {
+				t.Errorf("Expected %+v, got %+v", want, got)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("Expected %+v to be replayed", want)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// FileName is the name of the debug log file inside the log directory
+	FileName = "tash.log"
+	// MaxFileSize is the size in bytes at which the debug log is rotated
+	MaxFileSize = 5 * 1024 * 1024
+	// MaxBackups is the number of rotated log files kept alongside the active one
+	MaxBackups = 3
+)
+
+// RotatingFile is an io.WriteCloser that rotates the underlying file once it exceeds a maximum size.
+// Rotated files are renamed with a numeric suffix (tash.log.1, tash.log.2, ...).
+type RotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	mu         sync.Mutex
+	file       *os.File
+	size       int64
+}
+
+// OpenRotatingFile opens (or creates) the file at path for appending, creating parent directories as needed
+func OpenRotatingFile(path string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create log directory: %w", err)
+	}
+	r := &RotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *RotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("unable to open log file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("unable to stat log file: %w", err)
+	}
+	r.file, r.size = f, info.Size()
+	return nil
+}
+
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	for i := r.maxBackups - 1; i > 0; i-- {
+		_ = os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+	}
+	if r.maxBackups > 0 {
+		_ = os.Rename(r.path, r.path+".1")
+	} else {
+		_ = os.Remove(r.path)
+	}
+	return r.open()
+}
+
+// Write writes p to the log file, rotating first if the write would exceed the maximum size
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Close closes the underlying file
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+// nopCloser is returned when logging is disabled
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
+
+// Setup installs the default slog logger. When enabled, structured debug logs are written to a
+// rotating file in dir; otherwise all log output is discarded so nothing is printed over the TUI.
+// The returned closer must be closed on exit and the returned path is empty when logging is disabled.
+func Setup(dir string, enabled bool) (io.Closer, string, error) {
+	if !enabled {
+		slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+		return nopCloser{}, "", nil
+	}
+	path := filepath.Join(dir, FileName)
+	f, err := OpenRotatingFile(path, MaxFileSize, MaxBackups)
+	if err != nil {
+		slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+		return nopCloser{}, "", err
+	}
+	slog.SetDefault(slog.New(slog.NewJSONHandler(f, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	return f, path, nil
+}
+
+// WriteCrashLog writes a panic value and stack trace to a timestamped file in dir and returns its path
+func WriteCrashLog(dir string, value any, stack []byte) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("unable to create crash log directory: %w", err)
+	}
+	f, err := os.CreateTemp(dir, "crash-"+time.Now().Format("20060102-150405")+"-*.log")
+	if err != nil {
+		return "", fmt.Errorf("unable to create crash log: %w", err)
+	}
+	defer f.Close()
+	if _, err := fmt.Fprintf(f, "panic: %v\n\n%s", value, stack); err != nil {
+		return f.Name(), fmt.Errorf("unable to write crash log: %w", err)
+	}
+	return f.Name(), nil
+}
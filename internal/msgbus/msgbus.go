@@ -1,11 +1,11 @@
 package msgbus
 
 import (
-	"context"
 	"fmt"
 	"github.com/Aj4x/tash/internal/uuid"
+	"log/slog"
 	"sync"
-	"time"
+	"sync/atomic"
 )
 
 // Error represents a textual error value that implements the error interface.
@@ -23,6 +23,10 @@ const (
 	ErrGeneratingKey = Error("Error generating key")
 )
 
+// MaxQueued is the number of messages queued for a subscriber whose channel is full; beyond it the oldest queued
+// messages are dropped, so a subscriber that stopped receiving without unsubscribing doesn't grow memory forever.
+const MaxQueued = 10000
+
 // Topic represents a category or channel for messages in a publish-subscribe system.
 type Topic string
 
@@ -37,15 +41,74 @@ type TopicMessage[T any] struct {
 type MessageHandler[T any] chan TopicMessage[T]
 
 // subscription represents a registration to a specific Topic with a unique Key and a Handler to process incoming messages for the Topic.
+// Messages finding the Handler full wait in queue, which a single goroutine drains in order while draining is set.
 type subscription[T any] struct {
-	Topic   Topic
-	Key     uuid.UUID
-	Handler MessageHandler[T]
+	Topic    Topic
+	Key      uuid.UUID
+	Handler  MessageHandler[T]
+	mu       sync.Mutex
+	queue    []TopicMessage[T]
+	draining bool
+	dropped  int           // Messages dropped from the full queue since draining started
+	stop     chan struct{} // Closed on unsubscribing, dropping the queued messages
+}
+
+// publish sends a TopicMessage to the associated MessageHandler channel of the subscription, directly when nothing is
+// queued and the channel has space, otherwise after the queued messages, dropping the oldest of them when MaxQueued are
+// waiting already. It reports whether the message was queued and whether one was dropped.
+func (s *subscription[T]) publish(msg TopicMessage[T], inFlight *atomic.Int64) (queued, dropped bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.draining {
+		select {
+		case s.Handler <- msg:
+			return false, false
+		default:
+		}
+		s.draining = true
+		go s.drain(inFlight)
+	}
+	if len(s.queue) >= MaxQueued {
+		s.queue[0] = TopicMessage[T]{}
+		s.queue = s.queue[1:]
+		inFlight.Add(-1)
+		s.dropped++
+		dropped = true
+		if s.dropped == 1 {
+			slog.Debug("subscriber queue full, dropping the oldest messages", "topic", s.Topic, "queued", MaxQueued)
+		}
+	}
+	inFlight.Add(1)
+	s.queue = append(s.queue, msg)
+	return true, dropped
 }
 
-// publish sends a TopicMessage to the associated MessageHandler channel of the subscription.
-func (s *subscription[T]) publish(msg TopicMessage[T]) {
-	s.Handler <- msg
+// drain sends the queued messages to the Handler in order, until the queue is empty or the subscription is removed.
+func (s *subscription[T]) drain(inFlight *atomic.Int64) {
+	for {
+		s.mu.Lock()
+		if len(s.queue) == 0 {
+			if s.dropped > 0 {
+				slog.Debug("subscriber caught up", "topic", s.Topic, "dropped", s.dropped)
+			}
+			s.draining, s.dropped = false, 0
+			s.mu.Unlock()
+			return
+		}
+		msg := s.queue[0]
+		s.queue = s.queue[1:]
+		s.mu.Unlock()
+		select {
+		case s.Handler <- msg:
+			inFlight.Add(-1)
+		case <-s.stop:
+			s.mu.Lock()
+			inFlight.Add(-int64(len(s.queue)) - 1)
+			s.queue = nil
+			s.mu.Unlock()
+			return
+		}
+	}
 }
 
 // Publisher is an interface for publishing messages to a specified topic.
@@ -73,41 +136,57 @@ type PublisherSubscriber[T any] interface {
 	Unsubscriber
 }
 
+// Stats are counters of the traffic of a message bus, for diagnostics.
+// Delayed counts the deliveries queued because a subscriber's channel was full or earlier messages were queued,
+// and InFlight those of them not received yet. Dropped counts the queued deliveries dropped as MaxQueued were waiting.
+type Stats struct {
+	Topics      int
+	Subscribers int
+	Published   uint64
+	Delayed     uint64
+	InFlight    int64
+	Dropped     uint64
+}
+
+// StatsReporter is implemented by message buses that count their traffic.
+type StatsReporter interface {
+	Stats() Stats
+}
+
 // messageBus is a struct implementing a publisher-subscriber mechanism with concurrency control.
 // It maintains a map of topics to a list of subscriptions and ensures thread-safe access via a mutex.
 type messageBus[T any] struct {
-	subscribers map[Topic][]subscription[T]
+	subscribers map[Topic][]*subscription[T]
 	subLock     sync.Mutex
+	published   atomic.Uint64
+	delayed     atomic.Uint64
+	inFlight    atomic.Int64
+	dropped     atomic.Uint64
 }
 
 // NewMessageBus creates and initialises a new instance of a message bus implementing the PublisherSubscriber interface.
 func NewMessageBus[T any]() PublisherSubscriber[T] {
 	return &messageBus[T]{
-		subscribers: make(map[Topic][]subscription[T]),
+		subscribers: make(map[Topic][]*subscription[T]),
 	}
 }
 
-// Publish sends a TopicMessage to all subscribers of the specified topic, using a goroutine for each subscriber, with a timeout of 5 seconds for publishing.
+// Publish sends a TopicMessage to all subscribers of the specified topic without blocking. Each subscriber receives the
+// messages in publish order: a message finding the subscriber's channel full is queued, and the following messages are
+// queued behind it until a goroutine has delivered them all. Queued messages are dropped when the subscriber unsubscribes,
+// and the oldest of them once MaxQueued are waiting.
 func (m *messageBus[T]) Publish(msg TopicMessage[T]) {
+	m.published.Add(1)
 	m.subLock.Lock()
 	defer m.subLock.Unlock()
-	subscriptions, ok := m.subscribers[msg.Topic]
-	if !ok {
-		return
-	}
-	publish := func(s subscription[T], ctx context.Context, cancel context.CancelFunc) {
-		select {
-		case <-ctx.Done():
-			cancel()
-			return
-		default:
-			s.publish(msg)
+	for _, sub := range m.subscribers[msg.Topic] {
+		queued, dropped := sub.publish(msg, &m.inFlight)
+		if queued {
+			m.delayed.Add(1)
+		}
+		if dropped {
+			m.dropped.Add(1)
 		}
-		cancel()
-	}
-	for _, sub := range subscriptions {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		go publish(sub, ctx, cancel)
 	}
 }
 
@@ -120,10 +199,11 @@ func (m *messageBus[T]) Subscribe(topic Topic, handler MessageHandler[T]) (uuid.
 	if err != nil {
 		return uuid.UUID{}, fmt.Errorf("%w: %w", ErrGeneratingKey, err)
 	}
-	s := subscription[T]{
+	s := &subscription[T]{
 		Topic:   topic,
 		Key:     key,
 		Handler: handler,
+		stop:    make(chan struct{}),
 	}
 	m.subLock.Lock()
 	defer m.subLock.Unlock()
@@ -131,6 +211,23 @@ func (m *messageBus[T]) Subscribe(topic Topic, handler MessageHandler[T]) (uuid.
 	return key, nil
 }
 
+// Stats returns the counters of the bus traffic so far.
+func (m *messageBus[T]) Stats() Stats {
+	m.subLock.Lock()
+	defer m.subLock.Unlock()
+	stats := Stats{
+		Topics:    len(m.subscribers),
+		Published: m.published.Load(),
+		Delayed:   m.delayed.Load(),
+		InFlight:  m.inFlight.Load(),
+		Dropped:   m.dropped.Load(),
+	}
+	for _, subscriptions := range m.subscribers {
+		stats.Subscribers += len(subscriptions)
+	}
+	return stats
+}
+
 // Unsubscribe removes a subscription identified by a topic and its unique key from the message bus.
 func (m *messageBus[T]) Unsubscribe(topic Topic, key uuid.UUID) {
 	m.subLock.Lock()
@@ -141,13 +238,14 @@ func (m *messageBus[T]) Unsubscribe(topic Topic, key uuid.UUID) {
 	}
 	for i, subscription := range subscriptions {
 		if subscription.Key == key {
+			close(subscription.stop)
 			if len(subscriptions) == 1 {
 				delete(m.subscribers, topic)
-				fmt.Printf("removed topic %s, no more subscribers\n", topic)
+				slog.Debug("removed topic, no more subscribers", "topic", topic)
 				break
 			}
 			m.subscribers[topic] = append(subscriptions[:i], subscriptions[i+1:]...)
-			fmt.Printf("removed topic %s, %d subscribers remaining\n", topic, len(m.subscribers[topic]))
+			slog.Debug("removed subscriber", "topic", topic, "remaining", len(m.subscribers[topic]))
 			break
 		}
 	}
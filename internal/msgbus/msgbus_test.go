@@ -335,7 +335,7 @@ func TestTimeout(t *testing.T) {
 		case <-handler:
 			// Success - we received at least one message
 			receivedMessages++
-		case <-time.After(6 * time.Second): // Longer than the 5s timeout in Publish
+		case <-time.After(6 * time.Second):
 			t.Error("No message received within timeout period")
 		}
 
@@ -412,3 +412,118 @@ func TestMultipleTopics(t *testing.T) {
 		close(handler2)
 	})
 }
+
+func TestStats(t *testing.T) {
+	bus := msgbus.NewMessageBus[[]byte]()
+	topic := msgbus.Topic("test-topic")
+	handler := make(msgbus.MessageHandler[[]byte], 1)
+	if _, err := bus.Subscribe(topic, handler); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+
+	// The second message finds the channel full and is delivered once the first is received
+	bus.Publish(msgbus.TopicMessage[[]byte]{Topic: topic, Message: []byte("first")})
+	bus.Publish(msgbus.TopicMessage[[]byte]{Topic: topic, Message: []byte("second")})
+	bus.Publish(msgbus.TopicMessage[[]byte]{Topic: "unheard", Message: []byte("third")})
+
+	stats := bus.(msgbus.StatsReporter).Stats()
+	if stats.Topics != 1 || stats.Subscribers != 1 || stats.Published != 3 || stats.Delayed != 1 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+	<-handler
+	<-handler
+	deadline := time.Now().Add(time.Second)
+	for bus.(msgbus.StatsReporter).Stats().InFlight != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected no delivery in flight once the messages were received")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPublishPreservesOrderWhenTheBufferIsFull(t *testing.T) {
+	bus := msgbus.NewMessageBus[int]()
+	topic := msgbus.Topic("output")
+	handler := make(msgbus.MessageHandler[int], 4)
+	if _, err := bus.Subscribe(topic, handler); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+
+	// the buffer fills at once, and space frees up while messages are still published
+	const count = 1000
+	go func() {
+		for i := 0; i < count; i++ {
+			bus.Publish(msgbus.TopicMessage[int]{Topic: topic, Message: i})
+		}
+	}()
+	for i := 0; i < count; i++ {
+		select {
+		case msg := <-handler:
+			if msg.Message != i {
+				t.Fatalf("Expected message %d, got %d", i, msg.Message)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected message %d to be delivered", i)
+		}
+		if i%100 == 0 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
+
+func TestUnsubscribeDropsQueuedMessages(t *testing.T) {
+	bus := msgbus.NewMessageBus[int]()
+	topic := msgbus.Topic("output")
+	handler := make(msgbus.MessageHandler[int], 1)
+	key, err := bus.Subscribe(topic, handler)
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		bus.Publish(msgbus.TopicMessage[int]{Topic: topic, Message: i})
+	}
+	bus.Unsubscribe(topic, key)
+
+	deadline := time.Now().Add(time.Second)
+	for bus.(msgbus.StatsReporter).Stats().InFlight != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the queued messages to be dropped")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if msg := <-handler; msg.Message != 0 {
+		t.Errorf("Expected the first message to stay in the channel, got %d", msg.Message)
+	}
+}
+
+func TestPublishDropsTheOldestQueuedMessages(t *testing.T) {
+	bus := msgbus.NewMessageBus[int]()
+	topic := msgbus.Topic("output")
+	// a subscriber that stopped receiving
+	handler := make(msgbus.MessageHandler[int])
+	if _, err := bus.Subscribe(topic, handler); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	const count = msgbus.MaxQueued + 100
+	for i := 0; i < count; i++ {
+		bus.Publish(msgbus.TopicMessage[int]{Topic: topic, Message: i})
+	}
+	stats := bus.(msgbus.StatsReporter).Stats()
+	if stats.InFlight > msgbus.MaxQueued+1 || stats.Dropped < 99 {
+		t.Errorf("Expected the queue to be capped at %d, got %+v", msgbus.MaxQueued, stats)
+	}
+
+	// the latest messages are kept, in order
+	last := -1
+	for last != count-1 {
+		select {
+		case msg := <-handler:
+			if msg.Message <= last {
+				t.Fatalf("Expected message %d after %d", msg.Message, last)
+			}
+			last = msg.Message
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected the last message, got %d", last)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Package namespaces persists, per project, the task namespaces hidden from the task list and
+// those pinned to its top, so large catalogs stay manageable across starts. The choices are kept
+// in the data directory rather than the project, as they are personal.
+package namespaces
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// DirName is the name of the directory inside the data directory holding the choices
+const DirName = "namespaces"
+
+// Prefs are the namespace choices of a project. A namespace covers the namespaces nested in it,
+// e.g. hiding "ci" hides "ci:lint" and "ci:docker:build".
+type Prefs struct {
+	Hidden []string `json:"hidden,omitempty"`
+	// Pinned namespaces are listed first, in the order they were pinned
+	Pinned []string `json:"pinned,omitempty"`
+}
+
+// Match returns the namespace of list covering namespace, or false when there is none. The root
+// namespace, empty, is never covered.
+func Match(list []string, namespace string) (string, bool) {
+	if namespace == "" {
+		return "", false
+	}
+	for _, ns := range list {
+		if namespace == ns || strings.HasPrefix(namespace, ns+":") {
+			return ns, true
+		}
+	}
+	return "", false
+}
+
+// toggle adds namespace to list, or removes it when it is there, and reports whether it was added
+func toggle(list *[]string, namespace string) bool {
+	if i := slices.Index(*list, namespace); i >= 0 {
+		*list = slices.Delete(*list, i, i+1)
+		return false
+	}
+	*list = append(*list, namespace)
+	return true
+}
+
+// ToggleHidden hides namespace, or shows it again, and reports whether it is now hidden
+func (p *Prefs) ToggleHidden(namespace string) bool {
+	return toggle(&p.Hidden, namespace)
+}
+
+// TogglePinned pins namespace, or unpins it, and reports whether it is now pinned
+func (p *Prefs) TogglePinned(namespace string) bool {
+	return toggle(&p.Pinned, namespace)
+}
+
+// Store keeps the choices of each project. A Store with an empty directory is disabled.
+type Store struct {
+	Dir string
+}
+
+// NewStore returns a store keeping its choices in the data directory dataDir
+func NewStore(dataDir string) Store {
+	return Store{Dir: filepath.Join(dataDir, DirName)}
+}
+
+// entry is the stored choices of a project
+type entry struct {
+	Dir   string `json:"dir"`
+	Prefs Prefs  `json:"prefs"`
+}
+
+// path returns the file keeping the choices of the project in dir
+func (s Store) path(dir string) (string, string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", "", err
+	}
+	sum := sha256.Sum256([]byte(abs))
+	return filepath.Join(s.Dir, hex.EncodeToString(sum[:8])+".json"), abs, nil
+}
+
+// Load returns the choices of the project in dir. A project without choices has none hidden or
+// pinned.
+func (s Store) Load(dir string) (Prefs, error) {
+	if s.Dir == "" {
+		return Prefs{}, nil
+	}
+	path, abs, err := s.path(dir)
+	if err != nil {
+		return Prefs{}, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Prefs{}, nil
+	}
+	if err != nil {
+		return Prefs{}, fmt.Errorf("unable to read namespace choices: %w", err)
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return Prefs{}, fmt.Errorf("unable to parse namespace choices: %w", err)
+	}
+	if e.Dir != abs {
+		return Prefs{}, nil
+	}
+	return e.Prefs, nil
+}
+
+// Save stores the choices of the project in dir
+func (s Store) Save(dir string, p Prefs) error {
+	if s.Dir == "" {
+		return nil
+	}
+	path, abs, err := s.path(dir)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(entry{Dir: abs, Prefs: p}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return fmt.Errorf("unable to create the namespaces directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("unable to save namespace choices: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package namespaces
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMatchCoversNestedNamespaces(t *testing.T) {
+	list := []string{"ci", "docs:api"}
+	for namespace, want := range map[string]string{
+		"ci":          "ci",
+		"ci:docker":   "ci",
+		"docs:api":    "docs:api",
+		"docs":        "",
+		"cicd":        "",
+		"":            "",
+		"docs:api:v2": "docs:api",
+	} {
+		if got, _ := Match(list, namespace); got != want {
+			t.Errorf("Match(%q) = %q, want %q", namespace, got, want)
+		}
+	}
+}
+
+func TestStoreKeepsTheChoicesOfEachProject(t *testing.T) {
+	store := NewStore(t.TempDir())
+	project := t.TempDir()
+
+	var p Prefs
+	if !p.ToggleHidden("ci") || !p.TogglePinned("app") || !p.TogglePinned("db") || p.TogglePinned("app") {
+		t.Fatalf("Expected the toggles to report the new state, got %+v", p)
+	}
+	if err := store.Save(project, p); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	loaded, err := store.Load(project)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !slices.Equal(loaded.Hidden, []string{"ci"}) || !slices.Equal(loaded.Pinned, []string{"db"}) {
+		t.Errorf("Expected the saved choices, got %+v", loaded)
+	}
+	if other, err := store.Load(t.TempDir()); err != nil || len(other.Hidden) != 0 {
+		t.Errorf("Expected no choices for another project, got %+v, %v", other, err)
+	}
+}
@@ -0,0 +1,111 @@
+// Package paste uploads task output to a paste service, such as a private pastebin or GitHub
+// gists, so it can be shared as a link.
+package paste
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// GistURL is the GitHub API endpoint creating gists; uploads to it create a secret gist
+const GistURL = "https://api.github.com/gists"
+
+// requestTimeout bounds an upload
+const requestTimeout = 30 * time.Second
+
+// maxResponse bounds the response read for the link to the paste
+const maxResponse = 1 << 20
+
+// Client uploads pastes to URL. With Gist set, URL is the GitHub gists API and uploads create a
+// secret gist; otherwise the endpoint receives the content as the plain text body of a POST and
+// answers with the link, either as the body or as the "url" or "html_url" field of a JSON object.
+// Token, when set, is sent as a bearer token.
+type Client struct {
+	URL    string
+	Token  string
+	Gist   bool
+	Client *http.Client
+}
+
+// New returns a client uploading to endpoint with token, recognising the GitHub gists API
+func New(endpoint, token string) Client {
+	u, err := url.Parse(endpoint)
+	gist := err == nil && u.Host == "api.github.com" && strings.TrimSuffix(u.Path, "/") == "/gists"
+	return Client{URL: endpoint, Token: token, Gist: gist}
+}
+
+// Upload uploads content as a paste named name and returns its link
+func (c Client) Upload(ctx context.Context, name, content string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	body, contentType := []byte(content), "text/plain; charset=utf-8"
+	if c.Gist {
+		gist := map[string]any{
+			"description": name,
+			"public":      false,
+			"files":       map[string]any{name: map[string]string{"content": content}},
+		}
+		var err error
+		if body, err = json.Marshal(gist); err != nil {
+			return "", err
+		}
+		contentType = "application/json"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if c.Gist {
+		req.Header.Set("Accept", "application/vnd.github+json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("unable to upload the paste: %w", err)
+	}
+	defer resp.Body.Close()
+	answer, err := io.ReadAll(io.LimitReader(resp.Body, maxResponse))
+	if err != nil {
+		return "", fmt.Errorf("unable to read the answer of the paste service: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("unable to upload the paste: %s", resp.Status)
+	}
+	return pasteLink(answer)
+}
+
+// pasteLink returns the link to the paste answered by the paste service
+func pasteLink(answer []byte) (string, error) {
+	var fields struct {
+		URL     string `json:"url"`
+		HTMLURL string `json:"html_url"`
+	}
+	link := strings.TrimSpace(string(answer))
+	if json.Unmarshal(answer, &fields) == nil {
+		// gists answer with both, the API url and the page to share
+		link = fields.HTMLURL
+		if link == "" {
+			link = fields.URL
+		}
+	}
+	if u, err := url.Parse(link); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", errors.New("the paste service didn't answer with a link")
+	}
+	return link, nil
+}
@@ -0,0 +1,68 @@
+package paste
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUploadPlainText(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "FAIL\n" || r.Header.Get("Authorization") != "Bearer t0ken" {
+			http.Error(w, "unexpected upload", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte("https://paste.example.com/abc\n"))
+	}))
+	defer server.Close()
+
+	link, err := Client{URL: server.URL, Token: "t0ken"}.Upload(context.Background(), "build.log", "FAIL\n")
+	if err != nil || link != "https://paste.example.com/abc" {
+		t.Errorf("Upload() = %q, %v", link, err)
+	}
+
+	link, err = Client{URL: server.URL}.Upload(context.Background(), "build.log", "FAIL\n")
+	if err == nil {
+		t.Errorf("Expected the refused upload to be reported, got %q", link)
+	}
+}
+
+func TestUploadGist(t *testing.T) {
+	var gist struct {
+		Public bool                         `json:"public"`
+		Files  map[string]map[string]string `json:"files"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&gist); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"url": "https://api.github.com/gists/1", "html_url": "https://gist.github.com/ann/1"}`))
+	}))
+	defer server.Close()
+
+	c := Client{URL: server.URL, Gist: true, Client: server.Client()}
+	link, err := c.Upload(context.Background(), "build.log", "FAIL\n")
+	if err != nil || link != "https://gist.github.com/ann/1" {
+		t.Errorf("Upload() = %q, %v", link, err)
+	}
+	if gist.Public || gist.Files["build.log"]["content"] != "FAIL\n" {
+		t.Errorf("Expected a secret gist holding the output, got %+v", gist)
+	}
+	if !New(GistURL, "").Gist || New("https://paste.example.com", "").Gist {
+		t.Error("Expected only the gists API to be recognised")
+	}
+}
+
+func TestPasteLink(t *testing.T) {
+	for _, answer := range []string{"", "ok", `{"id": 1}`, "ftp://example.com/x"} {
+		if link, err := pasteLink([]byte(answer)); err == nil {
+			t.Errorf("Expected %q to be refused, got %q", answer, link)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+// Package preflight runs the checks a project declares its tasks need, such as binaries on the
+// PATH, environment variables and minimum versions, before the tasks are run
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/task"
+)
+
+// checkTimeout bounds how long a binary may take to report its version
+const checkTimeout = 10 * time.Second
+
+// Check is a pre-flight check declared in the project config
+type Check config.PreflightCheck
+
+// Label returns the name of the check
+func (c Check) Label() string {
+	switch {
+	case c.Name != "":
+		return c.Name
+	case c.Binary != "" && c.MinVersion != "":
+		return c.Binary + " " + c.MinVersion + " or later"
+	case c.Binary != "":
+		return c.Binary
+	default:
+		return "$" + c.Env
+	}
+}
+
+// Applies reports whether the task taskId depends on the check
+func (c Check) Applies(taskId string) bool {
+	if len(c.Tasks) == 0 {
+		return true
+	}
+	for _, pattern := range c.Tasks {
+		if ok, _ := path.Match(pattern, taskId); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Checks returns the checks of the config, or an error naming the first invalid one
+func Checks(cfg []config.PreflightCheck) ([]Check, error) {
+	checks := make([]Check, len(cfg))
+	for i, c := range cfg {
+		if c.Binary == "" && c.Env == "" {
+			return nil, fmt.Errorf("pre-flight check %d has neither a binary nor an env", i+1)
+		}
+		if c.MinVersion != "" && c.Binary == "" {
+			return nil, fmt.Errorf("pre-flight check %d has a min_version but no binary", i+1)
+		}
+		checks[i] = Check(c)
+	}
+	return checks, nil
+}
+
+// Result is the outcome of a check
+type Result struct {
+	Check Check
+	// Detail tells what was found, e.g. the version of the binary
+	Detail string
+	// Err is why the check failed, nil when it passed
+	Err error
+}
+
+// Run runs the checks in env, the environment tasks run in
+func Run(ctx context.Context, env task.Environment, checks []Check) []Result {
+	results := make([]Result, len(checks))
+	for i, c := range checks {
+		results[i] = run(ctx, env, c)
+	}
+	return results
+}
+
+// Failed returns the failed results of the checks taskId depends on
+func Failed(results []Result, taskId string) []Result {
+	var failed []Result
+	for _, r := range results {
+		if r.Err != nil && r.Check.Applies(taskId) {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+// run runs a single check
+func run(ctx context.Context, env task.Environment, c Check) Result {
+	r := Result{Check: c}
+	if c.Env != "" && !setIn(env.Environ(nil), c.Env) {
+		r.Err = fmt.Errorf("$%s isn't set", c.Env)
+		return r
+	}
+	if c.Binary == "" {
+		return r
+	}
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+	args := c.VersionArgs
+	if len(args) == 0 {
+		args = []string{"--version"}
+	}
+	cmd := env.Command(ctx, nil, append([]string{c.Binary}, args...)...)
+	if cmd.Err != nil {
+		r.Err = fmt.Errorf("%s isn't installed", c.Binary)
+		return r
+	}
+	r.Detail = cmd.Path
+	if c.MinVersion == "" {
+		return r
+	}
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		r.Err = fmt.Errorf("unable to get the version of %s: %w", c.Binary, err)
+		return r
+	}
+	version := versionPattern.FindString(string(out))
+	if version == "" {
+		version = numberPattern.FindString(string(out))
+	}
+	if version == "" {
+		r.Err = fmt.Errorf("no version in the output of %s %s", c.Binary, strings.Join(args, " "))
+		return r
+	}
+	r.Detail = version
+	if parseVersion(version).Less(parseVersion(c.MinVersion)) {
+		r.Err = fmt.Errorf("%s %s is older than %s", c.Binary, version, c.MinVersion)
+	}
+	return r
+}
+
+// setIn reports whether the variable name is set to a value that isn't empty in environ
+func setIn(environ []string, name string) bool {
+	for _, kv := range environ {
+		if n, v, _ := strings.Cut(kv, "="); n == name && v != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// versionPattern matches the first dotted version in a binary's output, e.g. "1.22.3" in
+// "go version go1.22.3 linux/amd64", and numberPattern the first number, for binaries reporting
+// a plain version
+var (
+	versionPattern = regexp.MustCompile(`\d+(\.\d+)+`)
+	numberPattern  = regexp.MustCompile(`\d+`)
+)
+
+// parseVersion reads the first three parts of a dotted version, e.g. "v1.22", a missing part
+// counting as 0
+func parseVersion(s string) task.Version {
+	var parts [3]int
+	for i, p := range strings.SplitN(strings.TrimPrefix(s, "v"), ".", 4) {
+		if i == len(parts) {
+			break
+		}
+		parts[i], _ = strconv.Atoi(p)
+	}
+	return task.Version{Major: parts[0], Minor: parts[1], Patch: parts[2]}
+}
@@ -0,0 +1,84 @@
+package preflight
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/task"
+)
+
+func TestChecks(t *testing.T) {
+	checks, err := Checks([]config.PreflightCheck{
+		{Binary: "go", MinVersion: "1.22"},
+		{Name: "AWS credentials", Env: "AWS_PROFILE", Tasks: []string{"deploy:*"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checks) != 2 || checks[0].Label() != "go 1.22 or later" || checks[1].Label() != "AWS credentials" {
+		t.Errorf("Expected both checks, got %+v", checks)
+	}
+	if checks[1].Applies("build") || !checks[1].Applies("deploy:prod") || !checks[0].Applies("build") {
+		t.Error("Expected checks to apply to the tasks they name, or to every task")
+	}
+
+	if _, err := Checks([]config.PreflightCheck{{MinVersion: "1"}}); err == nil || !strings.Contains(err.Error(), "check 1") {
+		t.Errorf("Expected an error naming the invalid check, got %v", err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+	bin := t.TempDir()
+	os.WriteFile(filepath.Join(bin, "tool"), []byte("#!/bin/sh\necho 'tool version v2.4.1 (build 7)'\n"), 0o755)
+	t.Setenv("TASH_TEST_SET", "1")
+	env := task.Environment{PathPrefix: []string{bin}}
+
+	results := Run(context.Background(), env, []Check{
+		{Binary: "tool", MinVersion: "2.4"},
+		{Binary: "tool", MinVersion: "2.10"},
+		{Binary: "tash-no-such-binary"},
+		{Env: "TASH_TEST_SET"},
+		{Env: "TASH_TEST_UNSET"},
+	})
+
+	wantErr := []string{"", "tool 2.4.1 is older than 2.10", "tash-no-such-binary isn't installed", "", "$TASH_TEST_UNSET isn't set"}
+	for i, r := range results {
+		got := ""
+		if r.Err != nil {
+			got = r.Err.Error()
+		}
+		if got != wantErr[i] {
+			t.Errorf("Check %d: expected error %q, got %q", i, wantErr[i], got)
+		}
+	}
+	if results[0].Detail != "2.4.1" {
+		t.Errorf("Expected the version found, got %q", results[0].Detail)
+	}
+	if failed := Failed(results, "build"); len(failed) != 3 {
+		t.Errorf("Expected 3 failed checks, got %d", len(failed))
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	for _, c := range []struct {
+		a, b string
+		less bool
+	}{
+		{"1.22.3", "1.22", false},
+		{"1.9", "1.10", true},
+		{"v3", "3.0.0", false},
+		{"3.0.0", "v3.0.1", true},
+	} {
+		if got := parseVersion(c.a).Less(parseVersion(c.b)); got != c.less {
+			t.Errorf("%q older than %q = %v, want %v", c.a, c.b, got, c.less)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Package problems extracts compiler errors, test failures and lint findings from task output
+// with problem matchers: regular expressions capturing the file, line and message of a problem.
+package problems
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Problem is an issue reported in a line of task output
+type Problem struct {
+	Matcher  string
+	File     string
+	Line     int
+	Column   int
+	Severity string
+	Message  string
+	// Task is the task whose output reported the problem
+	Task string
+	// OutputLine is the index of the output line reporting the problem
+	OutputLine int
+}
+
+// Location returns the problem's position in the form file:line:column
+func (p Problem) Location() string {
+	loc := p.File
+	if p.Line > 0 {
+		loc += ":" + strconv.Itoa(p.Line)
+		if p.Column > 0 {
+			loc += ":" + strconv.Itoa(p.Column)
+		}
+	}
+	return loc
+}
+
+// Matcher recognises problems with a regular expression. The named groups file, line, column,
+// severity and message capture the parts of a problem; file is required.
+type Matcher struct {
+	Name    string
+	pattern *regexp.Regexp
+}
+
+// New compiles a problem matcher
+func New(name, pattern string) (Matcher, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return Matcher{}, fmt.Errorf("problem matcher %s: %w", name, err)
+	}
+	if re.SubexpIndex("file") < 0 {
+		return Matcher{}, fmt.Errorf("problem matcher %s: pattern has no (?P<file>...) group", name)
+	}
+	return Matcher{Name: name, pattern: re}, nil
+}
+
+// defaultPatterns recognise the output of common tools when no matchers are configured
+var defaultPatterns = []struct{ name, pattern string }{
+	// gcc, clang, rustc's short format and many linters: file:line:col: severity: message
+	{"gcc", `^(?P<file>[^\s:][^:]*):(?P<line>\d+):(?P<column>\d+): (?P<severity>fatal error|error|warning|note): (?P<message>.*)$`},
+	// go build, go vet and go test failures: file.go:line:col: message
+	{"go", `^\s*(?P<file>[^\s:]+\.go):(?P<line>\d+)(?::(?P<column>\d+))?: (?P<message>.*)$`},
+	// tsc: file(line,col): error TS1234: message
+	{"tsc", `^(?P<file>[^\s(]+)\((?P<line>\d+),(?P<column>\d+)\): (?P<severity>error|warning) (?P<message>.*)$`},
+}
+
+// Defaults returns the built-in matchers
+func Defaults() []Matcher {
+	var matchers []Matcher
+	for _, d := range defaultPatterns {
+		m, err := New(d.name, d.pattern)
+		if err != nil {
+			panic(err)
+		}
+		matchers = append(matchers, m)
+	}
+	return matchers
+}
+
+// Match returns the problem reported by line, trying each matcher in turn
+func Match(matchers []Matcher, line string) (Problem, bool) {
+	for _, m := range matchers {
+		if p, ok := m.Match(line); ok {
+			return p, true
+		}
+	}
+	return Problem{}, false
+}
+
+// Match returns the problem reported by line, if the matcher recognises it
+func (m Matcher) Match(line string) (Problem, bool) {
+	match := m.pattern.FindStringSubmatch(strings.TrimRight(line, "\r\n"))
+	if match == nil {
+		return Problem{}, false
+	}
+	group := func(name string) string {
+		if i := m.pattern.SubexpIndex(name); i >= 0 {
+			return match[i]
+		}
+		return ""
+	}
+	p := Problem{
+		Matcher:  m.Name,
+		File:     group("file"),
+		Severity: strings.ToLower(group("severity")),
+		Message:  strings.TrimSpace(group("message")),
+	}
+	if p.File == "" {
+		return Problem{}, false
+	}
+	p.Line, _ = strconv.Atoi(group("line"))
+	p.Column, _ = strconv.Atoi(group("column"))
+	if p.Severity == "" {
+		p.Severity = "error"
+	}
+	return p, true
+}
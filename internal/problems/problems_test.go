@@ -0,0 +1,50 @@
+package problems
+
+import (
+	"testing"
+)
+
+func TestDefaultsMatchCommonTools(t *testing.T) {
+	tests := []struct {
+		line string
+		want Problem
+	}{
+		{"main.go:12:5: undefined: foo", Problem{Matcher: "go", File: "main.go", Line: 12, Column: 5, Severity: "error", Message: "undefined: foo"}},
+		{"    handler_test.go:40: expected 2, got 3", Problem{Matcher: "go", File: "handler_test.go", Line: 40, Severity: "error", Message: "expected 2, got 3"}},
+		{"src/app.c:3:10: warning: unused variable 'x'", Problem{Matcher: "gcc", File: "src/app.c", Line: 3, Column: 10, Severity: "warning", Message: "unused variable 'x'"}},
+		{"src/index.ts(7,14): error TS2322: Type 'string' is not assignable", Problem{Matcher: "tsc", File: "src/index.ts", Line: 7, Column: 14, Severity: "error", Message: "TS2322: Type 'string' is not assignable"}},
+	}
+	matchers := Defaults()
+	for _, tt := range tests {
+		got, ok := Match(matchers, tt.line)
+		if !ok {
+			t.Errorf("Expected a problem in %q", tt.line)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Expected %+v for %q, got %+v", tt.want, tt.line, got)
+		}
+	}
+	for _, line := range []string{"building", "ok  	github.com/x/y	0.1s", "task: [build] go build ./..."} {
+		if p, ok := Match(matchers, line); ok {
+			t.Errorf("Expected no problem in %q, got %+v", line, p)
+		}
+	}
+}
+
+func TestNewRequiresFileGroup(t *testing.T) {
+	if _, err := New("bad", `^(?P<line>\d+)`); err == nil {
+		t.Error("Expected a pattern without a file group to be refused")
+	}
+	if _, err := New("bad", `(`); err == nil {
+		t.Error("Expected an invalid pattern to be refused")
+	}
+	m, err := New("pytest", `^(?P<file>\S+\.py):(?P<line>\d+): (?P<message>.*)$`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, ok := m.Match("tests/test_app.py:17: AssertionError")
+	if !ok || p.Location() != "tests/test_app.py:17" || p.Message != "AssertionError" {
+		t.Errorf("Expected the pytest failure, got %+v", p)
+	}
+}
@@ -0,0 +1,175 @@
+// Package queue reads runs requested by dropping JSON files into a directory, a dead-simple way
+// for cron jobs and other tools to have the daemon run tasks. A job is claimed by moving it to
+// running/, and once it has run it is moved to done/ or failed/ along with its result.
+package queue
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Subdirectories of the queue holding the claimed and finished jobs
+const (
+	RunningDir = "running"
+	DoneDir    = "done"
+	FailedDir  = "failed"
+)
+
+// Job is a run requested by a file of the queue, e.g. {"task": "deploy", "vars": ["ENV=prod"]}
+type Job struct {
+	Task string   `json:"task"`
+	Vars []string `json:"vars,omitempty"`
+	// Result is added to the files moved to done/ and failed/
+	Result *Result `json:"result,omitempty"`
+
+	name string // File name of the job
+}
+
+// Name returns the file name of the job
+func (j Job) Name() string {
+	return j.name
+}
+
+// Result is how a job went
+type Result struct {
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Success bool      `json:"success"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// Queue is the directory jobs are dropped into. Tools should write a job under another name,
+// e.g. ending in .tmp, and rename it to end in .json, so half written jobs aren't picked up.
+type Queue struct {
+	Dir string
+}
+
+// Next claims the oldest job of the queue, by modification time then name, and returns it with
+// ok false when the queue is empty. Jobs that can't be read are moved to failed/ with the
+// reason and skipped.
+func (q Queue) Next() (job Job, ok bool, err error) {
+	entries, err := os.ReadDir(q.Dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Job{}, false, nil
+		}
+		return Job{}, false, err
+	}
+	type pending struct {
+		name    string
+		modTime time.Time
+	}
+	var jobs []pending
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue // removed since listed
+		}
+		jobs = append(jobs, pending{e.Name(), info.ModTime()})
+	}
+	slices.SortFunc(jobs, func(a, b pending) int {
+		if c := a.modTime.Compare(b.modTime); c != 0 {
+			return c
+		}
+		return strings.Compare(a.name, b.name)
+	})
+	for _, p := range jobs {
+		job, err := q.claim(p.name)
+		if errors.Is(err, os.ErrNotExist) {
+			continue // claimed by someone else
+		}
+		if err != nil {
+			return Job{}, false, err
+		}
+		if job.Task == "" {
+			now := time.Now()
+			if err := q.Finish(job, Result{Start: now, End: now, Error: job.Result.Error}); err != nil {
+				return Job{}, false, err
+			}
+			continue
+		}
+		return job, true, nil
+	}
+	return Job{}, false, nil
+}
+
+// claim moves the job named name to running/ and reads it. A job that can't be read is
+// returned without a task, with the reason in its result.
+func (q Queue) claim(name string) (Job, error) {
+	running := filepath.Join(q.Dir, RunningDir)
+	if err := os.MkdirAll(running, 0o755); err != nil {
+		return Job{}, err
+	}
+	path := filepath.Join(running, name)
+	if err := os.Rename(filepath.Join(q.Dir, name), path); err != nil {
+		return Job{}, err
+	}
+	job := Job{name: name}
+	data, err := os.ReadFile(path)
+	if err == nil {
+		err = json.Unmarshal(data, &job)
+	}
+	switch {
+	case err != nil:
+		job = Job{name: name, Result: &Result{Error: "invalid job: " + err.Error()}}
+	case job.Task == "":
+		job = Job{name: name, Result: &Result{Error: "the job names no task"}}
+	}
+	job.name = name
+	return job, nil
+}
+
+// Finish moves a claimed job to done/ or failed/, depending on result, recording the result in it
+func (q Queue) Finish(job Job, result Result) error {
+	dir := filepath.Join(q.Dir, FailedDir)
+	if result.Success {
+		dir = filepath.Join(q.Dir, DoneDir)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	job.Result = &result
+	data, err := json.MarshalIndent(job, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, job.name), append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("unable to record the result of %s: %w", job.name, err)
+	}
+	return os.Remove(filepath.Join(q.Dir, RunningDir, job.name))
+}
+
+// Recover moves the jobs left in running/ by a daemon that stopped while running them to
+// failed/, so they aren't run twice
+func (q Queue) Recover() error {
+	entries, err := os.ReadDir(filepath.Join(q.Dir, RunningDir))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	var errs []error
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		job := Job{name: e.Name()}
+		if data, err := os.ReadFile(filepath.Join(q.Dir, RunningDir, e.Name())); err == nil {
+			json.Unmarshal(data, &job)
+			job.name = e.Name()
+		}
+		now := time.Now()
+		errs = append(errs, q.Finish(job, Result{Start: now, End: now, Error: "tash stopped while running the job"}))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,75 @@
+package queue
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeJob(t *testing.T, dir, name, content string, age time.Duration) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Now().Add(-age)
+	os.Chtimes(path, mtime, mtime)
+}
+
+func readResult(t *testing.T, path string) Job {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil || job.Result == nil {
+		t.Fatalf("Expected a job with its result, got %s", data)
+	}
+	return job
+}
+
+func TestQueue(t *testing.T) {
+	q := Queue{Dir: t.TempDir()}
+	if _, ok, err := q.Next(); ok || err != nil {
+		t.Fatalf("Expected an empty queue, got %v %v", ok, err)
+	}
+	writeJob(t, q.Dir, "b.json", `{"task": "test"}`, time.Minute)
+	writeJob(t, q.Dir, "a.json", `{"task": "build", "vars": ["V=1"]}`, 2*time.Minute)
+	writeJob(t, q.Dir, "broken.json", `{"task":`, 3*time.Minute)
+	writeJob(t, q.Dir, "c.json.tmp", `{"task": "lint"}`, time.Hour)
+
+	job, ok, err := q.Next()
+	if err != nil || !ok || job.Task != "build" || job.Vars[0] != "V=1" || job.Name() != "a.json" {
+		t.Fatalf("Expected the oldest valid job, got %+v %v %v", job, ok, err)
+	}
+	if _, err := os.Stat(filepath.Join(q.Dir, RunningDir, "a.json")); err != nil {
+		t.Errorf("Expected the job to be claimed: %v", err)
+	}
+	if failed := readResult(t, filepath.Join(q.Dir, FailedDir, "broken.json")); failed.Result.Error == "" {
+		t.Error("Expected the invalid job to fail with the reason")
+	}
+
+	if err := q.Finish(job, Result{End: time.Now(), Success: true}); err != nil {
+		t.Fatal(err)
+	}
+	if done := readResult(t, filepath.Join(q.Dir, DoneDir, "a.json")); done.Task != "build" || !done.Result.Success {
+		t.Errorf("Expected the job to be done, got %+v", done)
+	}
+
+	// a job left running by a stopped daemon fails rather than running twice
+	if job, _, _ = q.Next(); job.Task != "test" {
+		t.Fatalf("Expected the next job, got %+v", job)
+	}
+	if err := q.Recover(); err != nil {
+		t.Fatal(err)
+	}
+	if failed := readResult(t, filepath.Join(q.Dir, FailedDir, "b.json")); failed.Task != "test" || failed.Result.Success {
+		t.Errorf("Expected the interrupted job to fail, got %+v", failed)
+	}
+	if _, ok, _ := q.Next(); ok {
+		t.Error("Expected jobs not ending in .json to be left alone")
+	}
+}
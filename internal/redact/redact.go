@@ -0,0 +1,113 @@
+// Package redact masks secrets in task output before it is shown, logged or exported.
+package redact
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Mask replaces every redacted secret
+const Mask = "*****"
+
+// minSecretLength is the length below which the values of secret-looking variables aren't
+// masked, so a flag such as GITHUB_TOKEN_ENABLED=1 doesn't mask every "1" in the output
+const minSecretLength = 4
+
+// secretNames are the parts of variable names whose values are masked
+var secretNames = []string{"TOKEN", "SECRET", "PASSWORD"}
+
+// Redactor masks the matches of patterns and the values of secret variables in lines of output.
+// The nil Redactor leaves lines alone.
+type Redactor struct {
+	patterns []*regexp.Regexp
+	values   []string
+}
+
+// New returns a Redactor masking the matches of the regular expressions patterns; in patterns
+// with capture groups only the groups are masked, e.g. `token=(\S+)`. Invalid patterns are
+// reported, and the returned Redactor masks the matches of the others.
+func New(patterns []string) (*Redactor, error) {
+	r := &Redactor{}
+	var errs []error
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid redaction pattern %q: %w", p, err))
+			continue
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, errors.Join(errs...)
+}
+
+// WithEnv returns a copy of r also masking the values of the variables in the NAME=value
+// entries of envs whose names contain TOKEN, SECRET or PASSWORD, in any case
+func (r *Redactor) WithEnv(envs ...[]string) *Redactor {
+	if r == nil {
+		return nil
+	}
+	c := &Redactor{patterns: r.patterns, values: slices.Clone(r.values)}
+	for _, env := range envs {
+		for _, kv := range env {
+			name, value, _ := strings.Cut(kv, "=")
+			if len(value) >= minSecretLength && secretName(name) && !slices.Contains(c.values, value) {
+				c.values = append(c.values, value)
+			}
+		}
+	}
+	// longer values first, so a secret containing another is masked whole
+	slices.SortFunc(c.values, func(a, b string) int { return cmp.Compare(len(b), len(a)) })
+	return c
+}
+
+// secretName reports whether the values of the variable name are masked
+func secretName(name string) bool {
+	name = strings.ToUpper(name)
+	return slices.ContainsFunc(secretNames, func(s string) bool { return strings.Contains(name, s) })
+}
+
+// Redact returns line with its secrets replaced by Mask
+func (r *Redactor) Redact(line string) string {
+	if r == nil {
+		return line
+	}
+	for _, v := range r.values {
+		line = strings.ReplaceAll(line, v, Mask)
+	}
+	for _, re := range r.patterns {
+		line = redactMatches(re, line)
+	}
+	return line
+}
+
+// redactMatches masks the matches of re in line, or only their groups when re has any
+func redactMatches(re *regexp.Regexp, line string) string {
+	matches := re.FindAllStringSubmatchIndex(line, -1)
+	if matches == nil {
+		return line
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		spans := m[:2]
+		if re.NumSubexp() > 0 {
+			spans = m[2:]
+		}
+		for i := 0; i+1 < len(spans); i += 2 {
+			start, end := spans[i], spans[i+1]
+			// unmatched optional groups and groups nested in one already masked are skipped
+			if start < 0 || start < last {
+				continue
+			}
+			b.WriteString(line[last:start])
+			b.WriteString(Mask)
+			last = end
+		}
+	}
+	b.WriteString(line[last:])
+	return b.String()
+}
@@ -0,0 +1,36 @@
+package redact
+
+import "testing"
+
+func TestRedact(t *testing.T) {
+	r, err := New([]string{`ghp_[A-Za-z0-9]+`, `token=(\S+)`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r = r.WithEnv([]string{"HOME=/home/ann", "API_TOKEN=abc123", "db_password=hunter2", "FEATURE_SECRET_ON=1"})
+	for _, tt := range []struct {
+		line, want string
+	}{
+		{"pushing with ghp_0123abcd", "pushing with *****"},
+		{"curl -d token=xyz -d user=ann", "curl -d token=***** -d user=ann"},
+		{"Authorization: Bearer abc123", "Authorization: Bearer *****"},
+		{"connecting as ann:hunter2@db", "connecting as ann:*****@db"},
+		{"cd /home/ann with 1 job", "cd /home/ann with 1 job"},
+	} {
+		if got := r.Redact(tt.line); got != tt.want {
+			t.Errorf("Redact(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+
+	r, err = New([]string{"(", "ghp_\\w+"})
+	if err == nil {
+		t.Error("Expected the invalid pattern to be reported")
+	}
+	if got := r.Redact("ghp_abc"); got != Mask {
+		t.Errorf("Expected the valid pattern to be used, got %q", got)
+	}
+	var none *Redactor
+	if got := none.WithEnv([]string{"API_TOKEN=abc123"}).Redact("abc123"); got != "abc123" {
+		t.Errorf("Expected the nil Redactor to leave lines alone, got %q", got)
+	}
+}
@@ -0,0 +1,107 @@
+// Package retention removes the old run logs and history records from the data directory, so it
+// doesn't grow forever
+package retention
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Aj4x/tash/internal/history"
+	"github.com/Aj4x/tash/internal/runlog"
+)
+
+// Defaults of the policy, used when the config doesn't set a limit
+const (
+	DefaultMaxAge         = 30 * 24 * time.Hour
+	DefaultMaxSize        = 500 << 20
+	DefaultMaxRunsPerTask = 100
+)
+
+// Policy limits what is kept; a zero limit keeps everything
+type Policy struct {
+	// MaxAge removes the runs started longer ago, with their history records
+	MaxAge time.Duration
+	// MaxSize is the total size in bytes of the run logs kept, removing the oldest runs first
+	MaxSize int64
+	// MaxRunsPerTask is the number of the latest runs of each task kept, in the logs and in the
+	// history
+	MaxRunsPerTask int
+}
+
+// Result tells what a cleanup removed
+type Result struct {
+	Runs    int   // Runs whose log and metadata were removed
+	Bytes   int64 // Size of the removed run logs and metadata
+	Records int   // History records removed
+}
+
+// Clean removes the runs of runs and the records of hist that p doesn't keep at now. Runs in
+// progress are only removed once older than the maximum age, so a run of a crashed tash doesn't
+// stay forever.
+func Clean(runs runlog.Store, hist history.Store, p Policy, now time.Time) (Result, error) {
+	var res Result
+	var errs []error
+	logged, err := runs.List()
+	if err != nil {
+		errs = append(errs, err)
+	}
+	perTask := map[string]int{}
+	var size int64
+	full := false
+	// newest first, so the limits keep the latest runs
+	for i := len(logged) - 1; i >= 0; i-- {
+		md := logged[i]
+		runSize := runs.Size(md)
+		keep := !p.expired(md.Start, now)
+		if keep && md.End != nil {
+			perTask[md.Task]++
+			keep = p.MaxRunsPerTask <= 0 || perTask[md.Task] <= p.MaxRunsPerTask
+			if keep && p.MaxSize > 0 {
+				full = full || size+runSize > p.MaxSize
+				keep = !full
+			}
+		}
+		if keep {
+			size += runSize
+			continue
+		}
+		if err := runs.Remove(md); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		res.Runs++
+		res.Bytes += runSize
+	}
+
+	res.Records, err = hist.Prune(func(records []history.Record) []history.Record {
+		return p.keepRecords(records, now)
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return res, errors.Join(errs...)
+}
+
+// expired reports whether a run started at start is older than the maximum age at now
+func (p Policy) expired(start, now time.Time) bool {
+	return p.MaxAge > 0 && now.Sub(start) > p.MaxAge
+}
+
+// keepRecords returns the records p keeps at now, the latest records of each task being counted
+// from the end of the history
+func (p Policy) keepRecords(records []history.Record, now time.Time) []history.Record {
+	keep := make([]bool, len(records))
+	perTask := map[string]int{}
+	for i := len(records) - 1; i >= 0; i-- {
+		r := records[i]
+		perTask[r.Task]++
+		keep[i] = !p.expired(r.Start, now) && (p.MaxRunsPerTask <= 0 || perTask[r.Task] <= p.MaxRunsPerTask)
+	}
+	var kept []history.Record
+	for i, r := range records {
+		if keep[i] {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
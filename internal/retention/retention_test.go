@@ -0,0 +1,70 @@
+package retention
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Aj4x/tash/internal/history"
+	"github.com/Aj4x/tash/internal/runlog"
+)
+
+func TestClean(t *testing.T) {
+	dir := t.TempDir()
+	runs, hist := runlog.NewStore(dir), history.NewStore(dir)
+	now := time.Date(2024, 5, 31, 12, 0, 0, 0, time.UTC)
+	logRun := func(taskId string, age time.Duration, output string, finished bool) runlog.Metadata {
+		start := now.Add(-age)
+		run, err := runs.Start(runlog.Metadata{Task: taskId, Start: start})
+		if err != nil {
+			t.Fatal(err)
+		}
+		run.WriteLine(output)
+		if finished {
+			run.Finish(start.Add(time.Second), nil)
+			hist.Append(history.Record{Task: taskId, Start: start, End: start.Add(time.Second), Success: true, Log: run.Metadata().Log})
+		}
+		return run.Metadata()
+	}
+	logRun("build", 40*24*time.Hour, "too old", true)
+	stale := logRun("test", 40*24*time.Hour, "crashed", false)
+	logRun("build", 3*time.Hour, "dropped by the count", true)
+	logRun("build", 2*time.Hour, "dropped by the count", true)
+	lint := logRun("lint", 90*time.Minute, "dropped by the size", true)
+	maxSize := runs.Size(logRun("build", time.Hour, "kept", true)) + runs.Size(logRun("build", 30*time.Minute, "kept", true))
+	running := logRun("test", time.Minute, strings.Repeat("x", 2000), false)
+	// the run in progress counts towards the size
+	maxSize += runs.Size(running) + runs.Size(lint) - 1
+
+	res, err := Clean(runs, hist, Policy{MaxAge: 30 * 24 * time.Hour, MaxSize: maxSize, MaxRunsPerTask: 2}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Runs != 5 || res.Records != 3 {
+		t.Errorf("Expected 5 runs and 3 records removed, got %+v", res)
+	}
+	if _, err := os.Stat(stale.Log); !errors.Is(err, os.ErrNotExist) {
+		t.Error("Expected the log of a run older than the maximum age to be removed")
+	}
+	if _, err := os.Stat(lint.Path()); !errors.Is(err, os.ErrNotExist) {
+		t.Error("Expected the metadata of a run beyond the maximum size to be removed")
+	}
+
+	kept, _ := runs.List()
+	var got []string
+	for _, md := range kept {
+		got = append(got, md.Task+" "+md.Start.Format("15:04"))
+	}
+	if want := "build 11:00|build 11:30|test 11:59"; strings.Join(got, "|") != want {
+		t.Errorf("Expected the runs %s to be kept, got %s", want, strings.Join(got, "|"))
+	}
+	if kept[2].Log != running.Log {
+		t.Error("Expected the run in progress to be kept")
+	}
+	records, _ := hist.Load()
+	if len(records) != 3 || records[0].Task != "lint" || records[1].Task != "build" {
+		t.Errorf("Expected the latest records of each task to be kept, got %v", records)
+	}
+}
@@ -0,0 +1,216 @@
+// Package runlog keeps the output of each task run in a log file under the data directory, next to
+// a metadata file describing the run for external tooling
+package runlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Aj4x/tash/internal/task"
+)
+
+// DirName is the name of the directory of the run logs inside the data directory
+const DirName = "runs"
+
+// Version is the version of the metadata format, increased on incompatible changes
+const Version = 1
+
+// Metadata describes a run. It is written as <name>.json next to the log, <name>.log, when the run
+// starts and again when it ends.
+type Metadata struct {
+	Version int    `json:"version"`
+	Task    string `json:"task"`
+	// Args are the CLI args passed to the task after "--"
+	Args []string `json:"args,omitempty"`
+	// Vars are the variables passed to the task, NAME=value, with secrets masked
+	Vars []string `json:"vars,omitempty"`
+	// EnvProfile is the environment profile the task ran with
+	EnvProfile string    `json:"env_profile,omitempty"`
+	Dir        string    `json:"dir,omitempty"`
+	Start      time.Time `json:"start"`
+	// End and ExitCode are set once the run has ended
+	End      *time.Time `json:"end,omitempty"`
+	ExitCode *int       `json:"exit_code,omitempty"`
+	Error    string     `json:"error,omitempty"`
+	// Log is the path of the file holding the output of the run
+	Log string `json:"log"`
+}
+
+// Path returns the path of the metadata file of the run
+func (md Metadata) Path() string {
+	return strings.TrimSuffix(md.Log, ".log") + ".json"
+}
+
+// Store keeps run logs in a directory. A Store with an empty directory is disabled.
+type Store struct {
+	Dir string
+}
+
+// NewStore returns a store keeping the run logs in the data directory dataDir
+func NewStore(dataDir string) Store {
+	return Store{Dir: filepath.Join(dataDir, DirName)}
+}
+
+// Run is the log of a run in progress
+type Run struct {
+	md  Metadata
+	f   *os.File
+	out *bufio.Writer
+}
+
+// Start creates the log and the metadata file of a run; the task and start of md are required.
+// A disabled store returns a nil Run, which ignores output.
+func (s Store) Start(md Metadata) (*Run, error) {
+	if s.Dir == "" {
+		return nil, nil
+	}
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create the run log directory: %w", err)
+	}
+	base := md.Start.UTC().Format("20060102T150405.000Z") + "-" + fileName(md.Task)
+	var f *os.File
+	var err error
+	for i := 1; ; i++ {
+		name := base
+		if i > 1 {
+			name = fmt.Sprintf("%s-%d", base, i)
+		}
+		f, err = os.OpenFile(filepath.Join(s.Dir, name+".log"), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if !errors.Is(err, os.ErrExist) {
+			break
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the run log: %w", err)
+	}
+	md.Version = Version
+	md.Log = f.Name()
+	r := &Run{md: md, f: f, out: bufio.NewWriter(f)}
+	if err := r.writeMetadata(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+// fileName turns a task id into a part of a file name
+func fileName(taskId string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`:/\*?"<>| `, r) {
+			return '-'
+		}
+		return r
+	}, taskId)
+}
+
+// WriteLine appends a line of output to the log
+func (r *Run) WriteLine(line string) error {
+	if r == nil {
+		return nil
+	}
+	_, err := r.out.WriteString(strings.TrimSuffix(line, "\n") + "\n")
+	return err
+}
+
+// Finish closes the log and records the end and the outcome of the run, runErr being why it
+// failed
+func (r *Run) Finish(end time.Time, runErr error) error {
+	if r == nil {
+		return nil
+	}
+	err := r.out.Flush()
+	if closeErr := r.f.Close(); err == nil {
+		err = closeErr
+	}
+	code := task.ExitCode(runErr)
+	r.md.End, r.md.ExitCode = &end, &code
+	if runErr != nil {
+		r.md.Error = runErr.Error()
+	}
+	return errors.Join(err, r.writeMetadata())
+}
+
+// Metadata returns the metadata of the run
+func (r *Run) Metadata() Metadata {
+	return r.md
+}
+
+// writeMetadata replaces the metadata file of the run
+func (r *Run) writeMetadata() error {
+	data, err := json.MarshalIndent(r.md, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := r.md.Path()
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("unable to write the run metadata: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("unable to write the run metadata: %w", err)
+	}
+	return nil
+}
+
+// List returns the metadata of the runs in the store, oldest first, skipping malformed files. A
+// missing directory yields no runs.
+func (s Store) List() ([]Metadata, error) {
+	if s.Dir == "" {
+		return nil, nil
+	}
+	paths, err := filepath.Glob(filepath.Join(s.Dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var runs []Metadata
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var md Metadata
+		if err := json.Unmarshal(data, &md); err != nil || md.Task == "" {
+			continue
+		}
+		runs = append(runs, md)
+	}
+	slices.SortStableFunc(runs, func(a, b Metadata) int { return a.Start.Compare(b.Start) })
+	return runs, nil
+}
+
+// Size returns the size in bytes of the log and the metadata file of the run md of the store
+func (s Store) Size(md Metadata) int64 {
+	var size int64
+	for _, path := range s.files(md) {
+		if info, err := os.Stat(path); err == nil {
+			size += info.Size()
+		}
+	}
+	return size
+}
+
+// Remove deletes the log and the metadata file of the run md of the store
+func (s Store) Remove(md Metadata) error {
+	var errs []error
+	for _, path := range s.files(md) {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// files returns the paths of the log and the metadata file of the run md in the store, which
+// stay in the store when the data directory moved
+func (s Store) files(md Metadata) []string {
+	log := filepath.Join(s.Dir, filepath.Base(md.Log))
+	return []string{log, strings.TrimSuffix(log, ".log") + ".json"}
+}
@@ -0,0 +1,82 @@
+package runlog
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRunWritesTheLogAndTheMetadata(t *testing.T) {
+	store := NewStore(t.TempDir())
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	run, err := store.Start(Metadata{Task: "db:migrate", Args: []string{"up"}, EnvProfile: "staging", Start: start})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A second run starting at the same time gets its own files
+	other, err := store.Start(Metadata{Task: "db:migrate", Start: start})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Metadata().Log == other.Metadata().Log {
+		t.Fatal("Expected each run to have its own log")
+	}
+
+	runs, err := store.List()
+	if err != nil || len(runs) != 2 || runs[0].End != nil {
+		t.Fatalf("Expected the runs in progress to be listed, got %v, %v", runs, err)
+	}
+
+	run.WriteLine("migrating\n")
+	run.WriteLine("failed")
+	if err := run.Finish(start.Add(time.Second), errors.New("exit status 2")); err != nil {
+		t.Fatal(err)
+	}
+	md := run.Metadata()
+	if filepath.Base(md.Log) != "20240501T100000.000Z-db-migrate.log" {
+		t.Errorf("Unexpected log name %s", md.Log)
+	}
+	if data, _ := os.ReadFile(md.Log); string(data) != "migrating\nfailed\n" {
+		t.Errorf("Unexpected log %q", data)
+	}
+
+	data, err := os.ReadFile(md.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"version": 1.0, "task": "db:migrate", "args": []any{"up"}, "env_profile": "staging",
+		"start": "2024-05-01T10:00:00Z", "end": "2024-05-01T10:00:01Z", "exit_code": 1.0,
+		"error": "exit status 2", "log": md.Log,
+	}
+	for key, value := range want {
+		if gotJSON, wantJSON := toJSON(got[key]), toJSON(value); gotJSON != wantJSON {
+			t.Errorf("Expected %s to be %s, got %s", key, wantJSON, gotJSON)
+		}
+	}
+}
+
+func TestDisabledStore(t *testing.T) {
+	run, err := Store{}.Start(Metadata{Task: "build", Start: time.Now()})
+	if run != nil || err != nil {
+		t.Fatalf("Expected no run, got %v, %v", run, err)
+	}
+	if err := run.WriteLine("ignored"); err != nil {
+		t.Error(err)
+	}
+	if err := run.Finish(time.Now(), nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func toJSON(v any) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
@@ -0,0 +1,77 @@
+// Package runopts persists the options a task was last run with from the execution options
+// overlay, so they are offered again for its next run.
+package runopts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// FileName is the name of the run options file inside the data directory
+const FileName = "run_options.json"
+
+// Options are the one-off settings of a task run
+type Options struct {
+	Force    bool   `json:"force,omitempty"`
+	Dry      bool   `json:"dry,omitempty"`
+	Verbose  bool   `json:"verbose,omitempty"`
+	Watch    bool   `json:"watch,omitempty"`
+	Parallel bool   `json:"parallel,omitempty"`
+	Profile  string `json:"profile,omitempty"`
+	Args     string `json:"args,omitempty"`
+	Timeout  string `json:"timeout,omitempty"`
+}
+
+// Store persists the last options of each task as a JSON object keyed by task id. A Store with
+// an empty path is disabled.
+type Store struct {
+	Path string
+}
+
+// NewStore returns a store using the run options file in dir
+func NewStore(dir string) Store {
+	return Store{Path: filepath.Join(dir, FileName)}
+}
+
+// Load reads the options of every task. A missing file yields no options.
+func (s Store) Load() (map[string]Options, error) {
+	options := map[string]Options{}
+	if s.Path == "" {
+		return options, nil
+	}
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return options, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read run options: %w", err)
+	}
+	if err := json.Unmarshal(data, &options); err != nil {
+		return nil, fmt.Errorf("unable to parse run options: %w", err)
+	}
+	return options, nil
+}
+
+// Save records opts as the last options of taskId
+func (s Store) Save(taskId string, opts Options) error {
+	if s.Path == "" {
+		return nil
+	}
+	options, err := s.Load()
+	if err != nil {
+		// start over rather than failing on a corrupt file
+		options = map[string]Options{}
+	}
+	options[taskId] = opts
+	data, err := json.MarshalIndent(options, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o755); err != nil {
+		return fmt.Errorf("unable to create run options directory: %w", err)
+	}
+	return os.WriteFile(s.Path, append(data, '\n'), 0o644)
+}
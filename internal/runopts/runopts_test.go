@@ -0,0 +1,50 @@
+package runopts
+
+import (
+	"os"
+	"testing"
+)
+
+func TestStoreSaveAndLoad(t *testing.T) {
+	store := NewStore(t.TempDir())
+	if options, err := store.Load(); err != nil || len(options) != 0 {
+		t.Fatalf("Expected no options before saving, got %v, %v", options, err)
+	}
+
+	if err := store.Save("build", Options{Force: true, Args: "-v ./..."}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := store.Save("deploy", Options{Timeout: "5m", Profile: "staging"}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := store.Save("build", Options{Dry: true}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	options, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := options["build"]; got != (Options{Dry: true}) {
+		t.Errorf("Expected the last options of build, got %+v", got)
+	}
+	if got := options["deploy"]; got.Timeout != "5m" || got.Profile != "staging" {
+		t.Errorf("Expected the options of deploy to be kept, got %+v", got)
+	}
+}
+
+func TestStoreSaveReplacesCorruptFile(t *testing.T) {
+	store := NewStore(t.TempDir())
+	if err := os.WriteFile(store.Path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Load(); err == nil {
+		t.Error("Expected an error loading a corrupt file")
+	}
+	if err := store.Save("build", Options{Watch: true}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if options, err := store.Load(); err != nil || !options["build"].Watch {
+		t.Errorf("Expected the file to be rewritten, got %v, %v", options, err)
+	}
+}
@@ -0,0 +1,137 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronField is the set of allowed values for one field of a cron expression
+type cronField map[int]bool
+
+// Cron is a schedule defined by a standard five-field cron expression
+// (minute, hour, day of month, month, day of week)
+type Cron struct {
+	expr                          string
+	minute, hour, dom, month, dow cronField
+	domRestricted, dowRestricted  bool
+}
+
+// cronBounds holds the minimum and maximum values of each cron field, in order
+var cronBounds = [5][2]int{
+	{0, 59}, // minute
+	{0, 23}, // hour
+	{1, 31}, // day of month
+	{1, 12}, // month
+	{0, 6},  // day of week (0 = Sunday)
+}
+
+// ParseCron parses a five-field cron expression. Each field supports "*", single values,
+// ranges ("1-5"), lists ("1,15") and steps ("*/10", "0-30/5").
+func ParseCron(expr string) (Cron, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return Cron{}, fmt.Errorf("%w: expected 5 fields, got %d", ErrInvalidCron, len(fields))
+	}
+	var parsed [5]cronField
+	for i, f := range fields {
+		field, err := parseCronField(f, cronBounds[i][0], cronBounds[i][1])
+		if err != nil {
+			return Cron{}, fmt.Errorf("%w: field %q: %w", ErrInvalidCron, f, err)
+		}
+		parsed[i] = field
+	}
+	return Cron{
+		expr:          expr,
+		minute:        parsed[0],
+		hour:          parsed[1],
+		dom:           parsed[2],
+		month:         parsed[3],
+		dow:           parsed[4],
+		domRestricted: fields[2] != "*",
+		dowRestricted: fields[4] != "*",
+	}, nil
+}
+
+func parseCronField(f string, min, max int) (cronField, error) {
+	field := cronField{}
+	for _, part := range strings.Split(f, ",") {
+		rangeStr, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("invalid step %q", stepStr)
+			}
+			step = n
+		}
+		lo, hi := min, max
+		if rangeStr != "*" {
+			loStr, hiStr, isRange := strings.Cut(rangeStr, "-")
+			n, err := strconv.Atoi(loStr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value %q", loStr)
+			}
+			lo, hi = n, n
+			if isRange {
+				if hi, err = strconv.Atoi(hiStr); err != nil {
+					return nil, fmt.Errorf("invalid value %q", hiStr)
+				}
+			} else if hasStep {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return nil, fmt.Errorf("value out of range %d-%d", min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			field[v] = true
+		}
+	}
+	return field, nil
+}
+
+// dayMatches applies cron's rule that when both day of month and day of week are
+// restricted, a day matching either of them is accepted
+func (c Cron) dayMatches(t time.Time) bool {
+	domMatch := c.dom[t.Day()]
+	dowMatch := c.dow[int(t.Weekday())]
+	if c.domRestricted && c.dowRestricted {
+		return domMatch || dowMatch
+	}
+	return domMatch && dowMatch
+}
+
+// Next returns the first time after t matching the expression, or the zero time if none is found within five years
+func (c Cron) Next(t time.Time) time.Time {
+	next := t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for next.Before(limit) {
+		switch {
+		case !c.month[int(next.Month())]:
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, next.Location())
+		case !c.dayMatches(next):
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, next.Location())
+		case !c.hour[next.Hour()]:
+			// the next hour on the wall clock; truncating to the hour would land on :30 or :45
+			// in zones whose offset isn't whole hours
+			hour := time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, next.Location())
+			if !hour.After(next) {
+				// never step back, whatever a daylight saving change makes of the wall clock
+				hour = next.Add(time.Hour)
+			}
+			next = hour
+		case !c.minute[next.Minute()]:
+			next = next.Add(time.Minute)
+		default:
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+// String returns the original cron expression
+func (c Cron) String() string {
+	return c.expr
+}
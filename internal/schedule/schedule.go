@@ -0,0 +1,81 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Error represents a textual error value that implements the error interface.
+type Error string
+
+// Error returns the string representation of the Error type. It satisfies the error interface.
+func (e Error) Error() string {
+	return string(e)
+}
+
+// ErrInvalidCron is wrapped by errors returned for malformed cron expressions
+// ErrInvalidSpec is wrapped by errors returned for schedule specs that are neither a duration nor a cron expression
+const (
+	ErrInvalidCron = Error("invalid cron expression")
+	ErrInvalidSpec = Error("invalid schedule")
+)
+
+// Schedule computes when a scheduled task should next run
+type Schedule interface {
+	// Next returns the next run time strictly after t
+	Next(t time.Time) time.Time
+	String() string
+}
+
+// Every is a schedule that runs at a fixed interval
+type Every time.Duration
+
+// Next returns t plus the interval
+func (e Every) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
+
+// String returns a description of the interval
+func (e Every) String() string {
+	return "every " + time.Duration(e).String()
+}
+
+// Parse parses a schedule spec: either a Go duration ("10m", "1h30m"), optionally prefixed
+// with "every ", or a five-field cron expression
+func Parse(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if d, err := time.ParseDuration(strings.TrimPrefix(spec, "every ")); err == nil {
+		if d < time.Second {
+			return nil, fmt.Errorf("%w: interval must be at least 1s", ErrInvalidSpec)
+		}
+		return Every(d), nil
+	}
+	if len(strings.Fields(spec)) == 5 {
+		return ParseCron(spec)
+	}
+	return nil, fmt.Errorf("%w: %q is neither a duration nor a cron expression", ErrInvalidSpec, spec)
+}
+
+// Entry is a task scheduled to run repeatedly
+type Entry struct {
+	TaskId   string
+	Schedule Schedule
+	NextRun  time.Time
+}
+
+// NewEntry creates an entry for taskId whose first run is computed from now
+func NewEntry(taskId string, s Schedule, now time.Time) Entry {
+	return Entry{TaskId: taskId, Schedule: s, NextRun: s.Next(now)}
+}
+
+// Due reports whether the entry should run at now
+func (e Entry) Due(now time.Time) bool {
+	return !e.NextRun.IsZero() && !now.Before(e.NextRun)
+}
+
+// Advance moves the entry's next run past now
+func (e Entry) Advance(now time.Time) Entry {
+	e.NextRun = e.Schedule.Next(now)
+	return e
+}
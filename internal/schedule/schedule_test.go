@@ -0,0 +1,68 @@
+package schedule
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseEvery(t *testing.T) {
+	s, err := Parse("every 10m")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	if next := s.Next(now); !next.Equal(now.Add(10 * time.Minute)) {
+		t.Errorf("Expected next run at 12:10, got %s", next)
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	// zones whose offset from UTC isn't a whole number of hours
+	kolkata := time.FixedZone("IST", 5*3600+30*60)
+	kathmandu := time.FixedZone("NPT", 5*3600+45*60)
+	tests := []struct {
+		expr     string
+		from     time.Time
+		expected time.Time
+	}{
+		{"*/15 * * * *", time.Date(2024, 1, 1, 12, 7, 30, 0, time.UTC), time.Date(2024, 1, 1, 12, 15, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC), time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC)},
+		{"30 2 1 * *", time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 1, 2, 30, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 9 * * *", time.Date(2024, 1, 1, 8, 10, 0, 0, kolkata), time.Date(2024, 1, 1, 9, 0, 0, 0, kolkata)},
+		{"15 */6 * * *", time.Date(2024, 1, 1, 7, 0, 0, 0, kathmandu), time.Date(2024, 1, 1, 12, 15, 0, 0, kathmandu)},
+	}
+	for _, tt := range tests {
+		c, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseCron(%q) error = %v", tt.expr, err)
+		}
+		if next := c.Next(tt.from); !next.Equal(tt.expected) {
+			t.Errorf("ParseCron(%q).Next(%s): expected %s, got %s", tt.expr, tt.from, tt.expected, next)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, spec := range []string{"soon", "61 * * * *", "* * *", "*/0 * * * *"} {
+		if _, err := Parse(spec); !errors.Is(err, ErrInvalidCron) && !errors.Is(err, ErrInvalidSpec) {
+			t.Errorf("Parse(%q): expected an invalid schedule error, got %v", spec, err)
+		}
+	}
+}
+
+func TestEntryDue(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	e := NewEntry("lint", Every(time.Minute), now)
+	if e.Due(now) {
+		t.Error("Expected entry not to be due immediately")
+	}
+	later := now.Add(time.Minute)
+	if !e.Due(later) {
+		t.Error("Expected entry to be due after its interval")
+	}
+	if e.Advance(later).Due(later) {
+		t.Error("Expected advanced entry not to be due")
+	}
+}
@@ -0,0 +1,167 @@
+package task
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// ProviderTask is the name of the provider backed by the go-task binary
+const ProviderTask = "task"
+
+// ParseTasksJson parses the output of "task --list-all --json" into a slice of tasks
+func ParseTasksJson(jsonStr string) ([]Task, error) {
+	var t struct {
+		Tasks []Task `json:"tasks"`
+	}
+	err := json.Unmarshal([]byte(jsonStr), &t)
+	if err != nil {
+		return nil, err
+	}
+	return t.Tasks, nil
+}
+
+// listBatchSize is how many tasks are sent together while a listing is read
+const listBatchSize = 50
+
+// taskBatcher collects tasks and passes them on in batches of listBatchSize
+type taskBatcher struct {
+	batch   []Task
+	onBatch func([]Task)
+}
+
+func (b *taskBatcher) add(t Task) {
+	b.batch = append(b.batch, t)
+	if len(b.batch) >= listBatchSize {
+		b.flush()
+	}
+}
+
+func (b *taskBatcher) flush() {
+	if len(b.batch) > 0 {
+		b.onBatch(b.batch)
+		b.batch = nil
+	}
+}
+
+// streamTasksJson decodes the tasks of a JSON listing as they are read from r, passing them to
+// onBatch in batches
+func streamTasksJson(r io.Reader, onBatch func([]Task)) error {
+	b := taskBatcher{onBatch: onBatch}
+	defer b.flush()
+	dec := json.NewDecoder(r)
+	if tok, err := dec.Token(); err != nil {
+		return err
+	} else if tok != json.Delim('{') {
+		return fmt.Errorf("unexpected %v at the start of the task listing", tok)
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if key != "tasks" {
+			var skipped json.RawMessage
+			if err := dec.Decode(&skipped); err != nil {
+				return err
+			}
+			continue
+		}
+		if tok, err := dec.Token(); err != nil {
+			return err
+		} else if tok != json.Delim('[') {
+			return fmt.Errorf("unexpected %v instead of the tasks of the listing", tok)
+		}
+		for dec.More() {
+			var t Task
+			if err := dec.Decode(&t); err != nil {
+				return err
+			}
+			b.add(t)
+		}
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// streamTaskList parses the text listing of older task versions as lines are read from r,
+// passing the tasks to onBatch in batches
+func streamTaskList(r io.Reader, onBatch func([]Task)) {
+	b := taskBatcher{onBatch: onBatch}
+	defer b.flush()
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if t, ok := ParseTaskLine(scanner.Text()); ok {
+			b.add(t)
+		}
+	}
+}
+
+// ParseTaskList parses the text output of "task --list-all", skipping lines that are not tasks
+func ParseTaskList(output string) []Task {
+	var tasks []Task
+	for _, line := range strings.Split(output, "\n") {
+		if t, ok := ParseTaskLine(line); ok {
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks
+}
+
+// listingJson returns the listing produced by running task with args as JSON, converting the
+// text listing of older task versions
+func listingJson(output string, args []string) (string, error) {
+	if slices.Contains(args, "--json") {
+		return output, nil
+	}
+	b, err := json.Marshal(struct {
+		Tasks []Task `json:"tasks"`
+	}{ParseTaskList(output)})
+	return string(b), err
+}
+
+// ListAll synchronously lists all tasks in env, as JSON when the installed task supports it, and
+// returns the parsed tasks. It is intended for headless use where no message bus is available.
+func ListAll(env Environment) ([]Task, error) {
+	args := listArgs(env)
+	cmd := env.Command(context.Background(), nil, append([]string{"task"}, args...)...)
+	slog.Debug("exec", "args", cmd.Args)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("error getting task list: %w: %s", err, msg)
+		}
+		return nil, fmt.Errorf("error getting task list: %w", err)
+	}
+	if !slices.Contains(args, "--json") {
+		return ParseTaskList(stdout.String()), nil
+	}
+	return ParseTasksJson(stdout.String())
+}
+
+// Find returns the task whose id or one of whose aliases is name
+func Find(tasks []Task, name string) (Task, bool) {
+	for _, t := range tasks {
+		if t.Id == name {
+			return t, true
+		}
+	}
+	for _, t := range tasks {
+		for _, alias := range t.Aliases {
+			if alias == name {
+				return t, true
+			}
+		}
+	}
+	return Task{}, false
+}
@@ -0,0 +1,110 @@
+package task
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestTaskNamespace(t *testing.T) {
+	tests := map[string]string{
+		"default":           "",
+		"sys:disk-space":    "sys",
+		"docker:compose:up": "docker:compose",
+	}
+	for id, expected := range tests {
+		if ns := (Task{Id: id}).Namespace(); ns != expected {
+			t.Errorf("Task '%s': expected namespace '%s', got '%s'", id, expected, ns)
+		}
+	}
+}
+
+func TestParseTasksJsonUpToDate(t *testing.T) {
+	tasks, err := ParseTasksJson(`{"tasks":[{"name":"build","up_to_date":true,"location":{"line":3,"column":3,"taskfile":"Taskfile.yml"}}]}`)
+	if err != nil {
+		t.Fatalf("ParseTasksJson() error = %v", err)
+	}
+	if len(tasks) != 1 {
+		t.Fatalf("Expected 1 task, got %d", len(tasks))
+	}
+	if !tasks[0].UpToDate {
+		t.Error("Expected task to be up to date")
+	}
+	if tasks[0].Location == nil || tasks[0].Location.Taskfile != "Taskfile.yml" {
+		t.Errorf("Expected location taskfile 'Taskfile.yml', got %+v", tasks[0].Location)
+	}
+}
+
+func TestFindByIdOrAlias(t *testing.T) {
+	tasks := []Task{
+		{Id: "build", Aliases: []string{"b"}},
+		{Id: "b"},
+		{Id: "test", Aliases: []string{"t", "check"}},
+	}
+	tests := map[string]string{
+		"build": "build",
+		"b":     "b",
+		"check": "test",
+	}
+	for name, expected := range tests {
+		found, ok := Find(tasks, name)
+		if !ok || found.Id != expected {
+			t.Errorf("Find('%s'): expected '%s', got '%s' (found %v)", name, expected, found.Id, ok)
+		}
+	}
+	if _, ok := Find(tasks, "deploy"); ok {
+		t.Error("Expected no task for 'deploy'")
+	}
+}
+
+func TestListingJsonConvertsTextListing(t *testing.T) {
+	out, err := listingJson("task: Available tasks for this project:\n* build:   Build it   (aliases: b)\n", []string{"--list-all"})
+	if err != nil {
+		t.Fatalf("listingJson() error = %v", err)
+	}
+	tasks, err := ParseTasksJson(out)
+	if err != nil {
+		t.Fatalf("ParseTasksJson() error = %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].Id != "build" || tasks[0].Aliases[0] != "b" {
+		t.Errorf("Expected the build task with alias b, got %+v", tasks)
+	}
+}
+
+func TestStreamTasksJsonSendsBatches(t *testing.T) {
+	var names []string
+	for i := range listBatchSize + 5 {
+		names = append(names, fmt.Sprintf(`{"name": "task-%d", "desc": "Task %d"}`, i, i))
+	}
+	listing := `{"tasks": [` + strings.Join(names, ",") + `], "location": "/src/Taskfile.yml"}`
+
+	var batches [][]Task
+	if err := streamTasksJson(strings.NewReader(listing), func(tasks []Task) { batches = append(batches, tasks) }); err != nil {
+		t.Fatalf("streamTasksJson() error = %v", err)
+	}
+	if len(batches) != 2 || len(batches[0]) != listBatchSize || len(batches[1]) != 5 {
+		t.Fatalf("Expected a full batch and 5 remaining tasks, got %d batches", len(batches))
+	}
+	if got := batches[1][4].Id; got != fmt.Sprintf("task-%d", listBatchSize+4) {
+		t.Errorf("Expected the tasks in order, got %q last", got)
+	}
+
+	batches = nil
+	err := streamTasksJson(strings.NewReader(`{"tasks": [{"name": "build"}, {"name": `), func(tasks []Task) { batches = append(batches, tasks) })
+	if err == nil {
+		t.Error("Expected an error for a truncated listing")
+	}
+	if len(batches) != 1 || batches[0][0].Id != "build" {
+		t.Errorf("Expected the tasks read before the error, got %v", batches)
+	}
+}
+
+func TestStreamTaskListParsesTextListing(t *testing.T) {
+	var tasks []Task
+	streamTaskList(strings.NewReader("task: Available tasks for this project:\n* build:   Build it\n* test:   Test it\n"), func(batch []Task) {
+		tasks = append(tasks, batch...)
+	})
+	if len(tasks) != 2 || tasks[1].Id != "test" {
+		t.Errorf("Expected build and test, got %+v", tasks)
+	}
+}
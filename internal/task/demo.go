@@ -0,0 +1,150 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Aj4x/tash/internal/msgbus"
+)
+
+// ProviderDemo is the name of the built-in provider with scripted tasks, used to try tash without
+// a Taskfile and to test it without the task binary
+const ProviderDemo = "demo"
+
+// ErrUnknownTask is returned when running a task the provider doesn't have
+const ErrUnknownTask = Error("unknown task")
+
+// demoStep is a line of scripted output, written after a delay
+type demoStep struct {
+	delay  time.Duration
+	line   string
+	stderr bool
+}
+
+// demoTask is a task of the demo provider with its scripted output and, if it fails, exit code
+type demoTask struct {
+	task     Task
+	steps    []demoStep
+	exitCode int
+}
+
+func demoOut(delay time.Duration, line string) demoStep {
+	return demoStep{delay: delay, line: line}
+}
+
+func demoErr(delay time.Duration, line string) demoStep {
+	return demoStep{delay: delay, line: line, stderr: true}
+}
+
+var demoTasks = []demoTask{
+	{
+		task: Task{Id: "build", Desc: "Compile the application", Aliases: []string{"b"}},
+		steps: []demoStep{
+			demoOut(100*time.Millisecond, "go build -o bin/app ./cmd/app"),
+			demoOut(600*time.Millisecond, "compiling 42 packages"),
+			demoOut(400*time.Millisecond, "wrote bin/app (12.4 MB)"),
+		},
+	},
+	{
+		task: Task{Id: "test", Desc: "Run the unit tests", Aliases: []string{"t"}},
+		steps: []demoStep{
+			demoOut(200*time.Millisecond, "ok   example.com/app/config   0.012s"),
+			demoOut(300*time.Millisecond, "ok   example.com/app/server   0.204s"),
+			demoOut(300*time.Millisecond, "ok   example.com/app/store    0.087s"),
+		},
+	},
+	{
+		task: Task{Id: "lint", Desc: "Run the linters, which report a failure"},
+		steps: []demoStep{
+			demoOut(200*time.Millisecond, "golangci-lint run ./..."),
+			demoErr(500*time.Millisecond, "server/handler.go:42:2: ineffectual assignment to err (ineffassign)"),
+			demoErr(100*time.Millisecond, "store/cache.go:17:6: func `unused` is unused (unused)"),
+		},
+		exitCode: 1,
+	},
+	{
+		task: Task{Id: "serve", Desc: "Start a development server that runs until cancelled"},
+		steps: []demoStep{
+			demoOut(300*time.Millisecond, "listening on http://localhost:8080"),
+			demoOut(2*time.Second, "GET / 200 1.2ms"),
+			demoOut(3*time.Second, "GET /api/health 200 0.3ms"),
+			demoOut(time.Hour, "shutting down"),
+		},
+	},
+	{
+		task: Task{Id: "docs:build", Desc: "Generate the documentation site"},
+		steps: []demoStep{
+			demoOut(300*time.Millisecond, "rendering 18 pages"),
+			demoOut(700*time.Millisecond, "site written to docs/public"),
+		},
+	},
+	{
+		task:  Task{Id: "_cleanup", Internal: true},
+		steps: []demoStep{demoOut(100*time.Millisecond, "removed bin/")},
+	},
+}
+
+// DemoTasks returns the tasks of the demo provider
+func DemoTasks() []Task {
+	tasks := make([]Task, len(demoTasks))
+	for i, d := range demoTasks {
+		tasks[i] = d.task
+	}
+	return tasks
+}
+
+// ListDemoJson sends the demo provider's tasks to the message bus in the same form as ListAllJson
+func ListDemoJson(bus msgbus.Publisher[Message]) {
+	b, err := json.Marshal(struct {
+		Tasks []Task `json:"tasks"`
+	}{DemoTasks()})
+	if err != nil {
+		bus.Publish(TypeTaskListAllErr.Message().SetError(err).TopicMessage())
+		return
+	}
+	bus.Publish(TypeTaskJSON.Message().SetOutput(string(b)).TopicMessage())
+}
+
+// runDemoAttempt plays back the scripted output of a demo task. Like runAttempt it is cancelled
+// along with msg's context, or when opts.Timeout is exceeded.
+func runDemoAttempt(msg Message, taskId string, opts ExecOptions, bus msgbus.Publisher[Message]) error {
+	var demo *demoTask
+	for i := range demoTasks {
+		if demoTasks[i].task.Id == taskId {
+			demo = &demoTasks[i]
+		}
+	}
+	if demo == nil {
+		return fmt.Errorf("%w '%s'", ErrUnknownTask, taskId)
+	}
+	ctx, cancel := context.WithCancel(msg.ctx)
+	if opts.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(msg.ctx, opts.Timeout)
+	}
+	defer cancel()
+	// there is no process to pause or signal, only the context to cancel
+	bus.Publish(msg.SetCommand(nil).SetTaskRunning(true).TopicMessage())
+
+	for _, step := range demo.steps {
+		select {
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				return fmt.Errorf("%w after %s", ErrTimeout, opts.Timeout)
+			}
+			bus.Publish(TypeTaskOutput.Message().SetOutput("Task cancelled").TopicMessage())
+			return fmt.Errorf("task failed: %w", ctx.Err())
+		case <-time.After(step.delay):
+		}
+		msgType := TypeTaskOutput
+		if step.stderr && !opts.MergeOutput {
+			msgType = TypeTaskOutputErr
+		}
+		bus.Publish(msgType.Message().SetOutput(step.line).TopicMessage())
+	}
+	if demo.exitCode != 0 {
+		return fmt.Errorf("task failed with exit code %d", demo.exitCode)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package task
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDemoTaskFailure(t *testing.T) {
+	bus := &recordingPublisher{}
+
+	ExecuteTaskWithOptions("lint", ExecOptions{Provider: ProviderDemo}, bus)
+
+	errs := bus.ofType(TypeTaskError)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error().Error(), "exit code 1") {
+		t.Fatalf("Expected lint to fail with exit code 1, got %v", errs)
+	}
+	if len(bus.ofType(TypeTaskOutputErr)) != 2 {
+		t.Errorf("Expected 2 lines on stderr, got %d", len(bus.ofType(TypeTaskOutputErr)))
+	}
+}
+
+func TestDemoTaskTimeout(t *testing.T) {
+	bus := &recordingPublisher{}
+
+	start := time.Now()
+	ExecuteTaskWithOptions("serve", ExecOptions{Provider: ProviderDemo, Timeout: 500 * time.Millisecond}, bus)
+
+	errs := bus.ofType(TypeTaskError)
+	if len(errs) != 1 || !errors.Is(errs[0].Error(), ErrTimeout) {
+		t.Fatalf("Expected a timeout error, got %v", errs)
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Error("Expected the demo task to stop at the timeout")
+	}
+}
+
+func TestDemoUnknownTask(t *testing.T) {
+	bus := &recordingPublisher{}
+
+	ExecuteTaskWithOptions("missing", ExecOptions{Provider: ProviderDemo}, bus)
+
+	if errs := bus.ofType(TypeTaskError); len(errs) != 1 || !errors.Is(errs[0].Error(), ErrUnknownTask) {
+		t.Errorf("Expected an unknown task error, got %v", errs)
+	}
+}
@@ -0,0 +1,122 @@
+package task
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// Environment controls the working directory and the environment of the commands tash spawns,
+// so they don't depend on the shell tash was started from. The zero value inherits both.
+type Environment struct {
+	// Dir is the working directory, which also decides the Taskfile task uses; empty is the
+	// current directory
+	Dir string
+	// Taskfile is the Taskfile task uses instead of the one it finds in Dir: a path, or the URL
+	// of a remote Taskfile
+	Taskfile string
+	// PathPrefix is prepended to PATH
+	PathPrefix []string
+	// Allow lists the inherited variables as glob patterns such as "GO*"; empty allows all
+	Allow []string
+	// Deny lists inherited variables to drop as glob patterns, applied after Allow
+	Deny []string
+}
+
+// IsZero reports whether e leaves the working directory and environment alone
+func (e Environment) IsZero() bool {
+	return e.Dir == "" && e.Taskfile == "" && len(e.PathPrefix) == 0 && len(e.Allow) == 0 && len(e.Deny) == 0
+}
+
+// Environ returns the environment of a spawned command: tash's environment filtered by Allow
+// and Deny with PathPrefix prepended to PATH, followed by extra NAME=value entries. A remote
+// Taskfile enables task's experiment reading it, unless the environment sets the experiment.
+func (e Environment) Environ(extra []string) []string {
+	var env []string
+	paths := e.PathPrefix
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		switch {
+		case !e.inherits(name):
+		case envName(name) == "PATH":
+			paths = append(slices.Clip(paths), value)
+		default:
+			env = append(env, kv)
+		}
+	}
+	if len(paths) > 0 {
+		env = append(env, "PATH="+strings.Join(paths, string(os.PathListSeparator)))
+	}
+	if experiment := experimentPrefix + FeatureRemoteTaskfiles.Experiment; IsRemoteTaskfile(e.Taskfile) && !e.sets(env, experiment) {
+		env = append(env, experiment+"=1")
+	}
+	return append(env, extra...)
+}
+
+// sets reports whether the variable name is in env
+func (e Environment) sets(env []string, name string) bool {
+	return slices.ContainsFunc(env, func(kv string) bool {
+		n, _, _ := strings.Cut(kv, "=")
+		return envName(n) == envName(name)
+	})
+}
+
+// inherits reports whether the variable name is passed on to spawned commands
+func (e Environment) inherits(name string) bool {
+	if len(e.Allow) > 0 && !matchesAny(e.Allow, name) {
+		return false
+	}
+	return !matchesAny(e.Deny, name)
+}
+
+// envName returns the variable name in the form it is compared in: Windows variable names are
+// case-insensitive, e.g. PATH is "Path"
+func envName(name string) string {
+	if runtime.GOOS == "windows" {
+		return strings.ToUpper(name)
+	}
+	return name
+}
+
+// matchesAny reports whether name matches one of the glob patterns
+func matchesAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(envName(p), envName(name)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// lookPath returns the program to run for name, preferring the directories of PathPrefix, as
+// exec.Command only searches tash's own PATH
+func (e Environment) lookPath(name string) string {
+	if strings.ContainsRune(name, filepath.Separator) {
+		return name
+	}
+	for _, dir := range e.PathPrefix {
+		if p, err := exec.LookPath(filepath.Join(dir, name)); err == nil {
+			return p
+		}
+	}
+	return name
+}
+
+// Command returns the command running args in the environment, adding extra NAME=value entries
+// to it; the command is cancelled with ctx. Commands running task are pointed at Taskfile.
+func (e Environment) Command(ctx context.Context, extra []string, args ...string) *exec.Cmd {
+	if e.Taskfile != "" && args[0] == "task" {
+		args = append([]string{args[0], "--taskfile", e.Taskfile}, args[1:]...)
+	}
+	cmd := exec.CommandContext(ctx, e.lookPath(args[0]), args[1:]...)
+	cmd.Dir = e.Dir
+	if !e.IsZero() || len(extra) > 0 {
+		cmd.Env = e.Environ(extra)
+	}
+	return cmd
+}
@@ -0,0 +1,66 @@
+package task
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestEnvironmentFiltersVariables(t *testing.T) {
+	t.Setenv("TASH_TEST_KEEP", "1")
+	t.Setenv("TASH_TEST_SECRET", "2")
+	t.Setenv("OTHER_TEST_VAR", "3")
+	t.Setenv("PATH", "/usr/bin")
+
+	env := Environment{
+		PathPrefix: []string{"/opt/tools/bin"},
+		Allow:      []string{"TASH_TEST_*", "PATH"},
+		Deny:       []string{"*_SECRET"},
+	}.Environ([]string{"EXTRA=4"})
+
+	want := []string{"TASH_TEST_KEEP=1", "PATH=/opt/tools/bin" + string(os.PathListSeparator) + "/usr/bin", "EXTRA=4"}
+	if !slices.Equal(env, want) {
+		t.Errorf("Expected %v, got %v", want, env)
+	}
+}
+
+func TestEnvironmentWithoutPath(t *testing.T) {
+	env := Environment{PathPrefix: []string{"/opt/tools/bin"}, Allow: []string{"HOME"}}.Environ(nil)
+	if !slices.Contains(env, "PATH=/opt/tools/bin") {
+		t.Errorf("Expected PATH to hold only the prefix, got %v", env)
+	}
+}
+
+func TestExecuteTaskInEnvironment(t *testing.T) {
+	fakeTaskBinary(t, "echo should not run\n")
+	// a task binary in the path prefix takes precedence over the one on PATH
+	tools := t.TempDir()
+	script := "#!/bin/sh\npwd\necho \"keep=$TASH_TEST_KEEP drop=$TASH_TEST_DROP\"\n"
+	if err := os.WriteFile(filepath.Join(tools, "task"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TASH_TEST_KEEP", "yes")
+	t.Setenv("TASH_TEST_DROP", "yes")
+	dir := t.TempDir()
+	bus := &recordingPublisher{}
+
+	ExecuteTaskWithOptions("build", ExecOptions{Environment: Environment{
+		Dir:        dir,
+		PathPrefix: []string{tools},
+		Deny:       []string{"TASH_TEST_DROP"},
+	}}, bus)
+
+	var lines []string
+	for _, m := range bus.ofType(TypeTaskOutput) {
+		lines = append(lines, m.Output())
+	}
+	resolved, _ := filepath.EvalSymlinks(dir)
+	if len(lines) != 2 || (lines[0] != dir && lines[0] != resolved) {
+		t.Fatalf("Expected the task to run in %s, got %v", dir, lines)
+	}
+	if lines[1] != "keep=yes drop=" {
+		t.Errorf("Expected TASH_TEST_DROP to be dropped, got %q", strings.TrimSpace(lines[1]))
+	}
+}
@@ -0,0 +1,251 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Aj4x/tash/internal/msgbus"
+	"github.com/Aj4x/tash/internal/redact"
+)
+
+// recordingPublisher collects every message published to it
+type recordingPublisher struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func (r *recordingPublisher) Publish(msg msgbus.TopicMessage[Message]) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, msg.Message)
+}
+
+func (r *recordingPublisher) ofType(t Type) []Message {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var messages []Message
+	for _, m := range r.messages {
+		if m.Type == t {
+			messages = append(messages, m)
+		}
+	}
+	return messages
+}
+
+// fakeTaskBinary installs a shell script named "task" at the front of PATH
+func fakeTaskBinary(t *testing.T, script string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "task"), []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestExecuteTaskWithOptionsRetries(t *testing.T) {
+	counter := filepath.Join(t.TempDir(), "attempts")
+	// fail on the first two attempts and succeed on the third
+	fakeTaskBinary(t, `echo x >> `+counter+`
+[ "$(wc -l < `+counter+`)" -ge 3 ]
+`)
+	bus := &recordingPublisher{}
+
+	ExecuteTaskWithOptions("flaky", ExecOptions{Retries: 3, RetryBackoff: time.Millisecond}, bus)
+
+	if len(bus.ofType(TypeTaskDone)) != 1 {
+		t.Fatalf("Expected the task to eventually succeed, got errors %v", bus.ofType(TypeTaskError))
+	}
+	var attempts []string
+	for _, m := range bus.ofType(TypeTaskOutput) {
+		if strings.HasPrefix(m.Output(), "Attempt ") {
+			attempts = append(attempts, m.Output())
+		}
+	}
+	if len(attempts) != 3 || attempts[2] != "Attempt 3/4" {
+		t.Errorf("Expected 3 attempts ending with 'Attempt 3/4', got %v", attempts)
+	}
+}
+
+func TestExecuteTaskWithOptionsTimeout(t *testing.T) {
+	fakeTaskBinary(t, "sleep 5\n")
+	bus := &recordingPublisher{}
+
+	ExecuteTaskWithOptions("slow", ExecOptions{Timeout: 100 * time.Millisecond}, bus)
+
+	errs := bus.ofType(TypeTaskError)
+	if len(errs) != 1 {
+		t.Fatalf("Expected a single task error, got %d", len(errs))
+	}
+	if !errors.Is(errs[0].Error(), ErrTimeout) {
+		t.Errorf("Expected a timeout error, got %v", errs[0].Error())
+	}
+}
+
+func TestExecuteTaskWithOptionsPassesArgsAndEnv(t *testing.T) {
+	fakeTaskBinary(t, `echo "args: $*"
+echo "profile: $PROFILE_NAME"
+`)
+	bus := &recordingPublisher{}
+
+	opts := ExecOptions{Force: true, Args: []string{"one", "two"}, Env: []string{"PROFILE_NAME=staging"}}
+	ExecuteTaskWithOptions("build", opts, bus)
+
+	var output []string
+	for _, m := range bus.ofType(TypeTaskOutput) {
+		output = append(output, m.Output())
+	}
+	for _, want := range []string{"args: --force build -- one two", "profile: staging"} {
+		if !slices.Contains(output, want) {
+			t.Errorf("Expected %q in the output, got %v", want, output)
+		}
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	fakeTaskBinary(t, "exit 3\n")
+	bus := &recordingPublisher{}
+	ExecuteTask("build", bus)
+
+	errs := bus.ofType(TypeTaskError)
+	if len(errs) != 1 {
+		t.Fatalf("Expected the task to fail, got %d errors", len(errs))
+	}
+	if code := ExitCode(errs[0].Error()); code != 3 {
+		t.Errorf("Expected exit code 3, got %d", code)
+	}
+
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, 0},
+		{fmt.Errorf("%w after 1s", ErrTimeout), ExitTimeout},
+		{errors.New("exec: \"task\": executable file not found in $PATH"), ExitFailure},
+	}
+	for _, tt := range tests {
+		if got := ExitCode(tt.err); got != tt.want {
+			t.Errorf("Expected exit code %d for %v, got %d", tt.want, tt.err, got)
+		}
+	}
+}
+
+func TestExecuteTaskWithLowPriority(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("nice values are Unix only")
+	}
+	// give tash time to lower the priority of the started process group
+	fakeTaskBinary(t, "sleep 0.2\nps -o nice= -p $$\n")
+	bus := &recordingPublisher{}
+
+	ExecuteTaskWithOptions("build", ExecOptions{LowPriority: true}, bus)
+
+	if errs := bus.ofType(TypeTaskOutputErr); len(errs) > 0 {
+		t.Fatalf("Expected the priority to be lowered, got %q", errs[0].Output())
+	}
+	out := bus.ofType(TypeTaskOutput)
+	if len(out) != 1 {
+		t.Fatalf("Expected the task's niceness, got %d lines", len(out))
+	}
+	if nice := strings.TrimSpace(out[0].Output()); nice != "10" {
+		t.Errorf("Expected niceness 10, got %s", nice)
+	}
+}
+
+func TestStartTaskReturnsBeforeTheRunFinishes(t *testing.T) {
+	fakeTaskBinary(t, "sleep 1\n")
+	bus := &recordingPublisher{}
+
+	start := time.Now()
+	StartTask("slow", ExecOptions{}, bus)
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("Expected StartTask to return at once, took %s", elapsed)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(bus.ofType(TypeTaskDone)) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the run to finish in the background")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// answeringPublisher records messages like recordingPublisher and answers password prompts
+type answeringPublisher struct {
+	recordingPublisher
+	password string
+}
+
+func (a *answeringPublisher) Publish(msg msgbus.TopicMessage[Message]) {
+	a.recordingPublisher.Publish(msg)
+	if msg.Message.Type == TypeTaskPassword {
+		_, _ = io.WriteString(msg.Message.Input(), a.password+"\n")
+	}
+}
+
+func TestExecuteTaskWithPasswordInput(t *testing.T) {
+	fakeTaskBinary(t, `printf '[sudo] password for ann: ' >&2
+read -r pw
+echo "got $pw"
+`)
+	bus := &answeringPublisher{password: "s3cret"}
+
+	ExecuteTaskWithOptions("install", ExecOptions{PasswordInput: true}, bus)
+
+	prompts := bus.ofType(TypeTaskPassword)
+	if len(prompts) != 1 || prompts[0].Output() != "[sudo] password for ann:" {
+		t.Fatalf("Expected the sudo prompt to be reported, got %d prompts", len(prompts))
+	}
+	out := bus.ofType(TypeTaskOutput)
+	if len(out) != 1 || out[0].Output() != "got s3cret" {
+		t.Errorf("Expected the task to read the password from stdin, got %d lines", len(out))
+	}
+	for _, m := range bus.ofType(TypeTaskOutputErr) {
+		if strings.Contains(m.Output(), "s3cret") {
+			t.Errorf("Expected the password not to be echoed, got %q", m.Output())
+		}
+	}
+
+	// without the option, tasks get an empty stdin and no prompt is reported
+	bus = &answeringPublisher{password: "s3cret"}
+	ExecuteTaskWithOptions("install", ExecOptions{}, bus)
+	if len(bus.ofType(TypeTaskPassword)) != 0 || len(bus.ofType(TypeTaskOutputErr)) != 1 {
+		t.Errorf("Expected the prompt to be left as output, got %d prompts", len(bus.ofType(TypeTaskPassword)))
+	}
+}
+
+func TestExecuteTaskWithOptionsRedactsSecrets(t *testing.T) {
+	fakeTaskBinary(t, `echo "using $DEPLOY_TOKEN"
+echo "key ghp_0123abcd" >&2
+echo "vars: $*"
+`)
+	bus := &recordingPublisher{}
+	redactor, err := redact.New([]string{`ghp_\w+`})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := ExecOptions{Env: []string{"DEPLOY_TOKEN=t0ps3cret"}, Vars: []string{"DB_PASSWORD=hunter2"}, Redactor: redactor}
+	ExecuteTaskWithOptions("deploy", opts, bus)
+
+	var output []string
+	for _, m := range append(bus.ofType(TypeTaskOutput), bus.ofType(TypeTaskOutputErr)...) {
+		output = append(output, m.Output())
+	}
+	for _, want := range []string{"using *****", "key *****", "vars: deploy DB_PASSWORD=*****"} {
+		if !slices.Contains(output, want) {
+			t.Errorf("Expected %q in the output, got %v", want, output)
+		}
+	}
+}
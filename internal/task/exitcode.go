@@ -0,0 +1,33 @@
+package task
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// Exit codes for failures that don't come with the task's own exit code
+const (
+	// ExitFailure is the exit code of a run that failed without exiting, e.g. task couldn't start
+	ExitFailure = 1
+	// ExitTimeout is the exit code of a run that exceeded its timeout, as used by timeout(1)
+	ExitTimeout = 124
+	// ExitInterrupted is the exit code of a run cancelled by an interrupt, as used by shells
+	ExitInterrupted = 130
+)
+
+// ExitCode maps the error of a run to an exit code: 0 for success, the task's own exit code when
+// it exited with one, ExitTimeout for timeouts and ExitFailure otherwise
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if errors.Is(err, ErrTimeout) {
+		return ExitTimeout
+	}
+	var exitError *exec.ExitError
+	// killed by a signal the exit code is -1
+	if errors.As(err, &exitError) && exitError.ExitCode() > 0 {
+		return exitError.ExitCode()
+	}
+	return ExitFailure
+}
@@ -0,0 +1,54 @@
+package task
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Feature is a capability of task that tash relies on and that only some releases offer
+type Feature struct {
+	// Name describes the feature in messages, in the plural
+	Name string
+	// Since is the first release offering the feature
+	Since Version
+	// Experiment is the name of the TASK_X_ variable enabling the feature while it is an
+	// experiment of task, empty once it is generally available
+	Experiment string
+}
+
+// Features tash gates on the installed version
+var (
+	FeatureJSONList        = Feature{Name: "JSON task listings", Since: jsonListVersion}
+	FeatureWildcards       = Feature{Name: "wildcard task names", Since: Version{Major: 3, Minor: 35, Patch: 0}}
+	FeatureRemoteTaskfiles = Feature{Name: "remote Taskfiles", Since: Version{Major: 3, Minor: 32, Patch: 0}, Experiment: "REMOTE_TASKFILES"}
+)
+
+// experimentPrefix starts the names of the variables enabling task's experiments
+const experimentPrefix = "TASK_X_"
+
+// Experiments returns the task experiments enabled in environ, a list of NAME=value entries
+func Experiments(environ []string) []string {
+	var enabled []string
+	for _, kv := range environ {
+		name, value, _ := strings.Cut(kv, "=")
+		experiment, ok := strings.CutPrefix(name, experimentPrefix)
+		if ok && experiment != "" && value != "" && value != "0" && !strings.EqualFold(value, "false") {
+			enabled = append(enabled, experiment)
+		}
+	}
+	slices.Sort(enabled)
+	return enabled
+}
+
+// Check returns why task v can't be used for f with the experiments enabled in environ, or nil
+// when it can. An undetected version (e.g. a development build) is assumed to be recent.
+func (v Version) Check(f Feature, environ []string) error {
+	if v.Known() && v.Less(f.Since) {
+		return fmt.Errorf("%s need task %s or later, %s is installed", f.Name, f.Since, v)
+	}
+	if f.Experiment != "" && !slices.Contains(Experiments(environ), f.Experiment) {
+		return fmt.Errorf("%s are an experiment of task, enable it with %s%s=1", f.Name, experimentPrefix, f.Experiment)
+	}
+	return nil
+}
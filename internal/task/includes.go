@@ -0,0 +1,77 @@
+package task
+
+import (
+	"path/filepath"
+	"slices"
+)
+
+// Taskfile returns the path of the Taskfile defining the task, or an empty string when the
+// listing didn't report it
+func (t Task) Taskfile() string {
+	if t.Location == nil {
+		return ""
+	}
+	return t.Location.Taskfile
+}
+
+// RootTaskfile returns the Taskfile that includes the others: the file defining the most tasks
+// of the root namespace, or the most tasks overall when every task is namespaced
+func RootTaskfile(tasks []Task) string {
+	counts := map[string]int{}
+	best := ""
+	for _, rootOnly := range []bool{true, false} {
+		for _, t := range tasks {
+			if t.Taskfile() == "" || (rootOnly && t.Namespace() != "") {
+				continue
+			}
+			counts[t.Taskfile()]++
+			if counts[t.Taskfile()] > counts[best] {
+				best = t.Taskfile()
+			}
+		}
+		if best != "" {
+			return best
+		}
+	}
+	return ""
+}
+
+// IncludePath returns the Taskfile of t relative to the directory of root, or an empty string
+// when t is defined in root itself or its Taskfile isn't known
+func IncludePath(t Task, root string) string {
+	path := t.Taskfile()
+	if path == "" || path == root {
+		return ""
+	}
+	if root != "" {
+		if rel, err := filepath.Rel(filepath.Dir(root), path); err == nil {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return path
+}
+
+// HasIncludes reports whether any of the tasks is defined in an included Taskfile
+func HasIncludes(tasks []Task) bool {
+	root := RootTaskfile(tasks)
+	return slices.ContainsFunc(tasks, func(t Task) bool { return IncludePath(t, root) != "" })
+}
+
+// GroupByTaskfile returns the tasks ordered by the Taskfile defining them: the root Taskfile
+// first, then the included files in the order their first task is listed. Tasks keep their
+// order within each file.
+func GroupByTaskfile(tasks []Task) []Task {
+	root := RootTaskfile(tasks)
+	rank := map[string]int{"": 0}
+	for _, t := range tasks {
+		include := IncludePath(t, root)
+		if _, ok := rank[include]; !ok {
+			rank[include] = len(rank)
+		}
+	}
+	grouped := slices.Clone(tasks)
+	slices.SortStableFunc(grouped, func(a, b Task) int {
+		return rank[IncludePath(a, root)] - rank[IncludePath(b, root)]
+	})
+	return grouped
+}
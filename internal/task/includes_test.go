@@ -0,0 +1,64 @@
+package task
+
+import (
+	"slices"
+	"testing"
+)
+
+func taskIn(id, taskfile string) Task {
+	return Task{Id: id, Location: &Location{Taskfile: taskfile, Line: 1}}
+}
+
+func TestRootTaskfilePrefersRootNamespace(t *testing.T) {
+	tasks := []Task{
+		taskIn("docs:a", "/p/docs/Taskfile.yml"),
+		taskIn("docs:b", "/p/docs/Taskfile.yml"),
+		taskIn("build", "/p/Taskfile.yml"),
+	}
+	if root := RootTaskfile(tasks); root != "/p/Taskfile.yml" {
+		t.Errorf("Expected /p/Taskfile.yml, got %s", root)
+	}
+	if root := RootTaskfile(tasks[:2]); root != "/p/docs/Taskfile.yml" {
+		t.Errorf("Expected the only Taskfile when every task is namespaced, got %s", root)
+	}
+	if root := RootTaskfile([]Task{{Id: "build"}}); root != "" {
+		t.Errorf("Expected no root without locations, got %s", root)
+	}
+}
+
+func TestIncludePathIsRelativeToRoot(t *testing.T) {
+	root := "/p/Taskfile.yml"
+	if got := IncludePath(taskIn("docs:a", "/p/docs/Taskfile.yml"), root); got != "docs/Taskfile.yml" {
+		t.Errorf("Expected docs/Taskfile.yml, got %s", got)
+	}
+	if got := IncludePath(taskIn("build", root), root); got != "" {
+		t.Errorf("Expected no include for the root Taskfile, got %s", got)
+	}
+	if got := IncludePath(Task{Id: "build"}, root); got != "" {
+		t.Errorf("Expected no include without a location, got %s", got)
+	}
+}
+
+func TestGroupByTaskfile(t *testing.T) {
+	tasks := []Task{
+		taskIn("api:build", "/p/api/Taskfile.yml"),
+		taskIn("build", "/p/Taskfile.yml"),
+		taskIn("docs:build", "/p/docs/Taskfile.yml"),
+		taskIn("api:test", "/p/api/Taskfile.yml"),
+		taskIn("test", "/p/Taskfile.yml"),
+	}
+	if !HasIncludes(tasks) {
+		t.Error("Expected includes to be detected")
+	}
+	var ids []string
+	for _, task := range GroupByTaskfile(tasks) {
+		ids = append(ids, task.Id)
+	}
+	want := []string{"build", "test", "api:build", "api:test", "docs:build"}
+	if !slices.Equal(ids, want) {
+		t.Errorf("Expected %v, got %v", want, ids)
+	}
+	if tasks[0].Id != "api:build" {
+		t.Error("Expected the tasks passed in to be left unchanged")
+	}
+}
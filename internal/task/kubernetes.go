@@ -0,0 +1,272 @@
+package task
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Aj4x/tash/internal/msgbus"
+	"github.com/Aj4x/tash/internal/redact"
+)
+
+// ProviderKubernetes is the name of the provider listing tasks with the task binary and running
+// each of them as a Kubernetes Job, using kubectl
+const ProviderKubernetes = "kubernetes"
+
+// KubernetesJob describes where the kubernetes provider runs tasks
+type KubernetesJob struct {
+	// Context is the kubectl context of the cluster; empty uses the current context
+	Context string
+	// Namespace is the namespace the jobs are created in; empty uses the context's namespace
+	Namespace string
+	// Image is the container image with the task binary and the project's Taskfile
+	Image string
+	// Dir is the directory of the project in the image; empty uses the image's working directory
+	Dir string
+}
+
+const (
+	// kubernetesJobTTL is how long a finished job and its pod are kept for inspection
+	kubernetesJobTTL = time.Hour
+	// kubernetesStartTimeout is how long the pod of a job may take to start, e.g. to pull its image
+	kubernetesStartTimeout = 5 * time.Minute
+)
+
+// kubernetesPollInterval is how often the status of a job is checked once its logs have ended
+var kubernetesPollInterval = time.Second
+
+// kubectl returns the kubectl command running args against the job's cluster and namespace
+func (k KubernetesJob) kubectl(ctx context.Context, env Environment, args ...string) *exec.Cmd {
+	prefix := []string{"kubectl"}
+	if k.Context != "" {
+		prefix = append(prefix, "--context", k.Context)
+	}
+	if k.Namespace != "" {
+		prefix = append(prefix, "--namespace", k.Namespace)
+	}
+	return env.Command(ctx, nil, append(prefix, args...)...)
+}
+
+// kubernetesJobName returns a name for a job running taskId, unique per millisecond and short
+// enough for the job-name label of its pod
+func kubernetesJobName(taskId string, now time.Time) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(taskId) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		} else if s := b.String(); s != "" && !strings.HasSuffix(s, "-") {
+			b.WriteByte('-')
+		}
+	}
+	id := strings.Trim(b.String(), "-")
+	if len(id) > 40 {
+		id = strings.TrimRight(id[:40], "-")
+	}
+	name := "tash-"
+	if id != "" {
+		name += id + "-"
+	}
+	return name + strconv.FormatInt(now.UnixMilli(), 36)
+}
+
+// kubernetesManifest returns the Job running taskId in the configured image. The job doesn't
+// retry failed pods, as retries are left to tash, and is removed some time after it finishes.
+func kubernetesManifest(name, taskId string, opts ExecOptions) ([]byte, error) {
+	container := map[string]any{
+		"name":    "task",
+		"image":   opts.Kubernetes.Image,
+		"command": TaskArgs(taskId, opts),
+	}
+	if opts.Kubernetes.Dir != "" {
+		container["workingDir"] = opts.Kubernetes.Dir
+	}
+	var env []map[string]string
+	for _, e := range opts.Env {
+		if k, v, ok := strings.Cut(e, "="); ok {
+			env = append(env, map[string]string{"name": k, "value": v})
+		}
+	}
+	if len(env) > 0 {
+		container["env"] = env
+	}
+	labels := map[string]string{"app.kubernetes.io/managed-by": "tash"}
+	return json.Marshal(map[string]any{
+		"apiVersion": "batch/v1",
+		"kind":       "Job",
+		"metadata": map[string]any{
+			"name":        name,
+			"labels":      labels,
+			"annotations": map[string]string{"tash/task": taskId},
+		},
+		"spec": map[string]any{
+			"backoffLimit":            0,
+			"ttlSecondsAfterFinished": int(kubernetesJobTTL.Seconds()),
+			"template": map[string]any{
+				"metadata": map[string]any{"labels": labels},
+				"spec": map[string]any{
+					"restartPolicy": "Never",
+					"containers":    []any{container},
+				},
+			},
+		},
+	})
+}
+
+// runKubernetesAttempt runs a single attempt of a task as a Kubernetes Job, publishing the logs of
+// its pod, and returns the reason it failed. A cancelled or timed out job is deleted.
+func runKubernetesAttempt(msg Message, taskId string, opts ExecOptions, bus msgbus.Publisher[Message]) error {
+	k := opts.Kubernetes
+	if k.Image == "" {
+		return errors.New("no image is configured to run tasks in kubernetes")
+	}
+	ctx, cancel := context.WithCancel(msg.ctx)
+	if opts.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(msg.ctx, opts.Timeout)
+	}
+	defer cancel()
+
+	name := kubernetesJobName(taskId, time.Now())
+	manifest, err := kubernetesManifest(name, taskId, opts)
+	if err != nil {
+		return err
+	}
+	slog.Debug("kubernetes job", "name", name, "image", k.Image, "context", k.Context, "namespace", k.Namespace)
+	create := k.kubectl(ctx, opts.Environment, "create", "--filename", "-")
+	create.Stdin = bytes.NewReader(manifest)
+	if out, err := create.CombinedOutput(); err != nil {
+		return fmt.Errorf("unable to create job %s: %s", name, kubectlError(out, err))
+	}
+	// there is no local process to pause or signal, only the job to delete
+	bus.Publish(msg.SetCommand(nil).SetTaskRunning(true).TopicMessage())
+	bus.Publish(TypeTaskOutputErr.Message().SetOutput("Running in job " + name).TopicMessage())
+
+	redactor := opts.Redactor.WithEnv(opts.Env, opts.Vars)
+	logs := k.kubectl(ctx, opts.Environment, "logs", "--follow",
+		"--pod-running-timeout="+kubernetesStartTimeout.String(), "job/"+name)
+	logsErr := streamKubectl(logs, redactor, bus)
+	// once the logs end the pod has exited, or never started when following them failed
+	failed, done, err := kubernetesJobStatus(ctx, k, opts.Environment, name)
+	for logsErr == nil && err == nil && !done && ctx.Err() == nil {
+		select {
+		case <-ctx.Done():
+		case <-time.After(kubernetesPollInterval):
+			failed, done, err = kubernetesJobStatus(ctx, k, opts.Environment, name)
+		}
+	}
+
+	if ctx.Err() != nil {
+		deleteKubernetesJob(k, opts.Environment, name, bus)
+		if ctx.Err() == context.DeadlineExceeded {
+			bus.Publish(TypeTaskOutputErr.Message().SetOutput(fmt.Sprintf("Task timed out after %s, deleted job %s", opts.Timeout, name)).TopicMessage())
+			return fmt.Errorf("%w after %s", ErrTimeout, opts.Timeout)
+		}
+		bus.Publish(TypeTaskOutput.Message().SetOutput("Task cancelled").TopicMessage())
+		return fmt.Errorf("task failed: %w", ctx.Err())
+	}
+	if err != nil {
+		return fmt.Errorf("unable to get the status of job %s: %w", name, err)
+	}
+	if !done {
+		deleteKubernetesJob(k, opts.Environment, name, bus)
+		return fmt.Errorf("unable to follow the logs of job %s: %w", name, logsErr)
+	}
+	if failed {
+		if code := kubernetesExitCode(k, opts.Environment, name); code > 0 {
+			return fmt.Errorf("task failed with exit code %d", code)
+		}
+		return fmt.Errorf("job %s failed", name)
+	}
+	return nil
+}
+
+// streamKubectl runs command, publishing its output as task output and its errors as task errors
+func streamKubectl(command *exec.Cmd, redactor *redact.Redactor, bus msgbus.Publisher[Message]) error {
+	streams, err := outputStreams(command, false)
+	if err != nil {
+		return err
+	}
+	if err := command.Start(); err != nil {
+		return err
+	}
+	readers := sync.WaitGroup{}
+	for _, s := range streams.readers {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			scanner := bufio.NewScanner(s.reader)
+			for scanner.Scan() {
+				bus.Publish(s.msgType.Message().SetOutput(redactor.Redact(scanner.Text())).TopicMessage())
+			}
+		}()
+	}
+	// all output must be read before waiting on the command, as Wait closes the pipes
+	readers.Wait()
+	return command.Wait()
+}
+
+// kubernetesJobStatus reports whether the job has finished, and whether it failed
+func kubernetesJobStatus(ctx context.Context, k KubernetesJob, env Environment, name string) (failed, done bool, err error) {
+	out, err := k.kubectl(ctx, env, "get", "job/"+name,
+		`--output=jsonpath={range .status.conditions[?(@.status=="True")]}{.type}{"\n"}{end}`).Output()
+	if err != nil {
+		return false, false, kubectlError(out, err)
+	}
+	conditions := strings.Fields(string(out))
+	switch {
+	case slices.Contains(conditions, "Failed"):
+		return true, true, nil
+	case slices.Contains(conditions, "Complete"):
+		return false, true, nil
+	}
+	return false, false, nil
+}
+
+// kubernetesExitCode returns the exit code of the task in the job's pod, or 0 when it is unknown
+func kubernetesExitCode(k KubernetesJob, env Environment, name string) int {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	out, err := k.kubectl(ctx, env, "get", "pods", "--selector=job-name="+name,
+		"--output=jsonpath={.items[*].status.containerStatuses[*].state.terminated.exitCode}").Output()
+	if err != nil {
+		return 0
+	}
+	for _, field := range strings.Fields(string(out)) {
+		if code, err := strconv.Atoi(field); err == nil && code > 0 {
+			return code
+		}
+	}
+	return 0
+}
+
+// deleteKubernetesJob deletes the job and, in the background, its pod
+func deleteKubernetesJob(k KubernetesJob, env Environment, name string, bus msgbus.Publisher[Message]) {
+	// the run's context has ended, the job is deleted regardless
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	out, err := k.kubectl(ctx, env, "delete", "job/"+name, "--cascade=background", "--ignore-not-found", "--wait=false").CombinedOutput()
+	if err != nil {
+		bus.Publish(TypeTaskOutputErr.Message().SetOutput(fmt.Sprintf("Unable to delete job %s: %s", name, kubectlError(out, err))).TopicMessage())
+	}
+}
+
+// kubectlError returns the message kubectl printed for err, or err itself when it printed none
+func kubectlError(out []byte, err error) error {
+	var exitError *exec.ExitError
+	if errors.As(err, &exitError) && len(bytes.TrimSpace(exitError.Stderr)) > 0 {
+		out = exitError.Stderr
+	}
+	if msg := strings.TrimSpace(string(out)); msg != "" {
+		return errors.New(msg)
+	}
+	return err
+}
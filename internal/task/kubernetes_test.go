@@ -0,0 +1,199 @@
+package task
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeKubectl installs a shell script named "kubectl" at the front of PATH, which logs its
+// arguments to the returned file
+func fakeKubectl(t *testing.T, script string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+	dir := t.TempDir()
+	calls := filepath.Join(dir, "calls")
+	script = "#!/bin/sh\nprintf '%s\\n' \"$*\" >> " + calls + "\n" + script
+	if err := os.WriteFile(filepath.Join(dir, "kubectl"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return calls
+}
+
+func readCalls(t *testing.T, calls string) []string {
+	t.Helper()
+	data, err := os.ReadFile(calls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func TestKubernetesJobName(t *testing.T) {
+	now := time.UnixMilli(1700000000000)
+	suffix := "-loyw3v28"
+	for taskId, want := range map[string]string{
+		"build":               "tash-build" + suffix,
+		"Deploy:Prod":         "tash-deploy-prod" + suffix,
+		"docs:_internal::gen": "tash-docs-internal-gen" + suffix,
+		":::":                 "tash" + suffix,
+	} {
+		if got := kubernetesJobName(taskId, now); got != want {
+			t.Errorf("kubernetesJobName(%q) = %q, want %q", taskId, got, want)
+		}
+	}
+	if got := kubernetesJobName(strings.Repeat("a", 100), now); len(got) > 63 {
+		t.Errorf("Expected the name to fit a label value, got %d characters", len(got))
+	}
+}
+
+func TestKubernetesManifest(t *testing.T) {
+	opts := ExecOptions{
+		Force:      true,
+		Vars:       []string{"ENV=prod"},
+		Env:        []string{"PROFILE=ci"},
+		Kubernetes: KubernetesJob{Image: "tasks:latest", Dir: "/app"},
+	}
+	data, err := kubernetesManifest("tash-build-1", "build", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var job struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Spec struct {
+			BackoffLimit *int `json:"backoffLimit"`
+			Template     struct {
+				Spec struct {
+					RestartPolicy string `json:"restartPolicy"`
+					Containers    []struct {
+						Image      string   `json:"image"`
+						Command    []string `json:"command"`
+						WorkingDir string   `json:"workingDir"`
+						Env        []struct {
+							Name  string `json:"name"`
+							Value string `json:"value"`
+						} `json:"env"`
+					} `json:"containers"`
+				} `json:"spec"`
+			} `json:"template"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(data, &job); err != nil {
+		t.Fatal(err)
+	}
+	if job.Metadata.Name != "tash-build-1" {
+		t.Errorf("Expected the job's name, got %q", job.Metadata.Name)
+	}
+	if job.Spec.BackoffLimit == nil || *job.Spec.BackoffLimit != 0 || job.Spec.Template.Spec.RestartPolicy != "Never" {
+		t.Errorf("Expected the job not to retry its pod, got %s", data)
+	}
+	if len(job.Spec.Template.Spec.Containers) != 1 {
+		t.Fatalf("Expected a single container, got %s", data)
+	}
+	c := job.Spec.Template.Spec.Containers[0]
+	if c.Image != "tasks:latest" || c.WorkingDir != "/app" {
+		t.Errorf("Expected the configured image and dir, got %q in %q", c.Image, c.WorkingDir)
+	}
+	if got := strings.Join(c.Command, " "); got != "task --force build ENV=prod" {
+		t.Errorf("Expected the task command, got %q", got)
+	}
+	if len(c.Env) != 1 || c.Env[0].Name != "PROFILE" || c.Env[0].Value != "ci" {
+		t.Errorf("Expected the extra environment, got %+v", c.Env)
+	}
+}
+
+func TestKubernetesProviderStreamsLogs(t *testing.T) {
+	manifest := filepath.Join(t.TempDir(), "manifest")
+	calls := fakeKubectl(t, `case "$*" in
+*create*) cat > `+manifest+` ;;
+*logs*) echo "building"; echo "done" ;;
+*"get job"*) echo Complete ;;
+esac
+`)
+	bus := &recordingPublisher{}
+
+	opts := ExecOptions{Provider: ProviderKubernetes, Kubernetes: KubernetesJob{Image: "tasks", Namespace: "ci"}}
+	ExecuteTaskWithOptions("build", opts, bus)
+
+	if errs := bus.ofType(TypeTaskError); len(errs) > 0 {
+		t.Fatalf("Expected the job to succeed, got %v", errs[0].Error())
+	}
+	var output []string
+	for _, m := range bus.ofType(TypeTaskOutput) {
+		output = append(output, m.Output())
+	}
+	if strings.Join(output, ",") != "building,done" {
+		t.Errorf("Expected the pod's logs as output, got %v", output)
+	}
+	for _, call := range readCalls(t, calls) {
+		if !strings.HasPrefix(call, "--namespace ci ") {
+			t.Errorf("Expected kubectl to use the namespace, got %q", call)
+		}
+	}
+	if data, err := os.ReadFile(manifest); err != nil || !strings.Contains(string(data), `"image":"tasks"`) {
+		t.Errorf("Expected the job's manifest on stdin, got %s (%v)", data, err)
+	}
+}
+
+func TestKubernetesProviderFailedJob(t *testing.T) {
+	fakeKubectl(t, `case "$*" in
+*logs*) echo "tests failed" ;;
+*"get job"*) echo Failed ;;
+*"get pods"*) echo 3 ;;
+esac
+`)
+	bus := &recordingPublisher{}
+
+	ExecuteTaskWithOptions("test", ExecOptions{Provider: ProviderKubernetes, Kubernetes: KubernetesJob{Image: "tasks"}}, bus)
+
+	errs := bus.ofType(TypeTaskError)
+	if len(errs) != 1 {
+		t.Fatalf("Expected a single task error, got %d", len(errs))
+	}
+	if got := errs[0].Error().Error(); got != "task failed with exit code 3" {
+		t.Errorf("Expected the pod's exit code, got %q", got)
+	}
+}
+
+func TestKubernetesProviderTimeoutDeletesJob(t *testing.T) {
+	calls := fakeKubectl(t, `case "$*" in
+*logs*) exec sleep 5 ;;
+esac
+`)
+	bus := &recordingPublisher{}
+
+	opts := ExecOptions{Provider: ProviderKubernetes, Timeout: 200 * time.Millisecond, Kubernetes: KubernetesJob{Image: "tasks"}}
+	ExecuteTaskWithOptions("slow", opts, bus)
+
+	errs := bus.ofType(TypeTaskError)
+	if len(errs) != 1 || !errors.Is(errs[0].Error(), ErrTimeout) {
+		t.Fatalf("Expected a timeout error, got %v", errs)
+	}
+	var deleted bool
+	for _, call := range readCalls(t, calls) {
+		deleted = deleted || strings.HasPrefix(call, "delete job/tash-slow-")
+	}
+	if !deleted {
+		t.Errorf("Expected the job to be deleted, got calls %v", readCalls(t, calls))
+	}
+}
+
+func TestKubernetesProviderRequiresImage(t *testing.T) {
+	bus := &recordingPublisher{}
+
+	ExecuteTaskWithOptions("build", ExecOptions{Provider: ProviderKubernetes}, bus)
+
+	if errs := bus.ofType(TypeTaskError); len(errs) != 1 || !strings.Contains(errs[0].Error().Error(), "no image") {
+		t.Errorf("Expected an error about the missing image, got %v", errs)
+	}
+}
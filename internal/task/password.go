@@ -0,0 +1,25 @@
+package task
+
+import (
+	"bufio"
+	"regexp"
+)
+
+// passwordPrompt matches output ending in a request for a password or passphrase, such as
+// "[sudo] password for ann: " or "Enter passphrase for key '/home/ann/.ssh/id_ed25519': "
+var passwordPrompt = regexp.MustCompile(`(?i)\b(password|passphrase)\b[^\n]*:\s*$`)
+
+// scanPromptLines splits output into lines like bufio.ScanLines, and also returns the output
+// read so far without a line ending when it ends in a password prompt, as the task writes
+// nothing more until the prompt is answered. prompted is set while such a token is returned.
+func scanPromptLines(prompted *bool) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		*prompted = false
+		advance, token, err := bufio.ScanLines(data, atEOF)
+		if advance > 0 || err != nil || atEOF || !passwordPrompt.Match(data) {
+			return advance, token, err
+		}
+		*prompted = true
+		return len(data), data, nil
+	}
+}
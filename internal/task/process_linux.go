@@ -0,0 +1,23 @@
+//go:build linux
+
+package task
+
+import "syscall"
+
+// ioprio_set arguments selecting the best-effort class at its lowest priority for a process group
+const (
+	ioprioWhoPgrp    = 2
+	ioprioClassBE    = 2
+	ioprioClassShift = 13
+	ioprioLowest     = 7
+)
+
+// lowerIOPriority gives the process group pgid the lowest best-effort IO priority, like
+// ionice -c 2 -n 7
+func lowerIOPriority(pgid int) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOPRIO_SET, ioprioWhoPgrp, uintptr(pgid), ioprioClassBE<<ioprioClassShift|ioprioLowest)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !windows
+
+package task
+
+// lowerIOPriority does nothing where IO priorities aren't supported
+func lowerIOPriority(pgid int) error {
+	return nil
+}
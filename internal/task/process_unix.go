@@ -0,0 +1,78 @@
+//go:build !windows
+
+// Process groups, signals and priorities are platform specific; everything using them lives in
+// the build-tagged process_*.go files so the rest of the package builds everywhere.
+
+package task
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// processTree is a started task and the processes it starts, which share its process group
+type processTree struct {
+	pgid int
+}
+
+// newProcessTree tracks the processes of a started command
+func newProcessTree(cmd *exec.Cmd) (*processTree, error) {
+	return &processTree{pgid: cmd.Process.Pid}, nil
+}
+
+// interrupt asks every process of the tree to stop by sending SIGINT to the process group
+func (t *processTree) interrupt() error {
+	return syscall.Kill(-t.pgid, syscall.SIGINT)
+}
+
+// kill stops every process left in the tree by sending SIGKILL to the process group
+func (t *processTree) kill() error {
+	if err := syscall.Kill(-t.pgid, syscall.SIGKILL); err != nil && !errors.Is(err, syscall.ESRCH) {
+		return err
+	}
+	return nil
+}
+
+// release frees what tracks the tree; process groups need nothing freed
+func (t *processTree) release() {}
+
+// PauseTaskProcess suspends a running task process group by sending a SIGSTOP signal
+func PauseTaskProcess(p *os.Process) error {
+	return syscall.Kill(-p.Pid, syscall.SIGSTOP)
+}
+
+// ResumeTaskProcess resumes a suspended task process group by sending a SIGCONT signal
+func ResumeTaskProcess(p *os.Process) error {
+	return syscall.Kill(-p.Pid, syscall.SIGCONT)
+}
+
+// TaskProcessAttr returns the system process attributes for task execution. Low priority tasks
+// are lowered by LowerTaskPriority once started, as Unix has no attribute for it.
+func TaskProcessAttr(lowPriority bool) *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{
+		Setpgid: true,
+	}
+}
+
+// lowPriorityNice is the niceness of low priority tasks
+const lowPriorityNice = 10
+
+// LowerTaskPriority lowers the CPU priority of a started task's process group, and its IO
+// priority where supported, like nice and ionice. The processes the task starts inherit it.
+func LowerTaskPriority(p *os.Process) error {
+	if err := syscall.Setpriority(syscall.PRIO_PGRP, p.Pid, lowPriorityNice); err != nil {
+		return err
+	}
+	return lowerIOPriority(p.Pid)
+}
+
+// ShellArgs returns the arguments running command with the user's shell
+func ShellArgs(command string) []string {
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "/bin/sh"
+	}
+	return []string{shell, "-c", command}
+}
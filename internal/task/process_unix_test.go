@@ -0,0 +1,29 @@
+//go:build !windows
+
+package task
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeoutKillsChildrenIgnoringInterrupt(t *testing.T) {
+	// background commands of a non-interactive shell ignore SIGINT, and this one keeps the
+	// task's output open, so the run only ends once it has been killed
+	fakeTaskBinary(t, "sleep 30 &\nwait\n")
+	bus := &recordingPublisher{}
+
+	start := time.Now()
+	ExecuteTaskWithOptions("compose", ExecOptions{Timeout: 200 * time.Millisecond, GracePeriod: 200 * time.Millisecond}, bus)
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the run to end after the grace period, took %s", elapsed)
+	}
+	var killed bool
+	for _, m := range bus.ofType(TypeTaskOutputErr) {
+		killed = killed || m.Output() == "Task still running, killing its processes"
+	}
+	if !killed {
+		t.Error("Expected the escalation to be reported")
+	}
+}
@@ -4,17 +4,98 @@ package task
 
 import (
 	"os"
+	"os/exec"
 	"syscall"
+
+	"golang.org/x/sys/windows"
 )
 
-// StopTaskProcess stops a running task process by killing it
-func StopTaskProcess(p *os.Process) error {
-	return p.Kill()
+// processTree is a started task and the processes it starts, which are assigned to a job object
+// along with it
+type processTree struct {
+	process *os.Process
+	job     windows.Handle
 }
 
-// TaskProcessAttr returns the system process attributes for task execution on Windows
-func TaskProcessAttr() *syscall.SysProcAttr {
-	return &syscall.SysProcAttr{
+// newProcessTree assigns a started command to a new job object, which the processes it starts
+// join. Processes started before the assignment escape the job; on error the tree falls back to
+// the command's own process.
+func newProcessTree(cmd *exec.Cmd) (*processTree, error) {
+	t := &processTree{process: cmd.Process}
+	job, err := windows.CreateJobObject(nil, nil)
+	if err != nil {
+		return t, err
+	}
+	h, err := windows.OpenProcess(windows.PROCESS_SET_QUOTA|windows.PROCESS_TERMINATE, false, uint32(cmd.Process.Pid))
+	if err != nil {
+		_ = windows.CloseHandle(job)
+		return t, err
+	}
+	defer windows.CloseHandle(h)
+	if err := windows.AssignProcessToJobObject(job, h); err != nil {
+		_ = windows.CloseHandle(job)
+		return t, err
+	}
+	t.job = job
+	return t, nil
+}
+
+// interrupt asks the tree to stop with ctrl+break, which reaches every console process of the
+// task's process group
+func (t *processTree) interrupt() error {
+	return windows.GenerateConsoleCtrlEvent(windows.CTRL_BREAK_EVENT, uint32(t.process.Pid))
+}
+
+// kill terminates every process of the job, or only the task's process without one
+func (t *processTree) kill() error {
+	if t.job == 0 {
+		return t.process.Kill()
+	}
+	return windows.TerminateJobObject(t.job, 1)
+}
+
+// release closes the job object; the processes in it keep running
+func (t *processTree) release() {
+	if t.job != 0 {
+		_ = windows.CloseHandle(t.job)
+	}
+}
+
+// PauseTaskProcess is not supported on Windows
+func PauseTaskProcess(p *os.Process) error {
+	return ErrNotSupported
+}
+
+// ResumeTaskProcess is not supported on Windows
+func ResumeTaskProcess(p *os.Process) error {
+	return ErrNotSupported
+}
+
+// belowNormalPriorityClass is the BELOW_NORMAL_PRIORITY_CLASS process creation flag
+const belowNormalPriorityClass = 0x00004000
+
+// TaskProcessAttr returns the system process attributes for task execution on Windows, where low
+// priority tasks are created in the below normal priority class
+func TaskProcessAttr(lowPriority bool) *syscall.SysProcAttr {
+	attr := &syscall.SysProcAttr{
 		CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP,
 	}
+	if lowPriority {
+		attr.CreationFlags |= belowNormalPriorityClass
+	}
+	return attr
+}
+
+// LowerTaskPriority does nothing on Windows, where TaskProcessAttr sets the priority class
+func LowerTaskPriority(p *os.Process) error {
+	return nil
+}
+
+// ShellArgs returns the arguments running command with the command interpreter
+func ShellArgs(command string) []string {
+	shell := os.Getenv("COMSPEC")
+	if shell == "" {
+		shell = "cmd.exe"
+	}
+	return []string{shell, "/C", command}
 }
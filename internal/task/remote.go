@@ -0,0 +1,44 @@
+package task
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// IsRemoteTaskfile reports whether taskfile is the URL of a remote Taskfile, fetched over https
+// or from a git repository, rather than a path
+func IsRemoteTaskfile(taskfile string) bool {
+	return strings.HasPrefix(taskfile, "https://") || strings.HasPrefix(taskfile, "http://") ||
+		strings.Contains(taskfile, ".git//")
+}
+
+// NotTrusted reports whether the listing failed because task asks whether to trust a remote
+// Taskfile, which it can't without a terminal
+func (e *ListError) NotTrusted() bool {
+	for _, line := range e.Stderr {
+		if strings.Contains(line, "not trusted") {
+			return true
+		}
+	}
+	return false
+}
+
+// TrustRemoteTaskfile answers yes to task's question whether to trust the remote Taskfile of
+// env, by listing its tasks with --yes. task remembers the Taskfile's checksum, so the listings
+// and runs that follow aren't asked again until the Taskfile changes.
+func TrustRemoteTaskfile(env Environment) error {
+	cmd := env.Command(context.Background(), nil, "task", "--list-all", "--yes")
+	slog.Debug("exec", "args", cmd.Args)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestIsRemoteTaskfile(t *testing.T) {
+	for taskfile, remote := range map[string]bool{
+		"https://example.com/Taskfile.yml":                      true,
+		"https://github.com/org/tasks.git//Taskfile.yml?ref=v1": true,
+		"git@github.com:org/tasks.git//Taskfile.yml":            true,
+		"./Taskfile.yml": false,
+		"":               false,
+	} {
+		if got := IsRemoteTaskfile(taskfile); got != remote {
+			t.Errorf("IsRemoteTaskfile(%q) = %v, want %v", taskfile, got, remote)
+		}
+	}
+}
+
+func TestCommandUsesTheRemoteTaskfile(t *testing.T) {
+	t.Setenv("TASK_X_REMOTE_TASKFILES", "")
+	os.Unsetenv("TASK_X_REMOTE_TASKFILES")
+	env := Environment{Taskfile: "https://example.com/Taskfile.yml"}
+
+	cmd := env.Command(context.Background(), nil, "task", "--list-all")
+	if got := strings.Join(cmd.Args[1:], " "); got != "--taskfile https://example.com/Taskfile.yml --list-all" {
+		t.Errorf("Expected the Taskfile to be passed to task, got %q", got)
+	}
+	if !slices.Contains(cmd.Env, "TASK_X_REMOTE_TASKFILES=1") {
+		t.Error("Expected the remote Taskfiles experiment to be enabled")
+	}
+	if cmd := env.Command(context.Background(), nil, "sh", "-c", "true"); len(cmd.Args) != 3 {
+		t.Errorf("Expected other commands to be left alone, got %v", cmd.Args)
+	}
+}
+
+func TestTrustRemoteTaskfile(t *testing.T) {
+	args := filepath.Join(t.TempDir(), "args")
+	fakeTaskBinary(t, `echo "$@" > `+args+"\n")
+	if err := TrustRemoteTaskfile(Environment{Taskfile: "https://example.com/Taskfile.yml"}); err != nil {
+		t.Fatalf("TrustRemoteTaskfile() error = %v", err)
+	}
+	data, err := os.ReadFile(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(data)); got != "--taskfile https://example.com/Taskfile.yml --list-all --yes" {
+		t.Errorf("Expected task to be told to trust the Taskfile, got %q", got)
+	}
+
+	listErr := &ListError{Err: errors.New("exit status 1"), Stderr: []string{`task: Taskfile "https://example.com/Taskfile.yml" not trusted by user`}}
+	if !listErr.NotTrusted() {
+		t.Error("Expected the listing to be reported as untrusted")
+	}
+}
@@ -0,0 +1,26 @@
+package task
+
+import "strings"
+
+// SSHArgs returns the command line running args on host over ssh, in dir on the host when set.
+// ssh runs the command with the remote user's shell, so the arguments are quoted for it;
+// BatchMode makes ssh fail rather than prompt for a password or host key no one can answer.
+func SSHArgs(host, dir string, args []string) []string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellQuote(arg)
+	}
+	command := strings.Join(quoted, " ")
+	if dir != "" {
+		command = "cd " + shellQuote(dir) + " && " + command
+	}
+	return []string{"ssh", "-o", "BatchMode=yes", host, "--", command}
+}
+
+// shellQuote quotes s for a POSIX shell, leaving plain words alone
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_=+:,./@%") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,29 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"time"
+)
+
+// statusTimeout bounds a status check, which may have to checksum many sources
+const statusTimeout = 10 * time.Second
+
+// UpToDate reports whether taskId is up to date, running "task --status", which exits with a
+// non-zero status when the task would run. Tasks without sources or status checks are never up
+// to date.
+func UpToDate(ctx context.Context, env Environment, taskId string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, statusTimeout)
+	defer cancel()
+	err := env.Command(ctx, nil, "task", "--status", taskId).Run()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.As(err, &exitErr) && ctx.Err() == nil:
+		return false, nil
+	default:
+		return false, err
+	}
+}
@@ -0,0 +1,21 @@
+package task
+
+import (
+	"context"
+	"testing"
+)
+
+func TestUpToDate(t *testing.T) {
+	// only the "fresh" task is up to date
+	fakeTaskBinary(t, `[ "$1" = --status ] && [ "$2" = fresh ]`+"\n")
+
+	for id, expected := range map[string]bool{"fresh": true, "stale": false} {
+		got, err := UpToDate(context.Background(), Environment{}, id)
+		if err != nil {
+			t.Fatalf("UpToDate(%s) error = %v", id, err)
+		}
+		if got != expected {
+			t.Errorf("Expected UpToDate(%s) to be %v, got %v", id, expected, got)
+		}
+	}
+}
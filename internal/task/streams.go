@@ -0,0 +1,65 @@
+package task
+
+import (
+	"io"
+	"os"
+	"os/exec"
+)
+
+// outputStream is a readable task output stream and the message type its lines are published as
+type outputStream struct {
+	reader  io.ReadCloser
+	msgType Type
+}
+
+// taskStreams holds the output streams of a command and any pipe ends owned by the parent
+type taskStreams struct {
+	readers []outputStream
+	writers []io.Closer
+}
+
+// closeWriters closes the parent's copies of pipe write ends, once the child has been started
+func (s taskStreams) closeWriters() {
+	for _, w := range s.writers {
+		_ = w.Close()
+	}
+}
+
+// closeAll closes every pipe end of the parent, when the command couldn't be started
+func (s taskStreams) closeAll() {
+	s.closeWriters()
+	for _, r := range s.readers {
+		_ = r.reader.Close()
+	}
+}
+
+// outputStreams connects the command's stdout and stderr. When merge is set both are attached to
+// the write end of a single pipe, so the kernel preserves the order in which lines were written.
+func outputStreams(command *exec.Cmd, merge bool) (taskStreams, error) {
+	if merge {
+		r, w, err := os.Pipe()
+		if err != nil {
+			return taskStreams{}, err
+		}
+		command.Stdout = w
+		command.Stderr = w
+		return taskStreams{
+			readers: []outputStream{{reader: r, msgType: TypeTaskOutput}},
+			writers: []io.Closer{w},
+		}, nil
+	}
+	stdout, err := command.StdoutPipe()
+	if err != nil {
+		return taskStreams{}, err
+	}
+	stderr, err := command.StderrPipe()
+	if err != nil {
+		return taskStreams{}, err
+	}
+	return taskStreams{
+		readers: []outputStream{
+			{reader: stdout, msgType: TypeTaskOutput},
+			{reader: stderr, msgType: TypeTaskOutputErr},
+		},
+	}, nil
+}
@@ -0,0 +1,59 @@
+package task
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"os/exec"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestOutputStreamsMergePreservesOrder(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+	command := exec.Command("sh", "-c", "echo one; echo two >&2; echo three")
+	streams, err := outputStreams(command, true)
+	if err != nil {
+		t.Fatalf("outputStreams() error = %v", err)
+	}
+	if len(streams.readers) != 1 {
+		t.Fatalf("Expected a single merged stream, got %d", len(streams.readers))
+	}
+	if err := command.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	streams.closeWriters()
+
+	var lines []string
+	scanner := bufio.NewScanner(streams.readers[0].reader)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := command.Wait(); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	expected := []string{"one", "two", "three"}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Errorf("Expected %v, got %v", expected, lines)
+	}
+}
+
+func TestOutputStreamsCloseAllReleasesTheReader(t *testing.T) {
+	command := exec.Command("tash-no-such-binary")
+	streams, err := outputStreams(command, true)
+	if err != nil {
+		t.Fatalf("outputStreams() error = %v", err)
+	}
+	if err := command.Start(); err == nil {
+		t.Fatal("Expected the command not to start")
+	}
+	streams.closeAll()
+
+	if _, err := streams.readers[0].reader.Read(make([]byte, 1)); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("Expected the pipe's read end to be closed, got %v", err)
+	}
+}
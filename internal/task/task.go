@@ -6,17 +6,52 @@ import (
 	"errors"
 	"fmt"
 	"github.com/Aj4x/tash/internal/msgbus"
+	"github.com/Aj4x/tash/internal/redact"
+	"io"
+	"log/slog"
 	"os/exec"
+	"regexp"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 )
 
 // Task represents a task from the Taskfile
 type Task struct {
-	Id      string   `json:"name"`
-	Desc    string   `json:"desc,omitempty"`
-	Summary string   `json:"summary,omitempty"`
-	Aliases []string `json:"aliases,omitempty"`
+	Id       string    `json:"name"`
+	Desc     string    `json:"desc,omitempty"`
+	Summary  string    `json:"summary,omitempty"`
+	Aliases  []string  `json:"aliases,omitempty"`
+	UpToDate bool      `json:"up_to_date,omitempty"`
+	Internal bool      `json:"internal,omitempty"`
+	Location *Location `json:"location,omitempty"`
+	// Vars are passed to the runs of the task as NAME=value; they select the item of a loop
+	// variant
+	Vars []string `json:"-"`
+	// LoopOf is the id of the task whose for loop generated this variant of the task, empty for
+	// listed tasks
+	LoopOf string `json:"-"`
+	// Command runs the task in place of the task binary, for tasks not defined in a Taskfile such
+	// as the jobs of CI pipelines
+	Command []string `json:"-"`
+}
+
+// Location describes where a task is defined
+type Location struct {
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Taskfile string `json:"taskfile"`
+}
+
+// Namespace returns the namespace part of the task id (e.g. "sys" for "sys:disk-space"),
+// or an empty string for tasks in the root namespace
+func (t Task) Namespace() string {
+	i := strings.LastIndex(t.Id, ":")
+	if i < 0 {
+		return ""
+	}
+	return t.Id[:i]
 }
 
 type Type string
@@ -39,8 +74,10 @@ const (
 	TypeTaskJSON        = Type("task.json")
 	TypeTaskCommand     = Type("task.command")
 	TypeTaskDone        = Type("task.done")
-	TypeTaskListAllDone = Type("list.done")
+	TypeTaskListPartial = Type("list.partial")
 	TypeTaskListAllErr  = Type("list.error")
+	TypeWatchTrigger    = Type("watch.trigger")
+	TypeTaskPassword    = Type("task.password")
 )
 
 type Message struct {
@@ -63,6 +100,9 @@ const (
 	CtxKeyOutput      = ContextKey("output")
 	CtxKeyCommand     = ContextKey("command")
 	CtxKeyTaskRunning = ContextKey("taskRunning")
+	CtxKeyTaskId      = ContextKey("taskId")
+	CtxKeyTasks       = ContextKey("tasks")
+	CtxKeyInput       = ContextKey("input")
 )
 
 func (m Message) Error() error {
@@ -105,6 +145,48 @@ func (m Message) SetTaskRunning(isRunning bool) Message {
 	return m
 }
 
+func (m Message) TaskId() string {
+	val := m.ctx.Value(CtxKeyTaskId)
+	if val == nil {
+		return ""
+	}
+	return val.(string)
+}
+
+func (m Message) SetTaskId(taskId string) Message {
+	m.ctx = context.WithValue(m.ctx, CtxKeyTaskId, taskId)
+	return m
+}
+
+// Tasks returns the tasks listed so far carried by a partial listing message
+func (m Message) Tasks() []Task {
+	val := m.ctx.Value(CtxKeyTasks)
+	if val == nil {
+		return nil
+	}
+	return val.([]Task)
+}
+
+func (m Message) SetTasks(tasks []Task) Message {
+	m.ctx = context.WithValue(m.ctx, CtxKeyTasks, tasks)
+	return m
+}
+
+// Input returns the stdin of the task carried by a password prompt message, which the password
+// is written to; closing it declines the prompt
+func (m Message) Input() io.WriteCloser {
+	val := m.ctx.Value(CtxKeyInput)
+	if val == nil {
+		return nil
+	}
+	return val.(io.WriteCloser)
+}
+
+func (m Message) SetInput(w io.WriteCloser) Message {
+	m.ctx = context.WithValue(m.ctx, CtxKeyInput, w)
+	return m
+}
+
 func (m Message) Wait() {
 	if m.Type != TypeTaskCommand {
 		return
@@ -119,9 +201,14 @@ func (m Message) CancelFunc() context.CancelFunc {
 	return m.ctxCancel
 }
 
-// ListAllJson executes the "task --list-all --json" command and sends the resulting JSON to the message bus.
-func ListAllJson(bus msgbus.Publisher[Message]) {
-	cmd := exec.Command("task", "--list-all", "--json")
+// ListAllJson executes the "task --list-all --json" command and sends the resulting JSON to the
+// message bus. The tasks are also sent in batches as the listing is read, so huge catalogs show
+// up progressively.
+// Task versions without JSON listing have their text listing parsed and converted to the same JSON.
+func ListAllJson(env Environment, bus msgbus.Publisher[Message]) {
+	args := listArgs(env)
+	cmd := env.Command(context.Background(), nil, append([]string{"task"}, args...)...)
+	slog.Debug("exec", "args", cmd.Args)
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
 		bus.Publish(TypeTaskListAllErr.Message().SetError(err).TopicMessage())
@@ -136,148 +223,462 @@ func ListAllJson(bus msgbus.Publisher[Message]) {
 		bus.Publish(TypeTaskListAllErr.Message().SetError(err).TopicMessage())
 		return
 	}
-	var taskOut string
-	stdoutScanner := bufio.NewScanner(stdout)
+	var taskOut strings.Builder
 	stdErrScanner := bufio.NewScanner(stderr)
-	wg := sync.WaitGroup{}
+	readers := sync.WaitGroup{}
+	readers.Add(2)
 	go func() {
-		started := false
-		for stdoutScanner.Scan() {
-			if !started {
-				wg.Add(1)
-				started = true
-			}
-			taskOut += stdoutScanner.Text()
+		defer readers.Done()
+		listed := io.TeeReader(stdout, &taskOut)
+		publish := func(tasks []Task) {
+			bus.Publish(TypeTaskListPartial.Message().SetTasks(tasks).TopicMessage())
 		}
-		if started {
-			wg.Done()
+		if slices.Contains(args, "--json") {
+			if err := streamTasksJson(listed, publish); err != nil {
+				// the complete listing is parsed again once read, which reports the error
+				slog.Debug("Unable to stream the task listing", "error", err)
+			}
+		} else {
+			streamTaskList(listed, publish)
 		}
+		// the rest of the listing is still needed for the complete JSON
+		_, _ = io.Copy(io.Discard, listed)
 	}()
+	// stderr is kept for the diagnostics of a failed listing, and otherwise shown as output
+	var stderrLines []string
 	go func() {
-		started := false
+		defer readers.Done()
 		for stdErrScanner.Scan() {
-			if !started {
-				wg.Add(1)
-				started = true
-			}
-			bus.Publish(TypeTaskOutputErr.Message().SetOutput(stdErrScanner.Text()).TopicMessage())
-		}
-		if started {
-			wg.Done()
+			stderrLines = append(stderrLines, stdErrScanner.Text())
 		}
 	}()
-	wg.Add(1)
-	go func() {
-		err := cmd.Wait()
+	// all output must be read before waiting on the command, as Wait closes the pipes
+	readers.Wait()
+	if err := cmd.Wait(); err != nil {
+		bus.Publish(TypeTaskListAllErr.Message().SetError(&ListError{Err: err, Stderr: stderrLines}).TopicMessage())
+		return
+	}
+	for _, line := range stderrLines {
+		bus.Publish(TypeTaskOutputErr.Message().SetOutput(line).TopicMessage())
+	}
+	if taskOut.Len() > 0 {
+		jsonOut, err := listingJson(taskOut.String(), args)
 		if err != nil {
-			bus.Publish(TypeTaskOutputErr.
-				Message().
-				SetOutput(fmt.Sprintf("error getting task list: %s", err)).
-				TopicMessage(),
-			)
-			wg.Done()
+			bus.Publish(TypeTaskListAllErr.Message().SetError(err).TopicMessage())
+			return
 		}
-		wg.Done()
-	}()
-	wg.Wait()
-	if len(taskOut) > 0 {
-		bus.Publish(TypeTaskJSON.Message().SetOutput(taskOut).TopicMessage())
+		bus.Publish(TypeTaskJSON.Message().SetOutput(jsonOut).TopicMessage())
 	}
 }
 
-// ParseTaskLine parses a task line from the task --list-all output
+// ListError is the failure of the task listing, with what task wrote to stderr, which usually
+// explains what is wrong with the Taskfile
+type ListError struct {
+	Err    error
+	Stderr []string
+}
+
+// Error returns the failure and the first line task reported
+func (e *ListError) Error() string {
+	for _, line := range e.Stderr {
+		if line = strings.TrimSpace(ansiPattern.ReplaceAllString(line, "")); line != "" {
+			return fmt.Sprintf("error getting task list: %s: %s", e.Err, line)
+		}
+	}
+	return fmt.Sprintf("error getting task list: %s", e.Err)
+}
+
+// Unwrap returns the error of the listing command
+func (e *ListError) Unwrap() error {
+	return e.Err
+}
+
+// ansiPattern matches the color escape sequences task adds when it thinks it is writing to a terminal
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+
+// ParseTaskLine parses a task line from the task --list-all output. It is the fallback for task
+// versions without JSON listing, so it tolerates colors, tabs, colons in descriptions and
+// irregular alias spacing.
 func ParseTaskLine(taskMsg string) (Task, bool) {
-	line, ok := strings.CutPrefix(taskMsg, "* ")
+	line := strings.TrimSpace(ansiPattern.ReplaceAllString(taskMsg, ""))
+	line, ok := strings.CutPrefix(line, "*")
 	if !ok {
 		return Task{}, false
 	}
-	t := Task{}
-	id, line, ok := strings.Cut(line, ": ")
-	if ok {
-		t.Id = id
-	}
-	var aliases []string
-	desc, aliasStr, ok := strings.Cut(line, "(aliases:")
-	if ok {
-		aliasStr, _ = strings.CutSuffix(aliasStr, ")")
-		aliases = strings.Split(aliasStr, ",")
+	id, rest, ok := cutTaskId(strings.TrimSpace(line))
+	if !ok {
+		return Task{}, false
 	}
+	desc, aliases := cutAliases(rest)
 	return Task{
 		Id:      id,
-		Desc:    strings.TrimSpace(desc),
+		Desc:    desc,
 		Aliases: aliases,
 	}, true
 }
 
-// ExecuteTask runs a task
+// cutTaskId splits a line at the colon ending the task id, which is the first colon followed by
+// whitespace or the end of the line, as ids may contain namespace colons
+func cutTaskId(line string) (id, rest string, ok bool) {
+	for i := 0; i < len(line); i++ {
+		if line[i] != ':' || (i+1 < len(line) && line[i+1] != ' ' && line[i+1] != '\t') {
+			continue
+		}
+		id = line[:i]
+		if id == "" || strings.ContainsAny(id, " \t") {
+			return "", "", false
+		}
+		return id, line[i+1:], true
+	}
+	return "", "", false
+}
+
+// cutAliases splits a trailing "(aliases: a, b)" from a task description
+func cutAliases(s string) (desc string, aliases []string) {
+	s = strings.TrimSpace(s)
+	i := strings.LastIndex(s, "(aliases:")
+	if i < 0 || !strings.HasSuffix(s, ")") {
+		return s, nil
+	}
+	for _, alias := range strings.Split(s[i+len("(aliases:"):len(s)-1], ",") {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			aliases = append(aliases, alias)
+		}
+	}
+	return strings.TrimSpace(s[:i]), aliases
+}
+
+// ExecOptions controls how a task is executed
+type ExecOptions struct {
+	// MergeOutput combines stdout and stderr into a single pipe, so lines are reported in the
+	// order the task wrote them. Merged output is published as TypeTaskOutput.
+	MergeOutput bool
+	// Timeout cancels the task's process group once exceeded; zero means no timeout
+	Timeout time.Duration
+	// Retries is the number of times a failed attempt is retried
+	Retries int
+	// RetryBackoff is the delay before the first retry, doubling for each subsequent retry
+	RetryBackoff time.Duration
+	// Provider runs the task; empty or ProviderTask uses the task binary
+	Provider string
+	// Shell runs the task id as a command line with the user's shell instead of as a task
+	Shell bool
+	// Vars are passed to the task as NAME=value arguments, e.g. for its required variables
+	Vars []string
+	// AssumeYes answers the task's prompts, which have been confirmed already
+	AssumeYes bool
+	// Verbose runs task with --verbose, reporting what it does
+	Verbose bool
+	// Silent runs task with --silent, hiding the commands it runs
+	Silent bool
+	// Force runs the task even when it is up to date
+	Force bool
+	// Dry prints the task's commands without running them
+	Dry bool
+	// Watch reruns the task when its sources change, until it is cancelled
+	Watch bool
+	// Parallel runs the task's dependencies and the tasks named in Args in parallel
+	Parallel bool
+	// Args are passed to the task after "--", available in the Taskfile as CLI_ARGS
+	Args []string
+	// Env is added to the task's environment as NAME=value entries
+	Env []string
+	// Environment is the working directory and environment the task runs in
+	Environment Environment
+	// LowPriority runs the task at reduced CPU and IO priority, so it doesn't starve interactive
+	// programs
+	LowPriority bool
+	// PasswordInput connects the task's stdin to a pipe and publishes TypeTaskPassword when its
+	// output stops at a password prompt, so the password can be written to it; otherwise the
+	// task's stdin is empty
+	PasswordInput bool
+	// Redactor masks secrets in the task's output and in the logged command; the values of the
+	// task's secret-looking variables are masked too. Nil leaves both alone.
+	Redactor *redact.Redactor
+	// GracePeriod is how long a cancelled task has to exit after being interrupted before all of
+	// its processes are killed; zero means five seconds
+	GracePeriod time.Duration
+	// Host runs the task on this host over ssh instead of locally, in HostDir on the host when
+	// set; Env doesn't reach the host
+	Host    string
+	HostDir string
+	// Kubernetes is where the task runs as a Job when Provider is ProviderKubernetes
+	Kubernetes KubernetesJob
+	// Command runs in place of the task binary, with the task id only naming the run; Vars,
+	// Args and the task flags don't apply to it
+	Command []string
+}
+
+// TaskArgs returns the command line running taskId with opts
+func TaskArgs(taskId string, opts ExecOptions) []string {
+	args := []string{"task"}
+	if opts.AssumeYes {
+		args = append(args, "--yes")
+	}
+	if opts.Verbose {
+		args = append(args, "--verbose")
+	}
+	if opts.Silent {
+		args = append(args, "--silent")
+	}
+	for _, flag := range []struct {
+		set  bool
+		name string
+	}{
+		{opts.Force, "--force"},
+		{opts.Dry, "--dry"},
+		{opts.Watch, "--watch"},
+		{opts.Parallel, "--parallel"},
+	} {
+		if flag.set {
+			args = append(args, flag.name)
+		}
+	}
+	args = append(args, taskId)
+	args = append(args, opts.Vars...)
+	if len(opts.Args) > 0 {
+		args = append(append(args, "--"), opts.Args...)
+	}
+	return args
+}
+
+// Error represents a textual error value that implements the error interface.
+type Error string
+
+// Error returns the string representation of the Error type. It satisfies the error interface.
+func (e Error) Error() string {
+	return string(e)
+}
+
+// ErrTimeout is wrapped by the error published when a task exceeds its timeout
+// ErrNotSupported is returned by process operations that are unavailable on the current platform
+const (
+	ErrTimeout      = Error("task timed out")
+	ErrNotSupported = Error("not supported on this platform")
+)
+
+// ExecuteTask runs a task with the default options
 func ExecuteTask(taskId string, bus msgbus.Publisher[Message]) {
-	msg := TypeTaskCommand.Message()
+	ExecuteTaskWithOptions(taskId, ExecOptions{}, bus)
+}
+
+// ExecuteShellCommand runs an ad-hoc command line with the user's shell, publishing its output
+// like a task's
+func ExecuteShellCommand(command string, opts ExecOptions, bus msgbus.Publisher[Message]) {
+	opts.Shell = true
+	ExecuteTaskWithOptions(command, opts, bus)
+}
+
+// StartTask runs a task like ExecuteTaskWithOptions in its own goroutine and returns at once. The
+// run is followed through the messages it publishes, from its command to its done or error
+// message.
+func StartTask(taskId string, opts ExecOptions, bus msgbus.Publisher[Message]) {
+	go ExecuteTaskWithOptions(taskId, opts, bus)
+}
+
+// StartShellCommand runs an ad-hoc command line like ExecuteShellCommand in its own goroutine
+// and returns at once
+func StartShellCommand(command string, opts ExecOptions, bus msgbus.Publisher[Message]) {
+	go ExecuteShellCommand(command, opts, bus)
+}
+
+// ExecuteTaskWithOptions runs a task using the given execution options, retrying failed attempts
+// according to opts.Retries
+func ExecuteTaskWithOptions(taskId string, opts ExecOptions, bus msgbus.Publisher[Message]) {
+	msg := TypeTaskCommand.Message().SetTaskId(taskId)
 	ctx, cancel := context.WithCancel(msg.ctx)
 	msg.ctx, msg.ctxCancel = ctx, cancel
-	command := exec.CommandContext(msg.ctx, "task", taskId)
-	command.SysProcAttr = TaskProcessAttr()
-	bus.Publish(msg.SetCommand(command).SetTaskRunning(true).TopicMessage())
-	// Add this near the beginning of the ExecuteTask function
-	go func() {
-		<-ctx.Done()
-		// If context is canceled, ensure we clean up properly
-		if ctx.Err() == context.Canceled {
-			// Context was explicitly canceled, not timed out
-			bus.Publish(TypeTaskOutputErr.Message().SetOutput("Task cancellation requested").TopicMessage())
-			if err := StopTaskProcess(command.Process); err != nil {
-				bus.Publish(TypeTaskOutputErr.Message().SetOutput(fmt.Sprintf("Error cancelling task task: %s", err)).TopicMessage())
-			} else {
-				bus.Publish(TypeTaskOutput.Message().SetOutput("Task cancelled").TopicMessage())
-			}
+	// release the context's resources once the task has finished
+	defer cancel()
+
+	attempts := opts.Retries + 1
+	run := runAttempt
+	if opts.Provider == ProviderDemo && !opts.Shell && len(opts.Command) == 0 {
+		run = runDemoAttempt
+	}
+	// ad-hoc commands, commands such as CI jobs and runs on a host stay out of the cluster
+	if opts.Provider == ProviderKubernetes && !opts.Shell && opts.Host == "" && len(opts.Command) == 0 {
+		run = runKubernetesAttempt
+	}
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempts > 1 {
+			bus.Publish(TypeTaskOutput.Message().SetOutput(fmt.Sprintf("Attempt %d/%d", attempt, attempts)).TopicMessage())
 		}
-	}()
-	stdout, err := command.StdoutPipe()
+		err = run(msg, taskId, opts, bus)
+		// stop on success, on explicit cancellation or once attempts are exhausted
+		if err == nil || ctx.Err() != nil || attempt == attempts {
+			break
+		}
+		delay := opts.RetryBackoff << (attempt - 1)
+		bus.Publish(TypeTaskOutputErr.Message().SetOutput(fmt.Sprintf("Attempt %d/%d failed: %s; retrying in %s", attempt, attempts, err, delay)).TopicMessage())
+		select {
+		case <-ctx.Done():
+		case <-time.After(delay):
+		}
+	}
+
+	bus.Publish(TypeTaskCommand.Message().SetCommand(nil).SetTaskRunning(false).TopicMessage())
+
 	if err != nil {
-		bus.Publish(TypeTaskCommand.Message().SetCommand(nil).SetTaskRunning(false).TopicMessage())
 		bus.Publish(TypeTaskError.Message().SetError(err).TopicMessage())
 		return
 	}
-	stderr, err := command.StderrPipe()
+
+	bus.Publish(TypeTaskDone.Message().TopicMessage())
+}
+
+// runAttempt executes a single attempt of a task, publishing its output, and returns the reason it failed.
+// The attempt is cancelled along with msg's context, or when opts.Timeout is exceeded.
+func runAttempt(msg Message, taskId string, opts ExecOptions, bus msgbus.Publisher[Message]) error {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if opts.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(msg.ctx, opts.Timeout)
+	} else {
+		ctx, cancel = context.WithCancel(msg.ctx)
+	}
+	defer cancel()
+	args := TaskArgs(taskId, opts)
+	if opts.Shell {
+		args = ShellArgs(taskId)
+	}
+	if len(opts.Command) > 0 {
+		args = opts.Command
+	}
+	if opts.Host != "" {
+		args = SSHArgs(opts.Host, opts.HostDir, args)
+	}
+	// the process tree is stopped by stopOnCancel rather than by the command's context, which
+	// would only reach the direct child
+	command := opts.Environment.Command(context.Background(), opts.Env, args...)
+	command.SysProcAttr = TaskProcessAttr(opts.LowPriority)
+	redactor := opts.Redactor.WithEnv(command.Environ(), opts.Vars)
+	loggedArgs := make([]string, len(command.Args))
+	for i, arg := range command.Args {
+		loggedArgs[i] = redactor.Redact(arg)
+	}
+	slog.Debug("exec", "args", loggedArgs, "mergeOutput", opts.MergeOutput, "timeout", opts.Timeout)
+	bus.Publish(msg.SetCommand(command).SetTaskRunning(true).TopicMessage())
+
+	streams, err := outputStreams(command, opts.MergeOutput)
 	if err != nil {
-		bus.Publish(TypeTaskCommand.Message().SetCommand(nil).SetTaskRunning(false).TopicMessage())
-		bus.Publish(TypeTaskError.Message().SetError(err).TopicMessage())
-		return
+		return err
+	}
+	var stdin io.WriteCloser
+	if opts.PasswordInput {
+		if stdin, err = command.StdinPipe(); err != nil {
+			streams.closeAll()
+			return err
+		}
 	}
 	if err := command.Start(); err != nil {
-		bus.Publish(TypeTaskCommand.Message().SetCommand(nil).SetTaskRunning(false).TopicMessage())
-		bus.Publish(TypeTaskError.Message().SetError(err).TopicMessage())
-		return
+		streams.closeAll()
+		return err
 	}
-
+	// the child holds its own copy of any pipe we created, so release ours to see EOF when it exits
+	streams.closeWriters()
+	tree, err := newProcessTree(command)
+	if err != nil {
+		bus.Publish(TypeTaskOutputErr.Message().SetOutput(fmt.Sprintf("Unable to track the task's processes: %s", err)).TopicMessage())
+	}
+	defer tree.release()
+	exited, stopped := make(chan struct{}), make(chan struct{})
 	go func() {
-		scanner := bufio.NewScanner(stdout)
-		for scanner.Scan() {
-			bus.Publish(TypeTaskOutput.Message().SetOutput(scanner.Text()).TopicMessage())
-		}
+		defer close(stopped)
+		stopOnCancel(ctx, tree, exited, opts, bus)
 	}()
-
-	go func() {
-		scanner := bufio.NewScanner(stderr)
-		for scanner.Scan() {
-			bus.Publish(TypeTaskOutputErr.Message().SetOutput(scanner.Text()).TopicMessage())
-		}
+	defer func() {
+		close(exited)
+		// the tree is released once nothing is stopping it any more
+		<-stopped
 	}()
+	if opts.LowPriority {
+		if err := LowerTaskPriority(command.Process); err != nil {
+			bus.Publish(TypeTaskOutputErr.Message().SetOutput(fmt.Sprintf("Unable to lower the task's priority: %s", err)).TopicMessage())
+		}
+	}
+
+	readers := sync.WaitGroup{}
+	for _, s := range streams.readers {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			defer s.reader.Close()
+			scanner := bufio.NewScanner(s.reader)
+			var prompted bool
+			if stdin != nil {
+				scanner.Split(scanPromptLines(&prompted))
+			}
+			for scanner.Scan() {
+				line := redactor.Redact(scanner.Text())
+				bus.Publish(s.msgType.Message().SetOutput(line).TopicMessage())
+				if prompted {
+					prompt := strings.TrimSpace(ansiPattern.ReplaceAllString(line, ""))
+					bus.Publish(TypeTaskPassword.Message().SetOutput(prompt).SetInput(stdin).TopicMessage())
+				}
+			}
+		}()
+	}
 
+	// all output must be read before waiting on the command, as Wait closes the pipes
+	readers.Wait()
 	err = command.Wait()
 
-	bus.Publish(TypeTaskCommand.Message().SetCommand(nil).SetTaskRunning(false).TopicMessage())
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("%w after %s", ErrTimeout, opts.Timeout)
+	}
 
+	if err != nil && ctx.Err() != nil {
+		return fmt.Errorf("task failed: %w", ctx.Err())
+	}
 	if err != nil {
 		var exitError *exec.ExitError
 		if errors.As(err, &exitError) {
-			err = fmt.Errorf("task failed with exit code %d: %w", exitError.ExitCode(), err)
-			bus.Publish(TypeTaskError.Message().SetError(err).TopicMessage())
-			return
+			return fmt.Errorf("task failed with exit code %d: %w", exitError.ExitCode(), err)
 		}
-		err = fmt.Errorf("task failed: %w", err)
-		bus.Publish(TypeTaskError.Message().SetError(err).TopicMessage())
+		return fmt.Errorf("task failed: %w", err)
+	}
+	return nil
+}
+
+// defaultGracePeriod is how long a cancelled task has to exit before its processes are killed
+const defaultGracePeriod = 5 * time.Second
+
+// stopOnCancel stops the process tree of a task once ctx ends. The tree is interrupted first, so
+// the task can clean up, and killed if it is still running after the grace period, so children
+// that ignore the interrupt or keep the output open don't outlive the task.
+func stopOnCancel(ctx context.Context, tree *processTree, exited <-chan struct{}, opts ExecOptions, bus msgbus.Publisher[Message]) {
+	select {
+	case <-exited:
 		return
+	case <-ctx.Done():
+	}
+	timedOut := ctx.Err() == context.DeadlineExceeded
+	if timedOut {
+		bus.Publish(TypeTaskOutputErr.Message().SetOutput(fmt.Sprintf("Task timed out after %s, cancelling", opts.Timeout)).TopicMessage())
+	} else {
+		bus.Publish(TypeTaskOutputErr.Message().SetOutput("Task cancellation requested").TopicMessage())
 	}
 
-	bus.Publish(TypeTaskDone.Message().TopicMessage())
+	grace := opts.GracePeriod
+	if grace <= 0 {
+		grace = defaultGracePeriod
+	}
+	if err := tree.interrupt(); err != nil {
+		bus.Publish(TypeTaskOutputErr.Message().SetOutput(fmt.Sprintf("Error interrupting task: %s", err)).TopicMessage())
+		grace = 0
+	} else if !timedOut {
+		bus.Publish(TypeTaskOutput.Message().SetOutput("Task cancelled").TopicMessage())
+	}
+
+	select {
+	case <-exited:
+		return
+	case <-time.After(grace):
+	}
+	bus.Publish(TypeTaskOutputErr.Message().SetOutput("Task still running, killing its processes").TopicMessage())
+	if err := tree.kill(); err != nil {
+		bus.Publish(TypeTaskOutputErr.Message().SetOutput(fmt.Sprintf("Error killing task: %s", err)).TopicMessage())
+	}
 }
@@ -1,7 +1,9 @@
 package task
 
 import (
+	"errors"
 	"reflect"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -27,32 +29,32 @@ func TestParseTaskLine(t *testing.T) {
 		"cowsay": {
 			Id:      "cowsay",
 			Desc:    "Displays a cute ASCII art cow with a greeting message, mimicking the 'cowsay' program",
-			Aliases: []string{" cow", " moo"},
+			Aliases: []string{"cow", "moo"},
 		},
 		"date-time": {
 			Id:      "date-time",
 			Desc:    "Shows the current date and time along with a calendar for the current month",
-			Aliases: []string{" date", " time", " dt"},
+			Aliases: []string{"date", "time", "dt"},
 		},
 		"default": {
 			Id:      "default",
 			Desc:    "List all",
-			Aliases: []string{" list", " ls"},
+			Aliases: []string{"list", "ls"},
 		},
 		"generate-lorem": {
 			Id:      "generate-lorem",
 			Desc:    "Outputs a paragraph of Lorem Ipsum placeholder text that can be used for testing text display capabilities",
-			Aliases: []string{" lorem", " ipsum"},
+			Aliases: []string{"lorem", "ipsum"},
 		},
 		"random-quotes": {
 			Id:      "random-quotes",
 			Desc:    "Shows a collection of famous programming and computer science quotes from well-known figures in the field",
-			Aliases: []string{" quotes", " q"},
+			Aliases: []string{"quotes", "q"},
 		},
 		"weather": {
 			Id:      "weather",
 			Desc:    "Displays a simulated weather forecast for demonstration purposes",
-			Aliases: []string{" wthr", " w"},
+			Aliases: []string{"wthr", "w"},
 		},
 		"cmd:dir": {
 			Id:      "cmd:dir",
@@ -67,22 +69,22 @@ func TestParseTaskLine(t *testing.T) {
 		"sys:disk-space": {
 			Id:      "sys:disk-space",
 			Desc:    "Displays information about disk space usage on all mounted filesystems",
-			Aliases: []string{" df", " disk"},
+			Aliases: []string{"df", "disk"},
 		},
 		"sys:network-info": {
 			Id:      "sys:network-info",
 			Desc:    "Displays information about network interfaces and current network connections",
-			Aliases: []string{" netinfo", " net"},
+			Aliases: []string{"netinfo", "net"},
 		},
 		"sys:process-list": {
 			Id:      "sys:process-list",
 			Desc:    "Displays a list of the top running processes on the system with details about CPU and memory usage",
-			Aliases: []string{" ps", " proc"},
+			Aliases: []string{"ps", "proc"},
 		},
 		"sys:system-info": {
 			Id:      "sys:system-info",
 			Desc:    "Displays detailed information about the current system including OS, CPU, and memory",
-			Aliases: []string{" sysinfo", " si"},
+			Aliases: []string{"sysinfo", "si"},
 		},
 	}
 
@@ -204,3 +206,115 @@ func TestParseTaskLine(t *testing.T) {
 		t.Errorf("Expected 'weather' task to have 2 aliases, got %d", len(weatherTask.Aliases))
 	}
 }
+
+func TestParseTaskLineTolerant(t *testing.T) {
+	tests := map[string]Task{
+		"* build:\tBuild: compile everything   (aliases:b,  bld )": {Id: "build", Desc: "Build: compile everything", Aliases: []string{"b", "bld"}},
+		"\x1b[33m* lint:\x1b[0m  Run the linters\r":                {Id: "lint", Desc: "Run the linters"},
+		"  * docker:compose:up:   Start (the stack)":               {Id: "docker:compose:up", Desc: "Start (the stack)"},
+		"* cmd:dir:":                   {Id: "cmd:dir"},
+		"*deploy: Deploy (aliases: d)": {Id: "deploy", Desc: "Deploy", Aliases: []string{"d"}},
+	}
+	for line, expected := range tests {
+		got, ok := ParseTaskLine(line)
+		if !ok {
+			t.Errorf("ParseTaskLine(%q): expected a task", line)
+			continue
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("ParseTaskLine(%q): expected %+v, got %+v", line, expected, got)
+		}
+	}
+	for _, line := range []string{"", "task: Available tasks for this project:", "* : no id", "* not a task"} {
+		if got, ok := ParseTaskLine(line); ok {
+			t.Errorf("ParseTaskLine(%q): expected no task, got %+v", line, got)
+		}
+	}
+}
+
+func FuzzParseTaskLine(f *testing.F) {
+	for _, seed := range []string{
+		"* cowsay:                 Displays a cute ASCII art cow (aliases: cow, moo)",
+		"* cmd:dir:                ",
+		"* build:\tBuild: compile (aliases:b,,)",
+		"\x1b[32m* lint:\x1b[0m Lint",
+		"task: Available tasks for this project:",
+		"* (aliases: x)",
+		"*:",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, line string) {
+		got, ok := ParseTaskLine(line)
+		if !ok {
+			return
+		}
+		if got.Id == "" || strings.ContainsAny(got.Id, " \t") {
+			t.Errorf("ParseTaskLine(%q): invalid id %q", line, got.Id)
+		}
+		if got.Desc != strings.TrimSpace(got.Desc) {
+			t.Errorf("ParseTaskLine(%q): description %q is not trimmed", line, got.Desc)
+		}
+		for _, alias := range got.Aliases {
+			if alias == "" || alias != strings.TrimSpace(alias) || strings.Contains(alias, ",") {
+				t.Errorf("ParseTaskLine(%q): invalid alias %q", line, alias)
+			}
+		}
+	})
+}
+
+func TestTaskArgs(t *testing.T) {
+	if got := TaskArgs("build", ExecOptions{}); !slices.Equal(got, []string{"task", "build"}) {
+		t.Errorf("Expected task build, got %v", got)
+	}
+	got := TaskArgs("deploy", ExecOptions{Vars: []string{"TARGET=staging"}, AssumeYes: true})
+	if want := []string{"task", "--yes", "deploy", "TARGET=staging"}; !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got := TaskArgs("build", ExecOptions{Verbose: true}); !slices.Equal(got, []string{"task", "--verbose", "build"}) {
+		t.Errorf("Expected task --verbose build, got %v", got)
+	}
+	if got := TaskArgs("build", ExecOptions{Silent: true}); !slices.Equal(got, []string{"task", "--silent", "build"}) {
+		t.Errorf("Expected task --silent build, got %v", got)
+	}
+	got = TaskArgs("test", ExecOptions{Force: true, Dry: true, Watch: true, Parallel: true, Args: []string{"-run", "TestX"}})
+	if want := []string{"task", "--force", "--dry", "--watch", "--parallel", "test", "--", "-run", "TestX"}; !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestSSHArgs(t *testing.T) {
+	got := SSHArgs("deploy@web1", "/srv/my app", []string{"task", "release", "MSG=it's done"})
+	want := []string{"ssh", "-o", "BatchMode=yes", "deploy@web1", "--", `cd '/srv/my app' && task release 'MSG=it'\''s done'`}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got := SSHArgs("web1", "", []string{"task", "build"}); got[len(got)-1] != "task build" {
+		t.Errorf("Expected the task to run in the home directory, got %v", got)
+	}
+}
+
+func TestListAllJsonReportsTheStderrOfAFailedListing(t *testing.T) {
+	fakeTaskBinary(t, `echo 'task: Failed to parse Taskfile.yml:' >&2
+echo 'yaml: line 4: did not find expected key' >&2
+exit 201
+`)
+	bus := &recordingPublisher{}
+
+	ListAllJson(Environment{}, bus)
+
+	failures := bus.ofType(TypeTaskListAllErr)
+	if len(failures) != 1 {
+		t.Fatalf("Expected the listing to fail, got %d failures", len(failures))
+	}
+	var listErr *ListError
+	if !errors.As(failures[0].Error(), &listErr) || len(listErr.Stderr) != 2 {
+		t.Fatalf("Expected the stderr of the listing, got %v", failures[0].Error())
+	}
+	if !strings.Contains(listErr.Error(), "Failed to parse Taskfile.yml") {
+		t.Errorf("Expected the error to tell what failed, got %q", listErr.Error())
+	}
+	if len(bus.ofType(TypeTaskOutputErr)) != 0 {
+		t.Error("Expected the stderr not to be shown as output")
+	}
+}
@@ -0,0 +1,59 @@
+package task
+
+import (
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strings"
+)
+
+// ErrNoTerminal is returned when no external terminal command is configured
+const ErrNoTerminal = Error("no external terminal configured")
+
+// commandPlaceholder marks where the task command goes in an external terminal template
+const commandPlaceholder = "{cmd}"
+
+// ExternalTerminalArgs expands an external terminal template, e.g. "alacritty -e" or
+// "tmux new-window {cmd}", into the arguments running taskId in it. The task command replaces
+// the {cmd} placeholder, or is appended when there is none.
+func ExternalTerminalArgs(template, taskId string) ([]string, error) {
+	fields := strings.Fields(template)
+	if len(fields) == 0 {
+		return nil, ErrNoTerminal
+	}
+	command := []string{"task", taskId}
+	var args []string
+	replaced := false
+	for _, f := range fields {
+		if f == commandPlaceholder {
+			args = append(args, command...)
+			replaced = true
+			continue
+		}
+		args = append(args, f)
+	}
+	if !replaced {
+		args = append(args, command...)
+	}
+	return args, nil
+}
+
+// RunInExternalTerminal starts taskId in a new terminal window described by template, for tasks
+// that need a fully interactive TTY. It returns once the terminal has started; its output is
+// not captured.
+func RunInExternalTerminal(template, taskId string) error {
+	args, err := ExternalTerminalArgs(template, taskId)
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	slog.Debug("exec", "args", cmd.Args, "external", true)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("starting external terminal: %w", err)
+	}
+	// reap the terminal when it exits, so it doesn't linger as a zombie
+	go func() {
+		_ = cmd.Wait()
+	}()
+	return nil
+}
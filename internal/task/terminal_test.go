@@ -0,0 +1,26 @@
+package task
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExternalTerminalArgs(t *testing.T) {
+	tests := map[string][]string{
+		"alacritty -e":                 {"alacritty", "-e", "task", "build"},
+		"tmux new-window {cmd}":        {"tmux", "new-window", "task", "build"},
+		"wezterm start -- {cmd} --now": {"wezterm", "start", "--", "task", "build", "--now"},
+	}
+	for template, expected := range tests {
+		args, err := ExternalTerminalArgs(template, "build")
+		if err != nil {
+			t.Fatalf("ExternalTerminalArgs(%q) error = %v", template, err)
+		}
+		if !reflect.DeepEqual(args, expected) {
+			t.Errorf("ExternalTerminalArgs(%q): expected %q, got %q", template, expected, args)
+		}
+	}
+	if _, err := ExternalTerminalArgs("  ", "build"); err != ErrNoTerminal {
+		t.Errorf("Expected ErrNoTerminal for an empty template, got %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"sync"
+)
+
+// Version is a semantic version of the installed task binary
+type Version struct {
+	Major, Minor, Patch int
+}
+
+// jsonListVersion is the first task release able to print the task list as JSON
+var jsonListVersion = Version{Major: 3, Minor: 9, Patch: 0}
+
+var versionPattern = regexp.MustCompile(`v?(\d+)\.(\d+)\.(\d+)`)
+
+// ParseVersion extracts the version from the output of "task --version", which has varied
+// between releases (e.g. "Task version: v3.38.0 (h1:...)" or "3.40.1")
+func ParseVersion(s string) (Version, bool) {
+	m := versionPattern.FindStringSubmatch(s)
+	if m == nil {
+		return Version{}, false
+	}
+	var v Version
+	v.Major, _ = strconv.Atoi(m[1])
+	v.Minor, _ = strconv.Atoi(m[2])
+	v.Patch, _ = strconv.Atoi(m[3])
+	return v, true
+}
+
+// Known reports whether the version was detected
+func (v Version) Known() bool {
+	return v != Version{}
+}
+
+// Less reports whether v is an earlier release than o
+func (v Version) Less(o Version) bool {
+	if v.Major != o.Major {
+		return v.Major < o.Major
+	}
+	if v.Minor != o.Minor {
+		return v.Minor < o.Minor
+	}
+	return v.Patch < o.Patch
+}
+
+// SupportsJSON reports whether task can list tasks as JSON. An undetected version (e.g. a
+// development build) is assumed to be recent.
+func (v Version) SupportsJSON() bool {
+	return v.Check(FeatureJSONList, nil) == nil
+}
+
+func (v Version) String() string {
+	if !v.Known() {
+		return "unknown"
+	}
+	return fmt.Sprintf("v%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+var (
+	installedVersionsMu sync.Mutex
+	installedVersions   = map[string]Version{}
+)
+
+// InstalledVersion runs "task --version" in env the first time it is called for the task binary
+// env resolves to, and returns the detected version of that binary
+func InstalledVersion(env Environment) Version {
+	installedVersionsMu.Lock()
+	defer installedVersionsMu.Unlock()
+	bin := env.lookPath("task")
+	if v, ok := installedVersions[bin]; ok {
+		return v
+	}
+	// the version doesn't depend on the Taskfile, which may be remote
+	env.Taskfile = ""
+	var v Version
+	if out, err := env.Command(context.Background(), nil, "task", "--version").Output(); err != nil {
+		slog.Debug("task version", "task", bin, "err", err)
+	} else {
+		v, _ = ParseVersion(string(out))
+		slog.Debug("task version", "task", bin, "version", v)
+	}
+	installedVersions[bin] = v
+	return v
+}
+
+// listArgs returns the arguments listing all tasks, preferring JSON when the task binary of env
+// supports it
+func listArgs(env Environment) []string {
+	if InstalledVersion(env).SupportsJSON() {
+		return []string{"--list-all", "--json"}
+	}
+	return []string{"--list-all"}
+}
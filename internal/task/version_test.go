@@ -0,0 +1,74 @@
+package task
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := map[string]Version{
+		"Task version: v3.38.0 (h1:abc)": {3, 38, 0},
+		"3.40.1\n":                       {3, 40, 1},
+		"Task version: v2.8.1":           {2, 8, 1},
+	}
+	for out, expected := range tests {
+		v, ok := ParseVersion(out)
+		if !ok || v != expected {
+			t.Errorf("ParseVersion(%q): expected %s, got %s", out, expected, v)
+		}
+	}
+	if _, ok := ParseVersion("Task version: (devel)"); ok {
+		t.Error("Expected a development build not to have a version")
+	}
+}
+
+func TestVersionSupportsJSON(t *testing.T) {
+	if (Version{2, 8, 1}).SupportsJSON() {
+		t.Error("Expected v2.8.1 not to support JSON listing")
+	}
+	if !(Version{3, 38, 0}).SupportsJSON() {
+		t.Error("Expected v3.38.0 to support JSON listing")
+	}
+	if !(Version{}).SupportsJSON() {
+		t.Error("Expected an unknown version to be assumed to support JSON listing")
+	}
+}
+
+func TestInstalledVersionUsesTheEnvironment(t *testing.T) {
+	fakeTaskBinary(t, "echo 'Task version: v2.8.1'\n")
+	// the task binary in the path prefix is the one tasks run with
+	tools := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tools, "task"), []byte("#!/bin/sh\necho 'Task version: v3.40.1'\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	env := Environment{PathPrefix: []string{tools}, Taskfile: "https://example.com/Taskfile.yml"}
+	if v := InstalledVersion(env); v != (Version{3, 40, 1}) {
+		t.Errorf("Expected the version of the task binary in the path prefix, got %s", v)
+	}
+	if args := listArgs(env); len(args) != 2 || args[1] != "--json" {
+		t.Errorf("Expected a JSON listing, got %v", args)
+	}
+}
+
+func TestVersionCheck(t *testing.T) {
+	if err := (Version{3, 30, 0}).Check(FeatureWildcards, nil); err == nil || err.Error() != "wildcard task names need task v3.35.0 or later, v3.30.0 is installed" {
+		t.Errorf("Expected an older task to be reported, got %v", err)
+	}
+	if err := (Version{3, 40, 0}).Check(FeatureWildcards, nil); err != nil {
+		t.Errorf("Expected v3.40.0 to support wildcards, got %v", err)
+	}
+	if err := (Version{3, 40, 0}).Check(FeatureRemoteTaskfiles, []string{"TASK_X_REMOTE_TASKFILES=0"}); err == nil {
+		t.Error("Expected remote Taskfiles to need their experiment")
+	}
+	if err := (Version{3, 40, 0}).Check(FeatureRemoteTaskfiles, []string{"TASK_X_REMOTE_TASKFILES=1"}); err != nil {
+		t.Errorf("Expected the enabled experiment to be used, got %v", err)
+	}
+}
+
+func TestExperiments(t *testing.T) {
+	environ := []string{"PATH=/bin", "TASK_X_REMOTE_TASKFILES=1", "TASK_X_GENTLE_FORCE=false", "TASK_X_MAP_VARIABLES=2"}
+	if got := Experiments(environ); len(got) != 2 || got[0] != "MAP_VARIABLES" || got[1] != "REMOTE_TASKFILES" {
+		t.Errorf("Expected the enabled experiments, got %v", got)
+	}
+}
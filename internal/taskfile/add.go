@@ -0,0 +1,104 @@
+package taskfile
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// newTaskfile is the content of the Taskfile created when tasks are added to a project without one
+const newTaskfile = "version: '3'\n\ntasks:\n"
+
+// AddTasks adds task definitions, written as under tasks and indented by two spaces, after the
+// last task of the Taskfile at path, creating the Taskfile when it doesn't exist. The definitions
+// are indented like the tasks already defined. It returns the line of the first added task.
+func AddTasks(path, definitions string) (int, error) {
+	var parsed yaml.Node
+	if err := yaml.Unmarshal([]byte(definitions), &parsed); err != nil {
+		return 0, fmt.Errorf("invalid task definitions: %w", err)
+	}
+
+	perm := os.FileMode(0o644)
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		data = []byte(newTaskfile)
+	case err != nil:
+		return 0, err
+	default:
+		if info, err := os.Stat(path); err == nil {
+			perm = info.Mode().Perm()
+		}
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return 0, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	root := documentRoot(&doc)
+	tasks := mappingValue(root, "tasks")
+	added := documentRoot(&parsed)
+	for i := 0; i+1 < len(added.Content); i += 2 {
+		if name := added.Content[i].Value; mappingValue(tasks, name) != nil {
+			return 0, fmt.Errorf("%w: %s", ErrTaskExists, name)
+		}
+	}
+
+	text := string(data)
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	lines := strings.SplitAfter(text, "\n")
+	lines = lines[:len(lines)-1] // the empty string after the final newline
+	var at int
+	indent, separate := "  ", false
+	switch {
+	case tasks == nil:
+		if len(lines) > 0 {
+			lines = append(lines, "\n")
+		}
+		lines = append(lines, "tasks:\n")
+		at = len(lines)
+	case tasks.Kind == yaml.ScalarNode && tasks.Tag == "!!null":
+		// tasks: without any task yet; add them right after the key
+		at = tasks.Line
+		for j := 0; j+1 < len(root.Content); j += 2 {
+			if root.Content[j+1] == tasks {
+				at = root.Content[j].Line
+			}
+		}
+	case tasks.Kind != yaml.MappingNode || tasks.Style&yaml.FlowStyle != 0:
+		return 0, fmt.Errorf("%s: adding to tasks written in flow style isn't supported", path)
+	default:
+		last := len(tasks.Content) - 2
+		at = taskEnd(lines, root, tasks, last)
+		indent = strings.Repeat(" ", tasks.Content[0].Column-1)
+		// keep the spacing between tasks
+		previous := tasks.Content[last].Line - 2
+		separate = previous >= 0 && strings.TrimSpace(lines[previous]) == ""
+	}
+
+	var inserted []string
+	if separate {
+		inserted = append(inserted, "\n")
+	}
+	for _, line := range strings.SplitAfter(definitions, "\n") {
+		if line == "" {
+			continue
+		}
+		trimmed := strings.TrimLeft(line, " ")
+		depth := (len(line) - len(trimmed)) / 2
+		inserted = append(inserted, strings.Repeat(indent, depth)+trimmed)
+	}
+	edited := append(append(append([]string{}, lines[:at]...), inserted...), lines[at:]...)
+	if err := os.WriteFile(path, []byte(strings.Join(edited, "")), perm); err != nil {
+		return 0, err
+	}
+	line := at + 1
+	if separate {
+		line++
+	}
+	return line, nil
+}
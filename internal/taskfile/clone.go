@@ -0,0 +1,80 @@
+package taskfile
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CloneTask copies the definition of the named task in the Taskfile at path to a new task called
+// newName, added right after it. The definition is copied as written, with its comments and
+// formatting. It returns the line of the new task.
+func CloneTask(path, name, newName string) (int, error) {
+	newName = strings.TrimSpace(newName)
+	if newName == "" {
+		return 0, fmt.Errorf("no name given for the copy of %s", name)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return 0, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	root := documentRoot(&doc)
+	tasks := mappingValue(root, "tasks")
+	if tasks == nil || tasks.Kind != yaml.MappingNode {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownTask, name)
+	}
+	i := taskIndex(tasks, name)
+	if i < 0 {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownTask, name)
+	}
+	if mappingValue(tasks, newName) != nil {
+		return 0, fmt.Errorf("%w: %s", ErrTaskExists, newName)
+	}
+	key := tasks.Content[i]
+	if tasks.Style&yaml.FlowStyle != 0 || tasks.Content[i+1].Style&yaml.FlowStyle != 0 {
+		return 0, fmt.Errorf("task %s: copying tasks written in flow style isn't supported", name)
+	}
+
+	lines := strings.SplitAfter(string(data), "\n")
+	start, end := key.Line-1, taskEnd(lines, root, tasks, i)
+	if !strings.HasSuffix(lines[end-1], "\n") {
+		lines[end-1] += "\n"
+	}
+	copied := append([]string{}, lines[start:end]...)
+	first := copied[0]
+	at := key.Column - 1
+	keyEnd := at + len(key.Value)
+	switch key.Style {
+	case yaml.DoubleQuotedStyle:
+		keyEnd = at + strings.Index(first[at+1:], `"`) + 2
+	case yaml.SingleQuotedStyle:
+		keyEnd = at + strings.Index(first[at+1:], `'`) + 2
+	}
+	encoded, err := yaml.Marshal(newName)
+	if err != nil {
+		return 0, err
+	}
+	copied[0] = first[:at] + strings.TrimSuffix(string(encoded), "\n") + first[keyEnd:]
+
+	// keep the spacing between tasks, separating the copy as the task is separated from the next
+	line := end + 1
+	if end < len(lines) && strings.TrimSpace(lines[end]) == "" && lines[end] != "" {
+		copied = append([]string{"\n"}, copied...)
+		line++
+	}
+	edited := append(append(append([]string{}, lines[:end]...), copied...), lines[end:]...)
+	if err := os.WriteFile(path, []byte(strings.Join(edited, "")), info.Mode().Perm()); err != nil {
+		return 0, err
+	}
+	return line, nil
+}
@@ -0,0 +1,65 @@
+package taskfile
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestCloneTaskCopiesTheDefinition(t *testing.T) {
+	path := writeTaskfile(t, t.TempDir(), "Taskfile.yml", `version: '3'
+
+tasks:
+  test:
+    desc: Run the tests # unit only
+    vars:
+      TAGS: unit
+    cmds:
+      - go test -tags {{.TAGS}} ./...
+
+  "lint": golangci-lint run
+`)
+
+	line, err := CloneTask(path, "test", "test:integration")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if line != 11 {
+		t.Errorf("Expected the copy at line 11, got %d", line)
+	}
+	if line, err = CloneTask(path, "ns:lint", "lint-fix"); err != nil || line != 19 {
+		t.Fatalf("Expected the quoted task to be copied to line 19, got %d and %v", line, err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `version: '3'
+
+tasks:
+  test:
+    desc: Run the tests # unit only
+    vars:
+      TAGS: unit
+    cmds:
+      - go test -tags {{.TAGS}} ./...
+
+  test:integration:
+    desc: Run the tests # unit only
+    vars:
+      TAGS: unit
+    cmds:
+      - go test -tags {{.TAGS}} ./...
+
+  "lint": golangci-lint run
+  lint-fix: golangci-lint run
+`
+	if string(data) != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, data)
+	}
+
+	if _, err := CloneTask(path, "test", "lint"); !errors.Is(err, ErrTaskExists) {
+		t.Errorf("Expected an existing task error, got %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package taskfile
+
+import (
+	"maps"
+	"strings"
+)
+
+// Commands previews the commands of the named task, with their template expressions expanded
+// against the variables the task runs with, overridden by vars given as NAME=value. It is a best
+// effort: unknown variables expand to nothing, expressions using template functions are left as
+// written, and dynamic variables are evaluated as task would. Calls of other tasks are shown as
+// the task command running them, and loops over a list or a matrix once per item.
+func (tf *Taskfile) Commands(name string, vars []string) ([]string, error) {
+	taskName, def, err := tf.Task(name)
+	if err != nil {
+		return nil, err
+	}
+	env, err := tf.Environment(name)
+	if err != nil {
+		return nil, err
+	}
+	data := map[string]any{"TASK": taskName, "ROOT_DIR": tf.Dir(), "TASKFILE_DIR": tf.Dir()}
+	for _, kv := range env.Env {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			data[k] = v
+		}
+	}
+	for _, v := range env.Vars {
+		data[v.Name] = v.Value
+	}
+	for _, kv := range vars {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			data[k] = v
+		}
+	}
+
+	var commands []string
+	for _, call := range def.Cmds {
+		items, as := []any{nil}, ""
+		if looped, name := loopItems(&call.For); len(looped) > 0 {
+			items, as = looped, name
+		}
+		for _, item := range items {
+			itemData := data
+			if as != "" {
+				itemData = maps.Clone(data)
+				itemData[as] = item
+			}
+			if command := call.preview(itemData); command != "" {
+				commands = append(commands, command)
+			}
+		}
+	}
+	return commands, nil
+}
+
+// preview returns the command line of a call with its template expressions expanded with data
+func (c Call) preview(data map[string]any) string {
+	if c.Task == "" {
+		return expand(c.Cmd, data)
+	}
+	line := "task " + c.Task
+	for _, v := range c.Vars {
+		line += " " + v.Name + "=" + expand(v.Value, data)
+	}
+	return line
+}
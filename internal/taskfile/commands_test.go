@@ -0,0 +1,52 @@
+package taskfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCommandsExpandsTemplates(t *testing.T) {
+	path := writeTaskfile(t, t.TempDir(), "Taskfile.yml", `version: '3'
+vars:
+  VERSION: 1.2.3
+tasks:
+  release:
+    vars:
+      TAG: v{{.VERSION}}
+    cmds:
+      - git tag {{.TAG}}
+      - cmd: echo {{.CHANNEL}} {{.MISSING}}
+      - for: [linux, darwin]
+        cmd: build --os {{.ITEM}}
+      - task: publish
+        vars:
+          TAG: '{{.TAG}}'
+      - echo {{.VERSION | upper}}
+  short: echo {{.TASK}}
+`)
+	tf, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commands, err := tf.Commands("release", []string{"CHANNEL=stable"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"git tag v1.2.3",
+		"echo stable ",
+		"build --os linux",
+		"build --os darwin",
+		"task publish TAG=v1.2.3",
+		// template functions aren't known, so the expression is left as written
+		"echo {{.VERSION | upper}}",
+	}
+	if !reflect.DeepEqual(commands, expected) {
+		t.Errorf("Expected %q, got %q", expected, commands)
+	}
+
+	if commands, _ := tf.Commands("short", nil); !reflect.DeepEqual(commands, []string{"echo short"}) {
+		t.Errorf("Expected the short form to be previewed, got %q", commands)
+	}
+}
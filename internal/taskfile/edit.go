@@ -0,0 +1,241 @@
+package taskfile
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Metadata is the descriptive part of a task definition
+type Metadata struct {
+	Desc    string
+	Summary string
+	Aliases []string
+}
+
+// metadataKeys are the keys of Metadata, in the order they are added to a task
+var metadataKeys = []string{"desc", "summary", "aliases"}
+
+// Metadata returns the metadata of the named task
+func (tf *Taskfile) Metadata(name string) (Metadata, error) {
+	_, def, err := tf.Task(name)
+	if err != nil {
+		return Metadata{}, err
+	}
+	return Metadata{Desc: def.Desc, Summary: def.Summary, Aliases: def.Aliases}, nil
+}
+
+// SetMetadata writes the metadata of the named task into the Taskfile at path. Only the task's
+// definition is rewritten, from its tree of YAML nodes, so its comments and the order of its keys
+// are kept and the rest of the file is left untouched. Empty fields remove their key, and a task
+// written in a short form is turned into a mapping with its commands under cmds.
+func SetMetadata(path, name string, meta Metadata) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+	root := documentRoot(&doc)
+	tasks := mappingValue(root, "tasks")
+	if tasks == nil || tasks.Kind != yaml.MappingNode {
+		return fmt.Errorf("%w: %s", ErrUnknownTask, name)
+	}
+	i := taskIndex(tasks, name)
+	if i < 0 {
+		return fmt.Errorf("%w: %s", ErrUnknownTask, name)
+	}
+	key, def := tasks.Content[i], tasks.Content[i+1]
+	if tasks.Style&yaml.FlowStyle != 0 || def.Style&yaml.FlowStyle != 0 {
+		return fmt.Errorf("task %s: editing tasks written in flow style isn't supported", name)
+	}
+	switch def.Kind {
+	case yaml.MappingNode:
+	case yaml.ScalarNode, yaml.SequenceNode:
+		if def.Tag == "!!null" {
+			*def = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			break
+		}
+		cmds := &yaml.Node{}
+		*cmds = *def
+		if cmds.Kind == yaml.ScalarNode {
+			cmds = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{cmds}}
+		}
+		*def = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: "cmds"}, cmds,
+		}}
+	default:
+		return fmt.Errorf("task %s: unsupported definition at line %d", name, def.Line)
+	}
+
+	setScalar(def, "desc", meta.Desc)
+	setScalar(def, "summary", meta.Summary)
+	var aliases *yaml.Node
+	if len(meta.Aliases) > 0 {
+		aliases = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle}
+		if existing := mappingValue(def, "aliases"); existing != nil && existing.Kind == yaml.SequenceNode {
+			aliases.Style = existing.Style
+		}
+		for _, alias := range meta.Aliases {
+			aliases.Content = append(aliases.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: alias})
+		}
+	}
+	setKey(def, "aliases", aliases)
+
+	// the comments above the task stay in the file, so they aren't written with it
+	key.HeadComment = ""
+	indent := 2
+	if def.Kind == yaml.MappingNode && len(def.Content) > 0 && def.Content[0].Column > key.Column {
+		indent = def.Content[0].Column - key.Column
+	}
+	var b bytes.Buffer
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(indent)
+	if err := enc.Encode(&yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{key, def}}); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+
+	lines := strings.SplitAfter(string(data), "\n")
+	start, end := key.Line-1, taskEnd(lines, root, tasks, i)
+	var edited strings.Builder
+	for _, line := range lines[:start] {
+		edited.WriteString(line)
+	}
+	margin := strings.Repeat(" ", key.Column-1)
+	for _, line := range strings.SplitAfter(strings.TrimSuffix(b.String(), "\n"), "\n") {
+		if strings.TrimSpace(line) != "" {
+			edited.WriteString(margin)
+		}
+		edited.WriteString(line)
+	}
+	edited.WriteString("\n")
+	for _, line := range lines[end:] {
+		edited.WriteString(line)
+	}
+	return os.WriteFile(path, []byte(edited.String()), info.Mode().Perm())
+}
+
+// taskEnd returns the index of the first line after the task defined at tasks.Content[i]: the line
+// of the key following it in the file, before the blank lines and comments leading up to it
+func taskEnd(lines []string, root, tasks *yaml.Node, i int) int {
+	end := len(lines)
+	if i+2 < len(tasks.Content) {
+		end = tasks.Content[i+2].Line - 1
+	} else {
+		for j := 0; j+1 < len(root.Content); j += 2 {
+			if root.Content[j+1] == tasks {
+				if j+2 < len(root.Content) {
+					end = root.Content[j+2].Line - 1
+				}
+				break
+			}
+		}
+	}
+	column := tasks.Content[i].Column
+	for end > tasks.Content[i].Line {
+		line := lines[end-1]
+		trimmed := strings.TrimSpace(line)
+		indented := len(line) - len(strings.TrimLeft(line, " \t"))
+		if trimmed != "" && !(strings.HasPrefix(trimmed, "#") && indented < column) {
+			break
+		}
+		end--
+	}
+	return end
+}
+
+// documentRoot returns the top-level node of a parsed document
+func documentRoot(doc *yaml.Node) *yaml.Node {
+	if doc.Kind == yaml.DocumentNode && len(doc.Content) > 0 {
+		return doc.Content[0]
+	}
+	return doc
+}
+
+// mappingValue returns the value of key in a mapping node, or nil when it isn't set
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// taskIndex returns the index of the key of the named task in the tasks mapping, dropping
+// namespaces as Task does, or -1
+func taskIndex(tasks *yaml.Node, name string) int {
+	for n := name; ; {
+		for i := 0; i+1 < len(tasks.Content); i += 2 {
+			if tasks.Content[i].Value == n {
+				return i
+			}
+		}
+		_, rest, ok := cutNamespace(n)
+		if !ok {
+			return -1
+		}
+		n = rest
+	}
+}
+
+// setScalar sets key to a string, written as a literal block when it spans lines, or removes the
+// key when value is empty
+func setScalar(node *yaml.Node, key, value string) {
+	if value == "" {
+		setKey(node, key, nil)
+		return
+	}
+	scalar := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+	if strings.Contains(value, "\n") {
+		scalar.Style = yaml.LiteralStyle
+	} else if existing := mappingValue(node, key); existing != nil && existing.Kind == yaml.ScalarNode && existing.Style != yaml.LiteralStyle && existing.Style != yaml.FoldedStyle {
+		scalar.Style = existing.Style
+	}
+	setKey(node, key, scalar)
+}
+
+// setKey replaces the value of a metadata key in a mapping node, keeping the comments of the old
+// value, or removes the key when value is nil. A new key is added after the metadata keys that
+// precede it, or first.
+func setKey(node *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != key {
+			continue
+		}
+		if value == nil {
+			node.Content = slices.Delete(node.Content, i, i+2)
+			return
+		}
+		old := node.Content[i+1]
+		value.LineComment, value.HeadComment, value.FootComment = old.LineComment, old.HeadComment, old.FootComment
+		node.Content[i+1] = value
+		return
+	}
+	if value == nil {
+		return
+	}
+	at := 0
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if j := slices.Index(metadataKeys, node.Content[i].Value); j >= 0 && j < slices.Index(metadataKeys, key) {
+			at = i + 2
+		}
+	}
+	node.Content = slices.Insert(node.Content, at, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
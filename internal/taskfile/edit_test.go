@@ -0,0 +1,72 @@
+package taskfile
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSetMetadataEditsInPlace(t *testing.T) {
+	dir := t.TempDir()
+	path := writeTaskfile(t, dir, "Taskfile.yml", `# Build tasks
+version: '3'
+
+tasks:
+    build:
+        # compiles everything
+        cmds:
+            - go build ./...
+        desc: old description # keep me
+    lint: golangci-lint run
+    test:
+`)
+
+	if err := SetMetadata(path, "build", Metadata{Desc: "Build the binaries", Summary: "Builds every package.\nRun before releasing.", Aliases: []string{"b"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetMetadata(path, "lint", Metadata{Desc: "Lint the code"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetMetadata(path, "ns:test", Metadata{Aliases: []string{"t", "check"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	for _, want := range []string{"# Build tasks\nversion: '3'\n\ntasks:\n", "# compiles everything", "desc: Build the binaries # keep me", "    build:\n        # compiles everything"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected %q to be kept, got:\n%s", want, content)
+		}
+	}
+
+	tf, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	build, _ := tf.Metadata("build")
+	if build.Summary != "Builds every package.\nRun before releasing." || !slices.Equal(build.Aliases, []string{"b"}) {
+		t.Errorf("Expected the summary and aliases to be written, got %+v", build)
+	}
+	_, lint, _ := tf.Task("lint")
+	if lint.Desc != "Lint the code" || len(lint.Cmds) != 1 || lint.Cmds[0].Cmd != "golangci-lint run" {
+		t.Errorf("Expected the short form to keep its command, got %+v", lint)
+	}
+	if test, _ := tf.Metadata("test"); !slices.Equal(test.Aliases, []string{"t", "check"}) {
+		t.Errorf("Expected aliases on the empty task, got %+v", test)
+	}
+
+	if err := SetMetadata(path, "build", Metadata{}); err != nil {
+		t.Fatal(err)
+	}
+	tf, _ = Load(path)
+	if build, _ := tf.Metadata("build"); build.Desc != "" || build.Summary != "" || build.Aliases != nil {
+		t.Errorf("Expected empty fields to remove their keys, got %+v", build)
+	}
+	if err := SetMetadata(path, "missing", Metadata{}); !strings.Contains(err.Error(), ErrUnknownTask.Error()) {
+		t.Errorf("Expected an unknown task error, got %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package taskfile
+
+import (
+	"slices"
+	"strings"
+)
+
+// Source tells where the value of a variable seen by a task comes from
+type Source string
+
+// Sources of variables
+const (
+	SourceEnvironment Source = "environment"
+	SourceSpecial     Source = "special"
+	SourceGlobalVars  Source = "global vars"
+	SourceGlobalEnv   Source = "global env"
+	SourceDotenv      Source = "dotenv"
+	SourceTaskVars    Source = "task vars"
+	SourceTaskEnv     Source = "task env"
+	SourceOverride    Source = "CLI override"
+)
+
+// Binding is a variable seen by a task, with the value it resolves to and where it comes from.
+// Env bindings are exported to the task's process; the others are only template variables.
+type Binding struct {
+	Name   string
+	Value  string
+	Source Source
+	Env    bool
+}
+
+// Bindings lists the variables the named task sees, as task --summary and the environment
+// resolution would give them: the special variables, the template variables in the order they
+// are defined with later definitions replacing earlier ones, overridden by vars given as
+// NAME=value, then the environment entries set by the Taskfile and finally the inherited process
+// environment, sorted by name.
+func (tf *Taskfile) Bindings(name string, overrides []string) ([]Binding, error) {
+	env, err := tf.Environment(name)
+	if err != nil {
+		return nil, err
+	}
+	bindings := []Binding{
+		{Name: "TASK", Value: env.Task, Source: SourceSpecial},
+		{Name: "ROOT_DIR", Value: tf.Dir(), Source: SourceSpecial},
+		{Name: "TASKFILE_DIR", Value: tf.Dir(), Source: SourceSpecial},
+	}
+	set := func(b Binding) {
+		i := slices.IndexFunc(bindings, func(existing Binding) bool { return existing.Name == b.Name && !existing.Env })
+		if i < 0 {
+			bindings = append(bindings, b)
+			return
+		}
+		bindings[i] = b
+	}
+	for _, v := range env.Vars {
+		set(Binding{Name: v.Name, Value: v.Value, Source: v.Source})
+	}
+	for _, kv := range overrides {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			set(Binding{Name: k, Value: v, Source: SourceOverride})
+		}
+	}
+
+	var inherited []Binding
+	for _, kv := range env.Env {
+		k, v, _ := strings.Cut(kv, "=")
+		b := Binding{Name: k, Value: v, Source: env.EnvSources[k], Env: true}
+		if b.Source == SourceEnvironment {
+			inherited = append(inherited, b)
+		} else {
+			bindings = append(bindings, b)
+		}
+	}
+	slices.SortFunc(inherited, func(a, b Binding) int { return strings.Compare(a.Name, b.Name) })
+	return append(bindings, inherited...), nil
+}
@@ -0,0 +1,57 @@
+package taskfile
+
+import (
+	"testing"
+)
+
+func TestBindingsAttributeSources(t *testing.T) {
+	dir := t.TempDir()
+	writeTaskfile(t, dir, ".env", "FROM_DOTENV=dotenv\n")
+	path := writeTaskfile(t, dir, "Taskfile.yml", `version: '3'
+dotenv: ['.env']
+vars:
+  VERSION: 1.0.0
+  CHANNEL: beta
+env:
+  GLOBAL: global
+tasks:
+  release:
+    vars:
+      VERSION: 2.0.0
+    env:
+      LOCAL: task
+`)
+	t.Setenv("TASHTEST_INHERITED", "inherited")
+	tf, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bindings, err := tf.Bindings("release", []string{"CHANNEL=stable"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := map[string]Binding{}
+	for _, b := range bindings {
+		if _, ok := found[b.Name]; ok && !b.Env {
+			t.Errorf("Expected %s to be listed once", b.Name)
+		}
+		found[b.Name] = b
+	}
+	for _, want := range []Binding{
+		{Name: "TASK", Value: "release", Source: SourceSpecial},
+		{Name: "VERSION", Value: "2.0.0", Source: SourceTaskVars},
+		{Name: "CHANNEL", Value: "stable", Source: SourceOverride},
+		{Name: "FROM_DOTENV", Value: "dotenv", Source: SourceDotenv, Env: true},
+		{Name: "GLOBAL", Value: "global", Source: SourceGlobalEnv, Env: true},
+		{Name: "LOCAL", Value: "task", Source: SourceTaskEnv, Env: true},
+		{Name: "TASHTEST_INHERITED", Value: "inherited", Source: SourceEnvironment, Env: true},
+	} {
+		if found[want.Name] != want {
+			t.Errorf("Expected %+v, got %+v", want, found[want.Name])
+		}
+	}
+	if last := bindings[len(bindings)-1]; last.Source != SourceEnvironment {
+		t.Errorf("Expected the inherited environment to be listed last, got %+v", last)
+	}
+}
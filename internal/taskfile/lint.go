@@ -0,0 +1,223 @@
+package taskfile
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Severities of diagnostics
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Diagnostic is a problem found in a Taskfile
+type Diagnostic struct {
+	File     string
+	Line     int
+	Column   int
+	Severity string
+	Message  string
+}
+
+// Location returns the position of the diagnostic in the form file:line:column
+func (d Diagnostic) Location() string {
+	loc := d.File
+	if d.Line > 0 {
+		loc += ":" + strconv.Itoa(d.Line)
+		if d.Column > 0 {
+			loc += ":" + strconv.Itoa(d.Column)
+		}
+	}
+	return loc
+}
+
+// yamlLinePattern finds the line in the errors of the YAML parser
+var yamlLinePattern = regexp.MustCompile(`line (\d+)`)
+
+// LintOptions tells Lint which features the installed task lacks. Each is nil when the feature
+// can be used, or the reason it can't, which is reported as a warning where the feature is used.
+type LintOptions struct {
+	Wildcards       error
+	RemoteTaskfiles error
+}
+
+// Lint checks the Taskfile at path and the Taskfiles it includes for the mistakes that keep
+// task from reading them or from running their tasks: invalid YAML, a missing or unsupported
+// schema version, includes of missing files, calls of tasks that aren't defined and features
+// the installed task lacks. Templated names and paths are skipped, as they are only known when
+// task runs.
+func Lint(path string, opts LintOptions) []Diagnostic {
+	var diagnostics []Diagnostic
+	lintFile(path, opts, map[string]bool{}, &diagnostics)
+	return diagnostics
+}
+
+// lintFile checks one Taskfile, then the Taskfiles it includes that haven't been checked yet
+func lintFile(path string, opts LintOptions, seen map[string]bool, diagnostics *[]Diagnostic) {
+	seen[path] = true
+	report := func(node *yaml.Node, severity, format string, args ...any) {
+		d := Diagnostic{File: path, Severity: severity, Message: fmt.Sprintf(format, args...)}
+		if node != nil {
+			d.Line, d.Column = node.Line, node.Column
+		}
+		*diagnostics = append(*diagnostics, d)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		report(nil, SeverityError, "%v", err)
+		return
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		d := Diagnostic{File: path, Severity: SeverityError, Message: strings.TrimPrefix(err.Error(), "yaml: ")}
+		if match := yamlLinePattern.FindStringSubmatch(err.Error()); match != nil {
+			d.Line, _ = strconv.Atoi(match[1])
+		}
+		*diagnostics = append(*diagnostics, d)
+		return
+	}
+	root := documentRoot(&doc)
+	if root.Kind != yaml.MappingNode {
+		report(root, SeverityError, "a Taskfile must be a mapping of version, tasks and other settings")
+		return
+	}
+	var tf Taskfile
+	if err := root.Decode(&tf); err != nil {
+		var typeErr *yaml.TypeError
+		if !errors.As(err, &typeErr) {
+			report(nil, SeverityError, "%v", err)
+			return
+		}
+		for _, e := range typeErr.Errors {
+			d := Diagnostic{File: path, Severity: SeverityError, Message: e}
+			if match := yamlLinePattern.FindStringSubmatch(e); match != nil {
+				d.Line, _ = strconv.Atoi(match[1])
+				d.Message = strings.TrimPrefix(e, match[0]+": ")
+			}
+			*diagnostics = append(*diagnostics, d)
+		}
+	}
+
+	switch version := mappingValue(root, "version"); {
+	case version == nil:
+		report(root, SeverityError, "missing schema version, add version: '3'")
+	case version.Value != "3" && !strings.HasPrefix(version.Value, "3."):
+		report(version, SeverityError, "unsupported schema version %s, task supports version 3", version.Value)
+	}
+
+	includes := mappingValue(root, "includes")
+	namespaces := map[string]bool{}
+	var included []string
+	if includes != nil && includes.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(includes.Content); i += 2 {
+			namespace, include := includes.Content[i], includes.Content[i+1]
+			namespaces[namespace.Value] = true
+			file, optional := include.Value, false
+			if include.Kind == yaml.MappingNode {
+				var entry struct {
+					Taskfile string `yaml:"taskfile"`
+					Optional bool   `yaml:"optional"`
+				}
+				if err := include.Decode(&entry); err != nil {
+					report(include, SeverityError, "include %s: %v", namespace.Value, err)
+					continue
+				}
+				file, optional = entry.Taskfile, entry.Optional
+			}
+			if strings.Contains(file, "://") && opts.RemoteTaskfiles != nil {
+				report(include, SeverityWarning, "include %s: %v", namespace.Value, opts.RemoteTaskfiles)
+			}
+			if file == "" || strings.Contains(file, "{{") || strings.Contains(file, "://") {
+				continue
+			}
+			resolved, err := includedTaskfile(filepath.Dir(path), file)
+			if err != nil {
+				if !optional {
+					report(include, SeverityError, "include %s: %s not found", namespace.Value, file)
+				}
+				continue
+			}
+			included = append(included, resolved)
+		}
+	}
+
+	tasks := mappingValue(root, "tasks")
+	if tasks != nil && tasks.Kind == yaml.MappingNode {
+		var defined []string
+		for name, def := range tf.Tasks {
+			defined = append(defined, name)
+			if def != nil {
+				defined = append(defined, def.Aliases...)
+			}
+		}
+		for i := 0; i+1 < len(tasks.Content); i += 2 {
+			if name := tasks.Content[i]; strings.Contains(name.Value, "*") && opts.Wildcards != nil {
+				report(name, SeverityWarning, "task %s: %v", name.Value, opts.Wildcards)
+			}
+			def := tasks.Content[i+1]
+			for _, list := range []string{"deps", "cmds"} {
+				calls := mappingValue(def, list)
+				if calls == nil || calls.Kind != yaml.SequenceNode {
+					continue
+				}
+				for _, call := range calls.Content {
+					name := mappingValue(call, "task")
+					if name == nil && list == "deps" && call.Kind == yaml.ScalarNode {
+						name = call
+					}
+					if name == nil || !calledTaskMissing(name.Value, defined, namespaces) {
+						continue
+					}
+					report(name, SeverityError, "task %s calls %s, which isn't defined", tasks.Content[i].Value, name.Value)
+				}
+			}
+		}
+	}
+
+	for _, file := range included {
+		if !seen[file] {
+			lintFile(file, opts, seen, diagnostics)
+		}
+	}
+}
+
+// includedTaskfile resolves the path of an included Taskfile, which may name its directory
+func includedTaskfile(dir, file string) (string, error) {
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(dir, file)
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		return "", err
+	}
+	if info.IsDir() {
+		return Find(file)
+	}
+	return file, nil
+}
+
+// calledTaskMissing reports whether a call of name can't be resolved: it matches neither a task
+// or alias of the Taskfile, including wildcard task names, nor a namespace it includes. Calls of
+// the root Taskfile, starting with ":", and templated names can't be checked here.
+func calledTaskMissing(name string, defined []string, namespaces map[string]bool) bool {
+	if name == "" || strings.HasPrefix(name, ":") || strings.Contains(name, "{{") {
+		return false
+	}
+	for _, d := range defined {
+		if matched, _ := path.Match(d, name); matched {
+			return false
+		}
+	}
+	namespace, _, ok := cutNamespace(name)
+	return !ok || !namespaces[namespace]
+}
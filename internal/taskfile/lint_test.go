@@ -0,0 +1,89 @@
+package taskfile
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLintReportsMistakesWithTheirLocation(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "docs"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeTaskfile(t, dir, "docs/Taskfile.yml", `tasks:
+  build:
+    cmds: [mkdocs build]
+`)
+	path := writeTaskfile(t, dir, "Taskfile.yml", `version: '3'
+includes:
+  docs: ./docs
+  missing: ./missing.yml
+  extra:
+    taskfile: ./extra.yml
+    optional: true
+tasks:
+  build:
+    aliases: [b]
+    deps: [generate, b, docs:build]
+    cmds:
+      - task: relase
+      - task: 'start:{{.NAME}}'
+      - task: start:web
+  start:*: echo start
+`)
+
+	diagnostics := Lint(path, LintOptions{})
+	expected := []Diagnostic{
+		{File: path, Line: 4, Column: 12, Severity: SeverityError, Message: "include missing: ./missing.yml not found"},
+		{File: path, Line: 11, Column: 12, Severity: SeverityError, Message: "task build calls generate, which isn't defined"},
+		{File: path, Line: 13, Column: 15, Severity: SeverityError, Message: "task build calls relase, which isn't defined"},
+		{File: filepath.Join(dir, "docs", "Taskfile.yml"), Line: 1, Column: 1, Severity: SeverityError, Message: "missing schema version, add version: '3'"},
+	}
+	if len(diagnostics) != len(expected) {
+		t.Fatalf("Expected %d diagnostics, got %+v", len(expected), diagnostics)
+	}
+	for i, want := range expected {
+		if diagnostics[i] != want {
+			t.Errorf("Expected %+v, got %+v", want, diagnostics[i])
+		}
+	}
+}
+
+func TestLintReportsInvalidYaml(t *testing.T) {
+	path := writeTaskfile(t, t.TempDir(), "Taskfile.yml", "version: '3'\ntasks:\n  build:\n    cmds: [go build\n")
+	diagnostics := Lint(path, LintOptions{})
+	if len(diagnostics) != 1 || diagnostics[0].Line == 0 || diagnostics[0].Severity != SeverityError {
+		t.Fatalf("Expected a located syntax error, got %+v", diagnostics)
+	}
+	if loc := diagnostics[0].Location(); loc != fmt.Sprintf("%s:%d", path, diagnostics[0].Line) {
+		t.Errorf("Expected the location to name the file and line, got %s", loc)
+	}
+}
+
+func TestLintWarnsOfUnsupportedFeatures(t *testing.T) {
+	path := writeTaskfile(t, t.TempDir(), "Taskfile.yml", `version: '3'
+includes:
+  shared: https://example.com/Taskfile.yml
+tasks:
+  start:*: echo start
+`)
+	diagnostics := Lint(path, LintOptions{
+		Wildcards:       errors.New("wildcard task names need task v3.35.0 or later"),
+		RemoteTaskfiles: errors.New("remote Taskfiles are an experiment"),
+	})
+	expected := []Diagnostic{
+		{File: path, Line: 3, Column: 11, Severity: SeverityWarning, Message: "include shared: remote Taskfiles are an experiment"},
+		{File: path, Line: 5, Column: 3, Severity: SeverityWarning, Message: "task start:*: wildcard task names need task v3.35.0 or later"},
+	}
+	if len(diagnostics) != len(expected) {
+		t.Fatalf("Expected %d diagnostics, got %+v", len(expected), diagnostics)
+	}
+	for i, want := range expected {
+		if diagnostics[i] != want {
+			t.Errorf("Expected %+v, got %+v", want, diagnostics[i])
+		}
+	}
+}
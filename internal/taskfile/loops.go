@@ -0,0 +1,143 @@
+package taskfile
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Call is a command or dependency of a task: a command line, or a call of another task
+type Call struct {
+	Cmd  string    `yaml:"cmd"`
+	Task string    `yaml:"task"`
+	Vars Vars      `yaml:"vars"`
+	For  yaml.Node `yaml:"for"`
+}
+
+// UnmarshalYAML accepts the short form of a call, a single string, which is read as a command
+// line; in deps it names a task instead, which nothing reads yet. Calls tash can't read are
+// ignored rather than failing the whole Taskfile.
+func (c *Call) UnmarshalYAML(node *yaml.Node) error {
+	type plain Call
+	switch {
+	case node.Kind == yaml.ScalarNode:
+		*c = Call{Cmd: node.Value}
+	case node.Kind != yaml.MappingNode || node.Decode((*plain)(c)) != nil:
+		*c = Call{}
+	}
+	return nil
+}
+
+// Variant is one run of a task generated by a for loop, such as a single cell of a matrix
+type Variant struct {
+	// Task is the task the loop calls, with the namespace of the task defining the loop
+	Task string
+	// Vars are the variables the loop passes for the item, as NAME=value
+	Vars []string
+}
+
+// Variants returns the runs generated by the for loops of the named task that call other tasks
+// with variables. Loops over a list and matrix loops are expanded; loops over variables or
+// sources, which take evaluating the Taskfile, and loops running commands, which task can't run
+// one item at a time, are not.
+func (tf *Taskfile) Variants(name string) ([]Variant, error) {
+	taskName, def, err := tf.Task(name)
+	if err != nil {
+		return nil, err
+	}
+	namespace := strings.TrimSuffix(name, taskName)
+	var variants []Variant
+	for _, call := range slices.Concat(def.Deps, def.Cmds) {
+		if call.Task == "" || len(call.Vars) == 0 || call.For.Kind == 0 {
+			continue
+		}
+		items, as := loopItems(&call.For)
+		callee := namespace + call.Task
+		if root, ok := strings.CutPrefix(call.Task, ":"); ok {
+			callee = root
+		}
+		for _, item := range items {
+			vars, ok := renderVars(call.Vars, map[string]any{as: item})
+			if !ok {
+				// the variables use more than the loop item, so the loop can't be expanded
+				break
+			}
+			variants = append(variants, Variant{Task: callee, Vars: vars})
+		}
+	}
+	return variants, nil
+}
+
+// loopItems returns the items of a loop over a list or a matrix, and the name of the variable
+// holding the item
+func loopItems(node *yaml.Node) ([]any, string) {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		var list []string
+		if err := node.Decode(&list); err != nil {
+			return nil, ""
+		}
+		items := make([]any, len(list))
+		for i, v := range list {
+			items[i] = v
+		}
+		return items, "ITEM"
+	case yaml.MappingNode:
+		var loop struct {
+			Matrix yaml.Node `yaml:"matrix"`
+			As     string    `yaml:"as"`
+		}
+		if err := node.Decode(&loop); err != nil || loop.Matrix.Kind != yaml.MappingNode {
+			return nil, ""
+		}
+		items := []any{map[string]string{}}
+		for i := 0; i+1 < len(loop.Matrix.Content); i += 2 {
+			key := loop.Matrix.Content[i].Value
+			var values []string
+			if err := loop.Matrix.Content[i+1].Decode(&values); err != nil {
+				return nil, ""
+			}
+			var product []any
+			for _, item := range items {
+				for _, v := range values {
+					cell := map[string]string{key: v}
+					for k, prev := range item.(map[string]string) {
+						cell[k] = prev
+					}
+					product = append(product, cell)
+				}
+			}
+			items = product
+		}
+		as := loop.As
+		if as == "" {
+			as = "ITEM"
+		}
+		return items, as
+	}
+	return nil, ""
+}
+
+// renderVars expands the template expressions of vars with data, reporting false when they
+// refer to anything else
+func renderVars(vars Vars, data map[string]any) ([]string, bool) {
+	rendered := make([]string, 0, len(vars))
+	for _, v := range vars {
+		if v.Sh != "" {
+			return nil, false
+		}
+		tmpl, err := template.New(v.Name).Option("missingkey=error").Parse(v.Value)
+		if err != nil {
+			return nil, false
+		}
+		var b strings.Builder
+		if err := tmpl.Execute(&b, data); err != nil {
+			return nil, false
+		}
+		rendered = append(rendered, fmt.Sprintf("%s=%s", v.Name, b.String()))
+	}
+	return rendered, true
+}
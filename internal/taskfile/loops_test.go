@@ -0,0 +1,56 @@
+package taskfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVariantsExpandsLoopsCallingTasks(t *testing.T) {
+	path := writeTaskfile(t, t.TempDir(), "Taskfile.yml", `version: '3'
+tasks:
+  release:
+    deps:
+      - for: [linux, darwin]
+        task: package
+        vars:
+          OS: '{{.ITEM}}'
+    cmds:
+      - echo releasing
+      - for:
+          matrix:
+            OS: [linux, windows]
+            ARCH: [amd64, arm64]
+        task: :build
+        vars:
+          TARGET: '{{.ITEM.OS}}/{{.ITEM.ARCH}}'
+      - for: [a, b]
+        cmd: echo {{.ITEM}}
+      - for: [x]
+        task: package
+        vars:
+          OS: '{{.ITEM}}-{{.VERSION}}'
+      - task: package
+        vars:
+          OS: [not, a, string]
+`)
+	tf, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	variants, err := tf.Variants("ci:release")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Variant{
+		{Task: "ci:package", Vars: []string{"OS=linux"}},
+		{Task: "ci:package", Vars: []string{"OS=darwin"}},
+		{Task: "build", Vars: []string{"TARGET=linux/amd64"}},
+		{Task: "build", Vars: []string{"TARGET=linux/arm64"}},
+		{Task: "build", Vars: []string{"TARGET=windows/amd64"}},
+		{Task: "build", Vars: []string{"TARGET=windows/arm64"}},
+	}
+	if !reflect.DeepEqual(variants, expected) {
+		t.Errorf("Expected %v, got %v", expected, variants)
+	}
+}
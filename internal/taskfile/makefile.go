@@ -0,0 +1,413 @@
+package taskfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MakefileNames are the file names make looks for, in order of precedence
+var MakefileNames = []string{"GNUmakefile", "makefile", "Makefile"}
+
+// FindMakefile returns the path of the Makefile in dir
+func FindMakefile(dir string) (string, error) {
+	for _, name := range MakefileNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no Makefile found in %s", dir)
+}
+
+// MakeImport is the best-effort translation of a Makefile to tasks
+type MakeImport struct {
+	Path  string
+	Tasks []MakeTask
+	// Skipped describes what couldn't be translated, such as pattern rules and conditionals
+	Skipped []string
+}
+
+// MakeTask is a task translated from a target of a Makefile
+type MakeTask struct {
+	Name string
+	Desc string
+	// Vars are the variables of the Makefile the commands use, in the order they are defined
+	Vars      []MakeVar
+	Deps      []string
+	Sources   []string
+	Generates []string
+	Cmds      []MakeCmd
+	Line      int // Line of the target in the Makefile
+}
+
+// MakeVar is a variable of a Makefile; Sh is set for variables set to the output of $(shell)
+type MakeVar struct {
+	Name  string
+	Value string
+	Sh    bool
+}
+
+// MakeCmd is a line of a recipe
+type MakeCmd struct {
+	Cmd         string
+	Silent      bool // The line started with @
+	IgnoreError bool // The line started with -
+}
+
+var (
+	// makeVarPattern matches variable assignments, e.g. "GOFLAGS ?= -v"
+	makeVarPattern = regexp.MustCompile(`^(?:export\s+|override\s+)?([A-Za-z_][A-Za-z0-9_.]*)\s*(:{1,3}=|\?=|\+=|!=|=)\s*(.*)$`)
+	// makeRefPattern matches references to variables, e.g. "$(GOFLAGS)" or "${GOFLAGS}"
+	makeRefPattern = regexp.MustCompile(`\$[({]([A-Za-z_][A-Za-z0-9_.]*)[)}]`)
+	// makeShellPattern matches a variable set to the output of a command
+	makeShellPattern = regexp.MustCompile(`^\$\(shell\s+(.*)\)$`)
+	// taskVarRefPattern matches the references to vars translated from variables, e.g. "{{.GOFLAGS}}"
+	taskVarRefPattern = regexp.MustCompile(`\{\{\.([A-Za-z_][A-Za-z0-9_.]*)\}\}`)
+)
+
+// makeDirectives start lines of a Makefile that have no equivalent in a Taskfile
+var makeDirectives = []string{"include", "-include", "sinclude", "ifeq", "ifneq", "ifdef", "ifndef", "else", "endif", "define", "endef", "vpath", "unexport"}
+
+// ImportMakefile reads the Makefile at path and translates its targets to tasks. Variables are
+// translated to the vars of the tasks using them, prerequisites that are targets to deps and
+// the others to sources, and files built by targets that aren't .PHONY to generates.
+func ImportMakefile(path string) (*MakeImport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	imp := &MakeImport{Path: path}
+	vars := map[string]MakeVar{}
+	var order []string
+	phony := map[string]bool{}
+	var current []int // Tasks the lines of the recipe being read are added to
+	var comment string
+	inDefine := false
+
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	for n := 0; n < len(lines); n++ {
+		lineNo := n + 1
+		line := lines[n]
+		for strings.HasSuffix(line, `\`) && n+1 < len(lines) {
+			n++
+			line = strings.TrimRight(strings.TrimSuffix(line, `\`), " \t") + " " + strings.TrimSpace(lines[n])
+		}
+
+		if strings.HasPrefix(line, "\t") {
+			if cmd := strings.TrimSpace(line); cmd != "" && !strings.HasPrefix(cmd, "#") {
+				for _, i := range current {
+					imp.Tasks[i].Cmds = append(imp.Tasks[i].Cmds, makeCmd(cmd))
+				}
+			}
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if inDefine {
+			inDefine = trimmed != "endef"
+			continue
+		}
+		switch {
+		case trimmed == "":
+			comment = ""
+			continue
+		case strings.HasPrefix(trimmed, "#"):
+			comment = strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+			continue
+		}
+		if word, _, _ := strings.Cut(trimmed, " "); slices.Contains(makeDirectives, word) {
+			imp.Skipped = append(imp.Skipped, fmt.Sprintf("line %d: %s", lineNo, trimmed))
+			inDefine = word == "define"
+			current, comment = nil, ""
+			continue
+		}
+		if m := makeVarPattern.FindStringSubmatch(trimmed); m != nil {
+			name, op, value := m[1], m[2], stripMakeComment(m[3])
+			v := MakeVar{Name: name, Value: value}
+			switch op {
+			case "+=":
+				if prev, ok := vars[name]; ok {
+					v = MakeVar{Name: name, Value: strings.TrimSpace(prev.Value + " " + value), Sh: prev.Sh}
+				}
+			case "?=":
+				if _, ok := vars[name]; ok {
+					continue
+				}
+			case "!=":
+				v.Sh = true
+			}
+			if sh := makeShellPattern.FindStringSubmatch(value); sh != nil {
+				v = MakeVar{Name: name, Value: sh[1], Sh: true}
+			}
+			if _, ok := vars[name]; !ok {
+				order = append(order, name)
+			}
+			vars[name] = v
+			current, comment = nil, ""
+			continue
+		}
+
+		targets, rest, ok := cutRule(expandMakeRefs(trimmed, vars))
+		if !ok || strings.Contains(strings.SplitN(rest, ";", 2)[0], "=") {
+			imp.Skipped = append(imp.Skipped, fmt.Sprintf("line %d: %s", lineNo, trimmed))
+			current, comment = nil, ""
+			continue
+		}
+		rest, desc, _ := strings.Cut(rest, "##")
+		prereqs, recipe, _ := strings.Cut(rest, ";")
+		if desc = strings.TrimSpace(desc); desc == "" {
+			desc = comment
+		}
+		comment = ""
+		names := strings.Fields(targets)
+		current = nil
+		if slices.Contains(names, ".PHONY") {
+			for _, p := range strings.Fields(prereqs) {
+				phony[p] = true
+			}
+			continue
+		}
+		for _, name := range names {
+			switch {
+			case strings.HasPrefix(name, "."):
+				continue
+			case strings.Contains(name, "%"):
+				imp.Skipped = append(imp.Skipped, fmt.Sprintf("line %d: pattern rule %s", lineNo, name))
+				continue
+			}
+			i := imp.index(name)
+			if i < 0 {
+				imp.Tasks = append(imp.Tasks, MakeTask{Name: name, Line: lineNo})
+				i = len(imp.Tasks) - 1
+			}
+			t := &imp.Tasks[i]
+			if t.Desc == "" {
+				t.Desc = desc
+			}
+			t.Deps = append(t.Deps, strings.Fields(strings.ReplaceAll(stripMakeComment(prereqs), "|", ""))...)
+			if recipe := strings.TrimSpace(recipe); recipe != "" {
+				t.Cmds = append(t.Cmds, makeCmd(recipe))
+			}
+			current = append(current, i)
+		}
+	}
+
+	for i := range imp.Tasks {
+		t := &imp.Tasks[i]
+		var deps []string
+		for _, d := range t.Deps {
+			if imp.index(d) >= 0 {
+				deps = append(deps, d)
+			} else {
+				t.Sources = append(t.Sources, d)
+			}
+		}
+		t.Deps = deps
+		if !phony[t.Name] && strings.ContainsAny(t.Name, "./") {
+			t.Generates = []string{t.Name}
+		}
+		for j, c := range t.Cmds {
+			c.Cmd = strings.NewReplacer("$@", t.Name, "$<", firstOf(t.Deps, t.Sources), "$^", strings.Join(append(slices.Clone(t.Deps), t.Sources...), " ")).Replace(c.Cmd)
+			t.Cmds[j].Cmd = translateMakeRefs(c.Cmd, vars)
+		}
+		t.Vars = usedVars(t.Cmds, vars, order)
+	}
+	return imp, nil
+}
+
+// index returns the index of the task of the named target, -1 when there is none
+func (imp *MakeImport) index(name string) int {
+	return slices.IndexFunc(imp.Tasks, func(t MakeTask) bool { return t.Name == name })
+}
+
+// cutRule splits a rule into its targets and what follows the colon, which may be a double colon
+func cutRule(line string) (targets, rest string, ok bool) {
+	i := strings.Index(line, ":")
+	if i <= 0 || strings.HasPrefix(line[i:], ":=") {
+		return "", "", false
+	}
+	rest = strings.TrimPrefix(line[i+1:], ":")
+	return line[:i], rest, true
+}
+
+// makeCmd translates a line of a recipe, dropping its @ and - prefixes
+func makeCmd(line string) MakeCmd {
+	var c MakeCmd
+	for {
+		switch {
+		case strings.HasPrefix(line, "@"):
+			c.Silent = true
+		case strings.HasPrefix(line, "-"):
+			c.IgnoreError = true
+		case strings.HasPrefix(line, "+"):
+		default:
+			c.Cmd = strings.TrimSpace(line)
+			return c
+		}
+		line = line[1:]
+	}
+}
+
+// stripMakeComment drops a comment ending a line
+func stripMakeComment(s string) string {
+	if i := strings.Index(s, " #"); i >= 0 {
+		s = s[:i]
+	}
+	return strings.TrimSpace(s)
+}
+
+// firstOf returns the first element of the first list that isn't empty
+func firstOf(lists ...[]string) string {
+	for _, l := range lists {
+		if len(l) > 0 {
+			return l[0]
+		}
+	}
+	return ""
+}
+
+// expandMakeRefs replaces the references to variables in s by their values, as make does in the
+// targets and prerequisites of rules; variables set to the output of a command are kept
+func expandMakeRefs(s string, vars map[string]MakeVar) string {
+	for range 10 { // values may refer to other variables
+		expanded := makeRefPattern.ReplaceAllStringFunc(s, func(ref string) string {
+			if v, ok := vars[makeRefPattern.FindStringSubmatch(ref)[1]]; ok && !v.Sh {
+				return v.Value
+			}
+			return ref
+		})
+		if expanded == s {
+			break
+		}
+		s = expanded
+	}
+	return s
+}
+
+// translateMakeRefs rewrites references to the variables of the Makefile as references to the
+// vars of the task, $(MAKE) as task and other references as environment variables of the shell
+func translateMakeRefs(s string, vars map[string]MakeVar) string {
+	s = makeRefPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		name := makeRefPattern.FindStringSubmatch(ref)[1]
+		switch _, ok := vars[name]; {
+		case ok:
+			return "{{." + name + "}}"
+		case name == "MAKE":
+			return "task"
+		default:
+			return "${" + name + "}"
+		}
+	})
+	return strings.ReplaceAll(s, "$$", "$")
+}
+
+// usedVars returns the variables the commands use, with the variables those use, in the order
+// they are defined in the Makefile; their values are translated like the commands
+func usedVars(cmds []MakeCmd, vars map[string]MakeVar, order []string) []MakeVar {
+	used := map[string]bool{}
+	var visit func(s string)
+	visit = func(s string) {
+		for _, m := range taskVarRefPattern.FindAllStringSubmatch(s, -1) {
+			if v, ok := vars[m[1]]; ok && !used[m[1]] {
+				used[m[1]] = true
+				visit(translateMakeRefs(v.Value, vars))
+			}
+		}
+	}
+	for _, c := range cmds {
+		visit(c.Cmd)
+	}
+	var result []MakeVar
+	for _, name := range order {
+		if used[name] {
+			v := vars[name]
+			v.Value = translateMakeRefs(v.Value, vars)
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// YAML returns the tasks as definitions written under tasks, indented by two spaces
+func (imp *MakeImport) YAML() (string, error) {
+	var b strings.Builder
+	for i, t := range imp.Tasks {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		def := yaml.Node{Kind: yaml.MappingNode}
+		add := func(key string, value *yaml.Node) {
+			def.Content = append(def.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+		}
+		if t.Desc != "" {
+			add("desc", stringNode(t.Desc))
+		}
+		if len(t.Vars) > 0 {
+			vars := &yaml.Node{Kind: yaml.MappingNode}
+			for _, v := range t.Vars {
+				value := stringNode(v.Value)
+				if v.Sh {
+					value = &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{stringNode("sh"), stringNode(v.Value)}}
+				}
+				vars.Content = append(vars.Content, stringNode(v.Name), value)
+			}
+			add("vars", vars)
+		}
+		for _, list := range []struct {
+			key    string
+			values []string
+		}{{"deps", t.Deps}, {"sources", t.Sources}, {"generates", t.Generates}} {
+			if len(list.values) > 0 {
+				seq := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+				for _, v := range list.values {
+					seq.Content = append(seq.Content, stringNode(v))
+				}
+				add(list.key, seq)
+			}
+		}
+		if silent := len(t.Cmds) > 0 && !slices.ContainsFunc(t.Cmds, func(c MakeCmd) bool { return !c.Silent }); silent {
+			add("silent", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"})
+		}
+		cmds := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, c := range t.Cmds {
+			if c.IgnoreError {
+				cmds.Content = append(cmds.Content, &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+					stringNode("cmd"), stringNode(c.Cmd),
+					stringNode("ignore_error"), {Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"},
+				}})
+			} else {
+				cmds.Content = append(cmds.Content, stringNode(c.Cmd))
+			}
+		}
+		if len(cmds.Content) > 0 {
+			add("cmds", cmds)
+		}
+		if len(def.Content) == 0 {
+			def.Style = yaml.FlowStyle
+		}
+
+		task := yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{stringNode(t.Name), &def}}
+		var out strings.Builder
+		enc := yaml.NewEncoder(&out)
+		enc.SetIndent(2)
+		if err := enc.Encode(&task); err != nil {
+			return "", fmt.Errorf("target %s: %w", t.Name, err)
+		}
+		for _, line := range strings.SplitAfter(out.String(), "\n") {
+			if line != "" {
+				b.WriteString("  " + line)
+			}
+		}
+	}
+	return b.String(), nil
+}
+
+// stringNode returns a node for the string s, quoted when needed
+func stringNode(s string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: s}
+}
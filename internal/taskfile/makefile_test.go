@@ -0,0 +1,91 @@
+package taskfile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestImportMakefile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Makefile")
+	os.WriteFile(path, []byte(`BINARY := app
+GOFLAGS ?= -v
+VERSION = $(shell git describe --tags)
+LDFLAGS = -X main.version=$(VERSION)
+
+.PHONY: build test clean
+
+# Build the binary
+build: bin/$(BINARY)
+
+bin/app: main.go go.mod
+	go build $(GOFLAGS) -ldflags "$(LDFLAGS)" \
+		-o $@ .
+
+test: ## Run the tests
+	@go test ./...
+	@echo done $$HOME
+
+clean:
+	-rm -rf bin
+
+%.o: %.c
+	cc -c $<
+`), 0o644)
+
+	imp, err := ImportMakefile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := imp.YAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `  build:
+    desc: Build the binary
+    deps: [bin/app]
+
+  bin/app:
+    vars:
+      GOFLAGS: -v
+      VERSION:
+        sh: git describe --tags
+      LDFLAGS: -X main.version={{.VERSION}}
+    sources: [main.go, go.mod]
+    generates: [bin/app]
+    cmds:
+      - go build {{.GOFLAGS}} -ldflags "{{.LDFLAGS}}" -o bin/app .
+
+  test:
+    desc: Run the tests
+    silent: true
+    cmds:
+      - go test ./...
+      - echo done $HOME
+
+  clean:
+    cmds:
+      - cmd: rm -rf bin
+        ignore_error: true
+`
+	if got != want {
+		t.Errorf("Unexpected tasks:\n%s\nwant:\n%s", got, want)
+	}
+	if len(imp.Skipped) != 1 || !strings.Contains(imp.Skipped[0], "pattern rule %.o") {
+		t.Errorf("Expected the pattern rule to be skipped, got %q", imp.Skipped)
+	}
+
+	taskfilePath := filepath.Join(dir, "Taskfile.yml")
+	if _, err := AddTasks(taskfilePath, got); err != nil {
+		t.Fatal(err)
+	}
+	tf, err := Load(taskfilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tf.Tasks) != 4 {
+		t.Errorf("Expected the Taskfile to define the 4 tasks, got %v", tf.Tasks)
+	}
+}
@@ -0,0 +1,86 @@
+package taskfile
+
+import (
+	"os"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Strings is a list of strings that may be written as a single string
+type Strings []string
+
+// UnmarshalYAML accepts a single string or a list of strings
+func (s *Strings) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*s = Strings{node.Value}
+		return nil
+	}
+	var list []string
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*s = list
+	return nil
+}
+
+// Requires lists the variables a task needs to be given to run
+type Requires struct {
+	Vars []RequiredVar `yaml:"vars"`
+}
+
+// RequiredVar is a variable a task requires, optionally limited to a set of allowed values
+type RequiredVar struct {
+	Name string   `yaml:"name"`
+	Enum []string `yaml:"enum"`
+}
+
+// UnmarshalYAML accepts a variable name or a mapping with the name and allowed values
+func (v *RequiredVar) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*v = RequiredVar{Name: node.Value}
+		return nil
+	}
+	type plain RequiredVar
+	return node.Decode((*plain)(v))
+}
+
+// Requirements is the input a task needs before it can run unattended
+type Requirements struct {
+	// Prompts are the questions task asks for confirmation before running
+	Prompts []string
+	// Vars are the required variables that aren't set by the Taskfile or the environment
+	Vars []RequiredVar
+}
+
+// Empty reports whether the task can run without any input
+func (r Requirements) Empty() bool {
+	return len(r.Prompts) == 0 && len(r.Vars) == 0
+}
+
+// Requirements returns the prompts and missing required variables of the named task. Required
+// variables set by the Taskfile, the task or the process environment are not missing.
+func (tf *Taskfile) Requirements(name string) (Requirements, error) {
+	_, def, err := tf.Task(name)
+	if err != nil {
+		return Requirements{}, err
+	}
+	defined := func(name string) bool {
+		if _, ok := os.LookupEnv(name); ok {
+			return true
+		}
+		for _, vars := range []Vars{tf.Vars, tf.Env, def.Vars, def.Env} {
+			if slices.ContainsFunc(vars, func(v Var) bool { return v.Name == name }) {
+				return true
+			}
+		}
+		return false
+	}
+	req := Requirements{Prompts: def.Prompt}
+	for _, v := range def.Requires.Vars {
+		if !defined(v.Name) {
+			req.Vars = append(req.Vars, v)
+		}
+	}
+	return req, nil
+}
@@ -0,0 +1,54 @@
+package taskfile
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRequirementsListsPromptsAndMissingVars(t *testing.T) {
+	path := writeTaskfile(t, t.TempDir(), "Taskfile.yml", `version: '3'
+vars:
+  REGION: eu
+tasks:
+  deploy:
+    prompt: Deploy to production?
+    requires:
+      vars:
+        - API_KEY
+        - REGION
+        - TASHTEST_FROM_ENV
+        - name: TARGET
+          enum: [staging, production]
+  wipe:
+    prompt:
+      - Wipe the database?
+      - Really?
+  build: go build
+`)
+	t.Setenv("TASHTEST_FROM_ENV", "set")
+	tf, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := tf.Requirements("deploy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(req.Prompts, []string{"Deploy to production?"}) {
+		t.Errorf("Expected the deploy prompt, got %v", req.Prompts)
+	}
+	if len(req.Vars) != 2 || req.Vars[0].Name != "API_KEY" || req.Vars[1].Name != "TARGET" {
+		t.Fatalf("Expected API_KEY and TARGET to be missing, got %+v", req.Vars)
+	}
+	if !slices.Equal(req.Vars[1].Enum, []string{"staging", "production"}) {
+		t.Errorf("Expected TARGET's allowed values, got %v", req.Vars[1].Enum)
+	}
+
+	if req, _ := tf.Requirements("wipe"); len(req.Prompts) != 2 {
+		t.Errorf("Expected two prompts, got %v", req.Prompts)
+	}
+	if req, _ := tf.Requirements("build"); !req.Empty() {
+		t.Errorf("Expected no requirements, got %+v", req)
+	}
+}
@@ -0,0 +1,122 @@
+package taskfile
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Snippet is a common task definition that can be added to a Taskfile
+type Snippet struct {
+	Name        string
+	Description string
+	// Tasks are the definitions added under tasks, indented by two spaces, in which {project}
+	// stands for the name of the project
+	Tasks string
+}
+
+// Snippets is the library of snippets offered for new Taskfiles
+var Snippets = []Snippet{
+	{
+		Name:        "go build",
+		Description: "Build the Go binary into bin/ when its sources change",
+		Tasks: `  build:
+    desc: Build {project}
+    sources: ["**/*.go", go.mod, go.sum]
+    generates: [bin/{project}]
+    cmds:
+      - go build -o bin/{project} .
+`,
+	},
+	{
+		Name:        "go test",
+		Description: "Run the Go tests with the race detector",
+		Tasks: `  test:
+    desc: Test {project}
+    cmds:
+      - go test -race ./...
+`,
+	},
+	{
+		Name:        "go lint",
+		Description: "Vet the Go code and lint it with golangci-lint",
+		Tasks: `  lint:
+    desc: Lint {project}
+    cmds:
+      - go vet ./...
+      - golangci-lint run
+`,
+	},
+	{
+		Name:        "docker build",
+		Description: "Build the Docker image, tagged with the git commit",
+		Tasks: `  docker:build:
+    desc: Build the {project} image
+    vars:
+      TAG:
+        sh: git rev-parse --short HEAD
+    cmds:
+      - docker build --tag {project}:{{.TAG}} --tag {project}:latest .
+`,
+	},
+	{
+		Name:        "docker run",
+		Description: "Run the latest Docker image, building it first",
+		Tasks: `  docker:run:
+    desc: Run the {project} image
+    deps: [docker:build]
+    cmds:
+      - docker run --rm --interactive --tty {project}:latest
+`,
+	},
+	{
+		Name:        "clean",
+		Description: "Remove the build output",
+		Tasks: `  clean:
+    desc: Remove the build output of {project}
+    cmds:
+      - rm -rf bin/
+`,
+	},
+}
+
+// projectPattern matches the project names snippets accept, which are valid image names
+var projectPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// ProjectName suggests the name of the project in dir for snippets, from the name of dir
+func ProjectName(dir string) string {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '.', r == '_', r == '-':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		default:
+			return '-'
+		}
+	}, filepath.Base(dir))
+	name = strings.TrimLeft(name, "._-")
+	if name == "" {
+		return "app"
+	}
+	return name
+}
+
+// Render returns the task definitions of the snippet for the named project
+func (s Snippet) Render(project string) string {
+	return strings.ReplaceAll(s.Tasks, "{project}", project)
+}
+
+// InsertSnippet adds the tasks of the snippet for the named project after the last task of the
+// Taskfile at path, creating the Taskfile when it doesn't exist. It returns the line of the first
+// added task.
+func InsertSnippet(path string, s Snippet, project string) (int, error) {
+	if !projectPattern.MatchString(project) {
+		return 0, fmt.Errorf("invalid project name %q: use lower case letters, digits, '.', '_' and '-'", project)
+	}
+	return AddTasks(path, s.Render(project))
+}
@@ -0,0 +1,53 @@
+package taskfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInsertSnippet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Taskfile.yml")
+
+	line, err := InsertSnippet(path, Snippets[0], "tash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if line != 4 || !strings.HasPrefix(string(data), newTaskfile+"  build:\n    desc: Build tash\n") {
+		t.Fatalf("Expected a new Taskfile with the snippet at line 4, got line %d:\n%s", line, data)
+	}
+	if _, err := InsertSnippet(path, Snippets[0], "tash"); !errors.Is(err, ErrTaskExists) {
+		t.Errorf("Expected the task to exist already, got %v", err)
+	}
+	if _, err := InsertSnippet(path, Snippets[1], "Not Valid"); err == nil {
+		t.Error("Expected an invalid project name to be refused")
+	}
+
+	content := "version: '3'\n\ntasks:\n    fmt:\n        cmds: [gofmt -w .]\n\n    vet: go vet ./...\n\nvars:\n    A: b\n"
+	os.WriteFile(path, []byte(content), 0o644)
+	line, err = InsertSnippet(path, Snippets[1], "tash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(path)
+	want := "    vet: go vet ./...\n\n    test:\n        desc: Test tash\n        cmds:\n            - go test -race ./...\n\nvars:\n"
+	if line != 9 || !strings.Contains(string(data), want) {
+		t.Errorf("Expected the snippet indented like the tasks at line 9, got line %d:\n%s", line, data)
+	}
+	tf, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := tf.Tasks["test"]; !ok {
+		t.Errorf("Expected the Taskfile to define the task, got %v", tf.Tasks)
+	}
+}
+
+func TestProjectName(t *testing.T) {
+	if got := ProjectName(filepath.Join(t.TempDir(), "My Service")); got != "my-service" {
+		t.Errorf("Expected my-service, got %q", got)
+	}
+}
@@ -0,0 +1,147 @@
+// Package taskfile reads Taskfiles directly, for the details the task binary doesn't report,
+// such as the variables and environment a task runs with.
+package taskfile
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Error represents a textual error value that implements the error interface.
+type Error string
+
+// Error returns the string representation of the Error type. It satisfies the error interface.
+func (e Error) Error() string {
+	return string(e)
+}
+
+// ErrNotFound is returned when no Taskfile exists in a directory
+// ErrUnknownTask is returned when a Taskfile doesn't define the requested task
+// ErrTaskExists is returned when adding a task under a name the Taskfile already defines
+const (
+	ErrNotFound    = Error("no Taskfile found")
+	ErrUnknownTask = Error("task not defined in Taskfile")
+	ErrTaskExists  = Error("task already defined in Taskfile")
+)
+
+// DefaultNames are the file names task looks for, in order of precedence
+var DefaultNames = []string{
+	"Taskfile.yml",
+	"taskfile.yml",
+	"Taskfile.yaml",
+	"taskfile.yaml",
+	"Taskfile.dist.yml",
+	"taskfile.dist.yml",
+	"Taskfile.dist.yaml",
+	"taskfile.dist.yaml",
+}
+
+// Taskfile is the part of a Taskfile tash reads
+type Taskfile struct {
+	// Path is the file the Taskfile was loaded from
+	Path    string              `yaml:"-"`
+	Version string              `yaml:"version"`
+	Vars    Vars                `yaml:"vars"`
+	Env     Vars                `yaml:"env"`
+	Dotenv  []string            `yaml:"dotenv"`
+	Tasks   map[string]*TaskDef `yaml:"tasks"`
+}
+
+// TaskDef is the definition of a task in a Taskfile
+type TaskDef struct {
+	Desc     string   `yaml:"desc"`
+	Summary  string   `yaml:"summary"`
+	Aliases  Strings  `yaml:"aliases"`
+	Dir      string   `yaml:"dir"`
+	Vars     Vars     `yaml:"vars"`
+	Env      Vars     `yaml:"env"`
+	Dotenv   []string `yaml:"dotenv"`
+	Prompt   Strings  `yaml:"prompt"`
+	Requires Requires `yaml:"requires"`
+	Deps     []Call   `yaml:"deps"`
+	Cmds     []Call   `yaml:"cmds"`
+}
+
+// UnmarshalYAML accepts the short forms of a task, a single command or a list of commands
+func (d *TaskDef) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.MappingNode:
+		type plain TaskDef
+		return node.Decode((*plain)(d))
+	case yaml.ScalarNode:
+		*d = TaskDef{Cmds: []Call{{Cmd: node.Value}}}
+	case yaml.SequenceNode:
+		*d = TaskDef{}
+		return node.Decode(&d.Cmds)
+	default:
+		*d = TaskDef{}
+	}
+	return nil
+}
+
+// Find returns the path of the Taskfile in dir
+func Find(dir string) (string, error) {
+	for _, name := range DefaultNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("%w in %s", ErrNotFound, dir)
+}
+
+// Load reads and parses the Taskfile at path
+func Load(path string) (*Taskfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var tf Taskfile
+	if err := yaml.Unmarshal(data, &tf); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	tf.Path = path
+	return &tf, nil
+}
+
+// Task returns the definition of the named task. Tasks of included Taskfiles are listed with
+// their namespace, which is dropped when looking them up in the file that defines them.
+func (tf *Taskfile) Task(name string) (string, *TaskDef, error) {
+	for n := name; ; {
+		if def, ok := tf.Tasks[n]; ok {
+			if def == nil {
+				def = &TaskDef{}
+			}
+			return n, def, nil
+		}
+		_, rest, ok := cutNamespace(n)
+		if !ok {
+			return "", nil, fmt.Errorf("%w: %s", ErrUnknownTask, name)
+		}
+		n = rest
+	}
+}
+
+// cutNamespace splits the first namespace from a task name
+func cutNamespace(name string) (namespace, rest string, ok bool) {
+	for i := 0; i < len(name); i++ {
+		if name[i] == ':' {
+			return name[:i], name[i+1:], true
+		}
+	}
+	return "", "", false
+}
+
+// Dir returns the directory of the Taskfile, which task paths are relative to
+func (tf *Taskfile) Dir() string {
+	return filepath.Dir(tf.Path)
+}
+
+// IsNotFound reports whether err means that no Taskfile exists
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound) || errors.Is(err, os.ErrNotExist)
+}
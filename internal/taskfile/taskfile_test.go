@@ -0,0 +1,152 @@
+package taskfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+func writeTaskfile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFindPrefersDefaultNamesInOrder(t *testing.T) {
+	dir := t.TempDir()
+	writeTaskfile(t, dir, "Taskfile.dist.yml", "version: '3'\n")
+	want := writeTaskfile(t, dir, "Taskfile.yaml", "version: '3'\n")
+
+	got, err := Find(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
+
+func TestFindReportsMissingTaskfile(t *testing.T) {
+	_, err := Find(t.TempDir())
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestLoadKeepsVariableOrderAndShortTasks(t *testing.T) {
+	path := writeTaskfile(t, t.TempDir(), "Taskfile.yml", `version: '3'
+vars:
+  B: two
+  A: one
+  DYNAMIC:
+    sh: echo hi
+tasks:
+  short: echo short
+  list:
+    - echo one
+  build:
+    desc: Build it
+`)
+	tf, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, v := range tf.Vars {
+		names = append(names, v.Name)
+	}
+	if !slices.Equal(names, []string{"B", "A", "DYNAMIC"}) {
+		t.Errorf("Expected vars in definition order, got %v", names)
+	}
+	if tf.Vars[2].Sh != "echo hi" {
+		t.Errorf("Expected dynamic var command 'echo hi', got %q", tf.Vars[2].Sh)
+	}
+	if _, def, err := tf.Task("short"); err != nil || def.Desc != "" {
+		t.Errorf("Expected short task to load, got %v, %v", def, err)
+	}
+	if _, def, _ := tf.Task("build"); def.Desc != "Build it" {
+		t.Errorf("Expected desc 'Build it', got %q", def.Desc)
+	}
+}
+
+func TestTaskDropsNamespaces(t *testing.T) {
+	tf := &Taskfile{Tasks: map[string]*TaskDef{"build": {Desc: "Build"}}}
+	name, def, err := tf.Task("docs:build")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "build" || def.Desc != "Build" {
+		t.Errorf("Expected build, got %s", name)
+	}
+	if _, _, err := tf.Task("docs:missing"); !errors.Is(err, ErrUnknownTask) {
+		t.Errorf("Expected ErrUnknownTask, got %v", err)
+	}
+}
+
+func TestEnvironmentLayersTaskfileDotenvAndTaskEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+	dir := t.TempDir()
+	writeTaskfile(t, dir, ".env", "# comment\nFROM_DOTENV=\"dotenv\"\nOVERRIDDEN=dotenv\n")
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := writeTaskfile(t, dir, "Taskfile.yml", `version: '3'
+dotenv: ['.env']
+vars:
+  NAME: world
+  GREETING: hello {{.NAME}}
+env:
+  OVERRIDDEN: global
+  GLOBAL: '{{.GREETING}}'
+tasks:
+  build:
+    dir: sub
+    vars:
+      WHO:
+        sh: echo "$GLOBAL"
+    env:
+      OVERRIDDEN: task
+      WHO_ENV: '{{.WHO}}'
+`)
+	t.Setenv("TASHTEST_INHERITED", "inherited")
+	tf, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := tf.Environment("build")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env.Dir != filepath.Join(dir, "sub") {
+		t.Errorf("Expected dir %s, got %s", filepath.Join(dir, "sub"), env.Dir)
+	}
+	for _, want := range []string{
+		"TASHTEST_INHERITED=inherited",
+		"FROM_DOTENV=dotenv",
+		"OVERRIDDEN=task",
+		"GLOBAL=hello world",
+		"WHO_ENV=hello world",
+	} {
+		if !slices.Contains(env.Env, want) {
+			t.Errorf("Expected %s in the environment", want)
+		}
+	}
+	if slices.Contains(env.Env, "OVERRIDDEN=global") {
+		t.Error("Expected the task's env to replace the Taskfile's")
+	}
+	vars := map[string]string{}
+	for _, v := range env.Vars {
+		vars[v.Name] = v.Value
+	}
+	if vars["GREETING"] != "hello world" || vars["WHO"] != "hello world" {
+		t.Errorf("Expected resolved vars, got %v", vars)
+	}
+}
@@ -0,0 +1,219 @@
+package taskfile
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Var is a variable or environment variable of a Taskfile. Values are either static, possibly
+// containing template expressions, or dynamic, produced by a shell command.
+type Var struct {
+	Name  string
+	Value string
+	Sh    string
+	// Source is where a resolved variable was set, empty in definitions
+	Source Source
+}
+
+// Vars keeps variables in the order they are defined, as later ones may refer to earlier ones
+type Vars []Var
+
+// UnmarshalYAML reads a mapping of variables, keeping their order
+func (v *Vars) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: expected a mapping of variables", node.Line)
+	}
+	vars := make(Vars, 0, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		name, value := node.Content[i].Value, node.Content[i+1]
+		switch value.Kind {
+		case yaml.ScalarNode:
+			vars = append(vars, Var{Name: name, Value: value.Value})
+		case yaml.MappingNode:
+			var dynamic struct {
+				Sh string `yaml:"sh"`
+			}
+			if err := value.Decode(&dynamic); err != nil {
+				return err
+			}
+			vars = append(vars, Var{Name: name, Sh: dynamic.Sh})
+		default:
+			// lists and maps are kept as their YAML text
+			var raw any
+			if err := value.Decode(&raw); err != nil {
+				return err
+			}
+			vars = append(vars, Var{Name: name, Value: fmt.Sprint(raw)})
+		}
+	}
+	*v = vars
+	return nil
+}
+
+// TaskEnv is the environment a task runs with
+type TaskEnv struct {
+	// Task is the name of the task in its Taskfile
+	Task string
+	// Dir is the directory the task runs in
+	Dir string
+	// Env is the process environment, in the form used by exec.Cmd
+	Env []string
+	// Vars are the resolved template variables, which task doesn't export to the environment
+	Vars Vars
+	// EnvSources tells where each entry of Env was last set
+	EnvSources map[string]Source
+}
+
+// Environment resolves the environment of the named task: the process environment, overlaid with the
+// dotenv files and env of the Taskfile and then of the task. Template expressions are expanded
+// and dynamic variables are evaluated with sh.
+func (tf *Taskfile) Environment(name string) (TaskEnv, error) {
+	taskName, def, err := tf.Task(name)
+	if err != nil {
+		return TaskEnv{}, err
+	}
+	dir := tf.Dir()
+	if def.Dir != "" {
+		dir = def.Dir
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(tf.Dir(), dir)
+		}
+	}
+
+	env := map[string]string{}
+	sources := map[string]Source{}
+	var order []string
+	setEnv := func(name, value string, source Source) {
+		if _, ok := env[name]; !ok {
+			order = append(order, name)
+		}
+		env[name] = value
+		sources[name] = source
+	}
+	for _, kv := range os.Environ() {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			setEnv(k, v, SourceEnvironment)
+		}
+	}
+
+	// template data holds the variables resolved so far; task exposes env vars to templates too
+	data := map[string]any{"TASK": taskName, "ROOT_DIR": tf.Dir(), "TASKFILE_DIR": tf.Dir()}
+	resolve := func(v Var) (string, error) {
+		if v.Sh != "" {
+			return evalSh(expand(v.Sh, data), dir, env, order)
+		}
+		return expand(v.Value, data), nil
+	}
+
+	var vars Vars
+	for _, group := range []struct {
+		vars, env            Vars
+		dotenv               []string
+		varSource, envSource Source
+	}{
+		{tf.Vars, tf.Env, tf.Dotenv, SourceGlobalVars, SourceGlobalEnv},
+		{def.Vars, def.Env, def.Dotenv, SourceTaskVars, SourceTaskEnv},
+	} {
+		for _, v := range group.vars {
+			value, err := resolve(v)
+			if err != nil {
+				return TaskEnv{}, fmt.Errorf("variable %s: %w", v.Name, err)
+			}
+			data[v.Name] = value
+			vars = append(vars, Var{Name: v.Name, Value: value, Source: group.varSource})
+		}
+		for _, file := range group.dotenv {
+			values, err := readDotenv(filepath.Join(tf.Dir(), expand(file, data)))
+			if err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				return TaskEnv{}, err
+			}
+			for _, v := range values {
+				setEnv(v.Name, v.Value, SourceDotenv)
+				data[v.Name] = v.Value
+			}
+		}
+		for _, v := range group.env {
+			value, err := resolve(v)
+			if err != nil {
+				return TaskEnv{}, fmt.Errorf("env %s: %w", v.Name, err)
+			}
+			setEnv(v.Name, value, group.envSource)
+			data[v.Name] = value
+		}
+	}
+
+	result := TaskEnv{Task: taskName, Dir: dir, Vars: vars, EnvSources: sources}
+	for _, k := range order {
+		result.Env = append(result.Env, k+"="+env[k])
+	}
+	return result, nil
+}
+
+// expand executes the template expressions in s, leaving s unchanged if it isn't a valid template
+func expand(s string, data map[string]any) string {
+	if !strings.Contains(s, "{{") {
+		return s
+	}
+	t, err := template.New("").Option("missingkey=zero").Parse(s)
+	if err != nil {
+		return s
+	}
+	var b bytes.Buffer
+	if err := t.Execute(&b, data); err != nil {
+		return s
+	}
+	return strings.ReplaceAll(b.String(), "<no value>", "")
+}
+
+// evalSh runs a dynamic variable's command and returns its trimmed output
+func evalSh(command, dir string, env map[string]string, order []string) (string, error) {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Dir = dir
+	for _, k := range order {
+		cmd.Env = append(cmd.Env, k+"="+env[k])
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("running %q: %w", command, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// readDotenv reads KEY=VALUE lines from a dotenv file
+func readDotenv(path string) (Vars, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var vars Vars
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		k, v, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		v = strings.TrimSpace(v)
+		if len(v) >= 2 && (v[0] == '"' || v[0] == '\'') && v[len(v)-1] == v[0] {
+			v = v[1 : len(v)-1]
+		}
+		vars = append(vars, Var{Name: strings.TrimSpace(k), Value: v})
+	}
+	return vars, scanner.Err()
+}
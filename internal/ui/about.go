@@ -0,0 +1,161 @@
+package ui
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/msgbus"
+	"github.com/Aj4x/tash/internal/task"
+	"github.com/Aj4x/tash/internal/taskfile"
+	"github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// clipboardOut is where the OSC 52 sequences copying text to the terminal's clipboard are written
+var clipboardOut io.Writer = os.Stderr
+
+// aboutLine is a detail of the about overlay
+type aboutLine struct {
+	Label string
+	Value string
+}
+
+// tashVersion returns the version tash was built as
+func tashVersion() string {
+	if bi, ok := debug.ReadBuildInfo(); ok && bi.Main.Version != "" {
+		return bi.Main.Version
+	}
+	return "unknown"
+}
+
+// describeFile returns path, noting when the file doesn't exist
+func describeFile(path string, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return path + " (not present)"
+	}
+	return path
+}
+
+// aboutReport returns the details of tash, task and the project needed to file a bug report
+func (m Model) aboutReport() []aboutLine {
+	lines := []aboutLine{
+		{"tash", tashVersion()},
+		{"Go", fmt.Sprintf("%s %s/%s", runtime.Version(), runtime.GOOS, runtime.GOARCH)},
+		{"Terminal", fmt.Sprintf("%s, %dx%d", cmp.Or(os.Getenv("TERM"), "TERM not set"), m.Width, m.Height)},
+	}
+
+	env := ExecEnvironment(m.Config)
+	if m.Config.Provider == task.ProviderDemo {
+		lines = append(lines, aboutLine{"task", "not used, showing the demo tasks"})
+	} else {
+		binary := "not found on PATH"
+		if cmd := env.Command(context.Background(), nil, "task"); cmd.Err == nil {
+			binary = cmd.Path
+		}
+		lines = append(lines, aboutLine{"task", m.TaskVersion.String() + " at " + binary})
+		experiments := task.Experiments(env.Environ(nil))
+		lines = append(lines, aboutLine{"Experiments", cmp.Or(strings.Join(experiments, ", "), "none")})
+	}
+
+	dir, _ := filepath.Abs(m.catalogDir())
+	lines = append(lines, aboutLine{"Directory", dir})
+	if m.Config.Taskfile != "" {
+		lines = append(lines, aboutLine{"Taskfile", m.Config.Taskfile})
+	} else if m.Config.Provider != task.ProviderDemo {
+		lines = append(lines, aboutLine{"Taskfile", describeFile(taskfile.Find(dir))})
+	}
+
+	lines = append(lines, aboutLine{"Config", describeFile(config.Path())})
+	lines = append(lines, aboutLine{"Project config", describeFile(filepath.Join(dir, config.ProjectFileName), nil)})
+	dataDir, err := config.DataDir()
+	if err != nil {
+		dataDir = err.Error()
+	}
+	lines = append(lines, aboutLine{"Data directory", dataDir})
+	lines = append(lines, aboutLine{"Log file", cmp.Or(m.LogPath, "off, start with --debug to write one")})
+
+	if reporter, ok := m.MessageBus.(msgbus.StatsReporter); ok {
+		stats := reporter.Stats()
+		lines = append(lines, aboutLine{"Message bus", fmt.Sprintf(
+			"%d published, %d delayed, %d in flight, %d dropped; %d subscribers on %d topics",
+			stats.Published, stats.Delayed, stats.InFlight, stats.Dropped, stats.Subscribers, stats.Topics)})
+	}
+	running := "idle"
+	if m.TasksLoading {
+		running = "running " + cmp.Or(m.RunningTaskId, "a command")
+	}
+	queue := fmt.Sprintf("%s; %d tasks selected", running, len(m.SelectedTasks))
+	if m.ExecutingBatch {
+		queue += " (batch executing)"
+	}
+	queue += fmt.Sprintf("; %d scheduled", len(m.Schedules))
+	lines = append(lines, aboutLine{"Queue", queue})
+	return lines
+}
+
+// aboutText returns the about details as plain text, as copied into a bug report
+func aboutText(lines []aboutLine) string {
+	var b strings.Builder
+	for _, l := range lines {
+		fmt.Fprintf(&b, "%s: %s\n", l.Label, l.Value)
+	}
+	return b.String()
+}
+
+// copyToClipboard copies text to the clipboard of the terminal with an OSC 52 sequence, which
+// also reaches the clipboard of the local machine over SSH. Terminals without OSC 52 ignore it.
+func copyToClipboard(text string) tea.Cmd {
+	return func() tea.Msg {
+		seq := osc52.New(text)
+		if os.Getenv("TMUX") != "" {
+			seq = seq.Tmux()
+		}
+		_, _ = seq.WriteTo(clipboardOut)
+		return nil
+	}
+}
+
+// handleAboutOverlayKey handles key presses in the about overlay
+func (m Model) handleAboutOverlayKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.resolveKey(msg) {
+	case ActionClose:
+		m.SetState(StateNormal)
+	case ActionCopy:
+		m.SetState(StateNormal)
+		m.AppendAppMsg("Copied the details for a bug report to the clipboard\n")
+		cmd := copyToClipboard(aboutText(m.aboutReport()))
+		return m, cmd
+	}
+	return m, nil
+}
+
+// RenderAboutOverlay renders the versions, paths and counters of the running tash
+func RenderAboutOverlay(width, height int, lines []aboutLine) string {
+	overlayWidth := int(float64(width) * 0.7)
+
+	labelWidth := 0
+	for _, l := range lines {
+		labelWidth = max(labelWidth, len(l.Label))
+	}
+	content := TaskPickerTitleStyle.Render("About tash") + "\n\n"
+	for _, l := range lines {
+		content += TaskDetailOverlayLabelStyle.Render(fmt.Sprintf("%-*s", labelWidth+1, l.Label+":")) + " " + l.Value + "\n"
+	}
+	content += "\n" + HelpStyle.Render("c copies these details for a bug report · esc closes")
+
+	overlay := GeneralOverlayStyle(overlayWidth).Render(content)
+
+	return placeOverlay(width, height, overlay)
+}
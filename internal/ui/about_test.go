@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/msgbus"
+	"github.com/Aj4x/tash/internal/task"
+)
+
+func TestAboutOverlayCopiesTheDetailsForABugReport(t *testing.T) {
+	var out bytes.Buffer
+	previous := clipboardOut
+	clipboardOut = &out
+	t.Cleanup(func() { clipboardOut = previous })
+	t.Setenv("TMUX", "")
+
+	cfg := config.Default()
+	cfg.Provider = task.ProviderDemo
+	m := NewModel(msgbus.NewMessageBus[task.Message](), cfg)
+	m.HandleWindowResize(120, 40)
+	m.LogPath = "/var/log/tash.log"
+	m.SelectedTasks = []task.Task{{Id: "build"}}
+
+	updated, _ := m.handleNormalKey(runes("B"))
+	if m = updated.(Model); m.State != StateAboutOverlay {
+		t.Fatalf("Expected the about overlay, got state %s", m.State)
+	}
+	overlay := RenderAboutOverlay(160, 40, m.aboutReport())
+	for _, want := range []string{"About tash", "demo tasks", "/var/log/tash.log", "Message bus", "1 tasks selected"} {
+		if !strings.Contains(overlay, want) {
+			t.Errorf("Expected %q in the overlay, got:\n%s", want, overlay)
+		}
+	}
+
+	updated, cmd := m.handleKeyMsg(runes("c"))
+	if m = updated.(Model); cmd == nil || m.State != StateNormal {
+		t.Fatalf("Expected the details to be copied, got state %s", m.State)
+	}
+	cmd()
+	seq := out.String()
+	encoded := strings.TrimSuffix(strings.TrimPrefix(seq, "\x1b]52;c;"), "\x07")
+	text, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatalf("Expected an OSC 52 sequence, got %q", seq)
+	}
+	if !strings.Contains(string(text), "Log file: /var/log/tash.log\n") {
+		t.Errorf("Expected the details as plain text, got:\n%s", text)
+	}
+}
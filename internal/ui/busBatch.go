@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"cmp"
+	"time"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/msgbus"
+	"github.com/Aj4x/tash/internal/task"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// idlePollInterval is how often the bus is polled while no messages arrive, unless configured
+	idlePollInterval = 50 * time.Millisecond
+	// defaultFPS and maxFPS are the default and highest number of frames drawn per second; while
+	// messages arrive the bus is polled once per frame
+	defaultFPS = 60
+	maxFPS     = 120
+	// reducedMotionPollInterval is how often the bus is polled in reduced motion mode, output
+	// being redrawn a few times a second at most
+	reducedMotionPollInterval = 250 * time.Millisecond
+	// maxBatch bounds the messages handled at once, so a task flooding the output can't hold up
+	// key presses for long
+	maxBatch = 4096
+)
+
+// busBatchMsg holds the bus messages that arrived since the bus was last polled
+type busBatchMsg []task.Message
+
+// pollMessages waits for the next poll of the bus, sooner while messages are arriving unless
+// motion is reduced
+func (m Model) pollMessages() tea.Cmd {
+	interval := m.tickInterval()
+	if m.busActive && !m.ReducedMotion {
+		interval = time.Second / time.Duration(FPS(m.Config))
+	}
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return drainBus(m.busHandler)
+	})
+}
+
+// tickInterval returns how often the bus is polled while no messages arrive
+func (m Model) tickInterval() time.Duration {
+	interval := max(cmp.Or(time.Duration(m.Config.TickInterval), idlePollInterval), time.Millisecond)
+	if m.ReducedMotion {
+		interval = max(interval, reducedMotionPollInterval)
+	}
+	return interval
+}
+
+// FPS returns the highest number of frames drawn per second configured in cfg
+func FPS(cfg config.Config) int {
+	if cfg.MaxFPS <= 0 {
+		return defaultFPS
+	}
+	return min(cfg.MaxFPS, maxFPS)
+}
+
+// drainBus returns the messages waiting in handler as a batch, or a tick when there are none
+func drainBus(handler msgbus.MessageHandler[task.Message]) tea.Msg {
+	var batch busBatchMsg
+	for len(batch) < maxBatch {
+		select {
+		case msg := <-handler:
+			batch = append(batch, msg.Message)
+		default:
+			if len(batch) == 0 {
+				return TickMessage{}
+			}
+			return batch
+		}
+	}
+	return batch
+}
+
+// handleBusBatch handles a batch of bus messages, flushing the output they append to the
+// viewport once at the end rather than for every line
+func (m Model) handleBusBatch(batch busBatchMsg) (Model, tea.Cmd) {
+	var cmds []tea.Cmd
+	m.batching = true
+	for _, msg := range batch {
+		var cmd tea.Cmd
+		m, cmd = m.handleBusMessage(msg)
+		cmds = append(cmds, cmd)
+	}
+	m.batching = false
+	if m.outputPending {
+		m.flushOutput()
+	}
+	m.busActive = len(batch) > 0
+	return m, tea.Batch(append(cmds, m.pollMessages())...)
+}
+
+// flushOutput shows the appended output in the viewport
+func (m *Model) flushOutput() {
+	m.outputPending = false
+	m.spillOutput()
+	m.renderOutput()
+	m.Viewport.GotoBottom()
+}
@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/msgbus"
+	"github.com/Aj4x/tash/internal/task"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// outputBatch returns a batch of n lines of task output
+func outputBatch(from, n int) busBatchMsg {
+	batch := make(busBatchMsg, n)
+	for i := range batch {
+		batch[i] = task.TypeTaskOutput.Message().SetOutput(fmt.Sprintf("line %d", from+i))
+	}
+	return batch
+}
+
+func TestBusBatchFlushesOutputOnce(t *testing.T) {
+	m := newNavigationModel(1)
+	m.HandleWindowResize(120, 40)
+
+	updated, _ := m.Update(outputBatch(0, 500))
+	m = updated.(Model)
+	if m.outputPending || m.batching {
+		t.Fatal("Expected the output to be flushed at the end of the batch")
+	}
+	if got := m.Viewport.TotalLineCount(); got != 501 {
+		t.Errorf("Expected the 500 lines after the leading empty line, got %d", got)
+	}
+	if !m.Viewport.AtBottom() || !strings.Contains(m.Viewport.View(), "line 499") {
+		t.Error("Expected the viewport to follow the output to its last line")
+	}
+	if !m.busActive {
+		t.Error("Expected the bus to be polled at the frame rate while messages arrive")
+	}
+
+	updated, _ = m.Update(TickMessage{})
+	if updated.(Model).busActive {
+		t.Error("Expected the bus to be polled at the idle rate once no messages arrive")
+	}
+}
+
+func TestDrainBusIsBounded(t *testing.T) {
+	handler := make(msgbus.MessageHandler[task.Message], maxBatch+10)
+	if _, ok := drainBus(handler).(TickMessage); !ok {
+		t.Fatal("Expected a tick when no messages are waiting")
+	}
+	for range maxBatch + 10 {
+		handler <- msgbus.TopicMessage[task.Message]{Message: task.TypeTaskOutput.Message()}
+	}
+	if batch := drainBus(handler).(busBatchMsg); len(batch) != maxBatch {
+		t.Errorf("Expected a batch of %d messages, got %d", maxBatch, len(batch))
+	}
+	if batch := drainBus(handler).(busBatchMsg); len(batch) != 10 {
+		t.Errorf("Expected the remaining 10 messages, got %d", len(batch))
+	}
+}
+
+func BenchmarkBusBatch(b *testing.B) {
+	batches := make([]busBatchMsg, 0, benchmarkLines/maxBatch+1)
+	for from := 0; from < benchmarkLines; from += maxBatch {
+		batches = append(batches, outputBatch(from, min(maxBatch, benchmarkLines-from)))
+	}
+	b.ReportAllocs()
+	for range b.N {
+		m := NewModel(nil, config.Default())
+		m.HandleWindowResize(120, 40)
+		for _, batch := range batches {
+			m, _ = m.handleBusBatch(batch)
+		}
+		m.clearOutput()
+	}
+}
+
+func TestOnlyThePollChainPollsTheBus(t *testing.T) {
+	m := newNavigationModel(1)
+	m.HandleWindowResize(120, 40)
+
+	// anything but the batches and ticks of the poll would start a second chain of polls
+	for _, msg := range []tea.Msg{listFetchedMsg{}, tea.FocusMsg{}, tea.ResumeMsg{}, task.TypeTaskOutput.Message().SetOutput("line")} {
+		if _, cmd := m.Update(msg); cmd != nil {
+			t.Errorf("Expected %T not to poll the bus, got a command", msg)
+		}
+	}
+	for _, msg := range []tea.Msg{TickMessage{}, outputBatch(0, 1)} {
+		if _, cmd := m.Update(msg); cmd == nil {
+			t.Errorf("Expected %T to poll the bus again", msg)
+		}
+	}
+}
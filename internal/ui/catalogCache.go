@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/Aj4x/tash/internal/catalog"
+	"github.com/Aj4x/tash/internal/task"
+)
+
+// UseCatalogCache caches the task list in store between starts. When the project's Taskfiles
+// haven't changed since the list was cached, the cached tasks are shown straight away; the list
+// is still refreshed in the background, updating the table only if the tasks changed.
+func (m *Model) UseCatalogCache(store catalog.Store) {
+	if m.Config.DisableCatalogCache || m.Config.Provider == task.ProviderDemo {
+		return
+	}
+	m.CatalogCache = store
+	m.showCachedCatalog()
+}
+
+// showCachedCatalog shows the cached tasks of the Taskfile in use, if any. A remote Taskfile's
+// tasks are shown however old they are, as it may not be reachable.
+func (m *Model) showCachedCatalog() {
+	var listing, age string
+	var ok bool
+	if task.IsRemoteTaskfile(m.Config.Taskfile) {
+		var listed time.Time
+		listing, listed, ok = m.CatalogCache.LoadRemote(m.Config.Taskfile)
+		age = ", listed " + m.TimeFormat.Format(listed, time.DateTime)
+	} else if m.Config.Taskfile == "" {
+		listing, ok = m.CatalogCache.Load(m.catalogDir())
+	}
+	if !ok {
+		return
+	}
+	tasks, err := parseTasksJson(listing)
+	if err != nil || len(tasks) == 0 {
+		return
+	}
+	m.showTasks(tasks)
+	m.startup.mark("cached tasks shown")
+	m.cachedTasks = tasks
+	m.AppendAppMsg(fmt.Sprintf("Showing %d cached tasks%s while the task list is refreshed\n", len(tasks), age))
+}
+
+// catalogDir returns the project directory the task list is cached for
+func (m Model) catalogDir() string {
+	if m.Config.WorkDir != "" {
+		return m.Config.WorkDir
+	}
+	return "."
+}
+
+// saveCatalog caches a listing along with the Taskfiles its tasks come from. The listing of a
+// remote Taskfile is cached by its URL instead.
+func (m Model) saveCatalog(listing string, tasks []task.Task) {
+	if task.IsRemoteTaskfile(m.Config.Taskfile) {
+		if err := m.CatalogCache.SaveRemote(m.Config.Taskfile, listing); err != nil {
+			slog.Warn("unable to cache the task list", "error", err)
+		}
+		return
+	}
+	if m.Config.Taskfile != "" {
+		return
+	}
+	var taskfiles []string
+	for _, t := range tasks {
+		if t.Location != nil && t.Location.Taskfile != "" && !slices.Contains(taskfiles, t.Location.Taskfile) {
+			taskfiles = append(taskfiles, t.Location.Taskfile)
+		}
+	}
+	if err := m.CatalogCache.Save(m.catalogDir(), listing, taskfiles); err != nil {
+		slog.Warn("unable to cache the task list", "error", err)
+	}
+}
+
+// cachedListUnchanged reports whether the refreshed tasks are the cached ones already shown, and
+// forgets the cached tasks
+func (m *Model) cachedListUnchanged(tasks []task.Task) bool {
+	cached := m.cachedTasks
+	m.cachedTasks = nil
+	return cached != nil && reflect.DeepEqual(cached, tasks)
+}
@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Aj4x/tash/internal/catalog"
+	"github.com/Aj4x/tash/internal/task"
+)
+
+func TestCachedTaskListIsShownAndRefreshedInTheBackground(t *testing.T) {
+	project := t.TempDir()
+	taskfile := filepath.Join(project, "Taskfile.yml")
+	if err := os.WriteFile(taskfile, []byte("version: '3'\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	listing := func(names ...string) string {
+		var tasks []map[string]any
+		for _, name := range names {
+			tasks = append(tasks, map[string]any{"name": name, "location": map[string]any{"taskfile": taskfile}})
+		}
+		data, _ := json.Marshal(map[string]any{"tasks": tasks})
+		return string(data)
+	}
+	store := catalog.NewStore(t.TempDir())
+
+	// the first start has nothing cached, and caches the listing
+	m := newNavigationModel(0)
+	m.Config.WorkDir = project
+	m.UseCatalogCache(store)
+	m.RefreshTaskList()
+	if len(m.Tasks) != 0 || !m.listing {
+		t.Fatalf("Expected nothing cached, got %d tasks", len(m.Tasks))
+	}
+	m, _ = m.handleBusMessage(task.TypeTaskJSON.Message().SetOutput(listing("build", "test")))
+
+	// the next start shows the cached tasks while they are refreshed
+	m = newNavigationModel(0)
+	m.Config.WorkDir = project
+	m.UseCatalogCache(store)
+	m.RefreshTaskList()
+	if len(m.Tasks) != 2 || m.listing || m.TasksLoading {
+		t.Fatalf("Expected the 2 cached tasks, got %d", len(m.Tasks))
+	}
+	m.Table.SetCursor(1)
+	m, _ = m.handleBusMessage(task.TypeTaskJSON.Message().SetOutput(listing("build", "test")))
+	if len(m.Tasks) != 2 || m.Table.Cursor() != 1 || m.cachedTasks != nil {
+		t.Fatalf("Expected the unchanged list to leave the table alone, got %d tasks", len(m.Tasks))
+	}
+
+	// a refresh that differs replaces the cached tasks
+	m = newNavigationModel(0)
+	m.Config.WorkDir = project
+	m.UseCatalogCache(store)
+	m, _ = m.handleBusMessage(task.TypeTaskJSON.Message().SetOutput(listing("build", "lint", "test")))
+	if len(m.Tasks) != 3 {
+		t.Fatalf("Expected the refreshed list, got %d tasks", len(m.Tasks))
+	}
+
+	// editing the Taskfile invalidates the cache
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(taskfile, later, later); err != nil {
+		t.Fatal(err)
+	}
+	m = newNavigationModel(0)
+	m.Config.WorkDir = project
+	m.UseCatalogCache(store)
+	if len(m.Tasks) != 0 {
+		t.Errorf("Expected the changed Taskfile to invalidate the cache, got %d tasks", len(m.Tasks))
+	}
+}
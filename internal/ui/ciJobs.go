@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"slices"
+
+	"github.com/Aj4x/tash/internal/ci"
+	"github.com/Aj4x/tash/internal/task"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ciJobsMsg carries the jobs of the project's CI pipelines, read along with the task list
+type ciJobsMsg struct {
+	jobs []task.Task
+	err  error
+}
+
+// isCIJob reports whether t is a job of a CI pipeline rather than a task of the Taskfile
+func isCIJob(t task.Task) bool {
+	return len(t.Command) > 0
+}
+
+// loadCIJobs reads the jobs of the project's CI pipelines when they are listed, as tasks of the ci
+// namespace running the jobs locally
+func (m Model) loadCIJobs() tea.Cmd {
+	if !m.Config.CI.List || m.Config.Provider == task.ProviderDemo {
+		return nil
+	}
+	dir := m.catalogDir()
+	runner := ci.Runner{Act: m.Config.CI.Act, Container: m.Config.CI.Container}
+	return func() tea.Msg {
+		jobs, err := ci.Load(dir)
+		tasks := make([]task.Task, len(jobs))
+		for i, j := range jobs {
+			tasks[i] = task.Task{
+				Id:       j.Id(),
+				Desc:     j.Description(),
+				Location: &task.Location{Taskfile: j.File, Line: j.Line},
+				Command:  runner.Args(j, dir),
+			}
+		}
+		return ciJobsMsg{jobs: tasks, err: err}
+	}
+}
+
+// handleCIJobsMsg lists the CI jobs after the tasks, replacing those listed before
+func (m Model) handleCIJobsMsg(msg ciJobsMsg) (Model, tea.Cmd) {
+	if msg.err != nil {
+		m.AppendErrorMsg("Unable to read the CI pipelines: " + msg.err.Error())
+	}
+	m.ciJobs = msg.jobs
+	m.showTasks(slices.DeleteFunc(slices.Clone(m.AllTasks), isCIJob))
+	return m, nil
+}
+
+// refuseCIJob reports whether t is a CI job that can't be run, as running jobs isn't enabled
+func (m *Model) refuseCIJob(t task.Task) bool {
+	if !isCIJob(t) || m.Config.CI.Run {
+		return false
+	}
+	m.AppendErrorMsg(t.Id + " is a CI job, set \"run\" in the \"ci\" config to run it locally")
+	return true
+}
@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/task"
+)
+
+func TestCIJobsListedAfterTasks(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, ".github", "workflows"), 0o755)
+	os.WriteFile(filepath.Join(dir, ".github", "workflows", "ci.yml"), []byte("jobs:\n  test:\n    runs-on: ubuntu-latest\n"), 0o644)
+	cfg := config.Default()
+	cfg.WorkDir = dir
+	cfg.CI.List = true
+	m := NewModel(nil, cfg)
+	m.HandleWindowResize(120, 40)
+	m.showTasks([]task.Task{{Id: "build", Desc: "Build"}})
+
+	m, _ = m.handleCIJobsMsg(m.loadCIJobs()().(ciJobsMsg))
+	if len(m.Tasks) != 2 || m.Tasks[1].Id != "ci:ci:test" {
+		t.Fatalf("Expected the CI job after the task, got %+v", m.Tasks)
+	}
+	// a refreshed listing keeps the jobs, and they aren't listed twice
+	m.showTasks([]task.Task{{Id: "build", Desc: "Build"}, {Id: "lint", Desc: "Lint"}})
+	m, _ = m.handleCIJobsMsg(ciJobsMsg{jobs: m.ciJobs})
+	if len(m.AllTasks) != 3 || m.AllTasks[2].Id != "ci:ci:test" {
+		t.Errorf("Expected the tasks followed by the job once, got %+v", m.AllTasks)
+	}
+}
+
+func TestCIJobsRunOnlyWhenEnabled(t *testing.T) {
+	cfg := config.Default()
+	m := NewModel(nil, cfg)
+	m.HandleWindowResize(120, 40)
+	m.ciJobs = []task.Task{{Id: "ci:gitlab:test", Desc: "test", Command: []string{"docker", "run"}}}
+	m.showTasks(nil)
+
+	if cmd := m.runTask("ci:gitlab:test"); cmd != nil || m.RunningTaskId != "" {
+		t.Errorf("Expected the job not to run, got %q running", m.RunningTaskId)
+	}
+
+	m.Config.CI.Run = true
+	if cmd := m.runTask("ci:gitlab:test"); cmd == nil || m.RunningTaskId != "ci:gitlab:test" {
+		t.Errorf("Expected the job to run once enabled")
+	}
+	if _, err := taskfilePath(m.AllTasks[0]); err != errNotInTaskfile {
+		t.Errorf("Expected CI jobs not to be edited as tasks, got %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Aj4x/tash/internal/task"
+	"github.com/Aj4x/tash/internal/taskfile"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ClonePrompt holds the name entered for the copy of a task
+type ClonePrompt struct {
+	TaskId string
+	Path   string // Taskfile defining the task, which the copy is added to
+	Name   string // Name of the copy in that Taskfile
+	Error  string // Reason the task couldn't be cloned, shown until the name is changed
+}
+
+// openClonePrompt asks for the name of a copy of t, suggesting one from its name in its Taskfile
+func (m *Model) openClonePrompt(t task.Task) {
+	if m.Config.Provider == task.ProviderDemo {
+		m.AppendErrorMsg("Demo tasks are not defined in a Taskfile")
+		return
+	}
+	path, err := taskfilePath(t)
+	var name string
+	if err == nil {
+		var tf *taskfile.Taskfile
+		if tf, err = taskfile.Load(path); err == nil {
+			name, _, err = tf.Task(t.Id)
+		}
+	}
+	if err != nil {
+		m.AppendErrorMsg("Unable to read the definition of " + t.Id + ": " + err.Error())
+		return
+	}
+	m.ClonePrompt = ClonePrompt{TaskId: t.Id, Path: path, Name: name + "-copy"}
+	m.SetState(StateClonePrompt)
+}
+
+// handleClonePromptKey handles key presses while entering the name of a copy
+func (m Model) handleClonePromptKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	prompt := &m.ClonePrompt
+	switch action := m.resolveKey(msg); {
+	case action == ActionClose:
+		m.SetState(StateNormal)
+	case action == ActionConfirm:
+		cmd := m.cloneTask()
+		return m, cmd
+	case IsKeyMatch(msg, "backspace"):
+		if runes := []rune(prompt.Name); len(runes) > 0 {
+			prompt.Name = string(runes[:len(runes)-1])
+		}
+		prompt.Error = ""
+	case msg.Type == tea.KeyRunes:
+		prompt.Name += string(msg.Runes)
+		prompt.Error = ""
+	}
+	return m, nil
+}
+
+// cloneTask adds the copy to the Taskfile and opens it in the user's editor at the new
+// definition. The task list is refreshed once the editor exits.
+func (m *Model) cloneTask() tea.Cmd {
+	prompt := m.ClonePrompt
+	name := strings.TrimSpace(prompt.Name)
+	line, err := taskfile.CloneTask(prompt.Path, prompt.TaskId, name)
+	if err != nil {
+		m.ClonePrompt.Error = err.Error()
+		return nil
+	}
+	m.SetState(StateNormal)
+	m.AppendAppMsg(fmt.Sprintf("Cloned %s to %s in %s\n", prompt.TaskId, name, prompt.Path))
+	return m.openEditor(prompt.Path, line)
+}
+
+// RenderClonePrompt renders the overlay used to name the copy of a task
+func RenderClonePrompt(width, height int, prompt ClonePrompt) string {
+	overlayWidth := int(float64(width) * 0.7)
+
+	content := TaskPickerTitleStyle.Render("Clone "+prompt.TaskId) + "\n\n"
+	content += TaskPickerInputStyle(overlayWidth).Render(prompt.Name) + "\n\n"
+	if prompt.Error != "" {
+		content += ErrorMsgStyle.Render(prompt.Error) + "\n\n"
+	}
+	content += HelpStyle.Render("Name in " + prompt.Path + "; the copy is added after the task")
+
+	overlay := GeneralOverlayStyle(overlayWidth).Render(content)
+
+	return placeOverlay(width, height, overlay)
+}
@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/task"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCloneTaskAddsTheCopyAndOpensTheEditor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Taskfile.yml")
+	content := "version: '3'\ntasks:\n  test: go test ./...\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("EDITOR", "true")
+	m := NewModel(nil, config.Default())
+	m.HandleWindowResize(120, 40)
+
+	m.openClonePrompt(task.Task{Id: "test", Location: &task.Location{Taskfile: path, Line: 3}})
+	if m.State != StateClonePrompt || m.ClonePrompt.Name != "test-copy" {
+		t.Fatalf("Expected the clone prompt with a suggested name, got state %s and %q", m.State, m.ClonePrompt.Name)
+	}
+	for range len("-copy") {
+		updated, _ := m.handleClonePromptKey(tea.KeyMsg{Type: tea.KeyBackspace})
+		m = updated.(Model)
+	}
+	updated, _ := m.handleClonePromptKey(runes(":integration"))
+	m = updated.(Model)
+
+	updated, cmd := m.handleClonePromptKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if m = updated.(Model); cmd == nil || m.State != StateNormal {
+		t.Fatalf("Expected the editor to be opened, got state %s and error %q", m.State, m.ClonePrompt.Error)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(string(data), "  test: go test ./...\n  test:integration: go test ./...\n") {
+		t.Errorf("Expected the copy after the task, got:\n%s", data)
+	}
+
+	m.openClonePrompt(task.Task{Id: "test", Location: &task.Location{Taskfile: path, Line: 3}})
+	m.ClonePrompt.Name = "test:integration"
+	updated, cmd = m.handleClonePromptKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if m = updated.(Model); cmd != nil || m.State != StateClonePrompt || m.ClonePrompt.Error == "" {
+		t.Errorf("Expected an existing name to be rejected in the prompt, got state %s", m.State)
+	}
+}
@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Aj4x/tash/internal/task"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// RenderCommandLine renders the overlay used to enter an ad-hoc shell command
+func RenderCommandLine(width, height int, input string) string {
+	overlayWidth := int(float64(width) * 0.7)
+
+	content := TaskPickerTitleStyle.Render("Run Command") + "\n\n"
+	content += ":" + TaskPickerInputStyle(overlayWidth).Render(input) + "\n\n"
+	content += HelpStyle.Render("Runs with your shell in the project directory; ctrl+x cancels it")
+
+	overlay := GeneralOverlayStyle(overlayWidth).Render(content)
+
+	return placeOverlay(width, height, overlay)
+}
+
+// handleCommandLineKey handles key presses while entering a shell command
+func (m Model) handleCommandLineKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch action := m.resolveKey(msg); {
+	case action == ActionClose:
+		m.SetState(StateNormal)
+	case action == ActionConfirm:
+		command := strings.TrimSpace(m.CommandInput)
+		m.SetState(StateNormal)
+		if command == "" {
+			return m, nil
+		}
+		return m, m.runShellCommand(command)
+	case IsKeyMatch(msg, "backspace"):
+		if len(m.CommandInput) > 0 {
+			m.CommandInput = m.CommandInput[:len(m.CommandInput)-1]
+		}
+	case msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace:
+		m.CommandInput += string(msg.Runes)
+		if msg.Type == tea.KeySpace {
+			m.CommandInput += " "
+		}
+	}
+	return m, nil
+}
+
+// runShellCommand runs an ad-hoc command through the same pipeline as tasks, so its output is
+// streamed to the viewport and it can be cancelled. Commands are not recorded in the run history.
+func (m *Model) runShellCommand(command string) tea.Cmd {
+	if m.TasksLoading {
+		m.AppendErrorMsg("Wait for the running task to finish before running a command")
+		return nil
+	}
+	m.AppendAppMsg(fmt.Sprintf("$ %s\n", command))
+	m.TasksLoading = true
+	m.RunningTaskId = ""
+	opts := m.ExecOptions("")
+	bus := m.MessageBus
+
+	return func() tea.Msg {
+		task.StartShellCommand(command, opts, bus)
+		return nil
+	}
+}
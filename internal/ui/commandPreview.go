@@ -0,0 +1,29 @@
+package ui
+
+import (
+	"github.com/Aj4x/tash/internal/task"
+)
+
+// maxPreviewCommands caps the commands listed in the details overlay
+const maxPreviewCommands = 10
+
+// CommandPreview holds the commands of a task with their template variables resolved, shown in
+// the details overlay so what a run will execute can be checked beforehand
+type CommandPreview struct {
+	Commands []string
+	Err      error
+}
+
+// commandPreview resolves the commands of t against the variables it would run with: those of
+// a loop variant and the answers already given to its run prompt. Demo tasks have no preview.
+func (m Model) commandPreview(t task.Task) *CommandPreview {
+	if m.Config.Provider == task.ProviderDemo {
+		return nil
+	}
+	tf, err := loadTaskfile(t)
+	if err != nil {
+		return &CommandPreview{Err: err}
+	}
+	commands, err := tf.Commands(t.Id, m.runVars(t))
+	return &CommandPreview{Commands: commands, Err: err}
+}
@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/task"
+)
+
+func TestCommandPreviewResolvesVariables(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Taskfile.yml")
+	content := `version: '3'
+vars:
+  VERSION: 1.4.0
+tasks:
+  release:
+    cmds:
+      - git tag v{{.VERSION}} -m "{{.NOTE}}"
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m := NewModel(nil, config.Default())
+	release := task.Task{Id: "release", Location: &task.Location{Taskfile: path, Line: 5}}
+	m.RunInputs = map[string]runInputs{"release": {Vars: []string{"NOTE=first"}}}
+
+	preview := m.commandPreview(release)
+	if preview == nil || preview.Err != nil {
+		t.Fatalf("Expected a preview, got %+v", preview)
+	}
+	expected := []string{`git tag v1.4.0 -m "first"`}
+	if !slices.Equal(preview.Commands, expected) {
+		t.Errorf("Expected %q, got %q", expected, preview.Commands)
+	}
+
+	overlay := RenderTaskDetailOverlay(120, 40, &release, preview)
+	if !strings.Contains(overlay, "$ git tag v1.4.0") {
+		t.Errorf("Expected the overlay to list the resolved command, got:\n%s", overlay)
+	}
+}
+
+func TestCommandPreviewTruncatesLongLists(t *testing.T) {
+	commands := make([]string, maxPreviewCommands+3)
+	for i := range commands {
+		commands[i] = "echo"
+	}
+	rendered := renderCommandPreview(CommandPreview{Commands: commands})
+	if !strings.Contains(rendered, "… 3 more") {
+		t.Errorf("Expected the remaining commands to be counted, got:\n%s", rendered)
+	}
+}
@@ -0,0 +1,149 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Aj4x/tash/internal/compose"
+	"github.com/Aj4x/tash/internal/task"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Compose holds the services of the compose file shown in the compose overlay
+type Compose struct {
+	Services []compose.Service
+	Selected int
+	Loading  bool
+	Error    string // Why the services couldn't be listed
+}
+
+// composeServicesMsg carries the services of the compose file with the state of their containers
+type composeServicesMsg struct {
+	services []compose.Service
+	err      error
+}
+
+// composeProject returns the compose project configured for tash
+func (m Model) composeProject() compose.Project {
+	return compose.Project{Command: m.Config.Compose.Command, File: m.Config.Compose.File}
+}
+
+// openCompose shows the services of the project's compose file, loading their state
+func (m *Model) openCompose() tea.Cmd {
+	if m.Config.Provider == task.ProviderDemo {
+		m.AppendErrorMsg("The demo has no compose file")
+		return nil
+	}
+	if _, ok := compose.Find(m.catalogDir()); !ok && m.Config.Compose.File == "" {
+		m.AppendErrorMsg("No compose file in " + m.catalogDir() + ", set \"file\" in the \"compose\" config")
+		return nil
+	}
+	m.Compose.Loading = true
+	m.Compose.Error = ""
+	m.SetState(StateCompose)
+	return m.loadComposeServices()
+}
+
+// loadComposeServices lists the services of the compose file in the background
+func (m Model) loadComposeServices() tea.Cmd {
+	project, env := m.composeProject(), ExecEnvironment(m.Config)
+	return func() tea.Msg {
+		services, err := project.Services(context.Background(), env)
+		return composeServicesMsg{services: services, err: err}
+	}
+}
+
+// handleComposeServicesMsg shows the listed services, keeping the selection where it was
+func (m Model) handleComposeServicesMsg(msg composeServicesMsg) (Model, tea.Cmd) {
+	c := &m.Compose
+	c.Loading = false
+	if msg.err != nil {
+		c.Error = msg.err.Error()
+		return m, nil
+	}
+	c.Services = msg.services
+	c.Selected = max(min(c.Selected, len(c.Services)-1), 0)
+	return m, nil
+}
+
+// runCompose runs compose with args for the selected service, streaming its output like a
+// task's. The overlay closes so the output can be followed.
+func (m *Model) runCompose(action Action, args ...string) tea.Cmd {
+	c := m.Compose
+	if len(c.Services) == 0 || m.refuseReadOnly(action) {
+		return nil
+	}
+	if m.TasksLoading {
+		m.AppendErrorMsg("Wait for the running task to finish before managing services")
+		return nil
+	}
+	service := c.Services[c.Selected].Name
+	runId := fmt.Sprintf("compose %s %s", action, service)
+	m.SetState(StateNormal)
+	m.AppendAppMsg("Executing: " + runId + "\n\n")
+	m.beginRun(runId)
+	opts := m.ExecOptions(runId)
+	opts.Command = m.composeProject().Args(append(args, service)...)
+	m.startRunLog(opts, "")
+	bus := m.MessageBus
+	return func() tea.Msg {
+		task.StartTask(runId, opts, bus)
+		return nil
+	}
+}
+
+// handleComposeKey handles key presses while the compose services are shown
+func (m Model) handleComposeKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	c := &m.Compose
+	switch m.resolveKey(msg) {
+	case ActionClose:
+		m.SetState(StateNormal)
+	case ActionUp:
+		c.Selected = max(c.Selected-1, 0)
+	case ActionDown:
+		c.Selected = max(min(c.Selected+1, len(c.Services)-1), 0)
+	case ActionRefresh:
+		c.Loading = true
+		return m, m.loadComposeServices()
+	case ActionStart:
+		return m, m.runCompose(ActionStart, "up", "--detach")
+	case ActionStop:
+		return m, m.runCompose(ActionStop, "down")
+	case ActionRestart:
+		return m, m.runCompose(ActionRestart, "restart")
+	case ActionLogs:
+		return m, m.runCompose(ActionLogs, "logs", "--follow", "--tail=200")
+	}
+	return m, nil
+}
+
+// RenderCompose renders the services of the compose file with the state of their containers
+func RenderCompose(width, height int, c Compose) string {
+	overlayWidth := int(float64(width) * 0.7)
+	content := TaskPickerTitleStyle.Render("Compose services") + "\n\n"
+	switch {
+	case c.Error != "":
+		content += ErrorMsgStyle.Render(c.Error) + "\n"
+	case c.Loading && c.Services == nil:
+		content += HelpStyle.Render("Loading services…") + "\n"
+	case len(c.Services) == 0:
+		content += HelpStyle.Render("The compose file has no services") + "\n"
+	}
+	for i, s := range c.Services {
+		icon, status := "○", "not created"
+		if s.State != "" {
+			status = s.Status
+		}
+		if s.Running() {
+			icon = AppMsgStyle.Render("●")
+		}
+		line := fmt.Sprintf("%s %-24s %s", icon, s.Name, status)
+		if i == c.Selected {
+			content += TaskPickerSelectedMatchStyle(overlayWidth).Render(line) + "\n"
+		} else {
+			content += TaskPickerMatchStyle(overlayWidth).Render(line) + "\n"
+		}
+	}
+	content += "\n" + HelpStyle.Render("u up, d down, r restart, l logs, ctrl+r refresh, esc close")
+	return placeOverlay(width, height, GeneralOverlayStyle(overlayWidth).Render(content))
+}
@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/compose"
+	"github.com/Aj4x/tash/internal/config"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestComposeOverlay(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.Default()
+	cfg.WorkDir = dir
+	m := NewModel(nil, cfg)
+	m.HandleWindowResize(120, 40)
+
+	if cmd := m.openCompose(); cmd != nil || m.State == StateCompose {
+		t.Fatal("Expected no overlay without a compose file")
+	}
+	os.WriteFile(filepath.Join(dir, "compose.yaml"), []byte("services: {}\n"), 0o644)
+	if cmd := m.openCompose(); cmd == nil || m.State != StateCompose {
+		t.Fatal("Expected the services to load")
+	}
+	m, _ = m.handleComposeServicesMsg(composeServicesMsg{services: []compose.Service{
+		{Name: "db", State: "running", Status: "Up 2 minutes"},
+		{Name: "web"},
+	}})
+	view := RenderCompose(m.Width, m.Height, m.Compose)
+	if !strings.Contains(view, "Up 2 minutes") || !strings.Contains(view, "not created") {
+		t.Errorf("Expected the state of each service, got %q", view)
+	}
+
+	next, _ := m.handleComposeKey(tea.KeyMsg{Type: tea.KeyDown})
+	next, cmd := next.(Model).handleComposeKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	m = next.(Model)
+	if cmd == nil || m.RunningTaskId != "compose restart web" || m.State != StateNormal {
+		t.Errorf("Expected web to restart with its output shown, got %q in %s", m.RunningTaskId, m.State)
+	}
+
+	m, _ = m.handleComposeServicesMsg(composeServicesMsg{err: errors.New("no such command: compose")})
+	if !strings.Contains(RenderCompose(m.Width, m.Height, m.Compose), "no such command") {
+		t.Error("Expected the error listing the services")
+	}
+}
+
+func TestComposeReadOnly(t *testing.T) {
+	cfg := config.Default()
+	cfg.ReadOnly = true
+	m := NewModel(nil, cfg)
+	m.Compose = Compose{Services: []compose.Service{{Name: "db"}}}
+
+	if cmd := m.runCompose(ActionStart, "up", "--detach"); cmd != nil {
+		t.Error("Expected read-only mode to refuse starting services")
+	}
+	if cmd := m.runCompose(ActionLogs, "logs", "--follow"); cmd == nil {
+		t.Error("Expected logs to be followed in read-only mode")
+	}
+}
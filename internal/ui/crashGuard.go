@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+
+	"github.com/Aj4x/tash/internal/logging"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Crash describes a recovered panic and where its stack trace was written
+type Crash struct {
+	Value   any    // The value passed to panic
+	LogPath string // Path of the crash log, empty if it could not be written
+	LogErr  error  // Error encountered writing the crash log, if any
+}
+
+// Error returns a human-readable description of the crash, including the crash log location
+func (c *Crash) Error() string {
+	if c.LogPath != "" {
+		return fmt.Sprintf("tash crashed: %v\nstack trace written to %s", c.Value, c.LogPath)
+	}
+	return fmt.Sprintf("tash crashed: %v (unable to write crash log: %v)", c.Value, c.LogErr)
+}
+
+// crashState is shared between copies of the CrashGuard so that a panic recovered in View
+// (which cannot return a command) is seen by the next Update
+type crashState struct {
+	crash *Crash
+}
+
+// CrashGuard wraps a tea.Model and recovers from panics in Init, Update and View. Instead of
+// leaving the terminal in a broken state, the panic is written to a crash log and the program
+// quits cleanly, letting bubbletea exit the alt screen and restore the cursor.
+type CrashGuard struct {
+	Model  tea.Model
+	LogDir string // Directory crash logs are written to
+	state  *crashState
+}
+
+// NewCrashGuard wraps model in a CrashGuard writing crash logs to logDir
+func NewCrashGuard(model tea.Model, logDir string) CrashGuard {
+	return CrashGuard{Model: model, LogDir: logDir, state: &crashState{}}
+}
+
+// Crash returns the recovered panic, or nil if the wrapped model did not panic
+func (g CrashGuard) Crash() *Crash {
+	return g.state.crash
+}
+
+func (g CrashGuard) recordCrash(r any) {
+	if g.state.crash != nil {
+		return
+	}
+	stack := debug.Stack()
+	path, err := logging.WriteCrashLog(g.LogDir, r, stack)
+	slog.Error("recovered panic", "panic", fmt.Sprint(r), "crashLog", path)
+	g.state.crash = &Crash{Value: r, LogPath: path, LogErr: err}
+}
+
+// Init initialises the wrapped model
+func (g CrashGuard) Init() (cmd tea.Cmd) {
+	defer func() {
+		if r := recover(); r != nil {
+			g.recordCrash(r)
+			cmd = tea.Quit
+		}
+	}()
+	return g.Model.Init()
+}
+
+// Update forwards the message to the wrapped model, quitting if it panics
+func (g CrashGuard) Update(msg tea.Msg) (model tea.Model, cmd tea.Cmd) {
+	if g.state.crash != nil {
+		return g, tea.Quit
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			g.recordCrash(r)
+			model, cmd = g, tea.Quit
+		}
+	}()
+	g.Model, cmd = g.Model.Update(msg)
+	return g, cmd
+}
+
+// View renders the wrapped model, falling back to a short notice if it panics
+func (g CrashGuard) View() (view string) {
+	if g.state.crash != nil {
+		return "tash crashed, exiting..."
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			g.recordCrash(r)
+			view = "tash crashed, exiting..."
+		}
+	}()
+	return g.Model.View()
+}
@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type panicModel struct{}
+
+func (panicModel) Init() tea.Cmd                       { return nil }
+func (panicModel) Update(tea.Msg) (tea.Model, tea.Cmd) { panic("boom") }
+func (panicModel) View() string                        { return "" }
+
+func TestCrashGuardRecoversUpdatePanic(t *testing.T) {
+	guard := NewCrashGuard(panicModel{}, t.TempDir())
+
+	_, cmd := guard.Update(TickMessage{})
+	if cmd == nil {
+		t.Fatal("Expected a quit command after a panic")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("Expected the command to quit the program")
+	}
+
+	crash := guard.Crash()
+	if crash == nil {
+		t.Fatal("Expected the crash to be recorded")
+	}
+	data, err := os.ReadFile(crash.LogPath)
+	if err != nil {
+		t.Fatalf("Expected crash log to be written: %v", err)
+	}
+	if !strings.Contains(string(data), "panic: boom") {
+		t.Errorf("Expected crash log to contain the panic value, got %q", string(data))
+	}
+}
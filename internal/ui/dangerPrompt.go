@@ -0,0 +1,99 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// DangerPrompt holds the task id typed to confirm a run of a task the config marks dangerous
+type DangerPrompt struct {
+	TaskId string
+	Group  string // Host group the task runs on once confirmed, or empty to run it here
+	Input  string
+	Error  string // Shown when the typed id doesn't match, until the input is changed
+}
+
+// confirmDangerous reports whether taskId can run. Tasks matching the config's dangerous_tasks
+// run only once their id has been typed in the danger prompt, which is opened otherwise; each
+// confirmation is used up by the run it was given for.
+func (m *Model) confirmDangerous(taskId string) bool {
+	if !m.Config.IsDangerous(taskId) {
+		return true
+	}
+	if m.dangerConfirmed == taskId {
+		m.dangerConfirmed = ""
+		return true
+	}
+	if m.Headless {
+		m.AppendErrorMsg(taskId + " is marked dangerous, run it from an attached tash")
+		return false
+	}
+	m.DangerPrompt = DangerPrompt{TaskId: taskId}
+	m.SetState(StateDangerPrompt)
+	return false
+}
+
+// handleDangerPromptKey handles key presses while typing the id of a dangerous task
+func (m Model) handleDangerPromptKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := &m.DangerPrompt
+	switch action := m.resolveKey(msg); {
+	case action == ActionClose:
+		return m.cancelDangerPrompt()
+	case action == ActionConfirm:
+		if p.Input != p.TaskId {
+			p.Error = "The name doesn't match, type " + p.TaskId + " to run it"
+			return m, nil
+		}
+		m.dangerConfirmed = p.TaskId
+		m.SetState(StateNormal)
+		if p.Group != "" {
+			return m, m.startFanOut(p.TaskId, p.Group)
+		}
+		return m, m.runTask(p.TaskId)
+	case IsKeyMatch(msg, "backspace"):
+		if runes := []rune(p.Input); len(runes) > 0 {
+			p.Input = string(runes[:len(runes)-1])
+		}
+		p.Error = ""
+	case msg.Type == tea.KeyRunes:
+		p.Input += string(msg.Runes)
+		p.Error = ""
+	}
+	return m, nil
+}
+
+// cancelDangerPrompt abandons the run of the dangerous task, along with the batch or repeated
+// run it belongs to
+func (m Model) cancelDangerPrompt() (tea.Model, tea.Cmd) {
+	m.SetState(StateNormal)
+	m.AppendErrorMsg("Run of " + m.DangerPrompt.TaskId + " cancelled")
+	if m.ExecutingBatch {
+		m.ExecutingBatch = false
+		m.CurrentBatchTaskIndex = -1
+	}
+	m.Repeat = RepeatState{}
+	m.DangerPrompt = DangerPrompt{}
+	m.nextRunOptions = nil
+	return m, nil
+}
+
+// RenderDangerPrompt renders the overlay asking for the id of a dangerous task before it runs
+func RenderDangerPrompt(width, height int, p DangerPrompt) string {
+	overlayWidth := int(float64(width) * 0.7)
+
+	title := "Run " + p.TaskId + "?"
+	if p.Group != "" {
+		title = "Run " + p.TaskId + " on host group " + p.Group + "?"
+	}
+	content := TaskPickerTitleStyle.Render(title) + "\n\n"
+	content += ErrorMsgStyle.Render("This task is marked dangerous in the config.") + "\n"
+	content += "Type " + p.TaskId + " to confirm the run:\n\n"
+	content += TaskPickerInputStyle(overlayWidth).Render(p.Input) + "\n\n"
+	if p.Error != "" {
+		content += ErrorMsgStyle.Render(p.Error) + "\n\n"
+	}
+	content += HelpStyle.Render("enter to run, esc to cancel the run")
+
+	overlay := GeneralOverlayStyle(overlayWidth).Render(content)
+
+	return placeOverlay(width, height, overlay)
+}
@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/task"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestDangerousTasksRunOnceTheirIdIsTyped(t *testing.T) {
+	cfg := config.Default()
+	cfg.Provider = task.ProviderDemo
+	cfg.DangerousTasks = []string{"db:drop", "*:prod*"}
+	m := NewModel(nil, cfg)
+	m.HandleWindowResize(120, 40)
+	m.AllTasks = []task.Task{{Id: "db:drop"}, {Id: "deploy:production"}, {Id: "build"}}
+
+	if cmd := m.runTask("deploy:production"); cmd != nil || m.State != StateDangerPrompt || m.TasksLoading {
+		t.Fatalf("Expected the run to wait for confirmation, got state %s", m.State)
+	}
+	if overlay := RenderDangerPrompt(m.Width, m.Height, m.DangerPrompt); !strings.Contains(overlay, "Type deploy:production to confirm") {
+		t.Errorf("Expected the overlay to ask for the task id, got:\n%s", overlay)
+	}
+
+	press := func(keys ...tea.KeyMsg) tea.Cmd {
+		var cmd tea.Cmd
+		for _, k := range keys {
+			var updated tea.Model
+			updated, cmd = m.handleDangerPromptKey(k)
+			m = updated.(Model)
+		}
+		return cmd
+	}
+	if cmd := press(runes("deploy:prod"), tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || m.State != StateDangerPrompt {
+		t.Fatalf("Expected a partial id to be refused, got state %s", m.State)
+	}
+	if !strings.Contains(m.DangerPrompt.Error, "doesn't match") {
+		t.Errorf("Expected the mismatch to be explained, got %q", m.DangerPrompt.Error)
+	}
+	if cmd := press(runes("uction"), tea.KeyMsg{Type: tea.KeyEnter}); cmd == nil || m.State != StateNormal || !m.TasksLoading {
+		t.Fatalf("Expected the task to run once its id is typed, got state %s", m.State)
+	}
+
+	// The confirmation is used up by that run
+	m.TasksLoading = false
+	if m.runTask("deploy:production"); m.State != StateDangerPrompt {
+		t.Errorf("Expected the next run to ask again, got state %s", m.State)
+	}
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.State != StateNormal || m.TasksLoading || !strings.Contains(m.output.String(), "Run of deploy:production cancelled") {
+		t.Errorf("Expected the run to be cancelled, got state %s", m.State)
+	}
+
+	if cmd := m.runTask("build"); cmd == nil || m.State != StateNormal {
+		t.Errorf("Expected other tasks to run without confirmation, got state %s", m.State)
+	}
+
+	m.TasksLoading = false
+	m.Headless = true
+	if cmd := m.runTask("db:drop"); cmd != nil || m.State != StateNormal {
+		t.Errorf("Expected a headless tash to refuse the run, got state %s", m.State)
+	}
+}
@@ -0,0 +1,195 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Aj4x/tash/internal/task"
+	"github.com/Aj4x/tash/internal/taskfile"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// lintMsg carries the diagnostics of the Taskfiles, checked when the task list is refreshed
+type lintMsg struct {
+	diagnostics []taskfile.Diagnostic
+}
+
+// Patterns of the errors task reports when it can't read a Taskfile
+var (
+	// newer versions locate the error on its own line, followed by the offending lines
+	taskErrorFilePattern  = regexp.MustCompile(`^file:\s+(.+?):(\d+)(?::(\d+))?$`)
+	taskErrorParsePattern = regexp.MustCompile(`Failed to parse (.+?):?$`)
+	taskErrorLinePattern  = regexp.MustCompile(`\bline (\d+)\b`)
+	taskErrorSnippet      = regexp.MustCompile(`^\s*>?\s*\d*\s*\|`)
+)
+
+// lintTaskfiles checks the Taskfile in use and the Taskfiles it includes in the background.
+// Demo tasks have no Taskfile, and remote Taskfiles are left to task.
+func (m Model) lintTaskfiles() tea.Cmd {
+	if m.Config.Provider == task.ProviderDemo || task.IsRemoteTaskfile(m.Config.Taskfile) {
+		return nil
+	}
+	dir, path, version, env := m.Config.WorkDir, m.Config.Taskfile, m.TaskVersion, ExecEnvironment(m.Config)
+	return func() tea.Msg {
+		if dir == "" {
+			var err error
+			if dir, err = os.Getwd(); err != nil {
+				return lintMsg{}
+			}
+		}
+		if path == "" {
+			var err error
+			if path, err = taskfile.Find(dir); err != nil {
+				return lintMsg{}
+			}
+		} else if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		environ := env.Environ(nil)
+		return lintMsg{diagnostics: taskfile.Lint(path, taskfile.LintOptions{
+			Wildcards:       version.Check(task.FeatureWildcards, environ),
+			RemoteTaskfiles: version.Check(task.FeatureRemoteTaskfiles, environ),
+		})}
+	}
+}
+
+// handleLint records the lint findings, pointing them out when they change
+func (m Model) handleLint(msg lintMsg) (Model, tea.Cmd) {
+	changed := !slices.Equal(m.lintDiagnostics, msg.diagnostics)
+	m.lintDiagnostics = msg.diagnostics
+	m.updateDiagnostics()
+	if changed && len(msg.diagnostics) > 0 {
+		notice := fmt.Sprintf("Found %d problems in the Taskfiles", len(msg.diagnostics))
+		if binding, ok := m.KeyBindings.Binding(ActionDiagnostics); ok {
+			notice += ", " + binding.Key + " lists them"
+		}
+		m.AppendErrorMsg(notice + "\n")
+	}
+	return m, nil
+}
+
+// recordListFailure turns the failure of a listing into a diagnostic and shows the diagnostics
+func (m *Model) recordListFailure(err error) {
+	var listErr *task.ListError
+	if !errors.As(err, &listErr) {
+		return
+	}
+	if listErr.NotTrusted() && task.IsRemoteTaskfile(m.Config.Taskfile) {
+		m.askTrust()
+		return
+	}
+	m.listDiagnostics = []taskfile.Diagnostic{listErrorDiagnostic(listErr.Stderr)}
+	m.updateDiagnostics()
+	if m.State == StateNormal && !m.Headless {
+		m.openDiagnostics()
+	}
+}
+
+// updateDiagnostics lists the failure of the listing, then the lint findings it doesn't repeat
+func (m *Model) updateDiagnostics() {
+	m.Diagnostics = append([]taskfile.Diagnostic{}, m.listDiagnostics...)
+	for _, d := range m.lintDiagnostics {
+		if !slices.ContainsFunc(m.listDiagnostics, func(l taskfile.Diagnostic) bool { return l.File == d.File && l.Line == d.Line }) {
+			m.Diagnostics = append(m.Diagnostics, d)
+		}
+	}
+	if m.DiagnosticSelected >= len(m.Diagnostics) {
+		m.DiagnosticSelected = 0
+	}
+}
+
+// listErrorDiagnostic reads what task wrote to stderr when it couldn't list the tasks: the file
+// and line it points at, and its message without the lines of the Taskfile it quotes
+func listErrorDiagnostic(stderr []string) taskfile.Diagnostic {
+	d := taskfile.Diagnostic{Severity: taskfile.SeverityError}
+	var message []string
+	for _, line := range stderr {
+		line = strings.TrimSpace(ansiPattern.ReplaceAllString(line, ""))
+		if match := taskErrorFilePattern.FindStringSubmatch(line); match != nil {
+			d.File = match[1]
+			d.Line, _ = strconv.Atoi(match[2])
+			d.Column, _ = strconv.Atoi(match[3])
+			continue
+		}
+		if line == "" || taskErrorSnippet.MatchString(line) {
+			continue
+		}
+		if match := taskErrorParsePattern.FindStringSubmatch(line); match != nil && d.File == "" {
+			d.File = match[1]
+		}
+		if match := taskErrorLinePattern.FindStringSubmatch(line); match != nil && d.Line == 0 {
+			d.Line, _ = strconv.Atoi(match[1])
+		}
+		line = strings.TrimPrefix(strings.TrimPrefix(line, "task: "), "err: ")
+		message = append(message, strings.TrimSpace(line))
+	}
+	d.Message = strings.Join(message, " ")
+	if d.Message == "" {
+		d.Message = "task couldn't list the tasks"
+	}
+	return d
+}
+
+// openDiagnostics shows the problems found in the Taskfiles
+func (m *Model) openDiagnostics() {
+	if m.DiagnosticSelected >= len(m.Diagnostics) {
+		m.DiagnosticSelected = 0
+	}
+	m.SetState(StateDiagnosticsOverlay)
+}
+
+// handleDiagnosticsOverlayKey handles key presses in the diagnostics list
+func (m Model) handleDiagnosticsOverlayKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch action := m.resolveKey(msg); {
+	case action == ActionClose || m.KeyBindings.Matches(msg, ActionDiagnostics):
+		m.SetState(StateNormal)
+	case action == ActionUp:
+		if m.DiagnosticSelected > 0 {
+			m.DiagnosticSelected--
+		}
+	case action == ActionDown:
+		if m.DiagnosticSelected < len(m.Diagnostics)-1 {
+			m.DiagnosticSelected++
+		}
+	case action == ActionOpenFile:
+		if m.DiagnosticSelected < len(m.Diagnostics) && m.Diagnostics[m.DiagnosticSelected].File != "" {
+			d := m.Diagnostics[m.DiagnosticSelected]
+			m.SetState(StateNormal)
+			return m, m.openEditor(d.File, d.Line)
+		}
+	}
+	return m, nil
+}
+
+// RenderDiagnosticsOverlay renders the list of problems found in the Taskfiles
+func RenderDiagnosticsOverlay(width, height int, list []taskfile.Diagnostic, selected int) string {
+	overlayWidth := int(float64(width) * 0.8)
+
+	content := TaskPickerTitleStyle.Render(fmt.Sprintf("Taskfile Diagnostics (%d)", len(list))) + "\n\n"
+	if len(list) == 0 {
+		content += "No problems found in the Taskfiles.\n"
+	}
+	// show a window of the list around the selection
+	rows := max(height-12, 3)
+	first := min(max(selected-rows/2, 0), max(len(list)-rows, 0))
+	for i := first; i < len(list) && i < first+rows; i++ {
+		d := list[i]
+		line := fmt.Sprintf("%-7s %s  %s", d.Severity, d.Location(), d.Message)
+		if i == selected {
+			content += TaskPickerSelectedMatchStyle(overlayWidth).Render(line) + "\n"
+		} else {
+			content += TaskPickerMatchStyle(overlayWidth).Render(line) + "\n"
+		}
+	}
+	content += "\n" + HelpStyle.Render("checked on every refresh; e opens the Taskfile in $EDITOR at the line")
+
+	overlay := GeneralOverlayStyle(overlayWidth).Render(content)
+
+	return placeOverlay(width, height, overlay)
+}
@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/task"
+	"github.com/Aj4x/tash/internal/taskfile"
+)
+
+func TestListErrorDiagnostic(t *testing.T) {
+	tests := []struct {
+		name     string
+		stderr   []string
+		expected taskfile.Diagnostic
+	}{
+		{
+			name: "located error with snippet",
+			stderr: []string{
+				"task: Failed to parse /src/Taskfile.yml:",
+				"err: invalid keys in command",
+				"file: /src/Taskfile.yml:7:9",
+				"  6 |     cmds:",
+				"> 7 |       - cmd: echo",
+				"    |         ^",
+			},
+			expected: taskfile.Diagnostic{File: "/src/Taskfile.yml", Line: 7, Column: 9, Severity: taskfile.SeverityError,
+				Message: "Failed to parse /src/Taskfile.yml: invalid keys in command"},
+		},
+		{
+			name:   "yaml error of older versions",
+			stderr: []string{"task: Failed to parse /src/Taskfile.yml:", "yaml: line 4: did not find expected key"},
+			expected: taskfile.Diagnostic{File: "/src/Taskfile.yml", Line: 4, Severity: taskfile.SeverityError,
+				Message: "Failed to parse /src/Taskfile.yml: yaml: line 4: did not find expected key"},
+		},
+		{
+			name:     "no stderr",
+			expected: taskfile.Diagnostic{Severity: taskfile.SeverityError, Message: "task couldn't list the tasks"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := listErrorDiagnostic(tt.stderr); got != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDiagnosticsCombineTheListingAndLint(t *testing.T) {
+	m := NewModel(nil, config.Default())
+	m.HandleWindowResize(120, 40)
+
+	m, _ = m.handleLint(lintMsg{diagnostics: []taskfile.Diagnostic{
+		{File: "/src/Taskfile.yml", Line: 4, Severity: taskfile.SeverityError, Message: "did not find expected key"},
+		{File: "/src/Taskfile.yml", Line: 9, Severity: taskfile.SeverityError, Message: "task build calls relase, which isn't defined"},
+	}})
+	if len(m.Diagnostics) != 2 || !strings.Contains(m.output.String(), "Found 2 problems in the Taskfiles, ! lists them") {
+		t.Fatalf("Expected the lint findings to be pointed out, got %+v", m.Diagnostics)
+	}
+
+	m.recordListFailure(&task.ListError{Err: errors.New("exit status 201"), Stderr: []string{
+		"task: Failed to parse /src/Taskfile.yml:", "yaml: line 4: did not find expected key",
+	}})
+	if m.State != StateDiagnosticsOverlay {
+		t.Errorf("Expected a failed listing to open the diagnostics, got state %s", m.State)
+	}
+	if len(m.Diagnostics) != 2 || !strings.HasPrefix(m.Diagnostics[0].Message, "Failed to parse") || m.Diagnostics[1].Line != 9 {
+		t.Errorf("Expected the listing failure first, without the lint finding it repeats, got %+v", m.Diagnostics)
+	}
+	overlay := RenderDiagnosticsOverlay(160, 40, m.Diagnostics, 0)
+	if !strings.Contains(overlay, "/src/Taskfile.yml:9") {
+		t.Errorf("Expected the overlay to show the locations, got:\n%s", overlay)
+	}
+}
@@ -0,0 +1,178 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/task"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// pickTask adds a task to the batch through the task picker
+func pickTask(tp *testProgram, name string) {
+	tp.Type("/")
+	tp.Type(name)
+	tp.Press(tea.KeyEnter)
+}
+
+func TestEndToEndExecuteSelectedTask(t *testing.T) {
+	tp := startProgram(t, config.Default())
+
+	tp.Press(tea.KeyEnter)
+	tp.WaitForOutput("building")
+	tp.WaitForOutput("Task executed successfully!")
+}
+
+func TestEndToEndPickerBatch(t *testing.T) {
+	tp := startProgram(t, config.Default())
+
+	// "b" is the alias of build
+	pickTask(tp, "b")
+	pickTask(tp, "lint")
+	m := tp.WaitFor("two selected tasks", func(m Model) bool {
+		return len(m.SelectedTasks) == 2 && m.State == StateNormal
+	})
+	if m.SelectedTasks[0].Id != "build" || m.SelectedTasks[1].Id != "lint" {
+		t.Fatalf("Expected build and lint to be selected, got %v", m.SelectedTasks)
+	}
+
+	tp.Press(tea.KeyCtrlE)
+	tp.WaitForOutput("linting")
+	tp.WaitFor("the batch to finish", func(m Model) bool { return !m.ExecutingBatch && !m.TasksLoading })
+	if output := tp.Output(); !strings.Contains(output, "building") || strings.Index(output, "building") > strings.Index(output, "linting") {
+		t.Error("Expected build to run before lint")
+	}
+}
+
+func TestEndToEndBatchAbortsOnFailure(t *testing.T) {
+	tp := startProgram(t, config.Default())
+
+	pickTask(tp, "fail")
+	pickTask(tp, "lint")
+	tp.WaitFor("two selected tasks", func(m Model) bool { return len(m.SelectedTasks) == 2 })
+
+	tp.Press(tea.KeyCtrlE)
+	if output := tp.WaitForOutput("Batch execution aborted"); strings.Contains(output, "linting") {
+		t.Error("Expected lint not to run after fail failed")
+	}
+}
+
+func TestEndToEndCancel(t *testing.T) {
+	tp := startProgram(t, config.Default())
+
+	pickTask(tp, "slow")
+	tp.WaitFor("slow to be selected", func(m Model) bool { return len(m.SelectedTasks) == 1 })
+	tp.Press(tea.KeyCtrlE)
+	tp.WaitForOutput("waiting")
+	tp.WaitFor("slow to be running", func(m Model) bool { return m.TaskRunning })
+
+	tp.Press(tea.KeyCtrlX)
+	tp.WaitForOutput("Task cancelled")
+	if tp.Model().ExecutingBatch {
+		t.Error("Expected cancelling to stop the batch")
+	}
+}
+
+func TestEndToEndStartupTaskByAlias(t *testing.T) {
+	tp := startProgramWith(t, config.Default(), func(m *Model) { m.StartupTask = "b" })
+
+	tp.WaitForOutput("building")
+}
+
+func TestEndToEndDemoProvider(t *testing.T) {
+	cfg := config.Default()
+	cfg.Provider = task.ProviderDemo
+	tp := startProgram(t, cfg)
+
+	tp.Press(tea.KeyEnter)
+	tp.WaitForOutput("wrote bin/app")
+	tp.WaitForOutput("Task executed successfully!")
+}
+
+func TestEndToEndShellResumes(t *testing.T) {
+	// "true" stands in for an interactive shell the user exits straight away
+	t.Setenv("SHELL", "true")
+	tp := startProgram(t, config.Default())
+
+	tp.Press(tea.KeyCtrlZ)
+	tp.WaitForOutput("Returned from shell")
+	tp.WaitFor("the task list to reload", func(m Model) bool {
+		return len(m.AllTasks) > 0 && !m.TasksLoading
+	})
+}
+
+func TestEndToEndTaskShellUsesTaskEnvironment(t *testing.T) {
+	dir := t.TempDir()
+	taskfile := "version: '3'\nenv:\n  GREETING: hello\ntasks:\n  build:\n    env:\n      TARGET: '{{.GREETING}} build'\n"
+	if err := os.WriteFile(filepath.Join(dir, "Taskfile.yml"), []byte(taskfile), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// the shell records its environment and exits straight away
+	envFile := filepath.Join(dir, "env.txt")
+	shell := filepath.Join(dir, "shell")
+	if err := os.WriteFile(shell, []byte("#!/bin/sh\nenv > "+envFile+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SHELL", shell)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// start in the package directory, where the fake task binary is, then move to the project
+	tp := startProgram(t, config.Default())
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	tp.Type("i")
+	tp.WaitFor("the details overlay", func(m Model) bool { return m.State == StateDetailsOverlay })
+	tp.Type("s")
+	tp.WaitForOutput("Returned from shell")
+
+	env, err := os.ReadFile(envFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"TARGET=hello build", "GREETING=hello", "TASH_TASK=build"} {
+		if !strings.Contains(string(env), want) {
+			t.Errorf("Expected %s in the shell's environment, got:\n%s", want, env)
+		}
+	}
+}
+
+func TestEndToEndShellCommand(t *testing.T) {
+	tp := startProgram(t, config.Default())
+
+	tp.Type(":echo hello from the shell")
+	tp.Press(tea.KeyEnter)
+	tp.WaitForOutput("hello from the shell")
+	tp.WaitFor("the command to finish", func(m Model) bool { return !m.TasksLoading })
+
+	tp.Type(":sleep 30")
+	tp.Press(tea.KeyEnter)
+	tp.WaitFor("the command to be running", func(m Model) bool { return m.TaskRunning })
+	tp.Press(tea.KeyCtrlX)
+	tp.WaitForOutput("Task cancelled")
+}
+
+func TestEndToEndFailureSummary(t *testing.T) {
+	tp := startProgramWith(t, config.Default(), func(m *Model) { m.StartupTask = "fail" })
+
+	m := tp.WaitFor("the failure summary", func(m Model) bool { return m.State == StateFailureSummary })
+	s := m.FailureSummary
+	if s.Task != "fail" {
+		t.Errorf("Expected the summary of fail, got %s", s.Task)
+	}
+	if len(s.Tail) == 0 || s.Tail[len(s.Tail)-1].Text != "failing" {
+		t.Errorf("Expected the last stderr line to be failing, got %v", s.Tail)
+	}
+
+	tp.Press(tea.KeyEsc)
+	tp.WaitFor("the summary to close", func(m Model) bool { return m.State == StateNormal })
+	tp.Type("F")
+	tp.WaitFor("the summary to reopen", func(m Model) bool { return m.State == StateFailureSummary })
+}
@@ -0,0 +1,131 @@
+package ui
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Aj4x/tash/internal/task"
+	"github.com/Aj4x/tash/internal/taskfile"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// editorExitedMsg is sent when the editor opened from tash exits
+type editorExitedMsg struct {
+	err error
+}
+
+// errNotInTaskfile is returned for the Taskfile of tasks defined elsewhere, such as CI jobs
+var errNotInTaskfile = errors.New("not defined in a Taskfile")
+
+// lineArgEditors accept "+line" before the file to open at a line
+var lineArgEditors = []string{"vi", "vim", "nvim", "nano", "emacs", "micro", "kak", "joe", "mg"}
+
+// editorArgs returns the command line opening path at line with the user's editor: $VISUAL,
+// $EDITOR, or the platform's default editor. A line of 0 opens the file at its start.
+func editorArgs(path string, line int) []string {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		args = []string{"vi"}
+		if runtime.GOOS == "windows" {
+			args = []string{"notepad"}
+		}
+	}
+	if line <= 0 {
+		return append(args, path)
+	}
+	name := strings.TrimSuffix(filepath.Base(args[0]), ".exe")
+	switch {
+	case slices.Contains(lineArgEditors, name):
+		return append(args, "+"+strconv.Itoa(line), path)
+	case name == "code" || name == "codium":
+		return append(args, "--goto", path+":"+strconv.Itoa(line))
+	case name == "hx" || name == "subl":
+		return append(args, path+":"+strconv.Itoa(line))
+	default:
+		return append(args, path)
+	}
+}
+
+// taskfilePath returns the Taskfile defining t: its listed location, or the Taskfile of the
+// working directory when the listing doesn't report locations
+func taskfilePath(t task.Task) (string, error) {
+	if isCIJob(t) {
+		return "", errNotInTaskfile
+	}
+	if path := t.Taskfile(); path != "" {
+		return path, nil
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	return taskfile.Find(dir)
+}
+
+// loadTaskfile loads the Taskfile defining t
+func loadTaskfile(t task.Task) (*taskfile.Taskfile, error) {
+	path, err := taskfilePath(t)
+	if err != nil {
+		return nil, err
+	}
+	return taskfile.Load(path)
+}
+
+// openTaskfile suspends the UI and opens the Taskfile defining t in the user's editor, at the
+// task's definition when its location is known
+func (m *Model) openTaskfile(t task.Task) tea.Cmd {
+	if m.Config.Provider == task.ProviderDemo {
+		m.AppendErrorMsg("Demo tasks are not defined in a Taskfile")
+		return nil
+	}
+	if isCIJob(t) && t.Location != nil {
+		// the job is opened in its workflow or pipeline file
+		return m.openEditor(t.Location.Taskfile, t.Location.Line)
+	}
+	path, err := taskfilePath(t)
+	if err != nil {
+		m.AppendErrorMsg("Unable to find the Taskfile of " + t.Id + ": " + err.Error())
+		return nil
+	}
+	line := 0
+	if t.Location != nil {
+		line = t.Location.Line
+	}
+	return m.openEditor(path, line)
+}
+
+// openEditor suspends the UI and opens path in the user's editor at line, when it is set
+func (m *Model) openEditor(path string, line int) tea.Cmd {
+	if m.refuseReadOnly(ActionOpenFile) {
+		return nil
+	}
+	args := editorArgs(path, line)
+	m.AppendAppMsg("Opening " + path + " with " + args[0] + "\n")
+	cmd := exec.Command(args[0], args[1:]...)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return editorExitedMsg{err: err}
+	})
+}
+
+// handleEditorExited resumes after the editor exits, reloading the task list as the Taskfile
+// may have changed
+func (m Model) handleEditorExited(msg editorExitedMsg) (Model, tea.Cmd) {
+	if msg.err != nil {
+		m.AppendErrorMsg("Editor exited with an error: " + msg.err.Error())
+	}
+	if m.TasksLoading {
+		// a task is still running, so leave the task list alone
+		return m, nil
+	}
+	return m, m.RefreshTaskList()
+}
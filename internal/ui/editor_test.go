@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestEditorArgs(t *testing.T) {
+	tests := []struct {
+		editor string
+		line   int
+		want   []string
+	}{
+		{"vim", 12, []string{"vim", "+12", "Taskfile.yml"}},
+		{"/usr/bin/nano", 3, []string{"/usr/bin/nano", "+3", "Taskfile.yml"}},
+		{"code -w", 7, []string{"code", "-w", "--goto", "Taskfile.yml:7"}},
+		{"hx", 2, []string{"hx", "Taskfile.yml:2"}},
+		{"gedit", 5, []string{"gedit", "Taskfile.yml"}},
+		{"vim", 0, []string{"vim", "Taskfile.yml"}},
+	}
+	for _, tt := range tests {
+		t.Setenv("VISUAL", "")
+		t.Setenv("EDITOR", tt.editor)
+		if got := editorArgs("Taskfile.yml", tt.line); !slices.Equal(got, tt.want) {
+			t.Errorf("Expected %v for %q, got %v", tt.want, tt.editor, got)
+		}
+	}
+}
+
+func TestEditorArgsPrefersVisual(t *testing.T) {
+	t.Setenv("VISUAL", "nvim")
+	t.Setenv("EDITOR", "nano")
+	if got := editorArgs("Taskfile.yml", 0); got[0] != "nvim" {
+		t.Errorf("Expected nvim, got %v", got)
+	}
+}
@@ -0,0 +1,162 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Aj4x/tash/internal/problems"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// failureTailLines is how many of the last stderr lines of a run the failure summary shows
+const failureTailLines = 10
+
+// outputTail is a line of stderr remembered for the failure summary
+type outputTail struct {
+	Text string
+	// OutputLine is the index of the line in the output
+	OutputLine int
+}
+
+// FailureSummary gathers what explains a failed run: its error, the problems it reported
+// and its last stderr lines
+type FailureSummary struct {
+	Task     string
+	Error    string
+	Duration time.Duration
+	Problems []problems.Problem
+	Tail     []outputTail
+	Selected int
+}
+
+// captureStderrLine remembers a stderr line of the run in progress, keeping the last
+// failureTailLines of them
+func (m *Model) captureStderrLine(line string) {
+	if m.RunningTaskId == "" {
+		return
+	}
+	// appendTaskOutput has already added the line
+	tail := append(m.runStderr, outputTail{Text: ansiPattern.ReplaceAllString(line, ""), OutputLine: m.lineCount() - 1})
+	if len(tail) > failureTailLines {
+		tail = tail[len(tail)-failureTailLines:]
+	}
+	m.runStderr = tail
+}
+
+// summariseFailure builds the summary of the run that just failed with runErr. It returns
+// nil when the run was cancelled.
+func (m *Model) summariseFailure(runErr error) *FailureSummary {
+	if m.RunningTaskId == "" || m.runCancelled {
+		return nil
+	}
+	summary := &FailureSummary{
+		Task:     m.RunningTaskId,
+		Error:    runErr.Error(),
+		Duration: time.Since(m.runStarted).Round(time.Millisecond),
+		Tail:     m.runStderr,
+	}
+	for _, p := range m.Problems {
+		if p.Task == m.RunningTaskId {
+			summary.Problems = append(summary.Problems, p)
+		}
+	}
+	return summary
+}
+
+// showFailureSummary records the summary of a failed run and opens it, unless another
+// overlay is open or a batch carries on past the failure
+func (m *Model) showFailureSummary(runErr error) {
+	summary := m.summariseFailure(runErr)
+	if summary == nil {
+		return
+	}
+	m.FailureSummary = summary
+	if m.Config.DisableFailureSummary || m.State != StateNormal || (m.ExecutingBatch && m.Config.ContinueOnError) {
+		return
+	}
+	m.SetState(StateFailureSummary)
+}
+
+// openFailureSummary shows the summary of the last failed run
+func (m *Model) openFailureSummary() {
+	if m.FailureSummary == nil {
+		m.AppendAppMsg("No failed runs to summarise\n")
+		return
+	}
+	m.SetState(StateFailureSummary)
+}
+
+// RenderFailureSummary renders the summary of a failed run
+func RenderFailureSummary(width, height int, s *FailureSummary) string {
+	overlayWidth := int(float64(width) * 0.8)
+
+	content := TaskPickerTitleStyle.Render(fmt.Sprintf("Task '%s' failed after %s", s.Task, s.Duration)) + "\n\n"
+	content += ErrorMsgStyle.Render(s.Error) + "\n"
+
+	if len(s.Problems) > 0 {
+		content += "\n" + TaskPickerTitleStyle.Render("Problems") + "\n"
+		// leave room for the error, the stderr lines and the help
+		rows := max(height-failureTailLines-16, 3)
+		first := min(max(s.Selected-rows/2, 0), max(len(s.Problems)-rows, 0))
+		for i := first; i < len(s.Problems) && i < first+rows; i++ {
+			p := s.Problems[i]
+			line := fmt.Sprintf("%-7s %s  %s", p.Severity, p.Location(), p.Message)
+			if i == s.Selected {
+				content += TaskPickerSelectedMatchStyle(overlayWidth).Render(line) + "\n"
+			} else {
+				content += TaskPickerMatchStyle(overlayWidth).Render(line) + "\n"
+			}
+		}
+		if len(s.Problems) > rows {
+			content += HelpStyle.Render(fmt.Sprintf("%d problems in total", len(s.Problems))) + "\n"
+		}
+	}
+
+	if len(s.Tail) > 0 {
+		content += "\n" + TaskPickerTitleStyle.Render("Last stderr lines") + "\n"
+		for _, l := range s.Tail {
+			content += TaskPickerMatchStyle(overlayWidth).Render(l.Text) + "\n"
+		}
+	}
+
+	help := "enter jumps to the output, esc closes"
+	if len(s.Problems) > 0 {
+		help = "enter jumps to the problem in the output, e opens it in $EDITOR, esc closes"
+	}
+	content += "\n" + HelpStyle.Render(help)
+
+	overlay := GeneralOverlayStyle(overlayWidth).Render(content)
+
+	return placeOverlay(width, height, overlay)
+}
+
+// handleFailureSummaryKey handles key presses in the failure summary
+func (m Model) handleFailureSummaryKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := m.FailureSummary
+	switch m.resolveKey(msg) {
+	case ActionClose:
+		m.SetState(StateNormal)
+	case ActionUp:
+		if s.Selected > 0 {
+			s.Selected--
+		}
+	case ActionDown:
+		if s.Selected < len(s.Problems)-1 {
+			s.Selected++
+		}
+	case ActionConfirm:
+		m.SetState(StateNormal)
+		switch {
+		case s.Selected < len(s.Problems):
+			m.jumpToOutputLine(s.Problems[s.Selected].OutputLine)
+		case len(s.Tail) > 0:
+			m.jumpToOutputLine(s.Tail[0].OutputLine)
+		}
+	case ActionOpenFile:
+		if s.Selected < len(s.Problems) {
+			m.SetState(StateNormal)
+			return m, m.openProblemFile(s.Problems[s.Selected])
+		}
+	}
+	return m, nil
+}
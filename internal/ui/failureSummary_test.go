@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// failRun runs build in m, writes its stderr and fails it
+func failRun(m Model, stderr ...string) Model {
+	m.RunningTaskId = "build"
+	m.openFold("build", foldRun)
+	for _, line := range stderr {
+		m.appendTaskOutput(line, ErrorMsgStyle)
+		m.captureStderrLine(line)
+	}
+	m.showFailureSummary(errors.New("task failed with exit code 1"))
+	m.recordRun(nil)
+	return m
+}
+
+func TestFailureSummaryKeepsLastStderrLines(t *testing.T) {
+	m := newNavigationModel(1)
+	m.HandleWindowResize(120, 40)
+	var stderr []string
+	for i := range failureTailLines + 5 {
+		stderr = append(stderr, fmt.Sprintf("line %d", i))
+	}
+	stderr = append(stderr, "main.go:3:1: undefined: foo")
+	m = failRun(m, stderr...)
+
+	if m.State != StateFailureSummary {
+		t.Fatalf("Expected the failure summary to open, got %s", m.State)
+	}
+	s := m.FailureSummary
+	if len(s.Tail) != failureTailLines {
+		t.Fatalf("Expected %d stderr lines, got %d", failureTailLines, len(s.Tail))
+	}
+	if last := s.Tail[len(s.Tail)-1]; last.Text != "main.go:3:1: undefined: foo" || last.OutputLine != len(stderr)-1 {
+		t.Errorf("Expected the last stderr line at output line %d, got %q at %d", len(stderr)-1, last.Text, last.OutputLine)
+	}
+	if len(s.Problems) != 1 || s.Problems[0].File != "main.go" {
+		t.Errorf("Expected the main.go problem, got %v", s.Problems)
+	}
+}
+
+func TestFailureSummaryIsSkippedForCancelledRuns(t *testing.T) {
+	m := newNavigationModel(1)
+	m.runCancelled = true
+	m = failRun(m, "interrupted")
+
+	if m.State != StateNormal || m.FailureSummary != nil {
+		t.Errorf("Expected no summary of a cancelled run, got %s", m.State)
+	}
+}
+
+func TestFailureSummaryWaitsForBatchesContinuingOnError(t *testing.T) {
+	m := newNavigationModel(1)
+	m.Config.ContinueOnError = true
+	m.ExecutingBatch = true
+	m = failRun(m, "failing")
+
+	if m.State != StateNormal {
+		t.Errorf("Expected the batch to carry on without the summary, got %s", m.State)
+	}
+	if m.FailureSummary == nil {
+		t.Error("Expected the summary to be kept for F")
+	}
+}
@@ -0,0 +1,287 @@
+package ui
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/Aj4x/tash/internal/fanout"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxFanOutLines caps the output lines kept for each host of a fan-out; older lines are dropped
+const maxFanOutLines = 10000
+
+// fanOutHost is the run of a task on one host of a group
+type fanOutHost struct {
+	Name  string
+	Lines []string
+	Done  bool
+	Err   error // Why the run failed, once done
+}
+
+// FanOut is a task running on every host of a group at once, or the group being chosen
+type FanOut struct {
+	TaskId string
+	Group  string
+	Hosts  []fanOutHost
+	// Selected is the host whose output is shown
+	Selected int
+	Viewport viewport.Model
+	// Groups are the host groups to choose from, and GroupSelected the chosen one, until the
+	// run starts
+	Groups        []string
+	GroupSelected int
+
+	run *fanout.Run // Nil once every host has finished
+}
+
+// fanOutMsg carries an update of a host, or reports every host finished when closed
+type fanOutMsg struct {
+	run    *fanout.Run
+	update fanout.Update
+	closed bool
+}
+
+// waitFanOut returns the command waiting for the next update of run
+func waitFanOut(run *fanout.Run) tea.Cmd {
+	return func() tea.Msg {
+		u, ok := <-run.Updates()
+		return fanOutMsg{run: run, update: u, closed: !ok}
+	}
+}
+
+// openFanOut runs the selected task on a host group, asking which one when the config has
+// several. While a fan-out runs, its hosts are shown again instead.
+func (m *Model) openFanOut() tea.Cmd {
+	if m.FanOut.run != nil {
+		m.SetState(StateFanOut)
+		return nil
+	}
+	if m.Focused != ControlTable || len(m.Tasks) == 0 || m.Table.SelectedRow() == nil {
+		return nil
+	}
+	taskId := m.Tasks[m.Table.Cursor()].Id
+	groups := slices.Sorted(maps.Keys(m.Config.HostGroups))
+	switch {
+	case len(groups) == 0:
+		m.AppendErrorMsg("Add host_groups to the config to run " + taskId + " on them")
+		return nil
+	case isCIJob(m.Tasks[m.Table.Cursor()]):
+		m.AppendErrorMsg(taskId + " is a CI job, which only runs locally")
+		return nil
+	case !m.taskRequirements(taskId).Empty():
+		m.AppendErrorMsg(taskId + " needs input, which runs on host groups can't ask for")
+		return nil
+	case len(groups) == 1:
+		return m.startFanOut(taskId, groups[0])
+	}
+	m.FanOut = FanOut{TaskId: taskId, Groups: groups}
+	m.SetState(StateFanOut)
+	return nil
+}
+
+// startFanOut runs taskId on every host of group, once a dangerous task has been confirmed
+func (m *Model) startFanOut(taskId, group string) tea.Cmd {
+	if !m.confirmDangerous(taskId) {
+		m.DangerPrompt.Group = group
+		return nil
+	}
+	g := m.Config.HostGroups[group]
+	if len(g.Hosts) == 0 {
+		m.SetState(StateNormal)
+		m.AppendErrorMsg("Host group " + group + " has no hosts")
+		return nil
+	}
+	opts := m.ExecOptions(taskId)
+	opts.HostDir = g.Dir
+	// runs on hosts have no terminal to prompt in
+	opts.PasswordInput = false
+	run := fanout.Start(taskId, g.Hosts, opts)
+
+	hosts := make([]fanOutHost, len(g.Hosts))
+	for i, h := range g.Hosts {
+		hosts[i] = fanOutHost{Name: h}
+	}
+	m.FanOut = FanOut{
+		TaskId:   taskId,
+		Group:    group,
+		Hosts:    hosts,
+		Viewport: viewport.New(int(float64(m.Width)*0.9)-6, max(int(float64(m.Height)*0.9)-10, 3)),
+		run:      run,
+	}
+	m.SetState(StateFanOut)
+	m.AppendAppMsg(fmt.Sprintf("Running %s on host group %s: %s\n", taskId, group, strings.Join(g.Hosts, ", ")))
+	return waitFanOut(run)
+}
+
+// handleFanOutMsg records an update of a host of the fan-out, summing the runs up in the output
+// once every host has finished
+func (m Model) handleFanOutMsg(msg fanOutMsg) (Model, tea.Cmd) {
+	f := &m.FanOut
+	if msg.run != f.run || f.run == nil {
+		return m, nil
+	}
+	if msg.closed {
+		f.run = nil
+		m.summarizeFanOut()
+		return m, nil
+	}
+	u := msg.update
+	i := slices.IndexFunc(f.Hosts, func(h fanOutHost) bool { return h.Name == u.Host })
+	if i < 0 {
+		return m, waitFanOut(msg.run)
+	}
+	// copy the host before changing it, so earlier model values don't see the change
+	f.Hosts = slices.Clone(f.Hosts)
+	h := &f.Hosts[i]
+	if u.Finished {
+		h.Done, h.Err = true, u.Err
+	} else {
+		line := u.Line
+		if u.Stderr {
+			line = ErrorMsgStyle.Render(line)
+		}
+		h.Lines = append(h.Lines, line)
+		if len(h.Lines) > maxFanOutLines {
+			h.Lines = slices.Clone(h.Lines[len(h.Lines)-maxFanOutLines:])
+		}
+	}
+	if i == f.Selected {
+		f.showSelectedHost()
+	}
+	return m, waitFanOut(msg.run)
+}
+
+// summarizeFanOut reports how the runs of the hosts went in the output
+func (m *Model) summarizeFanOut() {
+	f := m.FanOut
+	var failed []string
+	for _, h := range f.Hosts {
+		if h.Err != nil {
+			failed = append(failed, h.Name)
+		}
+	}
+	if len(failed) == 0 {
+		m.AppendAppMsg(fmt.Sprintf("%s succeeded on all %d hosts of %s\n", f.TaskId, len(f.Hosts), f.Group))
+		return
+	}
+	m.AppendErrorMsg(fmt.Sprintf("%s failed on %d of %d hosts of %s: %s", f.TaskId, len(failed), len(f.Hosts), f.Group, strings.Join(failed, ", ")))
+}
+
+// showSelectedHost shows the output of the selected host, following it while at the bottom
+func (f *FanOut) showSelectedHost() {
+	follow := f.Viewport.AtBottom()
+	f.Viewport.SetContent(strings.Join(f.Hosts[f.Selected].Lines, "\n"))
+	if follow {
+		f.Viewport.GotoBottom()
+	}
+}
+
+// status returns the aggregate status of the hosts
+func (f FanOut) status() string {
+	var running, succeeded, failed int
+	for _, h := range f.Hosts {
+		switch {
+		case !h.Done:
+			running++
+		case h.Err == nil:
+			succeeded++
+		default:
+			failed++
+		}
+	}
+	return fmt.Sprintf("%d hosts: %d running, %d succeeded, %d failed", len(f.Hosts), running, succeeded, failed)
+}
+
+// handleFanOutKey handles key presses while choosing a host group or following its runs
+func (m Model) handleFanOutKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	f := &m.FanOut
+	action := m.resolveKey(msg)
+	if f.Hosts == nil {
+		switch action {
+		case ActionClose:
+			m.FanOut = FanOut{}
+			m.SetState(StateNormal)
+		case ActionUp:
+			f.GroupSelected = max(f.GroupSelected-1, 0)
+		case ActionDown:
+			f.GroupSelected = min(f.GroupSelected+1, len(f.Groups)-1)
+		case ActionConfirm:
+			return m, m.startFanOut(f.TaskId, f.Groups[f.GroupSelected])
+		}
+		return m, nil
+	}
+	switch action {
+	case ActionClose:
+		m.SetState(StateNormal)
+	case ActionPrevTab:
+		f.Selected = (f.Selected + len(f.Hosts) - 1) % len(f.Hosts)
+		f.showSelectedHost()
+		f.Viewport.GotoBottom()
+	case ActionNextTab:
+		f.Selected = (f.Selected + 1) % len(f.Hosts)
+		f.showSelectedHost()
+		f.Viewport.GotoBottom()
+	case ActionUp:
+		f.Viewport.ScrollUp(1)
+	case ActionDown:
+		f.Viewport.ScrollDown(1)
+	case ActionCancel:
+		if f.run != nil {
+			f.run.Cancel()
+			m.AppendErrorMsg("Cancelling " + f.TaskId + " on host group " + f.Group)
+		}
+	}
+	return m, nil
+}
+
+// RenderFanOut renders the host groups to choose from, or the runs of the hosts as tabs below
+// their aggregate status, with the output of the selected host
+func RenderFanOut(width, height int, f FanOut) string {
+	overlayWidth := int(float64(width) * 0.9)
+
+	if f.Hosts == nil {
+		content := TaskPickerTitleStyle.Render("Run "+f.TaskId+" on a host group") + "\n\n"
+		for i, g := range f.Groups {
+			line := g
+			if i == f.GroupSelected {
+				content += TaskPickerSelectedMatchStyle(overlayWidth).Render(line) + "\n"
+			} else {
+				content += TaskPickerMatchStyle(overlayWidth).Render(line) + "\n"
+			}
+		}
+		content += "\n" + HelpStyle.Render("enter to run, esc to cancel")
+		return placeOverlay(width, height, GeneralOverlayStyle(overlayWidth).Render(content))
+	}
+
+	content := TaskPickerTitleStyle.Render(f.TaskId+" on "+f.Group) + "\n"
+	content += HelpStyle.Render(f.status()) + "\n\n"
+	tabs := make([]string, len(f.Hosts))
+	for i, h := range f.Hosts {
+		icon, style := "…", HelpStyle
+		switch {
+		case h.Done && h.Err == nil:
+			icon, style = "✓", AppMsgStyle
+		case h.Done:
+			icon, style = "✗", ErrorMsgStyle
+		}
+		tab := icon + " " + h.Name
+		if i == f.Selected {
+			tab = "[" + tab + "]"
+		} else {
+			tab = " " + tab + " "
+		}
+		tabs[i] = style.Render(tab)
+	}
+	content += strings.Join(tabs, " ") + "\n\n"
+	if h := f.Hosts[f.Selected]; h.Err != nil {
+		content += ErrorMsgStyle.Render(h.Err.Error()) + "\n"
+	}
+	content += f.Viewport.View() + "\n\n"
+	content += HelpStyle.Render("←/→ switch host, ctrl+x cancel, esc hide (the runs go on, M shows them again)")
+
+	return placeOverlay(width, height, GeneralOverlayStyle(overlayWidth).Render(content))
+}
@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/task"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFanOutRunsOnEveryHostOfTheGroup(t *testing.T) {
+	cfg := config.Default()
+	cfg.Provider = task.ProviderDemo
+	cfg.HostGroups = map[string]config.HostGroupConfig{
+		"prod":    {Hosts: []string{"web1", "web2"}},
+		"staging": {Hosts: []string{"stage1"}},
+	}
+	m := NewModel(nil, cfg)
+	m.HandleWindowResize(120, 40)
+	m.Tasks = []task.Task{{Id: "lint"}}
+	m.AllTasks = m.Tasks
+	m.UpdateTaskTable()
+
+	updated, _ := m.handleNormalKey(runes("M"))
+	m = updated.(Model)
+	if m.State != StateFanOut || len(m.FanOut.Groups) != 2 {
+		t.Fatalf("Expected the host groups to be offered, got state %s", m.State)
+	}
+	updated, cmd := m.handleFanOutKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if cmd == nil || m.FanOut.Group != "prod" || len(m.FanOut.Hosts) != 2 {
+		t.Fatalf("Expected lint to run on prod, got %+v", m.FanOut)
+	}
+	for cmd != nil {
+		msg, ok := cmd().(fanOutMsg)
+		if !ok {
+			t.Fatal("Expected the updates of the hosts")
+		}
+		m, cmd = m.handleFanOutMsg(msg)
+	}
+
+	if status := m.FanOut.status(); status != "2 hosts: 0 running, 0 succeeded, 2 failed" {
+		t.Errorf("Expected both hosts to fail the lint, got %q", status)
+	}
+	if len(m.FanOut.Hosts[1].Lines) == 0 {
+		t.Error("Expected the output of each host")
+	}
+	updated, _ = m.handleFanOutKey(tea.KeyMsg{Type: tea.KeyRight})
+	if m := updated.(Model); m.FanOut.Selected != 1 || !strings.Contains(RenderFanOut(m.Width, m.Height, m.FanOut), "[✗ web2]") {
+		t.Error("Expected the second host to be shown")
+	}
+	if !strings.Contains(m.output.String(), "lint failed on 2 of 2 hosts of prod: web1, web2") {
+		t.Errorf("Expected the runs to be summed up, got:\n%s", m.output.String())
+	}
+}
@@ -0,0 +1,246 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Fold levels: each task run is a fold, and the steps task announces within it are folds too
+const (
+	foldRun = iota
+	foldSection
+)
+
+// sectionPattern matches the line task prints before running each command, e.g.
+// "task: [build] go build ./...", which starts a section of the run's output
+var sectionPattern = regexp.MustCompile(`^task: \[([^\]]+)\]`)
+
+// ansiPattern matches color escape sequences in task output
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+
+// outputFold is a block of output lines that can be collapsed to a single line
+type outputFold struct {
+	Title     string
+	Level     int
+	Start     int // Index of the first line in the fold
+	End       int // Index after the last line in the fold, or -1 while output is still added to it
+	Collapsed bool
+}
+
+// displayLine maps a line of the viewport content to an output line, or to the placeholder of
+// a collapsed fold
+type displayLine struct {
+	line int // Index of the output line, or -1
+	fold int // Index of the collapsed fold, or -1
+}
+
+// end returns the index after the last line of the fold
+func (m *Model) foldEnd(f outputFold) int {
+	if f.End < 0 {
+		return m.lineCount()
+	}
+	return f.End
+}
+
+// openFold starts a fold at the next output line, closing the open folds at its level or deeper
+func (m *Model) openFold(title string, level int) {
+	m.closeFolds(level)
+	m.Folds = append(m.Folds, outputFold{Title: title, Level: level, Start: m.lineCount(), End: -1})
+}
+
+// closeFolds ends the open folds at level or deeper at the last output line
+func (m *Model) closeFolds(level int) {
+	for i := range m.Folds {
+		if m.Folds[i].End < 0 && m.Folds[i].Level >= level {
+			m.Folds[i].End = m.lineCount()
+		}
+	}
+}
+
+// sectionTitle returns the task named by a line of output announcing a new step, or an empty
+// string for other output
+func sectionTitle(output string) string {
+	first, _, _ := strings.Cut(output, "\n")
+	if match := sectionPattern.FindStringSubmatch(ansiPattern.ReplaceAllString(first, "")); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// appendTaskOutput appends a line of task output, applying the highlight rules and recording
+// the problems it reports. Lines announcing a step start a section fold
+// after them, so they stay visible as the section's header.
+func (m *Model) appendTaskOutput(output string, style lipgloss.Style) {
+	m.scanProblems(output)
+	m.appendLines(output, func(line string) string {
+		return highlightLine(line, style, m.highlights)
+	})
+	if title := sectionTitle(output); title != "" && m.RunningTaskId != "" {
+		m.openFold(title, foldSection)
+	}
+}
+
+// clearOutput empties the output and its folds
+func (m *Model) clearOutput() {
+	m.output.reset("")
+	m.outputLines = nil
+	m.removeSpill()
+	m.Folds = nil
+	m.FoldCursor = -1
+	m.Problems = nil
+	m.displayLines = nil
+	m.Viewport.SetContent(m.output.String())
+}
+
+// anyCollapsed reports whether any fold is collapsed
+func (m *Model) anyCollapsed() bool {
+	for _, f := range m.Folds {
+		if f.Collapsed {
+			return true
+		}
+	}
+	return false
+}
+
+// renderOutput sets the viewport content from the output lines, replacing collapsed folds with
+// a placeholder line and marking the header of the selected fold
+func (m *Model) renderOutput() {
+	if !m.anyCollapsed() && m.FoldCursor < 0 {
+		m.displayLines = nil
+		m.Viewport.SetContent(m.output.String())
+		return
+	}
+
+	// the outermost collapsed fold starting at each line; folds partly spilled to disk start at
+	// the first line in memory
+	collapsedAt := map[int]int{}
+	for i, f := range m.Folds {
+		start := max(f.Start, m.spilledLines)
+		if !f.Collapsed || start >= m.foldEnd(f) {
+			continue
+		}
+		if j, ok := collapsedAt[start]; !ok || f.Level < m.Folds[j].Level {
+			collapsedAt[start] = i
+		}
+	}
+	header := -1
+	if m.FoldCursor >= 0 && !m.Folds[m.FoldCursor].Collapsed {
+		header = m.Folds[m.FoldCursor].Start - 1
+	}
+
+	// the content starts with an empty line, as each appended line is preceded by a newline, or
+	// with the notice of the lines spilled to disk
+	var b strings.Builder
+	b.WriteString(m.spillNotice())
+	m.displayLines = []displayLine{{line: -1, fold: -1}}
+	for i := m.spilledLines; i < m.lineCount(); {
+		b.WriteString("\n")
+		if j, ok := collapsedAt[i]; ok {
+			f := m.Folds[j]
+			end := m.foldEnd(f)
+			placeholder := fmt.Sprintf("▸ %s: %d lines folded", f.Title, end-max(f.Start, m.spilledLines))
+			if j == m.FoldCursor {
+				b.WriteString(TableSelectedTaskStyle.Render(placeholder))
+			} else {
+				b.WriteString(HelpStyle.Render(placeholder))
+			}
+			m.displayLines = append(m.displayLines, displayLine{line: -1, fold: j})
+			i = end
+			continue
+		}
+		if i == header {
+			b.WriteString(TableSelectedTaskStyle.Render("▾ "))
+		}
+		b.WriteString(m.outputLines[i-m.spilledLines])
+		m.displayLines = append(m.displayLines, displayLine{line: i, fold: -1})
+		i++
+	}
+	m.Viewport.SetContent(b.String())
+}
+
+// displayIndex returns the viewport line showing output line i, or its fold's placeholder
+func (m *Model) displayIndex(i int) int {
+	if m.displayLines == nil {
+		// lines spilled to disk are represented by the notice
+		return max(i-m.spilledLines+1, 0)
+	}
+	for d, dl := range m.displayLines {
+		if dl.line >= i || (dl.fold >= 0 && m.foldEnd(m.Folds[dl.fold]) > i) {
+			return d
+		}
+	}
+	return len(m.displayLines) - 1
+}
+
+// visibleFold reports whether fold i is shown, rather than hidden inside a collapsed fold
+func (m *Model) visibleFold(i int) bool {
+	f := m.Folds[i]
+	for j, outer := range m.Folds {
+		if j != i && outer.Collapsed && outer.Start <= f.Start && m.foldEnd(f) <= m.foldEnd(outer) && outer.Level < f.Level {
+			return false
+		}
+	}
+	return true
+}
+
+// toggleFold collapses the selected fold, or expands it if it is collapsed. Without a selection
+// the latest run is toggled.
+func (m *Model) toggleFold() {
+	if len(m.Folds) == 0 {
+		return
+	}
+	if m.FoldCursor < 0 {
+		for i := len(m.Folds) - 1; i >= 0; i-- {
+			if m.Folds[i].Level == foldRun {
+				m.FoldCursor = i
+				break
+			}
+		}
+		if m.FoldCursor < 0 {
+			return
+		}
+	}
+	m.Folds[m.FoldCursor].Collapsed = !m.Folds[m.FoldCursor].Collapsed
+	m.renderOutput()
+	m.scrollToFold(m.FoldCursor)
+}
+
+// toggleAllFolds expands every fold when any is collapsed, and otherwise collapses every
+// finished run
+func (m *Model) toggleAllFolds() {
+	collapse := !m.anyCollapsed()
+	for i := range m.Folds {
+		m.Folds[i].Collapsed = collapse && m.Folds[i].Level == foldRun && m.Folds[i].End >= 0
+	}
+	if m.FoldCursor >= 0 && !m.visibleFold(m.FoldCursor) {
+		m.FoldCursor = -1
+	}
+	m.renderOutput()
+}
+
+// scrollToFold scrolls the viewport so the fold's header, the line before it, is in view
+func (m *Model) scrollToFold(fold int) {
+	d := max(m.displayIndex(m.Folds[fold].Start)-1, 0)
+	if d < m.Viewport.YOffset || d >= m.Viewport.YOffset+m.Viewport.Height {
+		m.Viewport.SetYOffset(d)
+	}
+}
+
+// moveFoldCursor selects the next visible fold, or the previous one when dir is negative
+func (m *Model) moveFoldCursor(dir int) {
+	i := m.FoldCursor
+	if i < 0 && dir < 0 {
+		i = len(m.Folds)
+	}
+	for i += dir; i >= 0 && i < len(m.Folds); i += dir {
+		if m.visibleFold(i) {
+			m.FoldCursor = i
+			m.renderOutput()
+			m.scrollToFold(i)
+			return
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newFoldModel returns a model whose output holds two finished runs of build; the second run
+// has two steps announced by task
+func newFoldModel() Model {
+	m := newNavigationModel(1)
+	m.HandleWindowResize(120, 40)
+	m.Focused = ControlViewport
+
+	m.AppendAppMsg("Executing task: build")
+	m.RunningTaskId = "build"
+	m.openFold("build", foldRun)
+	for i := range 3 {
+		m.appendTaskOutput(fmt.Sprintf("first run %d", i), OutputStyle)
+	}
+	m.closeFolds(foldRun)
+
+	m.AppendAppMsg("Executing task: build")
+	m.openFold("build", foldRun)
+	m.appendTaskOutput("task: [generate] go generate ./...", ErrorMsgStyle)
+	m.appendTaskOutput("generated", OutputStyle)
+	m.appendTaskOutput("task: [compile] go build ./...", ErrorMsgStyle)
+	for i := range 5 {
+		m.appendTaskOutput(fmt.Sprintf("compiling %d", i), OutputStyle)
+	}
+	m.closeFolds(foldRun)
+	return m
+}
+
+func TestSectionTitle(t *testing.T) {
+	if got := sectionTitle("task: [docs:build] mkdocs build"); got != "docs:build" {
+		t.Errorf("Expected docs:build, got %q", got)
+	}
+	if got := sectionTitle("\x1b[32mtask: [lint] golangci-lint run\x1b[0m"); got != "lint" {
+		t.Errorf("Expected lint through color codes, got %q", got)
+	}
+	if got := sectionTitle("building task: [x]"); got != "" {
+		t.Errorf("Expected no section for other output, got %q", got)
+	}
+}
+
+func TestFoldsGroupRunsAndSections(t *testing.T) {
+	m := newFoldModel()
+	var runs, sections []string
+	for _, f := range m.Folds {
+		lines := fmt.Sprintf("%s:%d", f.Title, m.foldEnd(f)-f.Start)
+		if f.Level == foldRun {
+			runs = append(runs, lines)
+		} else {
+			sections = append(sections, lines)
+		}
+	}
+	if strings.Join(runs, ",") != "build:3,build:8" {
+		t.Errorf("Expected runs of 3 and 8 lines, got %v", runs)
+	}
+	if strings.Join(sections, ",") != "generate:2,compile:5" {
+		t.Errorf("Expected the generate and compile steps, got %v", sections)
+	}
+}
+
+func TestToggleAllFoldsCollapsesFinishedRuns(t *testing.T) {
+	m := newFoldModel()
+	m = pressKeys(m, runes("Z"))
+	content := m.Viewport.View()
+	if strings.Contains(content, "compiling") || strings.Count(content, "▸ build:") != 2 {
+		t.Errorf("Expected both runs to be folded, got:\n%s", content)
+	}
+
+	m = pressKeys(m, runes("Z"))
+	if strings.Contains(m.Viewport.View(), "▸") || m.anyCollapsed() {
+		t.Error("Expected every fold to be expanded again")
+	}
+}
+
+func TestToggleSelectedFold(t *testing.T) {
+	m := newFoldModel()
+
+	// without a selection the latest run is folded
+	m = pressKeys(m, runes("z"))
+	if !m.Folds[1].Collapsed || m.FoldCursor != 1 {
+		t.Fatalf("Expected the latest run to be folded and selected, got cursor %d", m.FoldCursor)
+	}
+	if got := m.Viewport.View(); !strings.Contains(got, "▸ build: 8 lines folded") || strings.Contains(got, "compiling") {
+		t.Errorf("Expected the latest run's lines to be replaced by a placeholder, got:\n%s", got)
+	}
+
+	// the steps inside the folded run are skipped when moving the selection
+	m = pressKeys(m, runes("]"))
+	if m.FoldCursor != 1 {
+		t.Errorf("Expected the folded run's steps to be skipped, got cursor %d", m.FoldCursor)
+	}
+	m = pressKeys(m, runes("z"), runes("]"), runes("]"))
+	if m.Folds[m.FoldCursor].Title != "compile" {
+		t.Fatalf("Expected the compile step to be selected, got %+v", m.Folds[m.FoldCursor])
+	}
+	m = pressKeys(m, runes("z"))
+	if got := m.Viewport.View(); !strings.Contains(got, "generated") || strings.Contains(got, "compiling") ||
+		!strings.Contains(got, "▸ compile: 5 lines folded") {
+		t.Errorf("Expected only the compile step to be folded, got:\n%s", got)
+	}
+
+	m = pressKeys(m, runes("["), runes("["), runes("["), runes("z"))
+	if m.FoldCursor != 0 || !m.Folds[0].Collapsed {
+		t.Errorf("Expected the first run to be selected and folded, got cursor %d", m.FoldCursor)
+	}
+	if got := m.Viewport.View(); strings.Contains(got, "first run") {
+		t.Errorf("Expected the first run to be folded, got:\n%s", got)
+	}
+}
+
+func TestClearOutputDropsFolds(t *testing.T) {
+	m := newFoldModel()
+	m = pressKeys(m, runes("Z"))
+	m.TasksLoading = false
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyCtrlL})
+	if len(m.Folds) != 0 || len(m.outputLines) != 0 || m.output.String() != "" {
+		t.Error("Expected the output and its folds to be cleared")
+	}
+}
@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Aj4x/tash/internal/git"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// gitRefreshInterval is how often the git status shown in the status bar is read again
+const gitRefreshInterval = 5 * time.Second
+
+// gitStatusMsg carries the git status read in the background; Status is nil when tash doesn't
+// run in a git repository
+type gitStatusMsg struct {
+	Status *git.Status
+}
+
+// refreshGitStatus reads the git status again in the background once gitRefreshInterval has
+// passed since it was last read
+func (m Model) refreshGitStatus(now time.Time) (Model, tea.Cmd) {
+	if m.Config.DisableGitStatus || m.Headless || now.Sub(m.gitChecked) < gitRefreshInterval {
+		return m, nil
+	}
+	m.gitChecked = now
+	dir := m.Config.WorkDir
+	return m, func() tea.Msg {
+		s, err := git.Read(context.Background(), dir)
+		if err != nil {
+			return gitStatusMsg{}
+		}
+		return gitStatusMsg{Status: &s}
+	}
+}
+
+// gitStatusText describes the branch and uncommitted changes for the status bar
+func gitStatusText(s git.Status) string {
+	switch {
+	case len(s.Changed) == 1:
+		return fmt.Sprintf("On %s, 1 changed file", s.Branch)
+	case len(s.Changed) > 0:
+		return fmt.Sprintf("On %s, %d changed files", s.Branch, len(s.Changed))
+	case s.Dirty:
+		return fmt.Sprintf("On %s, uncommitted changes", s.Branch)
+	default:
+		return fmt.Sprintf("On %s, clean", s.Branch)
+	}
+}
+
+// withChangedFiles adds the variable configured to pass the changed files of the git working
+// tree to vars. The files are read when the run starts, so they are current for each run.
+func withChangedFiles(vars []string, name, dir string) []string {
+	if name == "" {
+		return vars
+	}
+	s, err := git.Read(context.Background(), dir)
+	if err != nil {
+		slog.Debug("Unable to read the changed files", "error", err)
+		return vars
+	}
+	return append(slices.Clip(vars), name+"="+strings.Join(s.Changed, " "))
+}
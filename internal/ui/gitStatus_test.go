@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Aj4x/tash/internal/git"
+)
+
+func TestGitStatusIsShown(t *testing.T) {
+	m := newNavigationModel(1)
+	m.HandleWindowResize(120, 40)
+	m.Initialised = true
+
+	updated, _ := m.Update(gitStatusMsg{Status: &git.Status{Branch: "main", Dirty: true, Changed: []string{"a.go", "b.go"}}})
+	m = updated.(Model)
+	if view := m.View(); !strings.Contains(view, "On main, 2 changed files") {
+		t.Errorf("Expected the branch and changes in the status bar, got %q", view)
+	}
+
+	updated, _ = m.Update(gitStatusMsg{})
+	if view := updated.(Model).View(); strings.Contains(view, "On main") {
+		t.Error("Expected no git status outside a repository")
+	}
+}
+
+func TestGitStatusRefreshInterval(t *testing.T) {
+	m := newNavigationModel(1)
+	now := time.Now()
+	m, cmd := m.refreshGitStatus(now)
+	if cmd == nil {
+		t.Fatal("Expected the git status to be read")
+	}
+	if _, cmd := m.refreshGitStatus(now.Add(time.Second)); cmd != nil {
+		t.Error("Expected the git status not to be read again straight away")
+	}
+	if _, cmd := m.refreshGitStatus(now.Add(gitRefreshInterval)); cmd == nil {
+		t.Error("Expected the git status to be read again after the interval")
+	}
+
+	m.Config.DisableGitStatus = true
+	if _, cmd := m.refreshGitStatus(now.Add(time.Hour)); cmd != nil {
+		t.Error("Expected no git status when disabled")
+	}
+}
+
+func TestChangedFilesArePassedToRuns(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	if err := exec.Command("git", "init", "-q", dir).Run(); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.go", "b.go"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	vars := []string{"TARGET=staging"}
+	got := withChangedFiles(vars, "CHANGED_FILES", dir)
+	want := []string{"TARGET=staging", "CHANGED_FILES=a.go b.go"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got := withChangedFiles(vars, "", dir); !slices.Equal(got, vars) {
+		t.Errorf("Expected no variable when not configured, got %v", got)
+	}
+}
@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/msgbus"
+	"github.com/Aj4x/tash/internal/task"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/teatest"
+)
+
+// waitTimeout bounds how long a test waits for the UI to reach a state
+const waitTimeout = 5 * time.Second
+
+// testProgram runs a Model in a teatest program without a terminal, so tests exercise the same
+// command and message flow as the application. Every model produced by an update is recorded,
+// letting tests wait for the UI to reach a state; teatest's output only holds the changes of
+// each frame, so the viewport content is recorded as well.
+type testProgram struct {
+	t  *testing.T
+	tm *teatest.TestModel
+
+	mu     sync.Mutex
+	model  Model
+	output string // snapshot of the viewport content, which the model shares by pointer
+}
+
+// recorder wraps the model under test and reports each updated model to the testProgram
+type recorder struct {
+	tp    *testProgram
+	model Model
+}
+
+func (r recorder) Init() tea.Cmd {
+	return r.model.Init()
+}
+
+func (r recorder) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := r.model.Update(msg)
+	r.model = model.(Model)
+	r.tp.mu.Lock()
+	r.tp.model = r.model
+	r.tp.output = r.model.output.String()
+	r.tp.mu.Unlock()
+	return r, cmd
+}
+
+func (r recorder) View() string {
+	return r.model.View()
+}
+
+// useFakeTaskBinary puts testdata/task at the front of PATH in place of the real task binary
+func useFakeTaskBinary(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+	script, err := os.ReadFile(filepath.Join("testdata", "task"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "task"), script, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	// keep run history out of the user's data directory
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+}
+
+// startProgram runs a new Model against the fake task binary and waits for the task list to load
+func startProgram(t *testing.T, cfg config.Config) *testProgram {
+	t.Helper()
+	return startProgramWith(t, cfg, func(*Model) {})
+}
+
+// startProgramWith runs a new Model, adjusted by setup before it starts, and waits for the task
+// list to load
+func startProgramWith(t *testing.T, cfg config.Config, setup func(m *Model)) *testProgram {
+	t.Helper()
+	useFakeTaskBinary(t)
+	tp := &testProgram{t: t}
+	tp.model = NewModel(msgbus.NewMessageBus[task.Message](), cfg)
+	setup(&tp.model)
+	tp.tm = teatest.NewTestModel(t, recorder{tp: tp, model: tp.model}, teatest.WithInitialTermSize(120, 40))
+	t.Cleanup(tp.quit)
+	tp.WaitFor("the task list to load", func(m Model) bool {
+		return len(m.AllTasks) > 0 && !m.TasksLoading
+	})
+	return tp
+}
+
+// quit stops the program, cancelling any running task
+func (tp *testProgram) quit() {
+	if m := tp.Model(); m.CommandCancel != nil {
+		m.CommandCancel()
+	}
+	_ = tp.tm.Quit()
+	tp.tm.WaitFinished(tp.t, teatest.WithFinalTimeout(waitTimeout), teatest.WithTimeoutFn(func(tb testing.TB) {
+		tb.Error("Timed out waiting for the program to quit")
+	}))
+}
+
+// Model returns the model after the most recent update
+func (tp *testProgram) Model() Model {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	return tp.model
+}
+
+// Output returns the content of the output viewport after the most recent update
+func (tp *testProgram) Output() string {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	return tp.output
+}
+
+// Type sends each character of s as a key press
+func (tp *testProgram) Type(s string) {
+	tp.tm.Type(s)
+}
+
+// Press sends a special key such as tea.KeyEnter or tea.KeyCtrlX
+func (tp *testProgram) Press(key tea.KeyType) {
+	tp.tm.Send(tea.KeyMsg{Type: key})
+}
+
+// WaitFor polls the model until cond holds, failing the test after waitTimeout
+func (tp *testProgram) WaitFor(what string, cond func(Model) bool) Model {
+	tp.t.Helper()
+	deadline := time.Now().Add(waitTimeout)
+	for {
+		m := tp.Model()
+		if cond(m) {
+			return m
+		}
+		if time.Now().After(deadline) {
+			tp.t.Fatalf("Timed out waiting for %s; output:\n%s", what, tp.Output())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// WaitForOutput waits until the output viewport contains text, returning the whole output
+func (tp *testProgram) WaitForOutput(text string) string {
+	tp.t.Helper()
+	tp.WaitFor("output '"+text+"'", func(Model) bool {
+		return strings.Contains(tp.Output(), text)
+	})
+	return tp.Output()
+}
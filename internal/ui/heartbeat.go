@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/Aj4x/tash/internal/heartbeat"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// sendHeartbeat posts the daemon's status to the configured heartbeat URL in the background,
+// once the heartbeat interval has passed since the last one. Only the daemon sends heartbeats:
+// an interface stops when its user leaves, which monitoring has no reason to hear about.
+func (m Model) sendHeartbeat(now time.Time) (Model, tea.Cmd) {
+	interval := time.Duration(m.Config.HeartbeatInterval)
+	if interval <= 0 {
+		interval = heartbeat.DefaultInterval
+	}
+	if !m.Headless || m.Config.HeartbeatURL == "" || now.Sub(m.heartbeatSent) < interval {
+		return m, nil
+	}
+	m.heartbeatSent = now
+	url, status := m.Config.HeartbeatURL, m.heartbeatStatus(now)
+	return m, func() tea.Msg {
+		if err := heartbeat.Send(context.Background(), nil, url, status); err != nil {
+			slog.Warn("unable to send the heartbeat", "url", url, "error", err)
+		}
+		return nil
+	}
+}
+
+// heartbeatStatus returns what the daemon is running and has scheduled
+func (m Model) heartbeatStatus(now time.Time) heartbeat.Status {
+	status := heartbeat.Status{
+		Time:      now,
+		Pid:       os.Getpid(),
+		Dir:       m.catalogDir(),
+		Scheduled: make([]heartbeat.Scheduled, 0, len(m.Schedules)),
+		Watching:  m.stopWatchers != nil,
+		LastRun:   m.lastRun,
+	}
+	if m.RunningTaskId != "" {
+		status.Running = &heartbeat.Run{Task: m.RunningTaskId, Started: m.runStarted}
+	}
+	for _, e := range m.Schedules {
+		status.Scheduled = append(status.Scheduled, heartbeat.Scheduled{
+			Task:     e.TaskId,
+			Schedule: e.Schedule.String(),
+			NextRun:  e.NextRun,
+		})
+	}
+	return status
+}
@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/heartbeat"
+)
+
+func TestDaemonSendsHeartbeats(t *testing.T) {
+	beats := make(chan heartbeat.Status, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var status heartbeat.Status
+		json.NewDecoder(r.Body).Decode(&status)
+		beats <- status
+	}))
+	defer server.Close()
+
+	cfg := config.Default()
+	cfg.HeartbeatURL = server.URL
+	cfg.Schedules = []config.ScheduleConfig{{Task: "backup", Schedule: "1h"}}
+	m := NewModel(nil, cfg)
+	m.HandleWindowResize(120, 40)
+	now := time.Now()
+
+	if _, cmd := m.sendHeartbeat(now); cmd != nil {
+		t.Fatal("Expected only the daemon to send heartbeats")
+	}
+	m.Headless = true
+	m.RunningTaskId, m.runStarted = "build", now.Add(-time.Second)
+	m, cmd := m.sendHeartbeat(now)
+	if cmd == nil {
+		t.Fatal("Expected a heartbeat")
+	}
+	cmd()
+	status := <-beats
+	if status.Running == nil || status.Running.Task != "build" || len(status.Scheduled) != 1 || status.Scheduled[0].Task != "backup" {
+		t.Errorf("Expected the running and scheduled tasks to be reported, got %+v", status)
+	}
+
+	if _, cmd := m.sendHeartbeat(now.Add(30 * time.Second)); cmd != nil {
+		t.Error("Expected no heartbeat before the interval has passed")
+	}
+	if _, cmd := m.sendHeartbeat(now.Add(heartbeat.DefaultInterval)); cmd == nil {
+		t.Error("Expected a heartbeat once the interval has passed")
+	}
+}
@@ -7,8 +7,16 @@ func RenderHelpOverlay(m *Model) string {
 	// Calculate overlay dimensions
 	overlayWidth := int(float64(m.Width) * 0.7)
 
+	// Search box and the scope of the listed bindings
+	scope := "current context"
+	if m.HelpShowAll {
+		scope = "all contexts"
+	}
+	header := "Search: " + TaskPickerInputStyle(overlayWidth).Render(m.HelpSearch) + "\n" +
+		HelpStyle.Render("Showing "+scope+m.taskVersionInfo()) + "\n\n"
+
 	// Get the viewport content
-	helpContent := m.HelpViewport.View()
+	helpContent := header + m.HelpViewport.View()
 
 	// Add scroll indicators if needed
 	scrollIndicator := ""
@@ -20,18 +28,41 @@ func RenderHelpOverlay(m *Model) string {
 	}
 
 	if scrollIndicator != "" {
-		scrollStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Italic(true)
+		scrollStyle := lipgloss.NewStyle().Foreground(theme.Muted).Italic(true)
 		helpContent = helpContent + "\n" + scrollStyle.Render(scrollIndicator)
 	}
 
 	// Wrap the content in the overlay style
 	overlay := GeneralOverlayStyle(overlayWidth).Render(helpContent)
 
-	return lipgloss.Place(
-		m.Width,
-		m.Height,
-		lipgloss.Center,
-		lipgloss.Center,
-		overlay,
-	)
+	return placeOverlay(m.Width, m.Height, overlay)
+}
+
+// helpContexts returns the contexts whose bindings apply to the state the help overlay was opened from
+func (m Model) helpContexts() []Context {
+	contexts := []Context{ContextGlobal, ContextHelpOverlay}
+	if m.Focused == ControlViewport {
+		contexts = append(contexts, ContextViewport)
+	}
+	return contexts
+}
+
+// refreshHelpContent regenerates the help viewport for the current search and scope
+func (m *Model) refreshHelpContent() {
+	// Calculate overlay dimensions
+	overlayWidth := int(float64(m.Width) * 0.7)
+	overlayHeight := int(float64(m.Height) * 0.7)
+	contentWidth := overlayWidth - 6     // 6 = 2*2 padding + 2 border
+	viewportHeight := overlayHeight - 11 // Account for padding, borders and the search box
+
+	// Set viewport dimensions
+	m.HelpViewport.Width = contentWidth
+	m.HelpViewport.Height = max(viewportHeight, 1)
+
+	var contexts []Context
+	if !m.HelpShowAll {
+		contexts = m.helpContexts()
+	}
+	m.HelpViewport.SetContent(m.KeyBindings.GenerateHelpContent(overlayWidth, contexts, m.HelpSearch))
+	m.HelpViewport.GotoTop()
 }
@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateHelpContentFiltersByContext(t *testing.T) {
+	kb := DefaultKeyBindings()
+
+	content := kb.GenerateHelpContent(120, []Context{ContextGlobal}, "")
+	if strings.Contains(content, "Clear search/close") {
+		t.Error("Expected help overlay bindings to be hidden outside the help overlay context")
+	}
+	if !strings.Contains(content, "Execute task") {
+		t.Error("Expected global bindings to be listed")
+	}
+
+	content = kb.GenerateHelpContent(120, nil, "")
+	if !strings.Contains(content, "Clear search/close") {
+		t.Error("Expected all bindings to be listed when no contexts are given")
+	}
+}
+
+func TestGenerateHelpContentFiltersBySearch(t *testing.T) {
+	kb := DefaultKeyBindings()
+
+	content := kb.GenerateHelpContent(120, nil, "PAUSE")
+	if !strings.Contains(content, "Pause/resume task") {
+		t.Error("Expected a case-insensitive match on the description")
+	}
+	if strings.Contains(content, "Execute task") {
+		t.Error("Expected non-matching bindings to be filtered out")
+	}
+
+	content = kb.GenerateHelpContent(120, nil, "no such binding")
+	if !strings.Contains(content, "No matching key bindings") {
+		t.Error("Expected a notice when nothing matches")
+	}
+}
@@ -0,0 +1,106 @@
+package ui
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/redact"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// colorNames maps the color names accepted in highlight rules to ANSI colors
+var colorNames = map[string]string{
+	"black":   "0",
+	"red":     "9",
+	"green":   "10",
+	"yellow":  "11",
+	"blue":    "12",
+	"magenta": "13",
+	"cyan":    "14",
+	"white":   "15",
+	"gray":    "245",
+	"grey":    "245",
+	"orange":  "214",
+}
+
+// highlightRule is a compiled highlight from the configuration
+type highlightRule struct {
+	pattern *regexp.Regexp
+	style   lipgloss.Style
+	match   bool
+}
+
+// highlightColor returns the color named by s: a color name, an ANSI number or a hex color
+func highlightColor(s string) lipgloss.TerminalColor {
+	if s == "" {
+		return lipgloss.NoColor{}
+	}
+	if ansi, ok := colorNames[strings.ToLower(s)]; ok {
+		return lipgloss.Color(ansi)
+	}
+	return lipgloss.Color(s)
+}
+
+// compileRedactor compiles the configured redaction patterns, skipping invalid ones; the values
+// of secret variables are masked regardless
+func compileRedactor(patterns []string) *redact.Redactor {
+	r, err := redact.New(patterns)
+	if err != nil {
+		slog.Warn("ignoring redaction patterns", "error", err)
+	}
+	return r
+}
+
+// compileHighlights compiles the configured highlight rules, skipping invalid patterns
+func compileHighlights(highlights []config.HighlightConfig) []highlightRule {
+	var rules []highlightRule
+	for _, h := range highlights {
+		pattern, err := regexp.Compile(h.Pattern)
+		if err != nil || h.Pattern == "" {
+			slog.Warn("ignoring highlight rule", "pattern", h.Pattern, "error", err)
+			continue
+		}
+		style := lipgloss.NewStyle().
+			Foreground(highlightColor(h.Color)).
+			Background(highlightColor(h.Background)).
+			Bold(h.Bold)
+		rules = append(rules, highlightRule{pattern: pattern, style: style, match: h.Match})
+	}
+	return rules
+}
+
+// highlightLine renders a line of task output with style, applying the first highlight rule that
+// matches it. Plain mode leaves output unstyled for screen readers.
+func highlightLine(line string, style lipgloss.Style, rules []highlightRule) string {
+	if theme.Plain {
+		return style.Render(line)
+	}
+	for _, rule := range rules {
+		matches := rule.pattern.FindAllStringIndex(line, -1)
+		if matches == nil {
+			continue
+		}
+		if !rule.match {
+			return rule.style.Inherit(style).Render(line)
+		}
+		var b strings.Builder
+		last := 0
+		for _, m := range matches {
+			if m[0] == m[1] {
+				continue
+			}
+			if m[0] > last {
+				b.WriteString(style.Render(line[last:m[0]]))
+			}
+			b.WriteString(rule.style.Inherit(style).Render(line[m[0]:m[1]]))
+			last = m[1]
+		}
+		if last < len(line) {
+			b.WriteString(style.Render(line[last:]))
+		}
+		return b.String()
+	}
+	return style.Render(line)
+}
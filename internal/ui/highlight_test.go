@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestCompileHighlightsSkipsInvalidPatterns(t *testing.T) {
+	rules := compileHighlights([]config.HighlightConfig{
+		{Pattern: "WARN", Color: "yellow"},
+		{Pattern: "(unclosed", Color: "red"},
+		{Pattern: "", Color: "red"},
+		{Pattern: "FAIL", Color: "#ff0000", Bold: true, Match: true},
+	})
+	if len(rules) != 2 {
+		t.Fatalf("Expected 2 valid rules, got %d", len(rules))
+	}
+	if got := rules[0].style.GetForeground(); got != lipgloss.Color("11") {
+		t.Errorf("Expected yellow to map to ANSI 11, got %v", got)
+	}
+	if got := rules[1].style.GetForeground(); got != lipgloss.Color("#ff0000") || !rules[1].style.GetBold() || !rules[1].match {
+		t.Errorf("Expected a bold #ff0000 match rule, got %+v", rules[1])
+	}
+}
+
+func TestHighlightLine(t *testing.T) {
+	warn := lipgloss.NewStyle().Foreground(lipgloss.Color("11"))
+	fail := lipgloss.NewStyle().Bold(true)
+	rules := []highlightRule{
+		{pattern: regexp.MustCompile("WARN"), style: warn},
+		{pattern: regexp.MustCompile("FAIL"), style: fail, match: true},
+	}
+	base := OutputStyle
+
+	if got, want := highlightLine("ok", base, rules), base.Render("ok"); got != want {
+		t.Errorf("Expected unmatched lines in the base style, got %q", got)
+	}
+	if got, want := highlightLine("WARN: FAIL", base, rules), warn.Inherit(base).Render("WARN: FAIL"); got != want {
+		t.Errorf("Expected the first matching rule to style the whole line, got %q", got)
+	}
+	want := base.Render("2 ") + fail.Inherit(base).Render("FAIL") + base.Render(", 1 ") + fail.Inherit(base).Render("FAIL")
+	if got := highlightLine("2 FAIL, 1 FAIL", base, rules); got != want {
+		t.Errorf("Expected only the matches to be styled, got %q", got)
+	}
+}
+
+func TestTaskOutputIsHighlighted(t *testing.T) {
+	cfg := config.Default()
+	cfg.Highlights = []config.HighlightConfig{{Pattern: "WARN", Color: "yellow"}}
+	m := NewModel(nil, cfg)
+	m.HandleWindowResize(120, 40)
+
+	m.appendTaskOutput("WARN deprecated flag", OutputStyle)
+	want := m.highlights[0].style.Inherit(OutputStyle).Render("WARN deprecated flag")
+	if got := m.outputLines[len(m.outputLines)-1]; got != want {
+		t.Errorf("Expected the highlighted line %q, got %q", want, got)
+	}
+}
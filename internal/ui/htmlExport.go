@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Aj4x/tash/internal/ansihtml"
+)
+
+// exportFold returns the run fold whose output is exported: the run containing the selected
+// fold, or the latest run. ok is false when nothing has run.
+func (m *Model) exportFold() (fold outputFold, ok bool) {
+	if m.FoldCursor >= 0 {
+		selected := m.Folds[m.FoldCursor]
+		for i := m.FoldCursor; i >= 0; i-- {
+			if f := m.Folds[i]; f.Level == foldRun && f.Start <= selected.Start {
+				return f, true
+			}
+		}
+	}
+	for i := len(m.Folds) - 1; i >= 0; i-- {
+		if m.Folds[i].Level == foldRun {
+			return m.Folds[i], true
+		}
+	}
+	return outputFold{}, false
+}
+
+// exportedRun returns the task and the output lines, as rendered, of the run to export, with the
+// header announcing it; lines spilled to disk have lost their colors and are left out. ok is
+// false, and the reason told, when there is nothing to export.
+func (m *Model) exportedRun() (taskId string, lines []string, ok bool) {
+	fold, ok := m.exportFold()
+	if !ok {
+		m.AppendErrorMsg("No run to export yet")
+		return "", nil, false
+	}
+	start, end := max(fold.Start-1, m.spilledLines, 0), m.foldEnd(fold)
+	if start >= end {
+		m.AppendErrorMsg("The output of " + fold.Title + " was moved to disk, press L to view it")
+		return "", nil, false
+	}
+	lines = m.outputLines[start-m.spilledLines : end-m.spilledLines]
+	if start > max(fold.Start-1, 0) {
+		lines = append([]string{fmt.Sprintf("… %d earlier lines of the run dropped", start-fold.Start+1)}, lines...)
+	}
+	return fold.Title, lines, true
+}
+
+// exportRunHTML writes the output of a run, with its colors, to a standalone HTML file to share
+// with people who don't use a terminal
+func (m *Model) exportRunHTML() {
+	taskId, lines, ok := m.exportedRun()
+	if !ok {
+		return
+	}
+
+	title := fmt.Sprintf("tash: %s, exported %s", taskId, m.TimeFormat.Format(time.Now(), "2006-01-02 15:04"))
+	f, err := os.CreateTemp("", "tash-"+strings.NewReplacer(":", "-", "/", "-").Replace(taskId)+"-*.html")
+	if err == nil {
+		_, err = f.WriteString(ansihtml.Document(title, lines))
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		m.AppendErrorMsg("Unable to export the output of " + taskId + ": " + err.Error())
+		return
+	}
+	m.AppendAppMsg(fmt.Sprintf("Exported the output of %s to %s\n", taskId, f.Name()))
+}
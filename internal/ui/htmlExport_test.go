@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportRunHTMLWritesTheSelectedRun(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("TMPDIR", dir)
+	m := newFoldModel()
+	section := len(m.Folds) - 1
+	m.AppendAppMsg("Executing task: lint")
+	m.openFold("lint", foldRun)
+	m.appendTaskOutput("\x1b[31m<FAIL>\x1b[0m", OutputStyle)
+	m.closeFolds(foldRun)
+
+	export := func(pattern string) string {
+		t.Helper()
+		m.exportRunHTML()
+		files, _ := filepath.Glob(filepath.Join(dir, pattern))
+		if len(files) != 1 || !strings.Contains(m.output.String(), "Exported the output of") {
+			t.Fatalf("Expected the export to be reported, got %v and:\n%s", files, m.output.String())
+		}
+		content, err := os.ReadFile(files[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(content)
+	}
+
+	// a section selected in the second run of build exports that whole run
+	m.FoldCursor = section
+	html := export("tash-build-*.html")
+	for _, want := range []string{"<title>tash: build, exported", "Executing task: build", "generated", "compiling 4"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected %q in the export, got:\n%s", want, html)
+		}
+	}
+	if strings.Contains(html, "first run") || strings.Contains(html, "FAIL") {
+		t.Errorf("Expected only the selected run to be exported, got:\n%s", html)
+	}
+
+	// without a selection the latest run is exported, with its colors
+	m.FoldCursor = -1
+	if html := export("tash-lint-*.html"); !strings.Contains(html, `<span style="color:#cd3131">&lt;FAIL&gt;</span>`) {
+		t.Errorf("Expected the colored output of lint, got:\n%s", html)
+	}
+
+	m.Folds = nil
+	m.exportRunHTML()
+	if !strings.Contains(m.output.String(), "No run to export yet") {
+		t.Error("Expected the lack of a run to be reported")
+	}
+}
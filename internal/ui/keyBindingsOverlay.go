@@ -0,0 +1,162 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Aj4x/tash/internal/config"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// keyBindingRows returns every binding in display order
+func (kb KeyBindings) keyBindingRows() []KeyBinding {
+	var rows []KeyBinding
+	for _, section := range kb.Sections {
+		rows = append(rows, section.KeyBindings...)
+	}
+	return rows
+}
+
+// Overrides returns the keys of the actions that differ from the defaults, keyed by action
+func (kb KeyBindings) Overrides() map[string][]string {
+	defaults := DefaultKeyBindings()
+	overrides := map[string][]string{}
+	for _, binding := range kb.keyBindingRows() {
+		if !binding.Rebindable() {
+			continue
+		}
+		if def, ok := defaults.Binding(binding.Action); ok && def.Key == binding.Key {
+			continue
+		}
+		overrides[string(binding.Action)] = binding.Keys()
+	}
+	return overrides
+}
+
+// openKeyBindings shows the key bindings overlay
+func (m *Model) openKeyBindings() {
+	m.KeyBindingSelected = 0
+	m.KeyBindingCapture = false
+	m.SetState(StateKeyBindingsOverlay)
+}
+
+// selectedKeyBinding returns the binding selected in the key bindings overlay
+func (m Model) selectedKeyBinding() KeyBinding {
+	return m.KeyBindings.keyBindingRows()[m.KeyBindingSelected]
+}
+
+// handleKeyBindingsOverlayKey handles key presses in the key bindings overlay. While capturing,
+// the next key pressed becomes the selected action's binding.
+func (m Model) handleKeyBindingsOverlayKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	selected := m.selectedKeyBinding()
+
+	if m.KeyBindingCapture {
+		m.KeyBindingCapture = false
+		if IsKeyMatch(msg, "esc") {
+			return m, nil
+		}
+		m.rebind(selected.Action, []string{keyName(msg)})
+		return m, nil
+	}
+
+	switch action := m.resolveKey(msg); {
+	case action == ActionClose || m.KeyBindings.Matches(msg, ActionKeyBindings):
+		m.SetState(StateNormal)
+	case action == ActionUp:
+		if m.KeyBindingSelected > 0 {
+			m.KeyBindingSelected--
+		}
+	case action == ActionDown:
+		if m.KeyBindingSelected < len(m.KeyBindings.keyBindingRows())-1 {
+			m.KeyBindingSelected++
+		}
+	case action == ActionConfirm:
+		if selected.Rebindable() {
+			m.KeyBindingCapture = true
+		}
+	case action == ActionReset:
+		if def, ok := DefaultKeyBindings().Binding(selected.Action); ok && selected.Rebindable() {
+			m.rebind(selected.Action, def.Keys())
+		}
+	}
+	return m, nil
+}
+
+// rebind binds action to keys and saves the overrides to the user config file
+func (m *Model) rebind(action Action, keys []string) {
+	if err := m.KeyBindings.Rebind(action, keys); err != nil {
+		m.AppendErrorMsg("Unable to rebind key: " + err.Error())
+		return
+	}
+	m.Config.KeyBindings = m.KeyBindings.Overrides()
+	m.AppendAppMsg(fmt.Sprintf("Bound %s to %s\n", action, strings.Join(keys, "/")))
+	binding, _ := m.KeyBindings.Binding(action)
+	for _, c := range m.KeyBindings.ConflictsFor(action) {
+		m.AppendErrorMsg(fmt.Sprintf("Key '%s' is also bound to %s", c.Key, describeOthers(c.Bindings, binding)))
+	}
+
+	if m.ConfigPath == "" {
+		return
+	}
+	if err := config.SaveKeyBindings(m.ConfigPath, m.Config.KeyBindings); err != nil {
+		m.AppendErrorMsg("Unable to save key bindings: " + err.Error())
+	}
+}
+
+// RenderKeyBindingsOverlay renders every key binding, marking fixed bindings and conflicts
+func RenderKeyBindingsOverlay(width, height int, kb KeyBindings, selectedIndex int, capturing bool) string {
+	overlayWidth := int(float64(width) * 0.7)
+	rows := kb.keyBindingRows()
+	conflicts := kb.Conflicts()
+
+	content := TaskPickerTitleStyle.Render("Key Bindings") + "\n\n"
+	if len(conflicts) > 0 {
+		content += ErrorMsgStyle.Render(fmt.Sprintf("%d conflicting keys", len(conflicts))) + "\n"
+	}
+	if capturing {
+		content += AppMsgStyle.Render(fmt.Sprintf("Press the new key for '%s' (esc to cancel)",
+			rows[selectedIndex].Description)) + "\n"
+	}
+	content += "\n"
+
+	// Show a window of rows around the selection
+	visible := max(int(float64(height)*0.7)-10, 3)
+	start := max(0, min(selectedIndex-visible/2, len(rows)-visible))
+	end := min(len(rows), start+visible)
+
+	for i := start; i < end; i++ {
+		b := rows[i]
+		line := fmt.Sprintf("%-14s %s", b.Key, b.Description)
+		if !b.Rebindable() {
+			line += " (fixed)"
+		}
+		for _, c := range conflicts {
+			for _, cb := range c.Bindings {
+				if cb.Key == b.Key && cb.Description == b.Description {
+					line += fmt.Sprintf(" ⚠ '%s' also bound to %s", c.Key, describeOthers(c.Bindings, b))
+				}
+			}
+		}
+		if i == selectedIndex {
+			content += TaskPickerSelectedMatchStyle(overlayWidth).Render(line) + "\n"
+		} else {
+			content += TaskPickerMatchStyle(overlayWidth).Render(line) + "\n"
+		}
+	}
+
+	overlay := GeneralOverlayStyle(overlayWidth).Render(content)
+
+	return placeOverlay(width, height, overlay)
+}
+
+// describeOthers lists the descriptions of bindings other than b
+func describeOthers(bindings []KeyBinding, b KeyBinding) string {
+	var names []string
+	for _, other := range bindings {
+		if other.Key == b.Key && other.Description == b.Description {
+			continue
+		}
+		names = append(names, "'"+other.Description+"'")
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestKeyBindingsOverlayRebindsAndSaves(t *testing.T) {
+	m := NewModel(nil, config.Default())
+	m.ConfigPath = filepath.Join(t.TempDir(), "config.json")
+	m.openKeyBindings()
+
+	// Move the selection onto the quit binding
+	for m.selectedKeyBinding().Action != ActionQuit {
+		updated, _ := m.handleKeyBindingsOverlayKey(tea.KeyMsg{Type: tea.KeyDown})
+		m = updated.(Model)
+	}
+
+	updated, _ := m.handleKeyBindingsOverlayKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if !m.KeyBindingCapture {
+		t.Fatal("Expected enter to start capturing a key")
+	}
+	updated, _ = m.handleKeyBindingsOverlayKey(tea.KeyMsg{Type: tea.KeyCtrlQ})
+	m = updated.(Model)
+
+	if !m.KeyBindings.Matches(tea.KeyMsg{Type: tea.KeyCtrlQ}, ActionQuit) {
+		t.Error("Expected quit to be rebound to ctrl+q")
+	}
+	cfg, err := config.Load(m.ConfigPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if keys := cfg.KeyBindings["quit"]; len(keys) != 1 || keys[0] != "ctrl+q" {
+		t.Errorf("Expected the rebinding to be saved, got %v", cfg.KeyBindings)
+	}
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// leaderToken stands for the configured leader key in a binding, e.g. "<leader> r"
+const leaderToken = "<leader>"
+
+// keyName returns the name of the pressed key as used in key bindings
+func keyName(msg tea.KeyMsg) string {
+	if msg.Type == tea.KeySpace {
+		return "space"
+	}
+	return msg.String()
+}
+
+// hasPrefix reports whether seq starts with prefix
+func hasPrefix(seq, prefix []string) bool {
+	if len(prefix) > len(seq) {
+		return false
+	}
+	for i := range prefix {
+		if seq[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// chord splits a binding key into its key sequence, substituting the leader key
+func chord(key, leader string) []string {
+	seq := strings.Fields(key)
+	for i, k := range seq {
+		if k == leaderToken {
+			seq[i] = leader
+		}
+	}
+	return seq
+}
+
+// Resolve maps a key press to the action bound to it in one of contexts. pending holds the
+// keys pressed so far of an unfinished chord. When key continues a chord but does not complete
+// it, Resolve returns ActionNone and the keys to keep pending; otherwise pending is cleared.
+// A key that breaks off a chord is resolved on its own.
+func (kb KeyBindings) Resolve(contexts []Context, pending []string, key, leader string) (Action, []string) {
+	seq := append(append([]string{}, pending...), key)
+	prefix := false
+	for _, section := range kb.Sections {
+		for _, binding := range section.KeyBindings {
+			if binding.Action == ActionNone || !binding.ActiveIn(contexts) {
+				continue
+			}
+			for _, k := range binding.Keys() {
+				bound := chord(k, leader)
+				if len(bound) == 0 || (leader == "" && strings.Contains(k, leaderToken)) {
+					continue
+				}
+				if len(bound) == len(seq) && hasPrefix(bound, seq) {
+					return binding.Action, nil
+				}
+				if hasPrefix(bound, seq) {
+					prefix = true
+				}
+			}
+		}
+	}
+	if prefix {
+		return ActionNone, seq
+	}
+	if len(pending) > 0 {
+		return kb.Resolve(contexts, nil, key, leader)
+	}
+	return ActionNone, nil
+}
+
+// resolveKey resolves msg to an action in the contexts of the current state, tracking the
+// keys of a chord in progress
+func (m *Model) resolveKey(msg tea.KeyMsg) Action {
+	contexts := m.State.HintContexts()
+	if m.State == StateNormal && m.Focused == ControlViewport {
+		contexts = append(contexts, ContextViewport)
+	}
+	action, pending := m.KeyBindings.Resolve(contexts, m.PendingKeys, keyName(msg), m.Config.Leader)
+	m.PendingKeys = pending
+	return action
+}
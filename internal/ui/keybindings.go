@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"runtime/debug"
 	"strings"
@@ -25,18 +26,156 @@ const (
 	ContextHelpOverlay    Context = "helpOverlay"
 	ContextDetailsOverlay Context = "detailsOverlay"
 	ContextViewport       Context = "viewport"
+	ContextSchedule       Context = "schedule"
+	ContextDiffOverlay    Context = "diffOverlay"
+	ContextSchedulePrompt Context = "schedulePrompt"
+	ContextStatsOverlay   Context = "statsOverlay"
+	ContextKeyBindings    Context = "keyBindings"
+	ContextCommandLine    Context = "commandLine"
+	ContextRunPrompt      Context = "runPrompt"
+	ContextRunOptions     Context = "runOptions"
+	ContextProblems       Context = "problems"
+	ContextFailureSummary Context = "failureSummary"
+	ContextVarsOverlay    Context = "varsOverlay"
+	ContextMetadataEditor Context = "metadataEditor"
+	ContextClonePrompt    Context = "clonePrompt"
+	ContextDiagnostics    Context = "diagnostics"
+	ContextRemotePrompt   Context = "remotePrompt"
+	ContextTrustPrompt    Context = "trustPrompt"
+	ContextNamespaces     Context = "namespaces"
+	ContextTour           Context = "tour"
+	ContextAbout          Context = "about"
+	ContextDangerPrompt   Context = "dangerPrompt"
+	ContextPasswordPrompt Context = "passwordPrompt"
+	ContextFanOut         Context = "fanOut"
+	ContextCompose        Context = "compose"
+	ContextPreflight      Context = "preflight"
+	ContextSnippets       Context = "snippets"
+	ContextMakeImport     Context = "makeImport"
 )
 
-// KeyBinding represents a single key binding with its key, description, and context
+// Action identifies what a key binding does. Key presses are resolved to actions and the
+// state handlers dispatch on them. Actions of rebindable bindings are the keys used for key
+// binding overrides in the config file.
+type Action string
+
+// Action constants
+const (
+	ActionNone           Action = ""
+	ActionHelp           Action = "help"
+	ActionQuit           Action = "quit"
+	ActionSwitchFocus    Action = "switch_focus"
+	ActionUp             Action = "up"
+	ActionDown           Action = "down"
+	ActionPageUp         Action = "page_up"
+	ActionPageDown       Action = "page_down"
+	ActionTop            Action = "top"
+	ActionBottom         Action = "bottom"
+	ActionHalfPageUp     Action = "half_page_up"
+	ActionHalfPageDown   Action = "half_page_down"
+	ActionExecute        Action = "execute"
+	ActionDetails        Action = "details"
+	ActionRefresh        Action = "refresh"
+	ActionCancel         Action = "cancel"
+	ActionPause          Action = "pause"
+	ActionRepeat         Action = "repeat"
+	ActionClearOutput    Action = "clear_output"
+	ActionRepairDisplay  Action = "repair_display"
+	ActionOpenPicker     Action = "open_picker"
+	ActionExecuteBatch   Action = "execute_batch"
+	ActionClearBatch     Action = "clear_batch"
+	ActionDiff           Action = "diff"
+	ActionStats          Action = "stats"
+	ActionSchedule       Action = "schedule"
+	ActionSchedules      Action = "schedules"
+	ActionToggleWatchers Action = "toggle_watchers"
+	ActionKeyBindings    Action = "key_bindings"
+	ActionToggleHidden   Action = "toggle_hidden"
+	ActionRunExternal    Action = "run_external"
+	ActionShell          Action = "shell"
+	ActionCommandLine    Action = "command_line"
+	ActionTaskShell      Action = "task_shell"
+	ActionTaskVars       Action = "task_vars"
+	ActionEditMetadata   Action = "edit_metadata"
+	ActionCloneTask      Action = "clone_task"
+	ActionDiagnostics    Action = "diagnostics"
+	ActionRemoteTaskfile Action = "remote_taskfile"
+	ActionGroupIncludes  Action = "group_includes"
+	ActionGroupLabels    Action = "group_labels"
+	ActionLabelFilter    Action = "label_filter"
+	ActionNamespaces     Action = "namespaces"
+	ActionRunNamespace   Action = "run_namespace"
+	ActionEditTaskfile   Action = "edit_taskfile"
+	ActionVerbosity      Action = "verbosity"
+	ActionRunOptions     Action = "run_options"
+	ActionToggleFold     Action = "toggle_fold"
+	ActionToggleAllFolds Action = "toggle_all_folds"
+	ActionNextFold       Action = "next_fold"
+	ActionPrevFold       Action = "prev_fold"
+	ActionProblems       Action = "problems"
+	ActionFailureSummary Action = "failure_summary"
+	ActionFullOutput     Action = "full_output"
+	ActionExportHTML     Action = "export_html"
+	ActionShareOutput    Action = "share_output"
+	ActionAbout          Action = "about"
+	ActionFanOut         Action = "fan_out"
+	ActionCompose        Action = "compose"
+	ActionPreflight      Action = "preflight"
+	ActionSnippets       Action = "snippets"
+	ActionMakeImport     Action = "make_import"
+	ActionReducedMotion  Action = "reduced_motion"
+	ActionRenderStats    Action = "render_stats"
+
+	// Actions of the overlays, which can't be rebound
+	ActionClose        Action = "close"
+	ActionConfirm      Action = "confirm"
+	ActionAutocomplete Action = "autocomplete"
+	ActionShowAll      Action = "show_all"
+	ActionToggleView   Action = "toggle_view"
+	ActionRemove       Action = "remove"
+	ActionReset        Action = "reset"
+	ActionToggle       Action = "toggle"
+	ActionOpenFile     Action = "open_file"
+	ActionHide         Action = "hide"
+	ActionPin          Action = "pin"
+	ActionTour         Action = "tour"
+	ActionCopy         Action = "copy"
+	ActionPrevTab      Action = "prev_tab"
+	ActionNextTab      Action = "next_tab"
+	ActionStart        Action = "start"
+	ActionStop         Action = "stop"
+	ActionRestart      Action = "restart"
+	ActionLogs         Action = "logs"
+)
+
+// Condition is a set of flags describing UI conditions a key binding depends on
+type Condition uint
+
+// Condition flags
+const (
+	CondTaskRunning   Condition = 1 << iota // A task is currently running
+	CondTasksSelected                       // Tasks have been added to the execution list
+)
+
+// Has reports whether all flags in other are set
+func (c Condition) Has(other Condition) bool {
+	return c&other == other
+}
+
+// KeyBinding represents a single key binding with its key, description, and context.
+// Bindings active in the main view can be rebound; those of the overlays are fixed.
 type KeyBinding struct {
-	Key         string    // The key or key combination (e.g., "ctrl+c", "enter")
+	Action      Action    // What the binding does, empty for fixed bindings
+	Key         string    // The key or key combination (e.g., "ctrl+c", "enter", "g g"); alternatives are separated by "/"
 	Description string    // Description of what the key does
 	Contexts    []Context // Contexts where this key binding is active
+	Requires    Condition // Conditions that must hold for the binding to be hinted
 }
 
 // KeyBindings contains all key bindings used in the application
 type KeyBindings struct {
 	Sections []KeyBindingSection // Sections of key bindings
+	ReadOnly bool                // Bindings of mutating actions are grayed out in the help and left out of the hints
 }
 
 // DefaultKeyBindings returns the default key bindings for the application
@@ -46,56 +185,428 @@ func DefaultKeyBindings() KeyBindings {
 			{
 				Name: "Help",
 				KeyBindings: []KeyBinding{
-					{Key: "?", Description: "Show/hide help", Contexts: []Context{ContextGlobal}},
+					{Action: ActionHelp, Key: "?", Description: "Show/hide help", Contexts: []Context{ContextGlobal}},
+					{Action: ActionAbout, Key: "B", Description: "About tash, details for a bug report", Contexts: []Context{ContextGlobal}},
+					{Action: ActionCopy, Key: "c", Description: "Copy the details", Contexts: []Context{ContextAbout}},
+					{Action: ActionClose, Key: "esc", Description: "Close", Contexts: []Context{ContextAbout}},
 				},
 			},
 			{
 				Name: "Navigation",
 				KeyBindings: []KeyBinding{
-					{Key: "q", Description: "Quit", Contexts: []Context{ContextGlobal}},
-					{Key: "tab", Description: "Switch focus", Contexts: []Context{ContextGlobal}},
-					{Key: "↑/↓/j/k", Description: "Navigate", Contexts: []Context{ContextGlobal}},
-					{Key: "pgup/pgdn", Description: "Page up/down", Contexts: []Context{ContextHelpOverlay, ContextViewport}},
-					{Key: "home/end", Description: "Top/bottom", Contexts: []Context{ContextHelpOverlay, ContextViewport}},
+					{Action: ActionQuit, Key: "q", Description: "Quit", Contexts: []Context{ContextGlobal}},
+					{Action: ActionSwitchFocus, Key: "tab", Description: "Switch focus", Contexts: []Context{ContextGlobal}},
+					{Action: ActionUp, Key: "↑/k", Description: "Move up", Contexts: []Context{ContextGlobal}},
+					{Action: ActionDown, Key: "↓/j", Description: "Move down", Contexts: []Context{ContextGlobal}},
+					{Action: ActionPageUp, Key: "pgup", Description: "Page up", Contexts: []Context{ContextGlobal, ContextHelpOverlay}},
+					{Action: ActionPageDown, Key: "pgdn", Description: "Page down", Contexts: []Context{ContextGlobal, ContextHelpOverlay}},
+					{Action: ActionHalfPageUp, Key: "ctrl+u", Description: "Half page up", Contexts: []Context{ContextGlobal}},
+					{Action: ActionHalfPageDown, Key: "ctrl+d", Description: "Half page down", Contexts: []Context{ContextGlobal}},
+					{Action: ActionTop, Key: "home/g g", Description: "Top (or line N with a count)", Contexts: []Context{ContextGlobal}},
+					{Action: ActionBottom, Key: "end/G", Description: "Bottom (or line N with a count)", Contexts: []Context{ContextGlobal}},
+					{Key: "1-9", Description: "Count prefix, e.g. 5j", Contexts: []Context{ContextGlobal}},
+				},
+			},
+			{
+				Name: "Help Overlay",
+				KeyBindings: []KeyBinding{
+					{Key: "type", Description: "Search bindings", Contexts: []Context{ContextHelpOverlay}},
+					{Action: ActionUp, Key: "↑", Description: "Scroll up", Contexts: []Context{ContextHelpOverlay}},
+					{Action: ActionDown, Key: "↓", Description: "Scroll down", Contexts: []Context{ContextHelpOverlay}},
+					{Action: ActionTop, Key: "home", Description: "Top", Contexts: []Context{ContextHelpOverlay}},
+					{Action: ActionBottom, Key: "end", Description: "Bottom", Contexts: []Context{ContextHelpOverlay}},
+					{Action: ActionShowAll, Key: "ctrl+a", Description: "Show all contexts", Contexts: []Context{ContextHelpOverlay}},
+					{Action: ActionTour, Key: "ctrl+t", Description: "Take the tour", Contexts: []Context{ContextHelpOverlay}},
+					{Action: ActionClose, Key: "esc", Description: "Clear search/close", Contexts: []Context{ContextHelpOverlay}},
+				},
+			},
+			{
+				Name: "Tour",
+				KeyBindings: []KeyBinding{
+					{Action: ActionDown, Key: "→/enter", Description: "Next step", Contexts: []Context{ContextTour}},
+					{Action: ActionUp, Key: "←", Description: "Previous step", Contexts: []Context{ContextTour}},
+					{Action: ActionClose, Key: "esc", Description: "Skip the tour", Contexts: []Context{ContextTour}},
 				},
 			},
 			{
 				Name: "Task Management",
 				KeyBindings: []KeyBinding{
-					{Key: "enter/e", Description: "Execute task", Contexts: []Context{ContextGlobal}},
-					{Key: "i", Description: "Task details", Contexts: []Context{ContextGlobal}},
-					{Key: "ctrl+r", Description: "Refresh tasks", Contexts: []Context{ContextGlobal}},
-					{Key: "ctrl+x", Description: "Cancel task", Contexts: []Context{ContextGlobal}},
-					{Key: "ctrl+l", Description: "Clear output", Contexts: []Context{ContextGlobal}},
+					{Action: ActionExecute, Key: "enter/e", Description: "Execute task", Contexts: []Context{ContextGlobal}},
+					{Action: ActionDetails, Key: "i", Description: "Task details", Contexts: []Context{ContextGlobal}},
+					{Action: ActionRefresh, Key: "ctrl+r", Description: "Refresh tasks", Contexts: []Context{ContextGlobal}},
+					{Action: ActionGroupIncludes, Key: "I", Description: "Group tasks by Taskfile", Contexts: []Context{ContextGlobal}},
+					{Action: ActionGroupLabels, Key: "#", Description: "Group tasks by label", Contexts: []Context{ContextGlobal}},
+					{Action: ActionLabelFilter, Key: "l", Description: "List the tasks of the next label", Contexts: []Context{ContextGlobal}},
+					{Action: ActionNamespaces, Key: "N", Description: "Hide or pin namespaces", Contexts: []Context{ContextGlobal}},
+					{Action: ActionUp, Key: "↑/k", Description: "Previous namespace", Contexts: []Context{ContextNamespaces}},
+					{Action: ActionDown, Key: "↓/j", Description: "Next namespace", Contexts: []Context{ContextNamespaces}},
+					{Action: ActionHide, Key: "h", Description: "Hide/show the namespace", Contexts: []Context{ContextNamespaces}},
+					{Action: ActionPin, Key: "p", Description: "Pin/unpin the namespace to the top", Contexts: []Context{ContextNamespaces}},
+					{Action: ActionConfirm, Key: "enter", Description: "Run every task in the namespace", Contexts: []Context{ContextNamespaces}},
+					{Action: ActionClose, Key: "esc", Description: "Close", Contexts: []Context{ContextNamespaces}},
+					{Action: ActionEditTaskfile, Key: "E", Description: "Open the task's Taskfile in $EDITOR", Contexts: []Context{ContextGlobal}},
+					{Action: ActionCloneTask, Key: "C", Description: "Clone the task under a new name", Contexts: []Context{ContextGlobal}},
+					{Action: ActionConfirm, Key: "enter", Description: "Clone and open in $EDITOR", Contexts: []Context{ContextClonePrompt}},
+					{Action: ActionClose, Key: "esc", Description: "Cancel", Contexts: []Context{ContextClonePrompt}},
+					{Action: ActionRemoteTaskfile, Key: "R", Description: "Use a remote Taskfile", Contexts: []Context{ContextGlobal}},
+					{Action: ActionConfirm, Key: "enter", Description: "List the Taskfile's tasks", Contexts: []Context{ContextRemotePrompt}},
+					{Action: ActionClose, Key: "esc", Description: "Cancel", Contexts: []Context{ContextRemotePrompt}},
+					{Action: ActionConfirm, Key: "y/enter", Description: "Trust the remote Taskfile", Contexts: []Context{ContextTrustPrompt}},
+					{Action: ActionClose, Key: "n/esc", Description: "Don't trust it", Contexts: []Context{ContextTrustPrompt}},
+					{Action: ActionVerbosity, Key: "V", Description: "Cycle verbose/silent runs", Contexts: []Context{ContextGlobal}},
+					{Action: ActionReducedMotion, Key: "Q", Description: "Toggle reduced motion", Contexts: []Context{ContextGlobal}},
+					{Action: ActionRenderStats, Key: "ctrl+f", Description: "Toggle the frame rate overlay", Contexts: []Context{ContextGlobal}},
+					{Action: ActionToggleHidden, Key: "H", Description: "Show/hide hidden tasks", Contexts: []Context{ContextGlobal}},
+					{Action: ActionCancel, Key: "ctrl+x", Description: "Cancel task", Contexts: []Context{ContextGlobal}, Requires: CondTaskRunning},
+					{Action: ActionPause, Key: "p", Description: "Pause/resume task", Contexts: []Context{ContextGlobal}, Requires: CondTaskRunning},
+					{Action: ActionRepeat, Key: "u", Description: "Run until failure", Contexts: []Context{ContextGlobal}},
+					{Action: ActionRunExternal, Key: "o", Description: "Run in new terminal", Contexts: []Context{ContextGlobal}},
+					{Action: ActionShell, Key: "ctrl+z", Description: "Open a shell", Contexts: []Context{ContextGlobal}},
+					{Action: ActionCommandLine, Key: ":", Description: "Run a shell command", Contexts: []Context{ContextGlobal}},
+					{Action: ActionConfirm, Key: "enter", Description: "Run command", Contexts: []Context{ContextCommandLine}},
+					{Action: ActionClose, Key: "esc", Description: "Cancel", Contexts: []Context{ContextCommandLine}},
+					{Action: ActionRunOptions, Key: "O", Description: "Run with options", Contexts: []Context{ContextGlobal}},
+					{Action: ActionUp, Key: "↑/shift+tab", Description: "Previous option", Contexts: []Context{ContextRunOptions}},
+					{Action: ActionDown, Key: "↓/tab", Description: "Next option", Contexts: []Context{ContextRunOptions}},
+					{Action: ActionToggle, Key: "space", Description: "Toggle option", Contexts: []Context{ContextRunOptions}},
+					{Action: ActionConfirm, Key: "enter", Description: "Run", Contexts: []Context{ContextRunOptions}},
+					{Action: ActionClose, Key: "esc", Description: "Cancel", Contexts: []Context{ContextRunOptions}},
+					{Action: ActionConfirm, Key: "enter", Description: "Accept", Contexts: []Context{ContextRunPrompt}},
+					{Action: ActionUp, Key: "↑", Description: "Previous value", Contexts: []Context{ContextRunPrompt}},
+					{Action: ActionDown, Key: "↓", Description: "Next value", Contexts: []Context{ContextRunPrompt}},
+					{Action: ActionClose, Key: "esc", Description: "Cancel the run", Contexts: []Context{ContextRunPrompt}},
+					{Action: ActionConfirm, Key: "enter", Description: "Run once the task id is typed", Contexts: []Context{ContextDangerPrompt}},
+					{Action: ActionClose, Key: "esc", Description: "Cancel the run", Contexts: []Context{ContextDangerPrompt}},
+					{Action: ActionConfirm, Key: "enter", Description: "Pass the password to the task", Contexts: []Context{ContextPasswordPrompt}},
+					{Action: ActionClose, Key: "esc", Description: "Decline the prompt", Contexts: []Context{ContextPasswordPrompt}},
+					{Action: ActionToggleFold, Key: "z", Description: "Fold/unfold the selected output", Contexts: []Context{ContextViewport}},
+					{Action: ActionToggleAllFolds, Key: "Z", Description: "Fold/unfold all runs", Contexts: []Context{ContextViewport}},
+					{Action: ActionNextFold, Key: "]", Description: "Select the next fold", Contexts: []Context{ContextViewport}},
+					{Action: ActionPrevFold, Key: "[", Description: "Select the previous fold", Contexts: []Context{ContextViewport}},
+					{Action: ActionProblems, Key: "P", Description: "List problems in the output", Contexts: []Context{ContextGlobal}},
+					{Action: ActionUp, Key: "↑/k", Description: "Previous problem", Contexts: []Context{ContextProblems}},
+					{Action: ActionDown, Key: "↓/j", Description: "Next problem", Contexts: []Context{ContextProblems}},
+					{Action: ActionConfirm, Key: "enter", Description: "Jump to output", Contexts: []Context{ContextProblems}},
+					{Action: ActionOpenFile, Key: "e", Description: "Open in $EDITOR", Contexts: []Context{ContextProblems}},
+					{Action: ActionClose, Key: "esc", Description: "Close", Contexts: []Context{ContextProblems}},
+					{Action: ActionDiagnostics, Key: "!", Description: "Taskfile diagnostics", Contexts: []Context{ContextGlobal}},
+					{Action: ActionUp, Key: "↑/k", Description: "Previous diagnostic", Contexts: []Context{ContextDiagnostics}},
+					{Action: ActionDown, Key: "↓/j", Description: "Next diagnostic", Contexts: []Context{ContextDiagnostics}},
+					{Action: ActionOpenFile, Key: "e/enter", Description: "Open in $EDITOR", Contexts: []Context{ContextDiagnostics}},
+					{Action: ActionClose, Key: "esc", Description: "Close", Contexts: []Context{ContextDiagnostics}},
+					{Action: ActionFailureSummary, Key: "F", Description: "Summary of the last failed run", Contexts: []Context{ContextGlobal}},
+					{Action: ActionFullOutput, Key: "L", Description: "Full output in the pager", Contexts: []Context{ContextGlobal}},
+					{Action: ActionExportHTML, Key: "X", Description: "Export the selected run as HTML", Contexts: []Context{ContextGlobal}},
+					{Action: ActionShareOutput, Key: "Y", Description: "Share the selected run via the paste service", Contexts: []Context{ContextGlobal}},
+					{Action: ActionUp, Key: "↑/k", Description: "Previous problem", Contexts: []Context{ContextFailureSummary}},
+					{Action: ActionDown, Key: "↓/j", Description: "Next problem", Contexts: []Context{ContextFailureSummary}},
+					{Action: ActionConfirm, Key: "enter", Description: "Jump to output", Contexts: []Context{ContextFailureSummary}},
+					{Action: ActionOpenFile, Key: "e", Description: "Open in $EDITOR", Contexts: []Context{ContextFailureSummary}},
+					{Action: ActionClose, Key: "esc", Description: "Close", Contexts: []Context{ContextFailureSummary}},
+					{Action: ActionClearOutput, Key: "ctrl+l", Description: "Clear output", Contexts: []Context{ContextGlobal}},
+					{Action: ActionRepairDisplay, Key: "ctrl+g", Description: "Repair display", Contexts: []Context{ContextGlobal}},
 				},
 			},
 			{
 				Name: "Task Picker",
 				KeyBindings: []KeyBinding{
-					{Key: "/", Description: "Open picker", Contexts: []Context{ContextGlobal}},
-					{Key: "tab", Description: "Autocomplete", Contexts: []Context{ContextTaskPicker}},
-					{Key: "enter", Description: "Select task", Contexts: []Context{ContextTaskPicker}},
-					{Key: "esc", Description: "Close picker", Contexts: []Context{ContextTaskPicker}},
-					{Key: "↑/↓", Description: "Navigate matches", Contexts: []Context{ContextTaskPicker}},
+					{Action: ActionOpenPicker, Key: "/", Description: "Open picker", Contexts: []Context{ContextGlobal}},
+					{Action: ActionAutocomplete, Key: "tab", Description: "Autocomplete", Contexts: []Context{ContextTaskPicker}},
+					{Action: ActionConfirm, Key: "enter", Description: "Select task", Contexts: []Context{ContextTaskPicker}},
+					{Action: ActionClose, Key: "esc", Description: "Close picker", Contexts: []Context{ContextTaskPicker}},
+					{Action: ActionUp, Key: "↑", Description: "Previous match", Contexts: []Context{ContextTaskPicker}},
+					{Action: ActionDown, Key: "↓", Description: "Next match", Contexts: []Context{ContextTaskPicker}},
 				},
 			},
 			{
 				Name: "Batch Execution",
 				KeyBindings: []KeyBinding{
-					{Key: "ctrl+e", Description: "Execute tasks", Contexts: []Context{ContextGlobal}},
-					{Key: "ctrl+d", Description: "Clear tasks", Contexts: []Context{ContextGlobal}},
+					{Action: ActionExecuteBatch, Key: "ctrl+e", Description: "Execute tasks", Contexts: []Context{ContextGlobal}, Requires: CondTasksSelected},
+					{Action: ActionFanOut, Key: "M", Description: "Run the task on a host group over ssh", Contexts: []Context{ContextGlobal}},
+					{Action: ActionUp, Key: "↑/k", Description: "Previous group/scroll up", Contexts: []Context{ContextFanOut}},
+					{Action: ActionDown, Key: "↓/j", Description: "Next group/scroll down", Contexts: []Context{ContextFanOut}},
+					{Action: ActionConfirm, Key: "enter", Description: "Run on the selected group", Contexts: []Context{ContextFanOut}},
+					{Action: ActionPrevTab, Key: "←/h", Description: "Previous host", Contexts: []Context{ContextFanOut}},
+					{Action: ActionNextTab, Key: "→/l/tab", Description: "Next host", Contexts: []Context{ContextFanOut}},
+					{Action: ActionCancel, Key: "ctrl+x", Description: "Cancel the runs", Contexts: []Context{ContextFanOut}},
+					{Action: ActionClose, Key: "esc", Description: "Hide, leaving the runs going", Contexts: []Context{ContextFanOut}},
+					{Action: ActionRunNamespace, Key: "A", Description: "Run every task in the selected task's namespace", Contexts: []Context{ContextGlobal}},
+					{Action: ActionClearBatch, Key: "ctrl+k", Description: "Clear tasks", Contexts: []Context{ContextGlobal}, Requires: CondTasksSelected},
+				},
+			},
+			{
+				Name: "Run Comparison",
+				KeyBindings: []KeyBinding{
+					{Action: ActionDiff, Key: "D", Description: "Compare last two runs", Contexts: []Context{ContextGlobal}},
+					{Action: ActionStats, Key: "T", Description: "Duration statistics", Contexts: []Context{ContextGlobal}},
+					{Action: ActionClose, Key: "esc", Description: "Close statistics", Contexts: []Context{ContextStatsOverlay}},
+					{Action: ActionToggleView, Key: "v", Description: "Unified/side-by-side", Contexts: []Context{ContextDiffOverlay}},
+					{Action: ActionUp, Key: "↑/k", Description: "Scroll up", Contexts: []Context{ContextDiffOverlay}},
+					{Action: ActionDown, Key: "↓/j", Description: "Scroll down", Contexts: []Context{ContextDiffOverlay}},
+					{Action: ActionPageUp, Key: "pgup", Description: "Page up", Contexts: []Context{ContextDiffOverlay}},
+					{Action: ActionPageDown, Key: "pgdn", Description: "Page down", Contexts: []Context{ContextDiffOverlay}},
+					{Action: ActionTop, Key: "home", Description: "Top", Contexts: []Context{ContextDiffOverlay}},
+					{Action: ActionBottom, Key: "end", Description: "Bottom", Contexts: []Context{ContextDiffOverlay}},
+					{Action: ActionClose, Key: "esc", Description: "Close comparison", Contexts: []Context{ContextDiffOverlay}},
+				},
+			},
+			{
+				Name: "Scheduling",
+				KeyBindings: []KeyBinding{
+					{Action: ActionSchedule, Key: "s", Description: "Schedule task", Contexts: []Context{ContextGlobal}},
+					{Action: ActionSchedules, Key: "S", Description: "Scheduled tasks", Contexts: []Context{ContextGlobal}},
+					{Action: ActionToggleWatchers, Key: "w", Description: "Toggle file watchers", Contexts: []Context{ContextGlobal}},
+					{Action: ActionConfirm, Key: "enter", Description: "Add schedule", Contexts: []Context{ContextSchedulePrompt}},
+					{Action: ActionClose, Key: "esc", Description: "Cancel", Contexts: []Context{ContextSchedulePrompt}},
+					{Action: ActionUp, Key: "↑/k", Description: "Previous schedule", Contexts: []Context{ContextSchedule}},
+					{Action: ActionDown, Key: "↓/j", Description: "Next schedule", Contexts: []Context{ContextSchedule}},
+					{Action: ActionRemove, Key: "d/delete", Description: "Remove schedule", Contexts: []Context{ContextSchedule}},
+					{Action: ActionClose, Key: "esc", Description: "Close schedules", Contexts: []Context{ContextSchedule}},
+				},
+			},
+			{
+				Name: "Compose Services",
+				KeyBindings: []KeyBinding{
+					{Action: ActionCompose, Key: "U", Description: "Services of the compose file", Contexts: []Context{ContextGlobal}},
+					{Action: ActionUp, Key: "↑/k", Description: "Previous service", Contexts: []Context{ContextCompose}},
+					{Action: ActionDown, Key: "↓/j", Description: "Next service", Contexts: []Context{ContextCompose}},
+					{Action: ActionStart, Key: "u", Description: "Start the service (up)", Contexts: []Context{ContextCompose}},
+					{Action: ActionStop, Key: "d", Description: "Remove the service (down)", Contexts: []Context{ContextCompose}},
+					{Action: ActionRestart, Key: "r", Description: "Restart the service", Contexts: []Context{ContextCompose}},
+					{Action: ActionLogs, Key: "l/enter", Description: "Follow the service's logs", Contexts: []Context{ContextCompose}},
+					{Action: ActionRefresh, Key: "ctrl+r", Description: "Refresh the services", Contexts: []Context{ContextCompose}},
+					{Action: ActionClose, Key: "esc", Description: "Close services", Contexts: []Context{ContextCompose}},
+				},
+			},
+			{
+				Name: "Pre-flight Checks",
+				KeyBindings: []KeyBinding{
+					{Action: ActionPreflight, Key: "J", Description: "Pre-flight checklist", Contexts: []Context{ContextGlobal}},
+					{Action: ActionRefresh, Key: "ctrl+r", Description: "Check again", Contexts: []Context{ContextPreflight}},
+					{Action: ActionClose, Key: "esc", Description: "Close checklist", Contexts: []Context{ContextPreflight}},
+				},
+			},
+			{
+				Name: "Adding Tasks",
+				KeyBindings: []KeyBinding{
+					{Action: ActionSnippets, Key: "ctrl+n", Description: "Add a snippet to the Taskfile", Contexts: []Context{ContextGlobal}},
+					{Action: ActionUp, Key: "↑", Description: "Previous snippet", Contexts: []Context{ContextSnippets}},
+					{Action: ActionDown, Key: "↓", Description: "Next snippet", Contexts: []Context{ContextSnippets}},
+					{Action: ActionConfirm, Key: "enter", Description: "Add and open in $EDITOR", Contexts: []Context{ContextSnippets}},
+					{Action: ActionClose, Key: "esc", Description: "Cancel", Contexts: []Context{ContextSnippets}},
+					{Action: ActionMakeImport, Key: "ctrl+o", Description: "Import the Makefile's targets", Contexts: []Context{ContextGlobal}},
+					{Action: ActionUp, Key: "↑/k", Description: "Scroll up", Contexts: []Context{ContextMakeImport}},
+					{Action: ActionDown, Key: "↓/j", Description: "Scroll down", Contexts: []Context{ContextMakeImport}},
+					{Action: ActionConfirm, Key: "enter", Description: "Add the tasks and open in $EDITOR", Contexts: []Context{ContextMakeImport}},
+					{Action: ActionClose, Key: "esc", Description: "Cancel", Contexts: []Context{ContextMakeImport}},
+				},
+			},
+			{
+				Name: "Key Bindings",
+				KeyBindings: []KeyBinding{
+					{Action: ActionKeyBindings, Key: "K", Description: "Edit key bindings", Contexts: []Context{ContextGlobal}},
+					{Action: ActionUp, Key: "↑/k", Description: "Previous binding", Contexts: []Context{ContextKeyBindings}},
+					{Action: ActionDown, Key: "↓/j", Description: "Next binding", Contexts: []Context{ContextKeyBindings}},
+					{Action: ActionConfirm, Key: "enter", Description: "Rebind", Contexts: []Context{ContextKeyBindings}},
+					{Action: ActionReset, Key: "r", Description: "Reset to default", Contexts: []Context{ContextKeyBindings}},
+					{Action: ActionClose, Key: "esc", Description: "Close", Contexts: []Context{ContextKeyBindings}},
 				},
 			},
 			{
 				Name: "Details Overlay",
 				KeyBindings: []KeyBinding{
-					{Key: "esc/i", Description: "Close details", Contexts: []Context{ContextDetailsOverlay}},
+					{Action: ActionClose, Key: "esc", Description: "Close details", Contexts: []Context{ContextDetailsOverlay}},
+					{Action: ActionTaskShell, Key: "s", Description: "Open a shell with the task's environment", Contexts: []Context{ContextDetailsOverlay}},
+					{Action: ActionTaskVars, Key: "v", Description: "Inspect the task's variables", Contexts: []Context{ContextDetailsOverlay}},
+					{Action: ActionEditMetadata, Key: "m", Description: "Edit desc, summary and aliases", Contexts: []Context{ContextDetailsOverlay}},
+					{Action: ActionUp, Key: "↑/shift+tab", Description: "Previous field", Contexts: []Context{ContextMetadataEditor}},
+					{Action: ActionDown, Key: "↓/tab", Description: "Next field", Contexts: []Context{ContextMetadataEditor}},
+					{Key: "alt+enter", Description: "New line in the summary", Contexts: []Context{ContextMetadataEditor}},
+					{Action: ActionConfirm, Key: "enter", Description: "Save to the Taskfile", Contexts: []Context{ContextMetadataEditor}},
+					{Action: ActionClose, Key: "esc", Description: "Cancel", Contexts: []Context{ContextMetadataEditor}},
+					{Action: ActionUp, Key: "↑/k", Description: "Scroll up", Contexts: []Context{ContextVarsOverlay}},
+					{Action: ActionDown, Key: "↓/j", Description: "Scroll down", Contexts: []Context{ContextVarsOverlay}},
+					{Action: ActionPageUp, Key: "pgup", Description: "Page up", Contexts: []Context{ContextVarsOverlay}},
+					{Action: ActionPageDown, Key: "pgdn", Description: "Page down", Contexts: []Context{ContextVarsOverlay}},
+					{Action: ActionClose, Key: "esc", Description: "Back to details", Contexts: []Context{ContextVarsOverlay}},
 				},
 			},
 		},
 	}
 }
 
+// Keys returns the individual keys of the binding, as reported by tea.KeyMsg.String()
+func (b KeyBinding) Keys() []string {
+	if b.Key == "/" {
+		return []string{"/"}
+	}
+	var keys []string
+	for _, k := range strings.Split(b.Key, "/") {
+		switch k {
+		case "":
+			continue
+		case "↑":
+			k = "up"
+		case "↓":
+			k = "down"
+		case "←":
+			k = "left"
+		case "→":
+			k = "right"
+		case "pgdn":
+			k = "pgdown"
+		}
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Rebindable reports whether the binding belongs to the main view and can be rebound
+func (b KeyBinding) Rebindable() bool {
+	return b.Action != ActionNone && b.ActiveIn([]Context{ContextGlobal, ContextViewport})
+}
+
+// Binding returns the rebindable binding for action
+func (kb KeyBindings) Binding(action Action) (KeyBinding, bool) {
+	for _, section := range kb.Sections {
+		for _, binding := range section.KeyBindings {
+			if binding.Action == action && binding.Rebindable() {
+				return binding, true
+			}
+		}
+	}
+	return KeyBinding{}, false
+}
+
+// Matches reports whether msg is one of the single keys bound to action in the main view
+func (kb KeyBindings) Matches(msg tea.KeyMsg, action Action) bool {
+	binding, ok := kb.Binding(action)
+	if !ok {
+		return false
+	}
+	for _, k := range binding.Keys() {
+		if keyName(msg) == k {
+			return true
+		}
+	}
+	return false
+}
+
+// Rebind binds action to keys, replacing its current keys
+func (kb KeyBindings) Rebind(action Action, keys []string) error {
+	if len(keys) == 0 {
+		return fmt.Errorf("no keys given for action %q", action)
+	}
+	for i := range kb.Sections {
+		for j := range kb.Sections[i].KeyBindings {
+			if b := kb.Sections[i].KeyBindings[j]; b.Action == action && b.Rebindable() {
+				kb.Sections[i].KeyBindings[j].Key = strings.Join(keys, "/")
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("unknown action %q", action)
+}
+
+// ApplyOverrides rebinds the actions named in overrides, as loaded from the config file.
+// It returns an error listing the overrides that could not be applied.
+func (kb KeyBindings) ApplyOverrides(overrides map[string][]string) error {
+	var errs []error
+	for action, keys := range overrides {
+		if err := kb.Rebind(Action(action), keys); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Conflict describes a key sequence bound to more than one binding in the same context. A
+// binding also conflicts with a chord it is a prefix of, since the chord could never complete.
+type Conflict struct {
+	Key      string
+	Bindings []KeyBinding
+}
+
+// Conflicts returns the key sequences that are bound more than once within a context
+func (kb KeyBindings) Conflicts() []Conflict {
+	type bound struct {
+		ctx     Context
+		seq     []string
+		binding KeyBinding
+	}
+	var all []bound
+	for _, section := range kb.Sections {
+		for _, binding := range section.KeyBindings {
+			if binding.Action == ActionNone {
+				continue
+			}
+			contexts := map[Context]bool{}
+			for _, ctx := range binding.Contexts {
+				if ctx == ContextViewport {
+					// viewport bindings are handled alongside the global ones
+					ctx = ContextGlobal
+				}
+				if contexts[ctx] {
+					continue
+				}
+				contexts[ctx] = true
+				for _, k := range binding.Keys() {
+					all = append(all, bound{ctx, strings.Fields(k), binding})
+				}
+			}
+		}
+	}
+
+	var conflicts []Conflict
+	index := map[string]int{}
+	for i, a := range all {
+		for _, b := range all[i+1:] {
+			if a.ctx != b.ctx || !(hasPrefix(a.seq, b.seq) || hasPrefix(b.seq, a.seq)) {
+				continue
+			}
+			key := strings.Join(a.seq, " ")
+			if len(b.seq) < len(a.seq) {
+				key = strings.Join(b.seq, " ")
+			}
+			id := string(a.ctx) + "\x00" + key
+			n, ok := index[id]
+			if !ok {
+				n = len(conflicts)
+				index[id] = n
+				conflicts = append(conflicts, Conflict{Key: key, Bindings: []KeyBinding{a.binding}})
+			}
+			if !containsBinding(conflicts[n].Bindings, b.binding) {
+				conflicts[n].Bindings = append(conflicts[n].Bindings, b.binding)
+			}
+		}
+	}
+	return conflicts
+}
+
+// containsBinding reports whether bindings includes b
+func containsBinding(bindings []KeyBinding, b KeyBinding) bool {
+	for _, other := range bindings {
+		if other.Action == b.Action && other.Key == b.Key && other.Description == b.Description {
+			return true
+		}
+	}
+	return false
+}
+
+// ConflictsFor returns the conflicts involving the binding for action
+func (kb KeyBindings) ConflictsFor(action Action) []Conflict {
+	var conflicts []Conflict
+	for _, c := range kb.Conflicts() {
+		for _, b := range c.Bindings {
+			if b.Action == action {
+				conflicts = append(conflicts, c)
+				break
+			}
+		}
+	}
+	return conflicts
+}
+
 // GetKeyBindingsForContext returns all key bindings for a specific context
 func (kb KeyBindings) GetKeyBindingsForContext(context Context) []KeyBinding {
 	var bindings []KeyBinding
@@ -132,42 +643,50 @@ func (kb KeyBindings) GetKeyBindingsForDetailsOverlay() []KeyBinding {
 	return kb.GetKeyBindingsForContext(ContextDetailsOverlay)
 }
 
-// RenderHelpView renders the help text at the bottom of the screen using the key bindings
-func (kb KeyBindings) RenderHelpView(taskRunning bool, showTaskPicker bool, hasSelectedTasks bool) string {
-	// If task picker is shown, show picker-specific help
-	if showTaskPicker {
-		bindings := kb.GetKeyBindingsForTaskPicker()
-		var help []string
-		for _, binding := range bindings {
-			help = append(help, fmt.Sprintf("%s: %s", binding.Key, binding.Description))
+// RenderHints renders the one-line hint strip for the given contexts. Bindings whose
+// conditions are not all met by active are left out, and the strip is cut to width.
+func (kb KeyBindings) RenderHints(contexts []Context, active Condition, width int) string {
+	var hints []string
+	for _, section := range kb.Sections {
+		for _, binding := range section.KeyBindings {
+			if !binding.ActiveIn(contexts) || !active.Has(binding.Requires) || (kb.ReadOnly && binding.Action.Mutating()) {
+				continue
+			}
+			hints = append(hints, fmt.Sprintf("%s: %s", binding.Key, binding.Description))
 		}
-		return HelpStyle.Render(strings.Join(help, " • "))
 	}
 
-	// For normal view, show a more concise help text with the most important commands
-	bindings := kb.GetKeyBindingsForGlobal()
-	var help []string
-
-	// Add basic help items
-	for _, binding := range bindings {
-		// Skip task cancellation if no task is running
-		if binding.Key == "ctrl+x" && !taskRunning {
-			continue
-		}
-
-		// Skip batch execution if no tasks are selected
-		if binding.Key == "ctrl+e" && !hasSelectedTasks {
-			continue
-		}
+	line := strings.Join(hints, " • ")
+	if width > 0 && lipgloss.Width(line) > width {
+		line = truncateRunes(line, width-1) + "…"
+	}
+	return HelpStyle.Render(line)
+}
 
-		help = append(help, fmt.Sprintf("%s: %s", binding.Key, binding.Description))
+// truncateRunes cuts s to at most n runes
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if n < 0 {
+		n = 0
 	}
+	if len(runes) > n {
+		runes = runes[:n]
+	}
+	return string(runes)
+}
 
-	return HelpStyle.Render(strings.Join(help, " • "))
+// helpLine renders a binding in the help, grayed out when read-only mode disables it
+func (kb KeyBindings) helpLine(binding KeyBinding) string {
+	if kb.ReadOnly && binding.Action.Mutating() {
+		return HelpTextDisabledStyle.Render(binding.Key+": "+binding.Description+" (read-only)") + "\n"
+	}
+	return HelpTextCommandStyle.Render(binding.Key+": ") + binding.Description + "\n"
 }
 
-// GenerateHelpContent creates the help content with a two-column layout using the key bindings
-func (kb KeyBindings) GenerateHelpContent(overlayWidth int) string {
+// GenerateHelpContent creates the help content with a two-column layout using the key bindings.
+// Only bindings active in one of contexts are listed (all bindings when contexts is empty), and
+// a non-empty filter further restricts them to those whose key or description contains it.
+func (kb KeyBindings) GenerateHelpContent(overlayWidth int, contexts []Context, filter string) string {
 	// Calculate column width (accounting for padding and border)
 	contentWidth := overlayWidth - 6      // 6 = 2*2 padding + 2 border
 	columnWidth := (contentWidth / 2) - 2 // 2 for spacing between columns
@@ -183,12 +702,31 @@ func (kb KeyBindings) GenerateHelpContent(overlayWidth int) string {
 
 	content += "\n\n"
 
+	filter = strings.ToLower(filter)
+	matched := 0
+
 	// Add each section
 	for _, section := range kb.Sections {
+		var bindings []KeyBinding
+		for _, binding := range section.KeyBindings {
+			if len(contexts) > 0 && !binding.ActiveIn(contexts) {
+				continue
+			}
+			if filter != "" &&
+				!strings.Contains(strings.ToLower(binding.Key), filter) &&
+				!strings.Contains(strings.ToLower(binding.Description), filter) {
+				continue
+			}
+			bindings = append(bindings, binding)
+		}
+		if len(bindings) == 0 {
+			continue
+		}
+		matched += len(bindings)
+
 		content += HelpTextSectionStyle.Render(section.Name) + "\n"
 
 		// Split bindings into two columns
-		bindings := section.KeyBindings
 		midpoint := (len(bindings) + 1) / 2
 		col1Bindings := bindings[:midpoint]
 		col2Bindings := bindings[midpoint:]
@@ -196,27 +734,40 @@ func (kb KeyBindings) GenerateHelpContent(overlayWidth int) string {
 		// Render column 1
 		col1Content := ""
 		for _, binding := range col1Bindings {
-			col1Content += HelpTextCommandStyle.Render(binding.Key+": ") + binding.Description + "\n"
+			col1Content += kb.helpLine(binding)
 		}
 		col1 := lipgloss.NewStyle().Width(columnWidth).Render(col1Content)
 
 		// Render column 2
 		col2Content := ""
 		for _, binding := range col2Bindings {
-			col2Content += HelpTextCommandStyle.Render(binding.Key+": ") + binding.Description + "\n"
+			col2Content += kb.helpLine(binding)
 		}
 		col2 := lipgloss.NewStyle().Width(columnWidth).Render(col2Content)
 
 		// Join columns
 		content += lipgloss.JoinHorizontal(lipgloss.Top, col1, "  ", col2) + "\n\n"
+	}
 
-		// output model for debugging
-
+	if matched == 0 {
+		content += HelpStyle.Render("No matching key bindings")
 	}
 
 	return content
 }
 
+// ActiveIn reports whether the binding is active in any of the given contexts
+func (b KeyBinding) ActiveIn(contexts []Context) bool {
+	for _, ctx := range b.Contexts {
+		for _, c := range contexts {
+			if ctx == c {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // IsKeyMatch checks if a key message matches a key binding
 func IsKeyMatch(msg tea.KeyMsg, keyBinding string) bool {
 	// Handle special cases for key combinations
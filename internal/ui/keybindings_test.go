@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestRenderHintsFollowsConditions(t *testing.T) {
+	kb := DefaultKeyBindings()
+
+	hints := kb.RenderHints(StateNormal.HintContexts(), 0, 0)
+	if strings.Contains(hints, "Cancel task") || strings.Contains(hints, "Execute tasks") {
+		t.Errorf("Expected conditional hints to be hidden, got %q", hints)
+	}
+
+	hints = kb.RenderHints(StateNormal.HintContexts(), CondTaskRunning|CondTasksSelected, 0)
+	if !strings.Contains(hints, "Cancel task") || !strings.Contains(hints, "Execute tasks") {
+		t.Errorf("Expected conditional hints to be shown, got %q", hints)
+	}
+}
+
+func TestRenderHintsForState(t *testing.T) {
+	kb := DefaultKeyBindings()
+
+	hints := kb.RenderHints(StateTaskPicker.HintContexts(), 0, 0)
+	if !strings.Contains(hints, "Autocomplete") || strings.Contains(hints, "Quit") {
+		t.Errorf("Expected only task picker hints, got %q", hints)
+	}
+
+	hints = kb.RenderHints(StateStatsOverlay.HintContexts(), 0, 0)
+	if !strings.Contains(hints, "Close statistics") {
+		t.Errorf("Expected statistics overlay hints, got %q", hints)
+	}
+}
+
+func TestRenderHintsTruncatesToWidth(t *testing.T) {
+	kb := DefaultKeyBindings()
+
+	hints := kb.RenderHints(StateNormal.HintContexts(), 0, 40)
+	if !strings.Contains(hints, "…") {
+		t.Errorf("Expected the hint strip to be truncated, got %q", hints)
+	}
+}
+
+func TestDefaultKeyBindingsHaveNoConflicts(t *testing.T) {
+	if conflicts := DefaultKeyBindings().Conflicts(); len(conflicts) != 0 {
+		t.Errorf("Expected no conflicts, got %+v", conflicts)
+	}
+}
+
+func TestRebindReportsConflicts(t *testing.T) {
+	kb := DefaultKeyBindings()
+	if err := kb.Rebind(ActionStats, []string{"q"}); err != nil {
+		t.Fatalf("Rebind() error = %v", err)
+	}
+
+	if !kb.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}, ActionStats) {
+		t.Error("Expected q to trigger the rebound action")
+	}
+	if kb.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("T")}, ActionStats) {
+		t.Error("Expected the old key to no longer trigger the action")
+	}
+
+	conflicts := kb.ConflictsFor(ActionStats)
+	if len(conflicts) != 1 || conflicts[0].Key != "q" {
+		t.Errorf("Expected a conflict on q, got %+v", conflicts)
+	}
+}
+
+func TestApplyOverrides(t *testing.T) {
+	kb := DefaultKeyBindings()
+	err := kb.ApplyOverrides(map[string][]string{"quit": {"ctrl+q"}, "nonexistent": {"x"}})
+	if err == nil {
+		t.Error("Expected an error for an unknown action")
+	}
+	if !kb.Matches(tea.KeyMsg{Type: tea.KeyCtrlQ}, ActionQuit) {
+		t.Error("Expected quit to be bound to ctrl+q")
+	}
+
+	overrides := kb.Overrides()
+	if len(overrides) != 1 || overrides["quit"][0] != "ctrl+q" {
+		t.Errorf("Expected only the quit override, got %v", overrides)
+	}
+}
+
+func TestResolveChords(t *testing.T) {
+	kb := DefaultKeyBindings()
+	if err := kb.Rebind(ActionTop, []string{"g g"}); err != nil {
+		t.Fatalf("Rebind() error = %v", err)
+	}
+	contexts := []Context{ContextGlobal}
+
+	action, pending := kb.Resolve(contexts, nil, "g", "")
+	if action != ActionNone || len(pending) != 1 {
+		t.Fatalf("Expected g to start a chord, got %q with %v pending", action, pending)
+	}
+	action, pending = kb.Resolve(contexts, pending, "g", "")
+	if action != ActionTop || len(pending) != 0 {
+		t.Errorf("Expected g g to resolve to %q, got %q with %v pending", ActionTop, action, pending)
+	}
+
+	// A key that breaks off a chord is resolved on its own
+	_, pending = kb.Resolve(contexts, nil, "g", "")
+	action, pending = kb.Resolve(contexts, pending, "q", "")
+	if action != ActionQuit || len(pending) != 0 {
+		t.Errorf("Expected q to resolve to %q, got %q with %v pending", ActionQuit, action, pending)
+	}
+}
+
+func TestResolveLeader(t *testing.T) {
+	kb := DefaultKeyBindings()
+	if err := kb.Rebind(ActionRefresh, []string{"<leader> r"}); err != nil {
+		t.Fatalf("Rebind() error = %v", err)
+	}
+	contexts := []Context{ContextGlobal}
+
+	if action, pending := kb.Resolve(contexts, nil, "space", ""); action != ActionNone || len(pending) != 0 {
+		t.Errorf("Expected leader bindings to be ignored without a leader, got %q with %v pending", action, pending)
+	}
+	_, pending := kb.Resolve(contexts, nil, "space", "space")
+	if action, _ := kb.Resolve(contexts, pending, "r", "space"); action != ActionRefresh {
+		t.Errorf("Expected space r to resolve to %q, got %q", ActionRefresh, action)
+	}
+}
+
+func TestConflictsIncludeChordPrefixes(t *testing.T) {
+	kb := DefaultKeyBindings()
+	if err := kb.Rebind(ActionTop, []string{"q q"}); err != nil {
+		t.Fatalf("Rebind() error = %v", err)
+	}
+
+	conflicts := kb.ConflictsFor(ActionTop)
+	if len(conflicts) != 1 || conflicts[0].Key != "q" {
+		t.Errorf("Expected q q to conflict with q, got %+v", conflicts)
+	}
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/task"
+)
+
+// labelNames returns the configured labels, sorted
+func labelNames(cfg config.Config) []string {
+	return slices.Sorted(maps.Keys(cfg.Labels))
+}
+
+// groupByLabel returns the tasks ordered by their first label, unlabelled tasks last. Tasks keep
+// their order within each label.
+func groupByLabel(tasks []task.Task, cfg config.Config) []task.Task {
+	names := labelNames(cfg)
+	rank := func(t task.Task) int {
+		if labels := cfg.LabelsFor(t.Id); len(labels) > 0 {
+			return slices.Index(names, labels[0])
+		}
+		return len(names)
+	}
+	grouped := slices.Clone(tasks)
+	slices.SortStableFunc(grouped, func(a, b task.Task) int {
+		return rank(a) - rank(b)
+	})
+	return grouped
+}
+
+// filterByLabel returns the tasks having label
+func filterByLabel(tasks []task.Task, cfg config.Config, label string) []task.Task {
+	return slices.DeleteFunc(slices.Clone(tasks), func(t task.Task) bool {
+		return !slices.Contains(cfg.LabelsFor(t.Id), label)
+	})
+}
+
+// cycleLabelFilter moves the label filter on to the next label, and back to all tasks after the
+// last one
+func (m *Model) cycleLabelFilter() {
+	names := labelNames(m.Config)
+	if len(names) == 0 {
+		m.AppendErrorMsg("No labels configured, add them to labels in the config\n")
+		return
+	}
+	next := ""
+	if i := slices.Index(names, m.LabelFilter); m.LabelFilter == "" {
+		next = names[0]
+	} else if i >= 0 && i+1 < len(names) {
+		next = names[i+1]
+	}
+	m.LabelFilter = next
+	m.applyTaskFilter()
+	m.UpdateTaskTable()
+	if m.LabelFilter == "" {
+		m.AppendAppMsg("Listing tasks of every label\n")
+	} else {
+		m.AppendAppMsg("Listing tasks labelled " + m.LabelFilter + "\n")
+	}
+}
@@ -0,0 +1,134 @@
+package ui
+
+import "github.com/charmbracelet/lipgloss"
+
+// Layout is the arrangement of the task list and the output viewport
+type Layout int
+
+const (
+	// LayoutSideBySide places the task list to the left of the output
+	LayoutSideBySide Layout = iota
+
+	// LayoutStacked places the task list above the output
+	LayoutStacked
+
+	// LayoutSinglePane shows only the focused panel; tab switches between them
+	LayoutSinglePane
+)
+
+// Layout selection thresholds
+const (
+	NarrowWidth      = 100 // Below this width the panels no longer fit side by side
+	StackedMinHeight = 24  // Below this height, narrow terminals show a single pane
+	panelChrome      = 4   // Border and status lines around the panels
+	MinWidth         = 20  // Below this size only a notice is shown
+	MinHeight        = 6
+	InlineHeight     = 20 // Default height in inline mode
+)
+
+// ParseLayout maps a layout config value to a layout; "auto" and "" choose by terminal size
+func ParseLayout(name string) (Layout, bool) {
+	switch name {
+	case "side-by-side":
+		return LayoutSideBySide, true
+	case "stacked":
+		return LayoutStacked, true
+	case "single":
+		return LayoutSinglePane, true
+	default:
+		return LayoutSideBySide, false
+	}
+}
+
+// chooseLayout returns the configured layout, or picks one that fits width and height
+func chooseLayout(name string, width, height int) Layout {
+	if l, ok := ParseLayout(name); ok {
+		return l
+	}
+	switch {
+	case width >= NarrowWidth:
+		return LayoutSideBySide
+	case height >= StackedMinHeight:
+		return LayoutStacked
+	default:
+		return LayoutSinglePane
+	}
+}
+
+// inlineHeight returns the height tash takes in a terminal height lines tall, which is less in
+// inline mode so the shell's output stays in view above it
+func (m Model) inlineHeight(height int) int {
+	if !m.Config.Inline {
+		return height
+	}
+	if m.Config.InlineHeight > 0 {
+		return min(height, max(m.Config.InlineHeight, MinHeight))
+	}
+	return min(height, InlineHeight)
+}
+
+// resizePanels sizes the task list and output for the current layout
+func (m *Model) resizePanels() {
+	if m.Config.Plain {
+		m.resizePlain()
+		return
+	}
+
+	m.Layout = chooseLayout(m.Config.Layout, m.Width, m.Height)
+	available := max(m.Height-panelChrome, 1)
+
+	switch m.Layout {
+	case LayoutStacked:
+		// each panel has its own border, two lines more than side by side
+		available = max(available-2, 2)
+		tableHeight := max(available*2/5, 1)
+		m.Table.SetWidth(m.Width - 2)
+		m.Table.SetHeight(tableHeight)
+		m.Viewport.Width = m.Width - 2
+		m.Viewport.Height = max(available-tableHeight, 1)
+	case LayoutSinglePane:
+		m.Table.SetWidth(m.Width - 2)
+		m.Table.SetHeight(available)
+		m.Viewport.Width = m.Width - 2
+		m.Viewport.Height = available
+	default:
+		tableWidth := int(float64(m.Width) * 0.4)
+		viewportWidth := m.Width - tableWidth - 4
+
+		m.Table.SetWidth(tableWidth)
+		m.Table.SetHeight(available)
+
+		m.Viewport.Width = viewportWidth
+		m.Viewport.Height = available
+	}
+}
+
+// renderPanels renders the task list and output in the current layout
+func (m Model) renderPanels() string {
+	if m.Config.Plain {
+		return m.renderPlainMain()
+	}
+
+	tableRendered := m.Table.View()
+	viewportRendered := m.Viewport.View()
+
+	if m.Focused == ControlTable {
+		tableRendered = FocusedStyle.Render(tableRendered)
+		viewportRendered = ViewportStyle.Render(viewportRendered)
+	} else if m.Focused == ControlViewport {
+		tableRendered = TableStyle.Render(tableRendered)
+		viewportRendered = FocusedStyle.Render(viewportRendered)
+	}
+
+	switch m.Layout {
+	case LayoutStacked:
+		return lipgloss.JoinVertical(lipgloss.Left, tableRendered, viewportRendered)
+	case LayoutSinglePane:
+		if m.Focused == ControlViewport {
+			return viewportRendered
+		}
+		return tableRendered
+	default:
+		return lipgloss.JoinHorizontal(lipgloss.Top, tableRendered, viewportRendered)
+	}
+}
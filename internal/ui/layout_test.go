@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestChooseLayout(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		want          Layout
+	}{
+		{"", 160, 40, LayoutSideBySide},
+		{"", 80, 40, LayoutStacked},
+		{"auto", 80, 20, LayoutSinglePane},
+		{"side-by-side", 80, 20, LayoutSideBySide},
+		{"stacked", 160, 40, LayoutStacked},
+	}
+	for _, tt := range tests {
+		if got := chooseLayout(tt.name, tt.width, tt.height); got != tt.want {
+			t.Errorf("chooseLayout(%q, %d, %d) = %d, expected %d", tt.name, tt.width, tt.height, got, tt.want)
+		}
+	}
+}
+
+func TestSinglePaneShowsFocusedPanel(t *testing.T) {
+	m := NewModel(nil, config.Default())
+	m.Initialised = true
+	m.HandleWindowResize(60, 20)
+	if m.Layout != LayoutSinglePane {
+		t.Fatalf("Expected a single pane layout, got %d", m.Layout)
+	}
+
+	m.AppendAppMsg("hello from the output\n")
+	if strings.Contains(m.View(), "hello from the output") {
+		t.Error("Expected the output to be hidden while the task list is focused")
+	}
+	m.Focused = ControlViewport
+	if !strings.Contains(m.View(), "hello from the output") {
+		t.Error("Expected the output to be shown once focused")
+	}
+}
+
+func TestTinyTerminalShowsNotice(t *testing.T) {
+	m := NewModel(nil, config.Default())
+	m.Initialised = true
+	m.HandleWindowResize(10, 3)
+	if !strings.Contains(m.View(), "Terminal too small") {
+		t.Errorf("Expected a notice for a tiny terminal, got %q", m.View())
+	}
+}
+
+func TestInlineModeIsCompact(t *testing.T) {
+	cfg := config.Default()
+	cfg.Inline = true
+	m := NewModel(nil, cfg)
+	m.Initialised = true
+	m.HandleWindowResize(160, 50)
+	if m.Height != InlineHeight {
+		t.Fatalf("Expected the height to be capped to %d lines, got %d", InlineHeight, m.Height)
+	}
+	if lines := strings.Count(m.View(), "\n") + 1; lines > InlineHeight {
+		t.Errorf("Expected the view to fit in %d lines, got %d", InlineHeight, lines)
+	}
+
+	m.Config.InlineHeight = 12
+	m.HandleWindowResize(160, 10)
+	if m.Height != 10 {
+		t.Errorf("Expected a short terminal to keep its height, got %d", m.Height)
+	}
+	if m.repairTerminal() != nil {
+		t.Error("Expected the screen and scrollback to be left alone after a run")
+	}
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlG}); cmd == nil || cmd() != tea.ClearScreen() {
+		t.Error("Expected ctrl+g to still repaint the view")
+	}
+}
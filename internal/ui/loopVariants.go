@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/Aj4x/tash/internal/task"
+	"github.com/Aj4x/tash/internal/taskfile"
+)
+
+// variantMarker prefixes the table rows of loop variants, listed below the task defining the loop
+const variantMarker = "  ↳ "
+
+// loopVariants returns the runs generated by the for loops of tasks, such as the cells of a
+// matrix, keyed by the id of the task defining the loop. A variant runs the task the loop calls
+// with the variables of its item, and is located at the loop's task. Each Taskfile is read once.
+func loopVariants(tasks []task.Task) map[string][]task.Task {
+	loaded := map[string]*taskfile.Taskfile{}
+	variants := map[string][]task.Task{}
+	for _, t := range tasks {
+		path := t.Taskfile()
+		if path == "" {
+			continue
+		}
+		tf, ok := loaded[path]
+		if !ok {
+			var err error
+			if tf, err = taskfile.Load(path); err != nil {
+				slog.Debug("Unable to read the Taskfile for loop variants", "taskfile", path, "error", err)
+			}
+			loaded[path] = tf
+		}
+		if tf == nil {
+			continue
+		}
+		generated, err := tf.Variants(t.Id)
+		if err != nil {
+			continue
+		}
+		for _, v := range generated {
+			variants[t.Id] = append(variants[t.Id], task.Task{Id: v.Task, Vars: v.Vars, LoopOf: t.Id, Location: t.Location})
+		}
+	}
+	return variants
+}
+
+// withVariants lists the loop variants of each task right below it
+func (m Model) withVariants(tasks []task.Task) []task.Task {
+	if len(m.variants) == 0 {
+		return tasks
+	}
+	listed := make([]task.Task, 0, len(tasks))
+	for _, t := range tasks {
+		listed = append(listed, t)
+		listed = append(listed, m.variants[t.Id]...)
+	}
+	return listed
+}
+
+// sameRow reports whether a and b are the same row of the task table
+func sameRow(a, b task.Task) bool {
+	return a.Id == b.Id && a.LoopOf == b.LoopOf && slices.Equal(a.Vars, b.Vars)
+}
+
+// variantRow returns the Id and Description cells of a loop variant
+func variantRow(t task.Task) (string, string) {
+	return variantMarker + t.Id, strings.Join(t.Vars, " ")
+}
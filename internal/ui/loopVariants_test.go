@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/task"
+)
+
+func TestLoopVariantsAreListedAndRunWithTheirVars(t *testing.T) {
+	taskfile := filepath.Join(t.TempDir(), "Taskfile.yml")
+	content := `version: '3'
+tasks:
+  release:
+    desc: Release every platform
+    cmds:
+      - for: [linux, darwin]
+        task: package
+        vars:
+          OS: '{{.ITEM}}'
+  package:
+    desc: Package one platform
+`
+	if err := os.WriteFile(taskfile, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	location := &task.Location{Taskfile: taskfile}
+	m := newNavigationModel(0)
+	m.showTasks([]task.Task{
+		{Id: "package", Desc: "Package one platform", Location: location},
+		{Id: "release", Desc: "Release every platform", Location: location},
+	})
+
+	if len(m.Tasks) != 4 || len(m.AllTasks) != 2 {
+		t.Fatalf("Expected the 2 variants to be listed below release, got %d rows", len(m.Tasks))
+	}
+	if row := m.Table.Rows()[3]; row[0] != variantMarker+"package" || row[2] != "OS=darwin" {
+		t.Errorf("Expected the darwin variant of package, got %v", row)
+	}
+
+	m.Table.SetCursor(3)
+	m.ExecuteSelectedTask()
+	if m.RunningTaskId != "package" || m.nextVariant != nil {
+		t.Fatalf("Expected package to run, got %q", m.RunningTaskId)
+	}
+
+	// the cursor stays on the variant when the list is refreshed
+	m.TasksLoading = false
+	m.showTasks(slices.Clone(m.AllTasks))
+	if c := m.Table.Cursor(); m.Tasks[c].LoopOf != "release" || m.Tasks[c].Vars[0] != "OS=darwin" {
+		t.Errorf("Expected the cursor to stay on the darwin variant, got row %d", c)
+	}
+
+	m.Config.HideLoopVariants = true
+	m.showTasks(m.AllTasks)
+	if len(m.Tasks) != 2 {
+		t.Errorf("Expected no variants when they are hidden, got %d rows", len(m.Tasks))
+	}
+}
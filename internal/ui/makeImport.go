@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/Aj4x/tash/internal/task"
+	"github.com/Aj4x/tash/internal/taskfile"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// MakeImport holds the tasks translated from the Makefile, previewed before they are added
+type MakeImport struct {
+	Makefile string
+	Path     string // Taskfile the tasks are added to, created when it doesn't exist
+	YAML     string // Definitions of the tasks
+	Skipped  []string
+	Offset   int    // First line of the definitions shown
+	Error    string // Reason the tasks couldn't be added
+}
+
+// openMakeImport translates the targets of the project's Makefile to tasks and previews them
+func (m *Model) openMakeImport() {
+	if m.Config.Provider == task.ProviderDemo {
+		m.AppendErrorMsg("Demo tasks are not defined in a Taskfile")
+		return
+	}
+	if task.IsRemoteTaskfile(m.Config.Taskfile) {
+		m.AppendErrorMsg("Tasks can't be added to a remote Taskfile")
+		return
+	}
+	dir := m.catalogDir()
+	makefile, err := taskfile.FindMakefile(dir)
+	if err != nil {
+		m.AppendErrorMsg(err.Error())
+		return
+	}
+	imp, err := taskfile.ImportMakefile(makefile)
+	if err != nil {
+		m.AppendErrorMsg("Unable to read the Makefile: " + err.Error())
+		return
+	}
+	if len(imp.Tasks) == 0 {
+		m.AppendErrorMsg("No target of " + makefile + " could be imported")
+		return
+	}
+	definitions, err := imp.YAML()
+	if err != nil {
+		m.AppendErrorMsg("Unable to import the Makefile: " + err.Error())
+		return
+	}
+	path := m.Config.Taskfile
+	if path == "" {
+		if path, err = taskfile.Find(dir); taskfile.IsNotFound(err) {
+			path = filepath.Join(dir, taskfile.DefaultNames[0])
+		}
+	}
+	m.MakeImport = MakeImport{Makefile: makefile, Path: path, YAML: definitions, Skipped: imp.Skipped}
+	m.SetState(StateMakeImport)
+}
+
+// handleMakeImportKey handles key presses while the imported tasks are previewed
+func (m Model) handleMakeImportKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	imp := &m.MakeImport
+	switch m.resolveKey(msg) {
+	case ActionClose:
+		m.SetState(StateNormal)
+	case ActionUp:
+		imp.Offset = max(imp.Offset-1, 0)
+	case ActionDown:
+		imp.Offset = min(imp.Offset+1, max(strings.Count(imp.YAML, "\n")-makeImportHeight(m.Height), 0))
+	case ActionConfirm:
+		line, err := taskfile.AddTasks(imp.Path, imp.YAML)
+		if err != nil {
+			imp.Error = err.Error()
+			return m, nil
+		}
+		m.SetState(StateNormal)
+		m.AppendAppMsg(fmt.Sprintf("Imported the targets of %s to %s\n", imp.Makefile, imp.Path))
+		return m, m.openEditor(imp.Path, line)
+	}
+	return m, nil
+}
+
+// makeImportHeight returns the number of lines of the definitions shown at once
+func makeImportHeight(height int) int {
+	return max(height-16, 5)
+}
+
+// RenderMakeImport renders the preview of the tasks imported from the Makefile
+func RenderMakeImport(width, height int, imp MakeImport) string {
+	overlayWidth := int(float64(width) * 0.8)
+
+	content := TaskPickerTitleStyle.Render("Import "+imp.Makefile) + "\n\n"
+	lines := strings.Split(strings.TrimSuffix(imp.YAML, "\n"), "\n")
+	end := min(imp.Offset+makeImportHeight(height), len(lines))
+	content += strings.Join(lines[min(imp.Offset, end):end], "\n") + "\n"
+	if end < len(lines) {
+		content += HelpStyle.Render(fmt.Sprintf("… %d more lines", len(lines)-end)) + "\n"
+	}
+	content += "\n"
+	if len(imp.Skipped) > 0 {
+		content += HelpStyle.Render("Not imported: "+strings.Join(imp.Skipped, "; ")) + "\n\n"
+	}
+	if imp.Error != "" {
+		content += ErrorMsgStyle.Render(imp.Error) + "\n\n"
+	}
+	content += HelpStyle.Render("enter add to " + imp.Path + ", ↑/↓ scroll, esc cancel")
+
+	return placeOverlay(width, height, GeneralOverlayStyle(overlayWidth).Render(content))
+}
@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestMakeImportPreviewsAndAddsTheTasks(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("EDITOR", "true")
+	cfg := config.Default()
+	cfg.WorkDir = dir
+	m := NewModel(nil, cfg)
+	m.HandleWindowResize(120, 40)
+
+	m.openMakeImport()
+	if m.State != StateNormal {
+		t.Fatal("Expected no preview without a Makefile")
+	}
+	os.WriteFile(filepath.Join(dir, "Makefile"), []byte("test: ## Run the tests\n\tgo test ./...\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "Taskfile.yml"), []byte("version: '3'\ntasks:\n  fmt: gofmt -w .\n"), 0o644)
+	m.openMakeImport()
+	if m.State != StateMakeImport {
+		t.Fatal("Expected the imported tasks to be previewed")
+	}
+	if view := RenderMakeImport(m.Width, m.Height, m.MakeImport); !strings.Contains(view, "desc: Run the tests") {
+		t.Errorf("Expected the definitions in the preview, got %q", view)
+	}
+
+	updated, cmd := m.handleMakeImportKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if m = updated.(Model); cmd == nil || m.State != StateNormal {
+		t.Fatalf("Expected the editor to be opened, got state %s and error %q", m.State, m.MakeImport.Error)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "Taskfile.yml"))
+	if !strings.HasSuffix(string(data), "  fmt: gofmt -w .\n  test:\n    desc: Run the tests\n    cmds:\n      - go test ./...\n") {
+		t.Errorf("Expected the task after the existing ones, got:\n%s", data)
+	}
+}
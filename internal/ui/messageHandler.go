@@ -3,12 +3,25 @@ package ui
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/Aj4x/tash/internal/task"
 	tea "github.com/charmbracelet/bubbletea"
+	"log/slog"
 )
 
 func (m Model) handleBusMessage(message task.Message) (Model, tea.Cmd) {
+	switch message.Type {
+	case task.TypeTaskOutput, task.TypeTaskOutputErr:
+		// output lines are summarised when the run finishes rather than logged individually
+		m.OutputLineCount++
+	case task.TypeTaskDone, task.TypeTaskError:
+		slog.Debug("bus message", "type", message.Type, "outputLines", m.OutputLineCount)
+		m.OutputLineCount = 0
+	default:
+		slog.Debug("bus message", "type", message.Type)
+	}
+
 	switch message.Type {
 	case task.TypeTaskOutput:
 		return m.handleTaskOutputMsg(message)
@@ -22,40 +35,74 @@ func (m Model) handleBusMessage(message task.Message) (Model, tea.Cmd) {
 		return m.handleTaskCommandMsg(message)
 	case task.TypeTaskDone:
 		return m.handleTaskDoneMsg(message)
-	case task.TypeTaskListAllDone:
-		return m.handleListAllDoneMsg(message)
+	case task.TypeTaskListPartial:
+		return m.handleTaskListPartialMsg(message)
 	case task.TypeTaskListAllErr:
 		return m.handleListAllErrMsg(message)
+	case task.TypeWatchTrigger:
+		return m.handleWatchTriggerMsg(message)
+	case task.TypeTaskPassword:
+		return m.handlePasswordMsg(message)
 	default:
 		return m, nil
 	}
 }
 
 func (m Model) handleTaskOutputMsg(msg task.Message) (Model, tea.Cmd) {
-	m.AppendCommandOutput(msg.Output())
+	m.appendTaskOutput(msg.Output(), OutputStyle)
+	m.captureRunLine(msg.Output())
+	if m.Config.MergeOutput {
+		// stderr arrives interleaved with stdout, so the summary shows the last lines of both
+		m.captureStderrLine(msg.Output())
+	}
 	return m, nil
 }
 
 func (m Model) handleTaskOutputErr(msg task.Message) (Model, tea.Cmd) {
-	m.AppendErrorMsg(msg.Output())
+	m.appendTaskOutput(msg.Output(), ErrorMsgStyle)
+	m.captureRunLine(msg.Output())
+	m.captureStderrLine(msg.Output())
 	return m, nil
 }
 
 func (m Model) handleTaskErrorMsg(msg task.Message) (Model, tea.Cmd) {
-	m.AppendErrorMsg(msg.Error().Error())
+	err := msg.Error()
+	m.showFailureSummary(err)
+	m.recordRun(err)
+	m.closeFolds(foldRun)
+	if errors.Is(err, task.ErrTimeout) {
+		m.AppendErrorMsg("Timeout: " + err.Error())
+	} else {
+		m.AppendErrorMsg(err.Error())
+	}
+	m.TasksLoading = false
+	if m.Repeat.Active {
+		m = m.finishRepeatIteration(false)
+		return m, m.repairTerminal()
+	}
 	if m.ExecutingBatch {
+		if m.Config.ContinueOnError {
+			m.AppendErrorMsg("Continuing batch execution after failure")
+			next, cmd := m.executeNextSelectedTask(m.CurrentBatchTaskIndex)
+			return next, tea.Batch(m.repairTerminal(), cmd)
+		}
 		m.AppendErrorMsg("Batch execution aborted")
 		m.ExecutingBatch = false
 		m.CurrentBatchTaskIndex = -1
 	}
-	return m, nil
+	return m, m.repairTerminal()
 }
 
 // handleTaskCommandMsg processes task command messages
 func (m Model) handleTaskCommandMsg(msg task.Message) (Model, tea.Cmd) {
 	m.TaskRunning = msg.TaskRunning()
+	m.TaskPaused = false
 	m.Command = msg.Command()
 	m.CommandCancel = msg.CancelFunc()
+	if !m.TaskRunning && m.State == StatePasswordPrompt {
+		// the prompt can't be answered once the task has exited
+		m.closePasswordPrompt()
+	}
 	return m, nil
 }
 
@@ -67,6 +114,15 @@ func (m Model) handleTaskJsonMsg(msg task.Message) (Model, tea.Cmd) {
 		m.AppendErrorMsg("Error parsing task list: " + err.Error())
 		return m, nil
 	}
+	m.startup.mark("task list")
+	m.saveCatalog(msgContent, tasks)
+	// a list refreshed behind the cached one doesn't hold up task runs, so it leaves
+	// TasksLoading alone
+	background := m.cachedTasks != nil
+	if m.cachedListUnchanged(tasks) {
+		m.AppendAppMsg("Task list unchanged since it was cached\n")
+		return m.runStartupTask()
+	}
 	var parsedJson bytes.Buffer
 	err = json.Indent(&parsedJson, []byte(msgContent), "", "\t")
 	if err != nil {
@@ -76,31 +132,69 @@ func (m Model) handleTaskJsonMsg(msg task.Message) (Model, tea.Cmd) {
 		m.AppendCommandOutput(string(parsedJson.Bytes()))
 	}
 	m.AppendAppMsg(fmt.Sprintf("Task list:\n%s\n", parsedJson.String()))
-	m.Tasks = tasks
-	m.AppendAppMsg(fmt.Sprintf("Tasks added: %d\n", len(m.Tasks)))
-	m.UpdateTaskTable()
-	m.TasksLoading = false
+	m.showTasks(tasks)
+	m.listedTasks = nil
+	m.listing = false
+	// the Taskfile may have changed, so ask for task input again
+	m.RunInputs = nil
+	m.AppendAppMsg(fmt.Sprintf("Tasks added: %d\n", len(m.AllTasks)))
+	if !background {
+		m.TasksLoading = false
+	}
+	return m.runStartupTask()
+}
+
+// handleTaskListPartialMsg shows the tasks listed so far while a listing is read, replacing the
+// previous list once the first of them arrive
+func (m Model) handleTaskListPartialMsg(msg task.Message) (Model, tea.Cmd) {
+	if !m.listing {
+		return m, nil
+	}
+	m.startup.mark("first tasks listed")
+	m.listedTasks = append(m.listedTasks, msg.Tasks()...)
+	m.showTasks(m.listedTasks)
 	return m, nil
 }
 
 func (m Model) handleTaskDoneMsg(msg task.Message) (Model, tea.Cmd) {
+	m.recordRun(nil)
+	m.closeFolds(foldRun)
 	m.TasksLoading = false
 	m.AppendAppMsg("Task executed successfully!\n")
+	if m.Repeat.Active {
+		next, cmd := m.finishRepeatIteration(true).nextRepeatIteration()
+		return next, tea.Batch(m.repairTerminal(), cmd)
+	}
 	if m.ExecutingBatch {
-		return m.executeNextSelectedTask(m.CurrentBatchTaskIndex)
+		next, cmd := m.executeNextSelectedTask(m.CurrentBatchTaskIndex)
+		return next, tea.Batch(m.repairTerminal(), cmd)
 	}
-	return m, nil
+	return m, m.repairTerminal()
 }
 
-func (m Model) handleListAllDoneMsg(msg task.Message) (Model, tea.Cmd) {
-	m.TasksLoading = false
-	m.AppendAppMsg("Task list refreshed successfully!\n")
-	m.UpdateTaskTable()
-	return m, nil
+// runStartupTask runs the task requested with --run, once, after the first task list has loaded
+func (m Model) runStartupTask() (Model, tea.Cmd) {
+	name := m.StartupTask
+	if name == "" {
+		return m, nil
+	}
+	m.StartupTask = ""
+	if m.refuseReadOnly(ActionExecute) {
+		return m, nil
+	}
+	t, ok := task.Find(m.AllTasks, name)
+	if !ok {
+		m.AppendErrorMsg(fmt.Sprintf("Unknown task '%s'", name))
+		return m, nil
+	}
+	return m, m.executeTask(t)
 }
 
 func (m Model) handleListAllErrMsg(msg task.Message) (Model, tea.Cmd) {
 	m.TasksLoading = false
+	m.listing = false
+	m.listedTasks = nil
 	m.AppendErrorMsg("Error: " + msg.Error().Error())
+	m.recordListFailure(msg.Error())
 	return m, nil
 }
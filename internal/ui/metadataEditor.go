@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Aj4x/tash/internal/task"
+	"github.com/Aj4x/tash/internal/taskfile"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Fields of the metadata editor, in the order they are listed
+const (
+	metadataDesc = iota
+	metadataSummary
+	metadataAliases
+	metadataCount
+)
+
+// MetadataForm holds the description, summary and aliases being edited for a task, written
+// back into the Taskfile defining it when saved
+type MetadataForm struct {
+	TaskId string
+	Path   string                // Taskfile defining the task
+	Values [metadataCount]string // Aliases are comma separated
+	Field  int                   // Index of the focused field
+	Error  string                // Reason the edit couldn't be saved, shown until it is changed
+}
+
+// openMetadataEditor opens the editor filled in with the metadata t has in its Taskfile
+func (m *Model) openMetadataEditor(t task.Task) {
+	if m.Config.Provider == task.ProviderDemo {
+		m.SetState(StateNormal)
+		m.AppendErrorMsg("Demo tasks are not defined in a Taskfile")
+		return
+	}
+	path, err := taskfilePath(t)
+	var meta taskfile.Metadata
+	if err == nil {
+		var tf *taskfile.Taskfile
+		if tf, err = taskfile.Load(path); err == nil {
+			meta, err = tf.Metadata(t.Id)
+		}
+	}
+	if err != nil {
+		m.SetState(StateNormal)
+		m.AppendErrorMsg("Unable to read the metadata of " + t.Id + ": " + err.Error())
+		return
+	}
+	m.MetadataForm = MetadataForm{
+		TaskId: t.Id,
+		Path:   path,
+		Values: [metadataCount]string{meta.Desc, strings.TrimSuffix(meta.Summary, "\n"), strings.Join(meta.Aliases, ", ")},
+	}
+	m.SetState(StateMetadataEditor)
+}
+
+// metadata returns the edited metadata, with blank aliases dropped
+func (f MetadataForm) metadata() taskfile.Metadata {
+	meta := taskfile.Metadata{
+		Desc:    strings.TrimSpace(f.Values[metadataDesc]),
+		Summary: strings.TrimSpace(f.Values[metadataSummary]),
+	}
+	for _, alias := range strings.Split(f.Values[metadataAliases], ",") {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			meta.Aliases = append(meta.Aliases, alias)
+		}
+	}
+	return meta
+}
+
+// handleMetadataEditorKey handles key presses in the metadata editor
+func (m Model) handleMetadataEditorKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	form := &m.MetadataForm
+	value := &form.Values[form.Field]
+
+	switch action := m.resolveKey(msg); {
+	case action == ActionClose:
+		m.SetState(StateDetailsOverlay)
+	case IsKeyMatch(msg, "alt+enter"):
+		if form.Field == metadataSummary {
+			*value += "\n"
+		}
+	case action == ActionConfirm:
+		return m.saveMetadata()
+	case action == ActionUp:
+		form.Field = (form.Field + metadataCount - 1) % metadataCount
+	case action == ActionDown:
+		form.Field = (form.Field + 1) % metadataCount
+	case IsKeyMatch(msg, "backspace"):
+		if runes := []rune(*value); len(runes) > 0 {
+			*value = string(runes[:len(runes)-1])
+		}
+		form.Error = ""
+	case msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace:
+		*value += string(msg.Runes)
+		if msg.Type == tea.KeySpace {
+			*value += " "
+		}
+		form.Error = ""
+	}
+	return m, nil
+}
+
+// saveMetadata writes the edited metadata into the Taskfile and refreshes the task list, so the
+// change shows up
+func (m Model) saveMetadata() (tea.Model, tea.Cmd) {
+	form := m.MetadataForm
+	if err := taskfile.SetMetadata(form.Path, form.TaskId, form.metadata()); err != nil {
+		m.MetadataForm.Error = err.Error()
+		return m, nil
+	}
+	m.SetState(StateNormal)
+	m.AppendAppMsg(fmt.Sprintf("Updated the metadata of %s in %s\n", form.TaskId, form.Path))
+	if m.TasksLoading {
+		return m, nil
+	}
+	return m, m.RefreshTaskList()
+}
+
+// RenderMetadataEditor renders the editor of a task's description, summary and aliases
+func RenderMetadataEditor(width, height int, form MetadataForm) string {
+	overlayWidth := int(float64(width) * 0.7)
+	labels := [metadataCount]string{"Description: ", "Summary: ", "Aliases: "}
+
+	content := TaskPickerTitleStyle.Render("Edit "+form.TaskId) + "\n"
+	content += HelpStyle.Render(form.Path) + "\n\n"
+	for i, label := range labels {
+		row := TaskDetailOverlayLabelStyle.Render(label) + strings.ReplaceAll(form.Values[i], "\n", "↵")
+		if i == form.Field {
+			content += TaskPickerSelectedMatchStyle(overlayWidth).Render(row) + "\n"
+		} else {
+			content += TaskPickerMatchStyle(overlayWidth).Render(row) + "\n"
+		}
+	}
+	if form.Error != "" {
+		content += "\n" + ErrorMsgStyle.Render(form.Error) + "\n"
+	}
+	content += "\n" + HelpStyle.Render("aliases are comma separated; empty fields are removed from the Taskfile")
+
+	overlay := GeneralOverlayStyle(overlayWidth).Render(content)
+
+	return placeOverlay(width, height, overlay)
+}
@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/task"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestMetadataEditorWritesTheTaskfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Taskfile.yml")
+	content := `version: '3'
+
+tasks:
+  # undocumented
+  build: go build
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m := NewModel(nil, config.Default())
+	m.HandleWindowResize(120, 40)
+	build := task.Task{Id: "build", Location: &task.Location{Taskfile: path, Line: 5}}
+	m.SelectedTask = &build
+	m.SetState(StateDetailsOverlay)
+
+	updated, _ := m.handleDetailsOverlayKey(runes("m"))
+	m = updated.(Model)
+	if m.State != StateMetadataEditor {
+		t.Fatalf("Expected the metadata editor, got state %s", m.State)
+	}
+	for _, msg := range []tea.KeyMsg{
+		runes("Build"), {Type: tea.KeySpace}, runes("it"),
+		{Type: tea.KeyTab}, {Type: tea.KeyTab},
+		runes("b,"), {Type: tea.KeySpace}, runes("compile"),
+	} {
+		updated, _ = m.handleMetadataEditorKey(msg)
+		m = updated.(Model)
+	}
+	updated, _ = m.handleMetadataEditorKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if m = updated.(Model); m.State != StateNormal || m.MetadataForm.Error != "" {
+		t.Fatalf("Expected the edit to be saved, got state %s and error %q", m.State, m.MetadataForm.Error)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `version: '3'
+
+tasks:
+  # undocumented
+  build:
+    desc: Build it
+    aliases: [b, compile]
+    cmds:
+      - go build
+`
+	if string(data) != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, data)
+	}
+	if !strings.Contains(m.output.String(), "Updated the metadata of build") {
+		t.Error("Expected the update to be reported")
+	}
+}
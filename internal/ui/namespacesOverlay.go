@@ -0,0 +1,201 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/Aj4x/tash/internal/namespaces"
+	"github.com/Aj4x/tash/internal/task"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// namespaceCount is a namespace of the listed tasks with the number of tasks it covers,
+// including those of its nested namespaces
+type namespaceCount struct {
+	Name  string
+	Tasks int
+}
+
+// UseNamespaceStore keeps the hidden and pinned namespaces of the project in store, and applies
+// the ones chosen before
+func (m *Model) UseNamespaceStore(store namespaces.Store) {
+	m.NamespaceStore = store
+	prefs, err := store.Load(m.catalogDir())
+	if err != nil {
+		slog.Warn("Unable to load the namespace choices", "error", err)
+		return
+	}
+	m.Namespaces = prefs
+}
+
+// pinNamespaces returns the tasks with those of the pinned namespaces first, in the order they
+// were pinned. Tasks keep their order otherwise.
+func pinNamespaces(tasks []task.Task, pinned []string) []task.Task {
+	rank := func(t task.Task) int {
+		if ns, ok := namespaces.Match(pinned, t.Namespace()); ok {
+			return slices.Index(pinned, ns)
+		}
+		return len(pinned)
+	}
+	ordered := slices.Clone(tasks)
+	slices.SortStableFunc(ordered, func(a, b task.Task) int {
+		return rank(a) - rank(b)
+	})
+	return ordered
+}
+
+// namespaceList returns the namespaces of all listed tasks, along with the namespaces they are
+// nested in, sorted by name
+func (m Model) namespaceList() []namespaceCount {
+	counts := map[string]int{}
+	for _, t := range m.AllTasks {
+		ns := t.Namespace()
+		for ns != "" {
+			counts[ns]++
+			i := strings.LastIndex(ns, ":")
+			if i < 0 {
+				break
+			}
+			ns = ns[:i]
+		}
+	}
+	list := make([]namespaceCount, 0, len(counts))
+	for name, n := range counts {
+		list = append(list, namespaceCount{Name: name, Tasks: n})
+	}
+	slices.SortFunc(list, func(a, b namespaceCount) int { return strings.Compare(a.Name, b.Name) })
+	return list
+}
+
+// openNamespaces lists the namespaces of the tasks to hide or pin them
+func (m *Model) openNamespaces() {
+	if len(m.namespaceList()) == 0 {
+		m.AppendErrorMsg("The tasks have no namespaces\n")
+		return
+	}
+	if m.NamespaceSelected >= len(m.namespaceList()) {
+		m.NamespaceSelected = 0
+	}
+	m.SetState(StateNamespacesOverlay)
+}
+
+// handleNamespacesOverlayKey handles key presses in the namespaces list
+func (m Model) handleNamespacesOverlayKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	list := m.namespaceList()
+	switch action := m.resolveKey(msg); {
+	case action == ActionClose || m.KeyBindings.Matches(msg, ActionNamespaces):
+		m.SetState(StateNormal)
+	case action == ActionConfirm && m.NamespaceSelected < len(list):
+		m.SetState(StateNormal)
+		return m.runNamespace(list[m.NamespaceSelected].Name)
+	case action == ActionUp:
+		if m.NamespaceSelected > 0 {
+			m.NamespaceSelected--
+		}
+	case action == ActionDown:
+		if m.NamespaceSelected < len(list)-1 {
+			m.NamespaceSelected++
+		}
+	case (action == ActionHide || action == ActionPin) && m.NamespaceSelected < len(list):
+		ns := list[m.NamespaceSelected].Name
+		if action == ActionHide {
+			m.Namespaces.ToggleHidden(ns)
+		} else {
+			m.Namespaces.TogglePinned(ns)
+		}
+		if err := m.NamespaceStore.Save(m.catalogDir(), m.Namespaces); err != nil {
+			m.AppendErrorMsg(err.Error() + "\n")
+		}
+		m.applyTaskFilter()
+		m.UpdateTaskTable()
+	}
+	return m, nil
+}
+
+// RenderNamespacesOverlay renders the namespaces of the tasks with whether they are hidden or
+// pinned
+func RenderNamespacesOverlay(width, height int, list []namespaceCount, prefs namespaces.Prefs, selected int) string {
+	overlayWidth := int(float64(width) * 0.7)
+
+	content := TaskPickerTitleStyle.Render(fmt.Sprintf("Namespaces (%d)", len(list))) + "\n\n"
+	// show a window of the list around the selection
+	rows := max(height-12, 3)
+	first := min(max(selected-rows/2, 0), max(len(list)-rows, 0))
+	for i := first; i < len(list) && i < first+rows; i++ {
+		ns := list[i]
+		var states []string
+		if by, ok := namespaces.Match(prefs.Hidden, ns.Name); ok {
+			states = append(states, "hidden"+inherited(by, ns.Name))
+		}
+		if by, ok := namespaces.Match(prefs.Pinned, ns.Name); ok {
+			states = append(states, "pinned"+inherited(by, ns.Name))
+		}
+		line := fmt.Sprintf("%-30s %4d tasks  %s", ns.Name, ns.Tasks, strings.Join(states, ", "))
+		if i == selected {
+			content += TaskPickerSelectedMatchStyle(overlayWidth).Render(line) + "\n"
+		} else {
+			content += TaskPickerMatchStyle(overlayWidth).Render(line) + "\n"
+		}
+	}
+	content += "\n" + HelpStyle.Render("h hides or shows a namespace, p pins it to the top; kept for this project. enter runs its tasks")
+
+	overlay := GeneralOverlayStyle(overlayWidth).Render(content)
+
+	return placeOverlay(width, height, overlay)
+}
+
+// inherited notes the namespace a state comes from when it isn't ns itself
+func inherited(by, ns string) string {
+	if by == ns {
+		return ""
+	}
+	return " (" + by + ")"
+}
+
+// namespaceTasks returns the tasks of ns and of the namespaces nested in it, in listing order.
+// Internal tasks can't be run on their own, so they are left out.
+func (m Model) namespaceTasks(ns string) []task.Task {
+	var tasks []task.Task
+	for _, t := range m.AllTasks {
+		if _, ok := namespaces.Match([]string{ns}, t.Namespace()); ok && !t.Internal {
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks
+}
+
+// runNamespace adds the tasks of ns to the execution list and executes the list. A list being
+// executed runs them after the tasks queued before, and with the same error policy.
+func (m Model) runNamespace(ns string) (Model, tea.Cmd) {
+	if m.refuseReadOnly(ActionRunNamespace) {
+		return m, nil
+	}
+	tasks := m.namespaceTasks(ns)
+	if len(tasks) == 0 {
+		m.AppendErrorMsg("No tasks to run in " + ns + "\n")
+		return m, nil
+	}
+	added := 0
+	for _, t := range tasks {
+		if !slices.ContainsFunc(m.SelectedTasks, func(s task.Task) bool { return s.Id == t.Id }) {
+			m.SelectedTasks = append(m.SelectedTasks, t)
+			added++
+		}
+	}
+	m.AppendAppMsg(fmt.Sprintf("Added %d tasks of %s to the execution list\n", added, ns))
+	if m.ExecutingBatch {
+		return m, nil
+	}
+	if m.TasksLoading {
+		if binding, ok := m.KeyBindings.Binding(ActionExecuteBatch); ok {
+			m.AppendAppMsg(binding.Key + " executes them once the current run is over\n")
+		}
+		return m, nil
+	}
+	m.ExecutingBatch = true
+	m.CurrentBatchTaskIndex = 0
+	m.AppendAppMsg(fmt.Sprintf("Executing %d selected tasks\n", len(m.SelectedTasks)))
+	return m.executeNextSelectedTask(m.CurrentBatchTaskIndex)
+}
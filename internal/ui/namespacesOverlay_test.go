@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/namespaces"
+	"github.com/Aj4x/tash/internal/task"
+)
+
+func TestNamespacesAreHiddenAndPinnedPerProject(t *testing.T) {
+	store := namespaces.NewStore(t.TempDir())
+	m := NewModel(nil, config.Default())
+	m.HandleWindowResize(120, 40)
+	m.Config.WorkDir = t.TempDir()
+	m.UseNamespaceStore(store)
+	m.showTasks([]task.Task{{Id: "build"}, {Id: "ci:lint"}, {Id: "ci:docker:push"}, {Id: "db:migrate"}})
+
+	m.openNamespaces()
+	list := m.namespaceList()
+	if len(list) != 3 || list[0] != (namespaceCount{Name: "ci", Tasks: 2}) || list[1].Name != "ci:docker" {
+		t.Fatalf("Expected ci, ci:docker and db, got %+v", list)
+	}
+	updated, _ := m.handleNamespacesOverlayKey(runes("h"))
+	m = updated.(Model)
+	for range 2 {
+		updated, _ = m.handleNamespacesOverlayKey(runes("j"))
+		m = updated.(Model)
+	}
+	updated, _ = m.handleNamespacesOverlayKey(runes("p"))
+	m = updated.(Model)
+
+	var ids []string
+	for _, t := range m.Tasks {
+		ids = append(ids, t.Id)
+	}
+	if !slices.Equal(ids, []string{"db:migrate", "build"}) || m.HiddenCount != 2 {
+		t.Errorf("Expected ci hidden and db pinned first, got %v with %d hidden", ids, m.HiddenCount)
+	}
+	overlay := RenderNamespacesOverlay(160, 40, m.namespaceList(), m.Namespaces, m.NamespaceSelected)
+	if !strings.Contains(overlay, "hidden (ci)") {
+		t.Errorf("Expected ci:docker to be shown hidden by ci, got:\n%s", overlay)
+	}
+
+	// the choices are kept for the next start in the project
+	restarted := NewModel(nil, config.Default())
+	restarted.Config.WorkDir = m.Config.WorkDir
+	restarted.UseNamespaceStore(store)
+	if !slices.Equal(restarted.Namespaces.Hidden, []string{"ci"}) || !slices.Equal(restarted.Namespaces.Pinned, []string{"db"}) {
+		t.Errorf("Expected the choices to be kept, got %+v", restarted.Namespaces)
+	}
+}
+
+func TestRunNamespaceQueuesItsTasks(t *testing.T) {
+	m := NewModel(nil, config.Default())
+	m.HandleWindowResize(120, 40)
+	m.RunInputs = map[string]runInputs{}
+	m.showTasks([]task.Task{
+		{Id: "build"}, {Id: "lint:go"}, {Id: "lint:setup", Internal: true}, {Id: "lint:docs:md"}, {Id: "test:unit"},
+	})
+	m.Table.SetCursor(1)
+
+	m = pressKeys(m, runes("A"))
+	var queued []string
+	for _, t := range m.SelectedTasks {
+		queued = append(queued, t.Id)
+	}
+	if !slices.Equal(queued, []string{"lint:go", "lint:docs:md"}) {
+		t.Fatalf("Expected the runnable lint tasks in order, got %v", queued)
+	}
+	if !m.ExecutingBatch || m.RunningTaskId != "lint:go" {
+		t.Errorf("Expected the batch to start with lint:go, got running %q", m.RunningTaskId)
+	}
+
+	// a namespace run while the batch executes is queued after it
+	m, _ = m.runNamespace("test")
+	if len(m.SelectedTasks) != 3 || m.SelectedTasks[2].Id != "test:unit" || m.RunningTaskId != "lint:go" {
+		t.Errorf("Expected test:unit to be queued behind the running batch, got %+v", m.SelectedTasks)
+	}
+}
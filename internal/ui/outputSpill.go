@@ -0,0 +1,162 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// defaultOutputMemoryLines is how many output lines are kept in memory when the config doesn't
+// say otherwise
+const defaultOutputMemoryLines = 50000
+
+// outputSpill is the temp file holding the output lines dropped from memory, without colors
+type outputSpill struct {
+	file *os.File
+	out  *bufio.Writer
+}
+
+// outputMemoryLines returns how many output lines are kept in memory
+func (m Model) outputMemoryLines() int {
+	if m.Config.OutputMemoryLines > 0 {
+		return m.Config.OutputMemoryLines
+	}
+	return defaultOutputMemoryLines
+}
+
+// lineCount returns the number of output lines, including those spilled to disk
+func (m *Model) lineCount() int {
+	return m.spilledLines + len(m.outputLines)
+}
+
+// spillNotice is the first line of the output once earlier lines were spilled to disk
+func (m *Model) spillNotice() string {
+	if m.spilledLines == 0 {
+		return ""
+	}
+	if m.spill == nil {
+		return HelpStyle.Render(fmt.Sprintf("… %d earlier lines dropped", m.spilledLines))
+	}
+	return HelpStyle.Render(fmt.Sprintf("… %d earlier lines moved to %s, press L to view the full output",
+		m.spilledLines, m.spill.file.Name()))
+}
+
+// spillOutput moves the oldest output lines to the spill file once more lines than the memory
+// limit are held. A quarter of the limit is kept free, so lines aren't spilled one at a time.
+func (m *Model) spillOutput() {
+	limit := m.outputMemoryLines()
+	if len(m.outputLines) <= limit {
+		return
+	}
+	if m.spill == nil {
+		f, err := os.CreateTemp("", "tash-output-*.log")
+		if err != nil {
+			slog.Warn("unable to spill output to disk, dropping the oldest lines", "error", err)
+		} else {
+			m.spill = &outputSpill{file: f, out: bufio.NewWriter(f)}
+		}
+	}
+	n := len(m.outputLines) - limit*3/4
+	if m.spill != nil {
+		for _, line := range m.outputLines[:n] {
+			m.spill.out.WriteString(ansiPattern.ReplaceAllString(line, "") + "\n")
+		}
+		if err := m.spill.out.Flush(); err != nil {
+			slog.Warn("unable to spill output to disk", "error", err)
+		}
+	}
+	// the remaining lines are copied so the spilled ones can be freed
+	m.outputLines = slices.Clone(m.outputLines[n:])
+	m.spilledLines += n
+	m.output.reset(m.spillNotice() + "\n" + strings.Join(m.outputLines, "\n"))
+}
+
+// removeSpill deletes the spill file
+func (m *Model) removeSpill() {
+	if m.spill == nil {
+		return
+	}
+	_ = m.spill.file.Close()
+	_ = os.Remove(m.spill.file.Name())
+	m.spill = nil
+	m.spilledLines = 0
+}
+
+// pagerExitedMsg is sent when the pager showing the full output exits
+type pagerExitedMsg struct {
+	path string
+	err  error
+}
+
+// pagerArgs returns the command line showing path with the user's pager: $PAGER, or the
+// platform's default pager
+func pagerArgs(path string) []string {
+	args := strings.Fields(os.Getenv("PAGER"))
+	if len(args) == 0 {
+		args = []string{"less"}
+		if runtime.GOOS == "windows" {
+			args = []string{"more"}
+		}
+	}
+	return append(args, path)
+}
+
+// writeFullOutput writes the spilled and the in-memory output, without colors, to w
+func (m *Model) writeFullOutput(w io.Writer) error {
+	if m.spill != nil {
+		if _, err := m.spill.file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		_, err := io.Copy(w, m.spill.file)
+		if _, seekErr := m.spill.file.Seek(0, io.SeekEnd); err == nil {
+			err = seekErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	out := bufio.NewWriter(w)
+	for _, line := range m.outputLines {
+		out.WriteString(ansiPattern.ReplaceAllString(line, "") + "\n")
+	}
+	return out.Flush()
+}
+
+// openFullOutput shows the whole output, including the lines spilled to disk, in the pager
+func (m *Model) openFullOutput() tea.Cmd {
+	f, err := os.CreateTemp("", "tash-full-output-*.log")
+	if err != nil {
+		m.AppendErrorMsg("Unable to write the full output: " + err.Error())
+		return nil
+	}
+	err = m.writeFullOutput(f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		m.AppendErrorMsg("Unable to write the full output: " + err.Error())
+		return nil
+	}
+	args := pagerArgs(f.Name())
+	return tea.ExecProcess(exec.Command(args[0], args[1:]...), func(err error) tea.Msg {
+		return pagerExitedMsg{path: f.Name(), err: err}
+	})
+}
+
+// handlePagerExited removes the copy of the output shown by the pager
+func (m Model) handlePagerExited(msg pagerExitedMsg) (Model, tea.Cmd) {
+	_ = os.Remove(msg.path)
+	if msg.err != nil {
+		m.AppendErrorMsg("Pager exited with an error: " + msg.err.Error())
+	}
+	return m, nil
+}
@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// newSpillModel returns a model keeping at most 8 output lines in memory, with lines 0 to n-1
+// of a run appended
+func newSpillModel(t *testing.T, n int) Model {
+	t.Helper()
+	m := newNavigationModel(1)
+	m.Config.OutputMemoryLines = 8
+	m.HandleWindowResize(120, 40)
+	t.Cleanup(m.removeSpill)
+	m.RunningTaskId = "build"
+	m.openFold("build", foldRun)
+	for i := range n {
+		m.appendTaskOutput(fmt.Sprintf("line %d", i), OutputStyle)
+	}
+	return m
+}
+
+func TestOutputIsSpilledToDisk(t *testing.T) {
+	m := newSpillModel(t, 20)
+	if m.lineCount() != 20 || len(m.outputLines) > 8 {
+		t.Fatalf("Expected 20 lines with at most 8 in memory, got %d with %d in memory", m.lineCount(), len(m.outputLines))
+	}
+	if m.spill == nil {
+		t.Fatal("Expected a spill file")
+	}
+	if !strings.Contains(m.output.String(), "earlier lines moved to") || !strings.HasSuffix(m.output.String(), "line 19") {
+		t.Errorf("Expected the notice and the latest lines, got %q", m.output.String())
+	}
+
+	var full bytes.Buffer
+	if err := m.writeFullOutput(&full); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(full.String(), "\n"), "\n")
+	if len(lines) != 20 || lines[0] != "line 0" || lines[19] != "line 19" {
+		t.Errorf("Expected the full output in order, got %q", lines)
+	}
+
+	// lines appended after the full output was read still go to the end of the spill file
+	for i := 20; i < 30; i++ {
+		m.appendTaskOutput(fmt.Sprintf("line %d", i), OutputStyle)
+	}
+	full.Reset()
+	if err := m.writeFullOutput(&full); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(full.String(), "\n"); got != 30 {
+		t.Errorf("Expected 30 lines, got %d", got)
+	}
+
+	path := m.spill.file.Name()
+	m.clearOutput()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected the spill file to be removed, got %v", err)
+	}
+	if m.lineCount() != 0 {
+		t.Errorf("Expected no output, got %d lines", m.lineCount())
+	}
+}
+
+func TestFoldsSpanningSpilledOutput(t *testing.T) {
+	m := newSpillModel(t, 20)
+	if got := m.displayIndex(0); got != 0 {
+		t.Errorf("Expected a spilled line to map to the notice, got %d", got)
+	}
+	m.toggleFold()
+	m.renderOutput()
+	view := m.Viewport.View()
+	if !strings.Contains(view, "build: ") || strings.Contains(view, "line 19") {
+		t.Errorf("Expected the run to be folded, got %q", view)
+	}
+	if got := m.displayIndex(0); got != 1 {
+		t.Errorf("Expected a spilled line to map to its fold, got %d", got)
+	}
+}
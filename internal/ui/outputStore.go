@@ -0,0 +1,37 @@
+package ui
+
+import "strings"
+
+// outputStore holds the content of the output viewport. The model and its copies share it, so it
+// is owned by the update loop: only Update, View and the handlers they call change or read it.
+// Commands running on their own goroutines never touch it, and report back with messages instead.
+type outputStore struct {
+	// the content is built in a strings.Builder, as concatenating to a string would copy all the
+	// output for every line
+	content strings.Builder
+}
+
+// newOutputStore returns an empty store
+func newOutputStore() *outputStore {
+	return &outputStore{}
+}
+
+// String returns the content
+func (s *outputStore) String() string {
+	return s.content.String()
+}
+
+// append adds lines to the content, each preceded by a newline
+func (s *outputStore) append(lines []string) {
+	for _, line := range lines {
+		s.content.WriteString("\n")
+		s.content.WriteString(line)
+	}
+}
+
+// reset replaces the content with text
+func (s *outputStore) reset(text string) {
+	// strings returned before stay valid, as Reset drops the buffer rather than reusing it
+	s.content.Reset()
+	s.content.WriteString(text)
+}
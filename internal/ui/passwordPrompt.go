@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Aj4x/tash/internal/task"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// PasswordPrompt holds the password typed for a prompt of the running task. The password is
+// only ever written to the task's stdin: it isn't shown, appended to the output or logged.
+type PasswordPrompt struct {
+	TaskId string
+	Prompt string
+	Input  io.WriteCloser // Stdin of the task
+	Secret []byte
+}
+
+// handlePasswordMsg opens the masked input for the password the running task prompts for
+func (m Model) handlePasswordMsg(msg task.Message) (Model, tea.Cmd) {
+	if msg.Input() == nil {
+		return m, nil
+	}
+	m.PasswordPrompt = PasswordPrompt{TaskId: m.RunningTaskId, Prompt: msg.Output(), Input: msg.Input()}
+	m.SetState(StatePasswordPrompt)
+	return m, nil
+}
+
+// handlePasswordPromptKey handles key presses while entering a password
+func (m Model) handlePasswordPromptKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := &m.PasswordPrompt
+	switch action := m.resolveKey(msg); {
+	case action == ActionClose:
+		// the task reads the end of its input, failing the prompt rather than waiting on it
+		if err := p.Input.Close(); err != nil {
+			m.AppendErrorMsg("Unable to decline the prompt: " + err.Error())
+		}
+		m.AppendErrorMsg("Password prompt declined")
+		m.closePasswordPrompt()
+	case action == ActionConfirm:
+		_, err := p.Input.Write(p.Secret)
+		if err == nil {
+			_, err = io.WriteString(p.Input, "\n")
+		}
+		if err != nil {
+			m.AppendErrorMsg("Unable to pass the password to the task: " + err.Error())
+		}
+		m.closePasswordPrompt()
+	case IsKeyMatch(msg, "backspace"):
+		_, size := utf8.DecodeLastRune(p.Secret)
+		clear(p.Secret[len(p.Secret)-size:])
+		p.Secret = p.Secret[:len(p.Secret)-size]
+	case msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace:
+		// spaces carry their rune too
+		p.Secret = append(p.Secret, string(msg.Runes)...)
+	}
+	return m, nil
+}
+
+// closePasswordPrompt wipes the typed password and returns to the main view
+func (m *Model) closePasswordPrompt() {
+	clear(m.PasswordPrompt.Secret)
+	m.PasswordPrompt = PasswordPrompt{}
+	m.SetState(StateNormal)
+}
+
+// RenderPasswordPrompt renders the masked input for the password the running task prompts for
+func RenderPasswordPrompt(width, height int, p PasswordPrompt) string {
+	overlayWidth := int(float64(width) * 0.7)
+
+	content := TaskPickerTitleStyle.Render("Password for "+p.TaskId) + "\n\n"
+	content += p.Prompt + "\n\n"
+	content += TaskPickerInputStyle(overlayWidth).Render(strings.Repeat("•", utf8.RuneCount(p.Secret))) + "\n\n"
+	content += HelpStyle.Render("Written to the task's input, never shown or logged; enter to pass it on, esc to decline")
+
+	overlay := GeneralOverlayStyle(overlayWidth).Render(content)
+
+	return placeOverlay(width, height, overlay)
+}
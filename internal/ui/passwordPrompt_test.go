@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/task"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// stdinRecorder stands in for the stdin of a task
+type stdinRecorder struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (s *stdinRecorder) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestPasswordPromptWritesTheMaskedPasswordToTheTask(t *testing.T) {
+	m := NewModel(nil, config.Default())
+	m.HandleWindowResize(120, 40)
+	m.RunningTaskId = "install"
+	if !m.ExecOptions("install").PasswordInput {
+		t.Error("Expected tasks to run with password input")
+	}
+
+	stdin := &stdinRecorder{}
+	m, _ = m.handleBusMessage(task.TypeTaskPassword.Message().SetOutput("[sudo] password for ann:").SetInput(stdin))
+	if m.State != StatePasswordPrompt {
+		t.Fatalf("Expected the password prompt to open, got state %s", m.State)
+	}
+	for _, k := range []tea.KeyMsg{runes("hunter"), {Type: tea.KeySpace, Runes: []rune(" ")}, runes("23"), {Type: tea.KeyBackspace}} {
+		updated, _ := m.handlePasswordPromptKey(k)
+		m = updated.(Model)
+	}
+	overlay := RenderPasswordPrompt(m.Width, m.Height, m.PasswordPrompt)
+	if strings.Contains(overlay, "hunter") || !strings.Contains(overlay, strings.Repeat("•", 8)) {
+		t.Errorf("Expected the password to be masked, got:\n%s", overlay)
+	}
+	updated, _ := m.handlePasswordPromptKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if got := stdin.String(); got != "hunter 2\n" {
+		t.Errorf("Expected the password to be written to the task, got %q", got)
+	}
+	if m.State != StateNormal || m.PasswordPrompt.Secret != nil || strings.Contains(m.output.String(), "hunter") {
+		t.Errorf("Expected the password to be forgotten, got state %s", m.State)
+	}
+
+	// declining closes the task's input
+	m, _ = m.handleBusMessage(task.TypeTaskPassword.Message().SetOutput("Password:").SetInput(stdin))
+	updated, _ = m.handlePasswordPromptKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if m = updated.(Model); !stdin.closed || m.State != StateNormal {
+		t.Errorf("Expected esc to close the task's input, got state %s", m.State)
+	}
+
+	cfg := config.Default()
+	cfg.DisablePasswordPrompts = true
+	if NewModel(nil, cfg).ExecOptions("install").PasswordInput {
+		t.Error("Expected the config to opt out of password input")
+	}
+}
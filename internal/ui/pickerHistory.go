@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/Aj4x/tash/internal/frecency"
+	"github.com/Aj4x/tash/internal/task"
+)
+
+// UsePickStore keeps the tasks chosen in the picker of the project in store, and loads those
+// chosen before
+func (m *Model) UsePickStore(store frecency.Store) {
+	m.PickStore = store
+	picks, err := store.Load(m.catalogDir())
+	if err != nil {
+		slog.Warn("Unable to load the picker history", "error", err)
+		return
+	}
+	m.Picks = picks
+}
+
+// pickerTasks returns the tasks the picker lists before anything is typed: all of them, the
+// most frecently picked first
+func (m Model) pickerTasks() []task.Task {
+	return frecency.Sort(m.Picks, m.AllTasks, func(t task.Task) string { return t.Id }, time.Now())
+}
+
+// recordPick remembers that the task id was chosen in the picker
+func (m *Model) recordPick(id string) {
+	if m.Picks == nil {
+		m.Picks = frecency.Picks{}
+	}
+	m.Picks.Record(id, time.Now())
+	if err := m.PickStore.Save(m.catalogDir(), m.Picks); err != nil {
+		m.AppendErrorMsg(err.Error() + "\n")
+	}
+}
@@ -0,0 +1,42 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/frecency"
+	"github.com/Aj4x/tash/internal/task"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPickerListsTheMostFrecentTasksFirst(t *testing.T) {
+	store := frecency.NewStore(t.TempDir())
+	m := NewModel(nil, config.Default())
+	m.HandleWindowResize(120, 40)
+	m.UsePickStore(store)
+	m.AllTasks = []task.Task{{Id: "build"}, {Id: "deploy"}, {Id: "test"}}
+
+	pick := func(m Model, input string) Model {
+		updated, _ := m.handleNormalKey(runes("/"))
+		m = updated.(Model)
+		m.TaskPickerInput = input
+		m.updateTaskPickerMatches()
+		updated, _ = m.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+		return updated.(Model)
+	}
+	m = pick(m, "deploy")
+	m = pick(m, "test")
+	m = pick(m, "test")
+
+	updated, _ := m.handleNormalKey(runes("/"))
+	m = updated.(Model)
+	if got := m.TaskPickerMatches; len(got) != 3 || got[0].Id != "test" || got[1].Id != "deploy" || got[2].Id != "build" {
+		t.Errorf("Expected the picked tasks first, most picked first, got %v", got)
+	}
+
+	restarted := NewModel(nil, config.Default())
+	restarted.UsePickStore(store)
+	if restarted.Picks["test"].Count != 2 {
+		t.Errorf("Expected the picks to be kept across starts, got %+v", restarted.Picks)
+	}
+}
@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// plainStatusLines is the number of lines the plain layout reserves for labels, status
+// lines and the hint strip
+const plainStatusLines = 6
+
+// resizePlain splits the screen height between the task list and the output in plain mode,
+// which stacks them instead of placing them side by side
+func (m *Model) resizePlain() {
+	available := max(m.Height-plainStatusLines, 2)
+	listHeight := max(available/3, 1)
+
+	m.Table.SetWidth(m.Width)
+	m.Table.SetHeight(listHeight)
+
+	m.Viewport.Width = m.Width
+	m.Viewport.Height = max(available-listHeight, 1)
+}
+
+// renderPlainMain renders the task list and output as labelled, unbordered sections, marking
+// the selected task and the focused section with text so they can be read out
+func (m Model) renderPlainMain() string {
+	var b strings.Builder
+
+	focus := func(c Control) string {
+		if m.Focused == c {
+			return ", focused"
+		}
+		return ""
+	}
+
+	fmt.Fprintf(&b, "Tasks (%d%s):\n", len(m.Tasks), focus(ControlTable))
+	if len(m.Tasks) == 0 {
+		b.WriteString("  No tasks loaded\n")
+	}
+
+	// Show a window of tasks around the cursor
+	visible := max(m.Table.Height(), 1)
+	cursor := m.Table.Cursor()
+	start := max(0, min(cursor-visible/2, len(m.Tasks)-visible))
+	end := min(len(m.Tasks), start+visible)
+	for i := start; i < end; i++ {
+		t := m.Tasks[i]
+		marker := "  "
+		if i == cursor {
+			marker = "> "
+		}
+		line := marker + t.Id
+		if t.Desc != "" {
+			line += ": " + t.Desc
+		}
+		b.WriteString(truncateRunes(line, m.Width) + "\n")
+	}
+
+	status := "idle"
+	switch {
+	case m.TaskPaused:
+		status = fmt.Sprintf("task %s paused", m.RunningTaskId)
+	case m.TaskRunning:
+		status = fmt.Sprintf("running task %s", m.RunningTaskId)
+	case m.TasksLoading:
+		status = "loading"
+	}
+	if flag := m.Verbosity.Flag(); flag != "" {
+		status += ", runs with " + flag
+	}
+	fmt.Fprintf(&b, "Output (%s%s):\n", status, focus(ControlViewport))
+	b.WriteString(m.Viewport.View())
+
+	return b.String()
+}
+
+// announce writes a line describing a change in the UI to the output in plain mode, so a
+// screen reader reads it out
+func (m *Model) announce(text string) {
+	if m.Config.Plain {
+		m.AppendAppMsg(text + "\n")
+	}
+}
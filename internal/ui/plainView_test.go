@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/task"
+)
+
+func TestPlainViewIsLinear(t *testing.T) {
+	ApplyTheme(PlainTheme)
+	defer ApplyTheme(DefaultTheme)
+
+	cfg := config.Default()
+	cfg.Plain = true
+	m := NewModel(nil, cfg)
+	m.Tasks = []task.Task{{Id: "build", Desc: "Build it"}, {Id: "test"}}
+	m.UpdateTaskTable()
+	m.Initialised = true
+	m.HandleWindowResize(80, 24)
+
+	view := m.View()
+	if !strings.Contains(view, "Tasks (2, focused):") || !strings.Contains(view, "> build: Build it") {
+		t.Errorf("Expected labelled tasks with the selection marked, got:\n%s", view)
+	}
+	if strings.ContainsAny(view, "╭│─") {
+		t.Errorf("Expected no box-drawing borders, got:\n%s", view)
+	}
+}
+
+func TestPlainModeAnnouncesStateChanges(t *testing.T) {
+	cfg := config.Default()
+	cfg.Plain = true
+	m := NewModel(nil, cfg)
+	m.HandleWindowResize(80, 24)
+
+	m.SetState(StateHelpOverlay)
+	m.SetState(StateNormal)
+	if !strings.Contains(m.output.String(), "Opened help") || !strings.Contains(m.output.String(), "Closed help") {
+		t.Errorf("Expected state changes to be announced, got %q", m.output.String())
+	}
+}
@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/preflight"
+	"github.com/Aj4x/tash/internal/task"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Preflight holds the outcome of the project's pre-flight checks
+type Preflight struct {
+	Results  []preflight.Result
+	Checking bool
+	Error    string // Why the checks couldn't be read
+}
+
+// preflightMsg carries the results of the pre-flight checks
+type preflightMsg struct {
+	results []preflight.Result
+	err     error
+}
+
+// runPreflight runs the pre-flight checks declared by the project in the background
+func (m Model) runPreflight() tea.Cmd {
+	if m.Config.Provider == task.ProviderDemo {
+		return nil
+	}
+	cfg, env := m.Config.Preflight, ExecEnvironment(m.Config)
+	return func() tea.Msg {
+		checks, err := preflight.Checks(cfg)
+		if err != nil {
+			return preflightMsg{err: err}
+		}
+		return preflightMsg{results: preflight.Run(context.Background(), env, checks)}
+	}
+}
+
+// handlePreflightMsg keeps the results of the checks, showing them when some failed
+func (m Model) handlePreflightMsg(msg preflightMsg) (Model, tea.Cmd) {
+	p := &m.Preflight
+	p.Checking = false
+	p.Error = ""
+	if msg.err != nil {
+		p.Error = msg.err.Error()
+		m.AppendErrorMsg("Unable to run the pre-flight checks: " + p.Error)
+		return m, nil
+	}
+	p.Results = msg.results
+	var failed int
+	for _, r := range p.Results {
+		if r.Err != nil {
+			failed++
+		}
+	}
+	if failed == 0 {
+		return m, nil
+	}
+	m.AppendErrorMsg(fmt.Sprintf("%d of %d pre-flight checks failed", failed, len(p.Results)))
+	if !m.Headless && m.State == StateNormal {
+		m.SetState(StatePreflight)
+	}
+	return m, nil
+}
+
+// refusePreflight reports whether taskId can't run as checks it depends on failed, telling which
+// and showing the checklist
+func (m *Model) refusePreflight(taskId string) bool {
+	failed := preflight.Failed(m.Preflight.Results, taskId)
+	if len(failed) == 0 {
+		return false
+	}
+	labels := make([]string, len(failed))
+	for i, r := range failed {
+		labels[i] = r.Check.Label()
+	}
+	m.AppendErrorMsg(fmt.Sprintf("Not running %s, it needs %s, which failed the pre-flight checks", taskId, strings.Join(labels, ", ")))
+	if !m.Headless && m.State == StateNormal {
+		m.SetState(StatePreflight)
+	}
+	return true
+}
+
+// openPreflight shows the pre-flight checklist
+func (m *Model) openPreflight() {
+	if len(m.Preflight.Results) == 0 && m.Preflight.Error == "" {
+		m.AppendAppMsg("The project declares no pre-flight checks in " + config.ProjectFileName + "\n")
+		return
+	}
+	m.SetState(StatePreflight)
+}
+
+// handlePreflightKey handles key presses while the pre-flight checklist is shown
+func (m Model) handlePreflightKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.resolveKey(msg) {
+	case ActionClose:
+		m.SetState(StateNormal)
+	case ActionRefresh:
+		if !m.Preflight.Checking {
+			m.Preflight.Checking = true
+			return m, m.runPreflight()
+		}
+	}
+	return m, nil
+}
+
+// RenderPreflight renders the checklist of the pre-flight checks with their outcome
+func RenderPreflight(width, height int, p Preflight) string {
+	overlayWidth := int(float64(width) * 0.7)
+	content := TaskPickerTitleStyle.Render("Pre-flight checks") + "\n\n"
+	if p.Error != "" {
+		content += ErrorMsgStyle.Render(p.Error) + "\n"
+	}
+	for _, r := range p.Results {
+		line := AppMsgStyle.Render("✓") + " " + r.Check.Label()
+		detail := r.Detail
+		if r.Err != nil {
+			line = ErrorMsgStyle.Render("✗") + " " + r.Check.Label()
+			detail = r.Err.Error()
+		}
+		if len(r.Check.Tasks) > 0 {
+			line += HelpStyle.Render(" (for " + strings.Join(r.Check.Tasks, ", ") + ")")
+		}
+		content += line + "\n"
+		if detail != "" {
+			content += "    " + HelpStyle.Render(detail) + "\n"
+		}
+	}
+	help := "ctrl+r check again, esc close"
+	if p.Checking {
+		help = "Checking…"
+	}
+	content += "\n" + HelpStyle.Render(help)
+	return placeOverlay(width, height, GeneralOverlayStyle(overlayWidth).Render(content))
+}
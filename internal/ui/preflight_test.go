@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/preflight"
+	"github.com/Aj4x/tash/internal/task"
+)
+
+func TestPreflightRefusesDependentTasks(t *testing.T) {
+	m := NewModel(nil, config.Default())
+	m.HandleWindowResize(120, 40)
+	m.AllTasks = []task.Task{{Id: "build"}, {Id: "deploy:prod"}}
+
+	m, _ = m.handlePreflightMsg(preflightMsg{results: []preflight.Result{
+		{Check: preflight.Check{Binary: "go"}, Detail: "/usr/bin/go"},
+		{Check: preflight.Check{Name: "AWS credentials", Env: "AWS_PROFILE", Tasks: []string{"deploy:*"}}, Err: errors.New("$AWS_PROFILE isn't set")},
+	}})
+	if m.State != StatePreflight {
+		t.Fatalf("Expected the checklist to open when a check fails, got %s", m.State)
+	}
+	view := RenderPreflight(m.Width, m.Height, m.Preflight)
+	if !strings.Contains(view, "✓ go") || !strings.Contains(view, "$AWS_PROFILE isn't set") {
+		t.Errorf("Expected the outcome of each check, got %q", view)
+	}
+	m.SetState(StateNormal)
+
+	if cmd := m.runTask("deploy:prod"); cmd != nil || m.RunningTaskId != "" {
+		t.Error("Expected the task depending on the failed check not to run")
+	}
+	if m.State != StatePreflight {
+		t.Error("Expected the checklist to show why")
+	}
+	m.SetState(StateNormal)
+	if cmd := m.runTask("build"); cmd == nil || m.RunningTaskId != "build" {
+		t.Error("Expected tasks not depending on the check to run")
+	}
+}
+
+func TestPreflightPassingStaysClosed(t *testing.T) {
+	m := NewModel(nil, config.Default())
+	m, _ = m.handlePreflightMsg(preflightMsg{results: []preflight.Result{{Check: preflight.Check{Binary: "go"}}}})
+	if m.State != StateNormal {
+		t.Errorf("Expected the checklist to stay closed, got %s", m.State)
+	}
+}
@@ -0,0 +1,131 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strings"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/problems"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// loadProblemMatchers compiles the configured problem matchers, or returns the built-in ones
+// when none are configured
+func loadProblemMatchers(cfg config.Config) []problems.Matcher {
+	if len(cfg.ProblemMatchers) == 0 {
+		return problems.Defaults()
+	}
+	var matchers []problems.Matcher
+	for _, pm := range cfg.ProblemMatchers {
+		m, err := problems.New(pm.Name, pm.Pattern)
+		if err != nil {
+			slog.Warn("ignoring problem matcher", "error", err)
+			continue
+		}
+		matchers = append(matchers, m)
+	}
+	return matchers
+}
+
+// scanProblems records the problems reported in output, which is about to be appended to the
+// output lines
+func (m *Model) scanProblems(output string) {
+	if m.RunningTaskId == "" || len(m.problemMatchers) == 0 {
+		return
+	}
+	line := m.lineCount()
+	for _, text := range strings.Split(output, "\n") {
+		if p, ok := problems.Match(m.problemMatchers, ansiPattern.ReplaceAllString(text, "")); ok {
+			p.Task = m.RunningTaskId
+			p.OutputLine = line
+			m.Problems = append(m.Problems, p)
+		}
+		line += len(TextWrap(text, m.Viewport.Width))
+	}
+}
+
+// openProblems shows the problems found in the output
+func (m *Model) openProblems() {
+	if m.ProblemSelected >= len(m.Problems) {
+		m.ProblemSelected = 0
+	}
+	m.SetState(StateProblemsOverlay)
+}
+
+// RenderProblemsOverlay renders the list of problems found in the output
+func RenderProblemsOverlay(width, height int, list []problems.Problem, selected int) string {
+	overlayWidth := int(float64(width) * 0.8)
+
+	content := TaskPickerTitleStyle.Render(fmt.Sprintf("Problems (%d)", len(list))) + "\n\n"
+	if len(list) == 0 {
+		content += "No problems found in the output.\n"
+	}
+	// show a window of the list around the selection
+	rows := max(height-12, 3)
+	first := min(max(selected-rows/2, 0), max(len(list)-rows, 0))
+	for i := first; i < len(list) && i < first+rows; i++ {
+		p := list[i]
+		line := fmt.Sprintf("%-7s %s  %s", p.Severity, p.Location(), p.Message)
+		if i == selected {
+			content += TaskPickerSelectedMatchStyle(overlayWidth).Render(line) + "\n"
+		} else {
+			content += TaskPickerMatchStyle(overlayWidth).Render(line) + "\n"
+		}
+	}
+	content += "\n" + HelpStyle.Render("enter jumps to the output line, e opens the file in $EDITOR")
+
+	overlay := GeneralOverlayStyle(overlayWidth).Render(content)
+
+	return placeOverlay(width, height, overlay)
+}
+
+// handleProblemsOverlayKey handles key presses in the problems list
+func (m Model) handleProblemsOverlayKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.resolveKey(msg) {
+	case ActionClose:
+		m.SetState(StateNormal)
+	case ActionUp:
+		if m.ProblemSelected > 0 {
+			m.ProblemSelected--
+		}
+	case ActionDown:
+		if m.ProblemSelected < len(m.Problems)-1 {
+			m.ProblemSelected++
+		}
+	case ActionConfirm:
+		if m.ProblemSelected < len(m.Problems) {
+			m.SetState(StateNormal)
+			m.jumpToOutputLine(m.Problems[m.ProblemSelected].OutputLine)
+		}
+	case ActionOpenFile:
+		if m.ProblemSelected < len(m.Problems) {
+			m.SetState(StateNormal)
+			return m, m.openProblemFile(m.Problems[m.ProblemSelected])
+		}
+	}
+	return m, nil
+}
+
+// openProblemFile opens the file of a problem in $EDITOR at the problem's line
+func (m *Model) openProblemFile(p problems.Problem) tea.Cmd {
+	args := editorArgs(p.File, p.Line)
+	m.AppendAppMsg("Opening " + p.Location() + " with " + args[0] + "\n")
+	return tea.ExecProcess(exec.Command(args[0], args[1:]...), func(err error) tea.Msg {
+		return editorExitedMsg{err: err}
+	})
+}
+
+// jumpToOutputLine focuses the output and scrolls it to an output line, unfolding the folds
+// hiding it
+func (m *Model) jumpToOutputLine(line int) {
+	for i, f := range m.Folds {
+		if f.Collapsed && line >= f.Start && line < m.foldEnd(f) {
+			m.Folds[i].Collapsed = false
+		}
+	}
+	m.renderOutput()
+	m.Focused = ControlViewport
+	m.Viewport.SetYOffset(m.displayIndex(line))
+}
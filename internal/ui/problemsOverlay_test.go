@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTaskOutputProblemsAreListed(t *testing.T) {
+	m := newNavigationModel(1)
+	m.HandleWindowResize(120, 40)
+	m.RunningTaskId = "build"
+	m.appendTaskOutput("go build ./...", OutputStyle)
+	m.appendTaskOutput("main.go:12:5: undefined: foo", ErrorMsgStyle)
+	m.appendTaskOutput("\x1b[31mutil.go:3:1: syntax error\x1b[0m", ErrorMsgStyle)
+
+	if len(m.Problems) != 2 {
+		t.Fatalf("Expected 2 problems, got %d", len(m.Problems))
+	}
+	p := m.Problems[1]
+	if p.File != "util.go" || p.Line != 3 || p.Task != "build" {
+		t.Errorf("Expected util.go:3 from build, got %s:%d from %s", p.File, p.Line, p.Task)
+	}
+	if m.Problems[0].OutputLine != 1 || p.OutputLine != 2 {
+		t.Errorf("Expected output lines 1 and 2, got %d and %d", m.Problems[0].OutputLine, p.OutputLine)
+	}
+
+	m.clearOutput()
+	if len(m.Problems) != 0 {
+		t.Errorf("Expected clearing the output to clear the problems, got %d", len(m.Problems))
+	}
+}
+
+func TestProblemsOverlayJumpsToOutputLine(t *testing.T) {
+	m := newNavigationModel(1)
+	m.HandleWindowResize(120, 10)
+	m.RunningTaskId = "build"
+	m.openFold("build", foldRun)
+	for range 30 {
+		m.appendTaskOutput("compiling", OutputStyle)
+	}
+	m.appendTaskOutput("main.go:12:5: undefined: foo", ErrorMsgStyle)
+	for range 30 {
+		m.appendTaskOutput("compiling", OutputStyle)
+	}
+	m.closeFolds(foldRun)
+	m.toggleAllFolds()
+
+	m = pressKeys(m, runes("P"))
+	if m.State != StateProblemsOverlay {
+		t.Fatalf("Expected the problems overlay, got %s", m.State)
+	}
+	updated, _ := m.handleProblemsOverlayKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+
+	if m.State != StateNormal || m.Focused != ControlViewport {
+		t.Errorf("Expected the output to be focused, got %s with %v focused", m.State, m.Focused)
+	}
+	if m.anyCollapsed() {
+		t.Error("Expected the fold hiding the problem to be unfolded")
+	}
+	if want := m.displayIndex(m.Problems[0].OutputLine); m.Viewport.YOffset != want {
+		t.Errorf("Expected the output scrolled to line %d, got %d", want, m.Viewport.YOffset)
+	}
+}
+
+func TestInvalidProblemMatchersAreSkipped(t *testing.T) {
+	cfg := config.Default()
+	cfg.ProblemMatchers = []config.ProblemMatcherConfig{
+		{Name: "broken", Pattern: "("},
+		{Name: "nofile", Pattern: "^error: (?P<message>.*)$"},
+		{Name: "py", Pattern: `^(?P<file>\S+\.py):(?P<line>\d+): (?P<message>.*)$`},
+	}
+	matchers := loadProblemMatchers(cfg)
+	if len(matchers) != 1 || matchers[0].Name != "py" {
+		t.Errorf("Expected only the py matcher, got %v", matchers)
+	}
+}
@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"github.com/Aj4x/tash/internal/history"
+	"github.com/Aj4x/tash/internal/queue"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// queuePollInterval is how often the daemon checks its queue directory for jobs
+const queuePollInterval = time.Second
+
+// jobQueue returns the queue directory of the daemon, or nil when it has none
+func (m Model) jobQueue() *queue.Queue {
+	dir := m.Config.QueueDir
+	if !m.Headless || dir == "" {
+		return nil
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(m.catalogDir(), dir)
+	}
+	return &queue.Queue{Dir: dir}
+}
+
+// runQueuedJob runs the oldest job dropped into the queue directory, once tash is idle. Jobs are
+// refused like the runs requested by other processes, e.g. dangerous tasks or tasks needing
+// input the job doesn't give, and moved to failed/ straight away.
+func (m Model) runQueuedJob(now time.Time) (Model, tea.Cmd) {
+	q := m.jobQueue()
+	if q == nil || m.TasksLoading || m.ExecutingBatch || m.queueJob != nil ||
+		len(m.AllTasks) == 0 || now.Sub(m.queueChecked) < queuePollInterval {
+		return m, nil
+	}
+	m.queueChecked = now
+	if !m.queueRecovered {
+		m.queueRecovered = true
+		if err := q.Recover(); err != nil {
+			slog.Warn("unable to recover the jobs of the queue", "dir", q.Dir, "error", err)
+		}
+	}
+	for {
+		job, ok, err := q.Next()
+		if err != nil {
+			slog.Warn("unable to read the queue", "dir", q.Dir, "error", err)
+			return m, nil
+		}
+		if !ok {
+			return m, nil
+		}
+		result := make(chan error, 1)
+		var cmd tea.Cmd
+		m, cmd = m.handleRemoteRun(RemoteRunMsg{Task: job.Task, Vars: job.Vars, origin: "the queued job " + job.Name(), result: result})
+		err = <-result
+		if err == nil && !m.TasksLoading {
+			err = fmt.Errorf("%s didn't start", job.Task)
+		}
+		if err == nil {
+			m.queueJob = &job
+			return m, cmd
+		}
+		m.AppendErrorMsg(fmt.Sprintf("The queued job %s failed: %s", job.Name(), err))
+		if err := q.Finish(job, queue.Result{Start: now, End: time.Now(), Error: err.Error()}); err != nil {
+			slog.Warn("unable to finish the queued job", "job", job.Name(), "error", err)
+		}
+	}
+}
+
+// finishQueuedJob moves the queued job whose run is recorded to done/ or failed/
+func (m *Model) finishQueuedJob(record history.Record) {
+	job, q := m.queueJob, m.jobQueue()
+	if job == nil || q == nil {
+		return
+	}
+	m.queueJob = nil
+	result := queue.Result{Start: record.Start, End: record.End, Success: record.Success, Error: record.Error}
+	if err := q.Finish(*job, result); err != nil {
+		slog.Warn("unable to finish the queued job", "job", job.Name(), "error", err)
+	}
+}
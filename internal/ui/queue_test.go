@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/queue"
+	"github.com/Aj4x/tash/internal/task"
+)
+
+func TestDaemonRunsQueuedJobs(t *testing.T) {
+	cfg := config.Default()
+	cfg.Provider = task.ProviderDemo
+	cfg.QueueDir = t.TempDir()
+	cfg.DangerousTasks = []string{"deploy"}
+	m := NewModel(nil, cfg)
+	m.HandleWindowResize(120, 40)
+	m.Headless = true
+	m.AllTasks = []task.Task{{Id: "build"}, {Id: "deploy"}}
+	os.WriteFile(filepath.Join(cfg.QueueDir, "1.json"), []byte(`{"task": "deploy"}`), 0o644)
+	os.WriteFile(filepath.Join(cfg.QueueDir, "2.json"), []byte(`{"task": "build"}`), 0o644)
+	now := time.Now()
+	os.Chtimes(filepath.Join(cfg.QueueDir, "1.json"), now.Add(-time.Minute), now.Add(-time.Minute))
+
+	m, cmd := m.runQueuedJob(now)
+	if cmd == nil || m.RunningTaskId != "build" || m.queueJob == nil {
+		t.Fatalf("Expected build to run, got %q", m.RunningTaskId)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.QueueDir, queue.FailedDir, "1.json")); err != nil {
+		t.Errorf("Expected the dangerous job to be refused: %v", err)
+	}
+	if _, cmd := m.runQueuedJob(now.Add(time.Minute)); cmd != nil {
+		t.Error("Expected no other job while one runs")
+	}
+
+	m.recordRun(nil)
+	if _, err := os.Stat(filepath.Join(cfg.QueueDir, queue.DoneDir, "2.json")); err != nil || m.queueJob != nil {
+		t.Errorf("Expected the job to be done: %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// readOnlyActions are the actions refused in read-only mode: they run, stop or schedule tasks,
+// run commands, or edit Taskfiles and the config
+var readOnlyActions = map[Action]bool{
+	ActionExecute:        true,
+	ActionCancel:         true,
+	ActionPause:          true,
+	ActionRepeat:         true,
+	ActionExecuteBatch:   true,
+	ActionRunNamespace:   true,
+	ActionFanOut:         true,
+	ActionRunOptions:     true,
+	ActionRunExternal:    true,
+	ActionShell:          true,
+	ActionCommandLine:    true,
+	ActionTaskShell:      true,
+	ActionSchedule:       true,
+	ActionToggleWatchers: true,
+	ActionEditTaskfile:   true,
+	ActionEditMetadata:   true,
+	ActionCloneTask:      true,
+	ActionSnippets:       true,
+	ActionMakeImport:     true,
+	ActionKeyBindings:    true,
+	ActionOpenFile:       true,
+	ActionStart:          true,
+	ActionStop:           true,
+	ActionRestart:        true,
+}
+
+// Mutating reports whether action is refused in read-only mode
+func (a Action) Mutating() bool {
+	return readOnlyActions[a]
+}
+
+// refuseReadOnly reports whether action is refused because tash is read-only, telling why
+func (m *Model) refuseReadOnly(action Action) bool {
+	if !m.Config.ReadOnly || !action.Mutating() {
+		return false
+	}
+	m.AppendErrorMsg(fmt.Sprintf("tash is read-only, %s is disabled\n", strings.ReplaceAll(string(action), "_", " ")))
+	return true
+}
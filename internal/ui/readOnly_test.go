@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/task"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestReadOnlyRefusesRunningAndEditing(t *testing.T) {
+	cfg := config.Default()
+	cfg.ReadOnly = true
+	cfg.Schedules = []config.ScheduleConfig{{Task: "build", Schedule: "5m"}}
+	m := NewModel(nil, cfg)
+	m.HandleWindowResize(120, 40)
+	m.Tasks = []task.Task{{Id: "build", Desc: "Build"}}
+	m.AllTasks = m.Tasks
+	m.UpdateTaskTable()
+	if len(m.Schedules) != 0 {
+		t.Errorf("Expected nothing scheduled, got %v", m.Schedules)
+	}
+
+	for _, key := range []tea.KeyMsg{{Type: tea.KeyEnter}, runes("E"), runes("C"), runes(":")} {
+		updated, cmd := m.handleNormalKey(key)
+		m = updated.(Model)
+		if cmd != nil || m.TasksLoading || m.State != StateNormal {
+			t.Errorf("Expected %s to be refused, got state %s", key, m.State)
+		}
+	}
+	if !strings.Contains(m.output.String(), "tash is read-only, execute is disabled") {
+		t.Errorf("Expected the refusal to be explained, got:\n%s", m.output.String())
+	}
+
+	// Listing, details and help still work
+	updated, _ := m.handleNormalKey(runes("i"))
+	if m = updated.(Model); m.State != StateDetailsOverlay {
+		t.Errorf("Expected the details to open, got state %s", m.State)
+	}
+	updated, _ = m.handleKeyMsg(runes("s"))
+	if m = updated.(Model); m.State != StateDetailsOverlay {
+		t.Errorf("Expected the task shell to be refused, got state %s", m.State)
+	}
+	if help := m.KeyBindings.GenerateHelpContent(120, nil, "execute task"); !strings.Contains(help, "Execute task (read-only)") {
+		t.Errorf("Expected disabled bindings to be marked in the help, got:\n%s", help)
+	}
+	if hints := m.KeyBindings.RenderHints(StateNormal.HintContexts(), 0, 0); strings.Contains(hints, "Execute task") {
+		t.Errorf("Expected disabled bindings to be left out of the hints, got %q", hints)
+	}
+}
@@ -0,0 +1,223 @@
+package ui
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Aj4x/tash/internal/instance"
+	"github.com/Aj4x/tash/internal/task"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// remoteRunTimeout is how long RemoteRun waits for the interface to accept or refuse a run
+const remoteRunTimeout = time.Second
+
+// RemoteRunMsg asks the interface to run a task on behalf of another process, such as a second
+// tash launched for the same project. Vars and Confirmed answer the task's input when given;
+// ConfirmedDangerous is set once the id of a task marked dangerous has been typed.
+type RemoteRunMsg struct {
+	Task               string
+	Vars               []string
+	Confirmed          bool
+	ConfirmedDangerous bool
+	origin             string // Who asked for the run, shown in the output
+	result             chan<- error
+}
+
+// RemoteCancelMsg asks the interface to cancel the running task on behalf of another process
+type RemoteCancelMsg struct {
+	result chan<- error
+}
+
+// RemoteEventMsg carries an event of the daemon the interface is attached to
+type RemoteEventMsg struct {
+	Event instance.Event
+}
+
+// RemoteDetachedMsg reports that the interface is no longer attached to its daemon
+type RemoteDetachedMsg struct {
+	Err error
+}
+
+// remoteRunResultMsg reports whether the daemon accepted a run requested by the interface
+type remoteRunResultMsg struct {
+	Task string
+	Err  error
+}
+
+// RemoteRun asks the program showing the interface to run the task of a run request, and
+// returns whether it did
+func RemoteRun(p *tea.Program, req instance.Request) error {
+	return awaitReply(p, func(result chan<- error) tea.Msg {
+		return RemoteRunMsg{Task: req.Task, Vars: req.Vars, Confirmed: req.Confirmed, ConfirmedDangerous: req.ConfirmedDangerous, result: result}
+	})
+}
+
+// RemoteCancel asks the program showing the interface to cancel the running task
+func RemoteCancel(p *tea.Program) error {
+	return awaitReply(p, func(result chan<- error) tea.Msg {
+		return RemoteCancelMsg{result: result}
+	})
+}
+
+// awaitReply sends the message made by msg to p and waits for its reply
+func awaitReply(p *tea.Program, msg func(result chan<- error) tea.Msg) error {
+	result := make(chan error, 1)
+	p.Send(msg(result))
+	select {
+	case err := <-result:
+		return err
+	case <-time.After(remoteRunTimeout):
+		return errors.New("the interface didn't answer")
+	}
+}
+
+// handleRemoteRun runs the task requested by another process, unless a task is already running
+func (m Model) handleRemoteRun(msg RemoteRunMsg) (Model, tea.Cmd) {
+	reply := func(err error) {
+		if msg.result != nil {
+			msg.result <- err
+		}
+	}
+	if m.Config.ReadOnly {
+		reply(errors.New("tash is read-only"))
+		return m, nil
+	}
+	t, ok := task.Find(m.AllTasks, msg.Task)
+	if !ok {
+		reply(fmt.Errorf("unknown task '%s'", msg.Task))
+		return m, nil
+	}
+	if m.TasksLoading {
+		reply(errors.New("a task is already running"))
+		return m, nil
+	}
+	if m.Config.IsDangerous(t.Id) {
+		if !msg.ConfirmedDangerous {
+			reply(fmt.Errorf("%s is marked dangerous, run it from an attached tash", t.Id))
+			return m, nil
+		}
+		m.dangerConfirmed = t.Id
+	}
+	if len(msg.Vars) > 0 || msg.Confirmed {
+		if m.RunInputs == nil {
+			m.RunInputs = map[string]runInputs{}
+		}
+		m.RunInputs[t.Id] = runInputs{Vars: msg.Vars, Confirmed: msg.Confirmed}
+	} else if _, answered := m.RunInputs[t.Id]; m.Headless && !answered && !m.taskRequirements(t.Id).Empty() {
+		reply(fmt.Errorf("%s needs input, run it from an attached tash", t.Id))
+		return m, nil
+	}
+	reply(nil)
+	m.AppendAppMsg("Running " + t.Id + " at the request of " + cmp.Or(msg.origin, "another tash") + "\n")
+	return m, m.executeTask(t)
+}
+
+// handleRemoteCancel cancels the running task at the request of another process
+func (m Model) handleRemoteCancel(msg RemoteCancelMsg) (Model, tea.Cmd) {
+	var err error
+	if m.Config.ReadOnly {
+		err = errors.New("tash is read-only")
+	} else if m.TaskRunning {
+		m.cancelTask()
+	} else {
+		err = errors.New("no task is running")
+	}
+	if msg.result != nil {
+		msg.result <- err
+	}
+	return m, nil
+}
+
+// sendRemoteRun asks the daemon the interface is attached to to run taskId
+func (m *Model) sendRemoteRun(taskId string, inputs runInputs) tea.Cmd {
+	m.remoteRequested = taskId
+	req := instance.Request{Command: instance.CommandRun, Task: taskId, Vars: inputs.Vars, Confirmed: inputs.Confirmed,
+		ConfirmedDangerous: m.Config.IsDangerous(taskId)}
+	socket := m.Remote.Socket
+	return func() tea.Msg {
+		_, err := instance.Send(socket, req)
+		return remoteRunResultMsg{Task: taskId, Err: err}
+	}
+}
+
+// handleRemoteRunResult reports a run the daemon refused
+func (m Model) handleRemoteRunResult(msg remoteRunResultMsg) (Model, tea.Cmd) {
+	if msg.Err == nil {
+		return m, nil
+	}
+	m.remoteRequested = ""
+	m.AppendErrorMsg(fmt.Sprintf("The daemon didn't run %s: %s", msg.Task, msg.Err))
+	if m.ExecutingBatch {
+		m.AppendErrorMsg("Batch execution aborted")
+		m.ExecutingBatch = false
+		m.CurrentBatchTaskIndex = -1
+	}
+	return m, nil
+}
+
+// handleRemoteEvent shows a task run of the daemon the interface is attached to as if it ran
+// locally
+func (m Model) handleRemoteEvent(msg RemoteEventMsg) (Model, tea.Cmd) {
+	e := msg.Event
+	switch e.Event {
+	case instance.EventStart:
+		if e.Task != m.remoteRequested {
+			m.AppendAppMsg(fmt.Sprintf("Executing task: %s (started by the daemon)\n\n", e.Task))
+		}
+		m.remoteRequested = ""
+		m.beginRun(e.Task)
+		m.TaskRunning = true
+		m.CommandCancel = m.remoteCancel()
+		return m, nil
+	case instance.EventOutput:
+		if e.Stderr {
+			return m.handleBusMessage(task.TypeTaskOutputErr.Message().SetOutput(e.Line))
+		}
+		return m.handleBusMessage(task.TypeTaskOutput.Message().SetOutput(e.Line))
+	case instance.EventDone:
+		m.TaskRunning = false
+		m.CommandCancel = nil
+		return m.handleBusMessage(task.TypeTaskDone.Message())
+	case instance.EventError:
+		m.TaskRunning = false
+		m.CommandCancel = nil
+		return m.handleBusMessage(task.TypeTaskError.Message().SetError(errors.New(e.Error)))
+	}
+	return m, nil
+}
+
+// remoteCancel returns a function asking the daemon to cancel its running task
+func (m Model) remoteCancel() func() {
+	socket := m.Remote.Socket
+	return func() {
+		go func() {
+			if _, err := instance.Send(socket, instance.Request{Command: instance.CommandCancel}); err != nil {
+				slog.Warn("unable to cancel the daemon's task", "error", err)
+			}
+		}()
+	}
+}
+
+// handleRemoteDetached runs tasks locally again once the daemon has gone
+func (m Model) handleRemoteDetached(msg RemoteDetachedMsg) (Model, tea.Cmd) {
+	if m.Remote == nil {
+		return m, nil
+	}
+	reason := "the daemon stopped"
+	if msg.Err != nil {
+		reason = msg.Err.Error()
+	}
+	m.AppendErrorMsg("Detached from the tash daemon: " + reason + ", tasks now run in this tash")
+	m.Remote = nil
+	m.remoteRequested = ""
+	if m.TaskRunning {
+		m.TaskRunning = false
+		m.CommandCancel = nil
+		m.TasksLoading = false
+	}
+	return m, nil
+}
@@ -0,0 +1,147 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/Aj4x/tash/internal/task"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// RemotePrompt holds the URL entered for a remote Taskfile
+type RemotePrompt struct {
+	URL   string
+	Error string // Reason the Taskfile can't be used, shown until the URL is changed
+}
+
+// trustedMsg reports whether task was told to trust the remote Taskfile
+type trustedMsg struct {
+	err error
+}
+
+// openRemotePrompt asks for the URL of a remote Taskfile, starting from the one in use
+func (m *Model) openRemotePrompt() {
+	if m.Config.Provider == task.ProviderDemo {
+		m.AppendErrorMsg("Demo tasks are not defined in a Taskfile")
+		return
+	}
+	m.RemotePrompt = RemotePrompt{URL: m.Config.Taskfile}
+	m.SetState(StateRemotePrompt)
+}
+
+// handleRemotePromptKey handles key presses while entering the URL of a remote Taskfile
+func (m Model) handleRemotePromptKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	prompt := &m.RemotePrompt
+	switch action := m.resolveKey(msg); {
+	case action == ActionClose:
+		m.SetState(StateNormal)
+	case action == ActionConfirm:
+		cmd := m.useTaskfile(strings.TrimSpace(prompt.URL))
+		return m, cmd
+	case IsKeyMatch(msg, "backspace"):
+		if runes := []rune(prompt.URL); len(runes) > 0 {
+			prompt.URL = string(runes[:len(runes)-1])
+		}
+		prompt.Error = ""
+	case msg.Type == tea.KeyRunes:
+		prompt.URL += string(msg.Runes)
+		prompt.Error = ""
+	}
+	return m, nil
+}
+
+// useTaskfile lists the tasks of the remote Taskfile at url, or of the project's own Taskfile
+// when url is empty. The cached tasks of the Taskfile are shown while it is fetched.
+func (m *Model) useTaskfile(url string) tea.Cmd {
+	if url != "" && !task.IsRemoteTaskfile(url) {
+		m.RemotePrompt.Error = "Enter an https:// URL or a git repository such as https://github.com/org/tasks.git//Taskfile.yml"
+		return nil
+	}
+	cfg := m.Config
+	cfg.Taskfile = url
+	if url != "" {
+		if err := m.TaskVersion.Check(task.FeatureRemoteTaskfiles, ExecEnvironment(cfg).Environ(nil)); err != nil {
+			m.RemotePrompt.Error = err.Error()
+			return nil
+		}
+	}
+	m.Config = cfg
+	m.SetState(StateNormal)
+	if url == "" {
+		m.AppendAppMsg("Using the project's Taskfile\n")
+	} else {
+		m.AppendAppMsg("Using the remote Taskfile " + url + "\n")
+	}
+	m.cachedTasks = nil
+	m.showCachedCatalog()
+	return m.RefreshTaskList()
+}
+
+// askTrust asks whether to trust the remote Taskfile in use, which task refused to read
+// without asking
+func (m *Model) askTrust() {
+	if m.State == StateNormal && !m.Headless {
+		m.SetState(StateTrustPrompt)
+	}
+}
+
+// handleTrustPromptKey handles key presses while asked whether to trust a remote Taskfile
+func (m Model) handleTrustPromptKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.resolveKey(msg) {
+	case ActionClose:
+		m.SetState(StateNormal)
+		notice := "The remote Taskfile " + m.Config.Taskfile + " isn't trusted"
+		if binding, ok := m.KeyBindings.Binding(ActionRemoteTaskfile); ok {
+			notice += ", " + binding.Key + " uses another Taskfile"
+		}
+		m.AppendErrorMsg(notice + "\n")
+	case ActionConfirm:
+		m.SetState(StateNormal)
+		env := ExecEnvironment(m.Config)
+		return m, func() tea.Msg {
+			return trustedMsg{err: task.TrustRemoteTaskfile(env)}
+		}
+	}
+	return m, nil
+}
+
+// handleTrusted lists the tasks of the remote Taskfile once task trusts it
+func (m Model) handleTrusted(msg trustedMsg) (Model, tea.Cmd) {
+	if msg.err != nil {
+		m.AppendErrorMsg("Unable to trust the remote Taskfile: " + msg.err.Error() + "\n")
+		return m, nil
+	}
+	m.AppendAppMsg("Trusted the remote Taskfile " + m.Config.Taskfile + "\n")
+	cmd := m.RefreshTaskList()
+	return m, cmd
+}
+
+// RenderRemotePrompt renders the overlay used to enter the URL of a remote Taskfile
+func RenderRemotePrompt(width, height int, prompt RemotePrompt) string {
+	overlayWidth := int(float64(width) * 0.7)
+
+	content := TaskPickerTitleStyle.Render("Remote Taskfile") + "\n\n"
+	content += TaskPickerInputStyle(overlayWidth).Render(prompt.URL) + "\n\n"
+	if prompt.Error != "" {
+		content += ErrorMsgStyle.Render(prompt.Error) + "\n\n"
+	}
+	content += HelpStyle.Render("An https:// URL or a git repository, e.g. https://github.com/org/tasks.git//Taskfile.yml?ref=main; empty uses the project's Taskfile")
+
+	overlay := GeneralOverlayStyle(overlayWidth).Render(content)
+
+	return placeOverlay(width, height, overlay)
+}
+
+// RenderTrustPrompt renders the question whether to trust the remote Taskfile at url
+func RenderTrustPrompt(width, height int, url string) string {
+	overlayWidth := int(float64(width) * 0.7)
+
+	content := TaskPickerTitleStyle.Render("Trust the remote Taskfile?") + "\n\n"
+	content += url + "\n\n"
+	content += HelpStyle.Render("Its tasks run commands on this machine, so only trust Taskfiles from sources you know. "+
+		"task asks again when the Taskfile changes.") + "\n\n"
+	content += HelpStyle.Render("y trusts it and lists its tasks, n cancels")
+
+	overlay := GeneralOverlayStyle(overlayWidth).Render(content)
+
+	return placeOverlay(width, height, overlay)
+}
@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/catalog"
+	"github.com/Aj4x/tash/internal/task"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestRemoteTaskfileIsListedFromTheCacheAndTrusted(t *testing.T) {
+	url := "https://example.com/Taskfile.yml"
+	store := catalog.NewStore(t.TempDir())
+	if err := store.SaveRemote(url, `{"tasks": [{"name": "deploy"}, {"name": "release"}]}`); err != nil {
+		t.Fatal(err)
+	}
+	m := newNavigationModel(0)
+	m.HandleWindowResize(120, 40)
+	m.UseCatalogCache(store)
+
+	m.openRemotePrompt()
+	updated, _ := m.handleRemotePromptKey(runes("./Taskfile.yml"))
+	m = updated.(Model)
+	updated, cmd := m.handleRemotePromptKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if m = updated.(Model); cmd != nil || m.State != StateRemotePrompt || m.RemotePrompt.Error == "" {
+		t.Fatalf("Expected a path to be rejected in the prompt, got state %s", m.State)
+	}
+
+	m.RemotePrompt.URL = url
+	updated, cmd = m.handleRemotePromptKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if m = updated.(Model); cmd == nil || m.State != StateNormal || m.Config.Taskfile != url {
+		t.Fatalf("Expected the remote Taskfile to be listed, got state %s and error %q", m.State, m.RemotePrompt.Error)
+	}
+	if len(m.Tasks) != 2 || m.Tasks[0].Id != "deploy" {
+		t.Errorf("Expected the cached tasks of the remote Taskfile, got %+v", m.Tasks)
+	}
+
+	m, _ = m.handleListAllErrMsg(task.TypeTaskListAllErr.Message().SetError(&task.ListError{
+		Err: errors.New("exit status 1"), Stderr: []string{`task: Taskfile "` + url + `" not trusted by user`},
+	}))
+	if m.State != StateTrustPrompt {
+		t.Fatalf("Expected to be asked whether to trust the Taskfile, got state %s", m.State)
+	}
+	if overlay := RenderTrustPrompt(160, 40, m.Config.Taskfile); !strings.Contains(overlay, url) {
+		t.Errorf("Expected the prompt to name the Taskfile, got:\n%s", overlay)
+	}
+	updated, _ = m.handleTrustPromptKey(runes("n"))
+	if m = updated.(Model); m.State != StateNormal || !strings.Contains(m.output.String(), "isn't trusted") {
+		t.Errorf("Expected declining to leave the Taskfile untrusted, got state %s", m.State)
+	}
+}
@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/instance"
+)
+
+func TestRemoteRun(t *testing.T) {
+	m := newNavigationModel(2)
+	m.AllTasks = m.Tasks
+
+	result := make(chan error, 1)
+	m, _ = m.handleRemoteRun(RemoteRunMsg{Task: "missing", result: result})
+	if err := <-result; err == nil {
+		t.Error("Expected an unknown task to be refused")
+	}
+
+	m, cmd := m.handleRemoteRun(RemoteRunMsg{Task: "task-1", result: result})
+	if err := <-result; err != nil || cmd == nil {
+		t.Fatalf("Expected task-1 to run, got %v", err)
+	}
+	if m.RunningTaskId != "task-1" {
+		t.Errorf("Expected task-1 to be running, got %q", m.RunningTaskId)
+	}
+
+	m, _ = m.handleRemoteRun(RemoteRunMsg{Task: "task-0", result: result})
+	if err := <-result; err == nil {
+		t.Error("Expected a run to be refused while another is running")
+	}
+}
+
+func TestHeadlessRunNeedsInput(t *testing.T) {
+	m := newRequirementsModel(t)
+	m.Headless = true
+
+	result := make(chan error, 1)
+	m, _ = m.handleRemoteRun(RemoteRunMsg{Task: "deploy", result: result})
+	if err := <-result; err == nil {
+		t.Fatal("Expected a task needing input to be refused")
+	}
+	if m.State == StateRunPrompt {
+		t.Error("Expected no prompt to open in a daemon")
+	}
+
+	m, cmd := m.handleRemoteRun(RemoteRunMsg{Task: "deploy", Vars: []string{"API_KEY=k", "TARGET=staging"}, Confirmed: true, result: result})
+	if err := <-result; err != nil || cmd == nil {
+		t.Fatalf("Expected deploy to run with the given input, got %v", err)
+	}
+	if got := m.RunInputs["deploy"].Vars; len(got) != 2 {
+		t.Errorf("Expected the given vars to be used, got %v", got)
+	}
+}
+
+func TestRemoteCancel(t *testing.T) {
+	m := newNavigationModel(1)
+	result := make(chan error, 1)
+	m, _ = m.handleRemoteCancel(RemoteCancelMsg{result: result})
+	if err := <-result; err == nil {
+		t.Error("Expected cancelling to fail without a running task")
+	}
+
+	cancelled := false
+	m.TaskRunning = true
+	m.CommandCancel = func() { cancelled = true }
+	m, _ = m.handleRemoteCancel(RemoteCancelMsg{result: result})
+	if err := <-result; err != nil || !cancelled || m.TaskRunning {
+		t.Errorf("Expected the task to be cancelled, got %v", err)
+	}
+}
+
+func TestAttachedInterfaceShowsDaemonRuns(t *testing.T) {
+	m := newNavigationModel(1)
+	m.HandleWindowResize(120, 40)
+	m.Remote = &instance.Info{Pid: 42, Socket: "unused"}
+
+	m, _ = m.handleRemoteEvent(RemoteEventMsg{Event: instance.Event{Event: instance.EventStart, Task: "task-0"}})
+	if !m.TaskRunning || m.RunningTaskId != "task-0" || m.CommandCancel == nil {
+		t.Fatalf("Expected task-0 to be shown running, got running=%v id=%q", m.TaskRunning, m.RunningTaskId)
+	}
+	m, _ = m.handleRemoteEvent(RemoteEventMsg{Event: instance.Event{Event: instance.EventOutput, Task: "task-0", Line: "compiled"}})
+	m, _ = m.handleRemoteEvent(RemoteEventMsg{Event: instance.Event{Event: instance.EventDone, Task: "task-0"}})
+	if m.TaskRunning || m.TasksLoading {
+		t.Error("Expected the run to be finished")
+	}
+	out := m.output.String()
+	for _, want := range []string{"started by the daemon", "compiled", "Task executed successfully"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected the output to contain %q, got %q", want, out)
+		}
+	}
+	if got := m.RunHistory["task-0"].Current; len(got) != 1 {
+		t.Errorf("Expected the run to be kept for comparison, got %v", got)
+	}
+
+	m, _ = m.handleRemoteDetached(RemoteDetachedMsg{})
+	if m.Remote != nil {
+		t.Error("Expected tasks to run locally once the daemon has gone")
+	}
+}
@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// renderStats records how long the views take to render, for the frame rate overlay. It is
+// shared by the copies of the model.
+type renderStats struct {
+	mu     sync.Mutex
+	frames []renderedFrame // Views rendered within the last second
+}
+
+// renderedFrame is a rendered view
+type renderedFrame struct {
+	at   time.Time
+	took time.Duration
+}
+
+// record adds a view rendered at at, which took took to render
+func (s *renderStats) record(at time.Time, took time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.frames = append(s.dropOld(at), renderedFrame{at, took})
+}
+
+// dropOld returns the frames rendered within the second before now
+func (s *renderStats) dropOld(now time.Time) []renderedFrame {
+	i := 0
+	for i < len(s.frames) && now.Sub(s.frames[i].at) > time.Second {
+		i++
+	}
+	return s.frames[i:]
+}
+
+// summary returns the number of views rendered within the second before now, and how long they
+// took to render on average and at most
+func (s *renderStats) summary(now time.Time) (count int, avg, longest time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.frames = s.dropOld(now)
+	var total time.Duration
+	for _, f := range s.frames {
+		total += f.took
+		longest = max(longest, f.took)
+	}
+	if len(s.frames) > 0 {
+		avg = total / time.Duration(len(s.frames))
+	}
+	return len(s.frames), avg, longest
+}
+
+// toggleRenderStats shows or hides the frame rate overlay
+func (m *Model) toggleRenderStats() {
+	if m.renderStats == nil {
+		m.renderStats = &renderStats{}
+		return
+	}
+	m.renderStats = nil
+}
+
+// renderStatsLine describes the rendering of the last second and the configured rates
+func (m Model) renderStatsLine(now time.Time) string {
+	count, avg, longest := m.renderStats.summary(now)
+	return fmt.Sprintf(" %d views/s, render %s avg, %s max · tick %s · max %d fps",
+		count, avg.Round(time.Microsecond), longest.Round(time.Microsecond), m.tickInterval(), FPS(m.Config))
+}
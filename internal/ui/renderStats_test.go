@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Aj4x/tash/internal/config"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestRenderStatsSummariseTheLastSecond(t *testing.T) {
+	var s renderStats
+	now := time.Now()
+	s.record(now.Add(-2*time.Second), time.Hour)
+	s.record(now.Add(-500*time.Millisecond), 2*time.Millisecond)
+	s.record(now.Add(-100*time.Millisecond), 4*time.Millisecond)
+
+	count, avg, longest := s.summary(now)
+	if count != 2 || avg != 3*time.Millisecond || longest != 4*time.Millisecond {
+		t.Errorf("Expected 2 views of 3ms on average and 4ms at most, got %d, %s, %s", count, avg, longest)
+	}
+}
+
+func TestRenderStatsOverlay(t *testing.T) {
+	cfg := config.Default()
+	cfg.MaxFPS = 500
+	cfg.TickInterval = config.Duration(200 * time.Millisecond)
+	m := NewModel(nil, cfg)
+	m.HandleWindowResize(120, 40)
+	m.Initialised = true
+
+	updated, _ := m.handleKeyMsg(tea.KeyMsg{Type: tea.KeyCtrlF})
+	m = updated.(Model)
+	m.View()
+	if view := m.View(); !strings.Contains(view, "1 views/s") || !strings.Contains(view, "tick 200ms · max 120 fps") {
+		t.Errorf("Expected the frame rate overlay with the configured rates, got %q", view)
+	}
+	updated, _ = m.handleKeyMsg(tea.KeyMsg{Type: tea.KeyCtrlF})
+	if view := updated.(Model).View(); strings.Contains(view, "views/s") {
+		t.Error("Expected the overlay to be hidden again")
+	}
+}
@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Aj4x/tash/internal/task"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// RepeatState tracks a task being rerun until it fails, for hunting flaky tests
+type RepeatState struct {
+	Active         bool
+	TaskId         string
+	Iteration      int
+	MaxIterations  int
+	IterationStart time.Time
+	Total          time.Duration
+}
+
+// startRepeat begins running t repeatedly until it fails or the iteration limit is reached
+func (m Model) startRepeat(t task.Task) (Model, tea.Cmd) {
+	maxIterations := m.Config.RepeatMaxIterations
+	if maxIterations <= 0 {
+		maxIterations = 100
+	}
+	m.Repeat = RepeatState{Active: true, TaskId: t.Id, MaxIterations: maxIterations}
+	m.AppendAppMsg(fmt.Sprintf("Running task '%s' until failure (max %d iterations)\n", t.Id, maxIterations))
+	return m.nextRepeatIteration()
+}
+
+// nextRepeatIteration starts the next iteration, or ends the run once the limit is reached
+func (m Model) nextRepeatIteration() (Model, tea.Cmd) {
+	if m.Repeat.Iteration >= m.Repeat.MaxIterations {
+		m.AppendAppMsg(fmt.Sprintf("Run until failure finished: %d iterations passed in %s\n",
+			m.Repeat.Iteration, m.Repeat.Total.Round(time.Millisecond)))
+		m.Repeat = RepeatState{}
+		return m, nil
+	}
+	m.Repeat.Iteration++
+	m.Repeat.IterationStart = time.Now()
+	m.AppendAppMsg(fmt.Sprintf("Iteration %d/%d\n", m.Repeat.Iteration, m.Repeat.MaxIterations))
+	return m, m.executeTask(task.Task{Id: m.Repeat.TaskId})
+}
+
+// finishRepeatIteration appends the summary of the iteration that just ended and,
+// when it failed, stops the run
+func (m Model) finishRepeatIteration(passed bool) Model {
+	elapsed := time.Since(m.Repeat.IterationStart)
+	m.Repeat.Total += elapsed
+	if passed {
+		m.AppendAppMsg(fmt.Sprintf("Iteration %d/%d passed in %s\n",
+			m.Repeat.Iteration, m.Repeat.MaxIterations, elapsed.Round(time.Millisecond)))
+		return m
+	}
+	m.AppendErrorMsg(fmt.Sprintf("Iteration %d/%d failed after %s; %d previous iterations passed",
+		m.Repeat.Iteration, m.Repeat.MaxIterations, elapsed.Round(time.Millisecond), m.Repeat.Iteration-1))
+	m.Repeat = RepeatState{}
+	return m
+}
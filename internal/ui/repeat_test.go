@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/task"
+)
+
+func TestRepeatStopsOnFailure(t *testing.T) {
+	m := NewModel(nil, config.Default())
+
+	m, cmd := m.startRepeat(task.Task{Id: "flaky"})
+	if cmd == nil || !m.Repeat.Active || m.Repeat.Iteration != 1 {
+		t.Fatalf("Expected the first iteration to start, got %+v", m.Repeat)
+	}
+
+	m, cmd = m.finishRepeatIteration(true).nextRepeatIteration()
+	if cmd == nil || m.Repeat.Iteration != 2 {
+		t.Fatalf("Expected a second iteration after a pass, got %+v", m.Repeat)
+	}
+
+	m = m.finishRepeatIteration(false)
+	if m.Repeat.Active {
+		t.Error("Expected run until failure to stop after a failed iteration")
+	}
+}
+
+func TestRepeatStopsAtMaxIterations(t *testing.T) {
+	cfg := config.Default()
+	cfg.RepeatMaxIterations = 2
+	m := NewModel(nil, cfg)
+
+	m, _ = m.startRepeat(task.Task{Id: "stable"})
+	m, _ = m.finishRepeatIteration(true).nextRepeatIteration()
+	m, cmd := m.finishRepeatIteration(true).nextRepeatIteration()
+	if cmd != nil || m.Repeat.Active {
+		t.Errorf("Expected the run to finish after 2 iterations, got %+v", m.Repeat)
+	}
+}
@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/retention"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// RetentionPolicy returns the limits of the run logs and history configured in cfg, a limit left
+// unset being the default and a negative one keeping everything
+func RetentionPolicy(cfg config.Config) retention.Policy {
+	limit := func(value, def int64) int64 {
+		switch {
+		case value == 0:
+			return def
+		case value < 0:
+			return 0
+		}
+		return value
+	}
+	r := cfg.Retention
+	return retention.Policy{
+		MaxAge:         time.Duration(limit(int64(r.MaxAge), int64(retention.DefaultMaxAge))),
+		MaxSize:        limit(int64(r.MaxSizeMB), retention.DefaultMaxSize>>20) << 20,
+		MaxRunsPerTask: int(limit(int64(r.MaxRunsPerTask), retention.DefaultMaxRunsPerTask)),
+	}
+}
+
+// cleanDataDir removes the run logs and history records the retention policy doesn't keep, in
+// the background; there is nothing to clean without the stores
+func (m Model) cleanDataDir() tea.Cmd {
+	if m.RunLogs.Dir == "" && m.History.Path == "" {
+		return nil
+	}
+	runs, hist, policy := m.RunLogs, m.History, RetentionPolicy(m.Config)
+	return func() tea.Msg {
+		res, err := retention.Clean(runs, hist, policy, time.Now())
+		if err != nil {
+			slog.Warn("unable to clean up the run logs and history", "error", err)
+		}
+		if res.Runs > 0 || res.Records > 0 {
+			slog.Info("cleaned up the run logs and history", "runs", res.Runs, "bytes", res.Bytes, "records", res.Records)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,147 @@
+package ui
+
+import (
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Aj4x/tash/internal/diff"
+	"github.com/Aj4x/tash/internal/history"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+// RunPair holds the output lines of the previous and the latest run of a task
+type RunPair struct {
+	Previous []string
+	Current  []string
+}
+
+// captureRunLine records an output line of the run in progress
+func (m *Model) captureRunLine(line string) {
+	if m.RunningTaskId != "" {
+		m.runLines = append(m.runLines, line)
+		m.writeRunLog(line)
+		if limit := m.outputMemoryLines(); len(m.runLines) > limit {
+			// like the output, only the latest lines of a long run are kept
+			m.runLines = slices.Clone(m.runLines[len(m.runLines)-limit*3/4:])
+		}
+	}
+}
+
+// recordRun stores the output of the run that just finished as the task's latest run
+// and appends it to the persisted run history
+func (m *Model) recordRun(runErr error) {
+	if m.RunningTaskId == "" {
+		return
+	}
+	record := history.Record{
+		Task:    m.RunningTaskId,
+		Start:   m.runStarted,
+		End:     time.Now(),
+		Success: runErr == nil,
+	}
+	if runErr != nil {
+		record.Error = runErr.Error()
+	}
+	record.Log = m.finishRunLog(record.End, runErr)
+	m.lastRun = &record
+	m.finishQueuedJob(record)
+	if m.Remote != nil {
+		// the daemon running the task records it
+	} else if err := m.History.Append(record); err != nil {
+		slog.Warn("unable to record run history", "error", err)
+	}
+
+	history := maps.Clone(m.RunHistory)
+	if history == nil {
+		history = map[string]RunPair{}
+	}
+	history[m.RunningTaskId] = RunPair{
+		Previous: history[m.RunningTaskId].Current,
+		Current:  m.runLines,
+	}
+	m.RunHistory = history
+	m.RunningTaskId = ""
+	m.runLines = nil
+}
+
+// openDiff shows the comparison of the last two runs of taskId
+func (m *Model) openDiff(taskId string) {
+	runs, ok := m.RunHistory[taskId]
+	if !ok || runs.Previous == nil {
+		m.AppendAppMsg("Task '" + taskId + "' needs at least two runs to compare\n")
+		return
+	}
+	m.diffTaskId = taskId
+	m.DiffViewport.Width = int(float64(m.Width)*0.9) - 6
+	m.DiffViewport.Height = int(float64(m.Height)*0.9) - 8
+	m.DiffViewport.SetContent(m.renderDiffContent())
+	m.DiffViewport.GotoTop()
+	m.SetState(StateDiffOverlay)
+}
+
+// renderDiffContent renders the diff of the compared task as unified or side-by-side text
+func (m Model) renderDiffContent() string {
+	runs := m.RunHistory[m.diffTaskId]
+	lines := diff.Lines(runs.Previous, runs.Current)
+	var sb strings.Builder
+	if !m.DiffSideBySide {
+		for _, l := range lines {
+			switch l.Kind {
+			case diff.Added:
+				sb.WriteString(DiffAddedStyle.Render("+ " + l.Text))
+			case diff.Removed:
+				sb.WriteString(DiffRemovedStyle.Render("- " + l.Text))
+			default:
+				sb.WriteString(DiffEqualStyle.Render("  " + l.Text))
+			}
+			sb.WriteString("\n")
+		}
+		return sb.String()
+	}
+
+	colWidth := (m.DiffViewport.Width - 3) / 2
+	cell := func(text string, style lipgloss.Style) string {
+		text = runewidth.Truncate(text, colWidth, "…")
+		return style.Render(runewidth.FillRight(text, colWidth))
+	}
+	blank := strings.Repeat(" ", max(colWidth, 0))
+	for i := 0; i < len(lines); i++ {
+		l := lines[i]
+		switch l.Kind {
+		case diff.Equal:
+			sb.WriteString(cell(l.Text, DiffEqualStyle) + " │ " + cell(l.Text, DiffEqualStyle))
+		case diff.Removed:
+			// pair a removal with a directly following addition so changed lines sit side by side
+			if i+1 < len(lines) && lines[i+1].Kind == diff.Added {
+				sb.WriteString(cell(l.Text, DiffRemovedStyle) + " │ " + cell(lines[i+1].Text, DiffAddedStyle))
+				i++
+			} else {
+				sb.WriteString(cell(l.Text, DiffRemovedStyle) + " │ " + blank)
+			}
+		case diff.Added:
+			sb.WriteString(blank + " │ " + cell(l.Text, DiffAddedStyle))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// RenderDiffOverlay renders the comparison of a task's last two runs
+func RenderDiffOverlay(m *Model) string {
+	overlayWidth := int(float64(m.Width) * 0.9)
+	mode := "unified"
+	if m.DiffSideBySide {
+		mode = "side by side: previous │ latest"
+	}
+	content := TaskPickerTitleStyle.Render("Run Comparison: "+m.diffTaskId) + "\n"
+	content += HelpStyle.Render(mode) + "\n\n"
+	content += m.DiffViewport.View()
+
+	overlay := GeneralOverlayStyle(overlayWidth).Render(content)
+
+	return placeOverlay(m.Width, m.Height, overlay)
+}
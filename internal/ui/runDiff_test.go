@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/history"
+)
+
+func TestRecordRunKeepsPreviousRun(t *testing.T) {
+	m := NewModel(nil, config.Default())
+	m.UseHistory(history.NewStore(t.TempDir()))
+
+	for _, output := range [][]string{{"first"}, {"second"}, {"third"}} {
+		m.RunningTaskId = "test"
+		for _, line := range output {
+			m.captureRunLine(line)
+		}
+		m.recordRun(nil)
+	}
+
+	runs := m.RunHistory["test"]
+	if !reflect.DeepEqual(runs.Previous, []string{"second"}) || !reflect.DeepEqual(runs.Current, []string{"third"}) {
+		t.Errorf("Expected the last two runs to be kept, got %+v", runs)
+	}
+	if m.RunningTaskId != "" {
+		t.Error("Expected the running task to be cleared once recorded")
+	}
+	if records, _ := m.History.Load(); len(records) != 3 {
+		t.Errorf("Expected the 3 runs in the history, got %v", records)
+	}
+}
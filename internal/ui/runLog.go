@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/Aj4x/tash/internal/runlog"
+	"github.com/Aj4x/tash/internal/task"
+)
+
+// UseRunLogs writes the output of each run to a log in store, next to a metadata file describing
+// the run; without a store runs aren't logged
+func (m *Model) UseRunLogs(store runlog.Store) {
+	m.RunLogs = store
+}
+
+// startRunLog creates the log and the metadata file of the run that just began with opts, run
+// with the environment profile named profile
+func (m *Model) startRunLog(opts task.ExecOptions, profile string) {
+	redactor := m.redactor.WithEnv(opts.Vars, opts.Env)
+	vars := make([]string, len(opts.Vars))
+	for i, v := range opts.Vars {
+		vars[i] = redactor.Redact(v)
+	}
+	run, err := m.RunLogs.Start(runlog.Metadata{
+		Task:       m.RunningTaskId,
+		Args:       opts.Args,
+		Vars:       vars,
+		EnvProfile: profile,
+		Dir:        opts.Environment.Dir,
+		Start:      m.runStarted,
+	})
+	if err != nil {
+		slog.Warn("unable to create the run log", "error", err)
+	}
+	m.runLog = run
+}
+
+// writeRunLog appends an output line of the run in progress to its log, which is dropped when it
+// can't be written
+func (m *Model) writeRunLog(line string) {
+	if err := m.runLog.WriteLine(line); err != nil {
+		slog.Warn("unable to write the run log", "error", err)
+		m.runLog.Finish(time.Now(), err)
+		m.runLog = nil
+	}
+}
+
+// finishRunLog records the end of the run in its metadata and returns the path of its log, empty
+// without a log
+func (m *Model) finishRunLog(end time.Time, runErr error) string {
+	if m.runLog == nil {
+		return ""
+	}
+	run := m.runLog
+	m.runLog = nil
+	if err := run.Finish(end, runErr); err != nil {
+		slog.Warn("unable to finish the run log", "error", err)
+	}
+	return run.Metadata().Log
+}
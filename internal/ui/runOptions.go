@@ -0,0 +1,276 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/runopts"
+	"github.com/Aj4x/tash/internal/task"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Fields of the execution options form, in the order they are listed
+const (
+	optionForce = iota
+	optionDry
+	optionVerbose
+	optionWatch
+	optionParallel
+	optionProfile
+	optionArgs
+	optionTimeout
+	optionCount
+)
+
+// RunOptionsForm holds the options chosen for a single run of a task
+type RunOptionsForm struct {
+	TaskId   string
+	Options  runopts.Options
+	Field    int    // Index of the focused field
+	Error    string // Reason the options were rejected, shown until they are changed
+	Defaults string // Variables the config passes to every run of the task
+}
+
+// runOptionsStore returns the store of the options each task was last run with
+func runOptionsStore() runopts.Store {
+	dir, err := config.DataDir()
+	if err != nil {
+		slog.Warn("run options will not be remembered", "error", err)
+		return runopts.Store{}
+	}
+	return runopts.NewStore(dir)
+}
+
+// profileNames returns the names of the configured environment profiles, sorted
+func (m Model) profileNames() []string {
+	return slices.Sorted(maps.Keys(m.Config.EnvProfiles))
+}
+
+// openRunOptions opens the execution options of t, filled in with the options it was last run
+// with or else the current defaults, including the task's CLI args from the config
+func (m *Model) openRunOptions(t task.Task) {
+	saved, err := m.RunOptionsStore.Load()
+	if err != nil {
+		slog.Warn("Unable to load run options", "error", err)
+	}
+	opts, ok := saved[t.Id]
+	if !ok {
+		opts = runopts.Options{Verbose: m.Verbosity == VerbosityVerbose}
+		if timeout := m.Config.TimeoutFor(t.Id); timeout > 0 {
+			opts.Timeout = timeout.String()
+		}
+		opts.Args = m.Config.TaskArgs[t.Id]
+	}
+	m.RunOptionsForm = RunOptionsForm{TaskId: t.Id, Options: opts, Defaults: strings.Join(m.Config.TaskVars[t.Id], " ")}
+	m.SetState(StateRunOptions)
+}
+
+// ParseRunArgs splits CLI args on whitespace, keeping quoted sections together
+func ParseRunArgs(s string) []string {
+	var args []string
+	var current strings.Builder
+	var quote rune
+	inArg := false
+	for _, r := range s {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args
+}
+
+// validateRunOptions reports why the options can't be used, or an empty string when they can
+func (m Model) validateRunOptions(o runopts.Options) string {
+	if o.Timeout != "" {
+		if d, err := time.ParseDuration(o.Timeout); err != nil || d < 0 {
+			return fmt.Sprintf("Invalid timeout %q, use a duration such as 90s or 5m", o.Timeout)
+		}
+	}
+	if _, ok := m.Config.EnvProfiles[o.Profile]; o.Profile != "" && !ok {
+		return fmt.Sprintf("Unknown environment profile %q", o.Profile)
+	}
+	return ""
+}
+
+// applyRunOptions returns opts with the run options applied. The options have been validated.
+func (m Model) applyRunOptions(opts task.ExecOptions, o runopts.Options) task.ExecOptions {
+	opts.Force, opts.Dry, opts.Watch, opts.Parallel = o.Force, o.Dry, o.Watch, o.Parallel
+	opts.Verbose = o.Verbose
+	if o.Verbose {
+		opts.Silent = false
+	}
+	opts.Args = ParseRunArgs(o.Args)
+	if o.Timeout != "" {
+		opts.Timeout, _ = time.ParseDuration(o.Timeout)
+	}
+	if profile := m.Config.EnvProfiles[o.Profile]; len(profile) > 0 {
+		for _, name := range slices.Sorted(maps.Keys(profile)) {
+			opts.Env = append(opts.Env, name+"="+profile[name])
+		}
+	}
+	return opts
+}
+
+// RenderRunOptions renders the execution options form of a task
+func RenderRunOptions(width, height int, form RunOptionsForm, profiles []string) string {
+	overlayWidth := int(float64(width) * 0.7)
+	o := form.Options
+
+	check := func(on bool) string {
+		if on {
+			return "[x]"
+		}
+		return "[ ]"
+	}
+	profile := o.Profile
+	if profile == "" {
+		profile = "none"
+	}
+	if len(profiles) == 0 {
+		profile += " (configure env_profiles)"
+	}
+	timeout := o.Timeout
+	if timeout == "" {
+		timeout = "none"
+	}
+	rows := [optionCount]string{
+		optionForce:    check(o.Force) + " Force, even if up to date",
+		optionDry:      check(o.Dry) + " Dry run, print the commands",
+		optionVerbose:  check(o.Verbose) + " Verbose",
+		optionWatch:    check(o.Watch) + " Watch sources and rerun",
+		optionParallel: check(o.Parallel) + " Parallel dependencies",
+		optionProfile:  "Env profile: " + profile,
+		optionArgs:     "CLI args: " + o.Args,
+		optionTimeout:  "Timeout: " + timeout,
+	}
+
+	content := TaskPickerTitleStyle.Render("Run "+form.TaskId+" with options") + "\n\n"
+	for i, row := range rows {
+		if i == form.Field {
+			content += TaskPickerSelectedMatchStyle(overlayWidth).Render(row) + "\n"
+		} else {
+			content += TaskPickerMatchStyle(overlayWidth).Render(row) + "\n"
+		}
+	}
+	if form.Defaults != "" {
+		content += "\n" + HelpStyle.Render("Default vars: "+form.Defaults) + "\n"
+	}
+	if form.Error != "" {
+		content += "\n" + ErrorMsgStyle.Render(form.Error) + "\n"
+	}
+	content += "\n" + HelpStyle.Render("space toggles or cycles, type to edit text fields, enter runs")
+
+	overlay := GeneralOverlayStyle(overlayWidth).Render(content)
+
+	return placeOverlay(width, height, overlay)
+}
+
+// handleRunOptionsKey handles key presses in the execution options form
+func (m Model) handleRunOptionsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	action := m.resolveKey(msg)
+	form := &m.RunOptionsForm
+	text := form.textField()
+
+	switch {
+	case action == ActionClose:
+		m.SetState(StateNormal)
+	case action == ActionConfirm:
+		return m.runWithOptions()
+	case action == ActionUp:
+		form.Field = (form.Field + optionCount - 1) % optionCount
+	case action == ActionDown:
+		form.Field = (form.Field + 1) % optionCount
+	case text != nil && IsKeyMatch(msg, "backspace"):
+		if len(*text) > 0 {
+			*text = (*text)[:len(*text)-1]
+		}
+		form.Error = ""
+	case text != nil && (msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace):
+		*text += string(msg.Runes)
+		if msg.Type == tea.KeySpace {
+			*text += " "
+		}
+		form.Error = ""
+	case action == ActionToggle:
+		form.toggle(m.profileNames())
+		form.Error = ""
+	}
+	return m, nil
+}
+
+// textField returns the value edited by typing in the focused field, or nil for other fields
+func (f *RunOptionsForm) textField() *string {
+	switch f.Field {
+	case optionArgs:
+		return &f.Options.Args
+	case optionTimeout:
+		return &f.Options.Timeout
+	default:
+		return nil
+	}
+}
+
+// toggle flips the focused checkbox, or moves the profile on to the next one
+func (f *RunOptionsForm) toggle(profiles []string) {
+	o := &f.Options
+	switch f.Field {
+	case optionForce:
+		o.Force = !o.Force
+	case optionDry:
+		o.Dry = !o.Dry
+	case optionVerbose:
+		o.Verbose = !o.Verbose
+	case optionWatch:
+		o.Watch = !o.Watch
+	case optionParallel:
+		o.Parallel = !o.Parallel
+	case optionProfile:
+		// cycle through no profile and then each profile in turn
+		i := slices.Index(profiles, o.Profile)
+		if i+1 < len(profiles) {
+			o.Profile = profiles[i+1]
+		} else {
+			o.Profile = ""
+		}
+	}
+}
+
+// runWithOptions remembers the form's options for the task and runs it with them
+func (m Model) runWithOptions() (tea.Model, tea.Cmd) {
+	form := m.RunOptionsForm
+	form.Options.Args = strings.TrimSpace(form.Options.Args)
+	form.Options.Timeout = strings.TrimSpace(form.Options.Timeout)
+	if reason := m.validateRunOptions(form.Options); reason != "" {
+		m.RunOptionsForm.Error = reason
+		return m, nil
+	}
+	if err := m.RunOptionsStore.Save(form.TaskId, form.Options); err != nil {
+		m.AppendErrorMsg("Unable to save run options: " + err.Error())
+	}
+	m.SetState(StateNormal)
+	m.nextRunOptions = &form
+	m.AppendAppMsg(fmt.Sprintf("Executing task: %s\n\n", form.TaskId))
+	return m, m.runTask(form.TaskId)
+}
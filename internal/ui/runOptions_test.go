@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Aj4x/tash/internal/runopts"
+	"github.com/Aj4x/tash/internal/task"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// pressOptionKeys sends each key to the model in the run options state
+func pressOptionKeys(m Model, keys ...tea.KeyMsg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+	for _, k := range keys {
+		var updated tea.Model
+		updated, cmd = m.handleRunOptionsKey(k)
+		m = updated.(Model)
+	}
+	return m, cmd
+}
+
+func TestRunOptionsAreRememberedPerTask(t *testing.T) {
+	m := newNavigationModel(2)
+	m.RunOptionsStore = runopts.NewStore(t.TempDir())
+	space := tea.KeyMsg{Type: tea.KeySpace}
+	down := tea.KeyMsg{Type: tea.KeyDown}
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+
+	m = pressKeys(m, runes("O"))
+	if m.State != StateRunOptions || m.RunOptionsForm.TaskId != "task-0" {
+		t.Fatalf("Expected the options of task-0, got state %s", m.State)
+	}
+	m, _ = pressOptionKeys(m, space)
+	for range optionArgs {
+		m, _ = pressOptionKeys(m, down)
+	}
+	m, _ = pressOptionKeys(m, runes("-v"), space, runes("./..."), down, runes("soon"), enter)
+	if m.State != StateRunOptions || m.RunOptionsForm.Error == "" {
+		t.Fatalf("Expected an invalid timeout to be refused, got state %s", m.State)
+	}
+
+	m, _ = pressOptionKeys(m, tea.KeyMsg{Type: tea.KeyBackspace}, tea.KeyMsg{Type: tea.KeyBackspace},
+		tea.KeyMsg{Type: tea.KeyBackspace}, tea.KeyMsg{Type: tea.KeyBackspace}, runes("2m"))
+	m, cmd := pressOptionKeys(m, enter)
+	if cmd == nil || m.State != StateNormal || !m.TasksLoading {
+		t.Fatalf("Expected the task to run, got state %s", m.State)
+	}
+	want := runopts.Options{Force: true, Args: "-v ./...", Timeout: "2m"}
+	saved, err := m.RunOptionsStore.Load()
+	if err != nil || saved["task-0"] != want {
+		t.Errorf("Expected %+v to be saved, got %+v, %v", want, saved["task-0"], err)
+	}
+
+	m.TasksLoading = false
+	m = pressKeys(m, runes("O"))
+	if m.RunOptionsForm.Options != want {
+		t.Errorf("Expected the last options to be offered again, got %+v", m.RunOptionsForm.Options)
+	}
+}
+
+func TestApplyRunOptions(t *testing.T) {
+	m := newNavigationModel(1)
+	m.Config.EnvProfiles = map[string]map[string]string{"staging": {"B": "2", "A": "1"}}
+	o := runopts.Options{Dry: true, Watch: true, Verbose: true, Profile: "staging", Args: `one "two three"`, Timeout: "90s"}
+	if reason := m.validateRunOptions(o); reason != "" {
+		t.Fatalf("Expected the options to be valid, got %s", reason)
+	}
+
+	opts := m.applyRunOptions(task.ExecOptions{Silent: true}, o)
+	if !opts.Dry || !opts.Watch || !opts.Verbose || opts.Silent || opts.Force {
+		t.Errorf("Expected the flags to be applied, got %+v", opts)
+	}
+	if !slices.Equal(opts.Args, []string{"one", "two three"}) {
+		t.Errorf("Expected quoted args to be kept together, got %q", opts.Args)
+	}
+	if !slices.Equal(opts.Env, []string{"A=1", "B=2"}) {
+		t.Errorf("Expected the profile's environment, got %v", opts.Env)
+	}
+	if opts.Timeout != 90*time.Second {
+		t.Errorf("Expected a 90s timeout, got %s", opts.Timeout)
+	}
+
+	if reason := m.validateRunOptions(runopts.Options{Profile: "production"}); reason == "" {
+		t.Error("Expected an unknown profile to be refused")
+	}
+}
+
+func TestRunOptionsProfileCycles(t *testing.T) {
+	form := RunOptionsForm{Field: optionProfile}
+	profiles := []string{"production", "staging"}
+	var seen []string
+	for range 3 {
+		form.toggle(profiles)
+		seen = append(seen, form.Options.Profile)
+	}
+	if !slices.Equal(seen, []string{"production", "staging", ""}) {
+		t.Errorf("Expected to cycle through the profiles and back to none, got %q", seen)
+	}
+}
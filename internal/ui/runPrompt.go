@@ -0,0 +1,207 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/Aj4x/tash/internal/task"
+	"github.com/Aj4x/tash/internal/taskfile"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// runInputs are the answers given to a task's prompts and required variables, reused for its
+// runs until the task list is reloaded
+type runInputs struct {
+	Vars      []string
+	Confirmed bool
+}
+
+// RunPrompt collects the input a task needs before it runs: confirmation of its prompts, then
+// values for its missing required variables
+type RunPrompt struct {
+	TaskId       string
+	Requirements taskfile.Requirements
+	Step         int    // Index of the current prompt, followed by the required variables
+	Input        string // Value typed for the current variable
+	Choice       int    // Selected value of the current variable when it has allowed values
+	Vars         []string
+	Defaults     string // Variables and CLI args the config passes to every run of the task
+}
+
+// promptStep reports whether the current step is a prompt rather than a variable
+func (p RunPrompt) promptStep() bool {
+	return p.Step < len(p.Requirements.Prompts)
+}
+
+// currentVar returns the required variable of the current step
+func (p RunPrompt) currentVar() taskfile.RequiredVar {
+	return p.Requirements.Vars[p.Step-len(p.Requirements.Prompts)]
+}
+
+// done reports whether every prompt and variable has been answered
+func (p RunPrompt) done() bool {
+	return p.Step >= len(p.Requirements.Prompts)+len(p.Requirements.Vars)
+}
+
+// taskRequirements returns the prompts and missing required variables of taskId, read from the
+// Taskfile defining it; variables the config passes to the task aren't missing. Tasks whose
+// Taskfile can't be read run without input.
+func (m Model) taskRequirements(taskId string) taskfile.Requirements {
+	if m.Config.Provider == task.ProviderDemo {
+		return taskfile.Requirements{}
+	}
+	t, ok := task.Find(m.AllTasks, taskId)
+	if !ok {
+		t = task.Task{Id: taskId}
+	}
+	tf, err := loadTaskfile(t)
+	if err != nil {
+		slog.Debug("Unable to read the Taskfile for requirements", "task", taskId, "error", err)
+		return taskfile.Requirements{}
+	}
+	req, err := tf.Requirements(t.Id)
+	if err != nil {
+		slog.Debug("Unable to read the requirements of task", "task", taskId, "error", err)
+		return taskfile.Requirements{}
+	}
+	req.Vars = slices.DeleteFunc(req.Vars, func(v taskfile.RequiredVar) bool {
+		return slices.ContainsFunc(m.Config.TaskVars[taskId], func(d string) bool { return strings.HasPrefix(d, v.Name+"=") })
+	})
+	return req
+}
+
+// inputsFor returns the inputs for a run of taskId. When the task has prompts or missing
+// required variables that haven't been answered yet, the run prompt is opened and ok is false.
+func (m *Model) inputsFor(taskId string) (inputs runInputs, ok bool) {
+	if inputs, ok := m.RunInputs[taskId]; ok {
+		return inputs, true
+	}
+	req := m.taskRequirements(taskId)
+	if m.Headless && !req.Empty() {
+		m.AppendErrorMsg(taskId + " needs input, run it from an attached tash")
+		return runInputs{}, false
+	}
+	if req.Empty() {
+		if m.RunInputs == nil {
+			m.RunInputs = map[string]runInputs{}
+		}
+		m.RunInputs[taskId] = runInputs{}
+		return runInputs{}, true
+	}
+	m.RunPrompt = RunPrompt{TaskId: taskId, Requirements: req, Defaults: m.taskDefaults(taskId)}
+	m.SetState(StateRunPrompt)
+	return runInputs{}, false
+}
+
+// RenderRunPrompt renders the overlay asking for a task's confirmation or required variables
+func RenderRunPrompt(width, height int, p RunPrompt) string {
+	overlayWidth := int(float64(width) * 0.7)
+	total := len(p.Requirements.Prompts) + len(p.Requirements.Vars)
+
+	content := TaskPickerTitleStyle.Render(fmt.Sprintf("Run %s (%d/%d)", p.TaskId, p.Step+1, total)) + "\n\n"
+	if p.Defaults != "" {
+		content += HelpStyle.Render("With "+p.Defaults+" from the config") + "\n\n"
+	}
+	switch {
+	case p.done():
+	case p.promptStep():
+		content += p.Requirements.Prompts[p.Step] + "\n\n"
+		content += HelpStyle.Render("y/enter to continue, n/esc to cancel the run")
+	case len(p.currentVar().Enum) > 0:
+		v := p.currentVar()
+		content += v.Name + ":\n"
+		for i, value := range v.Enum {
+			if i == p.Choice {
+				content += TaskPickerSelectedMatchStyle(overlayWidth).Render(value) + "\n"
+			} else {
+				content += TaskPickerMatchStyle(overlayWidth).Render(value) + "\n"
+			}
+		}
+		content += "\n" + HelpStyle.Render("↑/↓ to choose, enter to accept, esc to cancel the run")
+	default:
+		content += p.currentVar().Name + " = " + TaskPickerInputStyle(overlayWidth).Render(p.Input) + "\n\n"
+		content += HelpStyle.Render("Required by the task; enter to accept, esc to cancel the run")
+	}
+
+	overlay := GeneralOverlayStyle(overlayWidth).Render(content)
+
+	return placeOverlay(width, height, overlay)
+}
+
+// handleRunPromptKey handles key presses while answering a task's prompts and variables
+func (m Model) handleRunPromptKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	action := m.resolveKey(msg)
+	p := &m.RunPrompt
+	switch {
+	case action == ActionClose:
+		return m.cancelRunPrompt()
+	case p.promptStep() && IsKeyMatch(msg, "n"):
+		return m.cancelRunPrompt()
+	case action == ActionConfirm || (p.promptStep() && IsKeyMatch(msg, "y")):
+		return m.answerRunPrompt()
+	case p.promptStep():
+	case len(p.currentVar().Enum) > 0:
+		if action == ActionUp && p.Choice > 0 {
+			p.Choice--
+		}
+		if action == ActionDown && p.Choice < len(p.currentVar().Enum)-1 {
+			p.Choice++
+		}
+	case IsKeyMatch(msg, "backspace"):
+		if len(p.Input) > 0 {
+			p.Input = p.Input[:len(p.Input)-1]
+		}
+	case msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace:
+		p.Input += string(msg.Runes)
+		if msg.Type == tea.KeySpace {
+			p.Input += " "
+		}
+	}
+	return m, nil
+}
+
+// answerRunPrompt accepts the current step and runs the task once every step is answered
+func (m Model) answerRunPrompt() (tea.Model, tea.Cmd) {
+	p := &m.RunPrompt
+	if !p.promptStep() {
+		v := p.currentVar()
+		value := strings.TrimSpace(p.Input)
+		if len(v.Enum) > 0 {
+			value = v.Enum[p.Choice]
+		}
+		if value == "" {
+			// required variables can't be left empty
+			return m, nil
+		}
+		p.Vars = append(p.Vars, v.Name+"="+value)
+	}
+	p.Step++
+	p.Input, p.Choice = "", 0
+	if !p.done() {
+		return m, nil
+	}
+
+	if m.RunInputs == nil {
+		m.RunInputs = map[string]runInputs{}
+	}
+	m.RunInputs[p.TaskId] = runInputs{Vars: p.Vars, Confirmed: len(p.Requirements.Prompts) > 0}
+	m.SetState(StateNormal)
+	return m, m.runTask(p.TaskId)
+}
+
+// cancelRunPrompt abandons the run waiting for input, along with the batch or repeated run it
+// belongs to
+func (m Model) cancelRunPrompt() (tea.Model, tea.Cmd) {
+	m.SetState(StateNormal)
+	m.AppendErrorMsg("Run of " + m.RunPrompt.TaskId + " cancelled")
+	if m.ExecutingBatch {
+		m.ExecutingBatch = false
+		m.CurrentBatchTaskIndex = -1
+	}
+	m.Repeat = RepeatState{}
+	m.RunPrompt = RunPrompt{}
+	m.nextRunOptions = nil
+	return m, nil
+}
@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/task"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newRequirementsModel returns a model listing a deploy task that prompts and requires variables,
+// and a build task that needs no input
+func newRequirementsModel(t *testing.T) Model {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "Taskfile.yml")
+	content := `version: '3'
+tasks:
+  build: go build
+  deploy:
+    prompt: Deploy?
+    requires:
+      vars:
+        - API_KEY
+        - name: TARGET
+          enum: [staging, production]
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m := NewModel(nil, config.Default())
+	m.AllTasks = []task.Task{
+		{Id: "build", Location: &task.Location{Taskfile: path, Line: 3}},
+		{Id: "deploy", Location: &task.Location{Taskfile: path, Line: 4}},
+	}
+	return m
+}
+
+// pressPromptKeys sends each key to the model in the run prompt state
+func pressPromptKeys(m Model, keys ...tea.KeyMsg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+	for _, k := range keys {
+		var updated tea.Model
+		updated, cmd = m.handleRunPromptKey(k)
+		m = updated.(Model)
+	}
+	return m, cmd
+}
+
+func TestRunPromptCollectsInputBeforeRunning(t *testing.T) {
+	m := newRequirementsModel(t)
+	if cmd := m.runTask("deploy"); cmd != nil || m.State != StateRunPrompt {
+		t.Fatalf("Expected the run to wait for input, got state %s", m.State)
+	}
+	if m.TasksLoading {
+		t.Error("Expected the task not to be started yet")
+	}
+
+	m, _ = pressPromptKeys(m, runes("y"), runes("s3cret"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.RunPrompt.currentVar().Name != "TARGET" {
+		t.Fatalf("Expected to be asked for TARGET, got step %d", m.RunPrompt.Step)
+	}
+	m, cmd := pressPromptKeys(m, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil || m.State != StateNormal || !m.TasksLoading {
+		t.Fatalf("Expected the task to run once all input is given, got state %s", m.State)
+	}
+	inputs := m.RunInputs["deploy"]
+	if !inputs.Confirmed || !slices.Equal(inputs.Vars, []string{"API_KEY=s3cret", "TARGET=production"}) {
+		t.Errorf("Expected the answers to be remembered, got %+v", inputs)
+	}
+}
+
+func TestRunPromptRequiresAValue(t *testing.T) {
+	m := newRequirementsModel(t)
+	m.runTask("deploy")
+	m, _ = pressPromptKeys(m, tea.KeyMsg{Type: tea.KeyEnter}, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.State != StateRunPrompt || m.RunPrompt.currentVar().Name != "API_KEY" {
+		t.Errorf("Expected an empty value to be refused, got step %d", m.RunPrompt.Step)
+	}
+}
+
+func TestRunPromptCancelStopsBatch(t *testing.T) {
+	m := newRequirementsModel(t)
+	m.ExecutingBatch = true
+	m.runTask("deploy")
+	m, _ = pressPromptKeys(m, runes("n"))
+	if m.State != StateNormal || m.ExecutingBatch || m.TasksLoading {
+		t.Errorf("Expected the run and batch to be cancelled, got state %s, batch %v", m.State, m.ExecutingBatch)
+	}
+	if _, ok := m.RunInputs["deploy"]; ok {
+		t.Error("Expected no answers to be remembered after cancelling")
+	}
+}
+
+func TestTasksWithoutRequirementsRunStraightAway(t *testing.T) {
+	m := newRequirementsModel(t)
+	if cmd := m.runTask("build"); cmd == nil || m.State != StateNormal || !m.TasksLoading {
+		t.Errorf("Expected build to start without input, got state %s", m.State)
+	}
+}
@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Aj4x/tash/internal/schedule"
+	"github.com/Aj4x/tash/internal/task"
+)
+
+// RenderSchedulePrompt renders the overlay used to enter a schedule for a task
+func RenderSchedulePrompt(width, height int, selectedTask *task.Task, input string) string {
+	if selectedTask == nil {
+		return ""
+	}
+	overlayWidth := int(float64(width) * 0.7)
+
+	content := TaskPickerTitleStyle.Render("Schedule Task: "+selectedTask.Id) + "\n\n"
+	content += "Interval or cron: " + TaskPickerInputStyle(overlayWidth).Render(input) + "\n\n"
+	content += HelpStyle.Render("e.g. \"10m\", \"1h30m\" or \"*/15 * * * *\"")
+
+	overlay := GeneralOverlayStyle(overlayWidth).Render(content)
+
+	return placeOverlay(width, height, overlay)
+}
+
+// RenderScheduleOverlay renders the list of scheduled tasks with their next run times. With
+// reducedMotion the time left is counted down in minutes rather than seconds.
+func RenderScheduleOverlay(width, height int, entries []schedule.Entry, selectedIndex int, now time.Time, tf TimeFormat, reducedMotion bool) string {
+	overlayWidth := int(float64(width) * 0.7)
+
+	content := TaskPickerTitleStyle.Render("Scheduled Tasks") + "\n\n"
+	if len(entries) == 0 {
+		content += "No scheduled tasks. Press 's' on a task to schedule it.\n"
+	}
+	precision := time.Second
+	if reducedMotion {
+		precision = time.Minute
+	}
+	for i, e := range entries {
+		line := fmt.Sprintf("%s (%s) next run %s, in %s",
+			e.TaskId, e.Schedule, tf.Format(e.NextRun, time.Kitchen), e.NextRun.Sub(now).Round(precision))
+		if i == selectedIndex {
+			content += TaskPickerSelectedMatchStyle(overlayWidth).Render(line) + "\n"
+		} else {
+			content += TaskPickerMatchStyle(overlayWidth).Render(line) + "\n"
+		}
+	}
+
+	overlay := GeneralOverlayStyle(overlayWidth).Render(content)
+
+	return placeOverlay(width, height, overlay)
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/schedule"
+	"github.com/Aj4x/tash/internal/task"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// loadSchedules creates schedule entries from the config, skipping (and logging) invalid specs.
+// A read-only tash runs nothing on a schedule.
+func loadSchedules(cfg config.Config, now time.Time) []schedule.Entry {
+	if cfg.ReadOnly {
+		return nil
+	}
+	var entries []schedule.Entry
+	for _, sc := range cfg.Schedules {
+		s, err := schedule.Parse(sc.Schedule)
+		if err != nil {
+			slog.Warn("ignoring scheduled task", "task", sc.Task, "error", err)
+			continue
+		}
+		entries = append(entries, schedule.NewEntry(sc.Task, s, now))
+	}
+	return entries
+}
+
+// nextScheduled returns the entry that will run soonest, or nil if nothing is scheduled
+func nextScheduled(entries []schedule.Entry) *schedule.Entry {
+	var next *schedule.Entry
+	for i := range entries {
+		if entries[i].NextRun.IsZero() {
+			continue
+		}
+		if next == nil || entries[i].NextRun.Before(next.NextRun) {
+			next = &entries[i]
+		}
+	}
+	return next
+}
+
+// runDueSchedules starts the first scheduled task that is due. While another task is running
+// due entries are left pending and run as soon as tash is idle.
+func (m Model) runDueSchedules(now time.Time) (Model, tea.Cmd) {
+	if m.TasksLoading || m.ExecutingBatch {
+		return m, nil
+	}
+	for i, e := range m.Schedules {
+		if !e.Due(now) {
+			continue
+		}
+		// copy before updating so earlier model values don't see the change
+		m.Schedules = append([]schedule.Entry(nil), m.Schedules...)
+		m.Schedules[i] = e.Advance(now)
+		m.AppendAppMsg(fmt.Sprintf("Scheduled run (%s): %s\n", e.Schedule, e.TaskId))
+		return m, m.executeTask(task.Task{Id: e.TaskId})
+	}
+	return m, nil
+}
+
+// addSchedule schedules taskId using spec, reporting invalid specs in the output
+func (m *Model) addSchedule(taskId, spec string) bool {
+	s, err := schedule.Parse(spec)
+	if err != nil {
+		m.AppendErrorMsg(err.Error())
+		return false
+	}
+	entry := schedule.NewEntry(taskId, s, time.Now())
+	m.Schedules = append(append([]schedule.Entry(nil), m.Schedules...), entry)
+	m.AppendAppMsg(fmt.Sprintf("Scheduled task '%s' %s, next run at %s\n", taskId, s, m.TimeFormat.Format(entry.NextRun, time.Kitchen)))
+	return true
+}
+
+// removeSchedule removes the scheduled entry at index i
+func (m *Model) removeSchedule(i int) {
+	if i < 0 || i >= len(m.Schedules) {
+		return
+	}
+	m.AppendAppMsg(fmt.Sprintf("Removed schedule for task '%s'\n", m.Schedules[i].TaskId))
+	schedules := append([]schedule.Entry(nil), m.Schedules[:i]...)
+	m.Schedules = append(schedules, m.Schedules[i+1:]...)
+	if m.ScheduleSelected >= len(m.Schedules) && m.ScheduleSelected > 0 {
+		m.ScheduleSelected--
+	}
+}
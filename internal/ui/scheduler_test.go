@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/schedule"
+)
+
+func TestRunDueSchedules(t *testing.T) {
+	now := time.Now()
+	cfg := config.Default()
+	cfg.Schedules = []config.ScheduleConfig{
+		{Task: "lint", Schedule: "10m"},
+		{Task: "broken", Schedule: "whenever"},
+	}
+	m := NewModel(nil, cfg)
+	if len(m.Schedules) != 1 {
+		t.Fatalf("Expected invalid schedules to be skipped, got %d entries", len(m.Schedules))
+	}
+
+	if _, cmd := m.runDueSchedules(now); cmd != nil {
+		t.Error("Expected no run before the schedule is due")
+	}
+
+	firstRun := m.Schedules[0].NextRun
+	due := firstRun.Add(time.Second)
+	next, cmd := m.runDueSchedules(due)
+	if cmd == nil {
+		t.Fatal("Expected the due task to be executed")
+	}
+	if !next.TasksLoading {
+		t.Error("Expected the model to be marked as running a task")
+	}
+	if !next.Schedules[0].NextRun.After(due) {
+		t.Errorf("Expected the next run to be advanced past %s, got %s", due, next.Schedules[0].NextRun)
+	}
+	if !m.Schedules[0].NextRun.Equal(firstRun) {
+		t.Error("Expected the original model's schedules to be unchanged")
+	}
+
+	if _, cmd := next.runDueSchedules(due.Add(10 * time.Minute)); cmd != nil {
+		t.Error("Expected scheduled runs to wait while a task is running")
+	}
+}
+
+func TestScheduleTimesHonourTheTimeFormat(t *testing.T) {
+	cfg := config.Default()
+	cfg.TimeFormat = "datetime"
+	cfg.TimeZone = "Asia/Tokyo"
+	m := NewModel(nil, cfg)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	m.Schedules = []schedule.Entry{{TaskId: "lint", NextRun: now.Add(time.Hour)}}
+
+	view := RenderScheduleOverlay(120, 40, m.Schedules, 0, now, m.TimeFormat, false)
+	if !strings.Contains(view, "2024-03-01 22:00:00") {
+		t.Errorf("Expected the next run in Tokyo time, got %q", view)
+	}
+}
+
+func TestReducedMotionKeepsTheCountdownStill(t *testing.T) {
+	m := NewModel(nil, config.Default())
+	m.HandleWindowResize(120, 40)
+	now := time.Now()
+	m.Schedules = []schedule.Entry{{TaskId: "lint", NextRun: now.Add(110 * time.Second)}}
+
+	if view := RenderScheduleOverlay(m.Width, m.Height, m.Schedules, 0, now, m.TimeFormat, m.ReducedMotion); !strings.Contains(view, "in 1m50s") {
+		t.Errorf("Expected the time left in seconds, got %q", view)
+	}
+	updated, _ := m.handleKeyMsg(runes("Q"))
+	if m = updated.(Model); !m.ReducedMotion {
+		t.Fatal("Expected Q to turn reduced motion on")
+	}
+	if view := RenderScheduleOverlay(m.Width, m.Height, m.Schedules, 0, now, m.TimeFormat, m.ReducedMotion); !strings.Contains(view, "in 2m0s") {
+		t.Errorf("Expected the time left in minutes, got %q", view)
+	}
+}
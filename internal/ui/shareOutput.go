@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/Aj4x/tash/internal/paste"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// pasteMsg carries the link to the shared output of a run, or why it couldn't be shared
+type pasteMsg struct {
+	TaskId string
+	URL    string
+	Err    error
+}
+
+// shareRunOutput uploads the output of the selected or latest run, without colors, to the paste
+// service configured with paste_url
+func (m *Model) shareRunOutput() tea.Cmd {
+	if m.Config.PasteURL == "" {
+		m.AppendErrorMsg("Set paste_url in the config to share output")
+		return nil
+	}
+	taskId, lines, ok := m.exportedRun()
+	if !ok {
+		return nil
+	}
+	var content strings.Builder
+	for _, line := range lines {
+		content.WriteString(ansiPattern.ReplaceAllString(line, "") + "\n")
+	}
+	client := paste.New(m.Config.PasteURL, os.Getenv(m.Config.PasteTokenEnv))
+	name := "tash-" + strings.NewReplacer(":", "-", "/", "-").Replace(taskId) + ".log"
+	m.AppendAppMsg("Sharing the output of " + taskId + "…\n")
+	return func() tea.Msg {
+		link, err := client.Upload(context.Background(), name, content.String())
+		return pasteMsg{TaskId: taskId, URL: link, Err: err}
+	}
+}
+
+// handlePasteMsg reports the link to the shared output, copying it to the clipboard
+func (m Model) handlePasteMsg(msg pasteMsg) (Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.AppendErrorMsg("Unable to share the output of " + msg.TaskId + ": " + msg.Err.Error())
+		return m, nil
+	}
+	m.AppendAppMsg("Shared the output of " + msg.TaskId + " at " + msg.URL + " (copied to the clipboard)\n")
+	return m, copyToClipboard(msg.URL)
+}
@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestShareRunOutputUploadsThePlainOutput(t *testing.T) {
+	var uploaded, auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		uploaded, auth = string(body), r.Header.Get("Authorization")
+		w.Write([]byte("https://paste.example.com/abc"))
+	}))
+	defer server.Close()
+	var clipboard bytes.Buffer
+	previous := clipboardOut
+	clipboardOut = &clipboard
+	t.Cleanup(func() { clipboardOut = previous })
+
+	m := newFoldModel()
+	if cmd := m.shareRunOutput(); cmd != nil || !strings.Contains(m.output.String(), "Set paste_url") {
+		t.Fatal("Expected sharing to need a paste endpoint")
+	}
+
+	m.Config.PasteURL = server.URL
+	m.Config.PasteTokenEnv = "TASH_TEST_PASTE_TOKEN"
+	t.Setenv("TASH_TEST_PASTE_TOKEN", "t0ken")
+	cmd := m.shareRunOutput()
+	if cmd == nil {
+		t.Fatal("Expected the output to be uploaded")
+	}
+	updated, clip := m.Update(cmd())
+	m = updated.(Model)
+	if !strings.HasPrefix(uploaded, "Executing task: build\ntask: [generate]") || !strings.HasSuffix(uploaded, "compiling 4\n") {
+		t.Errorf("Expected the latest run to be uploaded, got %q", uploaded)
+	}
+	if auth != "Bearer t0ken" {
+		t.Errorf("Expected the token from the environment, got %q", auth)
+	}
+	if !strings.Contains(m.output.String(), "https://paste.example.com/abc") {
+		t.Errorf("Expected the link to be reported, got:\n%s", m.output.String())
+	}
+	if clip == nil {
+		t.Fatal("Expected the link to be copied")
+	}
+	clip()
+	if clipboard.Len() == 0 {
+		t.Error("Expected the link on the clipboard")
+	}
+}
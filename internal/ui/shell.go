@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/Aj4x/tash/internal/task"
+	"github.com/Aj4x/tash/internal/taskfile"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// shellExitedMsg is sent when the shell opened from tash exits
+type shellExitedMsg struct {
+	err error
+}
+
+// userShell returns the user's shell: $SHELL, or the platform's default shell
+func userShell() string {
+	if shell := os.Getenv("SHELL"); shell != "" {
+		return shell
+	}
+	if runtime.GOOS == "windows" {
+		if comspec := os.Getenv("COMSPEC"); comspec != "" {
+			return comspec
+		}
+		return "cmd.exe"
+	}
+	return "/bin/sh"
+}
+
+// openShell suspends the UI and runs the user's shell in the project directory and the
+// environment tasks run in, resuming when the shell exits
+func (m *Model) openShell() tea.Cmd {
+	shell := userShell()
+	m.AppendAppMsg("Opening " + shell + ", exit it to return to tash\n")
+	cmd := ExecEnvironment(m.Config).Command(context.Background(), nil, shell)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return shellExitedMsg{err: err}
+	})
+}
+
+// taskEnvironment resolves the directory and environment t runs with from the Taskfile that
+// defines it
+func taskEnvironment(t task.Task) (taskfile.TaskEnv, error) {
+	tf, err := loadTaskfile(t)
+	if err != nil {
+		return taskfile.TaskEnv{}, err
+	}
+	return tf.Environment(t.Id)
+}
+
+// openTaskShell suspends the UI and runs the user's shell in the directory and environment of t,
+// so its commands can be tried by hand. Template variables aren't exported by task, so they are
+// listed for reference instead.
+func (m *Model) openTaskShell(t task.Task) tea.Cmd {
+	if m.Config.Provider == task.ProviderDemo {
+		m.AppendErrorMsg("Demo tasks have no Taskfile environment")
+		return nil
+	}
+	env, err := taskEnvironment(t)
+	if err != nil {
+		m.AppendErrorMsg("Resolving the environment of " + t.Id + ": " + err.Error())
+		return nil
+	}
+	shell := userShell()
+	m.AppendAppMsg(fmt.Sprintf("Opening %s in %s with the environment of %s, exit it to return to tash\n", shell, env.Dir, t.Id))
+	for _, v := range env.Vars {
+		m.AppendAppMsg(fmt.Sprintf("  %s=%s\n", v.Name, v.Value))
+	}
+	cmd := exec.Command(shell)
+	cmd.Dir = env.Dir
+	cmd.Env = append(env.Env, "TASH_TASK="+t.Id)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return shellExitedMsg{err: err}
+	})
+}
+
+// handleShellExited resumes after the shell exits, reloading the task list in case the Taskfile
+// was edited from the shell
+func (m Model) handleShellExited(msg shellExitedMsg) (Model, tea.Cmd) {
+	if msg.err != nil {
+		m.AppendErrorMsg("Shell exited with an error: " + msg.err.Error())
+	}
+	m.AppendAppMsg("Returned from shell\n")
+	if m.TasksLoading {
+		// a task is still running, so leave the task list alone
+		return m, nil
+	}
+	return m, m.RefreshTaskList()
+}
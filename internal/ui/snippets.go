@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/Aj4x/tash/internal/task"
+	"github.com/Aj4x/tash/internal/taskfile"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// SnippetPicker holds the snippet picked to add to the Taskfile and the project it is for
+type SnippetPicker struct {
+	Path     string // Taskfile the snippet is added to, created when it doesn't exist
+	Selected int
+	Project  string // Name of the project the snippet is written for
+	Error    string // Reason the snippet couldn't be added, shown until the choice changes
+}
+
+// openSnippets shows the library of snippets, suggesting the project name from the directory
+func (m *Model) openSnippets() {
+	if m.Config.Provider == task.ProviderDemo {
+		m.AppendErrorMsg("Demo tasks are not defined in a Taskfile")
+		return
+	}
+	if task.IsRemoteTaskfile(m.Config.Taskfile) {
+		m.AppendErrorMsg("Snippets can't be added to a remote Taskfile")
+		return
+	}
+	dir := m.catalogDir()
+	path := m.Config.Taskfile
+	if path == "" {
+		var err error
+		if path, err = taskfile.Find(dir); taskfile.IsNotFound(err) {
+			path = filepath.Join(dir, taskfile.DefaultNames[0])
+		}
+	}
+	m.Snippets = SnippetPicker{Path: path, Project: taskfile.ProjectName(dir)}
+	m.SetState(StateSnippets)
+}
+
+// handleSnippetsKey handles key presses while picking a snippet; typing edits the project name
+func (m Model) handleSnippetsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	picker := &m.Snippets
+	switch action := m.resolveKey(msg); {
+	case action == ActionClose:
+		m.SetState(StateNormal)
+	case action == ActionConfirm:
+		cmd := m.insertSnippet()
+		return m, cmd
+	case action == ActionUp:
+		picker.Selected = max(picker.Selected-1, 0)
+		picker.Error = ""
+	case action == ActionDown:
+		picker.Selected = min(picker.Selected+1, len(taskfile.Snippets)-1)
+		picker.Error = ""
+	case IsKeyMatch(msg, "backspace"):
+		if runes := []rune(picker.Project); len(runes) > 0 {
+			picker.Project = string(runes[:len(runes)-1])
+		}
+		picker.Error = ""
+	case msg.Type == tea.KeyRunes:
+		picker.Project += string(msg.Runes)
+		picker.Error = ""
+	}
+	return m, nil
+}
+
+// insertSnippet adds the picked snippet to the Taskfile and opens it in the user's editor at
+// the added tasks. The task list is refreshed once the editor exits.
+func (m *Model) insertSnippet() tea.Cmd {
+	picker := m.Snippets
+	snippet := taskfile.Snippets[picker.Selected]
+	line, err := taskfile.InsertSnippet(picker.Path, snippet, picker.Project)
+	if err != nil {
+		m.Snippets.Error = err.Error()
+		return nil
+	}
+	m.SetState(StateNormal)
+	m.AppendAppMsg(fmt.Sprintf("Added the %s snippet to %s\n", snippet.Name, picker.Path))
+	return m.openEditor(picker.Path, line)
+}
+
+// RenderSnippets renders the library of snippets with the project name they are written for
+func RenderSnippets(width, height int, picker SnippetPicker) string {
+	overlayWidth := int(float64(width) * 0.7)
+
+	content := TaskPickerTitleStyle.Render("Add a snippet") + "\n\n"
+	for i, s := range taskfile.Snippets {
+		line := fmt.Sprintf("%-14s %s", s.Name, s.Description)
+		if i == picker.Selected {
+			content += TaskPickerSelectedMatchStyle(overlayWidth).Render(line) + "\n"
+		} else {
+			content += TaskPickerMatchStyle(overlayWidth).Render(line) + "\n"
+		}
+	}
+	content += "\n" + HelpStyle.Render("Project name") + "\n"
+	content += TaskPickerInputStyle(overlayWidth).Render(picker.Project) + "\n\n"
+	if picker.Error != "" {
+		content += ErrorMsgStyle.Render(picker.Error) + "\n\n"
+	}
+	content += HelpStyle.Render("↑/↓ choose, enter add to " + picker.Path + ", esc cancel")
+
+	return placeOverlay(width, height, GeneralOverlayStyle(overlayWidth).Render(content))
+}
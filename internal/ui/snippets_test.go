@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSnippetsCreateTheTaskfile(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "webapp")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("EDITOR", "true")
+	cfg := config.Default()
+	cfg.WorkDir = dir
+	m := NewModel(nil, cfg)
+	m.HandleWindowResize(120, 40)
+
+	m.openSnippets()
+	if m.State != StateSnippets || m.Snippets.Project != "webapp" {
+		t.Fatalf("Expected the picker with the project named after the directory, got state %s and %q", m.State, m.Snippets.Project)
+	}
+	updated, _ := m.handleSnippetsKey(tea.KeyMsg{Type: tea.KeyDown})
+	updated, _ = updated.(Model).handleSnippetsKey(runes("-2"))
+	updated, cmd := updated.(Model).handleSnippetsKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if m = updated.(Model); cmd == nil || m.State != StateNormal {
+		t.Fatalf("Expected the editor to be opened, got state %s and error %q", m.State, m.Snippets.Error)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "Taskfile.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "  test:\n    desc: Test webapp-2\n") {
+		t.Errorf("Expected the go test snippet for the project, got:\n%s", data)
+	}
+
+	m.openSnippets()
+	m.Snippets.Selected = 1
+	updated, cmd = m.handleSnippetsKey(tea.KeyMsg{Type: tea.KeyEnter})
+	if m = updated.(Model); cmd != nil || m.State != StateSnippets || m.Snippets.Error == "" {
+		t.Errorf("Expected an existing task to be reported in the picker, got state %s", m.State)
+	}
+}
@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// startupTimer logs to the debug log how long after the start of tash each startup milestone was
+// reached, once per milestone. It is shared by the copies of the model.
+type startupTimer struct {
+	start  time.Time
+	mu     sync.Mutex
+	logged map[string]bool
+}
+
+// TimeStartup logs the startup milestones, such as the first render and the task list arriving,
+// measured from start
+func (m *Model) TimeStartup(start time.Time) {
+	m.startup = &startupTimer{start: start, logged: map[string]bool{}}
+}
+
+// mark logs the time taken to reach milestone, the first time it is reached
+func (t *startupTimer) mark(milestone string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.logged[milestone] {
+		return
+	}
+	t.logged[milestone] = true
+	slog.Info("startup timing", "milestone", milestone, "elapsed", time.Since(t.start))
+}
@@ -1,7 +1,9 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
+	"os"
 
 	"github.com/Aj4x/tash/internal/task"
 	tea "github.com/charmbracelet/bubbletea"
@@ -9,24 +11,98 @@ import (
 
 // handleNormalKey handles key presses when in the normal state
 func (m Model) handleNormalKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Collect a count prefix for the navigation that follows
+	if len(m.PendingKeys) == 0 && m.addCountDigit(msg) {
+		return m, nil
+	}
+	count := m.Count
+	m.Count = 0
+
+	action := m.resolveKey(msg)
+	if len(m.PendingKeys) > 0 {
+		// keep the count for the action the chord resolves to
+		m.Count = count
+	}
+	if m.refuseReadOnly(action) {
+		return m, nil
+	}
+
 	// Quit
-	if IsKeyMatch(msg, "q") {
+	if action == ActionQuit {
+		if m.Embedded {
+			return m, nil
+		}
+		m.removeSpill()
 		return m, tea.Quit
 	}
 
 	// Clear output
-	if IsKeyMatch(msg, "ctrl+l") {
+	if action == ActionClearOutput {
 		if m.TasksLoading {
 			return m, nil
 		}
-		m.Result = new(string)
-		m.Viewport.SetContent(*m.Result)
+		m.clearOutput()
 		m.Viewport.GotoTop()
 		return m, nil
 	}
 
+	// Show the summary of the last failed run
+	if action == ActionFailureSummary {
+		m.openFailureSummary()
+		return m, nil
+	}
+
+	// Show the whole output, including lines spilled to disk, in the pager
+	if action == ActionFullOutput {
+		return m, m.openFullOutput()
+	}
+
+	// Export the output of the selected or latest run, with its colors, as HTML
+	if action == ActionExportHTML {
+		m.exportRunHTML()
+		return m, nil
+	}
+
+	// Upload the output of the selected or latest run to the paste service
+	if action == ActionShareOutput {
+		return m, m.shareRunOutput()
+	}
+
+	// List the problems found in the output
+	if action == ActionProblems {
+		m.openProblems()
+		return m, nil
+	}
+
+	// List the problems found in the Taskfiles
+	if action == ActionDiagnostics {
+		m.openDiagnostics()
+		return m, nil
+	}
+
+	// Fold the output of runs and their steps
+	switch action {
+	case ActionToggleFold:
+		m.toggleFold()
+		return m, nil
+	case ActionToggleAllFolds:
+		m.toggleAllFolds()
+		return m, nil
+	case ActionNextFold:
+		m.moveFoldCursor(1)
+		return m, nil
+	case ActionPrevFold:
+		m.moveFoldCursor(-1)
+		return m, nil
+	}
+
+	// Repair the terminal after a task has garbled it
+	if action == ActionRepairDisplay {
+		return m, m.repairDisplay()
+	}
+
 	// Refresh tasks
-	if IsKeyMatch(msg, "ctrl+r") {
+	if action == ActionRefresh {
 		if m.TasksLoading {
 			return m, nil
 		}
@@ -34,47 +110,36 @@ func (m Model) handleNormalKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 
 	// Task details
-	if IsKeyMatch(msg, "i") {
+	if action == ActionDetails {
 		if m.Focused == ControlTable && len(m.Tasks) > 0 && m.Table.SelectedRow() != nil {
 			selectedIndex := m.Table.Cursor()
 			m.SelectedTask = &m.Tasks[selectedIndex]
-			m.State = StateDetailsOverlay
+			m.CommandPreview = m.commandPreview(*m.SelectedTask)
+			m.SetState(StateDetailsOverlay)
 		}
 		return m, nil
 	}
 
 	// Switch focus
-	if IsKeyMatch(msg, "tab") {
+	if action == ActionSwitchFocus {
 		m.Focused = m.Focused.Tab()
 		if m.Focused == ControlTable {
 			m.Table.Focus()
+			m.announce("Focus: task list")
 		} else {
 			m.Table.Blur()
+			m.announce("Focus: output")
 		}
 		return m, nil
 	}
 
 	// Navigation
-	if IsKeyMatch(msg, "↑/↓/j/k") || IsKeyMatch(msg, "pgup/pgdn") {
-		var cmds []tea.Cmd
-		var cmd tea.Cmd
-
-		switch m.Focused {
-		case ControlTable:
-			m.Table, cmd = m.Table.Update(msg)
-			cmds = append(cmds, cmd)
-		case ControlViewport:
-			m.Viewport, cmd = m.Viewport.Update(msg)
-			cmds = append(cmds, cmd)
-		default:
-			return m, nil
-		}
-
-		return m, tea.Batch(cmds...)
+	if m.navigate(action, count) {
+		return m, nil
 	}
 
 	// Execute task
-	if IsKeyMatch(msg, "enter/e") {
+	if action == ActionExecute {
 		if m.TasksLoading {
 			return m, nil
 		}
@@ -86,41 +151,40 @@ func (m Model) handleNormalKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Pause or resume the running task
+	if action == ActionPause {
+		if m.TaskRunning && m.Command != nil && m.Command.Process != nil {
+			m.togglePause()
+		}
+		return m, nil
+	}
+
 	// Cancel task
-	if IsKeyMatch(msg, "ctrl+x") {
+	if action == ActionCancel {
 		if m.TaskRunning {
-			m.CommandCancel()
-			m.TaskRunning = false
-			m.Command = nil
-			m.CommandCancel = nil
-			if m.ExecutingBatch {
-				m.ExecutingBatch = false
-				m.CurrentBatchTaskIndex = -1
-			}
-			if m.TasksLoading {
-				m.TasksLoading = false
-			}
-			m.AppendAppMsg("Task cancellation requested\n")
+			m.cancelTask()
 		}
 		return m, nil
 	}
 
 	// Open task picker
-	if IsKeyMatch(msg, "/") {
+	if action == ActionOpenPicker {
 		if m.TasksLoading {
 			return m, nil
 		}
 
-		m.State = StateTaskPicker
+		m.SetState(StateTaskPicker)
 		m.TaskPickerInput = ""
-		m.TaskPickerMatches = m.Tasks // Initialize with all tasks
+		m.TaskPickerMatches = m.pickerTasks() // All tasks, including hidden ones, most frecent first
+		m.TaskPickerReasons = nil
+		m.TaskPickerSources = m.taskSources()
 		m.TaskPickerSelected = 0
 
 		return m, nil
 	}
 
 	// Execute selected tasks
-	if IsKeyMatch(msg, "ctrl+e") {
+	if action == ActionExecuteBatch {
 		if m.TasksLoading || len(m.SelectedTasks) == 0 || m.ExecutingBatch {
 			return m, nil
 		}
@@ -134,8 +198,21 @@ func (m Model) handleNormalKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.executeNextSelectedTask(m.CurrentBatchTaskIndex)
 	}
 
+	// Execute every task in the namespace of the selected task
+	if action == ActionRunNamespace {
+		if m.Focused != ControlTable || len(m.Tasks) == 0 || m.Table.SelectedRow() == nil {
+			return m, nil
+		}
+		selected := m.Tasks[m.Table.Cursor()]
+		if selected.Namespace() == "" {
+			m.AppendErrorMsg(selected.Id + " isn't in a namespace\n")
+			return m, nil
+		}
+		return m.runNamespace(selected.Namespace())
+	}
+
 	// Clear selected tasks
-	if IsKeyMatch(msg, "ctrl+d") {
+	if action == ActionClearBatch {
 		if len(m.SelectedTasks) > 0 {
 			m.SelectedTasks = []task.Task{}
 			m.AppendAppMsg("Selected tasks cleared\n")
@@ -143,25 +220,231 @@ func (m Model) handleNormalKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// Run the selected task repeatedly until it fails
+	if action == ActionRepeat {
+		if m.TasksLoading || m.ExecutingBatch {
+			return m, nil
+		}
+		if m.Focused == ControlTable && len(m.Tasks) > 0 && m.Table.SelectedRow() != nil {
+			return m.startRepeat(m.Tasks[m.Table.Cursor()])
+		}
+		return m, nil
+	}
+
+	// Run the selected task in an external terminal window
+	if action == ActionRunExternal {
+		if m.Focused == ControlTable && len(m.Tasks) > 0 && m.Table.SelectedRow() != nil {
+			m.runInExternalTerminal(m.Tasks[m.Table.Cursor()])
+		}
+		return m, nil
+	}
+
+	// Enter a shell command to run
+	if action == ActionCommandLine {
+		m.CommandInput = ""
+		m.SetState(StateCommandLine)
+		return m, nil
+	}
+
+	// Drop to a shell, resuming when it exits
+	if action == ActionShell {
+		return m, m.openShell()
+	}
+
+	// Compare the selected task's last two runs
+	if action == ActionDiff {
+		if m.Focused == ControlTable && len(m.Tasks) > 0 && m.Table.SelectedRow() != nil {
+			m.openDiff(m.Tasks[m.Table.Cursor()].Id)
+		}
+		return m, nil
+	}
+
+	// Show task duration statistics
+	if action == ActionStats {
+		m.openStats()
+		return m, nil
+	}
+
+	// Show or hide internal and ignored tasks
+	if action == ActionToggleHidden {
+		m.ShowHidden = !m.ShowHidden
+		m.applyTaskFilter()
+		m.UpdateTaskTable()
+		if m.ShowHidden {
+			m.AppendAppMsg("Showing hidden tasks\n")
+		} else {
+			m.AppendAppMsg(fmt.Sprintf("Hiding %d tasks\n", m.HiddenCount))
+		}
+		return m, nil
+	}
+
+	// Choose the options of a single run of the selected task
+	if action == ActionRunOptions {
+		if m.TasksLoading || m.ExecutingBatch {
+			return m, nil
+		}
+		if m.Focused == ControlTable && len(m.Tasks) > 0 && m.Table.SelectedRow() != nil {
+			m.openRunOptions(m.Tasks[m.Table.Cursor()])
+		}
+		return m, nil
+	}
+
+	// Cycle between normal, verbose and silent task runs
+	if action == ActionVerbosity {
+		m.Verbosity = m.Verbosity.Next()
+		if flag := m.Verbosity.Flag(); flag != "" {
+			m.AppendAppMsg("Tasks now run with " + flag + "\n")
+		} else {
+			m.AppendAppMsg("Tasks now run without --verbose or --silent\n")
+		}
+		return m, nil
+	}
+
+	// Redraw less often, for slow connections and screen readers
+	if action == ActionReducedMotion {
+		m.ReducedMotion = !m.ReducedMotion
+		if m.ReducedMotion {
+			m.AppendAppMsg("Reduced motion on: the screen is redrawn a few times a second at most\n")
+		} else {
+			m.AppendAppMsg("Reduced motion off\n")
+		}
+		return m, nil
+	}
+
+	// Show how fast the views render
+	if action == ActionRenderStats {
+		m.toggleRenderStats()
+		return m, nil
+	}
+
+	// Group the task list by the Taskfile defining each task
+	if action == ActionGroupIncludes {
+		m.GroupIncludes = !m.GroupIncludes
+		m.applyTaskFilter()
+		m.UpdateTaskTable()
+		if m.GroupIncludes {
+			m.AppendAppMsg("Grouping tasks by Taskfile\n")
+		} else {
+			m.AppendAppMsg("Listing tasks in Taskfile order\n")
+		}
+		return m, nil
+	}
+
+	// Group the task list by label
+	if action == ActionGroupLabels {
+		m.GroupLabels = !m.GroupLabels
+		m.applyTaskFilter()
+		m.UpdateTaskTable()
+		if m.GroupLabels {
+			m.AppendAppMsg("Grouping tasks by label\n")
+		} else {
+			m.AppendAppMsg("Listing tasks without grouping by label\n")
+		}
+		return m, nil
+	}
+
+	// List the tasks of the next label
+	if action == ActionLabelFilter {
+		m.cycleLabelFilter()
+		return m, nil
+	}
+
+	// Hide or pin namespaces
+	if action == ActionNamespaces {
+		m.openNamespaces()
+		return m, nil
+	}
+
+	// Open the Taskfile defining the selected task
+	if action == ActionEditTaskfile {
+		if m.Focused == ControlTable && len(m.Tasks) > 0 && m.Table.SelectedRow() != nil {
+			return m, m.openTaskfile(m.Tasks[m.Table.Cursor()])
+		}
+		return m, nil
+	}
+
+	// Add a snippet to the Taskfile
+	if action == ActionSnippets {
+		m.openSnippets()
+		return m, nil
+	}
+
+	// Import the targets of the Makefile
+	if action == ActionMakeImport {
+		m.openMakeImport()
+		return m, nil
+	}
+
+	// Clone the selected task
+	if action == ActionCloneTask {
+		if m.Focused == ControlTable && len(m.Tasks) > 0 && m.Table.SelectedRow() != nil {
+			m.openClonePrompt(m.Tasks[m.Table.Cursor()])
+		}
+		return m, nil
+	}
+
+	// Use a remote Taskfile
+	if action == ActionRemoteTaskfile {
+		m.openRemotePrompt()
+		return m, nil
+	}
+
+	// Toggle file watchers
+	if action == ActionToggleWatchers {
+		return m.toggleWatchers()
+	}
+
+	// Schedule the selected task
+	if action == ActionSchedule {
+		if m.Focused == ControlTable && len(m.Tasks) > 0 && m.Table.SelectedRow() != nil {
+			m.SelectedTask = &m.Tasks[m.Table.Cursor()]
+			m.ScheduleInput = ""
+			m.SetState(StateSchedulePrompt)
+		}
+		return m, nil
+	}
+
+	// Run the selected task on a host group
+	if action == ActionFanOut {
+		return m, m.openFanOut()
+	}
+
+	// Show the services of the compose file
+	if action == ActionCompose {
+		return m, m.openCompose()
+	}
+
+	// Show the pre-flight checklist
+	if action == ActionPreflight {
+		m.openPreflight()
+		return m, nil
+	}
+
+	// Show scheduled tasks
+	if action == ActionSchedules {
+		m.ScheduleSelected = 0
+		m.SetState(StateScheduleOverlay)
+		return m, nil
+	}
+
+	// Edit key bindings
+	if action == ActionKeyBindings {
+		m.openKeyBindings()
+		return m, nil
+	}
+
 	// Show help
-	if IsKeyMatch(msg, "?") {
-		m.State = StateHelpOverlay
-
-		// Calculate overlay dimensions
-		overlayWidth := int(float64(m.Width) * 0.7)
-		overlayHeight := int(float64(m.Height) * 0.7)
-		contentWidth := overlayWidth - 6    // 6 = 2*2 padding + 2 border
-		viewportHeight := overlayHeight - 6 // Account for padding and borders
-
-		// Set viewport dimensions
-		m.HelpViewport.Width = contentWidth
-		m.HelpViewport.Height = viewportHeight
-
-		// Generate and set content
-		content := m.KeyBindings.GenerateHelpContent(overlayWidth)
-		m.HelpViewport.SetContent(content)
-		m.HelpViewport.GotoTop()
+	if action == ActionHelp {
+		m.SetState(StateHelpOverlay)
+		m.HelpSearch = ""
+		m.HelpShowAll = false
+		m.refreshHelpContent()
+		return m, nil
+	}
 
+	// Show the details for a bug report
+	if action == ActionAbout {
+		m.SetState(StateAboutOverlay)
 		return m, nil
 	}
 
@@ -170,38 +453,329 @@ func (m Model) handleNormalKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 // handleDetailsOverlayKey handles key presses when in the details overlay state
 func (m Model) handleDetailsOverlayKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	action := m.resolveKey(msg)
+
 	// Check for keys that close the details overlay
-	if IsKeyMatch(msg, "esc/i") {
-		m.State = StateNormal
+	if action == ActionClose || m.KeyBindings.Matches(msg, ActionDetails) {
+		m.SetState(StateNormal)
+		return m, nil
+	}
+	if m.refuseReadOnly(action) {
+		return m, nil
+	}
+
+	// Open a shell with the environment the task runs with
+	if action == ActionTaskShell && m.SelectedTask != nil {
+		t := *m.SelectedTask
+		m.SetState(StateNormal)
+		return m, m.openTaskShell(t)
+	}
+
+	// Inspect the variables the task sees
+	if action == ActionTaskVars && m.SelectedTask != nil {
+		m.openVarsOverlay(*m.SelectedTask)
+	}
+
+	// Edit the task's description, summary and aliases
+	if action == ActionEditMetadata && m.SelectedTask != nil {
+		m.openMetadataEditor(*m.SelectedTask)
 	}
 	return m, nil
 }
 
-// handleHelpOverlayKey handles key presses when in the help overlay state
+// handleVarsOverlayKey handles key presses when inspecting the variables of a task, going back
+// to its details when closed
+func (m Model) handleVarsOverlayKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch action := m.resolveKey(msg); {
+	case action == ActionClose || m.KeyBindings.Matches(msg, ActionTaskVars):
+		m.SetState(StateDetailsOverlay)
+	case action == ActionUp:
+		m.VarsViewport.ScrollUp(1)
+	case action == ActionDown:
+		m.VarsViewport.ScrollDown(1)
+	case action == ActionPageUp:
+		m.VarsViewport.HalfPageUp()
+	case action == ActionPageDown:
+		m.VarsViewport.HalfPageDown()
+	case action == ActionTop:
+		m.VarsViewport.GotoTop()
+	case action == ActionBottom:
+		m.VarsViewport.GotoBottom()
+	}
+	return m, nil
+}
+
+// handleHelpOverlayKey handles key presses when in the help overlay state. Printable keys
+// are typed into the search box, which filters the listed bindings.
 func (m Model) handleHelpOverlayKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	// Check for keys that close the help overlay
-	if IsKeyMatch(msg, "esc") || IsKeyMatch(msg, "?") {
-		m.State = StateNormal
+	action := m.resolveKey(msg)
+
+	// Check for keys that close the help overlay; esc clears an active search first
+	if action == ActionClose {
+		if m.HelpSearch != "" {
+			m.HelpSearch = ""
+			m.refreshHelpContent()
+			return m, nil
+		}
+		m.SetState(StateNormal)
+		return m, nil
+	}
+	if m.KeyBindings.Matches(msg, ActionHelp) {
+		m.SetState(StateNormal)
 		return m, nil
 	}
 
 	// Handle navigation within help overlay
-	if IsKeyMatch(msg, "up") || IsKeyMatch(msg, "k") {
+	switch action {
+	case ActionUp:
 		m.HelpViewport.ScrollUp(1)
-	} else if IsKeyMatch(msg, "down") || IsKeyMatch(msg, "j") {
+	case ActionDown:
 		m.HelpViewport.ScrollDown(1)
-	} else if IsKeyMatch(msg, "pgup/pgdn") {
-		if msg.String() == "pgup" {
-			m.HelpViewport.HalfPageUp()
-		} else {
-			m.HelpViewport.HalfPageDown()
+	case ActionPageUp:
+		m.HelpViewport.HalfPageUp()
+	case ActionPageDown:
+		m.HelpViewport.HalfPageDown()
+	case ActionTop:
+		m.HelpViewport.GotoTop()
+	case ActionBottom:
+		m.HelpViewport.GotoBottom()
+	case ActionShowAll:
+		m.HelpShowAll = !m.HelpShowAll
+		m.refreshHelpContent()
+	case ActionTour:
+		m.openTour()
+	default:
+		switch {
+		case IsKeyMatch(msg, "backspace"):
+			if len(m.HelpSearch) > 0 {
+				m.HelpSearch = m.HelpSearch[:len(m.HelpSearch)-1]
+				m.refreshHelpContent()
+			}
+		case msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace:
+			m.HelpSearch += msg.String()
+			m.refreshHelpContent()
 		}
-	} else if IsKeyMatch(msg, "home/end") {
-		if msg.String() == "home" {
-			m.HelpViewport.GotoTop()
-		} else {
-			m.HelpViewport.GotoBottom()
+	}
+	return m, nil
+}
+
+// handleSchedulePromptKey handles key presses while entering a schedule for the selected task
+func (m Model) handleSchedulePromptKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch action := m.resolveKey(msg); {
+	case action == ActionClose:
+		m.SetState(StateNormal)
+	case action == ActionConfirm:
+		if m.SelectedTask != nil && m.addSchedule(m.SelectedTask.Id, m.ScheduleInput) {
+			m.SetState(StateNormal)
+		}
+	case IsKeyMatch(msg, "backspace"):
+		if len(m.ScheduleInput) > 0 {
+			m.ScheduleInput = m.ScheduleInput[:len(m.ScheduleInput)-1]
 		}
+	case msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace:
+		m.ScheduleInput += string(msg.Runes)
+		if msg.Type == tea.KeySpace {
+			m.ScheduleInput += " "
+		}
+	}
+	return m, nil
+}
+
+// handleScheduleOverlayKey handles key presses when the scheduled tasks overlay is shown
+func (m Model) handleScheduleOverlayKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch action := m.resolveKey(msg); {
+	case action == ActionClose || m.KeyBindings.Matches(msg, ActionSchedules):
+		m.SetState(StateNormal)
+	case action == ActionUp:
+		if m.ScheduleSelected > 0 {
+			m.ScheduleSelected--
+		}
+	case action == ActionDown:
+		if m.ScheduleSelected < len(m.Schedules)-1 {
+			m.ScheduleSelected++
+		}
+	case action == ActionRemove:
+		m.removeSchedule(m.ScheduleSelected)
+	}
+	return m, nil
+}
+
+// togglePause suspends or resumes the running task's process group
+func (m *Model) togglePause() {
+	if m.TaskPaused {
+		if err := task.ResumeTaskProcess(m.Command.Process); err != nil {
+			m.AppendErrorMsg("Unable to resume task: " + err.Error())
+			return
+		}
+		m.TaskPaused = false
+		m.AppendAppMsg("Task resumed\n")
+		return
+	}
+	if err := task.PauseTaskProcess(m.Command.Process); err != nil {
+		m.AppendErrorMsg("Unable to pause task: " + err.Error())
+		return
+	}
+	m.TaskPaused = true
+	m.AppendAppMsg("Task paused\n")
+}
+
+// handleDiffOverlayKey handles key presses when comparing runs
+func (m Model) handleDiffOverlayKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch action := m.resolveKey(msg); {
+	case action == ActionClose || m.KeyBindings.Matches(msg, ActionDiff):
+		m.SetState(StateNormal)
+	case action == ActionToggleView:
+		m.DiffSideBySide = !m.DiffSideBySide
+		m.DiffViewport.SetContent(m.renderDiffContent())
+	case action == ActionUp:
+		m.DiffViewport.ScrollUp(1)
+	case action == ActionDown:
+		m.DiffViewport.ScrollDown(1)
+	case action == ActionPageUp:
+		m.DiffViewport.HalfPageUp()
+	case action == ActionPageDown:
+		m.DiffViewport.HalfPageDown()
+	case action == ActionTop:
+		m.DiffViewport.GotoTop()
+	case action == ActionBottom:
+		m.DiffViewport.GotoBottom()
+	}
+	return m, nil
+}
+
+// handleStatsOverlayKey handles key presses when the statistics overlay is shown
+func (m Model) handleStatsOverlayKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.resolveKey(msg) == ActionClose || m.KeyBindings.Matches(msg, ActionStats) {
+		m.SetState(StateNormal)
 	}
 	return m, nil
 }
+
+// addCountDigit adds a digit key to the count prefix, reporting whether msg was one. A
+// leading zero is not a count.
+func (m *Model) addCountDigit(msg tea.KeyMsg) bool {
+	if msg.Type != tea.KeyRunes || len(msg.Runes) != 1 {
+		return false
+	}
+	r := msg.Runes[0]
+	if r < '0' || r > '9' || (r == '0' && m.Count == 0) {
+		return false
+	}
+	m.Count = min(m.Count*10+int(r-'0'), maxCount)
+	return true
+}
+
+// maxCount caps count prefixes so a held key can't overflow them
+const maxCount = 99999
+
+// navigate moves the selection in the table or scrolls the viewport, whichever is focused,
+// count times (once when count is zero). With a count, top and bottom go to line count.
+// It reports whether action was a navigation action.
+func (m *Model) navigate(action Action, count int) bool {
+	n := max(count, 1)
+	switch m.Focused {
+	case ControlTable:
+		half := max(m.Table.Height()/2, 1)
+		switch action {
+		case ActionUp:
+			m.Table.MoveUp(n)
+		case ActionDown:
+			m.Table.MoveDown(n)
+		case ActionPageUp:
+			m.Table.MoveUp(n * m.Table.Height())
+		case ActionPageDown:
+			m.Table.MoveDown(n * m.Table.Height())
+		case ActionHalfPageUp:
+			m.Table.MoveUp(n * half)
+		case ActionHalfPageDown:
+			m.Table.MoveDown(n * half)
+		case ActionTop, ActionBottom:
+			switch {
+			case count > 0:
+				m.Table.SetCursor(min(count, len(m.Table.Rows())) - 1)
+			case action == ActionTop:
+				m.Table.GotoTop()
+			default:
+				m.Table.GotoBottom()
+			}
+		default:
+			return false
+		}
+	case ControlViewport:
+		switch action {
+		case ActionUp:
+			m.Viewport.ScrollUp(n)
+		case ActionDown:
+			m.Viewport.ScrollDown(n)
+		case ActionPageUp:
+			m.Viewport.ScrollUp(n * m.Viewport.Height)
+		case ActionPageDown:
+			m.Viewport.ScrollDown(n * m.Viewport.Height)
+		case ActionHalfPageUp:
+			m.Viewport.ScrollUp(n * max(m.Viewport.Height/2, 1))
+		case ActionHalfPageDown:
+			m.Viewport.ScrollDown(n * max(m.Viewport.Height/2, 1))
+		case ActionTop, ActionBottom:
+			switch {
+			case count > 0:
+				m.Viewport.SetYOffset(count - 1)
+			case action == ActionTop:
+				m.Viewport.GotoTop()
+			default:
+				m.Viewport.GotoBottom()
+			}
+		default:
+			return false
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// runInExternalTerminal launches t in the configured external terminal, falling back to
+// "$TERMINAL -e" when none is configured
+func (m *Model) runInExternalTerminal(t task.Task) {
+	if m.Config.Provider == task.ProviderDemo {
+		m.AppendErrorMsg("Demo tasks can't run in an external terminal")
+		return
+	}
+	template := m.Config.ExternalTerminal
+	if template == "" && os.Getenv("TERMINAL") != "" {
+		template = os.Getenv("TERMINAL") + " -e"
+	}
+	if err := task.RunInExternalTerminal(template, t.Id); err != nil {
+		if errors.Is(err, task.ErrNoTerminal) {
+			m.AppendErrorMsg("No external terminal configured: set external_terminal (e.g. \"alacritty -e\") or $TERMINAL")
+			return
+		}
+		m.AppendErrorMsg(err.Error())
+		return
+	}
+	m.AppendAppMsg(fmt.Sprintf("Started task '%s' in an external terminal\n", t.Id))
+}
+
+// cancelTask cancels the running task, along with the batch or repeated runs it is part of
+func (m *Model) cancelTask() {
+	if m.TaskPaused {
+		// a stopped process group won't act on the interrupt until it is continued
+		m.togglePause()
+	}
+	m.CommandCancel()
+	m.runCancelled = true
+	m.TaskRunning = false
+	m.Command = nil
+	m.CommandCancel = nil
+	if m.ExecutingBatch {
+		m.ExecutingBatch = false
+		m.CurrentBatchTaskIndex = -1
+	}
+	if m.Repeat.Active {
+		m.AppendAppMsg(fmt.Sprintf("Run until failure stopped after %d iterations\n", m.Repeat.Iteration))
+		m.Repeat = RepeatState{}
+	}
+	if m.TasksLoading {
+		m.TasksLoading = false
+	}
+	m.AppendAppMsg("Task cancellation requested\n")
+}
@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/task"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newNavigationModel returns a model with n tasks in the table
+func newNavigationModel(n int) Model {
+	m := NewModel(nil, config.Default())
+	for i := range n {
+		m.Tasks = append(m.Tasks, task.Task{Id: fmt.Sprintf("task-%d", i)})
+	}
+	m.UpdateTaskTable()
+	return m
+}
+
+// pressKeys sends each key to the model in the normal state
+func pressKeys(m Model, keys ...tea.KeyMsg) Model {
+	for _, k := range keys {
+		updated, _ := m.handleNormalKey(k)
+		m = updated.(Model)
+	}
+	return m
+}
+
+func runes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestCountPrefixRepeatsNavigation(t *testing.T) {
+	m := pressKeys(newNavigationModel(20), runes("1"), runes("2"), runes("j"))
+	if got := m.Table.Cursor(); got != 12 {
+		t.Errorf("Expected 12j to move to row 12, got %d", got)
+	}
+	if m.Count != 0 {
+		t.Errorf("Expected the count to be reset, got %d", m.Count)
+	}
+
+	m = pressKeys(m, runes("3"), runes("k"))
+	if got := m.Table.Cursor(); got != 9 {
+		t.Errorf("Expected 3k to move to row 9, got %d", got)
+	}
+}
+
+func TestTopAndBottom(t *testing.T) {
+	m := pressKeys(newNavigationModel(20), runes("G"))
+	if got := m.Table.Cursor(); got != 19 {
+		t.Errorf("Expected G to move to the last row, got %d", got)
+	}
+
+	m = pressKeys(m, runes("g"), runes("g"))
+	if got := m.Table.Cursor(); got != 0 {
+		t.Errorf("Expected gg to move to the first row, got %d", got)
+	}
+
+	m = pressKeys(m, runes("5"), runes("G"))
+	if got := m.Table.Cursor(); got != 4 {
+		t.Errorf("Expected 5G to move to row 4, got %d", got)
+	}
+}
+
+func TestRunInExternalTerminal(t *testing.T) {
+	t.Setenv("TERMINAL", "")
+	m := newNavigationModel(1)
+	m.HandleWindowResize(120, 40)
+
+	m = pressKeys(m, runes("o"))
+	if !strings.Contains(m.output.String(), "No external terminal configured") {
+		t.Errorf("Expected a hint to configure a terminal, got %q", m.output.String())
+	}
+
+	// "true" stands in for a terminal emulator and ignores the task command
+	m.Config.ExternalTerminal = "true"
+	m = pressKeys(m, runes("o"))
+	if !strings.Contains(m.output.String(), "Started task 'task-0' in an external terminal") {
+		t.Errorf("Expected the task to start in the terminal, got %q", m.output.String())
+	}
+}
+
+func TestVerbosityCyclesTaskFlags(t *testing.T) {
+	m := newNavigationModel(1)
+
+	m = pressKeys(m, runes("V"))
+	if opts := m.ExecOptions("task-0"); !opts.Verbose || opts.Silent {
+		t.Errorf("Expected verbose runs, got %+v", opts)
+	}
+	m = pressKeys(m, runes("V"))
+	if opts := m.ExecOptions("task-0"); opts.Verbose || !opts.Silent {
+		t.Errorf("Expected silent runs, got %+v", opts)
+	}
+	m = pressKeys(m, runes("V"))
+	if opts := m.ExecOptions("task-0"); opts.Verbose || opts.Silent {
+		t.Errorf("Expected normal runs, got %+v", opts)
+	}
+
+	cfg := config.Default()
+	cfg.Verbosity = "silent"
+	if m := NewModel(nil, cfg); m.Verbosity != VerbositySilent {
+		t.Errorf("Expected the configured verbosity, got %s", m.Verbosity)
+	}
+}
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Aj4x/tash/internal/history"
+)
+
+// statsRecentRuns is the number of recent runs drawn in each task's sparkline
+const statsRecentRuns = 20
+
+// UseHistory appends the finished runs to the run history in store, which the statistics view is
+// built from; without a store the history isn't kept
+func (m *Model) UseHistory(store history.Store) {
+	m.History = store
+}
+
+// openStats loads the run history and shows the statistics overlay
+func (m *Model) openStats() {
+	records, err := m.History.Load()
+	if err != nil {
+		m.AppendErrorMsg("Unable to load run history: " + err.Error())
+		return
+	}
+	m.Stats = history.Compute(records, statsRecentRuns)
+	m.SetState(StateStatsOverlay)
+}
+
+// RenderStatsOverlay renders per-task duration statistics from the run history
+func RenderStatsOverlay(width, height int, stats []history.Stats) string {
+	overlayWidth := int(float64(width) * 0.9)
+
+	content := TaskPickerTitleStyle.Render("Task Duration Statistics") + "\n\n"
+	if len(stats) == 0 {
+		content += "No runs recorded yet.\n"
+	} else {
+		content += TaskDetailOverlayLabelStyle.Render(fmt.Sprintf("%-24s %5s %9s %9s %9s %9s %7s  %s",
+			"Task", "Runs", "Last", "Avg", "Min", "Max", "Success", "Recent")) + "\n"
+		for _, s := range stats {
+			content += fmt.Sprintf("%-24s %5d %9s %9s %9s %9s %6.0f%%  %s\n",
+				s.Task, s.Runs,
+				formatDuration(s.Last), formatDuration(s.Avg), formatDuration(s.Min), formatDuration(s.Max),
+				s.SuccessRate*100, history.Sparkline(s.Recent))
+		}
+	}
+
+	overlay := GeneralOverlayStyle(overlayWidth).Render(content)
+
+	return placeOverlay(width, height, overlay)
+}
+
+// formatDuration rounds a duration to a precision suitable for display
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
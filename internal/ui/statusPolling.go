@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/Aj4x/tash/internal/task"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxStatusChecks bounds the tasks checked per poll, which are those around the cursor, so a
+// poll of a large catalog doesn't hammer the disk
+const maxStatusChecks = 20
+
+// taskStatusMsg reports which of the polled tasks are up to date
+type taskStatusMsg struct {
+	UpToDate map[string]bool
+}
+
+// pollTaskStatus checks in the background whether the tasks around the cursor are up to date,
+// once the configured status poll interval has passed since the last check. The tasks are
+// checked one at a time, and not while a task runs or the previous check is still going.
+func (m Model) pollTaskStatus(now time.Time) (Model, tea.Cmd) {
+	interval := time.Duration(m.Config.StatusPollInterval)
+	if interval <= 0 || m.Headless || m.Config.Provider == task.ProviderDemo || m.statusPolling ||
+		m.TasksLoading || len(m.Tasks) == 0 || now.Sub(m.statusChecked) < interval {
+		return m, nil
+	}
+	m.statusChecked = now
+	m.statusPolling = true
+	ids := m.tasksAroundCursor(maxStatusChecks)
+	env := ExecEnvironment(m.Config)
+	return m, func() tea.Msg {
+		upToDate := map[string]bool{}
+		for _, id := range ids {
+			ok, err := task.UpToDate(context.Background(), env, id)
+			if err != nil {
+				slog.Debug("Unable to check whether the task is up to date", "task", id, "error", err)
+				continue
+			}
+			upToDate[id] = ok
+		}
+		return taskStatusMsg{UpToDate: upToDate}
+	}
+}
+
+// tasksAroundCursor returns the ids of up to n listed tasks centred on the cursor, leaving out
+// loop variants and CI jobs
+func (m Model) tasksAroundCursor(n int) []string {
+	start := max(0, m.Table.Cursor()-n/2)
+	end := min(len(m.Tasks), start+n)
+	start = max(0, end-n)
+	ids := make([]string, 0, end-start)
+	for _, t := range m.Tasks[start:end] {
+		if t.LoopOf == "" && !isCIJob(t) {
+			ids = append(ids, t.Id)
+		}
+	}
+	return ids
+}
+
+// handleTaskStatus updates the up-to-date indicators of the polled tasks
+func (m Model) handleTaskStatus(msg taskStatusMsg) (Model, tea.Cmd) {
+	m.statusPolling = false
+	var changed, listed bool
+	m.AllTasks, changed = withUpToDate(m.AllTasks, msg.UpToDate)
+	m.Tasks, listed = withUpToDate(m.Tasks, msg.UpToDate)
+	if changed || listed {
+		m.UpdateTaskTable()
+	}
+	return m, nil
+}
+
+// withUpToDate returns tasks with the up-to-date status given for them, and whether any changed.
+// The tasks are copied before they are changed, as the slice may be shared with other lists.
+func withUpToDate(tasks []task.Task, upToDate map[string]bool) ([]task.Task, bool) {
+	changed := false
+	for i, t := range tasks {
+		if status, ok := upToDate[t.Id]; ok && status != t.UpToDate && t.LoopOf == "" {
+			if !changed {
+				tasks = slices.Clone(tasks)
+				changed = true
+			}
+			tasks[i].UpToDate = status
+		}
+	}
+	return tasks, changed
+}
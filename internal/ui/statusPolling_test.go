@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Aj4x/tash/internal/config"
+)
+
+func TestTaskStatusPollingIsRateLimited(t *testing.T) {
+	m := newNavigationModel(50)
+	m.AllTasks = m.Tasks
+	now := time.Now()
+
+	if _, cmd := m.pollTaskStatus(now); cmd != nil {
+		t.Fatal("Expected no status checks unless an interval is configured")
+	}
+	m.Config.StatusPollInterval = config.Duration(30 * time.Second)
+	m, cmd := m.pollTaskStatus(now)
+	if cmd == nil || !m.statusPolling {
+		t.Fatal("Expected the tasks to be checked")
+	}
+	if _, cmd := m.pollTaskStatus(now.Add(time.Minute)); cmd != nil {
+		t.Error("Expected no second check while the first is still going")
+	}
+	m, _ = m.handleTaskStatus(taskStatusMsg{})
+	if _, cmd := m.pollTaskStatus(now.Add(10 * time.Second)); cmd != nil {
+		t.Error("Expected no check before the interval has passed")
+	}
+	m.TasksLoading = true
+	if _, cmd := m.pollTaskStatus(now.Add(time.Minute)); cmd != nil {
+		t.Error("Expected no check while a task runs")
+	}
+
+	m.Table.SetCursor(49)
+	if ids := m.tasksAroundCursor(maxStatusChecks); len(ids) != maxStatusChecks || ids[len(ids)-1] != "task-49" {
+		t.Errorf("Expected the last %d tasks, got %v", maxStatusChecks, ids)
+	}
+}
+
+func TestTaskStatusUpdatesTheIndicators(t *testing.T) {
+	m := newNavigationModel(2)
+	m.AllTasks = m.Tasks
+
+	m, _ = m.handleTaskStatus(taskStatusMsg{UpToDate: map[string]bool{"task-1": true}})
+	if !m.Tasks[1].UpToDate || m.Tasks[0].UpToDate {
+		t.Fatal("Expected only task-1 to be up to date")
+	}
+	if row := m.Table.Rows()[1]; row[0] != "task-1 ✓" {
+		t.Errorf("Expected task-1 to be marked up to date, got %q", row[0])
+	}
+}
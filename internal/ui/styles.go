@@ -1,86 +1,344 @@
 package ui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"fmt"
+	"sort"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme is the palette the styles are built from. Colors set to lipgloss.NoColor{} leave the
+// terminal's default color; Attributes then distinguishes messages by bold and underline alone.
+type Theme struct {
+	Name       string
+	Border     lipgloss.TerminalColor // Unfocused borders
+	Accent     lipgloss.TerminalColor // Focused borders, headers and section titles
+	Overlay    lipgloss.TerminalColor // Overlay borders and titles
+	Selected   lipgloss.TerminalColor // Selected rows
+	SelectedBg lipgloss.TerminalColor // Background of the selected match in overlays
+	Muted      lipgloss.TerminalColor // Help text and labels
+	Success    lipgloss.TerminalColor // Application messages and added diff lines
+	Error      lipgloss.TerminalColor // Error messages and removed diff lines
+	Output     lipgloss.TerminalColor // Task output
+	Info       lipgloss.TerminalColor // Status lines such as the next scheduled run
+	Warning    lipgloss.TerminalColor // Paused status
+	Subtle     lipgloss.TerminalColor // Unchanged diff lines
+	Attributes bool                   // Use underline and heavier borders where color would carry meaning
+	Plain      bool                   // Drop borders and emphasis and left-align overlays, for screen readers
+}
+
+// Built-in themes
+var (
+	DefaultTheme = Theme{
+		Name:       "default",
+		Border:     lipgloss.Color("240"),
+		Accent:     lipgloss.Color("69"),
+		Overlay:    lipgloss.Color("63"),
+		Selected:   lipgloss.Color("229"),
+		SelectedBg: lipgloss.Color("63"),
+		Muted:      lipgloss.Color("241"),
+		Success:    lipgloss.Color("10"),
+		Error:      lipgloss.Color("9"),
+		Output:     lipgloss.Color("7"),
+		Info:       lipgloss.Color("141"),
+		Warning:    lipgloss.Color("214"),
+		Subtle:     lipgloss.Color("245"),
+	}
+
+	// HighContrastTheme uses bright colors on the terminal background and white text on
+	// black for selections
+	HighContrastTheme = Theme{
+		Name:       "high-contrast",
+		Border:     lipgloss.Color("15"),
+		Accent:     lipgloss.Color("14"),
+		Overlay:    lipgloss.Color("15"),
+		Selected:   lipgloss.Color("0"),
+		SelectedBg: lipgloss.Color("15"),
+		Muted:      lipgloss.Color("15"),
+		Success:    lipgloss.Color("14"),
+		Error:      lipgloss.Color("11"),
+		Output:     lipgloss.Color("15"),
+		Info:       lipgloss.Color("14"),
+		Warning:    lipgloss.Color("11"),
+		Subtle:     lipgloss.Color("250"),
+		Attributes: true,
+	}
+
+	// DeuteranopiaTheme avoids red/green pairs, using blue for success and orange for errors
+	DeuteranopiaTheme = Theme{
+		Name:       "deuteranopia",
+		Border:     lipgloss.Color("240"),
+		Accent:     lipgloss.Color("33"),
+		Overlay:    lipgloss.Color("33"),
+		Selected:   lipgloss.Color("230"),
+		SelectedBg: lipgloss.Color("25"),
+		Muted:      lipgloss.Color("244"),
+		Success:    lipgloss.Color("39"),
+		Error:      lipgloss.Color("208"),
+		Output:     lipgloss.Color("7"),
+		Info:       lipgloss.Color("111"),
+		Warning:    lipgloss.Color("220"),
+		Subtle:     lipgloss.Color("245"),
+	}
+
+	// NoColorTheme is used for NO_COLOR and --no-color
+	NoColorTheme = Theme{
+		Name:       "no-color",
+		Border:     lipgloss.NoColor{},
+		Accent:     lipgloss.NoColor{},
+		Overlay:    lipgloss.NoColor{},
+		Selected:   lipgloss.NoColor{},
+		SelectedBg: lipgloss.NoColor{},
+		Muted:      lipgloss.NoColor{},
+		Success:    lipgloss.NoColor{},
+		Error:      lipgloss.NoColor{},
+		Output:     lipgloss.NoColor{},
+		Info:       lipgloss.NoColor{},
+		Warning:    lipgloss.NoColor{},
+		Subtle:     lipgloss.NoColor{},
+		Attributes: true,
+	}
+
+	// PlainTheme is used for --plain, the screen reader friendly mode
+	PlainTheme = Theme{
+		Name:       "plain",
+		Border:     lipgloss.NoColor{},
+		Accent:     lipgloss.NoColor{},
+		Overlay:    lipgloss.NoColor{},
+		Selected:   lipgloss.NoColor{},
+		SelectedBg: lipgloss.NoColor{},
+		Muted:      lipgloss.NoColor{},
+		Success:    lipgloss.NoColor{},
+		Error:      lipgloss.NoColor{},
+		Output:     lipgloss.NoColor{},
+		Info:       lipgloss.NoColor{},
+		Warning:    lipgloss.NoColor{},
+		Subtle:     lipgloss.NoColor{},
+		Plain:      true,
+	}
+)
+
+var themes = map[string]Theme{
+	DefaultTheme.Name:      DefaultTheme,
+	HighContrastTheme.Name: HighContrastTheme,
+	DeuteranopiaTheme.Name: DeuteranopiaTheme,
+	NoColorTheme.Name:      NoColorTheme,
+	PlainTheme.Name:        PlainTheme,
+}
+
+// ThemeNames returns the names of the built-in themes
+func ThemeNames() []string {
+	var names []string
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupTheme returns the built-in theme called name; an empty name is the default theme
+func LookupTheme(name string) (Theme, error) {
+	if name == "" {
+		return DefaultTheme, nil
+	}
+	t, ok := themes[name]
+	if !ok {
+		return DefaultTheme, fmt.Errorf("unknown theme %q, expected one of %v", name, ThemeNames())
+	}
+	return t, nil
+}
+
+// theme is the palette the style functions are built from
+var theme = DefaultTheme
 
 // Table Styles
 var (
-	TableStyle             = lipgloss.NewStyle().BorderStyle(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("240"))
-	TableHeaderStyle       = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("69"))
-	TableSelectedStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("229")).Bold(true)
-	TableSelectedTaskStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("39")).Bold(true).PaddingLeft(1)
+	TableStyle             lipgloss.Style
+	TableHeaderStyle       lipgloss.Style
+	TableSelectedStyle     lipgloss.Style
+	TableSelectedTaskStyle lipgloss.Style
+	ScheduleStatusStyle    lipgloss.Style
+	PausedStatusStyle      lipgloss.Style
 )
 
 var (
-	ViewportStyle = lipgloss.NewStyle().BorderStyle(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("240"))
-	FocusedStyle  = lipgloss.NewStyle().BorderStyle(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("69"))
-	HelpStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	ViewportStyle lipgloss.Style
+	FocusedStyle  lipgloss.Style
+	HelpStyle     lipgloss.Style
 
 	// Message Styles
-	AppMsgStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("10")).Bold(true) // Green for app messages
-	ErrorMsgStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Bold(true)  // Red for error messages
-	OutputStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("7"))             // Default color for regular output
+	AppMsgStyle   lipgloss.Style // Green for app messages
+	ErrorMsgStyle lipgloss.Style // Red for error messages
+	OutputStyle   lipgloss.Style // Default color for regular output
 )
 
+// Task Picker styles
+var (
+	TaskPickerTitleStyle lipgloss.Style
+)
+
+// Task Detail Overlay styles
+var (
+	TaskDetailOverlayTitleStyle lipgloss.Style
+	TaskDetailOverlayLabelStyle lipgloss.Style
+)
+
+// Help Text styles
+var (
+	HelpTextTitleStyle    lipgloss.Style
+	HelpTextSectionStyle  lipgloss.Style
+	HelpTextCommandStyle  lipgloss.Style
+	HelpTextDisabledStyle lipgloss.Style
+)
+
+// Run comparison styles
+var (
+	DiffAddedStyle   lipgloss.Style
+	DiffRemovedStyle lipgloss.Style
+	DiffEqualStyle   lipgloss.Style
+)
+
+// isNoColor reports whether c leaves the terminal's default color, in which case selections
+// are shown in reverse video instead
+func isNoColor(c lipgloss.TerminalColor) bool {
+	_, ok := c.(lipgloss.NoColor)
+	return ok
+}
+
+func init() {
+	ApplyTheme(DefaultTheme)
+}
+
+// ApplyTheme rebuilds every style from t
+func ApplyTheme(t Theme) {
+	theme = t
+
+	TableStyle = lipgloss.NewStyle().BorderStyle(lipgloss.RoundedBorder()).BorderForeground(t.Border)
+	TableHeaderStyle = lipgloss.NewStyle().Bold(true).Foreground(t.Accent)
+	TableSelectedStyle = lipgloss.NewStyle().Foreground(t.Selected).Bold(true).Reverse(isNoColor(t.Selected))
+	TableSelectedTaskStyle = lipgloss.NewStyle().Foreground(t.Accent).Bold(true).PaddingLeft(1)
+	ScheduleStatusStyle = lipgloss.NewStyle().Foreground(t.Info).PaddingLeft(1)
+	PausedStatusStyle = lipgloss.NewStyle().Foreground(t.Warning).Bold(true).PaddingLeft(1)
+
+	ViewportStyle = lipgloss.NewStyle().BorderStyle(lipgloss.RoundedBorder()).BorderForeground(t.Border)
+	FocusedStyle = lipgloss.NewStyle().BorderStyle(lipgloss.RoundedBorder()).BorderForeground(t.Accent)
+	if t.Attributes {
+		// a heavier border marks focus without relying on color
+		FocusedStyle = FocusedStyle.BorderStyle(lipgloss.ThickBorder())
+	}
+	HelpStyle = lipgloss.NewStyle().Foreground(t.Muted)
+
+	AppMsgStyle = lipgloss.NewStyle().Foreground(t.Success).Bold(true)
+	ErrorMsgStyle = lipgloss.NewStyle().Foreground(t.Error).Bold(true).Underline(t.Attributes)
+	OutputStyle = lipgloss.NewStyle().Foreground(t.Output)
+
+	TaskPickerTitleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(t.Overlay).
+		MarginBottom(1)
+
+	TaskDetailOverlayTitleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(t.Overlay).
+		MarginBottom(1)
+	TaskDetailOverlayLabelStyle = lipgloss.NewStyle().Bold(true).Foreground(t.Muted)
+
+	HelpTextTitleStyle = lipgloss.NewStyle().Bold(true).Foreground(t.Overlay).MarginBottom(1)
+	HelpTextSectionStyle = lipgloss.NewStyle().Bold(true).Foreground(t.Accent).MarginTop(1).MarginBottom(1).Underline(t.Attributes)
+	HelpTextCommandStyle = lipgloss.NewStyle().Bold(true).Foreground(t.Muted)
+	HelpTextDisabledStyle = lipgloss.NewStyle().Foreground(t.Subtle).Faint(true)
+
+	DiffAddedStyle = lipgloss.NewStyle().Foreground(t.Success).Bold(t.Attributes)
+	DiffRemovedStyle = lipgloss.NewStyle().Foreground(t.Error).Underline(t.Attributes)
+	DiffEqualStyle = lipgloss.NewStyle().Foreground(t.Subtle)
+
+	if t.Plain {
+		// no borders or emphasis; a screen reader reads the text as it is
+		TableStyle = lipgloss.NewStyle()
+		TableHeaderStyle = lipgloss.NewStyle()
+		TableSelectedStyle = lipgloss.NewStyle()
+		ViewportStyle = lipgloss.NewStyle()
+		FocusedStyle = lipgloss.NewStyle()
+		TaskPickerTitleStyle = lipgloss.NewStyle()
+		TaskDetailOverlayTitleStyle = lipgloss.NewStyle()
+		TaskDetailOverlayLabelStyle = lipgloss.NewStyle()
+		HelpTextTitleStyle = lipgloss.NewStyle()
+		HelpTextSectionStyle = lipgloss.NewStyle().MarginTop(1)
+		HelpTextCommandStyle = lipgloss.NewStyle()
+		HelpTextDisabledStyle = lipgloss.NewStyle()
+		AppMsgStyle = lipgloss.NewStyle()
+		ErrorMsgStyle = lipgloss.NewStyle()
+	}
+}
+
+// placeOverlay centers an overlay on the screen. Plain mode leaves it at the top left, where
+// a screen reader starts reading.
+func placeOverlay(width, height int, overlay string) string {
+	if theme.Plain {
+		return overlay
+	}
+	return lipgloss.Place(
+		width,
+		height,
+		lipgloss.Center,
+		lipgloss.Center,
+		overlay,
+	)
+}
+
 func GeneralOverlayStyle(overlayWidth int) lipgloss.Style {
+	if theme.Plain {
+		return lipgloss.NewStyle().Width(overlayWidth)
+	}
 	return lipgloss.NewStyle().
 		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("63")).
+		BorderForeground(theme.Overlay).
 		Padding(1, 2).
 		Width(overlayWidth)
 }
 
-// Task Picker styles
-var (
-	TaskPickerTitleStyle = lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("63")).
-		MarginBottom(1)
-)
-
 func TaskPickerInputStyle(overlayWidth int) lipgloss.Style {
+	if theme.Plain {
+		return lipgloss.NewStyle()
+	}
 	return lipgloss.NewStyle().
 		BorderStyle(lipgloss.NormalBorder()).
-		BorderForeground(lipgloss.Color("241")).
+		BorderForeground(theme.Muted).
 		Padding(0, 1).
 		Width(overlayWidth - 6)
 }
 
 func TaskPickerMatchStyle(overlayWidth int) lipgloss.Style {
+	if theme.Plain {
+		return lipgloss.NewStyle().SetString(" ").Width(overlayWidth - 6)
+	}
 	return lipgloss.NewStyle().
 		Padding(0, 1).
 		Width(overlayWidth - 6)
 }
 
 func TaskPickerSelectedMatchStyle(overlayWidth int) lipgloss.Style {
+	if theme.Plain {
+		// mark the selection with text rather than color
+		return lipgloss.NewStyle().SetString(">").Width(overlayWidth - 6)
+	}
 	return lipgloss.NewStyle().
-		Foreground(lipgloss.Color("229")).
-		Background(lipgloss.Color("63")).
+		Foreground(theme.Selected).
+		Background(theme.SelectedBg).
 		Bold(true).
+		Reverse(isNoColor(theme.SelectedBg)).
 		Padding(0, 1).
 		Width(overlayWidth - 6)
 }
 
-// Task Detail Overlay styles
-var (
-	TaskDetailOverlayTitleStyle = lipgloss.NewStyle().
-					Bold(true).
-					Foreground(lipgloss.Color("63")).
-					MarginBottom(1)
-	TaskDetailOverlayLabelStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("241"))
-)
-
 func TaskDetailOverlayStyle(overlayWidth, overlayHeight int) lipgloss.Style {
+	if theme.Plain {
+		return lipgloss.NewStyle().Width(overlayWidth)
+	}
 	return lipgloss.NewStyle().
 		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("63")).
+		BorderForeground(theme.Overlay).
 		Padding(1, 2).
 		Width(overlayWidth).
 		Height(overlayHeight)
 }
-
-// Help Text styles
-var (
-	HelpTextTitleStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("63")).MarginBottom(1)
-	HelpTextSectionStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("69")).MarginTop(1).MarginBottom(1)
-	HelpTextCommandStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("241"))
-)
@@ -0,0 +1,34 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestLookupTheme(t *testing.T) {
+	if th, err := LookupTheme(""); err != nil || th.Name != DefaultTheme.Name {
+		t.Errorf("Expected the default theme for an empty name, got %q (%v)", th.Name, err)
+	}
+	if th, err := LookupTheme("deuteranopia"); err != nil || th.Name != "deuteranopia" {
+		t.Errorf("Expected the deuteranopia theme, got %q (%v)", th.Name, err)
+	}
+	if _, err := LookupTheme("neon"); err == nil {
+		t.Error("Expected an error for an unknown theme")
+	}
+}
+
+func TestNoColorThemeUsesAttributes(t *testing.T) {
+	ApplyTheme(NoColorTheme)
+	defer ApplyTheme(DefaultTheme)
+
+	if _, ok := ErrorMsgStyle.GetForeground().(lipgloss.NoColor); !ok {
+		t.Errorf("Expected error messages to have no color, got %v", ErrorMsgStyle.GetForeground())
+	}
+	if !ErrorMsgStyle.GetUnderline() || !ErrorMsgStyle.GetBold() {
+		t.Error("Expected error messages to be bold and underlined")
+	}
+	if !TableSelectedStyle.GetReverse() {
+		t.Error("Expected the selected row to be shown in reverse video")
+	}
+}
@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/Aj4x/tash/internal/task"
+)
+
+// withDefaultVars returns the NAME=value entries of defaults that vars doesn't set, followed by
+// vars
+func withDefaultVars(defaults, vars []string) []string {
+	var merged []string
+	for _, d := range defaults {
+		name, _, _ := strings.Cut(d, "=")
+		if !slices.ContainsFunc(vars, func(v string) bool { return strings.HasPrefix(v, name+"=") }) {
+			merged = append(merged, d)
+		}
+	}
+	return append(merged, vars...)
+}
+
+// runVars returns the variables a run of t is given: the task's defaults from the config,
+// overridden by the variables of a loop variant and the input entered for the task
+func (m Model) runVars(t task.Task) []string {
+	return withDefaultVars(m.Config.TaskVars[t.Id], slices.Concat(t.Vars, m.RunInputs[t.Id].Vars))
+}
+
+// taskDefaults describes the variables and CLI args the config passes to every run of taskId,
+// or returns an empty string when there are none
+func (m Model) taskDefaults(taskId string) string {
+	defaults := m.Config.TaskVars[taskId]
+	if args := strings.TrimSpace(m.Config.TaskArgs[taskId]); args != "" {
+		defaults = append(slices.Clip(defaults), "--", args)
+	}
+	return strings.Join(defaults, " ")
+}
@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/runopts"
+	"github.com/Aj4x/tash/internal/task"
+)
+
+func TestTaskDefaultsAreOverriddenByRunVars(t *testing.T) {
+	cfg := config.Default()
+	cfg.TaskVars = map[string][]string{"deploy": {"ENV=staging", "REGION=eu"}}
+	cfg.TaskArgs = map[string]string{"deploy": "--dry-run 'two words'"}
+	m := NewModel(nil, cfg)
+	m.RunInputs = map[string]runInputs{"deploy": {Vars: []string{"ENV=prod"}}}
+
+	if got := m.runVars(task.Task{Id: "deploy"}); !slices.Equal(got, []string{"REGION=eu", "ENV=prod"}) {
+		t.Errorf("Expected the entered ENV to override the default, got %v", got)
+	}
+	opts := m.ExecOptions("deploy")
+	if !slices.Equal(opts.Vars, []string{"ENV=staging", "REGION=eu"}) || !slices.Equal(opts.Args, []string{"--dry-run", "two words"}) {
+		t.Errorf("Expected the defaults in the execution options, got vars %v and args %v", opts.Vars, opts.Args)
+	}
+	if got := m.taskDefaults("deploy"); got != "ENV=staging REGION=eu -- --dry-run 'two words'" {
+		t.Errorf("Expected the defaults to be described, got %q", got)
+	}
+
+	m.RunOptionsStore = runopts.NewStore(t.TempDir())
+	m.openRunOptions(task.Task{Id: "deploy"})
+	if m.RunOptionsForm.Options.Args != "--dry-run 'two words'" {
+		t.Errorf("Expected the form to start from the default args, got %q", m.RunOptionsForm.Options.Args)
+	}
+	if form := RenderRunOptions(160, 40, m.RunOptionsForm, nil); !strings.Contains(form, "Default vars: ENV=staging REGION=eu") {
+		t.Errorf("Expected the form to show the default vars, got:\n%s", form)
+	}
+}
@@ -1,13 +1,13 @@
 package ui
 
 import (
+	"fmt"
 	"github.com/Aj4x/tash/internal/task"
-	"github.com/charmbracelet/lipgloss"
 	"strings"
 )
 
 // RenderTaskDetailOverlay renders an overlay with detailed task information
-func RenderTaskDetailOverlay(width, height int, selectedTask *task.Task) string {
+func RenderTaskDetailOverlay(width, height int, selectedTask *task.Task, preview *CommandPreview) string {
 	if selectedTask == nil {
 		return ""
 	}
@@ -25,15 +25,35 @@ func RenderTaskDetailOverlay(width, height int, selectedTask *task.Task) string
 	content += TaskDetailOverlayLabelStyle.Render("Summary: ") + selectedTask.Summary + "\n\n"
 	content += TaskDetailOverlayLabelStyle.Render("Description: ") + selectedTask.Desc + "\n\n"
 	content += TaskDetailOverlayLabelStyle.Render("Aliases: ") + aliases + "\n"
+	if path := selectedTask.Taskfile(); path != "" {
+		content += "\n" + TaskDetailOverlayLabelStyle.Render("Taskfile: ") + fmt.Sprintf("%s:%d", path, selectedTask.Location.Line) + "\n"
+	}
+
+	if preview != nil {
+		content += "\n" + renderCommandPreview(*preview)
+	}
 
 	// Wrap the content in the overlay style
 	overlay := TaskDetailOverlayStyle(overlayWidth, overlayHeight).Render(content)
 
-	return lipgloss.Place(
-		width,
-		height,
-		lipgloss.Center,
-		lipgloss.Center,
-		overlay,
-	)
+	return placeOverlay(width, height, overlay)
+}
+
+// renderCommandPreview renders the resolved commands of a task, or why they couldn't be resolved
+func renderCommandPreview(preview CommandPreview) string {
+	content := TaskDetailOverlayLabelStyle.Render("Commands:") + "\n"
+	if preview.Err != nil {
+		return content + "  unable to resolve: " + preview.Err.Error() + "\n"
+	}
+	if len(preview.Commands) == 0 {
+		return content + "  none\n"
+	}
+	for i, command := range preview.Commands {
+		if i == maxPreviewCommands {
+			content += fmt.Sprintf("  … %d more\n", len(preview.Commands)-i)
+			break
+		}
+		content += "  $ " + command + "\n"
+	}
+	return content
 }
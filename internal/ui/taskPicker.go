@@ -2,12 +2,11 @@ package ui
 
 import (
 	"github.com/Aj4x/tash/internal/task"
-	"github.com/charmbracelet/lipgloss"
 	"strings"
 )
 
 // RenderTaskPicker renders the task picker overlay
-func RenderTaskPicker(width, height int, input string, matches []task.Task, selectedIndex int) string {
+func RenderTaskPicker(width, height int, input string, matches []task.Task, reasons map[string]string, selectedIndex int) string {
 	// Calculate overlay dimensions
 	overlayWidth := int(float64(width) * 0.7)
 
@@ -22,6 +21,9 @@ func RenderTaskPicker(width, height int, input string, matches []task.Task, sele
 			if len(match.Aliases) > 0 {
 				taskText += " (aliases: " + strings.Join(match.Aliases, ", ") + ")"
 			}
+			if reason, ok := reasons[match.Id]; ok {
+				taskText += " · " + reason
+			}
 
 			if i == selectedIndex {
 				content += TaskPickerSelectedMatchStyle(overlayWidth).Render(taskText) + "\n"
@@ -36,11 +38,5 @@ func RenderTaskPicker(width, height int, input string, matches []task.Task, sele
 	// Wrap the content in the overlay style
 	overlay := GeneralOverlayStyle(overlayWidth).Render(content)
 
-	return lipgloss.Place(
-		width,
-		height,
-		lipgloss.Center,
-		lipgloss.Center,
-		overlay,
-	)
+	return placeOverlay(width, height, overlay)
 }
@@ -0,0 +1,150 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/Aj4x/tash/internal/task"
+	"github.com/Aj4x/tash/internal/taskfile"
+)
+
+// matchRank orders the picker matches: the lower the rank, the higher the match is listed
+type matchRank int
+
+const (
+	matchExact matchRank = iota
+	matchId
+	matchAlias
+	matchDesc
+	matchSummary
+	matchCommand
+)
+
+// maxMatchSnippet is the length the line a task matched on is cut to in the picker
+const maxMatchSnippet = 60
+
+// taskSource is what the picker searches of a task beyond the listing, read from its Taskfile
+type taskSource struct {
+	Summary string
+	Cmds    []string
+}
+
+// taskSources reads the summaries and commands of the tasks from their Taskfiles, each read
+// once; tasks whose Taskfile can't be read are searched by their listing only
+func (m *Model) taskSources() map[string]taskSource {
+	if m.Config.Provider == task.ProviderDemo {
+		return nil
+	}
+	files := make(map[string]*taskfile.Taskfile)
+	sources := make(map[string]taskSource)
+	for _, t := range m.AllTasks {
+		path, err := taskfilePath(t)
+		if err != nil {
+			continue
+		}
+		tf, ok := files[path]
+		if !ok {
+			tf, _ = taskfile.Load(path)
+			files[path] = tf
+		}
+		if tf == nil {
+			continue
+		}
+		name := t.Id
+		if t.LoopOf != "" {
+			name = t.LoopOf
+		}
+		_, def, err := tf.Task(name)
+		if err != nil {
+			continue
+		}
+		src := taskSource{Summary: def.Summary}
+		for _, c := range def.Cmds {
+			if c.Cmd != "" {
+				src.Cmds = append(src.Cmds, c.Cmd)
+			}
+		}
+		sources[t.Id] = src
+	}
+	return sources
+}
+
+// matchTask ranks how t matches the lowercased input and returns the line it matched on
+// when that isn't its id or an alias
+func matchTask(t task.Task, src taskSource, input string) (matchRank, string, bool) {
+	if strings.Contains(strings.ToLower(t.Id), input) {
+		return matchId, "", true
+	}
+	for _, alias := range t.Aliases {
+		if strings.Contains(strings.ToLower(alias), input) {
+			return matchAlias, "", true
+		}
+	}
+	if strings.Contains(strings.ToLower(t.Desc), input) {
+		return matchDesc, "desc: " + snippet(t.Desc, input), true
+	}
+	summary := t.Summary
+	if summary == "" {
+		summary = src.Summary
+	}
+	if strings.Contains(strings.ToLower(summary), input) {
+		return matchSummary, "summary: " + snippet(summary, input), true
+	}
+	for _, cmd := range src.Cmds {
+		if strings.Contains(strings.ToLower(cmd), input) {
+			return matchCommand, "runs: " + snippet(cmd, input), true
+		}
+	}
+	return 0, "", false
+}
+
+// snippet returns the line of text containing the lowercased input, trimmed to fit the picker
+func snippet(text, input string) string {
+	line := text
+	for _, l := range strings.Split(text, "\n") {
+		if strings.Contains(strings.ToLower(l), input) {
+			line = l
+			break
+		}
+	}
+	line = strings.TrimSpace(line)
+	if r := []rune(line); len(r) > maxMatchSnippet {
+		line = string(r[:maxMatchSnippet-1]) + "…"
+	}
+	return line
+}
+
+// rankTaskMatches returns the tasks matching the input, best first and in listing order
+// within a rank, with the line each task matched on when it matched beyond its name
+func rankTaskMatches(tasks []task.Task, sources map[string]taskSource, query string) ([]task.Task, map[string]string) {
+	type ranked struct {
+		task task.Task
+		rank matchRank
+	}
+	var list []ranked
+	reasons := make(map[string]string)
+	input := strings.ToLower(query)
+	exact, found := task.Find(tasks, query)
+	for _, t := range tasks {
+		if found && t.Id == exact.Id {
+			list = append(list, ranked{t, matchExact})
+			continue
+		}
+		rank, reason, ok := matchTask(t, sources[t.Id], input)
+		if !ok {
+			continue
+		}
+		if reason != "" {
+			reasons[t.Id] = reason
+		}
+		list = append(list, ranked{t, rank})
+	}
+	slices.SortStableFunc(list, func(a, b ranked) int {
+		return int(a.rank - b.rank)
+	})
+	matches := make([]task.Task, len(list))
+	for i, r := range list {
+		matches[i] = r.task
+	}
+	return matches, reasons
+}
@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/task"
+)
+
+func TestTaskPickerRanksIdsAboveDescriptionsAndCommands(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Taskfile.yml")
+	content := "version: '3'\ntasks:\n" +
+		"  publish:\n    cmds:\n      - goreleaser release --clean\n" +
+		"  changelog:\n    desc: Write the changelog for goreleaser\n    cmds:\n      - git cliff\n" +
+		"  goreleaser:check: goreleaser check\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	location := &task.Location{Taskfile: path}
+	m := NewModel(nil, config.Default())
+	m.HandleWindowResize(120, 40)
+	m.AllTasks = []task.Task{
+		{Id: "publish", Location: location},
+		{Id: "changelog", Desc: "Write the changelog for goreleaser", Location: location},
+		{Id: "goreleaser:check", Location: location},
+		{Id: "lint", Summary: "Runs golangci-lint, then goreleaser check", Location: location},
+	}
+
+	updated, _ := m.handleNormalKey(runes("/"))
+	m = updated.(Model)
+	m.TaskPickerInput = "GoReleaser"
+	m.updateTaskPickerMatches()
+
+	var ids []string
+	for _, match := range m.TaskPickerMatches {
+		ids = append(ids, match.Id)
+	}
+	if got := strings.Join(ids, " "); got != "goreleaser:check changelog lint publish" {
+		t.Errorf("Expected the id match, then the description, summary and command matches, got %q", got)
+	}
+	if reason := m.TaskPickerReasons["publish"]; reason != "runs: goreleaser release --clean" {
+		t.Errorf("Expected the command the task was found by, got %q", reason)
+	}
+	if _, ok := m.TaskPickerReasons["goreleaser:check"]; ok {
+		t.Error("Expected no reason for a match on the task id")
+	}
+	overlay := RenderTaskPicker(160, 40, m.TaskPickerInput, m.TaskPickerMatches, m.TaskPickerReasons, 0)
+	if !strings.Contains(overlay, "publish · runs: goreleaser release") {
+		t.Errorf("Expected the picker to show why the task matched, got:\n%s", overlay)
+	}
+}
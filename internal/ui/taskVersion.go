@@ -0,0 +1,28 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/Aj4x/tash/internal/task"
+)
+
+// UseTaskVersion records the installed task version, pointing out the features tash falls back
+// from because the version lacks them
+func (m *Model) UseTaskVersion(v task.Version) {
+	m.TaskVersion = v
+	if err := v.Check(task.FeatureJSONList, nil); err != nil {
+		m.AppendErrorMsg(err.Error() + ", tasks are listed from the text listing without their locations\n")
+	}
+}
+
+// taskVersionInfo describes the installed task and the experiments enabled for it
+func (m *Model) taskVersionInfo() string {
+	if m.Config.Provider == task.ProviderDemo {
+		return ""
+	}
+	info := " · task " + m.TaskVersion.String()
+	if experiments := task.Experiments(ExecEnvironment(m.Config).Environ(nil)); len(experiments) > 0 {
+		info += " · experiments: " + strings.Join(experiments, ", ")
+	}
+	return info
+}
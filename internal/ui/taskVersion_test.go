@@ -0,0 +1,23 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/task"
+)
+
+func TestUseTaskVersion(t *testing.T) {
+	t.Setenv("TASK_X_REMOTE_TASKFILES", "1")
+	m := NewModel(nil, config.Default())
+	m.HandleWindowResize(120, 40)
+
+	m.UseTaskVersion(task.Version{Major: 3, Minor: 7, Patch: 0})
+	if !strings.Contains(m.output.String(), "JSON task listings need task v3.9.0 or later, v3.7.0 is installed") {
+		t.Errorf("Expected the text listing fallback to be pointed out, got:\n%s", m.output.String())
+	}
+	if info := m.taskVersionInfo(); info != " · task v3.7.0 · experiments: REMOTE_TASKFILES" {
+		t.Errorf("Expected the version and experiments, got %q", info)
+	}
+}
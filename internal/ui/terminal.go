@@ -0,0 +1,35 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// RepairTerminal returns a command that restores the terminal after a task has manipulated it
+// (e.g. by running vim or tput against /dev/tty). Leaving and re-entering the alt screen resets
+// the screen buffer and cursor state, and clearing the screen forces a full repaint.
+func RepairTerminal() tea.Cmd {
+	return tea.Sequence(
+		tea.ExitAltScreen,
+		tea.EnterAltScreen,
+		tea.ClearScreen,
+	)
+}
+
+// repairTerminal repairs the terminal after a run, leaving it alone in inline mode, where
+// clearing the screen would wipe the shell's scrollback, and when embedded in a host program that
+// owns the screen
+func (m Model) repairTerminal() tea.Cmd {
+	if m.Embedded || m.Config.Inline {
+		return nil
+	}
+	return RepairTerminal()
+}
+
+// repairDisplay repairs the terminal when asked to; without the alt screen, in inline mode or
+// when embedded, the view is only repainted
+func (m Model) repairDisplay() tea.Cmd {
+	if m.Embedded || m.Config.Inline {
+		return tea.ClearScreen
+	}
+	return RepairTerminal()
+}
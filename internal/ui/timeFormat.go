@@ -0,0 +1,31 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/Aj4x/tash/internal/config"
+)
+
+// TimeFormat shows timestamps in the configured layout and time zone
+type TimeFormat struct {
+	Layout   string // Replaces the layout of every timestamp when set
+	Location *time.Location
+}
+
+// NewTimeFormat returns the time format configured in cfg; an unknown time zone falls back to the
+// local one
+func NewTimeFormat(cfg config.Config) TimeFormat {
+	loc, _ := cfg.Location()
+	return TimeFormat{Layout: cfg.TimeLayout(), Location: loc}
+}
+
+// Format formats t in the configured time zone, with the configured layout or else layout
+func (f TimeFormat) Format(t time.Time, layout string) string {
+	if f.Layout != "" {
+		layout = f.Layout
+	}
+	if f.Location != nil {
+		t = t.In(f.Location)
+	}
+	return t.Format(layout)
+}
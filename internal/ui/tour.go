@@ -0,0 +1,172 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TourMarkerName is the file in the data directory recording that the onboarding tour was shown
+const TourMarkerName = "tour-done"
+
+// tourStep is a step of the onboarding tour. Steps about a panel focus it, so its border is
+// highlighted while the step is shown.
+type tourStep struct {
+	Title string
+	Text  func(m Model) string
+	Focus *Control
+}
+
+// keyOf returns the key bound to action in the main view, or fallback when it isn't bound
+func (m Model) keyOf(action Action, fallback string) string {
+	if binding, ok := m.KeyBindings.Binding(action); ok {
+		return binding.Key
+	}
+	return fallback
+}
+
+// focus returns the panel a tour step focuses
+func focus(c Control) *Control {
+	return &c
+}
+
+// tourSteps are the steps of the onboarding tour, in order
+var tourSteps = []tourStep{
+	{
+		Title: "Tasks",
+		Text: func(m Model) string {
+			return fmt.Sprintf("The left panel lists the tasks of your Taskfile. Move with %s and %s, "+
+				"and press %s to run the selected task.",
+				m.keyOf(ActionUp, "↑"), m.keyOf(ActionDown, "↓"), m.keyOf(ActionExecute, "enter"))
+		},
+		Focus: focus(ControlTable),
+	},
+	{
+		Title: "Output",
+		Text: func(m Model) string {
+			return fmt.Sprintf("The right panel streams the output of the runs. Press %s to switch "+
+				"focus to it and scroll, and %s to cancel a running task.",
+				m.keyOf(ActionSwitchFocus, "tab"), m.keyOf(ActionCancel, "ctrl+x"))
+		},
+		Focus: focus(ControlViewport),
+	},
+	{
+		Title: "Picker",
+		Text: func(m Model) string {
+			return fmt.Sprintf("Press %s to find a task by its name, description or the commands it "+
+				"runs. With nothing typed, the tasks you pick most come first.",
+				m.keyOf(ActionOpenPicker, "/"))
+		},
+	},
+	{
+		Title: "Batches",
+		Text: func(m Model) string {
+			return fmt.Sprintf("Tasks chosen in the picker are added to a batch, listed below the "+
+				"panels. Press %s to run them one after the other and %s to clear them.",
+				m.keyOf(ActionExecuteBatch, "ctrl+e"), m.keyOf(ActionClearBatch, "ctrl+k"))
+		},
+	},
+	{
+		Title: "Help",
+		Text: func(m Model) string {
+			return fmt.Sprintf("Press %s anytime for the keys of what you're looking at; ctrl+t in "+
+				"the help takes this tour again.", m.keyOf(ActionHelp, "?"))
+		},
+	},
+}
+
+// UseTourMarker shows the onboarding tour unless the marker in the data directory dataDir
+// records it was shown before. Runs started with --run skip it.
+func (m *Model) UseTourMarker(dataDir string) {
+	m.TourMarker = filepath.Join(dataDir, TourMarkerName)
+	if m.StartupTask != "" {
+		return
+	}
+	if _, err := os.Stat(m.TourMarker); errors.Is(err, os.ErrNotExist) {
+		m.openTour()
+	}
+}
+
+// openTour starts the onboarding tour at its first step
+func (m *Model) openTour() {
+	m.TourStep = 0
+	m.SetState(StateTour)
+	m.focusTourStep()
+}
+
+// focusTourStep focuses the panel the current step is about
+func (m *Model) focusTourStep() {
+	if c := tourSteps[m.TourStep].Focus; c != nil {
+		m.setFocus(*c)
+	}
+}
+
+// setFocus focuses the panel c
+func (m *Model) setFocus(c Control) {
+	m.Focused = c
+	if c == ControlTable {
+		m.Table.Focus()
+	} else {
+		m.Table.Blur()
+	}
+}
+
+// closeTour ends the tour and records that it was shown, so it doesn't open on the next start
+func (m *Model) closeTour() {
+	m.SetState(StateNormal)
+	m.setFocus(ControlTable)
+	if m.TourMarker == "" {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(m.TourMarker), 0o755); err != nil {
+		slog.Warn("Unable to record the tour as shown", "error", err)
+		return
+	}
+	if err := os.WriteFile(m.TourMarker, nil, 0o644); err != nil {
+		slog.Warn("Unable to record the tour as shown", "error", err)
+	}
+}
+
+// handleTourKey handles key presses during the onboarding tour
+func (m Model) handleTourKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch m.resolveKey(msg) {
+	case ActionClose:
+		m.closeTour()
+	case ActionDown:
+		if m.TourStep == len(tourSteps)-1 {
+			m.closeTour()
+			return m, nil
+		}
+		m.TourStep++
+		m.focusTourStep()
+	case ActionUp:
+		if m.TourStep > 0 {
+			m.TourStep--
+			m.focusTourStep()
+		}
+	}
+	return m, nil
+}
+
+// RenderTour renders the current step of the onboarding tour
+func RenderTour(m Model) string {
+	overlayWidth := int(float64(m.Width) * 0.5)
+	step := tourSteps[m.TourStep]
+
+	content := TaskPickerTitleStyle.Render(fmt.Sprintf("Welcome to tash (%d/%d)", m.TourStep+1, len(tourSteps))) + "\n\n"
+	content += TableSelectedTaskStyle.Render(step.Title) + "\n"
+	content += step.Text(m) + "\n\n"
+	next := "→/enter next"
+	if m.TourStep == len(tourSteps)-1 {
+		next = "→/enter finish"
+	}
+	content += HelpStyle.Render(next + " · ← back · esc skip the tour")
+
+	overlay := GeneralOverlayStyle(overlayWidth).Render(content)
+
+	return placeOverlay(m.Width, m.Height, overlay)
+}
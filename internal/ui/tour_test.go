@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTourOpensOnTheFirstStartOnly(t *testing.T) {
+	dir := t.TempDir()
+	m := NewModel(nil, config.Default())
+	m.HandleWindowResize(120, 40)
+	m.UseTourMarker(dir)
+	if m.State != StateTour || m.Focused != ControlTable {
+		t.Fatalf("Expected the tour on the first start, at the task table, got state %s", m.State)
+	}
+	if overlay := RenderTour(m); !strings.Contains(overlay, "Welcome to tash (1/5)") || !strings.Contains(overlay, "enter/e to run") {
+		t.Errorf("Expected the first step with the bound keys, got:\n%s", overlay)
+	}
+
+	updated, _ := m.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRight})
+	m = updated.(Model)
+	if m.TourStep != 1 || m.Focused != ControlViewport {
+		t.Errorf("Expected the output step to focus the viewport, got step %d", m.TourStep)
+	}
+	updated, _ = m.handleKeyMsg(tea.KeyMsg{Type: tea.KeyLeft})
+	m = updated.(Model)
+	if m.TourStep != 0 || m.Focused != ControlTable {
+		t.Errorf("Expected to go back to the first step, got step %d", m.TourStep)
+	}
+	updated, _ = m.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEsc})
+	if m = updated.(Model); m.State != StateNormal {
+		t.Fatalf("Expected esc to skip the tour, got state %s", m.State)
+	}
+
+	restarted := NewModel(nil, config.Default())
+	restarted.UseTourMarker(dir)
+	if restarted.State != StateNormal {
+		t.Errorf("Expected no tour once it was shown, got state %s", restarted.State)
+	}
+
+	// The help takes the tour again
+	updated, _ = m.handleNormalKey(runes("?"))
+	m = updated.(Model)
+	updated, _ = m.handleKeyMsg(tea.KeyMsg{Type: tea.KeyCtrlT})
+	if m = updated.(Model); m.State != StateTour || m.TourStep != 0 {
+		t.Errorf("Expected ctrl+t in the help to start the tour, got state %s", m.State)
+	}
+	for range tourSteps {
+		updated, _ = m.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+		m = updated.(Model)
+	}
+	if m.State != StateNormal {
+		t.Errorf("Expected the tour to end after its last step, got state %s", m.State)
+	}
+}
@@ -2,16 +2,35 @@ package ui
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"github.com/Aj4x/tash/internal/catalog"
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/frecency"
+	"github.com/Aj4x/tash/internal/git"
+	"github.com/Aj4x/tash/internal/history"
+	"github.com/Aj4x/tash/internal/instance"
 	"github.com/Aj4x/tash/internal/msgbus"
+	"github.com/Aj4x/tash/internal/namespaces"
+	"github.com/Aj4x/tash/internal/problems"
+	"github.com/Aj4x/tash/internal/queue"
+	"github.com/Aj4x/tash/internal/redact"
+	"github.com/Aj4x/tash/internal/runlog"
+	"github.com/Aj4x/tash/internal/runopts"
+	"github.com/Aj4x/tash/internal/schedule"
 	"github.com/Aj4x/tash/internal/task"
+	"github.com/Aj4x/tash/internal/taskfile"
+	"github.com/Aj4x/tash/internal/update"
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"log/slog"
 	"os/exec"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -36,57 +55,286 @@ func (c Control) Tab() Control {
 	return tabbedControl
 }
 
-// TextWrap wraps text to fit within a specified width
+// TextWrap wraps text to fit within a specified width, measured in terminal cells so wide
+// characters (e.g. CJK or emoji) are never split or allowed to overflow the line
 func TextWrap(s string, n int) []string {
 	if n <= 0 {
 		return nil
 	}
+	// most output is printable ASCII, one cell per byte, which is cut without measuring each rune
+	if printableASCII(s) {
+		if len(s) <= n {
+			return []string{s}
+		}
+		lines := make([]string, 0, (len(s)+n-1)/n)
+		for len(s) > n {
+			lines = append(lines, s[:n])
+			s = s[n:]
+		}
+		return append(lines, s)
+	}
+	widths := cellWidths()
 	var lines []string
-	remaining := s
-	for len(remaining) >= n {
-		lines = append(lines, remaining[:n])
-		remaining = remaining[n:]
+	start, width := 0, 0
+	for i, r := range s {
+		w := widths.RuneWidth(r)
+		if width+w > n && width > 0 {
+			lines = append(lines, s[start:i])
+			start, width = i, 0
+		}
+		width += w
 	}
-	lines = append(lines, remaining)
-	return lines
+	return append(lines, s[start:])
+}
+
+// cellWidths returns the widths of the runes in terminal cells, precomputed in a lookup table the
+// first time text other than printable ASCII is wrapped
+var cellWidths = sync.OnceValue(func() *runewidth.Condition {
+	c := runewidth.NewCondition()
+	c.CreateLUT()
+	return c
+})
+
+// printableASCII reports whether s only holds printable ASCII characters, each a cell wide
+func printableASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < ' ' || s[i] > '~' {
+			return false
+		}
+	}
+	return true
 }
 
 // Model represents the UI model for the application
 type Model struct {
-	MessageBus    msgbus.PublisherSubscriber[task.Message] `json:"-"`
-	busHandler    msgbus.MessageHandler[task.Message]
-	Tasks         []task.Task `json:"-"`
-	TasksLoading  bool
-	Result        *string        `json:"-"`
-	Viewport      viewport.Model `json:"-"`
-	Table         table.Model    `json:"-"`
-	Focused       Control
-	Width         int
-	Height        int
-	Initialised   bool
-	SelectedTask  *task.Task
-	State         UIState        // Current UI state (normal, task picker, details overlay, help overlay)
-	HelpViewport  viewport.Model `json:"-"` // Viewport for scrollable help content
-	Command       *exec.Cmd      `json:"-"`
-	CommandCancel context.CancelFunc
-	TaskRunning   bool
-	KeyBindings   KeyBindings `json:"-"` // Key bindings for the application
+	MessageBus     msgbus.PublisherSubscriber[task.Message] `json:"-"`
+	busHandler     msgbus.MessageHandler[task.Message]
+	Tasks          []task.Task   `json:"-"` // Tasks shown in the table
+	AllTasks       []task.Task   `json:"-"` // Every listed task, including hidden ones
+	listedTasks    []task.Task   // Tasks received so far while the task list is refreshed
+	listing        bool          // Whether the task list is being refreshed
+	CatalogCache   catalog.Store `json:"-"` // Where the task list is cached between starts
+	cachedTasks    []task.Task   // Tasks shown from the cache until the refreshed list arrives
+	startup        *startupTimer // Logs how long startup takes, when timed
+	renderStats    *renderStats  // Times the views for the frame rate overlay, nil when it is hidden
+	batching       bool          // Whether a batch of bus messages is being handled
+	outputPending  bool          // Whether output appended by the batch is yet to be shown
+	busActive      bool          // Whether the last poll of the bus found messages
+	statusChecked  time.Time     // When the tasks were last checked for being up to date
+	statusPolling  bool          // Whether the tasks are being checked for being up to date
+	heartbeatSent  time.Time     // When the daemon last sent a heartbeat
+	ShowHidden     bool          // Whether internal and ignored tasks are shown
+	GroupIncludes  bool          // Whether tasks are grouped by the Taskfile defining them
+	GroupLabels    bool          // Whether tasks are grouped by their configured labels
+	LabelFilter    string        // Label of the tasks listed, or empty for all tasks
+	Verbosity      Verbosity     // Whether tasks run with --verbose or --silent
+	ReducedMotion  bool          // Whether the screen is redrawn less often and counters kept still
+	HiddenCount    int           // Number of tasks currently hidden from the table
+	TasksLoading   bool
+	output         *outputStore   // The content of the output viewport
+	Viewport       viewport.Model `json:"-"`
+	Table          table.Model    `json:"-"`
+	Focused        Control
+	Width          int
+	Height         int
+	Layout         Layout // Arrangement of the task list and output, chosen on resize
+	Initialised    bool
+	SelectedTask   *task.Task
+	CommandPreview *CommandPreview // Resolved commands of the task shown in the details overlay
+	State          UIState         // Current UI state (normal, task picker, details overlay, help overlay)
+	HelpViewport   viewport.Model  `json:"-"` // Viewport for scrollable help content
+	HelpSearch     string          // Filter typed into the help overlay's search box
+	HelpShowAll    bool            // Whether the help overlay lists bindings for every context
+	Command        *exec.Cmd       `json:"-"`
+	CommandCancel  context.CancelFunc
+	TaskRunning    bool
+	TaskPaused     bool
+	KeyBindings    KeyBindings   `json:"-"` // Key bindings for the application
+	Config         config.Config `json:"-"` // User configuration
+	ConfigPath     string        // User config file that rebound keys are saved to
+	TimeFormat     TimeFormat    `json:"-"` // Layout and time zone of the timestamps shown
+
+	// Key bindings overlay
+	KeyBindingSelected int
+	KeyBindingCapture  bool
+
+	// Embedded is set when the model is hosted as a panel inside another program, which
+	// decides itself when to quit
+	Embedded bool
+
+	// Headless is set when the model runs in a daemon without an interface, so tasks needing
+	// input are refused rather than prompted for
+	Headless bool
+
+	// Job of the queue directory being run, and when the directory was last checked for jobs
+	queueJob       *queue.Job
+	queueChecked   time.Time
+	queueRecovered bool
+
+	// Remote is the daemon this interface is attached to, which runs its tasks instead
+	Remote          *instance.Info `json:"-"`
+	remoteRequested string
+
+	// StartupTask is a task id or alias to run once the task list has loaded
+	StartupTask string
+
+	// TaskVersion is the installed task, detected at startup, which decides the features tash
+	// uses; the zero value is an undetected version, assumed to be recent
+	TaskVersion task.Version
+
+	// PendingKeys holds the keys pressed so far of an unfinished chord, and Count the
+	// count prefix typed before a navigation key
+	PendingKeys []string
+	Count       int
 
 	// Task picker fields
 	TaskPickerInput    string
 	TaskPickerMatches  []task.Task `json:"-"`
 	TaskPickerSelected int
+	// TaskPickerReasons holds the line each match was found on when that isn't its id or an
+	// alias, and TaskPickerSources the summaries and commands read when the picker opened
+	TaskPickerReasons map[string]string     `json:"-"`
+	TaskPickerSources map[string]taskSource `json:"-"`
+	// Picks are the tasks chosen in the picker, kept per project to list the most frecent first
+	Picks     frecency.Picks `json:"-"`
+	PickStore frecency.Store `json:"-"`
 
 	// Selected tasks for batch execution
 	SelectedTasks         []task.Task
 	ExecutingBatch        bool
 	CurrentBatchTaskIndex int
+
+	// Ad-hoc shell command being entered
+	CommandInput string
+
+	// Input collected for tasks with prompts or required variables
+	RunPrompt RunPrompt
+	RunInputs map[string]runInputs
+
+	// Password asked for by the running task
+	PasswordPrompt PasswordPrompt
+
+	// Id typed to confirm a run of a dangerous task, and the run it confirms
+	DangerPrompt    DangerPrompt
+	dangerConfirmed string
+
+	// Task running on a group of hosts
+	FanOut FanOut `json:"-"`
+	// Services of the compose file
+	Compose Compose `json:"-"`
+	// Outcome of the project's pre-flight checks
+	Preflight Preflight `json:"-"`
+	// Snippet picked to add to the Taskfile
+	Snippets SnippetPicker `json:"-"`
+	// Tasks imported from the Makefile, previewed before they are added to the Taskfile
+	MakeImport MakeImport `json:"-"`
+
+	// Options of a single task run, and where the last options of each task are kept
+	RunOptionsForm  RunOptionsForm
+	RunOptionsStore runopts.Store `json:"-"`
+	nextRunOptions  *RunOptionsForm
+	nextVariant     *task.Task             // Loop variant whose variables the next run of its task gets
+	variants        map[string][]task.Task // Loop variants listed below the task defining the loop
+	ciJobs          []task.Task            // Jobs of the project's CI pipelines, listed after its tasks
+
+	// Scheduled task runs
+	Schedules        []schedule.Entry `json:"-"`
+	ScheduleInput    string
+	ScheduleSelected int
+
+	// Output of the current run, and the last two runs of each task for comparison
+	RunningTaskId string
+	runStarted    time.Time
+	runLines      []string
+	lastRun       *history.Record // Last finished run, reported by the daemon's heartbeat
+
+	// Output lines as rendered, the folds grouping them by run and step, and the rules
+	// highlighting and redacting task output
+	highlights   []highlightRule
+	redactor     *redact.Redactor
+	outputLines  []string
+	spilledLines int // Number of output lines moved from memory to the spill file
+	spill        *outputSpill
+	Folds        []outputFold `json:"-"`
+	FoldCursor   int          // Index of the selected fold, or -1
+	displayLines []displayLine
+	RunHistory   map[string]RunPair `json:"-"`
+	diffTaskId   string
+
+	// Problems reported in the output of the current run or batch, and the summary of the
+	// last failed run
+	Problems        []problems.Problem `json:"-"`
+	ProblemSelected int
+	problemMatchers []problems.Matcher
+	runStderr       []outputTail
+	runCancelled    bool
+	FailureSummary  *FailureSummary `json:"-"`
+
+	// Persisted run history and the statistics shown in the stats overlay
+	History history.Store   `json:"-"`
+	Stats   []history.Stats `json:"-"`
+	// Logs and metadata files of the runs, and the log of the run in progress
+	RunLogs        runlog.Store `json:"-"`
+	runLog         *runlog.Run
+	DiffViewport   viewport.Model `json:"-"`
+	DiffSideBySide bool
+
+	// Variables of the selected task listed in the vars overlay
+	VarsViewport viewport.Model `json:"-"`
+
+	// Description, summary and aliases being edited for the selected task
+	MetadataForm MetadataForm
+
+	// Name being entered for a copy of a task
+	ClonePrompt ClonePrompt
+
+	// Namespaces hidden from the task list and pinned to its top, kept per project
+	Namespaces        namespaces.Prefs
+	NamespaceStore    namespaces.Store `json:"-"`
+	NamespaceSelected int
+
+	// TourStep is the step of the onboarding tour shown, and TourMarker the file recording that
+	// the tour was shown, empty when it isn't recorded
+	TourStep   int
+	TourMarker string `json:"-"`
+
+	// LogPath is the debug log file, empty when debug logging is off
+	LogPath string `json:"-"`
+
+	// UpdateChecker looks up the latest release at startup, nil when the check is off, and
+	// LatestRelease is the release found newer than the running build
+	UpdateChecker *update.Checker `json:"-"`
+	LatestRelease *update.Release `json:"-"`
+
+	// URL being entered for a remote Taskfile
+	RemotePrompt RemotePrompt
+
+	// Problems found in the Taskfiles: the failure of the last listing and the lint findings
+	Diagnostics        []taskfile.Diagnostic `json:"-"`
+	DiagnosticSelected int
+	listDiagnostics    []taskfile.Diagnostic
+	lintDiagnostics    []taskfile.Diagnostic
+
+	// File watchers
+	PendingWatchRuns []string
+	stopWatchers     context.CancelFunc
+
+	// Run-until-fail mode
+	Repeat RepeatState
+
+	// Branch and changes of the git repository, nil outside a repository
+	Git        *git.Status `json:"-"`
+	gitChecked time.Time
+
+	// OutputLineCount counts output lines received since the last run finished, for the debug log
+	OutputLineCount int
 }
 
 // NewModel creates a new UI model
-func NewModel(bus msgbus.PublisherSubscriber[task.Message]) Model {
+func NewModel(bus msgbus.PublisherSubscriber[task.Message], cfg config.Config) Model {
 	columns := []table.Column{
 		{Title: "Id", Width: 30},
+		{Title: "Aliases", Width: 14},
 		{Title: "Description", Width: 40},
 	}
 
@@ -102,19 +350,36 @@ func NewModel(bus msgbus.PublisherSubscriber[task.Message]) Model {
 		Selected: TableSelectedStyle,
 	})
 
+	kb := DefaultKeyBindings()
+	kb.ReadOnly = cfg.ReadOnly
+	if err := kb.ApplyOverrides(cfg.KeyBindings); err != nil {
+		slog.Warn("Ignoring key binding overrides", "error", err)
+	}
+	configPath, _ := config.Path()
+
 	return Model{
-		MessageBus:   bus,
-		busHandler:   make(msgbus.MessageHandler[task.Message], 4096),
-		Tasks:        []task.Task{},
-		Result:       new(string),
-		Viewport:     viewport.New(0, 0),
-		Table:        t,
-		Focused:      ControlTable,
-		Initialised:  false,
-		SelectedTask: nil,
-		State:        StateNormal,
-		HelpViewport: viewport.New(0, 0),
-		KeyBindings:  DefaultKeyBindings(),
+		MessageBus:    bus,
+		busHandler:    make(msgbus.MessageHandler[task.Message], 4096),
+		Tasks:         []task.Task{},
+		output:        newOutputStore(),
+		Viewport:      viewport.New(0, 0),
+		Table:         t,
+		Focused:       ControlTable,
+		Initialised:   false,
+		SelectedTask:  nil,
+		State:         StateNormal,
+		HelpViewport:  viewport.New(0, 0),
+		DiffViewport:  viewport.New(0, 0),
+		VarsViewport:  viewport.New(0, 0),
+		KeyBindings:   kb,
+		Config:        cfg,
+		TimeFormat:    NewTimeFormat(cfg),
+		ReducedMotion: cfg.ReducedMotion,
+		ConfigPath:    configPath,
+		ShowHidden:    !cfg.HideTasks,
+		GroupIncludes: cfg.GroupByInclude,
+		GroupLabels:   cfg.GroupByLabel,
+		Verbosity:     ParseVerbosity(cfg.Verbosity),
 
 		// Initialize task picker fields
 		TaskPickerInput:    "",
@@ -123,28 +388,40 @@ func NewModel(bus msgbus.PublisherSubscriber[task.Message]) Model {
 
 		// Initialize selected tasks
 		SelectedTasks: []task.Task{},
+
+		Schedules:       loadSchedules(cfg, time.Now()),
+		RunOptionsStore: runOptionsStore(),
+		FoldCursor:      -1,
+		highlights:      compileHighlights(cfg.Highlights),
+		redactor:        compileRedactor(cfg.RedactPatterns),
+		problemMatchers: loadProblemMatchers(cfg),
 	}
 }
 
-// View renders the UI
+// View renders the UI, timing it while the frame rate overlay is shown
 func (m Model) View() string {
+	if m.renderStats == nil {
+		return m.view()
+	}
+	start := time.Now()
+	view := m.view()
+	m.renderStats.record(start, time.Since(start))
+	return view
+}
+
+// view renders the interface
+func (m Model) view() string {
 	if !m.Initialised {
 		return "Initialising..."
 	}
-
-	tableRendered := m.Table.View()
-	viewportRendered := m.Viewport.View()
-
-	if m.Focused == ControlTable {
-		tableRendered = FocusedStyle.Render(tableRendered)
-		viewportRendered = ViewportStyle.Render(viewportRendered)
-	} else if m.Focused == ControlViewport {
-		tableRendered = TableStyle.Render(tableRendered)
-		viewportRendered = FocusedStyle.Render(viewportRendered)
+	if m.Width < MinWidth || m.Height < MinHeight {
+		return fmt.Sprintf("Terminal too small (%dx%d), need at least %dx%d", m.Width, m.Height, MinWidth, MinHeight)
 	}
 
+	m.startup.mark("first render")
+
 	// Build the layout
-	mainView := lipgloss.JoinHorizontal(lipgloss.Top, tableRendered, viewportRendered)
+	mainView := m.renderPanels()
 
 	// Add selected tasks display if there are any
 	var selectedTasksText string
@@ -160,38 +437,213 @@ func (m Model) View() string {
 		)
 	}
 
-	// Add help text at the bottom
-	helpText := m.KeyBindings.RenderHelpView(m.TaskRunning, m.State == StateTaskPicker, len(m.SelectedTasks) > 0)
+	// Show the progress of a task list refresh
+	if m.listing {
+		loading := fmt.Sprintf(" Loading tasks… %d so far", len(m.listedTasks))
+		if m.ReducedMotion {
+			loading = " Loading tasks…"
+		}
+		selectedTasksText = lipgloss.JoinVertical(lipgloss.Left, selectedTasksText, HelpStyle.Render(loading))
+	}
+
+	// Show the label the task list is filtered by
+	if m.LabelFilter != "" {
+		selectedTasksText = lipgloss.JoinVertical(lipgloss.Left, selectedTasksText,
+			HelpStyle.Render(" Tasks labelled "+m.LabelFilter))
+	}
+
+	// Show how many tasks are hidden from the table
+	if m.HiddenCount > 0 {
+		selectedTasksText = lipgloss.JoinVertical(lipgloss.Left, selectedTasksText,
+			HelpStyle.Render(fmt.Sprintf(" %d hidden tasks", m.HiddenCount)))
+	}
+
+	// Show how many problems the output reported
+	if len(m.Problems) > 0 {
+		selectedTasksText = lipgloss.JoinVertical(lipgloss.Left, selectedTasksText,
+			ErrorMsgStyle.PaddingLeft(1).Render(fmt.Sprintf("%d problems, press P to list them", len(m.Problems))))
+	}
+
+	// Show the branch and changes of the git repository
+	if m.Git != nil {
+		selectedTasksText = lipgloss.JoinVertical(lipgloss.Left, selectedTasksText,
+			HelpStyle.Render(" "+gitStatusText(*m.Git)))
+	}
+
+	// Show that nothing can be run or edited
+	if m.Config.ReadOnly {
+		selectedTasksText = lipgloss.JoinVertical(lipgloss.Left, selectedTasksText,
+			HelpStyle.Render(" Read-only: running tasks and editing Taskfiles are disabled"))
+	}
+
+	// Show that a newer release is out
+	if m.LatestRelease != nil {
+		selectedTasksText = lipgloss.JoinVertical(lipgloss.Left, selectedTasksText,
+			HelpStyle.Render(" "+updateNotice(*m.LatestRelease)))
+	}
+
+	// Show the daemon running the tasks
+	if m.Remote != nil {
+		selectedTasksText = lipgloss.JoinVertical(lipgloss.Left, selectedTasksText,
+			HelpStyle.Render(fmt.Sprintf(" Attached to tash daemon (pid %d), quitting detaches", m.Remote.Pid)))
+	}
+
+	// Show the flag added to task runs
+	if flag := m.Verbosity.Flag(); flag != "" {
+		selectedTasksText = lipgloss.JoinVertical(lipgloss.Left, selectedTasksText,
+			HelpStyle.Render(" Runs with "+flag))
+	}
+
+	// Show the progress of the run until failure
+	if m.Repeat.Active {
+		selectedTasksText = lipgloss.JoinVertical(lipgloss.Left, selectedTasksText,
+			ScheduleStatusStyle.Render(fmt.Sprintf("Running '%s' until failure: iteration %d/%d",
+				m.Repeat.TaskId, m.Repeat.Iteration, m.Repeat.MaxIterations)))
+	}
+
+	// Show that the running task is paused
+	if m.TaskPaused {
+		selectedTasksText = lipgloss.JoinVertical(lipgloss.Left, selectedTasksText,
+			PausedStatusStyle.Render("⏸ Task paused - press p to resume"))
+	}
+
+	// Show when the next scheduled run is due
+	if next := nextScheduled(m.Schedules); next != nil {
+		selectedTasksText = lipgloss.JoinVertical(lipgloss.Left, selectedTasksText,
+			ScheduleStatusStyle.Render(fmt.Sprintf("Next scheduled run: %s at %s (%d scheduled)",
+				next.TaskId, m.TimeFormat.Format(next.NextRun, time.Kitchen), len(m.Schedules))))
+	}
+
+	// Show how fast the views render
+	if m.renderStats != nil {
+		selectedTasksText = lipgloss.JoinVertical(lipgloss.Left, selectedTasksText,
+			HelpStyle.Render(m.renderStatsLine(time.Now())))
+	}
+
+	// Add the hint strip for the current state at the bottom
+	helpText := m.KeyBindings.RenderHints(m.State.HintContexts(), m.hintConditions(), m.Width)
+	if len(m.PendingKeys) > 0 || m.Count > 0 {
+		// Show the count and keys of an unfinished chord
+		pending := strings.Join(m.PendingKeys, " ")
+		if m.Count > 0 {
+			pending = strconv.Itoa(m.Count) + pending
+		}
+		helpText = HelpStyle.Render(pending + " …")
+	}
 
 	// Combine everything
 	var fullView string
-	if len(m.SelectedTasks) > 0 {
+	if selectedTasksText != "" {
 		fullView = lipgloss.JoinVertical(lipgloss.Left, mainView, selectedTasksText, helpText)
 	} else {
 		fullView = lipgloss.JoinVertical(lipgloss.Left, mainView, helpText)
 	}
 
-	// Render the appropriate view based on the current state
+	if m.State == StateNormal {
+		return fullView
+	}
+
+	// Overlays keep the hint strip on the last line
+	m.Height--
+	return lipgloss.JoinVertical(lipgloss.Left, m.renderOverlay(), helpText)
+}
+
+// renderOverlay renders the overlay for the current state
+func (m Model) renderOverlay() string {
 	switch m.State {
 	case StateDetailsOverlay:
-		return RenderTaskDetailOverlay(m.Width, m.Height, m.SelectedTask)
+		return RenderTaskDetailOverlay(m.Width, m.Height, m.SelectedTask, m.CommandPreview)
 	case StateTaskPicker:
-		return RenderTaskPicker(m.Width, m.Height, m.TaskPickerInput, m.TaskPickerMatches, m.TaskPickerSelected)
+		return RenderTaskPicker(m.Width, m.Height, m.TaskPickerInput, m.TaskPickerMatches, m.TaskPickerReasons, m.TaskPickerSelected)
 	case StateHelpOverlay:
 		return RenderHelpOverlay(&m)
-	default: // StateNormal
-		return fullView
+	case StateDiffOverlay:
+		return RenderDiffOverlay(&m)
+	case StateStatsOverlay:
+		return RenderStatsOverlay(m.Width, m.Height, m.Stats)
+	case StateKeyBindingsOverlay:
+		return RenderKeyBindingsOverlay(m.Width, m.Height, m.KeyBindings, m.KeyBindingSelected, m.KeyBindingCapture)
+	case StateSchedulePrompt:
+		return RenderSchedulePrompt(m.Width, m.Height, m.SelectedTask, m.ScheduleInput)
+	case StateCommandLine:
+		return RenderCommandLine(m.Width, m.Height, m.CommandInput)
+	case StateRunPrompt:
+		return RenderRunPrompt(m.Width, m.Height, m.RunPrompt)
+	case StateFailureSummary:
+		return RenderFailureSummary(m.Width, m.Height, m.FailureSummary)
+	case StateVarsOverlay:
+		return RenderVarsOverlay(&m)
+	case StateMetadataEditor:
+		return RenderMetadataEditor(m.Width, m.Height, m.MetadataForm)
+	case StateClonePrompt:
+		return RenderClonePrompt(m.Width, m.Height, m.ClonePrompt)
+	case StateDiagnosticsOverlay:
+		return RenderDiagnosticsOverlay(m.Width, m.Height, m.Diagnostics, m.DiagnosticSelected)
+	case StateRemotePrompt:
+		return RenderRemotePrompt(m.Width, m.Height, m.RemotePrompt)
+	case StateTrustPrompt:
+		return RenderTrustPrompt(m.Width, m.Height, m.Config.Taskfile)
+	case StateNamespacesOverlay:
+		return RenderNamespacesOverlay(m.Width, m.Height, m.namespaceList(), m.Namespaces, m.NamespaceSelected)
+	case StateTour:
+		return RenderTour(m)
+	case StateAboutOverlay:
+		return RenderAboutOverlay(m.Width, m.Height, m.aboutReport())
+	case StateDangerPrompt:
+		return RenderDangerPrompt(m.Width, m.Height, m.DangerPrompt)
+	case StatePasswordPrompt:
+		return RenderPasswordPrompt(m.Width, m.Height, m.PasswordPrompt)
+	case StateFanOut:
+		return RenderFanOut(m.Width, m.Height, m.FanOut)
+	case StateCompose:
+		return RenderCompose(m.Width, m.Height, m.Compose)
+	case StatePreflight:
+		return RenderPreflight(m.Width, m.Height, m.Preflight)
+	case StateSnippets:
+		return RenderSnippets(m.Width, m.Height, m.Snippets)
+	case StateMakeImport:
+		return RenderMakeImport(m.Width, m.Height, m.MakeImport)
+	case StateProblemsOverlay:
+		return RenderProblemsOverlay(m.Width, m.Height, m.Problems, m.ProblemSelected)
+	case StateRunOptions:
+		return RenderRunOptions(m.Width, m.Height, m.RunOptionsForm, m.profileNames())
+	case StateScheduleOverlay:
+		return RenderScheduleOverlay(m.Width, m.Height, m.Schedules, m.ScheduleSelected, time.Now(), m.TimeFormat, m.ReducedMotion)
+	default:
+		return ""
+	}
+}
+
+// hintConditions returns the conditions used to decide which hints are shown
+func (m Model) hintConditions() Condition {
+	var c Condition
+	if m.TaskRunning {
+		c |= CondTaskRunning
 	}
+	if len(m.SelectedTasks) > 0 {
+		c |= CondTasksSelected
+	}
+	return c
 }
 
 // AppendToViewport adds text to the viewport
 func (m *Model) AppendToViewport(msg string, style lipgloss.Style) {
+	m.appendLines(msg, func(line string) string { return style.Render(line) })
+}
+
+// appendLines wraps msg to the viewport and adds each line, rendered by render
+func (m *Model) appendLines(msg string, render func(string) string) {
 	lines := TextWrap(msg, m.Viewport.Width)
-	for _, line := range lines {
-		*m.Result += "\n" + style.Render(line)
+	for i, line := range lines {
+		lines[i] = render(line)
+	}
+	m.output.append(lines)
+	m.outputLines = append(m.outputLines, lines...)
+	if m.batching {
+		m.outputPending = true
+		return
 	}
-	m.Viewport.SetContent(*m.Result)
-	m.Viewport.GotoBottom()
+	m.flushOutput()
 }
 
 // AppendAppMsg adds an application message to the viewport
@@ -209,16 +661,53 @@ func (m *Model) AppendCommandOutput(msg string) {
 	m.AppendToViewport(msg, OutputStyle)
 }
 
-// UpdateTaskTable updates the task table with the current tasks
+// UpdateTaskTable updates the task table with the current tasks. A Taskfile column is shown
+// when some tasks come from included Taskfiles.
 func (m *Model) UpdateTaskTable() {
+	columns := []table.Column{
+		{Title: "Id", Width: 30},
+		{Title: "Aliases", Width: 14},
+		{Title: "Description", Width: 40},
+	}
+	includes := task.HasIncludes(m.AllTasks)
+	if includes {
+		columns = append(columns, table.Column{Title: "Taskfile", Width: 24})
+	}
+	labels := len(m.Config.Labels) > 0
+	if labels {
+		columns = append(columns, table.Column{Title: "Labels", Width: 16})
+	}
+	root := task.RootTaskfile(m.AllTasks)
+
 	var rows []table.Row
 	for _, t := range m.Tasks {
-		rows = append(rows, table.Row{
-			t.Id,
-			t.Desc,
-		})
+		id, desc := t.Id, t.Desc
+		if t.UpToDate {
+			id += " ✓"
+		}
+		if t.LoopOf != "" {
+			id, desc = variantRow(t)
+		}
+		row := table.Row{
+			id,
+			strings.Join(t.Aliases, ", "),
+			desc,
+		}
+		if includes {
+			row = append(row, task.IncludePath(t, root))
+		}
+		if labels {
+			row = append(row, strings.Join(m.Config.LabelsFor(t.Id), ", "))
+		}
+		rows = append(rows, row)
 	}
+	// the rows are cleared first, as the table renders them against the new columns
+	m.Table.SetRows(nil)
+	m.Table.SetColumns(columns)
 	m.Table.SetRows(rows)
+	if m.Table.Cursor() >= len(rows) {
+		m.Table.SetCursor(max(len(rows)-1, 0))
+	}
 }
 
 // Init initializes the model
@@ -231,31 +720,38 @@ func (m Model) Init() tea.Cmd {
 	}
 	topics := []msgbus.Topic{
 		task.TypeTaskOutput.Topic(),
+		// stderr lines, shown in the output and kept for the failure summary
+		task.TypeTaskOutputErr.Topic(),
 		task.TypeTaskError.Topic(),
 		task.TypeTaskJSON.Topic(),
 		task.TypeTaskCommand.Topic(),
 		task.TypeTaskDone.Topic(),
-		task.TypeTaskListAllDone.Topic(),
+		task.TypeTaskListPartial.Topic(),
 		task.TypeTaskListAllErr.Topic(),
+		task.TypeWatchTrigger.Topic(),
+		task.TypeTaskPassword.Topic(),
 	}
 	for _, t := range topics {
 		sub(t)
 	}
+	var watchers tea.Cmd
+	if m.Config.WatchEnabled && !m.Config.ReadOnly {
+		watchers = m.startWatchers()
+	}
+	// Init works on a copy of the model, so the refresh that changes it is left to Update
+	refresh := func() tea.Msg { return refreshTaskListMsg{} }
 	return tea.Batch(
-		m.RefreshTaskList(),
+		refresh,
 		m.pollMessages(),
+		watchers,
+		m.checkForUpdate(),
+		m.runPreflight(),
+		m.cleanDataDir(),
 	)
 }
 
 func parseTasksJson(jsonStr string) ([]task.Task, error) {
-	var t struct {
-		Tasks []task.Task `json:"tasks"`
-	}
-	err := json.Unmarshal([]byte(jsonStr), &t)
-	if err != nil {
-		return nil, err
-	}
-	return t.Tasks, nil
+	return task.ParseTasksJson(jsonStr)
 }
 
 // Update handles messages and updates the model
@@ -269,40 +765,108 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handleKeyMsg(msg)
 
 	case TickMessage:
-		return m, m.pollMessages()
-
-	// handle any bus messages
-	case task.Message:
-		// Process the message and set up another listener
-		newModel, cmd := m.handleBusMessage(msg)
+		m.busActive = false
+		now := time.Now()
+		newModel, cmd := m.runDueSchedules(now)
 		if cmd == nil {
-			return newModel, newModel.pollMessages()
+			newModel, cmd = newModel.runPendingWatchRuns()
 		}
-		return newModel, tea.Batch(cmd, newModel.pollMessages())
-	case msgbus.TopicMessage[task.Message]:
-		// Process the message and set up another listener
-		newModel, cmd := m.handleBusMessage(msg.Message)
 		if cmd == nil {
-			return newModel, newModel.pollMessages()
+			newModel, cmd = newModel.runQueuedJob(now)
 		}
-		return newModel, tea.Batch(cmd, newModel.pollMessages())
+		newModel, gitCmd := newModel.refreshGitStatus(now)
+		newModel, statusCmd := newModel.pollTaskStatus(now)
+		newModel, heartbeatCmd := newModel.sendHeartbeat(now)
+		return newModel, tea.Batch(cmd, gitCmd, statusCmd, heartbeatCmd, newModel.pollMessages())
+
+	case refreshTaskListMsg:
+		return m, m.RefreshTaskList()
+
+	case listFetchedMsg:
+		// the listing arrives over the bus, which is already being polled
+		return m, nil
+
+	case taskStatusMsg:
+		return m.handleTaskStatus(msg)
+
+	case lintMsg:
+		return m.handleLint(msg)
+
+	case trustedMsg:
+		return m.handleTrusted(msg)
+
+	case gitStatusMsg:
+		m.Git = msg.Status
+		return m, nil
+
+	case updateMsg:
+		m.LatestRelease = &msg.Release
+		return m, nil
+
+	case pasteMsg:
+		return m.handlePasteMsg(msg)
+
+	case fanOutMsg:
+		return m.handleFanOutMsg(msg)
+
+	case ciJobsMsg:
+		return m.handleCIJobsMsg(msg)
+
+	case composeServicesMsg:
+		return m.handleComposeServicesMsg(msg)
+
+	case preflightMsg:
+		return m.handlePreflightMsg(msg)
+
+	case watchersStartedMsg:
+		m.stopWatchers = msg.stop
+		return m, nil
+
+	case shellExitedMsg:
+		return m.handleShellExited(msg)
+
+	case editorExitedMsg:
+		return m.handleEditorExited(msg)
+
+	case pagerExitedMsg:
+		return m.handlePagerExited(msg)
+
+	case RemoteRunMsg:
+		return m.handleRemoteRun(msg)
+
+	case RemoteCancelMsg:
+		return m.handleRemoteCancel(msg)
+
+	case RemoteEventMsg:
+		return m.handleRemoteEvent(msg)
+
+	case remoteRunResultMsg:
+		return m.handleRemoteRunResult(msg)
+
+	case RemoteDetachedMsg:
+		return m.handleRemoteDetached(msg)
+
+	// handle any bus messages; only the batches and ticks of the poll started by Init poll again,
+	// so the bus is polled by a single chain of commands
+	case busBatchMsg:
+		return m.handleBusBatch(msg)
+	case task.Message:
+		return m.handleBusMessage(msg)
+	case msgbus.TopicMessage[task.Message]:
+		return m.handleBusMessage(msg.Message)
 	default:
-		return m, m.pollMessages()
+		return m, nil
 	}
 }
 
 type TickMessage struct{}
 
-func (m Model) pollMessages() tea.Cmd {
-	return tea.Tick(time.Millisecond*50, func(t time.Time) tea.Msg {
-		select {
-		case msg := <-m.busHandler:
-			return msg.Message
-		default:
-			return TickMessage{}
-		}
-	})
-}
+// listFetchedMsg reports that the task list command has finished; its output was published to
+// the bus
+type listFetchedMsg struct{}
+
+// refreshTaskListMsg asks for the task list to be refreshed
+type refreshTaskListMsg struct{}
 
 // handleWindowSizeMsg handles window resize events
 func (m Model) handleWindowSizeMsg(msg tea.WindowSizeMsg) (tea.Model, tea.Cmd) {
@@ -323,6 +887,58 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleDetailsOverlayKey(msg)
 	case StateHelpOverlay:
 		return m.handleHelpOverlayKey(msg)
+	case StateDiffOverlay:
+		return m.handleDiffOverlayKey(msg)
+	case StateStatsOverlay:
+		return m.handleStatsOverlayKey(msg)
+	case StateKeyBindingsOverlay:
+		return m.handleKeyBindingsOverlayKey(msg)
+	case StateSchedulePrompt:
+		return m.handleSchedulePromptKey(msg)
+	case StateCommandLine:
+		return m.handleCommandLineKey(msg)
+	case StateRunPrompt:
+		return m.handleRunPromptKey(msg)
+	case StateRunOptions:
+		return m.handleRunOptionsKey(msg)
+	case StateProblemsOverlay:
+		return m.handleProblemsOverlayKey(msg)
+	case StateFailureSummary:
+		return m.handleFailureSummaryKey(msg)
+	case StateVarsOverlay:
+		return m.handleVarsOverlayKey(msg)
+	case StateMetadataEditor:
+		return m.handleMetadataEditorKey(msg)
+	case StateClonePrompt:
+		return m.handleClonePromptKey(msg)
+	case StateDiagnosticsOverlay:
+		return m.handleDiagnosticsOverlayKey(msg)
+	case StateRemotePrompt:
+		return m.handleRemotePromptKey(msg)
+	case StateTrustPrompt:
+		return m.handleTrustPromptKey(msg)
+	case StateNamespacesOverlay:
+		return m.handleNamespacesOverlayKey(msg)
+	case StateTour:
+		return m.handleTourKey(msg)
+	case StateAboutOverlay:
+		return m.handleAboutOverlayKey(msg)
+	case StateDangerPrompt:
+		return m.handleDangerPromptKey(msg)
+	case StatePasswordPrompt:
+		return m.handlePasswordPromptKey(msg)
+	case StateFanOut:
+		return m.handleFanOutKey(msg)
+	case StateCompose:
+		return m.handleComposeKey(msg)
+	case StatePreflight:
+		return m.handlePreflightKey(msg)
+	case StateSnippets:
+		return m.handleSnippetsKey(msg)
+	case StateMakeImport:
+		return m.handleMakeImportKey(msg)
+	case StateScheduleOverlay:
+		return m.handleScheduleOverlayKey(msg)
 	default: // StateNormal
 		return m.handleNormalKey(msg)
 	}
@@ -330,17 +946,20 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 // handleTaskPickerKey handles key presses when the task picker is open
 func (m Model) handleTaskPickerKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	action := m.resolveKey(msg)
+
 	// Close the task picker
-	if IsKeyMatch(msg, "esc") {
-		m.State = StateNormal
+	if action == ActionClose {
+		m.SetState(StateNormal)
 		m.Focused = ControlTable
 		return m, nil
 	}
 
 	// Select the current task
-	if IsKeyMatch(msg, "enter") {
+	if action == ActionConfirm {
 		if len(m.TaskPickerMatches) > 0 && m.TaskPickerSelected < len(m.TaskPickerMatches) {
 			selectedTask := m.TaskPickerMatches[m.TaskPickerSelected]
+			m.recordPick(selectedTask.Id)
 
 			// Check if task is already in selected tasks
 			alreadySelected := false
@@ -358,14 +977,14 @@ func (m Model) handleTaskPickerKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 
 			// Close the picker
-			m.State = StateNormal
+			m.SetState(StateNormal)
 			m.Focused = ControlTable
 		}
 		return m, nil
 	}
 
 	// Autocomplete with the selected match
-	if IsKeyMatch(msg, "tab") {
+	if action == ActionAutocomplete {
 		if len(m.TaskPickerMatches) > 0 && m.TaskPickerSelected < len(m.TaskPickerMatches) {
 			m.TaskPickerInput = m.TaskPickerMatches[m.TaskPickerSelected].Id
 			// Update matches based on the new input
@@ -375,7 +994,7 @@ func (m Model) handleTaskPickerKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 
 	// Navigate up in matches
-	if IsKeyMatch(msg, "up") || IsKeyMatch(msg, "k") {
+	if action == ActionUp {
 		if m.TaskPickerSelected > 0 {
 			m.TaskPickerSelected--
 		}
@@ -383,7 +1002,7 @@ func (m Model) handleTaskPickerKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 
 	// Navigate down in matches
-	if IsKeyMatch(msg, "down") || IsKeyMatch(msg, "j") {
+	if action == ActionDown {
 		if m.TaskPickerSelected < len(m.TaskPickerMatches)-1 {
 			m.TaskPickerSelected++
 		}
@@ -411,31 +1030,16 @@ func (m Model) handleTaskPickerKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 // updateTaskPickerMatches updates the task picker matches based on the current input
 func (m *Model) updateTaskPickerMatches() {
 	if m.TaskPickerInput == "" {
-		m.TaskPickerMatches = m.Tasks
+		m.TaskPickerMatches = m.pickerTasks()
+		m.TaskPickerReasons = nil
 		return
 	}
 
-	// Filter tasks based on input
-	var matches []task.Task
-	input := strings.ToLower(m.TaskPickerInput)
-
-	for _, t := range m.Tasks {
-		// Check if input matches task ID
-		if strings.Contains(strings.ToLower(t.Id), input) {
-			matches = append(matches, t)
-			continue
-		}
-
-		// Check if input matches any alias
-		for _, alias := range t.Aliases {
-			if strings.Contains(strings.ToLower(alias), input) {
-				matches = append(matches, t)
-				break
-			}
-		}
-	}
-
+	// Ids and aliases rank above descriptions, summaries and commands; a task whose id or
+	// alias is the input goes first
+	matches, reasons := rankTaskMatches(m.AllTasks, m.TaskPickerSources, m.TaskPickerInput)
 	m.TaskPickerMatches = matches
+	m.TaskPickerReasons = reasons
 
 	// Reset selected index if out of bounds
 	if m.TaskPickerSelected >= len(matches) {
@@ -456,49 +1060,46 @@ func (m Model) executeNextSelectedTask(index int) (Model, tea.Cmd) {
 	selectedTask := m.SelectedTasks[index]
 	m.CurrentBatchTaskIndex++
 	m.AppendAppMsg(fmt.Sprintf("Executing task %d/%d: %s\n\n", index+1, len(m.SelectedTasks), selectedTask.Id))
-	m.TasksLoading = true
 
 	// Create a command that will execute the current task and then execute the next task
-	return m, func() tea.Msg {
-		task.ExecuteTask(selectedTask.Id, m.MessageBus)
-		return TickMessage{}
-	}
+	return m, m.runTask(selectedTask.Id)
 }
 
 // RefreshTaskList refreshes the task list
 func (m *Model) RefreshTaskList() tea.Cmd {
-	m.Tasks = []task.Task{}
-	m.TasksLoading = true
+	// the current tasks stay listed until the new listing starts arriving; the cached tasks are
+	// only replaced once the whole listing is in, and don't hold up runs meanwhile
+	if m.cachedTasks == nil {
+		m.listing = true
+		m.listedTasks = nil
+		m.TasksLoading = true
+	}
 	m.AppendAppMsg("\nRefreshing task list\n")
-	return func() tea.Msg {
-		task.ListAllJson(m.MessageBus)
-		return TickMessage{}
+	m.listDiagnostics = nil
+	bus, provider, env := m.MessageBus, m.Config.Provider, ExecEnvironment(m.Config)
+	list := func() tea.Msg {
+		start := time.Now()
+		if provider == task.ProviderDemo {
+			task.ListDemoJson(bus)
+		} else {
+			task.ListAllJson(env, bus)
+		}
+		slog.Debug("task list fetched", "provider", provider, "took", time.Since(start))
+		return listFetchedMsg{}
 	}
+	return tea.Batch(list, m.lintTaskfiles(), m.loadCIJobs())
 }
 
 // HandleWindowResize handles window resize events
 func (m *Model) HandleWindowResize(width, height int) {
 	m.Width = width
-	m.Height = height
-
-	tableWidth := int(float64(m.Width) * 0.4)
-	viewportWidth := m.Width - tableWidth - 4
-
-	m.Table.SetWidth(tableWidth)
-	m.Table.SetHeight(m.Height - 4)
+	m.Height = m.inlineHeight(height)
 
-	m.Viewport.Width = viewportWidth
-	m.Viewport.Height = m.Height - 4
+	m.resizePanels()
 
 	// Resize help viewport if needed
 	if m.State == StateHelpOverlay {
-		overlayWidth := int(float64(m.Width) * 0.7)
-		overlayHeight := int(float64(m.Height) * 0.7)
-		contentWidth := overlayWidth - 6    // 6 = 2*2 padding + 2 border
-		viewportHeight := overlayHeight - 6 // Account for padding and borders
-
-		m.HelpViewport.Width = contentWidth
-		m.HelpViewport.Height = viewportHeight
+		m.refreshHelpContent()
 	}
 }
 
@@ -509,12 +1110,117 @@ func (m *Model) ExecuteSelectedTask() tea.Cmd {
 	}
 
 	selectedIndex := m.Table.Cursor()
-	selectedTask := m.Tasks[selectedIndex]
-	m.AppendAppMsg(fmt.Sprintf("Executing task: %s\n\n", selectedTask.Id))
-	m.TasksLoading = true
+	return m.executeTask(m.Tasks[selectedIndex])
+}
+
+// executeTask starts a single task run
+func (m *Model) executeTask(selectedTask task.Task) tea.Cmd {
+	msg := "Executing task: " + selectedTask.Id
+	if len(selectedTask.Vars) > 0 {
+		msg += " " + strings.Join(selectedTask.Vars, " ")
+		m.nextVariant = &selectedTask
+	}
+	if defaults := m.taskDefaults(selectedTask.Id); defaults != "" {
+		msg += " (config: " + defaults + ")"
+	}
+	m.AppendAppMsg(msg + "\n\n")
+	return m.runTask(selectedTask.Id)
+}
 
+// runTask marks a run of taskId as started and returns the command executing it. Tasks with
+// unanswered prompts or required variables wait for them to be entered first.
+func (m *Model) runTask(taskId string) tea.Cmd {
+	if m.State == StateRunPrompt && m.RunPrompt.TaskId != taskId {
+		m.AppendErrorMsg("Skipping " + taskId + " while waiting for input for " + m.RunPrompt.TaskId)
+		return nil
+	}
+	if m.State == StateDangerPrompt && m.DangerPrompt.TaskId != taskId {
+		m.AppendErrorMsg("Skipping " + taskId + " while waiting for confirmation of " + m.DangerPrompt.TaskId)
+		return nil
+	}
+	t, _ := task.Find(m.AllTasks, taskId)
+	if m.refuseCIJob(t) || m.refusePreflight(taskId) {
+		return nil
+	}
+	inputs, ok := m.inputsFor(taskId)
+	if !ok || !m.confirmDangerous(taskId) {
+		return nil
+	}
+	// a loop variant adds the variables of its item to the input
+	if v := m.nextVariant; v != nil && v.Id == taskId {
+		inputs.Vars = slices.Concat(inputs.Vars, v.Vars)
+		m.nextVariant = nil
+	}
+	if m.Remote != nil {
+		return m.sendRemoteRun(taskId, inputs)
+	}
+	m.beginRun(taskId)
+	opts := m.ExecOptions(taskId)
+	opts.Vars = withDefaultVars(opts.Vars, inputs.Vars)
+	opts.AssumeYes = inputs.Confirmed
+	opts.Command = t.Command
+	profile := ""
+	if next := m.nextRunOptions; next != nil && next.TaskId == taskId {
+		opts = m.applyRunOptions(opts, next.Options)
+		profile = next.Options.Profile
+		m.nextRunOptions = nil
+	}
+	m.startRunLog(opts, profile)
+	bus := m.MessageBus
+	changedFilesVar := m.Config.ChangedFilesVar
+
+	// the task runs on its own, reporting its progress on the bus
 	return func() tea.Msg {
-		task.ExecuteTask(selectedTask.Id, m.MessageBus)
-		return TickMessage{}
+		opts.Vars = withChangedFiles(opts.Vars, changedFilesVar, opts.Environment.Dir)
+		task.StartTask(taskId, opts, bus)
+		return nil
+	}
+}
+
+// beginRun resets the state kept about the current run for a new run of taskId
+func (m *Model) beginRun(taskId string) {
+	m.TasksLoading = true
+	m.RunningTaskId = taskId
+	m.runStarted = time.Now()
+	m.runLines = nil
+	m.runStderr = nil
+	m.runCancelled = false
+	m.openFold(taskId, foldRun)
+	if !m.ExecutingBatch || m.CurrentBatchTaskIndex <= 1 {
+		// problems are kept across the runs of a batch
+		m.Problems = nil
+	}
+}
+
+// ExecOptions returns the execution options for a task derived from the user configuration
+func (m Model) ExecOptions(taskId string) task.ExecOptions {
+	return task.ExecOptions{
+		MergeOutput:  m.Config.MergeOutput,
+		Timeout:      m.Config.TimeoutFor(taskId),
+		Retries:      m.Config.RetriesFor(taskId),
+		RetryBackoff: time.Duration(m.Config.RetryBackoff),
+		Provider:     m.Config.Provider,
+		Kubernetes:   task.KubernetesJob(m.Config.Kubernetes),
+		Verbose:      m.Verbosity == VerbosityVerbose,
+		Silent:       m.Verbosity == VerbositySilent,
+		Vars:         m.Config.TaskVars[taskId],
+		Args:         ParseRunArgs(m.Config.TaskArgs[taskId]),
+		Environment:  ExecEnvironment(m.Config),
+		LowPriority:  m.Config.LowPriorityFor(taskId),
+		GracePeriod:  time.Duration(m.Config.CancelGracePeriod),
+		// nothing can answer the prompts of a headless tash
+		PasswordInput: !m.Config.DisablePasswordPrompts && !m.Headless,
+		Redactor:      m.redactor,
+	}
+}
+
+// ExecEnvironment returns the working directory and environment cfg sets for spawned commands
+func ExecEnvironment(cfg config.Config) task.Environment {
+	return task.Environment{
+		Dir:        cfg.WorkDir,
+		Taskfile:   cfg.Taskfile,
+		PathPrefix: cfg.PathPrefix,
+		Allow:      cfg.EnvAllow,
+		Deny:       cfg.EnvDeny,
 	}
 }
@@ -2,8 +2,12 @@ package ui
 
 import (
 	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/Aj4x/tash/internal/config"
 	"github.com/Aj4x/tash/internal/task"
 )
 
@@ -45,12 +49,22 @@ func TestParseTasksJsonWithExtendedInput(t *testing.T) {
 			Desc:    "Displays a cute ASCII art cow with a greeting message, mimicking the 'cowsay' program",
 			Summary: "ASCII cow art",
 			Aliases: []string{"cow", "moo"},
+			Location: &task.Location{
+				Line:     115,
+				Column:   3,
+				Taskfile: "/home/Aj4x/go/src/github.com/Aj4x/tash/examples/Taskfile.yml",
+			},
 		},
 		{
 			Id:      "date-time",
 			Desc:    "Shows the current date and time along with a calendar for the current month",
 			Summary: "Display current date and time",
 			Aliases: []string{"date", "time", "dt"},
+			Location: &task.Location{
+				Line:     47,
+				Column:   3,
+				Taskfile: "/home/Aj4x/go/src/github.com/Aj4x/tash/examples/Taskfile.yml",
+			},
 		},
 	}
 
@@ -71,3 +85,73 @@ func TestParseTasksJsonWithExtendedInput(t *testing.T) {
 		}
 	}
 }
+
+func TestTextWrapUsesCellWidth(t *testing.T) {
+	tests := []struct {
+		in       string
+		width    int
+		expected []string
+	}{
+		{"abcdef", 4, []string{"abcd", "ef"}},
+		{"abcd", 4, []string{"abcd"}},
+		{"日本語です", 4, []string{"日本", "語で", "す"}},
+		{"a日本", 2, []string{"a", "日", "本"}},
+		{"", 4, []string{""}},
+		{"abcdefghij", 3, []string{"abc", "def", "ghi", "j"}},
+		// control characters take no cells
+		{"a\tbc", 2, []string{"a\tb", "c"}},
+	}
+	for _, tt := range tests {
+		if got := TextWrap(tt.in, tt.width); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("TextWrap(%q, %d): expected %q, got %q", tt.in, tt.width, tt.expected, got)
+		}
+	}
+}
+
+// benchmarkLines is the number of output lines the append benchmarks write, as a busy build might
+const benchmarkLines = 100_000
+
+func BenchmarkTextWrap(b *testing.B) {
+	line := strings.Repeat("compiling internal/ui/ui.go ", 6)
+	b.ReportAllocs()
+	for range b.N {
+		for range benchmarkLines {
+			TextWrap(line, 80)
+		}
+	}
+}
+
+func BenchmarkTextWrapWide(b *testing.B) {
+	line := strings.Repeat("テスト出力 ", 20)
+	b.ReportAllocs()
+	for range b.N {
+		for range benchmarkLines {
+			TextWrap(line, 80)
+		}
+	}
+}
+
+func BenchmarkOutputStoreAppend(b *testing.B) {
+	lines := []string{"line of the build output"}
+	b.ReportAllocs()
+	for range b.N {
+		s := newOutputStore()
+		for range benchmarkLines {
+			s.append(lines)
+		}
+	}
+}
+
+// BenchmarkAppendToViewport covers the whole append path. It writes fewer lines, as the viewport
+// measures all of its content whenever it is set, once per appended line.
+func BenchmarkAppendToViewport(b *testing.B) {
+	b.ReportAllocs()
+	for range b.N {
+		m := NewModel(nil, config.Default())
+		m.HandleWindowResize(120, 40)
+		for i := range benchmarkLines / 20 {
+			m.AppendCommandOutput(fmt.Sprintf("line %d of the build output", i))
+		}
+		m.clearOutput()
+	}
+}
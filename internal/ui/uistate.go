@@ -1,5 +1,7 @@
 package ui
 
+import "log/slog"
+
 // UIState represents the different states of the UI
 type UIState int
 
@@ -15,6 +17,85 @@ const (
 
 	// StateHelpOverlay is the state when the help overlay is active
 	StateHelpOverlay
+
+	// StateSchedulePrompt is the state when entering a schedule for the selected task
+	StateSchedulePrompt
+
+	// StateScheduleOverlay is the state when the list of scheduled tasks is shown
+	StateScheduleOverlay
+
+	// StateDiffOverlay is the state when comparing the output of a task's last two runs
+	StateDiffOverlay
+
+	// StateStatsOverlay is the state when the task duration statistics are shown
+	StateStatsOverlay
+
+	// StateKeyBindingsOverlay is the state when the key bindings are listed for rebinding
+	StateKeyBindingsOverlay
+
+	// StateCommandLine is the state when entering an ad-hoc shell command
+	StateCommandLine
+
+	// StateRunPrompt is the state when answering a task's prompts and required variables
+	StateRunPrompt
+
+	// StateRunOptions is the state when choosing the options of a single task run
+	StateRunOptions
+
+	// StateProblemsOverlay is the state when the problems found in the output are listed
+	StateProblemsOverlay
+
+	// StateFailureSummary is the state when the summary of a failed run is shown
+	StateFailureSummary
+
+	// StateVarsOverlay is the state when the variables of a task are inspected
+	StateVarsOverlay
+
+	// StateMetadataEditor is the state when editing the description, summary and aliases of a task
+	StateMetadataEditor
+
+	// StateClonePrompt is the state when entering the name of a copy of a task
+	StateClonePrompt
+
+	// StateDiagnosticsOverlay is the state when the problems found in the Taskfiles are listed
+	StateDiagnosticsOverlay
+
+	// StateRemotePrompt is the state when entering the URL of a remote Taskfile
+	StateRemotePrompt
+
+	// StateTrustPrompt is the state when asked whether to trust a remote Taskfile
+	StateTrustPrompt
+
+	// StateNamespacesOverlay is the state when the namespaces of the tasks are listed to be
+	// hidden or pinned
+	StateNamespacesOverlay
+
+	// StateTour is the state when the onboarding tour is shown
+	StateTour
+
+	// StateAboutOverlay is the state when the versions and paths for a bug report are shown
+	StateAboutOverlay
+
+	// StateDangerPrompt is the state when typing the id of a dangerous task to confirm its run
+	StateDangerPrompt
+
+	// StatePasswordPrompt is the state when entering a password the running task prompts for
+	StatePasswordPrompt
+
+	// StateFanOut is the state when a task runs on a group of hosts, or the group is chosen
+	StateFanOut
+
+	// StateCompose is the state when the services of the compose file are shown
+	StateCompose
+
+	// StatePreflight is the state when the checklist of the pre-flight checks is shown
+	StatePreflight
+
+	// StateSnippets is the state when a snippet is picked to add to the Taskfile
+	StateSnippets
+
+	// StateMakeImport is the state when the tasks imported from the Makefile are previewed
+	StateMakeImport
 )
 
 // String returns a string representation of the UIState
@@ -28,7 +109,204 @@ func (s UIState) String() string {
 		return "DetailsOverlay"
 	case StateHelpOverlay:
 		return "HelpOverlay"
+	case StateSchedulePrompt:
+		return "SchedulePrompt"
+	case StateScheduleOverlay:
+		return "ScheduleOverlay"
+	case StateDiffOverlay:
+		return "DiffOverlay"
+	case StateStatsOverlay:
+		return "StatsOverlay"
+	case StateKeyBindingsOverlay:
+		return "KeyBindingsOverlay"
+	case StateCommandLine:
+		return "CommandLine"
+	case StateRunPrompt:
+		return "RunPrompt"
+	case StateRunOptions:
+		return "RunOptions"
+	case StateProblemsOverlay:
+		return "ProblemsOverlay"
+	case StateFailureSummary:
+		return "FailureSummary"
+	case StateVarsOverlay:
+		return "VarsOverlay"
+	case StateMetadataEditor:
+		return "MetadataEditor"
+	case StateClonePrompt:
+		return "ClonePrompt"
+	case StateDiagnosticsOverlay:
+		return "DiagnosticsOverlay"
+	case StateRemotePrompt:
+		return "RemotePrompt"
+	case StateTrustPrompt:
+		return "TrustPrompt"
+	case StateNamespacesOverlay:
+		return "NamespacesOverlay"
+	case StateTour:
+		return "Tour"
+	case StateAboutOverlay:
+		return "AboutOverlay"
+	case StateDangerPrompt:
+		return "DangerPrompt"
+	case StatePasswordPrompt:
+		return "PasswordPrompt"
+	case StateFanOut:
+		return "FanOut"
+	case StateCompose:
+		return "Compose"
+	case StatePreflight:
+		return "Preflight"
+	case StateSnippets:
+		return "Snippets"
+	case StateMakeImport:
+		return "MakeImport"
 	default:
 		return "Unknown"
 	}
 }
+
+// SetState transitions the UI to a new state, recording the transition in the debug log
+func (m *Model) SetState(s UIState) {
+	if m.State != s {
+		slog.Debug("ui state transition", "from", m.State.String(), "to", s.String())
+		if s == StateNormal {
+			m.announce("Closed " + m.State.Description())
+		} else {
+			m.announce("Opened " + s.Description())
+		}
+	}
+	m.State = s
+}
+
+// HintContexts returns the key binding contexts whose hints are shown in state s
+func (s UIState) HintContexts() []Context {
+	switch s {
+	case StateTaskPicker:
+		return []Context{ContextTaskPicker}
+	case StateDetailsOverlay:
+		return []Context{ContextDetailsOverlay}
+	case StateHelpOverlay:
+		return []Context{ContextHelpOverlay}
+	case StateSchedulePrompt:
+		return []Context{ContextSchedulePrompt}
+	case StateScheduleOverlay:
+		return []Context{ContextSchedule}
+	case StateDiffOverlay:
+		return []Context{ContextDiffOverlay}
+	case StateStatsOverlay:
+		return []Context{ContextStatsOverlay}
+	case StateKeyBindingsOverlay:
+		return []Context{ContextKeyBindings}
+	case StateCommandLine:
+		return []Context{ContextCommandLine}
+	case StateRunPrompt:
+		return []Context{ContextRunPrompt}
+	case StateRunOptions:
+		return []Context{ContextRunOptions}
+	case StateProblemsOverlay:
+		return []Context{ContextProblems}
+	case StateFailureSummary:
+		return []Context{ContextFailureSummary}
+	case StateVarsOverlay:
+		return []Context{ContextVarsOverlay}
+	case StateMetadataEditor:
+		return []Context{ContextMetadataEditor}
+	case StateClonePrompt:
+		return []Context{ContextClonePrompt}
+	case StateDiagnosticsOverlay:
+		return []Context{ContextDiagnostics}
+	case StateRemotePrompt:
+		return []Context{ContextRemotePrompt}
+	case StateTrustPrompt:
+		return []Context{ContextTrustPrompt}
+	case StateNamespacesOverlay:
+		return []Context{ContextNamespaces}
+	case StateTour:
+		return []Context{ContextTour}
+	case StateAboutOverlay:
+		return []Context{ContextAbout}
+	case StateDangerPrompt:
+		return []Context{ContextDangerPrompt}
+	case StatePasswordPrompt:
+		return []Context{ContextPasswordPrompt}
+	case StateFanOut:
+		return []Context{ContextFanOut}
+	case StateCompose:
+		return []Context{ContextCompose}
+	case StatePreflight:
+		return []Context{ContextPreflight}
+	case StateSnippets:
+		return []Context{ContextSnippets}
+	case StateMakeImport:
+		return []Context{ContextMakeImport}
+	default: // StateNormal
+		return []Context{ContextGlobal}
+	}
+}
+
+// Description returns a readable name for the state, used for announcements in plain mode
+func (s UIState) Description() string {
+	switch s {
+	case StateTaskPicker:
+		return "task picker"
+	case StateDetailsOverlay:
+		return "task details"
+	case StateHelpOverlay:
+		return "help"
+	case StateSchedulePrompt:
+		return "schedule prompt"
+	case StateScheduleOverlay:
+		return "scheduled tasks"
+	case StateDiffOverlay:
+		return "run comparison"
+	case StateStatsOverlay:
+		return "duration statistics"
+	case StateKeyBindingsOverlay:
+		return "key bindings"
+	case StateCommandLine:
+		return "command line"
+	case StateRunPrompt:
+		return "task input"
+	case StateRunOptions:
+		return "run options"
+	case StateProblemsOverlay:
+		return "problems"
+	case StateFailureSummary:
+		return "failure summary"
+	case StateVarsOverlay:
+		return "task variables"
+	case StateMetadataEditor:
+		return "task metadata"
+	case StateClonePrompt:
+		return "clone prompt"
+	case StateDiagnosticsOverlay:
+		return "Taskfile diagnostics"
+	case StateRemotePrompt:
+		return "remote Taskfile prompt"
+	case StateTrustPrompt:
+		return "remote Taskfile trust prompt"
+	case StateNamespacesOverlay:
+		return "namespaces"
+	case StateTour:
+		return "tour"
+	case StateAboutOverlay:
+		return "about"
+	case StateDangerPrompt:
+		return "dangerous task confirmation"
+	case StatePasswordPrompt:
+		return "password prompt"
+	case StateFanOut:
+		return "host group runs"
+	case StateCompose:
+		return "compose services"
+	case StatePreflight:
+		return "pre-flight checklist"
+	case StateSnippets:
+		return "snippet picker"
+	case StateMakeImport:
+		return "Makefile import preview"
+	default:
+		return "main view"
+	}
+}
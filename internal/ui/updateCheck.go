@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Aj4x/tash/internal/update"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// updateMsg carries a release of tash newer than the running build
+type updateMsg struct {
+	Release update.Release
+}
+
+// UseUpdateChecker looks up, when tash starts, whether a newer release is out with checker,
+// unless the config opts out of the check
+func (m *Model) UseUpdateChecker(checker update.Checker) {
+	if m.Config.DisableUpdateCheck {
+		return
+	}
+	m.UpdateChecker = &checker
+}
+
+// checkForUpdate looks up the latest release in the background, reporting it only when it is
+// newer than the running build; failures are only logged, the check is a courtesy
+func (m Model) checkForUpdate() tea.Cmd {
+	if m.UpdateChecker == nil || m.Headless {
+		return nil
+	}
+	checker := *m.UpdateChecker
+	current := tashVersion()
+	return func() tea.Msg {
+		release, err := checker.Latest(context.Background(), time.Now())
+		if err != nil {
+			slog.Debug("update check failed", "error", err)
+			return nil
+		}
+		if !update.Newer(current, release.Version) {
+			return nil
+		}
+		return updateMsg{Release: release}
+	}
+}
+
+// updateNotice describes the newer release for the status bar
+func updateNotice(r update.Release) string {
+	notice := "tash " + r.Version + " is available"
+	if r.URL != "" {
+		notice += ": " + r.URL
+	}
+	return notice
+}
@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/update"
+)
+
+func TestUpdateCheckShowsANoticeForNewerReleases(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"tag_name": "v99.0.0", "html_url": "https://example.com/v99.0.0"}`))
+	}))
+	defer server.Close()
+	checker := update.Checker{URL: server.URL, CacheFile: filepath.Join(t.TempDir(), update.FileName)}
+
+	cfg := config.Default()
+	cfg.DisableUpdateCheck = true
+	m := NewModel(nil, cfg)
+	m.UseUpdateChecker(checker)
+	if m.checkForUpdate() != nil {
+		t.Error("Expected no check when the config opts out")
+	}
+
+	m = NewModel(nil, config.Default())
+	m.HandleWindowResize(160, 40)
+	m.Initialised = true
+	m.UseUpdateChecker(checker)
+	cmd := m.checkForUpdate()
+	if cmd == nil {
+		t.Fatal("Expected the check to run")
+	}
+	// Test binaries have no release version, and builds of unknown version are never behind
+	if msg := cmd(); msg != nil {
+		t.Errorf("Expected no notice for a build of unknown version, got %v", msg)
+	}
+
+	updated, _ := m.Update(updateMsg{Release: update.Release{Version: "v99.0.0", URL: "https://example.com/v99.0.0"}})
+	m = updated.(Model)
+	if view := m.View(); !strings.Contains(view, "tash v99.0.0 is available: https://example.com/v99.0.0") {
+		t.Errorf("Expected the notice in the status bar, got:\n%s", view)
+	}
+}
@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Aj4x/tash/internal/task"
+	"github.com/mattn/go-runewidth"
+)
+
+// varsNameWidth caps the width of the name column of the vars overlay
+const varsNameWidth = 28
+
+// openVarsOverlay lists the variables t sees, with their values and where they come from. The
+// vars of a loop variant and the answers given to the task's run prompt are CLI overrides, as
+// tash passes them on the task command line.
+func (m *Model) openVarsOverlay(t task.Task) {
+	m.VarsViewport.Width = int(float64(m.Width)*0.9) - 6
+	m.VarsViewport.Height = max(int(float64(m.Height)*0.9)-8, 1)
+	m.VarsViewport.SetContent(m.renderVarsContent(t))
+	m.VarsViewport.GotoTop()
+	m.SetState(StateVarsOverlay)
+}
+
+// renderVarsContent renders the variables of t as rows of name, value and source
+func (m Model) renderVarsContent(t task.Task) string {
+	if m.Config.Provider == task.ProviderDemo {
+		return "Demo tasks are not defined in a Taskfile"
+	}
+	tf, err := loadTaskfile(t)
+	if err != nil {
+		return "Unable to read the Taskfile: " + err.Error()
+	}
+	bindings, err := tf.Bindings(t.Id, m.runVars(t))
+	if err != nil {
+		return "Unable to resolve the variables: " + err.Error()
+	}
+
+	sources := make([]string, len(bindings))
+	nameWidth, sourceWidth := 0, 0
+	for i, b := range bindings {
+		sources[i] = string(b.Source)
+		if b.Env {
+			sources[i] += " (env)"
+		}
+		nameWidth = max(nameWidth, min(runewidth.StringWidth(b.Name), varsNameWidth))
+		sourceWidth = max(sourceWidth, len(sources[i]))
+	}
+	valueWidth := max(m.VarsViewport.Width-nameWidth-sourceWidth-4, 10)
+	cell := func(text string, width int) string {
+		return runewidth.FillRight(runewidth.Truncate(text, width, "…"), width)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(TableHeaderStyle.Render(cell("NAME", nameWidth)+"  "+cell("VALUE", valueWidth)+"  SOURCE") + "\n")
+	for i, b := range bindings {
+		value := strings.ReplaceAll(b.Value, "\n", "↵")
+		fmt.Fprintf(&sb, "%s  %s  %s\n", cell(b.Name, nameWidth), cell(value, valueWidth), HelpStyle.Render(sources[i]))
+	}
+	return sb.String()
+}
+
+// RenderVarsOverlay renders the variables of the selected task
+func RenderVarsOverlay(m *Model) string {
+	overlayWidth := int(float64(m.Width) * 0.9)
+	title := "Variables"
+	if m.SelectedTask != nil {
+		title += ": " + m.SelectedTask.Id
+	}
+	content := TaskPickerTitleStyle.Render(title) + "\n"
+	content += HelpStyle.Render("template variables, then the environment of the task's process (env)") + "\n\n"
+	content += m.VarsViewport.View()
+
+	overlay := GeneralOverlayStyle(overlayWidth).Render(content)
+
+	return placeOverlay(m.Width, m.Height, overlay)
+}
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/task"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestVarsOverlayAttributesSources(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Taskfile.yml")
+	content := `version: '3'
+vars:
+  VERSION: 1.0.0
+tasks:
+  release:
+    vars:
+      CHANNEL: beta
+    env:
+      TARGET: prod
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m := NewModel(nil, config.Default())
+	m.Width, m.Height = 160, 60
+	release := task.Task{Id: "release", Location: &task.Location{Taskfile: path, Line: 4}}
+	m.SelectedTask = &release
+	m.RunInputs = map[string]runInputs{"release": {Vars: []string{"CHANNEL=stable"}}}
+	m.SetState(StateDetailsOverlay)
+
+	updated, _ := m.handleDetailsOverlayKey(runes("v"))
+	m = updated.(Model)
+	if m.State != StateVarsOverlay {
+		t.Fatalf("Expected the vars overlay, got state %s", m.State)
+	}
+	rows := map[string]string{}
+	for _, line := range strings.Split(m.VarsViewport.View(), "\n") {
+		if fields := strings.Fields(line); len(fields) >= 3 {
+			rows[fields[0]] = strings.Join(fields[1:], " ")
+		}
+	}
+	for name, want := range map[string]string{
+		"VERSION": "1.0.0 global vars",
+		"CHANNEL": "stable CLI override",
+		"TARGET":  "prod task env (env)",
+	} {
+		if rows[name] != want {
+			t.Errorf("Expected %s to be %q, got %q", name, want, rows[name])
+		}
+	}
+
+	updated, _ = m.handleVarsOverlayKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if m = updated.(Model); m.State != StateDetailsOverlay {
+		t.Errorf("Expected esc to go back to the details, got state %s", m.State)
+	}
+}
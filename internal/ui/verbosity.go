@@ -0,0 +1,47 @@
+package ui
+
+// Verbosity selects whether tasks run with --verbose, --silent or neither
+type Verbosity int
+
+const (
+	VerbosityNormal Verbosity = iota
+	VerbosityVerbose
+	VerbositySilent
+)
+
+// ParseVerbosity returns the verbosity named s, defaulting to normal
+func ParseVerbosity(s string) Verbosity {
+	switch s {
+	case "verbose":
+		return VerbosityVerbose
+	case "silent":
+		return VerbositySilent
+	default:
+		return VerbosityNormal
+	}
+}
+
+// String returns the name of the verbosity, as used in the config file
+func (v Verbosity) String() string {
+	switch v {
+	case VerbosityVerbose:
+		return "verbose"
+	case VerbositySilent:
+		return "silent"
+	default:
+		return "normal"
+	}
+}
+
+// Next returns the verbosity that follows v when cycling: normal, verbose, silent
+func (v Verbosity) Next() Verbosity {
+	return (v + 1) % 3
+}
+
+// Flag returns the task flag applying the verbosity, or an empty string for normal runs
+func (v Verbosity) Flag() string {
+	if v == VerbosityNormal {
+		return ""
+	}
+	return "--" + v.String()
+}
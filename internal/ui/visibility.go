@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"path"
+	"slices"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/namespaces"
+	"github.com/Aj4x/tash/internal/task"
+)
+
+// isHiddenTask reports whether t is hidden from the task list when hidden tasks are not shown:
+// internal tasks, tasks without a description and tasks matching an ignore pattern
+func isHiddenTask(t task.Task, cfg config.Config) bool {
+	if t.Internal || t.Desc == "" {
+		return true
+	}
+	for _, pattern := range cfg.IgnoreTasks {
+		if ok, _ := path.Match(pattern, t.Id); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// showTasks replaces the listed tasks, followed by the CI jobs, keeping the cursor on the
+// selected task when it is still listed
+func (m *Model) showTasks(tasks []task.Task) {
+	var selected task.Task
+	if c := m.Table.Cursor(); c >= 0 && c < len(m.Tasks) {
+		selected = m.Tasks[c]
+	}
+	m.AllTasks = append(slices.Clip(tasks), m.ciJobs...)
+	m.variants = nil
+	if !m.Config.HideLoopVariants && m.Config.Provider != task.ProviderDemo {
+		m.variants = loopVariants(tasks)
+	}
+	m.applyTaskFilter()
+	m.UpdateTaskTable()
+	for i, t := range m.Tasks {
+		if sameRow(t, selected) {
+			m.Table.SetCursor(i)
+			break
+		}
+	}
+}
+
+// applyTaskFilter sets the tasks shown in the table from all listed tasks, leaving out hidden
+// namespaces and keeping the tasks with the chosen label, grouping them by Taskfile or label when
+// enabled, pinned namespaces first, and listing the loop variants of each task below it
+func (m *Model) applyTaskFilter() {
+	m.HiddenCount = 0
+	m.Tasks = make([]task.Task, 0, len(m.AllTasks))
+	for _, t := range m.AllTasks {
+		_, hiddenNamespace := namespaces.Match(m.Namespaces.Hidden, t.Namespace())
+		if hiddenNamespace || (!m.ShowHidden && isHiddenTask(t, m.Config)) {
+			m.HiddenCount++
+			continue
+		}
+		m.Tasks = append(m.Tasks, t)
+	}
+	if m.LabelFilter != "" {
+		m.Tasks = filterByLabel(m.Tasks, m.Config, m.LabelFilter)
+	}
+	if m.GroupIncludes {
+		m.Tasks = task.GroupByTaskfile(m.Tasks)
+	}
+	if m.GroupLabels {
+		m.Tasks = groupByLabel(m.Tasks, m.Config)
+	}
+	if len(m.Namespaces.Pinned) > 0 {
+		m.Tasks = pinNamespaces(m.Tasks, m.Namespaces.Pinned)
+	}
+	m.Tasks = m.withVariants(m.Tasks)
+}
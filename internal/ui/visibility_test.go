@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/task"
+)
+
+func TestHiddenTasksAreFiltered(t *testing.T) {
+	cfg := config.Default()
+	cfg.HideTasks = true
+	cfg.IgnoreTasks = []string{"ci:*"}
+	m := NewModel(nil, cfg)
+	m.AllTasks = []task.Task{
+		{Id: "build", Desc: "Build"},
+		{Id: "helper"},
+		{Id: "setup", Desc: "Setup", Internal: true},
+		{Id: "ci:lint", Desc: "Lint in CI"},
+	}
+
+	m.applyTaskFilter()
+	if len(m.Tasks) != 1 || m.Tasks[0].Id != "build" {
+		t.Errorf("Expected only build to be shown, got %+v", m.Tasks)
+	}
+	if m.HiddenCount != 3 {
+		t.Errorf("Expected 3 hidden tasks, got %d", m.HiddenCount)
+	}
+
+	m.ShowHidden = true
+	m.applyTaskFilter()
+	if len(m.Tasks) != 4 || m.HiddenCount != 0 {
+		t.Errorf("Expected all tasks to be shown, got %d with %d hidden", len(m.Tasks), m.HiddenCount)
+	}
+}
+
+func TestGroupIncludesOrdersTasksByTaskfile(t *testing.T) {
+	m := NewModel(nil, config.Default())
+	m.AllTasks = []task.Task{
+		{Id: "docs:build", Location: &task.Location{Taskfile: "/p/docs/Taskfile.yml"}},
+		{Id: "build", Location: &task.Location{Taskfile: "/p/Taskfile.yml"}},
+		{Id: "test", Location: &task.Location{Taskfile: "/p/Taskfile.yml"}},
+	}
+	m.applyTaskFilter()
+	m.UpdateTaskTable()
+	if columns := m.Table.Columns(); len(columns) != 4 || columns[3].Title != "Taskfile" {
+		t.Fatalf("Expected a Taskfile column, got %+v", columns)
+	}
+	if row := m.Table.Rows()[0]; row[3] != "docs/Taskfile.yml" {
+		t.Errorf("Expected docs/Taskfile.yml for docs:build, got %q", row[3])
+	}
+
+	m = pressKeys(m, runes("I"))
+	if !m.GroupIncludes || m.Tasks[0].Id != "build" || m.Tasks[2].Id != "docs:build" {
+		t.Errorf("Expected root tasks first when grouped, got %+v", m.Tasks)
+	}
+	if row := m.Table.Rows()[0]; row[0] != "build" || row[3] != "" {
+		t.Errorf("Expected build from the root Taskfile first, got %v", row)
+	}
+}
+
+func TestTaskfileColumnOnlyShownWithIncludes(t *testing.T) {
+	m := NewModel(nil, config.Default())
+	m.AllTasks = []task.Task{{Id: "build", Location: &task.Location{Taskfile: "/p/Taskfile.yml"}}}
+	m.applyTaskFilter()
+	m.UpdateTaskTable()
+	if columns := m.Table.Columns(); len(columns) != 3 {
+		t.Errorf("Expected no Taskfile column without includes, got %+v", columns)
+	}
+}
+
+func TestTaskListLoadsProgressively(t *testing.T) {
+	m := newNavigationModel(3)
+	m.AllTasks = m.Tasks
+	m.Table.SetCursor(2)
+
+	m.RefreshTaskList()
+	if len(m.Tasks) != 3 {
+		t.Fatalf("Expected the tasks to stay listed until the new listing arrives, got %d", len(m.Tasks))
+	}
+
+	m, _ = m.handleBusMessage(task.TypeTaskListPartial.Message().SetTasks([]task.Task{{Id: "task-0"}}))
+	if len(m.Tasks) != 1 || m.Table.Cursor() != 0 {
+		t.Fatalf("Expected the first batch to replace the list, got %d tasks", len(m.Tasks))
+	}
+	m, _ = m.handleBusMessage(task.TypeTaskListPartial.Message().SetTasks([]task.Task{{Id: "task-1"}, {Id: "task-2"}}))
+	if len(m.Tasks) != 3 {
+		t.Fatalf("Expected the second batch to be added, got %d tasks", len(m.Tasks))
+	}
+
+	m.Table.SetCursor(1)
+	listing := `{"tasks": [{"name": "task-new"}, {"name": "task-0"}, {"name": "task-1"}, {"name": "task-2"}]}`
+	m, _ = m.handleBusMessage(task.TypeTaskJSON.Message().SetOutput(listing))
+	if len(m.Tasks) != 4 || m.listing || m.TasksLoading {
+		t.Fatalf("Expected the complete listing, got %d tasks", len(m.Tasks))
+	}
+	if got := m.Tasks[m.Table.Cursor()].Id; got != "task-1" {
+		t.Errorf("Expected the cursor to stay on task-1, got %s", got)
+	}
+
+	// batches of a listing that already finished are ignored
+	m, _ = m.handleBusMessage(task.TypeTaskListPartial.Message().SetTasks([]task.Task{{Id: "late"}}))
+	if len(m.Tasks) != 4 {
+		t.Errorf("Expected a late batch to be ignored, got %d tasks", len(m.Tasks))
+	}
+}
+
+func TestLabelsGroupAndFilterTasks(t *testing.T) {
+	cfg := config.Default()
+	cfg.Labels = map[string][]string{"release": {"release:*"}, "build": {"build", "docs:*"}}
+	m := NewModel(nil, cfg)
+	m.HandleWindowResize(120, 40)
+	m.AllTasks = []task.Task{{Id: "lint"}, {Id: "release:notes"}, {Id: "build"}, {Id: "docs:site"}}
+	m.applyTaskFilter()
+	m.UpdateTaskTable()
+	if columns := m.Table.Columns(); len(columns) != 4 || columns[3].Title != "Labels" {
+		t.Fatalf("Expected a Labels column, got %+v", columns)
+	}
+
+	m = pressKeys(m, runes("#"))
+	var ids []string
+	for _, t := range m.Tasks {
+		ids = append(ids, t.Id)
+	}
+	if strings.Join(ids, " ") != "build docs:site release:notes lint" {
+		t.Errorf("Expected the tasks grouped by label with unlabelled ones last, got %v", ids)
+	}
+
+	m = pressKeys(m, runes("l"))
+	if m.LabelFilter != "build" || len(m.Tasks) != 2 {
+		t.Errorf("Expected the build tasks, got %q with %+v", m.LabelFilter, m.Tasks)
+	}
+	m = pressKeys(m, runes("l"), runes("l"))
+	if m.LabelFilter != "" || len(m.Tasks) != 4 {
+		t.Errorf("Expected every task after the last label, got %q with %d tasks", m.LabelFilter, len(m.Tasks))
+	}
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Aj4x/tash/internal/task"
+	"github.com/Aj4x/tash/internal/watch"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// watchersStartedMsg carries the function that stops the running watchers
+type watchersStartedMsg struct {
+	stop context.CancelFunc
+}
+
+// startWatchers returns a command that starts a watcher goroutine for each configured watcher.
+// Triggers are published on the message bus as TypeWatchTrigger messages.
+func (m Model) startWatchers() tea.Cmd {
+	if len(m.Config.Watchers) == 0 {
+		return nil
+	}
+	watchers := m.Config.Watchers
+	bus := m.MessageBus
+	return func() tea.Msg {
+		ctx, cancel := context.WithCancel(context.Background())
+		for _, wc := range watchers {
+			w := watch.Watcher{
+				Root:     ".",
+				Patterns: wc.Patterns,
+				Debounce: time.Duration(wc.Debounce),
+			}
+			taskId := wc.Task
+			go w.Run(ctx, func(paths []string) {
+				bus.Publish(task.TypeWatchTrigger.Message().
+					SetTaskId(taskId).
+					SetOutput(strings.Join(paths, ", ")).
+					TopicMessage())
+			})
+		}
+		return watchersStartedMsg{stop: cancel}
+	}
+}
+
+// toggleWatchers enables or disables the configured file watchers
+func (m Model) toggleWatchers() (Model, tea.Cmd) {
+	if len(m.Config.Watchers) == 0 {
+		m.AppendAppMsg("No file watchers configured\n")
+		return m, nil
+	}
+	if m.stopWatchers != nil {
+		m.stopWatchers()
+		m.stopWatchers = nil
+		m.PendingWatchRuns = nil
+		m.AppendAppMsg("File watchers disabled\n")
+		return m, nil
+	}
+	m.AppendAppMsg(fmt.Sprintf("File watchers enabled (%d)\n", len(m.Config.Watchers)))
+	return m, m.startWatchers()
+}
+
+// handleWatchTriggerMsg queues the triggered task; it runs as soon as no other task is running
+func (m Model) handleWatchTriggerMsg(msg task.Message) (Model, tea.Cmd) {
+	if m.stopWatchers == nil {
+		// the watchers were disabled after the change was detected
+		return m, nil
+	}
+	taskId := msg.TaskId()
+	m.AppendAppMsg(fmt.Sprintf("Change detected (%s), queueing task '%s'\n", msg.Output(), taskId))
+	for _, id := range m.PendingWatchRuns {
+		if id == taskId {
+			return m, nil
+		}
+	}
+	m.PendingWatchRuns = append(append([]string(nil), m.PendingWatchRuns...), taskId)
+	return m.runPendingWatchRuns()
+}
+
+// runPendingWatchRuns starts the oldest queued watcher run if tash is idle
+func (m Model) runPendingWatchRuns() (Model, tea.Cmd) {
+	if len(m.PendingWatchRuns) == 0 || m.TasksLoading || m.ExecutingBatch || m.Repeat.Active {
+		return m, nil
+	}
+	taskId := m.PendingWatchRuns[0]
+	m.PendingWatchRuns = m.PendingWatchRuns[1:]
+	return m, m.executeTask(task.Task{Id: taskId})
+}
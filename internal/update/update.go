@@ -0,0 +1,138 @@
+// Package update checks whether a newer tash release is out, so users of old builds learn about
+// fixes. The latest release is cached in the data directory and looked up at most once a day.
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Aj4x/tash/internal/task"
+)
+
+// DefaultURL is the GitHub API endpoint describing the latest tash release
+const DefaultURL = "https://api.github.com/repos/Aj4x/tash/releases/latest"
+
+// FileName is the name of the file inside the data directory caching the latest release
+const FileName = "latest-release.json"
+
+// Interval is how long a looked up release is used before it is looked up again
+const Interval = 24 * time.Hour
+
+// requestTimeout bounds the lookup, which must never hold up anything else
+const requestTimeout = 5 * time.Second
+
+// Release is a published release of tash
+type Release struct {
+	Version string `json:"tag_name"`
+	URL     string `json:"html_url"`
+}
+
+// Checker looks up the latest release at URL, caching it in CacheFile. A Checker with an empty
+// CacheFile looks the release up on every check.
+type Checker struct {
+	URL       string
+	CacheFile string
+	Client    *http.Client
+}
+
+// NewChecker returns a checker of the tash releases caching them in the data directory dataDir
+func NewChecker(dataDir string) Checker {
+	return Checker{URL: DefaultURL, CacheFile: filepath.Join(dataDir, FileName)}
+}
+
+// cached is the last looked up release, with when it was looked up
+type cached struct {
+	Release Release   `json:"release"`
+	Checked time.Time `json:"checked"`
+}
+
+// Latest returns the latest release, from the cache when it was looked up within Interval of
+// now. Failed lookups are cached too, so an offline machine doesn't try again on every start.
+func (c Checker) Latest(ctx context.Context, now time.Time) (Release, error) {
+	last, ok := c.load()
+	if ok && now.Sub(last.Checked) < Interval {
+		return last.Release, nil
+	}
+	release, err := c.fetch(ctx)
+	if err != nil {
+		c.save(cached{Release: last.Release, Checked: now})
+		return Release{}, err
+	}
+	c.save(cached{Release: release, Checked: now})
+	return release, nil
+}
+
+// fetch looks the latest release up
+func (c Checker) fetch(ctx context.Context) (Release, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.URL, nil)
+	if err != nil {
+		return Release{}, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Release{}, fmt.Errorf("unable to look up the latest release: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Release{}, fmt.Errorf("unable to look up the latest release: %s", resp.Status)
+	}
+	var release Release
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return Release{}, fmt.Errorf("unable to parse the latest release: %w", err)
+	}
+	return release, nil
+}
+
+// load returns the cached release
+func (c Checker) load() (cached, bool) {
+	if c.CacheFile == "" {
+		return cached{}, false
+	}
+	data, err := os.ReadFile(c.CacheFile)
+	if err != nil {
+		return cached{}, false
+	}
+	var last cached
+	if err := json.Unmarshal(data, &last); err != nil {
+		return cached{}, false
+	}
+	return last, true
+}
+
+// save caches a looked up release; caching is best effort
+func (c Checker) save(last cached) {
+	if c.CacheFile == "" {
+		return
+	}
+	data, err := json.Marshal(last)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(c.CacheFile), 0o755); err != nil {
+		return
+	}
+	_ = os.WriteFile(c.CacheFile, data, 0o644)
+}
+
+// Newer reports whether the release latest is later than the version current. Builds whose
+// version isn't known, such as those built from a checkout, are never behind.
+func Newer(current, latest string) bool {
+	c, ok := task.ParseVersion(current)
+	if !ok {
+		return false
+	}
+	l, ok := task.ParseVersion(latest)
+	return ok && c.Less(l)
+}
@@ -0,0 +1,62 @@
+package update
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLatestCachesTheReleaseForADay(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests > 2 {
+			http.Error(w, "rate limited", http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`{"tag_name": "v1.4.0", "html_url": "https://example.com/v1.4.0"}`))
+	}))
+	defer server.Close()
+
+	c := Checker{URL: server.URL, CacheFile: filepath.Join(t.TempDir(), FileName)}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for _, at := range []time.Time{now, now.Add(time.Hour)} {
+		release, err := c.Latest(context.Background(), at)
+		if err != nil || release.Version != "v1.4.0" || release.URL != "https://example.com/v1.4.0" {
+			t.Fatalf("Latest() = %+v, %v", release, err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Expected the cached release to be used within a day, got %d requests", requests)
+	}
+
+	if _, err := c.Latest(context.Background(), now.Add(25*time.Hour)); err != nil || requests != 2 {
+		t.Errorf("Expected the release to be looked up again after a day, got %d requests, %v", requests, err)
+	}
+	if _, err := c.Latest(context.Background(), now.Add(50*time.Hour)); err == nil {
+		t.Error("Expected the failed lookup to be reported")
+	}
+	if _, err := c.Latest(context.Background(), now.Add(51*time.Hour)); err != nil || requests != 3 {
+		t.Errorf("Expected a failed lookup not to be retried within a day, got %d requests, %v", requests, err)
+	}
+}
+
+func TestNewer(t *testing.T) {
+	for _, tt := range []struct {
+		current, latest string
+		want            bool
+	}{
+		{"v1.2.0", "v1.3.0", true},
+		{"v1.2.0", "v1.2.0", false},
+		{"v1.10.0", "v1.9.1", false},
+		{"(devel)", "v1.3.0", false},
+		{"v1.2.0", "", false},
+	} {
+		if got := Newer(tt.current, tt.latest); got != tt.want {
+			t.Errorf("Newer(%q, %q) = %v, want %v", tt.current, tt.latest, got, tt.want)
+		}
+	}
+}
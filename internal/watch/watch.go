@@ -0,0 +1,165 @@
+package watch
+
+import (
+	"context"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Aj4x/tash/internal/git"
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultDebounce is how long changes must settle before the watcher fires
+const DefaultDebounce = 300 * time.Millisecond
+
+// VendoredDirs are the names of the directories holding dependencies, which aren't watched
+var VendoredDirs = []string{"node_modules", "vendor", "bower_components", "__pycache__"}
+
+// Watcher reports when files of a directory tree matching its patterns are created, modified or
+// removed, using the notifications of the OS. Every directory of the tree is watched, except
+// hidden, vendored and git-ignored directories that no pattern names.
+type Watcher struct {
+	Root     string
+	Patterns []string
+	Debounce time.Duration
+}
+
+// tree watches the directories of a watcher's tree
+type tree struct {
+	w       Watcher
+	fs      *fsnotify.Watcher
+	ignored map[string]bool // Directories git ignores, relative to the root
+}
+
+// rel returns the slash-separated path of p relative to the root
+func (t tree) rel(p string) string {
+	rel, err := filepath.Rel(t.w.Root, p)
+	if err != nil {
+		return filepath.ToSlash(p)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// matches reports whether the file at rel matches a pattern
+func (t tree) matches(rel string) bool {
+	return slices.ContainsFunc(t.w.Patterns, func(pattern string) bool { return Match(pattern, rel) })
+}
+
+// skipped reports whether the directory at rel isn't watched: a hidden, vendored or ignored
+// directory, unless a pattern names it, e.g. "vendor/**"
+func (t tree) skipped(rel string) bool {
+	if rel == "." {
+		return false
+	}
+	name := path.Base(rel)
+	if !strings.HasPrefix(name, ".") && !slices.Contains(VendoredDirs, name) && !t.ignored[rel] {
+		return false
+	}
+	return !slices.ContainsFunc(t.w.Patterns, func(pattern string) bool {
+		return strings.HasPrefix(pattern, rel+"/")
+	})
+}
+
+// add watches the directory dir and those below it, returning the matching files found in them
+func (t tree) add(dir string) []string {
+	var files []string
+	_ = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel := t.rel(p)
+		if !d.IsDir() {
+			if t.matches(rel) {
+				files = append(files, rel)
+			}
+			return nil
+		}
+		if t.skipped(rel) {
+			return filepath.SkipDir
+		}
+		if err := t.fs.Add(p); err != nil {
+			slog.Warn("unable to watch directory", "dir", p, "error", err)
+		}
+		return nil
+	})
+	return files
+}
+
+// changed returns the matching files an event changed: the file itself, or those of a directory
+// created with files already in it
+func (t tree) changed(ev fsnotify.Event) []string {
+	rel := t.rel(ev.Name)
+	if ev.Has(fsnotify.Create) {
+		if info, err := os.Stat(ev.Name); err == nil && info.IsDir() {
+			if t.skipped(rel) {
+				return nil
+			}
+			return t.add(ev.Name)
+		}
+	}
+	if ev.Op&(fsnotify.Create|fsnotify.Write|fsnotify.Remove|fsnotify.Rename) != 0 && t.matches(rel) {
+		return []string{rel}
+	}
+	return nil
+}
+
+// Run watches until ctx is cancelled, calling onChange with the changed paths once changes have
+// stopped arriving for the debounce period
+func (w Watcher) Run(ctx context.Context, onChange func(paths []string)) {
+	debounce := w.Debounce
+	if debounce <= 0 {
+		debounce = DefaultDebounce
+	}
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		slog.Warn("unable to watch files", "root", w.Root, "error", err)
+		return
+	}
+	defer fsw.Close()
+	t := tree{w: w, fs: fsw, ignored: map[string]bool{}}
+	// outside a git repository nothing is ignored
+	if dirs, err := git.IgnoredDirs(ctx, w.Root); err == nil {
+		for _, d := range dirs {
+			t.ignored[d] = true
+		}
+	}
+	t.add(w.Root)
+
+	settled := time.NewTimer(debounce)
+	settled.Stop()
+	pending := map[string]bool{}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-fsw.Events:
+			if !ok {
+				return
+			}
+			if paths := t.changed(ev); len(paths) > 0 {
+				for _, p := range paths {
+					pending[p] = true
+				}
+				settled.Reset(debounce)
+			}
+		case err, ok := <-fsw.Errors:
+			if !ok {
+				return
+			}
+			slog.Warn("file watcher error", "root", w.Root, "error", err)
+		case <-settled.C:
+			if len(pending) > 0 {
+				slog.Debug("watch triggered", "root", w.Root, "patterns", w.Patterns, "changed", len(pending))
+				onChange(slices.Sorted(maps.Keys(pending)))
+				pending = map[string]bool{}
+			}
+		}
+	}
+}
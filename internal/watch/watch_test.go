@@ -0,0 +1,81 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		expected      bool
+	}{
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "internal/ui/ui.go", true},
+		{"**/*.go", "README.md", false},
+		{"internal/**", "internal/ui/ui.go", true},
+		{"cmd/*/main.go", "cmd/tash/main.go", true},
+		{"cmd/*/main.go", "cmd/tash/sub/main.go", false},
+		{"*.yml", "examples/Taskfile.yml", false},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.pattern, tt.name); got != tt.expected {
+			t.Errorf("Match(%q, %q): expected %v, got %v", tt.pattern, tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestWatcherRun(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "node_modules", "dep"), 0o755)
+	w := Watcher{Root: root, Patterns: []string{"**/*.go"}, Debounce: 20 * time.Millisecond}
+
+	changes := make(chan []string, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.Run(ctx, func(paths []string) { changes <- paths })
+
+	// writes until the watcher, which may still be registering the directories, reports them
+	waitFor := func(files ...string) []string {
+		t.Helper()
+		timeout := time.After(2 * time.Second)
+		for {
+			for _, f := range files {
+				p := filepath.Join(root, filepath.FromSlash(f))
+				os.MkdirAll(filepath.Dir(p), 0o755)
+				if err := os.WriteFile(p, []byte("package main"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			select {
+			case paths := <-changes:
+				return paths
+			case <-time.After(50 * time.Millisecond):
+			case <-timeout:
+				t.Fatal("Expected the watcher to report the change")
+			}
+		}
+	}
+
+	if paths := waitFor("notes.txt", "node_modules/dep/dep.go", "main.go"); !slices.Equal(paths, []string{"main.go"}) {
+		t.Errorf("Expected only main.go to be reported, got %v", paths)
+	}
+	if paths := waitFor("pkg/sub/sub.go"); !slices.Equal(paths, []string{"pkg/sub/sub.go"}) {
+		t.Errorf("Expected the file of a new directory to be reported, got %v", paths)
+	}
+}
+
+func TestSkippedDirs(t *testing.T) {
+	tr := tree{w: Watcher{Patterns: []string{"**/*.go", "vendor/**"}}, ignored: map[string]bool{"dist": true}}
+	for dir, expected := range map[string]bool{
+		".": false, "internal": false, ".git": true, "web/node_modules": true, "dist": true, "vendor": false,
+	} {
+		if got := tr.skipped(dir); got != expected {
+			t.Errorf("skipped(%q): expected %v, got %v", dir, expected, got)
+		}
+	}
+}
@@ -0,0 +1,347 @@
+// Package web serves the tash dashboard: a minimal web interface listing the tasks, running
+// them and streaming their output live over a WebSocket, so people without a terminal on a
+// shared machine can trigger tasks.
+package web
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Aj4x/tash/pkg/tash"
+)
+
+//go:embed dashboard.html
+var dashboard []byte
+
+// Events streamed to the dashboard, the same as those of "tash run --events jsonl"
+const (
+	EventRunStarted  = "run-started"
+	EventOutputLine  = "output-line"
+	EventRunFinished = "run-finished"
+)
+
+// maxBacklog bounds the events of the current or last run replayed to browsers connecting
+// while it runs or after it finished; the latest are kept
+const maxBacklog = 5000
+
+// clientBuffer is how many events a browser may fall behind before it is disconnected
+const clientBuffer = 1024
+
+// Event is a lifecycle event of a run, sent to the browsers as a JSON text message
+type Event struct {
+	Event string    `json:"event"`
+	Task  string    `json:"task"`
+	Time  time.Time `json:"time"`
+	// Stream ("stdout" or "stderr") and Text are set on output-line events
+	Stream string `json:"stream,omitempty"`
+	Text   string `json:"text,omitempty"`
+	// Success, ExitCode, DurationMs and Error are set on run-finished events
+	Success    *bool  `json:"success,omitempty"`
+	ExitCode   *int   `json:"exit_code,omitempty"`
+	DurationMs *int64 `json:"duration_ms,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Server serves the dashboard. Like the interface, it runs one task at a time.
+type Server struct {
+	// List returns the tasks offered to run
+	List func() ([]tash.Task, error)
+	// Run runs the task with the given id, calling output for each line it writes
+	Run func(ctx context.Context, id string, output func(tash.Line)) error
+	// ReadOnly lists the tasks and shows the output without allowing runs
+	ReadOnly bool
+	// Hosts are the names the dashboard is reached by, e.g. "localhost"; requests naming any
+	// other host are refused, so a site whose name is rebound to this machine's address can't
+	// reach it. Hosts given as IP addresses are always accepted.
+	Hosts []string
+
+	mu      sync.Mutex
+	running string             // Task being run, or empty
+	cancel  context.CancelFunc // Cancels the running task
+	backlog backlog            // Events of the current or last run
+	clients map[chan Event]struct{}
+	runs    sync.WaitGroup
+}
+
+// Handler returns the routes of the dashboard
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleDashboard)
+	mux.HandleFunc("GET /api/tasks", s.handleTasks)
+	mux.HandleFunc("POST /api/run", s.handleRun)
+	mux.HandleFunc("POST /api/cancel", s.handleCancel)
+	mux.HandleFunc("GET /api/events", s.handleEvents)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.knownHost(r.Host) {
+			http.Error(w, "unknown host refused", http.StatusForbidden)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// knownHost reports whether host, the Host header of a request, names the dashboard
+func (s *Server) knownHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if net.ParseIP(strings.Trim(host, "[]")) != nil {
+		return true
+	}
+	return slices.ContainsFunc(s.Hosts, func(h string) bool { return strings.EqualFold(h, host) })
+}
+
+func (s *Server) handleDashboard(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(dashboard)
+}
+
+// tasksResponse lists the tasks along with the state of the dashboard
+type tasksResponse struct {
+	Tasks    []tash.Task `json:"tasks"`
+	Running  string      `json:"running,omitempty"`
+	ReadOnly bool        `json:"read_only"`
+}
+
+func (s *Server) handleTasks(w http.ResponseWriter, r *http.Request) {
+	tasks, err := s.List()
+	if err != nil {
+		http.Error(w, "unable to list the tasks: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.mu.Lock()
+	resp := tasksResponse{Tasks: tasks, Running: s.running, ReadOnly: s.ReadOnly}
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
+	if !s.allowed(w, r) {
+		return
+	}
+	name := r.FormValue("task")
+	tasks, err := s.List()
+	if err != nil {
+		http.Error(w, "unable to list the tasks: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	t, ok := tash.Find(tasks, name)
+	if !ok {
+		http.Error(w, "unknown task "+name, http.StatusNotFound)
+		return
+	}
+	if err := s.start(t.ID); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	slog.Info("running task from the dashboard", "task", t.ID, "remote", r.RemoteAddr)
+	writeJSON(w, http.StatusAccepted, map[string]string{"running": t.ID})
+}
+
+func (s *Server) handleCancel(w http.ResponseWriter, r *http.Request) {
+	if !s.allowed(w, r) {
+		return
+	}
+	s.mu.Lock()
+	cancel := s.cancel
+	s.mu.Unlock()
+	if cancel == nil {
+		http.Error(w, "no task is running", http.StatusConflict)
+		return
+	}
+	cancel()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// allowed reports whether r may run or cancel tasks, answering it when it may not. Requests
+// from other sites are refused, so a page open in a teammate's browser can't run tasks.
+func (s *Server) allowed(w http.ResponseWriter, r *http.Request) bool {
+	if s.ReadOnly {
+		http.Error(w, "the dashboard is read-only", http.StatusForbidden)
+		return false
+	}
+	if !sameOrigin(r) {
+		http.Error(w, "cross-origin request refused", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// sameOrigin reports whether r comes from the dashboard itself, or from a client that isn't a
+// browser and sends no Origin
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+// start runs id in the background, unless a task is already running
+func (s *Server) start(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running != "" {
+		return errors.New("waiting for " + s.running + " to finish")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.running, s.cancel, s.backlog = id, cancel, backlog{}
+	s.publishLocked(Event{Event: EventRunStarted, Task: id})
+	s.runs.Add(1)
+	go s.run(ctx, id)
+	return nil
+}
+
+// Stop cancels the running task, if any, and waits for it to exit
+func (s *Server) Stop() {
+	s.mu.Lock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.mu.Unlock()
+	s.runs.Wait()
+}
+
+// run runs id, publishing its output and how it finished
+func (s *Server) run(ctx context.Context, id string) {
+	defer s.runs.Done()
+	start := time.Now()
+	err := s.Run(ctx, id, func(l tash.Line) {
+		stream := "stdout"
+		if l.Stderr {
+			stream = "stderr"
+		}
+		s.publish(Event{Event: EventOutputLine, Task: id, Stream: stream, Text: l.Text})
+	})
+	success, code, ms := err == nil, tash.ExitCode(err), time.Since(start).Milliseconds()
+	finished := Event{Event: EventRunFinished, Task: id, Success: &success, ExitCode: &code, DurationMs: &ms}
+	if err != nil {
+		finished.Error = err.Error()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cancel()
+	s.running, s.cancel = "", nil
+	s.publishLocked(finished)
+}
+
+func (s *Server) publish(e Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.publishLocked(e)
+}
+
+// publishLocked records e in the backlog and sends it to the browsers; browsers too far behind
+// are disconnected, and reload the backlog when they reconnect
+func (s *Server) publishLocked(e Event) {
+	e.Time = time.Now()
+	s.backlog.add(e)
+	for c := range s.clients {
+		select {
+		case c <- e:
+		default:
+			delete(s.clients, c)
+			close(c)
+		}
+	}
+}
+
+// subscribe returns the backlog and a channel receiving the events following it
+func (s *Server) subscribe() ([]Event, chan Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.clients == nil {
+		s.clients = map[chan Event]struct{}{}
+	}
+	c := make(chan Event, clientBuffer)
+	s.clients[c] = struct{}{}
+	return s.backlog.list(), c
+}
+
+// backlog keeps the latest maxBacklog events in a ring
+type backlog struct {
+	events []Event
+	next   int // Index of the oldest event once the ring is full
+}
+
+func (b *backlog) add(e Event) {
+	if len(b.events) < maxBacklog {
+		b.events = append(b.events, e)
+		return
+	}
+	b.events[b.next] = e
+	b.next = (b.next + 1) % maxBacklog
+}
+
+// list returns the events, oldest first
+func (b *backlog) list() []Event {
+	return append(append([]Event(nil), b.events[b.next:]...), b.events[:b.next]...)
+}
+
+func (s *Server) unsubscribe(c chan Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.clients[c]; ok {
+		delete(s.clients, c)
+		close(c)
+	}
+}
+
+// handleEvents streams the events of the runs to a browser over a WebSocket, starting with
+// those of the current or last run
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if !sameOrigin(r) {
+		http.Error(w, "cross-origin request refused", http.StatusForbidden)
+		return
+	}
+	conn, err := upgrade(w, r)
+	if err != nil {
+		slog.Debug("dashboard WebSocket refused", "error", err)
+		return
+	}
+	defer conn.Close()
+	backlog, events := s.subscribe()
+	defer s.unsubscribe(events)
+
+	closed := make(chan struct{})
+	go func() {
+		conn.readLoop()
+		close(closed)
+	}()
+	send := func(e Event) bool {
+		msg, err := json.Marshal(e)
+		return err == nil && conn.WriteText(msg) == nil
+	}
+	for _, e := range backlog {
+		if !send(e) {
+			return
+		}
+	}
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok || !send(e) {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
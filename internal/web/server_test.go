@@ -0,0 +1,181 @@
+package web
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Aj4x/tash/pkg/tash"
+)
+
+// dialEvents connects to the event stream of the dashboard at base
+func dialEvents(t *testing.T, base string) (net.Conn, *bufio.Reader) {
+	t.Helper()
+	u, _ := url.Parse(base)
+	conn, err := net.Dial("tcp", u.Host)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	io.WriteString(conn, "GET /api/events HTTP/1.1\r\nHost: "+u.Host+"\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n"+
+		"Sec-WebSocket-Version: 13\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n")
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the accept key of the sample handshake of RFC 6455
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("Expected the handshake to be accepted, got %s %v", resp.Status, resp.Header)
+	}
+	return conn, r
+}
+
+// readEvent reads an event sent as a text frame
+func readEvent(t *testing.T, r *bufio.Reader) Event {
+	t.Helper()
+	var head [2]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		t.Fatal(err)
+	}
+	n := int(head[1] & 0x7f)
+	if n == 126 {
+		var ext [2]byte
+		io.ReadFull(r, ext[:])
+		n = int(binary.BigEndian.Uint16(ext[:]))
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		t.Fatal(err)
+	}
+	var e Event
+	if head[0] != 0x80|opText || json.Unmarshal(payload, &e) != nil {
+		t.Fatalf("Expected an event, got frame %x %q", head[0], payload)
+	}
+	return e
+}
+
+func TestDashboardRunsTasks(t *testing.T) {
+	release := make(chan struct{})
+	s := &Server{
+		List: func() ([]tash.Task, error) {
+			return []tash.Task{{ID: "build", Aliases: []string{"b"}}}, nil
+		},
+		Run: func(ctx context.Context, id string, output func(tash.Line)) error {
+			output(tash.Line{Text: "compiling"})
+			<-release
+			output(tash.Line{Text: "done", Stderr: true})
+			return nil
+		},
+	}
+	server := httptest.NewServer(s.Handler())
+	defer server.Close()
+
+	resp, err := http.PostForm(server.URL+"/api/run", url.Values{"task": {"b"}})
+	if err != nil || resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("Expected the run to start, got %v %v", resp, err)
+	}
+	if resp, _ := http.PostForm(server.URL+"/api/run", url.Values{"task": {"build"}}); resp.StatusCode != http.StatusConflict {
+		t.Errorf("Expected a second run to wait for the first, got %s", resp.Status)
+	}
+	if resp, _ := http.PostForm(server.URL+"/api/run", url.Values{"task": {"deploy"}}); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected unknown tasks to be refused, got %s", resp.Status)
+	}
+
+	// a browser connecting during the run gets its output so far, then the rest live
+	_, r := dialEvents(t, server.URL)
+	if e := readEvent(t, r); e.Event != EventRunStarted || e.Task != "build" {
+		t.Errorf("Expected the run to start, got %+v", e)
+	}
+	if e := readEvent(t, r); e.Event != EventOutputLine || e.Text != "compiling" || e.Stream != "stdout" {
+		t.Errorf("Expected the output so far, got %+v", e)
+	}
+	close(release)
+	if e := readEvent(t, r); e.Text != "done" || e.Stream != "stderr" {
+		t.Errorf("Expected the live output, got %+v", e)
+	}
+	if e := readEvent(t, r); e.Event != EventRunFinished || e.Success == nil || !*e.Success || *e.ExitCode != 0 {
+		t.Errorf("Expected the run to succeed, got %+v", e)
+	}
+	s.Stop()
+}
+
+func TestDashboardRefusesUnsafeRuns(t *testing.T) {
+	s := &Server{
+		List: func() ([]tash.Task, error) { return []tash.Task{{ID: "build"}}, nil },
+		Run: func(ctx context.Context, id string, output func(tash.Line)) error {
+			t.Error("Expected no run")
+			return nil
+		},
+	}
+	server := httptest.NewServer(s.Handler())
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/api/run", strings.NewReader("task=build"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Origin", "https://evil.example.com")
+	if resp, err := http.DefaultClient.Do(req); err != nil || resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected cross-origin runs to be refused, got %v %v", resp, err)
+	}
+
+	s.ReadOnly = true
+	if resp, _ := http.PostForm(server.URL+"/api/run", url.Values{"task": {"build"}}); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected runs to be refused when read-only, got %s", resp.Status)
+	}
+	resp, err := http.Get(server.URL + "/api/tasks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var list tasksResponse
+	if json.NewDecoder(resp.Body).Decode(&list); len(list.Tasks) != 1 || !list.ReadOnly {
+		t.Errorf("Expected the tasks to be listed, got %+v", list)
+	}
+}
+
+func TestDashboardRefusesUnknownHosts(t *testing.T) {
+	s := &Server{
+		List:  func() ([]tash.Task, error) { return []tash.Task{{ID: "build"}}, nil },
+		Hosts: []string{"localhost"},
+	}
+	server := httptest.NewServer(s.Handler())
+	defer server.Close()
+
+	for host, want := range map[string]int{
+		"attacker.example.com:7070": http.StatusForbidden,
+		"localhost:7070":            http.StatusOK,
+		"LOCALHOST":                 http.StatusOK,
+		"127.0.0.1:7070":            http.StatusOK,
+		"[::1]:7070":                http.StatusOK,
+	} {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/tasks", nil)
+		req.Host = host
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("Host %s: expected %d, got %s", host, want, resp.Status)
+		}
+	}
+}
+
+func TestBacklogKeepsTheLatestEvents(t *testing.T) {
+	var s Server
+	for i := range maxBacklog + 10 {
+		s.publish(Event{Event: EventOutputLine, Text: strconv.Itoa(i)})
+	}
+	backlog, _ := s.subscribe()
+	if len(backlog) != maxBacklog || backlog[0].Text != "10" || backlog[len(backlog)-1].Text != strconv.Itoa(maxBacklog+9) {
+		t.Errorf("Expected the last %d events, got %d from %q to %q", maxBacklog, len(backlog), backlog[0].Text, backlog[len(backlog)-1].Text)
+	}
+}
@@ -0,0 +1,85 @@
+package tash
+
+import (
+	"github.com/Aj4x/tash/internal/config"
+	"github.com/Aj4x/tash/internal/history"
+	"github.com/Aj4x/tash/internal/msgbus"
+	"github.com/Aj4x/tash/internal/runlog"
+	"github.com/Aj4x/tash/internal/task"
+	"github.com/Aj4x/tash/internal/ui"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// PanelOptions configures a Panel
+type PanelOptions struct {
+	// LoadConfig applies the user's tash config file and the project's .tash.json, and keeps the
+	// run history and logs in tash's data directory, so the panel behaves like the standalone
+	// application. Otherwise the defaults are used and no history or logs are kept.
+	LoadConfig bool
+	// MergeOutput combines task stdout and stderr
+	MergeOutput bool
+}
+
+// Panel is a bubbletea component running the tash interface inside another program. The host
+// must pass every message it receives to Update, not only key presses, as the panel relies on
+// its own messages to stream task output. Sizing is done with SetSize rather than
+// tea.WindowSizeMsg, so the panel can occupy part of the host's screen. Quit keys are left to
+// the host.
+type Panel struct {
+	model ui.Model
+}
+
+// NewPanel creates a panel listing the tasks of the working directory
+func NewPanel(opts PanelOptions) Panel {
+	cfg := config.Default()
+	if opts.LoadConfig {
+		// a missing or broken config file leaves the defaults in place, as it does for tash itself
+		cfg, _ = config.LoadDefault()
+		cfg, _ = config.LoadProject(cfg, ".")
+	}
+	if opts.MergeOutput {
+		cfg.MergeOutput = true
+	}
+	m := ui.NewModel(msgbus.NewMessageBus[task.Message](), cfg)
+	m.Embedded = true
+	if opts.LoadConfig {
+		if dir, err := config.DataDir(); err == nil {
+			m.UseHistory(history.NewStore(dir))
+			m.UseRunLogs(runlog.NewStore(dir))
+		}
+	}
+	return Panel{model: m}
+}
+
+// Init starts loading the task list
+func (p Panel) Init() tea.Cmd {
+	return p.model.Init()
+}
+
+// Update handles a message from the host program
+func (p Panel) Update(msg tea.Msg) (Panel, tea.Cmd) {
+	if _, ok := msg.(tea.WindowSizeMsg); ok {
+		// the host's window size is not the panel's
+		return p, nil
+	}
+	model, cmd := p.model.Update(msg)
+	p.model = model.(ui.Model)
+	return p, cmd
+}
+
+// SetSize sets the number of columns and lines the panel renders into
+func (p Panel) SetSize(width, height int) (Panel, tea.Cmd) {
+	model, cmd := p.model.Update(tea.WindowSizeMsg{Width: width, Height: height})
+	p.model = model.(ui.Model)
+	return p, cmd
+}
+
+// View renders the panel
+func (p Panel) View() string {
+	return p.model.View()
+}
+
+// Running reports whether a task is currently executing in the panel
+func (p Panel) Running() bool {
+	return p.model.TaskRunning
+}
@@ -0,0 +1,211 @@
+// Package tash is the public API for embedding tash in other programs. It exposes the task
+// catalog, a synchronous task runner and a bubbletea panel hosting the full tash interface.
+//
+// The types in this package are stable; everything under internal/ may change between releases.
+package tash
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Aj4x/tash/internal/msgbus"
+	"github.com/Aj4x/tash/internal/redact"
+	"github.com/Aj4x/tash/internal/task"
+)
+
+// Task describes a task defined in the Taskfile of the working directory
+type Task struct {
+	ID          string   `json:"id"`
+	Namespace   string   `json:"namespace"`
+	Description string   `json:"desc,omitempty"`
+	Summary     string   `json:"summary,omitempty"`
+	Aliases     []string `json:"aliases,omitempty"`
+	UpToDate    bool     `json:"up_to_date"`
+	Taskfile    string   `json:"taskfile,omitempty"`
+}
+
+func newTask(t task.Task) Task {
+	pt := Task{
+		ID:          t.Id,
+		Namespace:   t.Namespace(),
+		Description: t.Desc,
+		Summary:     t.Summary,
+		Aliases:     t.Aliases,
+		UpToDate:    t.UpToDate,
+	}
+	if t.Location != nil {
+		pt.Taskfile = t.Location.Taskfile
+	}
+	return pt
+}
+
+// List returns every task in the Taskfile of the working directory
+func List() ([]Task, error) {
+	tasks, err := task.ListAll(task.Environment{})
+	if err != nil {
+		return nil, err
+	}
+	list := make([]Task, len(tasks))
+	for i, t := range tasks {
+		list[i] = newTask(t)
+	}
+	return list, nil
+}
+
+// Find returns the task whose id or one of whose aliases is name
+func Find(tasks []Task, name string) (Task, bool) {
+	for _, t := range tasks {
+		if t.ID == name {
+			return t, true
+		}
+	}
+	for _, t := range tasks {
+		for _, alias := range t.Aliases {
+			if alias == name {
+				return t, true
+			}
+		}
+	}
+	return Task{}, false
+}
+
+// RunOptions controls how Run executes a task
+type RunOptions struct {
+	// MergeOutput combines stdout and stderr so lines are reported in the order they were written
+	MergeOutput bool
+	// Timeout cancels the task once exceeded; zero means no timeout
+	Timeout time.Duration
+	// Retries is the number of times a failed attempt is retried
+	Retries int
+	// RetryBackoff is the delay before the first retry, doubling for each subsequent retry
+	RetryBackoff time.Duration
+	// Vars are passed to the task as NAME=value arguments
+	Vars []string
+	// Args are passed to the task after "--", available in the Taskfile as CLI_ARGS
+	Args []string
+	// Demo runs the built-in demo tasks instead of the tasks of the Taskfile
+	Demo bool
+	// Dir is the directory the task runs in, which also decides the Taskfile; empty is the
+	// current directory
+	Dir string
+	// Taskfile is the Taskfile defining the task instead of the one in Dir: a path, or the URL
+	// of a remote Taskfile
+	Taskfile string
+	// PathPrefix lists directories put in front of PATH for the task
+	PathPrefix []string
+	// EnvAllow restricts the inherited environment variables to those matching these glob
+	// patterns; empty inherits every variable
+	EnvAllow []string
+	// EnvDeny drops inherited environment variables matching these glob patterns
+	EnvDeny []string
+	// LowPriority runs the task at reduced CPU and IO priority
+	LowPriority bool
+	// GracePeriod is how long a cancelled task has to exit before all of its processes are
+	// killed; zero means five seconds
+	GracePeriod time.Duration
+	// RedactPatterns are regular expressions whose matches in the output are masked with "*****";
+	// in patterns with capture groups only the groups are masked. The values of environment
+	// variables named like *TOKEN*, *SECRET* or *PASSWORD* are always masked.
+	RedactPatterns []string
+}
+
+// Line is a line of output written by a running task, or by tash about the run (e.g. retries)
+type Line struct {
+	Text   string
+	Stderr bool
+}
+
+// Run executes the task with the given id, calling output for each line it writes, and returns
+// once it has finished. Output is called by one goroutine at a time, in the order the lines are
+// read, stdout and stderr being read concurrently unless merged. Cancelling ctx stops the task's whole process group. The returned error
+// describes why the task failed, or is nil on success.
+func Run(ctx context.Context, id string, opts RunOptions, output func(Line)) error {
+	redactor, err := redact.New(opts.RedactPatterns)
+	if err != nil {
+		return err
+	}
+	p := &runPublisher{output: output}
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			p.cancel()
+		case <-done:
+		}
+	}()
+	execOpts := task.ExecOptions{
+		MergeOutput:  opts.MergeOutput,
+		Timeout:      opts.Timeout,
+		Retries:      opts.Retries,
+		RetryBackoff: opts.RetryBackoff,
+		Vars:         opts.Vars,
+		Args:         opts.Args,
+		LowPriority:  opts.LowPriority,
+		GracePeriod:  opts.GracePeriod,
+		Redactor:     redactor,
+		Environment: task.Environment{
+			Dir:        opts.Dir,
+			Taskfile:   opts.Taskfile,
+			PathPrefix: opts.PathPrefix,
+			Allow:      opts.EnvAllow,
+			Deny:       opts.EnvDeny,
+		},
+	}
+	if opts.Demo {
+		execOpts.Provider = task.ProviderDemo
+	}
+	task.ExecuteTaskWithOptions(id, execOpts, p)
+	close(done)
+	return p.err
+}
+
+// ExitCode returns the exit code matching the error returned by Run, so programs wrapping tash
+// exit the way task would: 0 on success, the task's exit code when it exited with one, 124 when
+// it timed out and 1 otherwise
+func ExitCode(err error) int {
+	return task.ExitCode(err)
+}
+
+// runPublisher receives the messages of a single run in the order they are published
+type runPublisher struct {
+	mu         sync.Mutex
+	outputMu   sync.Mutex // Serialises the calls of output, made for stdout and stderr at once
+	output     func(Line)
+	cancelFunc context.CancelFunc
+	cancelled  bool
+	err        error
+}
+
+func (p *runPublisher) Publish(msg msgbus.TopicMessage[task.Message]) {
+	m := msg.Message
+	switch m.Type {
+	case task.TypeTaskOutput, task.TypeTaskOutputErr:
+		if p.output != nil {
+			p.outputMu.Lock()
+			defer p.outputMu.Unlock()
+			p.output(Line{Text: m.Output(), Stderr: m.Type == task.TypeTaskOutputErr})
+		}
+	case task.TypeTaskCommand:
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if cancel := m.CancelFunc(); cancel != nil && m.TaskRunning() {
+			p.cancelFunc = cancel
+			if p.cancelled {
+				cancel()
+			}
+		}
+	case task.TypeTaskError:
+		p.err = m.Error()
+	}
+}
+
+// cancel stops the run, or marks it to be stopped as soon as it has started
+func (p *runPublisher) cancel() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cancelled = true
+	if p.cancelFunc != nil {
+		p.cancelFunc()
+	}
+}
@@ -0,0 +1,100 @@
+package tash
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/Aj4x/tash/internal/task"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fakeTaskBinary installs a shell script named "task" at the front of PATH
+func fakeTaskBinary(t *testing.T, script string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "task"), []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestNewTask(t *testing.T) {
+	got := newTask(task.Task{
+		Id:       "docker:up",
+		Desc:     "Start",
+		Aliases:  []string{"up"},
+		Location: &task.Location{Taskfile: "Taskfile.yml"},
+	})
+	if got.ID != "docker:up" || got.Namespace != "docker" || got.Description != "Start" || got.Taskfile != "Taskfile.yml" {
+		t.Errorf("Unexpected task %+v", got)
+	}
+	if found, ok := Find([]Task{got}, "up"); !ok || found.ID != "docker:up" {
+		t.Errorf("Expected to find docker:up by its alias, got %+v", found)
+	}
+}
+
+func TestRunReportsOutputAndFailure(t *testing.T) {
+	fakeTaskBinary(t, "echo hello\necho oops >&2\nexit 3\n")
+
+	var lines []Line
+	err := Run(context.Background(), "build", RunOptions{MergeOutput: true}, func(l Line) {
+		lines = append(lines, l)
+	})
+	if err == nil {
+		t.Fatal("Expected the run to fail")
+	}
+	if code := ExitCode(err); code != 3 {
+		t.Errorf("Expected exit code 3, got %d", code)
+	}
+	if len(lines) != 2 || lines[0].Text != "hello" || lines[1].Text != "oops" {
+		t.Errorf("Expected lines 'hello' and 'oops', got %+v", lines)
+	}
+}
+
+func TestRunSerialisesOutput(t *testing.T) {
+	fakeTaskBinary(t, "for i in $(seq 200); do echo out; echo err >&2; done\n")
+
+	// appending without a lock is only safe when output isn't called concurrently
+	var lines []Line
+	if err := Run(context.Background(), "build", RunOptions{}, func(l Line) {
+		lines = append(lines, l)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 400 {
+		t.Errorf("Expected 400 lines, got %d", len(lines))
+	}
+}
+
+func TestRunCancel(t *testing.T) {
+	fakeTaskBinary(t, "sleep 5\n")
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := Run(ctx, "slow", RunOptions{}, nil); err == nil {
+		t.Fatal("Expected the cancelled run to fail")
+	}
+	if time.Since(start) > 3*time.Second {
+		t.Error("Expected the run to stop when the context was cancelled")
+	}
+}
+
+func TestPanelIgnoresQuitAndHostSize(t *testing.T) {
+	p := NewPanel(PanelOptions{})
+	p, _ = p.SetSize(60, 20)
+	p, _ = p.Update(tea.WindowSizeMsg{Width: 200, Height: 60})
+	if got := p.model.Width; got != 60 {
+		t.Errorf("Expected the panel to keep its width of 60, got %d", got)
+	}
+	if _, cmd := p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}); cmd != nil {
+		t.Error("Expected the panel not to quit the host program")
+	}
+}